@@ -0,0 +1,393 @@
+// Package assertion evaluates declarative response checks and extracts
+// response values into variables, so common tests need no JavaScript.
+// Outcomes are reported as script.TestResult values alongside script tests.
+package assertion
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/artpar/currier/internal/core"
+	"github.com/artpar/currier/internal/interpolate"
+	"github.com/artpar/currier/internal/jsonpath"
+	"github.com/artpar/currier/internal/jsonschema"
+	"github.com/artpar/currier/internal/script"
+	"github.com/artpar/currier/internal/xpath"
+)
+
+// response holds the parts of a response assertions read, decoding the body
+// at most once.
+type response struct {
+	status   int
+	headers  *core.Headers
+	body     []byte
+	duration time.Duration
+
+	json    any
+	jsonErr error
+	jsonOK  bool
+	xml     *xpath.Node
+	xmlErr  error
+	xmlOK   bool
+}
+
+func newResponse(resp *core.Response) *response {
+	return &response{
+		status:   resp.Status().Code(),
+		headers:  resp.Headers(),
+		body:     resp.Body().Bytes(),
+		duration: resp.Timing().Total,
+	}
+}
+
+func (r *response) decodeJSON() (any, error) {
+	if !r.jsonOK {
+		r.json, r.jsonErr = jsonschema.DecodeJSON(bytes.TrimSpace(r.body))
+		if r.jsonErr != nil {
+			r.jsonErr = fmt.Errorf("response body is not JSON")
+		}
+		r.jsonOK = true
+	}
+	return r.json, r.jsonErr
+}
+
+func (r *response) decodeXML() (*xpath.Node, error) {
+	if !r.xmlOK {
+		r.xml, r.xmlErr = xpath.Parse(r.body)
+		if r.xmlErr != nil {
+			r.xmlErr = fmt.Errorf("response body is not XML")
+		}
+		r.xmlOK = true
+	}
+	return r.xml, r.xmlErr
+}
+
+// Evaluate checks a response against assertions and returns one test result
+// per assertion, named after its line in the Parse syntax. Properties and
+// values are interpolated with engine when it is not nil.
+func Evaluate(assertions []core.Assertion, resp *core.Response, engine *interpolate.Engine) []script.TestResult {
+	if len(assertions) == 0 || resp == nil {
+		return nil
+	}
+
+	r := newResponse(resp)
+	results := make([]script.TestResult, 0, len(assertions))
+	for _, a := range assertions {
+		tr := script.TestResult{Name: FormatAssertion(a)}
+		if err := check(interpolateAssertion(a, engine), r); err != nil {
+			tr.Error = err.Error()
+		} else {
+			tr.Passed = true
+		}
+		results = append(results, tr)
+	}
+	return results
+}
+
+func interpolateAssertion(a core.Assertion, engine *interpolate.Engine) core.Assertion {
+	if engine == nil {
+		return a
+	}
+	if v, err := engine.Interpolate(a.Property); err == nil {
+		a.Property = v
+	}
+	if v, err := engine.Interpolate(a.Value); err == nil {
+		a.Value = v
+	}
+	return a
+}
+
+func check(a core.Assertion, r *response) error {
+	if err := Validate(a); err != nil {
+		return err
+	}
+
+	switch a.Source {
+	case core.AssertionSourceStatus:
+		return checkStatus(a, r.status)
+	case core.AssertionSourceHeader:
+		return checkHeader(a, r.headers)
+	case core.AssertionSourceJSONPath:
+		return checkJSONPath(a, r)
+	case core.AssertionSourceXPath:
+		return checkXPath(a, r)
+	case core.AssertionSourceResponseTime:
+		limit, _ := parseMillis(a.Value)
+		if ms := r.duration.Milliseconds(); ms >= limit {
+			return fmt.Errorf("response took %dms, expected below %dms", ms, limit)
+		}
+		return nil
+	case core.AssertionSourceBody:
+		return checkText(a, "body", string(r.body))
+	case core.AssertionSourceSchema:
+		return checkSchema(a, r)
+	}
+	return fmt.Errorf("unknown assertion source %q", a.Source)
+}
+
+func checkStatus(a core.Assertion, status int) error {
+	codes := splitList(a.Value)
+	for _, code := range codes {
+		if statusMatches(code, status) {
+			return nil
+		}
+	}
+	if a.Operator == core.AssertionIn {
+		return fmt.Errorf("expected status in %s, got %d", strings.Join(codes, ", "), status)
+	}
+	return fmt.Errorf("expected status %s, got %d", strings.Join(codes, ", "), status)
+}
+
+// statusMatches compares a status with a code such as 200 or a class such
+// as 2xx.
+func statusMatches(code string, status int) bool {
+	if strings.HasSuffix(strings.ToLower(code), "xx") {
+		return strconv.Itoa(status/100) == code[:1]
+	}
+	n, err := strconv.Atoi(code)
+	return err == nil && n == status
+}
+
+func checkHeader(a core.Assertion, headers *core.Headers) error {
+	values := headers.GetAll(a.Property)
+	if len(values) == 0 {
+		return fmt.Errorf("header %s is missing", a.Property)
+	}
+	if a.Operator == core.AssertionExists {
+		return nil
+	}
+	return checkText(a, "header "+a.Property, strings.Join(values, ", "))
+}
+
+// checkText applies equals, contains and matches to a string.
+func checkText(a core.Assertion, what, actual string) error {
+	expected := unquote(a.Value)
+	switch a.Operator {
+	case core.AssertionEquals:
+		if strings.TrimSpace(actual) != expected {
+			return fmt.Errorf("expected %s to equal %q, got %q", what, expected, truncate(actual))
+		}
+	case core.AssertionContains:
+		if !strings.Contains(actual, expected) {
+			return fmt.Errorf("expected %s to contain %q", what, expected)
+		}
+	case core.AssertionMatches:
+		re, err := regexp.Compile(expected)
+		if err != nil {
+			return fmt.Errorf("invalid regular expression: %w", err)
+		}
+		if !re.MatchString(actual) {
+			return fmt.Errorf("expected %s to match %q, got %q", what, expected, truncate(actual))
+		}
+	}
+	return nil
+}
+
+func checkJSONPath(a core.Assertion, r *response) error {
+	doc, err := r.decodeJSON()
+	if err != nil {
+		return err
+	}
+	path, err := jsonpath.Parse(a.Property)
+	if err != nil {
+		return fmt.Errorf("invalid JSONPath %q: %w", a.Property, err)
+	}
+
+	matches := path.Select(doc)
+	if len(matches) == 0 {
+		return fmt.Errorf("%s matched nothing", a.Property)
+	}
+	// A path matching several values is compared as an array of them
+	actual := matches[0]
+	if len(matches) > 1 {
+		actual = matches
+	}
+
+	switch a.Operator {
+	case core.AssertionEquals:
+		expected := parseJSONValue(a.Value)
+		if !jsonschema.Equal(actual, expected) {
+			return fmt.Errorf("expected %s to equal %s, got %s", a.Property, jsonschema.FormatValue(expected), truncate(jsonschema.FormatValue(actual)))
+		}
+	case core.AssertionType:
+		want := unquote(a.Value)
+		if !jsonschema.IsType(actual, want) {
+			return fmt.Errorf("expected %s to be %s, got %s", a.Property, want, jsonschema.TypeName(actual))
+		}
+	case core.AssertionMatches:
+		s, ok := actual.(string)
+		if !ok {
+			s = jsonschema.FormatValue(actual)
+		}
+		return checkText(a, a.Property, s)
+	}
+	return nil
+}
+
+// parseJSONValue reads an expected value as JSON, falling back to a plain
+// string so that `equals Ada` and `equals "Ada"` mean the same.
+func parseJSONValue(s string) any {
+	s = strings.TrimSpace(s)
+	if v, err := jsonschema.DecodeJSON([]byte(s)); err == nil {
+		return v
+	}
+	return unquote(s)
+}
+
+func checkXPath(a core.Assertion, r *response) error {
+	doc, err := r.decodeXML()
+	if err != nil {
+		return err
+	}
+	path, err := xpath.Compile(a.Property)
+	if err != nil {
+		return fmt.Errorf("invalid XPath %q: %w", a.Property, err)
+	}
+
+	nodes := path.Select(doc)
+	if len(nodes) == 0 {
+		return fmt.Errorf("%s matched nothing", a.Property)
+	}
+
+	switch a.Operator {
+	case core.AssertionType:
+		want := unquote(a.Value)
+		if got := nodes[0].Kind.String(); got != want {
+			return fmt.Errorf("expected %s to be %s, got %s", a.Property, want, got)
+		}
+	case core.AssertionEquals, core.AssertionMatches:
+		return checkText(a, a.Property, strings.TrimSpace(nodes[0].Text()))
+	}
+	return nil
+}
+
+func checkSchema(a core.Assertion, r *response) error {
+	doc, err := r.decodeJSON()
+	if err != nil {
+		return err
+	}
+	schema, err := loadSchema(a.Value)
+	if err != nil {
+		return err
+	}
+
+	errs := schema.Validate(doc)
+	if len(errs) == 0 {
+		return nil
+	}
+	lines := make([]string, len(errs))
+	for i, e := range errs {
+		lines[i] = e.Error()
+	}
+	return fmt.Errorf("%s", strings.Join(lines, "\n"))
+}
+
+// loadSchema reads an inline JSON schema, or a schema file when the value
+// does not look like JSON.
+func loadSchema(value string) (*jsonschema.Schema, error) {
+	value = unquote(value)
+	data := []byte(value)
+	if trimmed := strings.TrimSpace(value); trimmed != "true" && trimmed != "false" && !strings.HasPrefix(trimmed, "{") {
+		var err error
+		data, err = os.ReadFile(trimmed)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read schema: %w", err)
+		}
+	}
+	schema, err := jsonschema.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+	return schema, nil
+}
+
+// parseMillis reads a duration such as 500, 500ms or 2s as milliseconds.
+func parseMillis(s string) (int64, error) {
+	s = strings.TrimSpace(unquote(s))
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil && n > 0 {
+		return n, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid duration %q (use milliseconds, e.g. 500 or 500ms)", s)
+	}
+	return d.Milliseconds(), nil
+}
+
+// Extract captures values from a response. It returns the extracted
+// variables and one test result per extraction, so failures show up next
+// to assertions.
+func Extract(extractions []core.Extraction, resp *core.Response) (map[string]string, []script.TestResult) {
+	if len(extractions) == 0 || resp == nil {
+		return nil, nil
+	}
+
+	r := newResponse(resp)
+	vars := make(map[string]string, len(extractions))
+	results := make([]script.TestResult, 0, len(extractions))
+	for _, e := range extractions {
+		tr := script.TestResult{Name: FormatExtraction(e)}
+		value, err := extract(e, r)
+		if err != nil {
+			tr.Error = err.Error()
+		} else {
+			tr.Passed = true
+			vars[e.Variable] = value
+		}
+		results = append(results, tr)
+	}
+	return vars, results
+}
+
+func extract(e core.Extraction, r *response) (string, error) {
+	if err := ValidateExtraction(e); err != nil {
+		return "", err
+	}
+
+	switch e.Source {
+	case core.AssertionSourceJSONPath:
+		doc, err := r.decodeJSON()
+		if err != nil {
+			return "", err
+		}
+		matches := jsonpath.MustParse(e.Expression).Select(doc)
+		if len(matches) == 0 {
+			return "", fmt.Errorf("%s matched nothing", e.Expression)
+		}
+		if s, ok := matches[0].(string); ok {
+			return s, nil
+		}
+		return jsonschema.FormatValue(matches[0]), nil
+
+	case core.AssertionSourceHeader:
+		if r.headers.Get(e.Expression) == "" {
+			return "", fmt.Errorf("header %s is missing", e.Expression)
+		}
+		return r.headers.Get(e.Expression), nil
+
+	case core.AssertionSourceRegex:
+		re := regexp.MustCompile(unquote(e.Expression))
+		m := re.FindSubmatch(r.body)
+		if m == nil {
+			return "", fmt.Errorf("%s matched nothing", e.Expression)
+		}
+		if len(m) > 1 {
+			return string(m[1]), nil
+		}
+		return string(m[0]), nil
+	}
+	return "", fmt.Errorf("cannot extract from %q", e.Source)
+}
+
+func truncate(s string) string {
+	const max = 200
+	if len(s) <= max {
+		return s
+	}
+	return s[:max] + "…"
+}
@@ -0,0 +1,194 @@
+package assertion
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/artpar/currier/internal/core"
+	"github.com/artpar/currier/internal/interfaces"
+	"github.com/artpar/currier/internal/interpolate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newResponseFixture(status int, contentType, body string) *core.Response {
+	headers := core.NewHeaders()
+	headers.Set("Content-Type", contentType)
+	headers.Set("X-Request-Id", "abc-123")
+	return core.NewResponse("req", "HTTP/1.1", core.NewStatus(status, "")).
+		WithHeaders(headers).
+		WithBody(core.NewRawBody([]byte(body), contentType)).
+		WithTiming(interfaces.TimingInfo{Total: 120 * time.Millisecond})
+}
+
+func TestEvaluate(t *testing.T) {
+	jsonResp := newResponseFixture(201, "application/json; charset=utf-8",
+		`{"id": 7, "name": "Ada", "tags": ["a", "b"], "meta": {"active": true, "score": 1.5}}`)
+	xmlResp := newResponseFixture(200, "application/xml",
+		`<user id="7"><name>Ada</name><role>admin</role></user>`)
+
+	tests := []struct {
+		line  string
+		resp  *core.Response
+		error string // Empty when the assertion passes
+	}{
+		{"status equals 201", jsonResp, ""},
+		{"status equals 200", jsonResp, "expected status 200, got 201"},
+		{"status in 200, 201", jsonResp, ""},
+		{"status in 2xx", jsonResp, ""},
+		{"status in 4xx,5xx", jsonResp, "expected status in 4xx, 5xx, got 201"},
+		{"header content-type matches ^application/json", jsonResp, ""},
+		{"header X-Request-Id equals abc-123", jsonResp, ""},
+		{"header X-Request-Id contains 123", jsonResp, ""},
+		{"header X-Missing exists", jsonResp, "header X-Missing is missing"},
+		{"jsonpath $.id equals 7", jsonResp, ""},
+		{"jsonpath $.id equals 7.0", jsonResp, ""},
+		{`jsonpath $.id equals "7"`, jsonResp, `expected $.id to equal "7", got 7`},
+		{"jsonpath $.name equals Ada", jsonResp, ""},
+		{`jsonpath $.name equals "Bob"`, jsonResp, `expected $.name to equal "Bob", got "Ada"`},
+		{`jsonpath $.tags equals ["a","b"]`, jsonResp, ""},
+		{`jsonpath $.tags[*] equals ["a","b"]`, jsonResp, ""},
+		{"jsonpath $.meta.active exists", jsonResp, ""},
+		{"jsonpath $.meta.missing exists", jsonResp, "$.meta.missing matched nothing"},
+		{"jsonpath $.meta type object", jsonResp, ""},
+		{"jsonpath $.meta.score type integer", jsonResp, "expected $.meta.score to be integer, got number"},
+		{"jsonpath $.name matches ^A", jsonResp, ""},
+		{"jsonpath $.id exists", xmlResp, "response body is not JSON"},
+		{"xpath /user/name equals Ada", xmlResp, ""},
+		{"xpath //@id equals 8", xmlResp, `expected //@id to equal "8", got "7"`},
+		{"xpath //role exists", xmlResp, ""},
+		{"xpath //@id type attribute", xmlResp, ""},
+		{"xpath /user/role matches ^adm", xmlResp, ""},
+		{"xpath //name exists", jsonResp, "response body is not XML"},
+		{"response_time below 500", jsonResp, ""},
+		{"response_time below 100ms", jsonResp, "response took 120ms, expected below 100ms"},
+		{"body contains \"Ada\"", jsonResp, ""},
+		{`body matches "id":\s*7`, jsonResp, ""},
+		{"body contains Bob", jsonResp, `expected body to contain "Bob"`},
+		{`schema valid {"type": "object", "required": ["id"]}`, jsonResp, ""},
+		{`schema valid {"properties": {"id": {"type": "string"}}}`, jsonResp, "/id: expected string, got integer"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			assertions, _, err := Parse(tt.line)
+			require.NoError(t, err)
+
+			results := Evaluate(assertions, tt.resp, nil)
+			require.Len(t, results, 1)
+			assert.Equal(t, tt.line, results[0].Name)
+			assert.Equal(t, tt.error == "", results[0].Passed)
+			assert.Equal(t, tt.error, results[0].Error)
+		})
+	}
+}
+
+func TestEvaluate_Interpolation(t *testing.T) {
+	engine := interpolate.NewEngine()
+	engine.SetVariable("expectedName", "Ada")
+	engine.SetVariable("field", "name")
+
+	assertions, _, err := Parse("jsonpath $.{{field}} equals {{expectedName}}")
+	require.NoError(t, err)
+
+	results := Evaluate(assertions, newResponseFixture(200, "application/json", `{"name":"Ada"}`), engine)
+	require.Len(t, results, 1)
+	assert.True(t, results[0].Passed, results[0].Error)
+	assert.Equal(t, "jsonpath $.{{field}} equals {{expectedName}}", results[0].Name)
+}
+
+func TestEvaluate_SchemaFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "user.schema.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"required": ["email"]}`), 0644))
+
+	results := Evaluate([]core.Assertion{{Source: core.AssertionSourceSchema, Operator: core.AssertionValid, Value: path}},
+		newResponseFixture(200, "application/json", `{"name":"Ada"}`), nil)
+	require.Len(t, results, 1)
+	assert.Equal(t, `(root): missing required property "email"`, results[0].Error)
+
+	results = Evaluate([]core.Assertion{{Source: core.AssertionSourceSchema, Operator: core.AssertionValid, Value: "missing.json"}},
+		newResponseFixture(200, "application/json", `{}`), nil)
+	assert.Contains(t, results[0].Error, "failed to read schema")
+}
+
+func TestExtract(t *testing.T) {
+	resp := newResponseFixture(200, "application/json", `{"auth": {"token": "t-1", "expires": 3600}, "next": "/page?cursor=c42"}`)
+
+	_, extractions, err := Parse(`
+extract token jsonpath $.auth.token
+extract ttl jsonpath $.auth.expires
+extract requestId header X-Request-Id
+extract cursor regex "cursor=(\w+)"
+extract missing jsonpath $.nope
+`)
+	require.NoError(t, err)
+
+	vars, results := Extract(extractions, resp)
+	assert.Equal(t, map[string]string{"token": "t-1", "ttl": "3600", "requestId": "abc-123", "cursor": "c42"}, vars)
+	require.Len(t, results, 5)
+	assert.Equal(t, "extract token jsonpath $.auth.token", results[0].Name)
+	assert.True(t, results[3].Passed)
+	assert.False(t, results[4].Passed)
+	assert.Equal(t, "$.nope matched nothing", results[4].Error)
+}
+
+func TestParse(t *testing.T) {
+	text := `# Smoke checks
+status in 200, 201
+
+header "Content-Type" matches ^application/json
+jsonpath "$['odd key']" equals "x y"
+extract id regex "id=(\d+)"
+extract name jsonpath "$['full name']"`
+
+	assertions, extractions, err := Parse(text)
+	require.NoError(t, err)
+
+	assert.Equal(t, []core.Assertion{
+		{Source: core.AssertionSourceStatus, Operator: core.AssertionIn, Value: "200, 201"},
+		{Source: core.AssertionSourceHeader, Property: "Content-Type", Operator: core.AssertionMatches, Value: "^application/json"},
+		{Source: core.AssertionSourceJSONPath, Property: "$['odd key']", Operator: core.AssertionEquals, Value: `"x y"`},
+	}, assertions)
+	assert.Equal(t, []core.Extraction{
+		{Variable: "id", Source: core.AssertionSourceRegex, Expression: `"id=(\d+)"`},
+		{Variable: "name", Source: core.AssertionSourceJSONPath, Expression: "$['full name']"},
+	}, extractions)
+
+	// Format writes the same syntax back
+	again, againExtractions, err := Parse(Format(assertions, extractions))
+	require.NoError(t, err)
+	assert.Equal(t, assertions, again)
+	assert.Equal(t, extractions, againExtractions)
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		text  string
+		error string
+	}{
+		{"cookie session exists", `line 1: unknown assertion source "cookie"`},
+		{"status", "line 1: status assertion needs an operator (equals, in)"},
+		{"\nstatus below 200", `line 2: status does not support "below" (use equals, in)`},
+		{"status equals 999", `line 1: invalid status code "999"`},
+		{"header", "line 1: header assertion needs a header name"},
+		{"jsonpath $.id equals", "line 1: jsonpath equals needs a value"},
+		{"jsonpath id exists", `line 1: invalid JSONPath "id": path must start with $`},
+		{"jsonpath $.id type text", `line 1: unknown type "text" (use string, number, integer, boolean, null, array, object)`},
+		{"xpath //a[ exists", `line 1: invalid XPath "//a[": unclosed [ in step "a["`},
+		{"body matches (", "line 1: invalid regular expression: error parsing regexp: missing closing ): `(`"},
+		{"response_time below soon", `line 1: invalid duration "soon" (use milliseconds, e.g. 500 or 500ms)`},
+		{"extract 1x jsonpath $.a", `line 1: invalid variable name "1x"`},
+		{"extract x xpath //a", `line 1: cannot extract from "xpath" (use jsonpath, header or regex)`},
+		{"extract x header", "line 1: extract x needs an expression"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			_, _, err := Parse(tt.text)
+			require.Error(t, err)
+			assert.Equal(t, tt.error, err.Error())
+		})
+	}
+}
@@ -0,0 +1,358 @@
+package assertion
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/artpar/currier/internal/core"
+	"github.com/artpar/currier/internal/jsonpath"
+	"github.com/artpar/currier/internal/xpath"
+)
+
+// operators lists the operators each assertion source supports.
+var operators = map[core.AssertionSource][]core.AssertionOperator{
+	core.AssertionSourceStatus:       {core.AssertionEquals, core.AssertionIn},
+	core.AssertionSourceHeader:       {core.AssertionEquals, core.AssertionMatches, core.AssertionContains, core.AssertionExists},
+	core.AssertionSourceJSONPath:     {core.AssertionEquals, core.AssertionMatches, core.AssertionExists, core.AssertionType},
+	core.AssertionSourceXPath:        {core.AssertionEquals, core.AssertionMatches, core.AssertionExists, core.AssertionType},
+	core.AssertionSourceResponseTime: {core.AssertionBelow},
+	core.AssertionSourceBody:         {core.AssertionMatches, core.AssertionContains},
+	core.AssertionSourceSchema:       {core.AssertionValid},
+}
+
+// extractionSources lists the sources values can be extracted from.
+var extractionSources = []core.AssertionSource{
+	core.AssertionSourceJSONPath,
+	core.AssertionSourceHeader,
+	core.AssertionSourceRegex,
+}
+
+var variableName = regexp.MustCompile(`^[a-zA-Z_$][a-zA-Z0-9_\-$]*$`)
+
+// hasProperty reports whether assertions on source name a property.
+func hasProperty(source core.AssertionSource) bool {
+	switch source {
+	case core.AssertionSourceHeader, core.AssertionSourceJSONPath, core.AssertionSourceXPath:
+		return true
+	}
+	return false
+}
+
+// Validate checks that an assertion is well formed. Regular expressions and
+// paths are compiled unless they contain {{variables}}.
+func Validate(a core.Assertion) error {
+	ops, ok := operators[a.Source]
+	if !ok {
+		return fmt.Errorf("unknown assertion source %q", a.Source)
+	}
+	if hasProperty(a.Source) && strings.TrimSpace(a.Property) == "" {
+		return fmt.Errorf("%s assertion needs a %s", a.Source, propertyName(a.Source))
+	}
+	if !containsOperator(ops, a.Operator) {
+		return fmt.Errorf("%s does not support %q (use %s)", a.Source, a.Operator, joinOperators(ops))
+	}
+	if a.Operator != core.AssertionExists && strings.TrimSpace(a.Value) == "" {
+		return fmt.Errorf("%s %s needs a value", a.Source, a.Operator)
+	}
+	if hasVariables(a.Property) || hasVariables(a.Value) {
+		return nil
+	}
+
+	switch a.Source {
+	case core.AssertionSourceJSONPath:
+		if _, err := jsonpath.Parse(a.Property); err != nil {
+			return fmt.Errorf("invalid JSONPath %q: %w", a.Property, err)
+		}
+	case core.AssertionSourceXPath:
+		if _, err := xpath.Compile(a.Property); err != nil {
+			return fmt.Errorf("invalid XPath %q: %w", a.Property, err)
+		}
+	}
+
+	switch a.Operator {
+	case core.AssertionMatches:
+		if _, err := regexp.Compile(unquote(a.Value)); err != nil {
+			return fmt.Errorf("invalid regular expression: %w", err)
+		}
+	case core.AssertionIn, core.AssertionEquals:
+		if a.Source == core.AssertionSourceStatus {
+			for _, code := range splitList(a.Value) {
+				if !validStatus(code) {
+					return fmt.Errorf("invalid status code %q", code)
+				}
+			}
+		}
+	case core.AssertionBelow:
+		if _, err := parseMillis(a.Value); err != nil {
+			return err
+		}
+	case core.AssertionType:
+		if err := validType(a.Source, unquote(a.Value)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateExtraction checks that an extraction is well formed.
+func ValidateExtraction(e core.Extraction) error {
+	if !variableName.MatchString(e.Variable) {
+		return fmt.Errorf("invalid variable name %q", e.Variable)
+	}
+	if !containsSource(extractionSources, e.Source) {
+		return fmt.Errorf("cannot extract from %q (use jsonpath, header or regex)", e.Source)
+	}
+	if strings.TrimSpace(e.Expression) == "" {
+		return fmt.Errorf("extract %s needs an expression", e.Variable)
+	}
+	switch e.Source {
+	case core.AssertionSourceJSONPath:
+		if _, err := jsonpath.Parse(e.Expression); err != nil {
+			return fmt.Errorf("invalid JSONPath %q: %w", e.Expression, err)
+		}
+	case core.AssertionSourceRegex:
+		if _, err := regexp.Compile(unquote(e.Expression)); err != nil {
+			return fmt.Errorf("invalid regular expression: %w", err)
+		}
+	}
+	return nil
+}
+
+// Parse reads assertions and extractions written one per line:
+//
+//	status equals 200
+//	status in 200, 201, 3xx
+//	header Content-Type matches ^application/json
+//	jsonpath $.user.name equals "Ada"
+//	jsonpath $.items type array
+//	xpath //user/@id exists
+//	response_time below 500ms
+//	body contains "ok"
+//	schema valid {"type": "object", "required": ["id"]}
+//	extract token jsonpath $.auth.token
+//	extract session header X-Session
+//	extract id regex "id=(\d+)"
+//
+// Blank lines and lines starting with # are ignored. Properties containing
+// spaces are quoted. Values run to the end of the line.
+func Parse(text string) ([]core.Assertion, []core.Extraction, error) {
+	var assertions []core.Assertion
+	var extractions []core.Extraction
+
+	for i, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		word, rest := nextToken(line)
+		if strings.EqualFold(word, "extract") {
+			e, err := parseExtraction(rest)
+			if err != nil {
+				return nil, nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			extractions = append(extractions, e)
+			continue
+		}
+
+		a, err := parseAssertion(word, rest)
+		if err != nil {
+			return nil, nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		assertions = append(assertions, a)
+	}
+
+	return assertions, extractions, nil
+}
+
+func parseAssertion(source, rest string) (core.Assertion, error) {
+	a := core.Assertion{Source: core.AssertionSource(strings.ToLower(source))}
+	if _, ok := operators[a.Source]; !ok {
+		return a, fmt.Errorf("unknown assertion source %q", source)
+	}
+	if hasProperty(a.Source) {
+		var property string
+		property, rest = nextToken(rest)
+		a.Property = unquote(property)
+	}
+	var op string
+	op, rest = nextToken(rest)
+	a.Operator = core.AssertionOperator(strings.ToLower(op))
+	a.Value = strings.TrimSpace(rest)
+
+	if op == "" && (a.Property != "" || !hasProperty(a.Source)) {
+		return a, fmt.Errorf("%s assertion needs an operator (%s)", a.Source, joinOperators(operators[a.Source]))
+	}
+	return a, Validate(a)
+}
+
+func parseExtraction(rest string) (core.Extraction, error) {
+	var e core.Extraction
+	var source string
+	e.Variable, rest = nextToken(rest)
+	source, rest = nextToken(rest)
+	e.Source = core.AssertionSource(strings.ToLower(source))
+	e.Expression = strings.TrimSpace(rest)
+	if e.Source != core.AssertionSourceRegex {
+		e.Expression = unquote(e.Expression)
+	}
+	return e, ValidateExtraction(e)
+}
+
+// Format writes assertions and extractions in the syntax read by Parse.
+func Format(assertions []core.Assertion, extractions []core.Extraction) string {
+	lines := make([]string, 0, len(assertions)+len(extractions))
+	for _, a := range assertions {
+		lines = append(lines, FormatAssertion(a))
+	}
+	for _, e := range extractions {
+		lines = append(lines, FormatExtraction(e))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// FormatAssertion writes one assertion as a line of the Parse syntax. The
+// line doubles as the assertion's test name.
+func FormatAssertion(a core.Assertion) string {
+	parts := []string{string(a.Source)}
+	if hasProperty(a.Source) {
+		parts = append(parts, quoteToken(a.Property))
+	}
+	parts = append(parts, string(a.Operator))
+	if a.Value != "" {
+		parts = append(parts, a.Value)
+	}
+	return strings.Join(parts, " ")
+}
+
+// FormatExtraction writes one extraction as a line of the Parse syntax.
+func FormatExtraction(e core.Extraction) string {
+	expr := e.Expression
+	if e.Source != core.AssertionSourceRegex {
+		expr = quoteToken(expr)
+	}
+	return strings.Join([]string{"extract", e.Variable, string(e.Source), expr}, " ")
+}
+
+// nextToken splits off the first space-separated token. A token starting
+// with a quote runs to the matching quote.
+func nextToken(s string) (string, string) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return "", ""
+	}
+	if q := s[0]; q == '"' || q == '\'' {
+		for i := 1; i < len(s); i++ {
+			if s[i] == '\\' && q == '"' {
+				i++
+				continue
+			}
+			if s[i] == q {
+				return s[:i+1], s[i+1:]
+			}
+		}
+		return s, ""
+	}
+	if i := strings.IndexAny(s, " \t"); i >= 0 {
+		return s[:i], s[i+1:]
+	}
+	return s, ""
+}
+
+// unquote removes one level of single or double quotes.
+func unquote(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) < 2 || s[0] != s[len(s)-1] {
+		return s
+	}
+	switch s[0] {
+	case '"':
+		if u, err := strconv.Unquote(s); err == nil {
+			return u
+		}
+		return s[1 : len(s)-1]
+	case '\'':
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// quoteToken quotes a property that would otherwise split into tokens.
+func quoteToken(s string) string {
+	if s == "" || strings.ContainsAny(s, " \t") || s[0] == '"' || s[0] == '\'' {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+func splitList(s string) []string {
+	return strings.FieldsFunc(unquote(s), func(r rune) bool { return r == ',' || r == ' ' || r == '\t' })
+}
+
+func validStatus(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	if strings.HasSuffix(strings.ToLower(code), "xx") {
+		return code[0] >= '1' && code[0] <= '5'
+	}
+	n, err := strconv.Atoi(code)
+	return err == nil && n >= 100 && n <= 599
+}
+
+func validType(source core.AssertionSource, name string) error {
+	var types []string
+	if source == core.AssertionSourceXPath {
+		types = []string{"element", "attribute", "text"}
+	} else {
+		types = []string{"string", "number", "integer", "boolean", "null", "array", "object"}
+	}
+	for _, t := range types {
+		if name == t {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown type %q (use %s)", name, strings.Join(types, ", "))
+}
+
+func containsOperator(ops []core.AssertionOperator, op core.AssertionOperator) bool {
+	for _, o := range ops {
+		if o == op {
+			return true
+		}
+	}
+	return false
+}
+
+func containsSource(sources []core.AssertionSource, source core.AssertionSource) bool {
+	for _, s := range sources {
+		if s == source {
+			return true
+		}
+	}
+	return false
+}
+
+func joinOperators(ops []core.AssertionOperator) string {
+	names := make([]string, len(ops))
+	for i, op := range ops {
+		names[i] = string(op)
+	}
+	return strings.Join(names, ", ")
+}
+
+func propertyName(source core.AssertionSource) string {
+	switch source {
+	case core.AssertionSourceHeader:
+		return "header name"
+	case core.AssertionSourceXPath:
+		return "XPath"
+	}
+	return "JSONPath"
+}
+
+func hasVariables(s string) bool {
+	return strings.Contains(s, "{{")
+}
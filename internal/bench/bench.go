@@ -0,0 +1,419 @@
+// Package bench drives requests at a target rate or concurrency for a fixed
+// duration and reports latency percentiles, errors and throughput.
+package bench
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/artpar/currier/internal/core"
+	"github.com/artpar/currier/internal/interpolate"
+	httpclient "github.com/artpar/currier/internal/protocol/http"
+)
+
+// DefaultDuration is how long a benchmark runs when neither a duration nor a
+// request limit is set.
+const DefaultDuration = 10 * time.Second
+
+// Error classes used in Report.Errors.
+const (
+	ErrorTimeout = "timeout"
+	ErrorRefused = "connection refused"
+	ErrorReset   = "connection reset"
+	ErrorDNS     = "dns"
+	ErrorTLS     = "tls"
+	ErrorBuild   = "request build"
+	ErrorOther   = "other"
+)
+
+// Sample is the outcome of a single request.
+type Sample struct {
+	Request    int           // Index into the benchmarked requests
+	Offset     time.Duration // Completion time relative to the start of the run
+	Latency    time.Duration
+	Status     int    // Zero when the request failed before a response
+	ErrorClass string // Empty on transport success
+	Bytes      int64
+}
+
+// Failed reports whether the sample counts as an error: a transport failure
+// or an HTTP status of 400 or above.
+func (s Sample) Failed() bool {
+	return s.ErrorClass != "" || s.Status >= 400
+}
+
+// Snapshot is a point-in-time view of a running benchmark.
+type Snapshot struct {
+	Elapsed     time.Duration
+	Duration    time.Duration // Configured duration, zero when limited by count
+	Requests    int
+	Failed      int
+	MaxRequests int // Configured request limit, zero when limited by time
+}
+
+// Throughput returns the completed requests per second so far.
+func (s Snapshot) Throughput() float64 {
+	if s.Elapsed <= 0 {
+		return 0
+	}
+	return float64(s.Requests) / s.Elapsed.Seconds()
+}
+
+// Bench runs a load test against one or more request definitions. Requests
+// are sent round-robin; each send is interpolated again, so dynamic values
+// such as {{$uuid}} are fresh for every request.
+type Bench struct {
+	requests    []*core.RequestDefinition
+	target      string
+	engine      *interpolate.Engine
+	collection  *core.Collection
+	engines     []*interpolate.Engine // Per-request engines with folder variables
+	httpClient  *httpclient.Client
+	clientOpts  []httpclient.Option
+	concurrency int
+	rate        float64
+	duration    time.Duration
+	maxRequests int
+
+	mu      sync.Mutex
+	samples []Sample
+	failed  int
+	started time.Time
+}
+
+// Option configures the Bench.
+type Option func(*Bench)
+
+// WithEnvironment sets the environment for variable interpolation.
+func WithEnvironment(env *core.Environment) Option {
+	return func(b *Bench) {
+		if env != nil {
+			b.engine.Scope().SetEnvironment(interpolate.NewVariableSetFrom(env.ExportAll()))
+		}
+	}
+}
+
+// WithGlobals sets the variables of global environments, which resolve
+// beneath the environment's.
+func WithGlobals(globals map[string]string) Option {
+	return func(b *Bench) {
+		b.engine.Scope().SetGlobal(interpolate.NewVariableSetFrom(globals))
+	}
+}
+
+// WithCollection resolves variables through the collection the requests
+// belong to: its variables and those of each request's folders.
+func WithCollection(coll *core.Collection) Option {
+	return func(b *Bench) {
+		b.collection = coll
+	}
+}
+
+// WithEngine sets the interpolation engine.
+func WithEngine(engine *interpolate.Engine) Option {
+	return func(b *Bench) {
+		if engine != nil {
+			b.engine = engine
+		}
+	}
+}
+
+// WithSeed seeds the random source of {{$random*}} dynamic variables, so
+// runs generate the same sequence of values.
+func WithSeed(seed uint64) Option {
+	return func(b *Bench) {
+		b.engine.SetSeed(seed)
+	}
+}
+
+// WithHTTPClient sets a custom HTTP client.
+func WithHTTPClient(client *httpclient.Client) Option {
+	return func(b *Bench) {
+		b.httpClient = client
+	}
+}
+
+// WithClientOptions configures the bench's own HTTP client, e.g. with a
+// proxy or timeout, keeping its per-worker connection pool.
+func WithClientOptions(opts ...httpclient.Option) Option {
+	return func(b *Bench) {
+		b.clientOpts = append(b.clientOpts, opts...)
+	}
+}
+
+// WithConcurrency sets how many requests may be in flight at once. Defaults to 1.
+func WithConcurrency(n int) Option {
+	return func(b *Bench) {
+		b.concurrency = n
+	}
+}
+
+// WithRate limits how many requests are started per second. Zero sends as
+// fast as the workers allow.
+func WithRate(rps float64) Option {
+	return func(b *Bench) {
+		b.rate = rps
+	}
+}
+
+// WithDuration sets how long the benchmark runs. Requests still in flight
+// when the time is up are cancelled and not counted.
+func WithDuration(d time.Duration) Option {
+	return func(b *Bench) {
+		b.duration = d
+	}
+}
+
+// WithMaxRequests stops the benchmark after n requests.
+func WithMaxRequests(n int) Option {
+	return func(b *Bench) {
+		b.maxRequests = n
+	}
+}
+
+// WithTarget sets the name shown in the report, e.g. a collection name.
+func WithTarget(name string) Option {
+	return func(b *Bench) {
+		b.target = name
+	}
+}
+
+// New creates a benchmark for the given requests.
+func New(requests []*core.RequestDefinition, opts ...Option) *Bench {
+	b := &Bench{
+		requests:    requests,
+		engine:      interpolate.NewEngine(),
+		concurrency: 1,
+	}
+
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	if b.collection != nil {
+		b.engine.Scope().SetCollection(interpolate.NewVariableSetFrom(b.collection.Variables()))
+		b.engines = make([]*interpolate.Engine, len(requests))
+		for i, req := range requests {
+			b.engines[i] = b.engine.With(interpolate.LevelFolder, b.collection.FolderVariables(req.ID()))
+		}
+	}
+
+	if b.httpClient == nil {
+		clientOpts := []httpclient.Option{
+			httpclient.WithTimeout(30 * time.Second),
+			httpclient.WithTransport(newTransport(b.concurrency)),
+		}
+		b.httpClient = httpclient.NewClient(append(clientOpts, b.clientOpts...)...)
+	}
+	if b.target == "" && len(requests) == 1 {
+		b.target = requests[0].Method() + " " + requests[0].Name()
+	}
+
+	return b
+}
+
+// newTransport returns a transport that keeps one idle connection per worker,
+// so connections are reused instead of being reopened for every request.
+func newTransport(concurrency int) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConns = concurrency
+	transport.MaxIdleConnsPerHost = concurrency
+	return transport
+}
+
+// Run executes the benchmark until the duration elapses, the request limit
+// is reached or ctx is cancelled, and returns the report.
+func (b *Bench) Run(ctx context.Context) (*Report, error) {
+	if len(b.requests) == 0 {
+		return nil, fmt.Errorf("no requests to benchmark")
+	}
+	if b.concurrency < 1 {
+		return nil, fmt.Errorf("concurrency must be at least 1")
+	}
+	if b.rate < 0 {
+		return nil, fmt.Errorf("rate must not be negative")
+	}
+	if b.duration < 0 || b.maxRequests < 0 {
+		return nil, fmt.Errorf("duration and request count must not be negative")
+	}
+	if b.duration == 0 && b.maxRequests == 0 {
+		b.duration = DefaultDuration
+	}
+
+	var runCtx context.Context
+	var cancel context.CancelFunc
+	if b.duration > 0 {
+		runCtx, cancel = context.WithTimeout(ctx, b.duration)
+	} else {
+		runCtx, cancel = context.WithCancel(ctx)
+	}
+	defer cancel()
+
+	b.mu.Lock()
+	b.samples = nil
+	b.failed = 0
+	b.started = time.Now()
+	start := b.started
+	b.mu.Unlock()
+
+	jobs := make(chan int)
+	go b.dispatch(runCtx, start, jobs)
+
+	var wg sync.WaitGroup
+	for w := 0; w < b.concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				sample, ok := b.send(runCtx, i%len(b.requests), start)
+				if !ok {
+					continue
+				}
+				b.record(sample)
+			}
+		}()
+	}
+	wg.Wait()
+
+	b.mu.Lock()
+	samples := b.samples
+	b.mu.Unlock()
+
+	return newReport(b, samples, start, time.Now()), nil
+}
+
+// dispatch hands out request numbers to the workers, pacing them when a
+// rate is set. It closes jobs when the run is over.
+func (b *Bench) dispatch(ctx context.Context, start time.Time, jobs chan<- int) {
+	defer close(jobs)
+
+	var interval time.Duration
+	if b.rate > 0 {
+		interval = time.Duration(float64(time.Second) / b.rate)
+	}
+
+	for i := 0; b.maxRequests == 0 || i < b.maxRequests; i++ {
+		if interval > 0 {
+			// Schedule against the start time so slow sends don't drift the rate
+			if wait := time.Until(start.Add(time.Duration(i) * interval)); wait > 0 {
+				timer := time.NewTimer(wait)
+				select {
+				case <-timer.C:
+				case <-ctx.Done():
+					timer.Stop()
+					return
+				}
+			}
+		}
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// send executes one request. It returns false when the request was cut off
+// by the end of the run, so it should not be counted.
+func (b *Bench) send(ctx context.Context, index int, start time.Time) (Sample, bool) {
+	sample := Sample{Request: index}
+
+	engine := b.engine
+	if b.engines != nil {
+		engine = b.engines[index]
+	}
+	req, err := b.requests[index].ToRequestWithEnv(engine)
+	if err != nil {
+		sample.ErrorClass = ErrorBuild
+		sample.Offset = time.Since(start)
+		return sample, true
+	}
+
+	sent := time.Now()
+	resp, err := b.httpClient.Send(ctx, req)
+	sample.Latency = time.Since(sent)
+	sample.Offset = time.Since(start)
+
+	if err != nil {
+		if ctx.Err() != nil {
+			return sample, false
+		}
+		sample.ErrorClass = ClassifyError(err)
+		return sample, true
+	}
+
+	sample.Status = resp.Status().Code()
+	sample.Bytes = resp.Body().Size()
+	return sample, true
+}
+
+// record adds a sample to the results.
+func (b *Bench) record(sample Sample) {
+	b.mu.Lock()
+	b.samples = append(b.samples, sample)
+	if sample.Failed() {
+		b.failed++
+	}
+	b.mu.Unlock()
+}
+
+// Snapshot returns the progress of the running benchmark. It is safe to call
+// from another goroutine while Run is in progress.
+func (b *Bench) Snapshot() Snapshot {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	s := Snapshot{
+		Duration:    b.duration,
+		Requests:    len(b.samples),
+		Failed:      b.failed,
+		MaxRequests: b.maxRequests,
+	}
+	if !b.started.IsZero() {
+		s.Elapsed = time.Since(b.started)
+	}
+	return s
+}
+
+// ClassifyError maps a transport error to one of the Error* classes.
+func ClassifyError(err error) string {
+	var netErr net.Error
+	var dnsErr *net.DNSError
+	var certErr *tls.CertificateVerificationError
+	var unknownAuth x509.UnknownAuthorityError
+	var hostErr x509.HostnameError
+	var invalidErr x509.CertificateInvalidError
+	var recordErr tls.RecordHeaderError
+
+	switch {
+	case errors.As(err, &dnsErr):
+		return ErrorDNS
+	case errors.Is(err, context.DeadlineExceeded),
+		errors.As(err, &netErr) && netErr.Timeout():
+		return ErrorTimeout
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return ErrorRefused
+	case errors.Is(err, syscall.ECONNRESET),
+		errors.Is(err, syscall.EPIPE),
+		errors.Is(err, io.EOF),
+		errors.Is(err, io.ErrUnexpectedEOF):
+		return ErrorReset
+	case errors.As(err, &certErr),
+		errors.As(err, &unknownAuth),
+		errors.As(err, &hostErr),
+		errors.As(err, &invalidErr),
+		errors.As(err, &recordErr):
+		return ErrorTLS
+	default:
+		return ErrorOther
+	}
+}
@@ -0,0 +1,318 @@
+package bench
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/artpar/currier/internal/core"
+	httpclient "github.com/artpar/currier/internal/protocol/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBench_Run(t *testing.T) {
+	t.Run("stops after max requests", func(t *testing.T) {
+		var hits int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&hits, 1)
+			w.Write([]byte("ok"))
+		}))
+		defer server.Close()
+
+		req := core.NewRequestDefinition("Health", "GET", server.URL+"/health")
+		b := New([]*core.RequestDefinition{req}, WithConcurrency(4), WithMaxRequests(25))
+
+		report, err := b.Run(context.Background())
+		require.NoError(t, err)
+
+		assert.Equal(t, 25, report.Requests)
+		assert.Equal(t, int32(25), atomic.LoadInt32(&hits))
+		assert.Equal(t, 25, report.Succeeded)
+		assert.Equal(t, 0, report.Failed)
+		assert.Equal(t, 25, report.StatusCodes[200])
+		assert.Equal(t, int64(50), report.BytesReceived)
+		assert.Equal(t, "GET Health", report.Target)
+		assert.Equal(t, 4, report.Concurrency)
+		assert.Greater(t, report.Throughput, 0.0)
+		assert.LessOrEqual(t, report.Latency.Min, report.Latency.P50)
+		assert.LessOrEqual(t, report.Latency.P50, report.Latency.P99)
+		assert.LessOrEqual(t, report.Latency.P99, report.Latency.Max)
+
+		total := 0
+		for _, bucket := range report.Timeline {
+			total += bucket.Requests
+		}
+		assert.Equal(t, 25, total)
+	})
+
+	t.Run("interpolates every request", func(t *testing.T) {
+		var mu sync.Mutex
+		seen := make(map[string]bool)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			seen[r.Header.Get("X-Request-ID")] = true
+			mu.Unlock()
+		}))
+		defer server.Close()
+
+		env := core.NewEnvironment("test")
+		env.SetVariable("base", server.URL)
+		req := core.NewRequestDefinition("Create", "POST", "{{base}}/items")
+		req.SetHeader("X-Request-ID", "{{$uuid}}")
+
+		b := New([]*core.RequestDefinition{req}, WithEnvironment(env), WithConcurrency(2), WithMaxRequests(10))
+		report, err := b.Run(context.Background())
+		require.NoError(t, err)
+
+		assert.Equal(t, 10, report.Succeeded)
+		assert.Len(t, seen, 10)
+		assert.False(t, seen["{{$uuid}}"])
+	})
+
+	t.Run("breaks down errors by status and class", func(t *testing.T) {
+		var n int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&n, 1)%2 == 0 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+		}))
+		defer server.Close()
+
+		reqs := []*core.RequestDefinition{
+			core.NewRequestDefinition("Flaky", "GET", server.URL),
+			core.NewRequestDefinition("Down", "GET", "http://127.0.0.1:1/"),
+		}
+		b := New(reqs, WithMaxRequests(8), WithTarget("Mixed"))
+
+		report, err := b.Run(context.Background())
+		require.NoError(t, err)
+
+		assert.Equal(t, "Mixed", report.Target)
+		assert.Equal(t, 8, report.Requests)
+		assert.Equal(t, 4, report.Errors[ErrorRefused])
+		assert.Equal(t, 2, report.StatusCodes[200])
+		assert.Equal(t, 2, report.StatusCodes[503])
+		assert.Equal(t, 2, report.Succeeded)
+		assert.Equal(t, 6, report.Failed)
+		assert.InDelta(t, 0.75, report.ErrorRate(), 0.001)
+
+		require.Len(t, report.Endpoints, 2)
+		assert.Equal(t, "Flaky", report.Endpoints[0].Name)
+		assert.Equal(t, 4, report.Endpoints[0].Requests)
+		assert.Equal(t, 2, report.Endpoints[0].Failed)
+		assert.Equal(t, 4, report.Endpoints[1].Failed)
+	})
+
+	t.Run("limits the request rate", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		defer server.Close()
+
+		req := core.NewRequestDefinition("Health", "GET", server.URL)
+		b := New([]*core.RequestDefinition{req}, WithConcurrency(4), WithRate(50), WithDuration(300*time.Millisecond))
+
+		report, err := b.Run(context.Background())
+		require.NoError(t, err)
+
+		// 50 req/s for 300ms starts at most 15 requests
+		assert.LessOrEqual(t, report.Requests, 16)
+		assert.GreaterOrEqual(t, report.Requests, 5)
+		assert.Equal(t, 50.0, report.Rate)
+	})
+
+	t.Run("stops at the duration", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(5 * time.Millisecond)
+		}))
+		defer server.Close()
+
+		req := core.NewRequestDefinition("Slow", "GET", server.URL)
+		b := New([]*core.RequestDefinition{req}, WithConcurrency(2), WithDuration(200*time.Millisecond))
+
+		start := time.Now()
+		report, err := b.Run(context.Background())
+		require.NoError(t, err)
+
+		assert.Less(t, time.Since(start), 2*time.Second)
+		assert.Greater(t, report.Requests, 0)
+		assert.Empty(t, report.Errors, "requests cut off by the deadline are not counted")
+	})
+
+	t.Run("keeps pooled connections with client options", func(t *testing.T) {
+		var conns int32
+		server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(time.Millisecond)
+		}))
+		server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+			if state == http.StateNew {
+				atomic.AddInt32(&conns, 1)
+			}
+		}
+		server.StartTLS()
+		defer server.Close()
+
+		req := core.NewRequestDefinition("Health", "GET", server.URL)
+		b := New([]*core.RequestDefinition{req}, WithConcurrency(8), WithMaxRequests(80),
+			WithClientOptions(httpclient.WithInsecureSkipVerify()))
+		report, err := b.Run(context.Background())
+		require.NoError(t, err)
+
+		assert.Equal(t, 80, report.Succeeded)
+		assert.Less(t, atomic.LoadInt32(&conns), int32(16), "connections are reused across requests")
+	})
+
+	t.Run("rejects invalid configuration", func(t *testing.T) {
+		req := core.NewRequestDefinition("Health", "GET", "http://localhost")
+
+		_, err := New(nil).Run(context.Background())
+		assert.Error(t, err)
+
+		_, err = New([]*core.RequestDefinition{req}, WithConcurrency(0)).Run(context.Background())
+		assert.Error(t, err)
+
+		_, err = New([]*core.RequestDefinition{req}, WithRate(-1)).Run(context.Background())
+		assert.Error(t, err)
+	})
+}
+
+func TestBench_Snapshot(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+
+	req := core.NewRequestDefinition("Health", "GET", server.URL)
+	b := New([]*core.RequestDefinition{req}, WithMaxRequests(3))
+	assert.Equal(t, 0, b.Snapshot().Requests)
+
+	done := make(chan *Report)
+	go func() {
+		report, _ := b.Run(context.Background())
+		done <- report
+	}()
+	close(release)
+	report := <-done
+
+	snap := b.Snapshot()
+	assert.Equal(t, 3, snap.Requests)
+	assert.Equal(t, 3, snap.MaxRequests)
+	assert.Equal(t, report.Requests, snap.Requests)
+	assert.Greater(t, snap.Throughput(), 0.0)
+}
+
+func TestPercentile(t *testing.T) {
+	var latencies []time.Duration
+	for i := 1; i <= 100; i++ {
+		latencies = append(latencies, time.Duration(i)*time.Millisecond)
+	}
+
+	assert.Equal(t, 50*time.Millisecond, Percentile(latencies, 50))
+	assert.Equal(t, 90*time.Millisecond, Percentile(latencies, 90))
+	assert.Equal(t, 99*time.Millisecond, Percentile(latencies, 99))
+	assert.Equal(t, 100*time.Millisecond, Percentile(latencies, 100))
+	assert.Equal(t, 1*time.Millisecond, Percentile(latencies, 0))
+	assert.Equal(t, time.Duration(0), Percentile(nil, 50))
+	assert.Equal(t, 7*time.Millisecond, Percentile([]time.Duration{7 * time.Millisecond}, 99))
+}
+
+func TestTimeline(t *testing.T) {
+	t.Run("one second buckets", func(t *testing.T) {
+		samples := []Sample{
+			{Offset: 100 * time.Millisecond},
+			{Offset: 900 * time.Millisecond, Status: 500},
+			{Offset: 1500 * time.Millisecond},
+		}
+		width, buckets := timeline(samples, 2*time.Second)
+
+		assert.Equal(t, time.Second, width)
+		require.Len(t, buckets, 2)
+		assert.Equal(t, 2, buckets[0].Requests)
+		assert.Equal(t, 1, buckets[0].Failed)
+		assert.Equal(t, 2.0, buckets[0].Rate)
+		assert.Equal(t, time.Second, buckets[1].Start)
+		assert.Equal(t, 1, buckets[1].Requests)
+	})
+
+	t.Run("wider buckets for long runs", func(t *testing.T) {
+		width, buckets := timeline(nil, 5*time.Minute)
+		assert.Equal(t, 5*time.Second, width)
+		assert.Len(t, buckets, 60)
+	})
+
+	t.Run("partial last bucket", func(t *testing.T) {
+		_, buckets := timeline([]Sample{{Offset: 1200 * time.Millisecond}}, 1500*time.Millisecond)
+		require.Len(t, buckets, 2)
+		assert.InDelta(t, 2.0, buckets[1].Rate, 0.001)
+	})
+}
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{context.DeadlineExceeded, ErrorTimeout},
+		{&net.DNSError{Err: "no such host", Name: "nope.invalid"}, ErrorDNS},
+		{fmt.Errorf("read: %w", &net.OpError{Op: "dial", Err: timeoutError{}}), ErrorTimeout},
+		{errors.New("boom"), ErrorOther},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			assert.Equal(t, tt.want, ClassifyError(tt.err))
+		})
+	}
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestReport_WriteJSON(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	req := core.NewRequestDefinition("Create", "POST", server.URL)
+	report, err := New([]*core.RequestDefinition{req}, WithMaxRequests(5)).Run(context.Background())
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, report.WriteJSON(&buf))
+
+	var out map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &out))
+	assert.Equal(t, "POST Create", out["target"])
+	assert.Equal(t, 5.0, out["requests"])
+	assert.Equal(t, map[string]any{"201": 5.0}, out["status_codes"])
+	assert.Contains(t, out["latency"], "p99_ms")
+	assert.NotEmpty(t, out["timeline"])
+	assert.NotContains(t, out, "endpoints")
+}
+
+func TestReport_Histogram(t *testing.T) {
+	report := &Report{Timeline: []Bucket{
+		{Start: 0, Requests: 10, Rate: 10},
+		{Start: time.Second, Requests: 5, Failed: 2, Rate: 5},
+		{Start: 2 * time.Second},
+	}}
+
+	lines := report.Histogram(10)
+	require.Len(t, lines, 3)
+	assert.Equal(t, "   0s ██████████ 10.0", lines[0])
+	assert.Equal(t, "   1s █████ 5.0 (2 errors)", lines[1])
+	assert.Equal(t, "   2s  0.0", lines[2])
+}
@@ -0,0 +1,347 @@
+package bench
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxBuckets caps the number of throughput buckets; long runs use wider buckets.
+const maxBuckets = 60
+
+// LatencyStats summarizes the latency of requests that received a response.
+type LatencyStats struct {
+	Min  time.Duration
+	Mean time.Duration
+	P50  time.Duration
+	P90  time.Duration
+	P95  time.Duration
+	P99  time.Duration
+	Max  time.Duration
+}
+
+// Bucket is one slot of the throughput histogram.
+type Bucket struct {
+	Start    time.Duration // Offset from the start of the run
+	Requests int
+	Failed   int
+	Rate     float64 // Requests per second within the bucket
+}
+
+// EndpointStats summarizes one request of a multi-request benchmark.
+type EndpointStats struct {
+	Name     string
+	Method   string
+	Requests int
+	Failed   int
+	Latency  LatencyStats
+}
+
+// Report is the result of a benchmark run.
+type Report struct {
+	Target        string
+	StartTime     time.Time
+	EndTime       time.Time
+	Duration      time.Duration
+	Concurrency   int
+	Rate          float64 // Target rate, zero when unlimited
+	Requests      int
+	Succeeded     int
+	Failed        int
+	BytesReceived int64
+	Throughput    float64 // Completed requests per second
+	Latency       LatencyStats
+	StatusCodes   map[int]int    // Count per HTTP status
+	Errors        map[string]int // Count per transport error class
+	BucketWidth   time.Duration
+	Timeline      []Bucket
+	Endpoints     []EndpointStats // Per-request stats when benchmarking several requests
+}
+
+// newReport aggregates samples into a report.
+func newReport(b *Bench, samples []Sample, start, end time.Time) *Report {
+	r := &Report{
+		Target:      b.target,
+		StartTime:   start,
+		EndTime:     end,
+		Duration:    end.Sub(start),
+		Concurrency: b.concurrency,
+		Rate:        b.rate,
+		Requests:    len(samples),
+		StatusCodes: make(map[int]int),
+		Errors:      make(map[string]int),
+	}
+
+	latencies := make([]time.Duration, 0, len(samples))
+	perRequest := make([][]time.Duration, len(b.requests))
+	if len(b.requests) > 1 {
+		r.Endpoints = make([]EndpointStats, len(b.requests))
+		for i, req := range b.requests {
+			r.Endpoints[i] = EndpointStats{Name: req.Name(), Method: req.Method()}
+		}
+	}
+
+	for _, s := range samples {
+		if s.Failed() {
+			r.Failed++
+		} else {
+			r.Succeeded++
+		}
+		if s.ErrorClass != "" {
+			r.Errors[s.ErrorClass]++
+		}
+		if s.Status != 0 {
+			r.StatusCodes[s.Status]++
+			latencies = append(latencies, s.Latency)
+			perRequest[s.Request] = append(perRequest[s.Request], s.Latency)
+		}
+		r.BytesReceived += s.Bytes
+
+		if r.Endpoints != nil {
+			r.Endpoints[s.Request].Requests++
+			if s.Failed() {
+				r.Endpoints[s.Request].Failed++
+			}
+		}
+	}
+
+	r.Latency = latencyStats(latencies)
+	for i := range r.Endpoints {
+		r.Endpoints[i].Latency = latencyStats(perRequest[i])
+	}
+	if r.Duration > 0 {
+		r.Throughput = float64(r.Requests) / r.Duration.Seconds()
+	}
+	r.BucketWidth, r.Timeline = timeline(samples, r.Duration)
+
+	return r
+}
+
+// ErrorRate returns the fraction of requests that failed, between 0 and 1.
+func (r *Report) ErrorRate() float64 {
+	if r.Requests == 0 {
+		return 0
+	}
+	return float64(r.Failed) / float64(r.Requests)
+}
+
+// latencyStats computes summary statistics for a set of latencies.
+func latencyStats(latencies []time.Duration) LatencyStats {
+	if len(latencies) == 0 {
+		return LatencyStats{}
+	}
+
+	sorted := make([]time.Duration, len(latencies))
+	copy(sorted, latencies)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var total time.Duration
+	for _, l := range sorted {
+		total += l
+	}
+
+	return LatencyStats{
+		Min:  sorted[0],
+		Mean: total / time.Duration(len(sorted)),
+		P50:  Percentile(sorted, 50),
+		P90:  Percentile(sorted, 90),
+		P95:  Percentile(sorted, 95),
+		P99:  Percentile(sorted, 99),
+		Max:  sorted[len(sorted)-1],
+	}
+}
+
+// Percentile returns the p-th percentile (0-100) of sorted latencies using
+// the nearest-rank method.
+func Percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	if rank > len(sorted) {
+		rank = len(sorted)
+	}
+	return sorted[rank-1]
+}
+
+// timeline groups samples by completion time. Buckets are one second wide,
+// or wider for long runs so there are at most maxBuckets of them.
+func timeline(samples []Sample, duration time.Duration) (time.Duration, []Bucket) {
+	width := time.Second
+	if duration > maxBuckets*time.Second {
+		width = time.Duration(math.Ceil(duration.Seconds()/maxBuckets)) * time.Second
+	}
+
+	count := int((duration + width - 1) / width)
+	if count < 1 {
+		count = 1
+	}
+	buckets := make([]Bucket, count)
+	for i := range buckets {
+		buckets[i].Start = time.Duration(i) * width
+	}
+
+	for _, s := range samples {
+		i := int(s.Offset / width)
+		if i >= count {
+			i = count - 1
+		}
+		buckets[i].Requests++
+		if s.Failed() {
+			buckets[i].Failed++
+		}
+	}
+
+	for i := range buckets {
+		// The last bucket may cover less than a full width
+		span := width
+		if end := buckets[i].Start + width; end > duration && duration > buckets[i].Start {
+			span = duration - buckets[i].Start
+		}
+		buckets[i].Rate = float64(buckets[i].Requests) / span.Seconds()
+	}
+
+	return width, buckets
+}
+
+type jsonLatency struct {
+	MinMS  float64 `json:"min_ms"`
+	MeanMS float64 `json:"mean_ms"`
+	P50MS  float64 `json:"p50_ms"`
+	P90MS  float64 `json:"p90_ms"`
+	P95MS  float64 `json:"p95_ms"`
+	P99MS  float64 `json:"p99_ms"`
+	MaxMS  float64 `json:"max_ms"`
+}
+
+type jsonBucket struct {
+	StartMS  int64   `json:"start_ms"`
+	Requests int     `json:"requests"`
+	Failed   int     `json:"failed"`
+	Rate     float64 `json:"rate"`
+}
+
+type jsonEndpoint struct {
+	Name     string      `json:"name"`
+	Method   string      `json:"method"`
+	Requests int         `json:"requests"`
+	Failed   int         `json:"failed"`
+	Latency  jsonLatency `json:"latency"`
+}
+
+type jsonReport struct {
+	Target        string         `json:"target"`
+	StartTime     time.Time      `json:"start_time"`
+	DurationMS    int64          `json:"duration_ms"`
+	Concurrency   int            `json:"concurrency"`
+	Rate          float64        `json:"rate,omitempty"`
+	Requests      int            `json:"requests"`
+	Succeeded     int            `json:"succeeded"`
+	Failed        int            `json:"failed"`
+	ErrorRate     float64        `json:"error_rate"`
+	BytesReceived int64          `json:"bytes_received"`
+	Throughput    float64        `json:"throughput"`
+	Latency       jsonLatency    `json:"latency"`
+	StatusCodes   map[string]int `json:"status_codes"`
+	Errors        map[string]int `json:"errors"`
+	BucketWidthMS int64          `json:"bucket_width_ms"`
+	Timeline      []jsonBucket   `json:"timeline"`
+	Endpoints     []jsonEndpoint `json:"endpoints,omitempty"`
+}
+
+// WriteJSON writes the report as indented JSON. Durations are in
+// milliseconds.
+func (r *Report) WriteJSON(w io.Writer) error {
+	out := jsonReport{
+		Target:        r.Target,
+		StartTime:     r.StartTime,
+		DurationMS:    r.Duration.Milliseconds(),
+		Concurrency:   r.Concurrency,
+		Rate:          r.Rate,
+		Requests:      r.Requests,
+		Succeeded:     r.Succeeded,
+		Failed:        r.Failed,
+		ErrorRate:     r.ErrorRate(),
+		BytesReceived: r.BytesReceived,
+		Throughput:    r.Throughput,
+		Latency:       toJSONLatency(r.Latency),
+		StatusCodes:   make(map[string]int, len(r.StatusCodes)),
+		Errors:        r.Errors,
+		BucketWidthMS: r.BucketWidth.Milliseconds(),
+		Timeline:      make([]jsonBucket, 0, len(r.Timeline)),
+	}
+	for code, n := range r.StatusCodes {
+		out.StatusCodes[strconv.Itoa(code)] = n
+	}
+	for _, b := range r.Timeline {
+		out.Timeline = append(out.Timeline, jsonBucket{
+			StartMS:  b.Start.Milliseconds(),
+			Requests: b.Requests,
+			Failed:   b.Failed,
+			Rate:     b.Rate,
+		})
+	}
+	for _, e := range r.Endpoints {
+		out.Endpoints = append(out.Endpoints, jsonEndpoint{
+			Name:     e.Name,
+			Method:   e.Method,
+			Requests: e.Requests,
+			Failed:   e.Failed,
+			Latency:  toJSONLatency(e.Latency),
+		})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+func toJSONLatency(l LatencyStats) jsonLatency {
+	return jsonLatency{
+		MinMS:  ms(l.Min),
+		MeanMS: ms(l.Mean),
+		P50MS:  ms(l.P50),
+		P90MS:  ms(l.P90),
+		P95MS:  ms(l.P95),
+		P99MS:  ms(l.P99),
+		MaxMS:  ms(l.Max),
+	}
+}
+
+// ms converts a duration to fractional milliseconds.
+func ms(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// Histogram renders the throughput timeline as text bars no wider than
+// width characters, one line per bucket.
+func (r *Report) Histogram(width int) []string {
+	maxRate := 0.0
+	for _, b := range r.Timeline {
+		if b.Rate > maxRate {
+			maxRate = b.Rate
+		}
+	}
+
+	lines := make([]string, 0, len(r.Timeline))
+	for _, b := range r.Timeline {
+		filled := 0
+		if maxRate > 0 {
+			filled = int(float64(width)*b.Rate/maxRate + 0.5)
+		}
+		line := fmt.Sprintf("%5s %s %.1f", b.Start.String(), strings.Repeat("█", filled), b.Rate)
+		if b.Failed > 0 {
+			line += fmt.Sprintf(" (%d errors)", b.Failed)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
@@ -0,0 +1,406 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/artpar/currier/internal/bench"
+	"github.com/artpar/currier/internal/core"
+	httpclient "github.com/artpar/currier/internal/protocol/http"
+	"github.com/spf13/cobra"
+)
+
+// BenchOptions holds options for the bench command.
+type BenchOptions struct {
+	Method             string
+	Headers            []string
+	Body               string
+	Requests           []string
+	EnvFiles           []string
+	Concurrency        int
+	Rate               float64
+	Duration           time.Duration
+	MaxRequests        int
+	Timeout            time.Duration
+	ProxyURL           string
+	InsecureSkipVerify bool
+	JSON               bool
+	Output             string
+	Seed               uint64
+}
+
+// NewBenchCommand creates the bench command.
+func NewBenchCommand() *cobra.Command {
+	opts := &BenchOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "bench URL|COLLECTION_FILE",
+		Short: "Load test a request or collection",
+		Long: `Send a request or the requests of a collection repeatedly and report
+latency percentiles, errors and throughput.
+
+The target is either a URL (use --method, --header and --body to shape the
+request) or a collection file. For collections, --request selects requests by
+name; otherwise all requests are sent in round-robin order.
+
+Every request is interpolated again before it is sent, so dynamic variables
+such as {{$uuid}} get a fresh value each time.
+
+The run lasts --duration, or until --requests have been sent. --concurrency
+sets how many requests are in flight at once and --rate caps how many are
+started per second. Press Ctrl+C to stop early and still get the report.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runBench(cmd, args[0], opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.Method, "method", "X", "GET", "HTTP method for URL targets")
+	cmd.Flags().StringArrayVarP(&opts.Headers, "header", "H", nil, "Request headers for URL targets (format: Key:Value)")
+	cmd.Flags().StringVarP(&opts.Body, "body", "d", "", "Request body for URL targets")
+	cmd.Flags().StringArrayVarP(&opts.Requests, "request", "r", nil, "Collection request(s) to benchmark, by name")
+	cmd.Flags().StringArrayVarP(&opts.EnvFiles, "env", "e", nil, "Environment file(s) for variable substitution")
+	cmd.Flags().IntVarP(&opts.Concurrency, "concurrency", "c", 10, "Number of requests in flight at once")
+	cmd.Flags().Float64Var(&opts.Rate, "rate", 0, "Maximum requests started per second (0 = unlimited)")
+	cmd.Flags().DurationVar(&opts.Duration, "duration", bench.DefaultDuration, "How long to run")
+	cmd.Flags().IntVarP(&opts.MaxRequests, "requests", "n", 0, "Stop after this many requests (0 = no limit)")
+	cmd.Flags().DurationVar(&opts.Timeout, "timeout", 30*time.Second, "Request timeout")
+	cmd.Flags().StringVar(&opts.ProxyURL, "proxy", "", "Proxy URL (http://, https://, or socks5://)")
+	cmd.Flags().BoolVarP(&opts.InsecureSkipVerify, "insecure", "k", false, "Skip server certificate verification")
+	cmd.Flags().BoolVar(&opts.JSON, "json", false, "Output the report as JSON")
+	cmd.Flags().StringVarP(&opts.Output, "output", "o", "", "Also write the JSON report to a file")
+	cmd.Flags().Uint64Var(&opts.Seed, "seed", 0, "Seed for {{$random*}} dynamic variables, for reproducible runs")
+
+	return cmd
+}
+
+func runBench(cmd *cobra.Command, target string, opts *BenchOptions) error {
+	if opts.Concurrency < 1 {
+		return fmt.Errorf("--concurrency must be at least 1")
+	}
+	if opts.Rate < 0 {
+		return fmt.Errorf("--rate must not be negative")
+	}
+	if opts.MaxRequests < 0 {
+		return fmt.Errorf("--requests must not be negative")
+	}
+
+	requests, name, coll, err := benchRequests(target, opts)
+	if err != nil {
+		return err
+	}
+
+	benchOpts := []bench.Option{
+		bench.WithTarget(name),
+		bench.WithConcurrency(opts.Concurrency),
+		bench.WithRate(opts.Rate),
+		bench.WithMaxRequests(opts.MaxRequests),
+	}
+	// An explicit request limit without an explicit duration runs until done
+	if opts.MaxRequests == 0 || cmd.Flags().Changed("duration") {
+		benchOpts = append(benchOpts, bench.WithDuration(opts.Duration))
+	}
+
+	// Load environment if provided
+	var env *core.Environment
+	if len(opts.EnvFiles) > 0 {
+		env, err = core.LoadMultipleEnvironments(opts.EnvFiles)
+		if err != nil {
+			return fmt.Errorf("failed to load environment: %w", err)
+		}
+		if env != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Using environment: %s\n", env.Name())
+		}
+	}
+	if coll != nil {
+		benchOpts = append(benchOpts, bench.WithCollection(coll))
+	}
+	if globals := loadGlobals(cmd.ErrOrStderr()); len(globals) > 0 {
+		benchOpts = append(benchOpts, bench.WithGlobals(core.MergeGlobals(globals)))
+	}
+	if env != nil {
+		benchOpts = append(benchOpts, bench.WithEnvironment(env))
+	}
+	if cmd.Flags().Changed("seed") {
+		benchOpts = append(benchOpts, bench.WithSeed(opts.Seed))
+	}
+
+	// Only change the client defaults when asked; the bench's own client
+	// pools one connection per worker.
+	if opts.ProxyURL != "" || opts.InsecureSkipVerify || cmd.Flags().Changed("timeout") {
+		clientOpts := []httpclient.Option{httpclient.WithTimeout(opts.Timeout)}
+		if opts.ProxyURL != "" {
+			clientOpts = append(clientOpts, httpclient.WithProxy(opts.ProxyURL))
+		}
+		if opts.InsecureSkipVerify {
+			clientOpts = append(clientOpts, httpclient.WithInsecureSkipVerify())
+		}
+		benchOpts = append(benchOpts, bench.WithClientOptions(clientOpts...))
+	}
+
+	b := bench.New(requests, benchOpts...)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	errOut := cmd.ErrOrStderr()
+	fmt.Fprintf(errOut, "Benchmarking %s with %d workers\n", name, opts.Concurrency)
+
+	// Live progress on stderr
+	done := make(chan struct{})
+	progressDone := make(chan struct{})
+	go func() {
+		defer close(progressDone)
+		ticker := time.NewTicker(250 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				fmt.Fprintf(errOut, "\r%s", formatBenchProgress(b.Snapshot()))
+			case <-done:
+				fmt.Fprintf(errOut, "\r%s\n", formatBenchProgress(b.Snapshot()))
+				return
+			}
+		}
+	}()
+
+	report, err := b.Run(ctx)
+	close(done)
+	<-progressDone
+	if err != nil {
+		return err
+	}
+
+	if opts.Output != "" {
+		if err := writeBenchJSONFile(opts.Output, report); err != nil {
+			return err
+		}
+		fmt.Fprintf(errOut, "Wrote bench report: %s\n", opts.Output)
+	}
+
+	if opts.JSON {
+		return report.WriteJSON(cmd.OutOrStdout())
+	}
+	writeBenchReport(cmd.OutOrStdout(), report)
+	return nil
+}
+
+// benchRequests resolves the bench target to request definitions and a
+// display name. The collection is nil for URL targets.
+func benchRequests(target string, opts *BenchOptions) ([]*core.RequestDefinition, string, *core.Collection, error) {
+	if isURLTarget(target) {
+		if len(opts.Requests) > 0 {
+			return nil, "", nil, fmt.Errorf("--request only applies to collection targets")
+		}
+		method := strings.ToUpper(opts.Method)
+		req := core.NewRequestDefinition(target, method, target)
+		headers := parseHeaders(opts.Headers)
+		for key, value := range headers {
+			req.SetHeader(key, value)
+		}
+		if opts.Body != "" {
+			contentType := headers["Content-Type"]
+			if contentType == "" {
+				contentType = "text/plain"
+			}
+			req.SetBodyRaw(opts.Body, contentType)
+		}
+		return []*core.RequestDefinition{req}, method + " " + target, nil, nil
+	}
+
+	data, err := os.ReadFile(target)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("failed to read collection file: %w", err)
+	}
+	result, err := newImporterRegistry().DetectAndImport(context.Background(), data)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("failed to parse collection: %w", err)
+	}
+	coll := result.Collection
+
+	all := collectionRequests(coll)
+	if len(opts.Requests) == 0 {
+		if len(all) == 0 {
+			return nil, "", nil, fmt.Errorf("collection %s has no requests", coll.Name())
+		}
+		return all, coll.Name(), coll, nil
+	}
+
+	var selected []*core.RequestDefinition
+	for _, name := range opts.Requests {
+		found := false
+		for _, req := range all {
+			if req.Name() == name {
+				selected = append(selected, req)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, "", nil, fmt.Errorf("no request named %q in collection %s", name, coll.Name())
+		}
+	}
+	if len(selected) == 1 {
+		return selected, selected[0].Method() + " " + selected[0].Name(), coll, nil
+	}
+	return selected, coll.Name(), coll, nil
+}
+
+// isURLTarget reports whether a bench target is a URL rather than a file.
+func isURLTarget(target string) bool {
+	return strings.HasPrefix(target, "http://") ||
+		strings.HasPrefix(target, "https://") ||
+		strings.HasPrefix(target, "{{")
+}
+
+// collectionRequests returns all requests of a collection, folders included,
+// in collection order.
+func collectionRequests(coll *core.Collection) []*core.RequestDefinition {
+	requests := append([]*core.RequestDefinition{}, coll.Requests()...)
+	var walk func(folders []*core.Folder)
+	walk = func(folders []*core.Folder) {
+		for _, folder := range folders {
+			requests = append(requests, folder.Requests()...)
+			walk(folder.Folders())
+		}
+	}
+	walk(coll.Folders())
+	return requests
+}
+
+func writeBenchJSONFile(path string, report *bench.Report) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create bench report: %w", err)
+	}
+	if err := report.WriteJSON(f); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write bench report: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write bench report: %w", err)
+	}
+	return nil
+}
+
+// formatBenchProgress renders a one-line progress readout.
+func formatBenchProgress(s bench.Snapshot) string {
+	line := fmt.Sprintf("Running: %s", s.Elapsed.Round(100*time.Millisecond))
+	if s.Duration > 0 {
+		line += " / " + s.Duration.String()
+	}
+	line += fmt.Sprintf("  %d requests", s.Requests)
+	if s.MaxRequests > 0 {
+		line += fmt.Sprintf(" / %d", s.MaxRequests)
+	}
+	line += fmt.Sprintf("  %.1f req/s  %d errors", s.Throughput(), s.Failed)
+	return line
+}
+
+// writeBenchReport writes the human-readable bench report.
+func writeBenchReport(out io.Writer, r *bench.Report) {
+	fmt.Fprintln(out)
+	fmt.Fprintf(out, "Target:      %s\n", r.Target)
+	fmt.Fprintf(out, "Duration:    %s\n", formatDuration(r.Duration))
+	rate := "unlimited"
+	if r.Rate > 0 {
+		rate = fmt.Sprintf("%g req/s", r.Rate)
+	}
+	fmt.Fprintf(out, "Workers:     %d (rate: %s)\n", r.Concurrency, rate)
+	fmt.Fprintln(out)
+
+	fmt.Fprintf(out, "Requests:    %d (%.1f req/s)\n", r.Requests, r.Throughput)
+	fmt.Fprintf(out, "Succeeded:   %d\n", r.Succeeded)
+	fmt.Fprintf(out, "Failed:      %d (%.1f%%)\n", r.Failed, 100*r.ErrorRate())
+	fmt.Fprintf(out, "Received:    %s\n", formatByteCount(r.BytesReceived))
+
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "Latency:")
+	writeLatency(out, r.Latency, "  ")
+
+	if len(r.StatusCodes) > 0 {
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, "Status codes:")
+		codes := make([]int, 0, len(r.StatusCodes))
+		for code := range r.StatusCodes {
+			codes = append(codes, code)
+		}
+		sort.Ints(codes)
+		for _, code := range codes {
+			fmt.Fprintf(out, "  %d  %d\n", code, r.StatusCodes[code])
+		}
+	}
+
+	if len(r.Errors) > 0 {
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, "Errors:")
+		classes := make([]string, 0, len(r.Errors))
+		for class := range r.Errors {
+			classes = append(classes, class)
+		}
+		sort.Strings(classes)
+		for _, class := range classes {
+			fmt.Fprintf(out, "  %-20s %d\n", class, r.Errors[class])
+		}
+	}
+
+	if len(r.Endpoints) > 0 {
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, "Requests:")
+		for _, e := range r.Endpoints {
+			fmt.Fprintf(out, "  %s %s: %d sent, %d failed, p50 %s, p99 %s\n",
+				e.Method, e.Name, e.Requests, e.Failed, formatLatency(e.Latency.P50), formatLatency(e.Latency.P99))
+		}
+	}
+
+	if len(r.Timeline) > 0 {
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, "Throughput (req/s):")
+		for _, line := range r.Histogram(40) {
+			fmt.Fprintf(out, "  %s\n", line)
+		}
+	}
+}
+
+func writeLatency(out io.Writer, l bench.LatencyStats, indent string) {
+	rows := []struct {
+		label string
+		value time.Duration
+	}{
+		{"min", l.Min}, {"mean", l.Mean}, {"p50", l.P50}, {"p90", l.P90},
+		{"p95", l.P95}, {"p99", l.P99}, {"max", l.Max},
+	}
+	for _, row := range rows {
+		fmt.Fprintf(out, "%s%-5s %s\n", indent, row.label, formatLatency(row.value))
+	}
+}
+
+// formatLatency formats a latency with sub-millisecond precision.
+func formatLatency(d time.Duration) string {
+	switch {
+	case d < time.Millisecond:
+		return fmt.Sprintf("%dµs", d.Microseconds())
+	case d < time.Second:
+		return fmt.Sprintf("%.2fms", float64(d)/float64(time.Millisecond))
+	default:
+		return fmt.Sprintf("%.2fs", d.Seconds())
+	}
+}
+
+// formatByteCount formats a byte count using binary units.
+func formatByteCount(n int64) string {
+	switch {
+	case n < 1024:
+		return fmt.Sprintf("%dB", n)
+	case n < 1024*1024:
+		return fmt.Sprintf("%.1fKB", float64(n)/1024)
+	default:
+		return fmt.Sprintf("%.1fMB", float64(n)/(1024*1024))
+	}
+}
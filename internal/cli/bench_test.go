@@ -0,0 +1,169 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/artpar/currier/internal/bench"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBenchCommand(t *testing.T) {
+	var mu sync.Mutex
+	paths := make(map[string]int)
+	bodies := make(map[string]bool)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		buf := new(bytes.Buffer)
+		buf.ReadFrom(r.Body)
+		mu.Lock()
+		paths[r.Method+" "+r.URL.Path]++
+		bodies[buf.String()] = true
+		mu.Unlock()
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	reset := func() {
+		mu.Lock()
+		paths = make(map[string]int)
+		bodies = make(map[string]bool)
+		mu.Unlock()
+	}
+
+	t.Run("registered on root", func(t *testing.T) {
+		cmd, _, err := NewRootCommand("1.0.0").Find([]string{"bench"})
+		require.NoError(t, err)
+		assert.Contains(t, cmd.Use, "bench")
+	})
+
+	t.Run("benchmarks a URL", func(t *testing.T) {
+		reset()
+		out := &bytes.Buffer{}
+		errOut := &bytes.Buffer{}
+		cmd := NewBenchCommand()
+		cmd.SetOut(out)
+		cmd.SetErr(errOut)
+		cmd.SetArgs([]string{server.URL + "/items", "-X", "post", "-d", `{"id":"{{$uuid}}"}`,
+			"-H", "Content-Type: application/json", "-c", "3", "-n", "12"})
+
+		require.NoError(t, cmd.Execute())
+
+		assert.Equal(t, 12, paths["POST /items"])
+		assert.Len(t, bodies, 12, "each request gets a fresh {{$uuid}}")
+		assert.Contains(t, errOut.String(), "Benchmarking POST "+server.URL+"/items with 3 workers")
+
+		output := out.String()
+		assert.Contains(t, output, "Requests:    12")
+		assert.Contains(t, output, "Failed:      0 (0.0%)")
+		assert.Contains(t, output, "p99")
+		assert.Contains(t, output, "Status codes:\n  200  12")
+		assert.Contains(t, output, "Throughput (req/s):")
+	})
+
+	t.Run("benchmarks selected collection requests as JSON", func(t *testing.T) {
+		reset()
+		dir := t.TempDir()
+		collection := fmt.Sprintf(`{
+			"info": {
+				"name": "Bench API",
+				"schema": "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+			},
+			"variable": [{"key": "base", "value": "%s"}],
+			"item": [
+				{"name": "List", "request": {"method": "GET", "url": "{{base}}/items"}},
+				{"name": "Folder", "item": [
+					{"name": "Missing", "request": {"method": "GET", "url": "{{base}}/missing"}}
+				]},
+				{"name": "Other", "request": {"method": "GET", "url": "{{base}}/other"}}
+			]
+		}`, server.URL)
+		collPath := filepath.Join(dir, "collection.json")
+		require.NoError(t, os.WriteFile(collPath, []byte(collection), 0644))
+		reportPath := filepath.Join(dir, "bench.json")
+
+		out := &bytes.Buffer{}
+		errOut := &bytes.Buffer{}
+		cmd := NewBenchCommand()
+		cmd.SetOut(out)
+		cmd.SetErr(errOut)
+		cmd.SetArgs([]string{collPath, "-r", "List", "-r", "Missing", "-c", "1", "-n", "6", "--json", "-o", reportPath})
+
+		require.NoError(t, cmd.Execute())
+
+		assert.Equal(t, 3, paths["GET /items"])
+		assert.Equal(t, 3, paths["GET /missing"])
+		assert.Zero(t, paths["GET /other"])
+
+		var report map[string]any
+		require.NoError(t, json.Unmarshal(out.Bytes(), &report))
+		assert.Equal(t, "Bench API", report["target"])
+		assert.Equal(t, 6.0, report["requests"])
+		assert.Equal(t, 3.0, report["failed"])
+		assert.Equal(t, map[string]any{"200": 3.0, "404": 3.0}, report["status_codes"])
+		assert.Len(t, report["endpoints"], 2)
+
+		saved, err := os.ReadFile(reportPath)
+		require.NoError(t, err)
+		assert.True(t, json.Valid(saved))
+		assert.Contains(t, errOut.String(), "Wrote bench report: "+reportPath)
+	})
+
+	t.Run("rejects unknown request", func(t *testing.T) {
+		dir := t.TempDir()
+		collPath := filepath.Join(dir, "collection.json")
+		require.NoError(t, os.WriteFile(collPath, []byte(`{
+			"info": {"name": "Empty", "schema": "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"},
+			"item": [{"name": "List", "request": {"method": "GET", "url": "http://localhost/"}}]
+		}`), 0644))
+
+		cmd := NewBenchCommand()
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetArgs([]string{collPath, "-r", "Nope"})
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `no request named "Nope"`)
+	})
+
+	t.Run("rejects invalid options", func(t *testing.T) {
+		for _, args := range [][]string{
+			{server.URL, "-c", "0"},
+			{server.URL, "--rate", "-1"},
+			{server.URL, "-r", "List"},
+		} {
+			cmd := NewBenchCommand()
+			cmd.SetOut(&bytes.Buffer{})
+			cmd.SetErr(&bytes.Buffer{})
+			cmd.SetArgs(args)
+			assert.Error(t, cmd.Execute(), args)
+		}
+	})
+}
+
+func TestFormatBenchProgress(t *testing.T) {
+	line := formatBenchProgress(bench.Snapshot{
+		Elapsed:  2 * time.Second,
+		Duration: 10 * time.Second,
+		Requests: 50,
+		Failed:   2,
+	})
+	assert.Equal(t, "Running: 2s / 10s  50 requests  25.0 req/s  2 errors", line)
+}
+
+func TestFormatLatency(t *testing.T) {
+	assert.Equal(t, "500µs", formatLatency(500*time.Microsecond))
+	assert.Equal(t, "12.50ms", formatLatency(12500*time.Microsecond))
+	assert.Equal(t, "1.50s", formatLatency(1500*time.Millisecond))
+}
@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/artpar/currier/internal/storage/filesystem"
+	"github.com/spf13/cobra"
+)
+
+// NewCollectionCommand creates the collection command for managing saved
+// collections.
+func NewCollectionCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "collection",
+		Short: "Manage saved collections",
+	}
+
+	cmd.AddCommand(newCollectionConvertCommand())
+
+	return cmd
+}
+
+func newCollectionConvertCommand() *cobra.Command {
+	var layout string
+
+	cmd := &cobra.Command{
+		Use:   "convert COLLECTION",
+		Short: "Convert a saved collection to another layout",
+		Long: `Convert a saved collection, given by name or ID, between the file layout
+(one YAML file per collection) and the directory layout (a directory per
+collection mirroring its folders, with one file per request, long bodies and
+scripts in files of their own, and no timestamps), which diffs and merges
+cleanly when checked into a repository.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			target, err := filesystem.ParseLayout(layout)
+			if err != nil {
+				return err
+			}
+			store, err := initCollectionStore()
+			if err != nil {
+				return err
+			}
+			return runCollectionConvert(cmd, store, args[0], target)
+		},
+	}
+
+	cmd.Flags().StringVar(&layout, "layout", string(filesystem.LayoutDirectory), "Layout to convert to: directory or file")
+
+	return cmd
+}
+
+func runCollectionConvert(cmd *cobra.Command, store *filesystem.CollectionStore, ref string, layout filesystem.Layout) error {
+	ctx := context.Background()
+	meta, err := findSavedCollection(ctx, store, ref)
+	if err != nil {
+		return err
+	}
+
+	path, err := store.Convert(ctx, meta.ID, layout)
+	if err != nil {
+		return fmt.Errorf("failed to convert %s: %w", meta.Name, err)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Converted %s to the %s layout: %s\n", meta.Name, layout, path)
+	return nil
+}
+
+// findSavedCollection finds a saved collection by ID or case-insensitive
+// name. Workspace collections come first, so they win over personal ones.
+func findSavedCollection(ctx context.Context, store *filesystem.CollectionStore, ref string) (filesystem.CollectionMeta, error) {
+	metas, err := store.List(ctx)
+	if err != nil {
+		return filesystem.CollectionMeta{}, err
+	}
+	for _, meta := range metas {
+		if meta.ID == ref || strings.EqualFold(meta.Name, ref) {
+			return meta, nil
+		}
+	}
+	return filesystem.CollectionMeta{}, fmt.Errorf("collection not found: %s", ref)
+}
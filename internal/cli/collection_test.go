@@ -0,0 +1,40 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/artpar/currier/internal/core"
+	"github.com/artpar/currier/internal/storage/filesystem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectionConvert(t *testing.T) {
+	t.Run("converts a collection found by name", func(t *testing.T) {
+		dir := t.TempDir()
+		store, err := filesystem.NewCollectionStore(dir)
+		require.NoError(t, err)
+		c := core.NewCollection("Billing API")
+		c.AddRequest(core.NewRequestDefinition("List Invoices", "GET", "https://example.com/invoices"))
+		require.NoError(t, store.Save(context.Background(), c))
+
+		cmd := NewCollectionCommand()
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+
+		require.NoError(t, runCollectionConvert(cmd, store, "billing api", filesystem.LayoutDirectory))
+		assert.Contains(t, out.String(), "Converted Billing API to the directory layout")
+		assert.FileExists(t, filepath.Join(dir, "billing-api", "list-invoices.request.yaml"))
+	})
+
+	t.Run("returns an error for unknown collections", func(t *testing.T) {
+		store, err := filesystem.NewCollectionStore(t.TempDir())
+		require.NoError(t, err)
+
+		err = runCollectionConvert(NewCollectionCommand(), store, "missing", filesystem.LayoutDirectory)
+		assert.EqualError(t, err, "collection not found: missing")
+	})
+}
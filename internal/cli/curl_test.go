@@ -448,7 +448,10 @@ func TestCurlParsing_AdditionalFeatures(t *testing.T) {
 		requests := collection.Requests()
 		require.Len(t, requests, 1)
 		assert.Equal(t, "POST", requests[0].Method())
-		assert.Equal(t, "name=John Doe", requests[0].Body())
+		assert.Equal(t, "urlencoded", requests[0].BodyType())
+		require.Len(t, requests[0].FormFields(), 1)
+		assert.Equal(t, "name", requests[0].FormFields()[0].Key)
+		assert.Equal(t, "John Doe", requests[0].FormFields()[0].Value)
 	})
 
 	t.Run("parses real-world GitHub API example", func(t *testing.T) {
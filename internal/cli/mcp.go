@@ -48,9 +48,15 @@ Configure in Claude Code's MCP settings:
 }
 
 func runMCPServer(dataDir string) error {
+	ws, err := currentWorkspace()
+	if err != nil {
+		return err
+	}
+
 	// Create MCP server
 	server, err := mcp.NewServer(mcp.ServerConfig{
-		DataDir: dataDir,
+		DataDir:   dataDir,
+		Workspace: ws,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to create MCP server: %w", err)
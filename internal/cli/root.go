@@ -3,8 +3,10 @@ package cli
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
@@ -14,9 +16,11 @@ import (
 	"github.com/artpar/currier/internal/history/sqlite"
 	"github.com/artpar/currier/internal/importer"
 	"github.com/artpar/currier/internal/interpolate"
+	"github.com/artpar/currier/internal/secrets"
 	starredstore "github.com/artpar/currier/internal/starred/sqlite"
 	"github.com/artpar/currier/internal/storage/filesystem"
 	"github.com/artpar/currier/internal/tui/views"
+	"github.com/artpar/currier/internal/workspace"
 )
 
 // NewRootCommand creates the root command.
@@ -47,11 +51,6 @@ func NewRootCommand(version string) *cobra.Command {
 				fmt.Fprintf(os.Stderr, "Loaded environment: %s\n", env.Name())
 			}
 
-			// Merge collection variables into environment
-			if len(collections) > 0 {
-				env = core.MergeCollectionVariables(env, collections)
-			}
-
 			return runTUI(collections, env, captureMode)
 		},
 	}
@@ -68,8 +67,12 @@ func NewRootCommand(version string) *cobra.Command {
 	cmd.AddCommand(NewSendCommand())
 	cmd.AddCommand(NewCurlCommand())
 	cmd.AddCommand(NewRunCommand())
+	cmd.AddCommand(NewBenchCommand())
 	cmd.AddCommand(NewMCPCommand())
 	cmd.AddCommand(NewProxyCommand())
+	cmd.AddCommand(NewSecretsCommand())
+	cmd.AddCommand(NewCollectionCommand())
+	cmd.AddCommand(NewWorkspaceCommand())
 
 	return cmd
 }
@@ -148,6 +151,10 @@ func runTUI(collections []*core.Collection, env *core.Environment, captureMode b
 		view.SetHistoryStore(historyStore)
 	}
 
+	if ws, err := currentWorkspace(); err == nil && ws != nil {
+		view.SetWorkspace(ws.Name())
+	}
+
 	// Initialize collection store for persistence
 	collectionStore, err := initCollectionStore()
 	if err != nil {
@@ -189,7 +196,7 @@ func runTUI(collections []*core.Collection, env *core.Environment, captureMode b
 	// Set up interpolation engine with environment
 	if env != nil {
 		engine := interpolate.NewEngine()
-		engine.SetVariables(env.ExportAll())
+		engine.Scope().SetEnvironment(interpolate.NewVariableSetFrom(env.ExportAll()))
 		view.SetEnvironment(env, engine)
 	}
 
@@ -245,9 +252,23 @@ func initCollectionStore() (*filesystem.CollectionStore, error) {
 		configDir = filepath.Join(homeDir, ".config")
 	}
 
+	// Layer the collections of the current workspace over the user's
+	var opts []filesystem.CollectionOption
+	ws, err := currentWorkspace()
+	if err != nil {
+		return nil, err
+	}
+	if ws != nil {
+		layout, err := filesystem.ParseLayout(ws.CollectionLayout())
+		if err != nil {
+			return nil, fmt.Errorf("invalid workspace settings: %w", err)
+		}
+		opts = append(opts, filesystem.WithCollectionWorkspace(ws.CollectionsDir()), filesystem.WithLayout(layout))
+	}
+
 	// Create collections directory
 	collectionsDir := filepath.Join(configDir, "currier", "collections")
-	store, err := filesystem.NewCollectionStore(collectionsDir)
+	store, err := filesystem.NewCollectionStore(collectionsDir, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("could not create collection store: %w", err)
 	}
@@ -255,26 +276,103 @@ func initCollectionStore() (*filesystem.CollectionStore, error) {
 	return store, nil
 }
 
+// currentWorkspace returns the workspace of the working directory, or nil
+// if there is none.
+func currentWorkspace() (*workspace.Workspace, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, nil
+	}
+	ws, err := workspace.Find(wd)
+	if err != nil {
+		return nil, fmt.Errorf("could not load workspace: %w", err)
+	}
+	return ws, nil
+}
+
 // initEnvironmentStore creates and initializes the filesystem environment store.
 func initEnvironmentStore() (*filesystem.EnvironmentStore, error) {
+	environmentsDir, err := environmentsDir()
+	if err != nil {
+		return nil, err
+	}
+
+	// Layer the environments of the current workspace over the user's
+	var opts []filesystem.EnvironmentOption
+	ws, err := currentWorkspace()
+	if err != nil {
+		return nil, err
+	}
+	if ws != nil {
+		opts = append(opts, filesystem.WithEnvironmentWorkspace(ws.EnvironmentsDir(), ws.Settings.Environment))
+	}
+
+	// Create environments directory
+	store, err := filesystem.NewEnvironmentStore(environmentsDir, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("could not create environment store: %w", err)
+	}
+
+	// Unlock encrypted secrets without a prompt when a key is configured
+	key, err := secrets.KeyFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	if key != nil {
+		if err := store.Unlock(context.Background(), key); err != nil {
+			return nil, fmt.Errorf("could not unlock secrets: %w", err)
+		}
+	}
+
+	return store, nil
+}
+
+// environmentsDir returns the directory saved environments live in.
+func environmentsDir() (string, error) {
 	// Get user config directory
 	configDir, err := os.UserConfigDir()
 	if err != nil {
 		homeDir, err := os.UserHomeDir()
 		if err != nil {
-			return nil, fmt.Errorf("could not determine config directory: %w", err)
+			return "", fmt.Errorf("could not determine config directory: %w", err)
 		}
 		configDir = filepath.Join(homeDir, ".config")
 	}
+	return filepath.Join(configDir, "currier", "environments"), nil
+}
 
-	// Create environments directory
-	environmentsDir := filepath.Join(configDir, "currier", "environments")
-	store, err := filesystem.NewEnvironmentStore(environmentsDir)
+// loadGlobals returns the global environments saved in the data directory
+// and reports them on w. Problems loading them are warnings, not errors.
+func loadGlobals(w io.Writer) []*core.Environment {
+	dir, err := environmentsDir()
 	if err != nil {
-		return nil, fmt.Errorf("could not create environment store: %w", err)
+		return nil
+	}
+	if _, err := os.Stat(dir); err != nil {
+		if ws, _ := currentWorkspace(); ws == nil {
+			return nil // Nothing saved yet
+		}
 	}
 
-	return store, nil
+	store, err := initEnvironmentStore()
+	if err != nil {
+		fmt.Fprintf(w, "Warning: Could not load global environments: %v\n", err)
+		return nil
+	}
+	globals, err := store.Globals(context.Background())
+	if err != nil {
+		fmt.Fprintf(w, "Warning: Could not load global environments: %v\n", err)
+		return nil
+	}
+
+	if len(globals) > 0 {
+		names := make([]string, len(globals))
+		for i, env := range globals {
+			names[i] = env.Name()
+		}
+		fmt.Fprintf(w, "Using global environment(s): %s\n", strings.Join(names, ", "))
+	}
+	return globals
 }
 
 // initCookieJar creates and initializes the persistent cookie jar.
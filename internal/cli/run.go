@@ -2,21 +2,44 @@ package cli
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/artpar/currier/internal/core"
+	"github.com/artpar/currier/internal/importer"
+	"github.com/artpar/currier/internal/reporter"
 	"github.com/artpar/currier/internal/runner"
 	"github.com/artpar/currier/internal/script"
+	"github.com/artpar/currier/internal/storage/filesystem"
 	"github.com/spf13/cobra"
 )
 
+// skippedMark marks requests that were skipped in run output.
+const skippedMark = "↷"
+
 // RunOptions holds options for the run command.
 type RunOptions struct {
-	EnvFiles []string
-	Verbose  bool
-	JSON     bool
+	EnvFiles    []string
+	Verbose     bool
+	JSON        bool
+	Concurrency int
+	DataFile    string
+	Iterations  int
+	Reporters   []string
+	Seed        uint64
+
+	Snapshots       bool
+	UpdateSnapshots bool
+	SnapshotDir     string
+	SnapshotIgnore  []string
+	SnapshotHeaders []string
+
+	Contract string
 }
 
 // NewRunCommand creates the run command.
@@ -24,9 +47,46 @@ func NewRunCommand() *cobra.Command {
 	opts := &RunOptions{}
 
 	cmd := &cobra.Command{
-		Use:   "run COLLECTION_FILE",
+		Use:   "run COLLECTION",
 		Short: "Run all requests in a collection",
-		Long:  "Execute all requests in a collection file sequentially and display results.",
+		Long: `Execute all requests in a collection file and display results.
+
+COLLECTION is a collection file or directory, or the name or ID of a saved
+collection.
+Saved collections are looked up in the workspace of the current directory
+(the nearest .currier directory) first, then in your own collections.
+
+Requests run sequentially by default. Use --concurrency to run independent
+requests in parallel; folders marked "sequential: true" still run in order.
+
+Use --data with a CSV or JSON file to run the collection once per data row.
+Row values are available as {{variables}} and via currier.iterationData in
+scripts. --iterations repeats the run, cycling through data rows if needed.
+Dynamic variables such as {{$randomEmail}} or {{$randomInt 1 100}} get fresh
+values on each use; pass --seed to generate the same values on every run.
+
+Scripts can control the run: currier.execution.setNextRequest("name") jumps
+to another request (postman.setNextRequest is also supported), passing null
+or calling currier.execution.stop() ends the run, and
+currier.execution.skipRequest() in a pre-request script skips the request.
+Requests with a "skip_if" expression are skipped when it is truthy.
+
+Use --reporter to choose output formats: cli, json, junit, tap and html.
+Give a reporter an output file with name=path, e.g.
+  --reporter cli,junit=results.xml,html=report.html
+Reporters without a path write to stdout; only one may do so.
+
+Use --snapshots for golden testing: the first run records each response
+(status, selected headers and body) under __snapshots__/<collection> next to
+the collection file, and later runs fail with a diff when a response changes.
+Mask volatile JSON fields with --snapshot-ignore, e.g. '$.id' or
+'$..createdAt' ("createdAt" alone matches at any depth). Run with
+--update-snapshots to accept the new responses.
+
+Use --contract with an OpenAPI 3 spec to check every response against it:
+the operation and status code must be declared, the content type must match
+and JSON bodies must satisfy the response schema. Violations are reported as
+failing tests with JSON pointers to the offending values.`,
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runCollection(cmd, args[0], opts)
@@ -35,25 +95,46 @@ func NewRunCommand() *cobra.Command {
 
 	cmd.Flags().StringArrayVarP(&opts.EnvFiles, "env", "e", nil, "Environment file(s) for variable substitution")
 	cmd.Flags().BoolVarP(&opts.Verbose, "verbose", "v", false, "Show detailed output for each request")
-	cmd.Flags().BoolVar(&opts.JSON, "json", false, "Output results as JSON")
+	cmd.Flags().BoolVar(&opts.JSON, "json", false, "Output results as JSON (same as --reporter json)")
+	cmd.Flags().StringArrayVar(&opts.Reporters, "reporter", nil, "Reporters as name[=path], comma separated: cli, json, junit, tap, html (default cli)")
+	cmd.Flags().IntVar(&opts.Concurrency, "concurrency", 1, "Number of requests to run in parallel")
+	cmd.Flags().StringVar(&opts.DataFile, "data", "", "CSV or JSON data file; runs one iteration per row")
+	cmd.Flags().IntVarP(&opts.Iterations, "iterations", "n", 0, "Number of iterations (default: one per data row, or 1)")
+	cmd.Flags().Uint64Var(&opts.Seed, "seed", 0, "Seed for {{$random*}} dynamic variables, for reproducible runs")
+	cmd.Flags().BoolVar(&opts.Snapshots, "snapshots", false, "Compare responses with recorded snapshots, recording missing ones")
+	cmd.Flags().BoolVar(&opts.UpdateSnapshots, "update-snapshots", false, "Rewrite snapshots that differ (implies --snapshots)")
+	cmd.Flags().StringVar(&opts.SnapshotDir, "snapshot-dir", "", "Snapshot directory (default: __snapshots__/<collection> next to the collection file)")
+	cmd.Flags().StringArrayVar(&opts.SnapshotIgnore, "snapshot-ignore", nil, "JSON path to mask in snapshots, e.g. $.id or $..createdAt (repeatable)")
+	cmd.Flags().StringArrayVar(&opts.SnapshotHeaders, "snapshot-header", nil, "Response header to record in snapshots (repeatable, default Content-Type)")
+	cmd.Flags().StringVar(&opts.Contract, "contract", "", "OpenAPI spec to validate responses against (default: the collection's attached contract)")
 
 	return cmd
 }
 
 func runCollection(cmd *cobra.Command, collectionPath string, opts *RunOptions) error {
-	// Read collection file
-	data, err := os.ReadFile(collectionPath)
+	if opts.Concurrency < 1 {
+		return fmt.Errorf("--concurrency must be at least 1")
+	}
+	if opts.Iterations < 0 {
+		return fmt.Errorf("--iterations must not be negative")
+	}
+
+	// Resolve reporters up front so a typo fails before the run
+	reporters := newReporterRegistry(opts.Verbose)
+	specs, err := reporterSpecs(reporters, opts)
 	if err != nil {
-		return fmt.Errorf("failed to read collection file: %w", err)
+		return err
 	}
 
-	// Use the registry to detect format and import
-	registry := newImporterRegistry()
-	result, err := registry.DetectAndImport(context.Background(), data)
+	collection, collectionPath, err := loadRunCollection(cmd, collectionPath)
 	if err != nil {
-		return fmt.Errorf("failed to parse collection: %w", err)
+		return err
+	}
+
+	snapshots, err := snapshotStore(collectionPath, opts)
+	if err != nil {
+		return err
 	}
-	collection := result.Collection
 
 	// Load environment if provided
 	var env *core.Environment
@@ -67,16 +148,70 @@ func runCollection(cmd *cobra.Command, collectionPath string, opts *RunOptions)
 		}
 	}
 
+	// Load iteration data if provided
+	var rows []map[string]string
+	if opts.DataFile != "" {
+		rows, err = runner.LoadIterationData(opts.DataFile)
+		if err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return fmt.Errorf("data file %s has no rows", opts.DataFile)
+		}
+		fmt.Fprintf(cmd.ErrOrStderr(), "Using data file: %s (%d rows)\n", opts.DataFile, len(rows))
+	}
+
 	// Create runner with options
 	runnerOpts := []runner.Option{}
+	if globals := loadGlobals(cmd.ErrOrStderr()); len(globals) > 0 {
+		runnerOpts = append(runnerOpts, runner.WithGlobals(core.MergeGlobals(globals)))
+		for _, global := range globals {
+			runnerOpts = append(runnerOpts, runner.WithSecrets(global.SecretValues()...))
+		}
+	}
 	if env != nil {
 		runnerOpts = append(runnerOpts, runner.WithEnvironment(env))
 	}
+	runnerOpts = append(runnerOpts,
+		runner.WithConcurrency(opts.Concurrency),
+		runner.WithIterations(opts.Iterations),
+		runner.WithIterationData(rows),
+		runner.WithModuleDir(filesystem.CollectionRoot(collectionPath)),
+	)
+	if cmd.Flags().Changed("seed") {
+		runnerOpts = append(runnerOpts, runner.WithSeed(opts.Seed))
+	}
+	if snapshots != nil {
+		runnerOpts = append(runnerOpts, runner.WithSnapshots(snapshots))
+		fmt.Fprintf(cmd.ErrOrStderr(), "Using snapshots: %s\n", snapshots.Dir())
+	}
+	if path := contractPath(collectionPath, collection, opts); path != "" {
+		contract, err := importer.LoadContract(path)
+		if err != nil {
+			return fmt.Errorf("failed to load contract: %w", err)
+		}
+		runnerOpts = append(runnerOpts, runner.WithContract(contract))
+		fmt.Fprintf(cmd.ErrOrStderr(), "Using contract: %s\n", path)
+	}
 
-	// Progress callback
+	// Progress callback. Progress only goes to stdout when the cli reporter
+	// writes there, so machine-readable reports on stdout stay clean.
 	out := cmd.OutOrStdout()
+	if !writesToStdout(specs, "cli") {
+		out = cmd.ErrOrStderr()
+	}
+	multi := opts.Iterations > 1 || (opts.Iterations == 0 && len(rows) > 1)
+	lastIteration := -1
 	runnerOpts = append(runnerOpts, runner.WithProgressCallback(func(current, total int, result *runner.RunResult) {
 		if opts.Verbose {
+			if multi && result.Iteration != lastIteration {
+				fmt.Fprintf(out, "Iteration %d\n", result.Iteration+1)
+				lastIteration = result.Iteration
+			}
+			if result.Skipped {
+				fmt.Fprintf(out, "%s %s %s (skipped: %s)\n", skippedMark, result.Method, result.RequestName, result.SkipReason)
+				return
+			}
 			status := "✓"
 			if result.Error != nil {
 				status = "✗"
@@ -95,7 +230,7 @@ func runCollection(cmd *cobra.Command, collectionPath string, opts *RunOptions)
 				}
 				fmt.Fprintf(out, "%s %s\n", testStatus, tr.Name)
 				if tr.Error != "" {
-					fmt.Fprintf(out, "    Error: %s\n", tr.Error)
+					fmt.Fprintf(out, "    Error: %s\n", indentLines(tr.Error, "    "))
 				}
 			}
 		} else {
@@ -105,6 +240,10 @@ func runCollection(cmd *cobra.Command, collectionPath string, opts *RunOptions)
 
 	r := runner.NewRunner(collection, runnerOpts...)
 
+	// From here on errors describe the run, not the command line; printing
+	// usage would also corrupt reports written to stdout.
+	cmd.SilenceUsage = true
+
 	// Run collection
 	fmt.Fprintf(out, "Running collection: %s\n", collection.Name())
 	ctx := context.Background()
@@ -116,67 +255,95 @@ func runCollection(cmd *cobra.Command, collectionPath string, opts *RunOptions)
 	}
 
 	// Output results
+	if err := reporters.Write(specs, summary, cmd.OutOrStdout()); err != nil {
+		return err
+	}
+	for _, spec := range specs {
+		if spec.Path != "" {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Wrote %s report: %s\n", spec.Name, spec.Path)
+		}
+	}
+
+	return runFailureError(summary)
+}
+
+// newReporterRegistry returns the built-in reporters plus the human-readable
+// cli reporter.
+func newReporterRegistry(verbose bool) *reporter.Registry {
+	reporters := reporter.NewDefaultRegistry()
+	reporters.Register(&cliReporter{verbose: verbose})
+	return reporters
+}
+
+// reporterSpecs resolves the --reporter and --json flags. Without either,
+// the cli reporter writes to stdout.
+func reporterSpecs(reporters *reporter.Registry, opts *RunOptions) ([]reporter.Spec, error) {
+	values := opts.Reporters
 	if opts.JSON {
-		return outputRunResultsJSON(cmd, summary)
+		values = append(values, "json")
+	}
+	if len(values) == 0 {
+		values = []string{"cli"}
 	}
-	return outputRunResultsHuman(cmd, summary, opts.Verbose)
+
+	specs, err := reporter.ParseSpecs(values)
+	if err != nil {
+		return nil, err
+	}
+	if err := reporters.Validate(specs); err != nil {
+		return nil, err
+	}
+	return specs, nil
 }
 
-func outputRunResultsJSON(cmd *cobra.Command, summary *runner.RunSummary) error {
-	// Build JSON output
-	results := make([]map[string]any, 0, len(summary.Results))
-	for _, r := range summary.Results {
-		result := map[string]any{
-			"name":        r.RequestName,
-			"method":      r.Method,
-			"url":         r.URL,
-			"status":      r.Status,
-			"status_text": r.StatusText,
-			"duration_ms": r.Duration.Milliseconds(),
-		}
-		if r.Error != nil {
-			result["error"] = r.Error.Error()
-		}
-		if len(r.TestResults) > 0 {
-			tests := make([]map[string]any, 0, len(r.TestResults))
-			for _, tr := range r.TestResults {
-				test := map[string]any{
-					"name":   tr.Name,
-					"passed": tr.Passed,
-				}
-				if tr.Error != "" {
-					test["error"] = tr.Error
-				}
-				tests = append(tests, test)
-			}
-			result["tests"] = tests
+// writesToStdout reports whether the named reporter writes to stdout.
+func writesToStdout(specs []reporter.Spec, name string) bool {
+	for _, spec := range specs {
+		if spec.Name == name && spec.Path == "" {
+			return true
 		}
-		results = append(results, result)
 	}
+	return false
+}
 
-	output := map[string]any{
-		"collection":      summary.CollectionName,
-		"total_requests":  summary.TotalRequests,
-		"executed":        summary.Executed,
-		"passed":          summary.Passed,
-		"failed":          summary.Failed,
-		"total_tests":     summary.TotalTests,
-		"tests_passed":    summary.TestsPassed,
-		"tests_failed":    summary.TestsFailed,
-		"total_duration":  summary.TotalDuration.Milliseconds(),
-		"results":         results,
+// runFailureError returns an error when any request or test failed, so the
+// command exits non-zero in CI.
+func runFailureError(summary *runner.RunSummary) error {
+	if summary.Failed > 0 || summary.TestsFailed > 0 {
+		return fmt.Errorf("%d requests failed, %d tests failed", summary.Failed, summary.TestsFailed)
 	}
+	return nil
+}
 
-	return outputJSONResult(cmd, output)
+// cliReporter is the human-readable reporter used by default.
+type cliReporter struct {
+	verbose bool
 }
 
-func outputRunResultsHuman(cmd *cobra.Command, summary *runner.RunSummary, verbose bool) error {
-	out := cmd.OutOrStdout()
+func (c *cliReporter) Name() string          { return "cli" }
+func (c *cliReporter) FileExtension() string { return ".txt" }
+
+func (c *cliReporter) Report(w io.Writer, summary *runner.RunSummary) error {
+	writeRunResultsHuman(w, summary, c.verbose)
+	return nil
+}
+
+// writeRunResultsHuman writes per-request results (unless verbose, where the
+// progress output already showed them) followed by the run summary.
+func writeRunResultsHuman(out io.Writer, summary *runner.RunSummary, verbose bool) {
+	multi := len(summary.Iterations) > 1
 
 	// If not verbose, show summary of each request
 	if !verbose {
 		fmt.Fprintln(out)
-		for _, r := range summary.Results {
+		for i, r := range summary.Results {
+			if multi && (i == 0 || summary.Results[i-1].Iteration != r.Iteration) {
+				fmt.Fprintf(out, "Iteration %d/%d\n", r.Iteration+1, len(summary.Iterations))
+			}
+			if r.Skipped {
+				fmt.Fprintf(out, "%s %s %s (skipped: %s)\n", skippedMark, r.Method, r.RequestName, r.SkipReason)
+				continue
+			}
 			status := "✓"
 			if r.Error != nil {
 				status = "✗"
@@ -203,7 +370,7 @@ func outputRunResultsHuman(cmd *cobra.Command, summary *runner.RunSummary, verbo
 				if !tr.Passed {
 					fmt.Fprintf(out, "  ✗ %s\n", tr.Name)
 					if tr.Error != "" {
-						fmt.Fprintf(out, "    %s\n", tr.Error)
+						fmt.Fprintf(out, "    %s\n", indentLines(tr.Error, "    "))
 					}
 				}
 			}
@@ -213,57 +380,136 @@ func outputRunResultsHuman(cmd *cobra.Command, summary *runner.RunSummary, verbo
 	// Summary
 	fmt.Fprintln(out)
 	fmt.Fprintf(out, "Summary:\n")
+	if multi {
+		for _, it := range summary.Iterations {
+			status := "✓"
+			if !it.IsSuccess() {
+				status = "✗"
+			}
+			fmt.Fprintf(out, "  %s Iteration %d: %d/%d requests, %d/%d tests passed (%s)\n",
+				status,
+				it.Index+1,
+				it.Passed,
+				it.Executed,
+				it.TestsPassed,
+				it.TotalTests,
+				formatDuration(it.Duration))
+		}
+	}
 	fmt.Fprintf(out, "  Requests: %d/%d passed\n", summary.Passed, summary.TotalRequests)
+	if summary.Skipped > 0 {
+		fmt.Fprintf(out, "  Skipped: %d\n", summary.Skipped)
+	}
+	if summary.Stopped {
+		fmt.Fprintf(out, "  Run stopped by script\n")
+	}
 	if summary.TotalTests > 0 {
 		fmt.Fprintf(out, "  Tests: %d/%d passed\n", summary.TestsPassed, summary.TotalTests)
 	}
+	if snaps := summary.Snapshots; snaps.Total() > 0 {
+		fmt.Fprintf(out, "  Snapshots: %d matched, %d mismatched, %d recorded, %d updated\n",
+			snaps.Matched, snaps.Mismatched, snaps.Recorded, snaps.Updated)
+	}
 	fmt.Fprintf(out, "  Total time: %s\n", formatDuration(summary.TotalDuration))
+}
 
-	// Exit with error if any failures
-	if summary.Failed > 0 || summary.TestsFailed > 0 {
-		return fmt.Errorf("%d requests failed, %d tests failed", summary.Failed, summary.TestsFailed)
+// indentLines indents every line after the first, so multi-line errors such
+// as snapshot diffs line up under their test.
+func indentLines(text, indent string) string {
+	return strings.ReplaceAll(text, "\n", "\n"+indent)
+}
+
+// loadRunCollection loads the collection to run: the collection file or
+// collection directory at ref, or else the saved collection named ref, looked
+// up in the current workspace first. It returns the collection and the path
+// it was loaded from.
+func loadRunCollection(cmd *cobra.Command, ref string) (*core.Collection, string, error) {
+	ctx := context.Background()
+	if dir := filesystem.CollectionDirectory(ref); dir != "" {
+		store, err := initCollectionStore()
+		if err != nil {
+			return nil, "", err
+		}
+		collection, err := store.GetByPath(ctx, dir)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to load collection: %w", err)
+		}
+		return collection, dir, nil
 	}
 
-	return nil
+	data, err := os.ReadFile(ref)
+	if errors.Is(err, os.ErrNotExist) {
+		store, storeErr := initCollectionStore()
+		if storeErr != nil {
+			return nil, "", fmt.Errorf("failed to read collection file: %w", err)
+		}
+		meta, findErr := findSavedCollection(ctx, store, ref)
+		if findErr != nil {
+			return nil, "", fmt.Errorf("failed to read collection file: %w", err)
+		}
+		collection, err := store.Get(ctx, meta.ID)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to load collection %s: %w", meta.Name, err)
+		}
+		fmt.Fprintf(cmd.ErrOrStderr(), "Using saved collection: %s\n", meta.Path)
+		return collection, meta.Path, nil
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read collection file: %w", err)
+	}
+
+	// Use the registry to detect format and import
+	registry := newImporterRegistry()
+	result, err := registry.DetectAndImport(ctx, data)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to parse collection: %w", err)
+	}
+	return result.Collection, ref, nil
 }
 
-func formatDuration(d time.Duration) string {
-	if d < time.Second {
-		return fmt.Sprintf("%dms", d.Milliseconds())
+// snapshotStore returns the snapshot store for the run, or nil when snapshot
+// testing is off.
+func snapshotStore(collectionPath string, opts *RunOptions) (*runner.SnapshotStore, error) {
+	if !opts.Snapshots && !opts.UpdateSnapshots {
+		return nil, nil
 	}
-	return fmt.Sprintf("%.2fs", d.Seconds())
+
+	dir := opts.SnapshotDir
+	if dir == "" {
+		base := filepath.Base(collectionPath)
+		base = strings.TrimSuffix(base, filepath.Ext(base))
+		base = strings.TrimSuffix(base, ".postman_collection")
+		dir = filepath.Join(filepath.Dir(collectionPath), "__snapshots__", base)
+	}
+
+	return runner.NewSnapshotStore(runner.SnapshotConfig{
+		Dir:     dir,
+		Update:  opts.UpdateSnapshots,
+		Headers: opts.SnapshotHeaders,
+		Ignore:  opts.SnapshotIgnore,
+	})
 }
 
-func outputJSONResult(cmd *cobra.Command, result map[string]any) error {
-	// Simple JSON output
-	out := cmd.OutOrStdout()
-	fmt.Fprintf(out, "{\n")
-	first := true
-	for k, v := range result {
-		if !first {
-			fmt.Fprintf(out, ",\n")
-		}
-		first = false
-		switch val := v.(type) {
-		case string:
-			fmt.Fprintf(out, "  %q: %q", k, val)
-		case int, int64:
-			fmt.Fprintf(out, "  %q: %v", k, val)
-		case []map[string]any:
-			fmt.Fprintf(out, "  %q: %v", k, formatSlice(val))
-		default:
-			fmt.Fprintf(out, "  %q: %v", k, val)
-		}
+// contractPath returns the OpenAPI spec to check responses against: the
+// --contract flag, else the collection's attached contract resolved relative
+// to the collection file, or to the collection directory in the directory
+// layout. It returns "" when there is none.
+func contractPath(collectionPath string, collection *core.Collection, opts *RunOptions) string {
+	if opts.Contract != "" {
+		return opts.Contract
 	}
-	fmt.Fprintf(out, "\n}\n")
-	return nil
+	path := collection.Contract()
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(filesystem.CollectionRoot(collectionPath), path)
 }
 
-func formatSlice(slice []map[string]any) string {
-	if len(slice) == 0 {
-		return "[]"
+func formatDuration(d time.Duration) string {
+	if d < time.Second {
+		return fmt.Sprintf("%dms", d.Milliseconds())
 	}
-	return fmt.Sprintf("[...%d items...]", len(slice))
+	return fmt.Sprintf("%.2fs", d.Seconds())
 }
 
 // FormatTestResults formats test results for CLI output.
@@ -2,13 +2,20 @@ package cli
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/artpar/currier/internal/runner"
 	"github.com/artpar/currier/internal/script"
-	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -33,66 +40,6 @@ func TestFormatDuration(t *testing.T) {
 	})
 }
 
-func TestFormatSlice(t *testing.T) {
-	t.Run("formats empty slice", func(t *testing.T) {
-		result := formatSlice([]map[string]any{})
-		assert.Equal(t, "[]", result)
-	})
-
-	t.Run("formats slice with items", func(t *testing.T) {
-		slice := []map[string]any{
-			{"key": "value1"},
-			{"key": "value2"},
-			{"key": "value3"},
-		}
-		result := formatSlice(slice)
-		assert.Equal(t, "[...3 items...]", result)
-	})
-}
-
-func TestOutputJSONResult(t *testing.T) {
-	t.Run("outputs string values", func(t *testing.T) {
-		cmd := &cobra.Command{}
-		buf := &bytes.Buffer{}
-		cmd.SetOut(buf)
-
-		result := map[string]any{
-			"status": "success",
-		}
-		err := outputJSONResult(cmd, result)
-		assert.NoError(t, err)
-		assert.Contains(t, buf.String(), "status")
-		assert.Contains(t, buf.String(), "success")
-	})
-
-	t.Run("outputs int values", func(t *testing.T) {
-		cmd := &cobra.Command{}
-		buf := &bytes.Buffer{}
-		cmd.SetOut(buf)
-
-		result := map[string]any{
-			"count": 42,
-		}
-		err := outputJSONResult(cmd, result)
-		assert.NoError(t, err)
-		assert.Contains(t, buf.String(), "count")
-		assert.Contains(t, buf.String(), "42")
-	})
-
-	t.Run("outputs slice values", func(t *testing.T) {
-		cmd := &cobra.Command{}
-		buf := &bytes.Buffer{}
-		cmd.SetOut(buf)
-
-		result := map[string]any{
-			"items": []map[string]any{{"a": "b"}},
-		}
-		err := outputJSONResult(cmd, result)
-		assert.NoError(t, err)
-		assert.Contains(t, buf.String(), "items")
-	})
-}
-
 func TestFormatTestResults(t *testing.T) {
 	t.Run("formats test results", func(t *testing.T) {
 		results := []script.TestResult{
@@ -110,12 +57,18 @@ func TestFormatTestResults(t *testing.T) {
 	})
 }
 
-func TestOutputRunResultsJSON(t *testing.T) {
-	t.Run("outputs successful run as JSON", func(t *testing.T) {
-		cmd := &cobra.Command{}
-		buf := &bytes.Buffer{}
-		cmd.SetOut(buf)
+// reportRun writes summary with the named reporter of the run command.
+func reportRun(t *testing.T, name string, summary *runner.RunSummary) string {
+	t.Helper()
+	rep, ok := newReporterRegistry(false).Get(name)
+	require.True(t, ok, name)
+	buf := &bytes.Buffer{}
+	require.NoError(t, rep.Report(buf, summary))
+	return buf.String()
+}
 
+func TestRunReporter_JSON(t *testing.T) {
+	t.Run("outputs successful run as JSON", func(t *testing.T) {
 		summary := &runner.RunSummary{
 			CollectionName: "Test Collection",
 			TotalRequests:  2,
@@ -154,21 +107,17 @@ func TestOutputRunResultsJSON(t *testing.T) {
 			},
 		}
 
-		err := outputRunResultsJSON(cmd, summary)
-		require.NoError(t, err)
-
-		output := buf.String()
-		assert.Contains(t, output, "Test Collection")
-		assert.Contains(t, output, "total_requests")
-		// Results are truncated to [...N items...]
-		assert.Contains(t, output, "...2 items...")
+		var report map[string]any
+		require.NoError(t, json.Unmarshal([]byte(reportRun(t, "json", summary)), &report))
+		assert.Equal(t, "Test Collection", report["collection"])
+		assert.Equal(t, float64(2), report["total_requests"])
+		results := report["results"].([]any)
+		require.Len(t, results, 2)
+		assert.Equal(t, "Get Users", results[0].(map[string]any)["name"])
+		assert.Len(t, results[1].(map[string]any)["tests"], 2)
 	})
 
 	t.Run("outputs run with errors as JSON", func(t *testing.T) {
-		cmd := &cobra.Command{}
-		buf := &bytes.Buffer{}
-		cmd.SetOut(buf)
-
 		summary := &runner.RunSummary{
 			CollectionName: "Test Collection",
 			TotalRequests:  1,
@@ -187,20 +136,15 @@ func TestOutputRunResultsJSON(t *testing.T) {
 			},
 		}
 
-		err := outputRunResultsJSON(cmd, summary)
-		require.NoError(t, err)
-
-		output := buf.String()
-		// Results are truncated, check for summary fields
-		assert.Contains(t, output, "failed")
-		assert.Contains(t, output, "...1 items...")
+		var report map[string]any
+		require.NoError(t, json.Unmarshal([]byte(reportRun(t, "json", summary)), &report))
+		assert.Equal(t, float64(1), report["failed"])
+		results := report["results"].([]any)
+		require.Len(t, results, 1)
+		assert.Equal(t, "connection refused", results[0].(map[string]any)["error"])
 	})
 
 	t.Run("outputs run with failed tests as JSON", func(t *testing.T) {
-		cmd := &cobra.Command{}
-		buf := &bytes.Buffer{}
-		cmd.SetOut(buf)
-
 		summary := &runner.RunSummary{
 			CollectionName: "Test Collection",
 			TotalRequests:  1,
@@ -227,22 +171,16 @@ func TestOutputRunResultsJSON(t *testing.T) {
 			},
 		}
 
-		err := outputRunResultsJSON(cmd, summary)
-		require.NoError(t, err)
-
-		output := buf.String()
-		// Check summary fields, not inner results (which are truncated)
-		assert.Contains(t, output, "tests_failed")
-		assert.Contains(t, output, "1")
+		var report map[string]any
+		require.NoError(t, json.Unmarshal([]byte(reportRun(t, "json", summary)), &report))
+		assert.Equal(t, float64(1), report["tests_failed"])
+		tests := report["results"].([]any)[0].(map[string]any)["tests"].([]any)
+		assert.Equal(t, "expected 200, got 404", tests[1].(map[string]any)["error"])
 	})
 }
 
-func TestOutputRunResultsHuman(t *testing.T) {
+func TestRunReporter_CLI(t *testing.T) {
 	t.Run("outputs successful run in human format", func(t *testing.T) {
-		cmd := &cobra.Command{}
-		buf := &bytes.Buffer{}
-		cmd.SetOut(buf)
-
 		summary := &runner.RunSummary{
 			CollectionName: "Test Collection",
 			TotalRequests:  2,
@@ -272,10 +210,8 @@ func TestOutputRunResultsHuman(t *testing.T) {
 			},
 		}
 
-		err := outputRunResultsHuman(cmd, summary, false)
-		require.NoError(t, err)
-
-		output := buf.String()
+		output := reportRun(t, "cli", summary)
+		assert.NoError(t, runFailureError(summary))
 		assert.Contains(t, output, "✓")
 		assert.Contains(t, output, "GET")
 		assert.Contains(t, output, "Get Users")
@@ -284,10 +220,6 @@ func TestOutputRunResultsHuman(t *testing.T) {
 	})
 
 	t.Run("outputs failed run in human format", func(t *testing.T) {
-		cmd := &cobra.Command{}
-		buf := &bytes.Buffer{}
-		cmd.SetOut(buf)
-
 		summary := &runner.RunSummary{
 			CollectionName: "Test Collection",
 			TotalRequests:  1,
@@ -305,19 +237,12 @@ func TestOutputRunResultsHuman(t *testing.T) {
 			},
 		}
 
-		err := outputRunResultsHuman(cmd, summary, false)
-		require.Error(t, err)
-		assert.Contains(t, err.Error(), "1 requests failed")
-
-		output := buf.String()
+		output := reportRun(t, "cli", summary)
+		assert.ErrorContains(t, runFailureError(summary), "1 requests failed")
 		assert.Contains(t, output, "✗")
 	})
 
 	t.Run("shows failed test details", func(t *testing.T) {
-		cmd := &cobra.Command{}
-		buf := &bytes.Buffer{}
-		cmd.SetOut(buf)
-
 		summary := &runner.RunSummary{
 			CollectionName: "Test Collection",
 			TotalRequests:  1,
@@ -342,19 +267,13 @@ func TestOutputRunResultsHuman(t *testing.T) {
 			},
 		}
 
-		err := outputRunResultsHuman(cmd, summary, false)
-		require.Error(t, err)
-
-		output := buf.String()
+		output := reportRun(t, "cli", summary)
+		assert.Error(t, runFailureError(summary))
 		assert.Contains(t, output, "Status is 200")
 		assert.Contains(t, output, "expected 200, got 404")
 	})
 
 	t.Run("shows no test info when no tests", func(t *testing.T) {
-		cmd := &cobra.Command{}
-		buf := &bytes.Buffer{}
-		cmd.SetOut(buf)
-
 		summary := &runner.RunSummary{
 			CollectionName: "Test Collection",
 			TotalRequests:  1,
@@ -373,10 +292,438 @@ func TestOutputRunResultsHuman(t *testing.T) {
 			},
 		}
 
-		err := outputRunResultsHuman(cmd, summary, false)
+		output := reportRun(t, "cli", summary)
+		assert.NoError(t, runFailureError(summary))
+		assert.NotContains(t, output, "Tests:")
+	})
+}
+
+func TestRunCommand_Concurrency(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(10 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	collection := fmt.Sprintf(`{
+		"info": {
+			"name": "Parallel",
+			"schema": "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+		},
+		"item": [
+			{"name": "First", "request": {"method": "GET", "url": "%[1]s/1"}},
+			{"name": "Second", "request": {"method": "GET", "url": "%[1]s/2"}},
+			{"name": "Third", "request": {"method": "GET", "url": "%[1]s/3"}}
+		]
+	}`, server.URL)
+	path := filepath.Join(t.TempDir(), "collection.json")
+	require.NoError(t, os.WriteFile(path, []byte(collection), 0644))
+
+	t.Run("runs with concurrency and keeps order", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		cmd := NewRunCommand()
+		cmd.SetOut(out)
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetArgs([]string{path, "--concurrency", "3"})
+
+		require.NoError(t, cmd.Execute())
+
+		output := out.String()
+		assert.Contains(t, output, "Requests: 3/3 passed")
+		first := bytes.Index(out.Bytes(), []byte("GET First"))
+		third := bytes.Index(out.Bytes(), []byte("GET Third"))
+		assert.True(t, first >= 0 && third > first, "expected results in collection order:\n%s", output)
+	})
+
+	t.Run("rejects invalid concurrency", func(t *testing.T) {
+		cmd := NewRunCommand()
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetArgs([]string{path, "--concurrency", "0"})
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "--concurrency")
+	})
+}
+
+func TestRunCommand_DataFile(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/users/bob" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	collection := fmt.Sprintf(`{
+		"info": {
+			"name": "Data",
+			"schema": "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+		},
+		"item": [
+			{
+				"name": "Get User",
+				"request": {"method": "GET", "url": "%s/users/{{user}}"},
+				"event": [{"listen": "test", "script": {"exec": [
+					"pm.test('found ' + pm.iterationData.get('user'), function() { pm.expect(pm.response.status).toBe(200); });"
+				]}}]
+			}
+		]
+	}`, server.URL)
+	collPath := filepath.Join(dir, "collection.json")
+	require.NoError(t, os.WriteFile(collPath, []byte(collection), 0644))
+	dataPath := filepath.Join(dir, "users.csv")
+	require.NoError(t, os.WriteFile(dataPath, []byte("user\nalice\nbob\n"), 0644))
+
+	t.Run("runs one iteration per row", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		cmd := NewRunCommand()
+		cmd.SetOut(out)
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetArgs([]string{collPath, "--data", dataPath})
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "1 tests failed")
+
+		output := out.String()
+		assert.Contains(t, output, "Iteration 1/2")
+		assert.Contains(t, output, "Iteration 2/2")
+		assert.Contains(t, output, "✓ Iteration 1: 1/1 requests, 1/1 tests passed")
+		assert.Contains(t, output, "✗ Iteration 2: 1/1 requests, 0/1 tests passed")
+		assert.Contains(t, output, "found bob")
+	})
+
+	t.Run("iterations override row count", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		cmd := NewRunCommand()
+		cmd.SetOut(out)
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetArgs([]string{collPath, "--data", dataPath, "--iterations", "1"})
+
+		require.NoError(t, cmd.Execute())
+		assert.Contains(t, out.String(), "Requests: 1/1 passed")
+		assert.NotContains(t, out.String(), "Iteration")
+	})
+
+	t.Run("rejects missing data file", func(t *testing.T) {
+		cmd := NewRunCommand()
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetArgs([]string{collPath, "--data", filepath.Join(dir, "missing.csv")})
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "data file")
+	})
+}
+
+func TestRunCommand_FlowControl(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	collection := fmt.Sprintf(`{
+		"info": {
+			"name": "Flow",
+			"schema": "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+		},
+		"item": [
+			{
+				"name": "Skipped",
+				"request": {"method": "GET", "url": "%[1]s/skipped"},
+				"event": [{"listen": "prerequest", "script": {"exec": ["pm.execution.skipRequest();"]}}]
+			},
+			{
+				"name": "Last",
+				"request": {"method": "GET", "url": "%[1]s/last"},
+				"event": [{"listen": "test", "script": {"exec": ["postman.setNextRequest(null);"]}}]
+			}
+		]
+	}`, server.URL)
+	path := filepath.Join(t.TempDir(), "collection.json")
+	require.NoError(t, os.WriteFile(path, []byte(collection), 0644))
+
+	out := &bytes.Buffer{}
+	cmd := NewRunCommand()
+	cmd.SetOut(out)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{path, "--iterations", "2"})
+
+	require.NoError(t, cmd.Execute())
+
+	output := out.String()
+	assert.Contains(t, output, "↷ GET Skipped (skipped: skipped by pre-request script)")
+	assert.Contains(t, output, "Skipped: 1")
+	assert.Contains(t, output, "Run stopped by script")
+	assert.Contains(t, output, "Requests: 1/4 passed")
+}
+
+func TestRunCommand_Reporters(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	collection := fmt.Sprintf(`{
+		"info": {
+			"name": "Reports",
+			"schema": "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+		},
+		"item": [
+			{
+				"name": "Health",
+				"request": {"method": "GET", "url": "%s/health"},
+				"event": [{"listen": "test", "script": {"exec": [
+					"pm.test('status is 200', function() { pm.expect(pm.response.status).toBe(200); });",
+					"pm.test('status is 201', function() { pm.expect(pm.response.status).toBe(201); });"
+				]}}]
+			}
+		]
+	}`, server.URL)
+	collPath := filepath.Join(dir, "collection.json")
+	require.NoError(t, os.WriteFile(collPath, []byte(collection), 0644))
+
+	t.Run("writes file reports and cli output", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		errOut := &bytes.Buffer{}
+		junitPath := filepath.Join(dir, "reports", "junit.xml")
+		htmlPath := filepath.Join(dir, "reports", "report.html")
+		cmd := NewRunCommand()
+		cmd.SetOut(out)
+		cmd.SetErr(errOut)
+		cmd.SetArgs([]string{collPath, "--reporter", "cli,junit=" + junitPath, "--reporter", "html=" + htmlPath})
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "1 tests failed")
+
+		assert.Contains(t, out.String(), "Requests: 1/1 passed")
+		assert.Contains(t, errOut.String(), "Wrote junit report: "+junitPath)
+
+		junit, err := os.ReadFile(junitPath)
 		require.NoError(t, err)
+		assert.Contains(t, string(junit), `<testcase name="GET Health" classname="Reports"`)
+		assert.Contains(t, string(junit), `message="status is 201: Expected 200 to be 201"`)
 
-		output := buf.String()
-		assert.NotContains(t, output, "Tests:")
+		html, err := os.ReadFile(htmlPath)
+		require.NoError(t, err)
+		assert.Contains(t, string(html), "status is 201")
+		assert.Contains(t, string(html), "{&#34;ok&#34;:true}")
+	})
+
+	t.Run("masks secrets in report files", func(t *testing.T) {
+		secretColl := fmt.Sprintf(`{
+			"info": {
+				"name": "Secret",
+				"schema": "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+			},
+			"item": [{"name": "Health", "request": {
+				"method": "GET",
+				"url": "%s/health?key={{apiKey}}",
+				"header": [{"key": "Authorization", "value": "Bearer {{apiKey}}"}]
+			}}]
+		}`, server.URL)
+		secretPath := filepath.Join(dir, "secret.json")
+		require.NoError(t, os.WriteFile(secretPath, []byte(secretColl), 0644))
+		envPath := filepath.Join(dir, "env.json")
+		require.NoError(t, os.WriteFile(envPath, []byte(`{"name": "ci", "secrets": {"apiKey": "s3cr3t-api-key"}}`), 0644))
+		jsonPath := filepath.Join(dir, "reports", "secret.json")
+
+		cmd := NewRunCommand()
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetArgs([]string{secretPath, "-e", envPath, "--reporter", "json=" + jsonPath})
+		require.NoError(t, cmd.Execute())
+
+		report, err := os.ReadFile(jsonPath)
+		require.NoError(t, err)
+		assert.Contains(t, string(report), "Bearer ********")
+		assert.Contains(t, string(report), "key=********")
+		assert.NotContains(t, string(report), "s3cr3t-api-key")
+	})
+
+	t.Run("tap on stdout keeps progress off stdout", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		errOut := &bytes.Buffer{}
+		cmd := NewRunCommand()
+		cmd.SetOut(out)
+		cmd.SetErr(errOut)
+		cmd.SetArgs([]string{collPath, "--reporter", "tap"})
+
+		require.Error(t, cmd.Execute())
+		assert.True(t, strings.HasPrefix(out.String(), "TAP version 13\n"), out.String())
+		assert.Contains(t, errOut.String(), "Running collection: Reports")
+	})
+
+	t.Run("json flag writes parseable JSON", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		cmd := NewRunCommand()
+		cmd.SetOut(out)
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetArgs([]string{collPath, "--json"})
+
+		require.Error(t, cmd.Execute())
+		var report map[string]any
+		require.NoError(t, json.Unmarshal(out.Bytes(), &report))
+		assert.Equal(t, "Reports", report["collection"])
+	})
+
+	t.Run("rejects unknown reporter", func(t *testing.T) {
+		cmd := NewRunCommand()
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetArgs([]string{collPath, "--reporter", "xml"})
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `unknown reporter "xml"`)
+	})
+
+	t.Run("rejects two reporters on stdout", func(t *testing.T) {
+		cmd := NewRunCommand()
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetArgs([]string{collPath, "--reporter", "cli,junit"})
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "both write to stdout")
+	})
+}
+
+func TestRunCommand_Snapshots(t *testing.T) {
+	var version atomic.Int32
+	version.Store(1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"version":%d,"requestId":"%d"}`, version.Load(), time.Now().UnixNano())
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	collection := fmt.Sprintf(`{
+		"info": {
+			"name": "Golden",
+			"schema": "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+		},
+		"item": [{"name": "Status", "request": {"method": "GET", "url": "%s/status"}}]
+	}`, server.URL)
+	collPath := filepath.Join(dir, "golden.postman_collection.json")
+	require.NoError(t, os.WriteFile(collPath, []byte(collection), 0644))
+	snapPath := filepath.Join(dir, "__snapshots__", "golden", "Status.snap")
+
+	run := func(args ...string) (string, error) {
+		out := &bytes.Buffer{}
+		cmd := NewRunCommand()
+		cmd.SetOut(out)
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetArgs(append([]string{collPath, "--snapshot-ignore", "requestId"}, args...))
+		err := cmd.Execute()
+		return out.String(), err
+	}
+
+	t.Run("records next to the collection", func(t *testing.T) {
+		output, err := run("--snapshots")
+		require.NoError(t, err)
+		assert.Contains(t, output, "Snapshots: 0 matched, 0 mismatched, 1 recorded, 0 updated")
+
+		saved, err := os.ReadFile(snapPath)
+		require.NoError(t, err)
+		assert.Contains(t, string(saved), `"requestId": "<ignored>"`)
+	})
+
+	t.Run("fails with a diff when the response changes", func(t *testing.T) {
+		version.Store(2)
+		output, err := run("--snapshots")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "1 tests failed")
+		assert.Contains(t, output, "✗ Response matches snapshot")
+		assert.Contains(t, output, "\n    -  \"version\": 1")
+		assert.Contains(t, output, "\n    +  \"version\": 2")
+	})
+
+	t.Run("updates snapshots", func(t *testing.T) {
+		output, err := run("--update-snapshots")
+		require.NoError(t, err)
+		assert.Contains(t, output, "1 updated")
+
+		_, err = run("--snapshots")
+		require.NoError(t, err)
+	})
+
+	t.Run("rejects invalid ignore paths", func(t *testing.T) {
+		_, err := run("--snapshots", "--snapshot-ignore", "$.items[")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid snapshot ignore path")
+	})
+}
+
+func TestRunCommand_Contract(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": "7"}`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	collPath := filepath.Join(dir, "pets.postman_collection.json")
+	require.NoError(t, os.WriteFile(collPath, []byte(fmt.Sprintf(`{
+		"info": {
+			"name": "Pets",
+			"schema": "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+		},
+		"item": [{"name": "Get Pet", "request": {"method": "GET", "url": "%s/pets/7"}}]
+	}`, server.URL)), 0644))
+	specPath := filepath.Join(dir, "openapi.yaml")
+	require.NoError(t, os.WriteFile(specPath, []byte(`
+openapi: 3.0.3
+info: {title: Pets, version: "1"}
+paths:
+  /pets/{id}:
+    get:
+      responses:
+        "200":
+          description: A pet
+          content:
+            application/json:
+              schema:
+                type: object
+                required: [id]
+                properties:
+                  id: {type: integer}
+`), 0644))
+
+	t.Run("reports violations as failed tests", func(t *testing.T) {
+		out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+		cmd := NewRunCommand()
+		cmd.SetOut(out)
+		cmd.SetErr(errOut)
+		cmd.SetArgs([]string{collPath, "--contract", specPath})
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "1 tests failed")
+		assert.Contains(t, errOut.String(), "Using contract: "+specPath)
+		assert.Contains(t, out.String(), "2/3 tests")
+		assert.Contains(t, out.String(), "✗ Contract: Body matches schema")
+		assert.Contains(t, out.String(), "/id: expected integer, got string")
+	})
+
+	t.Run("fails on an unreadable contract", func(t *testing.T) {
+		cmd := NewRunCommand()
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetArgs([]string{collPath, "--contract", filepath.Join(dir, "missing.yaml")})
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to load contract")
 	})
 }
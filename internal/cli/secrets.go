@@ -0,0 +1,124 @@
+package cli
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"os"
+
+	"github.com/artpar/currier/internal/core"
+	"github.com/artpar/currier/internal/secrets"
+	"github.com/spf13/cobra"
+)
+
+// NewSecretsCommand creates the secrets command for managing encrypted
+// environment secrets.
+func NewSecretsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "secrets",
+		Short: "Manage encrypted environment secrets",
+		Long: `Environment secrets are encrypted at rest with a key derived from a
+passphrase or a keyfile. Set ` + secrets.PassphraseEnv + ` or
+` + secrets.KeyFileEnv + ` to unlock them without a prompt, as in CI; the TUI
+asks for the passphrase once per session otherwise.`,
+	}
+
+	cmd.AddCommand(newSecretsEncryptCommand())
+	cmd.AddCommand(newSecretsKeygenCommand())
+
+	return cmd
+}
+
+func newSecretsEncryptCommand() *cobra.Command {
+	var keyFile string
+
+	cmd := &cobra.Command{
+		Use:   "encrypt [ENV_FILE...]",
+		Short: "Encrypt plaintext environment secrets",
+		Long: `Encrypt the "secrets" of environment files in the simple JSON format,
+replacing them with a "secrets_encrypted" section that is safe to commit.
+Without files, encrypts the secrets of the saved environments.
+
+The key comes from --keyfile, ` + secrets.KeyFileEnv + ` or
+` + secrets.PassphraseEnv + `.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSecretsEncrypt(cmd, args, keyFile)
+		},
+	}
+
+	cmd.Flags().StringVar(&keyFile, "keyfile", "", "Keyfile to encrypt with")
+
+	return cmd
+}
+
+func runSecretsEncrypt(cmd *cobra.Command, paths []string, keyFile string) error {
+	var key *secrets.Key
+	var err error
+	if keyFile != "" {
+		key, err = secrets.LoadKeyFile(keyFile)
+	} else {
+		key, err = secrets.KeyFromEnv()
+	}
+	if err != nil {
+		return err
+	}
+	if key == nil {
+		return fmt.Errorf("no key: pass --keyfile or set %s or %s", secrets.KeyFileEnv, secrets.PassphraseEnv)
+	}
+
+	if len(paths) == 0 {
+		store, err := initEnvironmentStore()
+		if err != nil {
+			return err
+		}
+		ctx := context.Background()
+		if err := store.Unlock(ctx, key); err != nil {
+			return fmt.Errorf("could not unlock secrets: %w", err)
+		}
+		count, err := store.EncryptAll(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Encrypted secrets of %d environment(s)\n", count)
+		return nil
+	}
+
+	for _, path := range paths {
+		encrypted, err := core.EncryptEnvironmentFile(path, key)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt %s: %w", path, err)
+		}
+		if encrypted {
+			fmt.Fprintf(cmd.OutOrStdout(), "Encrypted secrets of %s\n", path)
+		} else {
+			fmt.Fprintf(cmd.OutOrStdout(), "No secrets in %s\n", path)
+		}
+	}
+	return nil
+}
+
+func newSecretsKeygenCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "keygen KEYFILE",
+		Short: "Generate a random keyfile",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key := make([]byte, 32)
+			if _, err := rand.Read(key); err != nil {
+				return fmt.Errorf("failed to generate key: %w", err)
+			}
+			// Never overwrite a keyfile, which would lose its secrets
+			f, err := os.OpenFile(args[0], os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+			if err != nil {
+				return fmt.Errorf("failed to create keyfile: %w", err)
+			}
+			defer f.Close()
+			if _, err := f.WriteString(base64.StdEncoding.EncodeToString(key) + "\n"); err != nil {
+				return fmt.Errorf("failed to write keyfile: %w", err)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Wrote keyfile %s\n", args[0])
+			return nil
+		},
+	}
+}
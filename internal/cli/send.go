@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"os"
+	"os/signal"
 	"strings"
 	"time"
 
@@ -26,6 +28,9 @@ type SendOptions struct {
 	KeyFile            string
 	CAFile             string
 	InsecureSkipVerify bool
+	Output             string
+	PreviewLimit       int64
+	Seed               uint64
 }
 
 // NewSendCommand creates the send command.
@@ -49,6 +54,7 @@ func NewSendCommand() *cobra.Command {
 	cmd.Flags().BoolVar(&opts.JSON, "json", false, "Output response as JSON")
 	cmd.Flags().DurationVar(&opts.Timeout, "timeout", 30*time.Second, "Request timeout")
 	cmd.Flags().StringArrayVarP(&opts.EnvFiles, "env", "e", nil, "Environment file(s) for variable substitution")
+	cmd.Flags().Uint64Var(&opts.Seed, "seed", 0, "Seed for {{$random*}} dynamic variables, for reproducible values")
 
 	// Proxy settings
 	cmd.Flags().StringVar(&opts.ProxyURL, "proxy", "", "Proxy URL (http://, https://, or socks5://)")
@@ -59,12 +65,24 @@ func NewSendCommand() *cobra.Command {
 	cmd.Flags().StringVar(&opts.CAFile, "cacert", "", "Custom CA certificate PEM file")
 	cmd.Flags().BoolVarP(&opts.InsecureSkipVerify, "insecure", "k", false, "Skip server certificate verification")
 
+	// Streaming download settings
+	cmd.Flags().StringVarP(&opts.Output, "output", "o", "", "Stream the response body to a file instead of printing it")
+	cmd.Flags().Int64Var(&opts.PreviewLimit, "preview-limit", httpclient.DefaultPreviewLimit, "Max bytes of a streamed body to keep for output")
+
 	return cmd
 }
 
 func runSend(cmd *cobra.Command, method, url string, opts *SendOptions) error {
 	// Create interpolation engine
 	engine := interpolate.NewEngine()
+	if cmd.Flags().Changed("seed") {
+		engine.SetSeed(opts.Seed)
+	}
+
+	// Global environments resolve beneath the environment files
+	if globals := loadGlobals(cmd.ErrOrStderr()); len(globals) > 0 {
+		engine.Scope().SetGlobal(interpolate.NewVariableSetFrom(core.MergeGlobals(globals)))
+	}
 
 	// Load environment files if provided
 	if len(opts.EnvFiles) > 0 {
@@ -73,7 +91,7 @@ func runSend(cmd *cobra.Command, method, url string, opts *SendOptions) error {
 			return fmt.Errorf("failed to load environment: %w", err)
 		}
 		if env != nil {
-			engine.SetVariables(env.ExportAll())
+			engine.Scope().SetEnvironment(interpolate.NewVariableSetFrom(env.ExportAll()))
 			fmt.Fprintf(cmd.ErrOrStderr(), "Loaded environment: %s\n", env.Name())
 		}
 	}
@@ -109,9 +127,11 @@ func runSend(cmd *cobra.Command, method, url string, opts *SendOptions) error {
 		clientOpts = append(clientOpts, httpclient.WithInsecureSkipVerify())
 	}
 
+	client := httpclient.NewClient(clientOpts...)
+
 	// Create the app with HTTP protocol
 	application := app.New(
-		app.WithProtocol("http", httpclient.NewClient(clientOpts...)),
+		app.WithProtocol("http", client),
 	)
 
 	// Create request with interpolated URL
@@ -143,6 +163,11 @@ func runSend(cmd *cobra.Command, method, url string, opts *SendOptions) error {
 		req.SetBody(core.NewRawBody([]byte(interpolatedBody), contentType))
 	}
 
+	// Stream to disk when an output file is given
+	if opts.Output != "" {
+		return runDownload(cmd, client, req, opts)
+	}
+
 	// Send request
 	ctx := context.Background()
 	resp, err := application.Send(ctx, req)
@@ -157,6 +182,47 @@ func runSend(cmd *cobra.Command, method, url string, opts *SendOptions) error {
 	return outputHuman(cmd, resp)
 }
 
+// runDownload streams the response body to opts.Output, reporting progress on stderr.
+// Interrupting the transfer leaves the partial body in opts.Output+".part".
+func runDownload(cmd *cobra.Command, client *httpclient.Client, req *core.Request, opts *SendOptions) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	errOut := cmd.ErrOrStderr()
+	resp, err := client.Download(ctx, req, httpclient.DownloadOptions{
+		Path:         opts.Output,
+		PreviewLimit: opts.PreviewLimit,
+		OnProgress: func(p httpclient.DownloadProgress) {
+			fmt.Fprintf(errOut, "\r%s", formatProgress(p))
+			if p.Done {
+				fmt.Fprintln(errOut)
+			}
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("download failed: %w", err)
+	}
+
+	if opts.JSON {
+		return outputJSON(cmd, resp)
+	}
+
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "HTTP %d %s\n", resp.Status().Code(), resp.Status().Text())
+	fmt.Fprintf(out, "Time: %dms\n", resp.Timing().Total.Milliseconds())
+	fmt.Fprintf(out, "Saved: %s (%d bytes)\n", resp.DownloadPath(), resp.DownloadSize())
+	return nil
+}
+
+// formatProgress renders a download progress line.
+func formatProgress(p httpclient.DownloadProgress) string {
+	line := fmt.Sprintf("Downloaded %d bytes", p.Written)
+	if p.Total > 0 {
+		line = fmt.Sprintf("Downloaded %d/%d bytes (%.0f%%)", p.Written, p.Total, p.Percent())
+	}
+	return fmt.Sprintf("%s at %.1f KB/s", line, p.BytesPerSecond()/1024)
+}
+
 func outputJSON(cmd *cobra.Command, resp *core.Response) error {
 	result := map[string]any{
 		"status":      resp.Status().Code(),
@@ -165,6 +231,11 @@ func outputJSON(cmd *cobra.Command, resp *core.Response) error {
 		"body":        resp.Body().String(),
 		"timing_ms":   resp.Timing().Total.Milliseconds(),
 	}
+	if path := resp.DownloadPath(); path != "" {
+		result["download_path"] = path
+		result["download_size"] = resp.DownloadSize()
+		result["body_truncated"] = resp.IsBodyTruncated()
+	}
 
 	encoder := json.NewEncoder(cmd.OutOrStdout())
 	encoder.SetIndent("", "  ")
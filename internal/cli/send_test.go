@@ -6,6 +6,8 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -234,3 +236,28 @@ func TestSendCommandAdvanced(t *testing.T) {
 		require.NoError(t, err)
 	})
 }
+
+func TestSendCommand_Output(t *testing.T) {
+	t.Run("streams body to output file", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("file contents"))
+		}))
+		defer server.Close()
+
+		dest := filepath.Join(t.TempDir(), "body.txt")
+		out := &bytes.Buffer{}
+		errOut := &bytes.Buffer{}
+		cmd := NewSendCommand()
+		cmd.SetOut(out)
+		cmd.SetErr(errOut)
+		cmd.SetArgs([]string{"GET", server.URL, "--output", dest})
+
+		require.NoError(t, cmd.Execute())
+
+		data, err := os.ReadFile(dest)
+		require.NoError(t, err)
+		assert.Equal(t, "file contents", string(data))
+		assert.Contains(t, out.String(), "Saved: "+dest)
+		assert.Contains(t, errOut.String(), "Downloaded 13/13 bytes")
+	})
+}
@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/artpar/currier/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+// NewWorkspaceCommand creates the workspace command for managing project
+// workspaces.
+func NewWorkspaceCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "workspace",
+		Short: "Manage project workspaces",
+		Long: `A workspace is a .currier directory in a project that holds the project's
+collections, environments and settings, so they can be checked in with the
+code. Currier finds it by walking up from the current directory and shows
+its collections and environments alongside your own.`,
+	}
+
+	cmd.AddCommand(newWorkspaceInitCommand())
+
+	return cmd
+}
+
+func newWorkspaceInitCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "init [DIR]",
+		Short: "Create a workspace in a project directory",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir := "."
+			if len(args) > 0 {
+				dir = args[0]
+			}
+			return runWorkspaceInit(cmd, dir)
+		},
+	}
+}
+
+func runWorkspaceInit(cmd *cobra.Command, dir string) error {
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return fmt.Errorf("not a directory: %s", dir)
+	}
+	ws, err := workspace.Init(dir)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Initialized workspace %s in %s\n", ws.Name(), ws.Dir)
+	return nil
+}
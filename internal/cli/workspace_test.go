@@ -0,0 +1,168 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/artpar/currier/internal/core"
+	"github.com/artpar/currier/internal/storage/filesystem"
+	"github.com/artpar/currier/internal/workspace"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkspaceInit(t *testing.T) {
+	t.Run("creates a workspace in the directory", func(t *testing.T) {
+		dir := t.TempDir()
+		cmd := NewWorkspaceCommand()
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetArgs([]string{"init", dir})
+
+		require.NoError(t, cmd.Execute())
+		assert.Contains(t, out.String(), "Initialized workspace")
+		assert.DirExists(t, filepath.Join(dir, ".currier", "collections"))
+		assert.DirExists(t, filepath.Join(dir, ".currier", "environments"))
+	})
+
+	t.Run("rejects a missing directory", func(t *testing.T) {
+		err := runWorkspaceInit(NewWorkspaceCommand(), filepath.Join(t.TempDir(), "missing"))
+		assert.ErrorContains(t, err, "not a directory")
+	})
+}
+
+func TestRunCommand_SavedCollection(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	// Keep the user's own config out of the test
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	project := t.TempDir()
+	ws, err := workspace.Init(project)
+	require.NoError(t, err)
+	store, err := filesystem.NewCollectionStore(ws.CollectionsDir())
+	require.NoError(t, err)
+	c := core.NewCollection("Smoke Tests")
+	c.AddRequest(core.NewRequestDefinition("Health", "GET", server.URL+"/health"))
+	require.NoError(t, store.Save(context.Background(), c))
+	dirStore, err := filesystem.NewCollectionStore(ws.CollectionsDir(), filesystem.WithLayout(filesystem.LayoutDirectory))
+	require.NoError(t, err)
+	d := core.NewCollection("Team API")
+	d.AddRequest(core.NewRequestDefinition("Health", "GET", server.URL+"/health"))
+	require.NoError(t, dirStore.Save(context.Background(), d))
+
+	t.Chdir(project)
+
+	t.Run("runs a workspace collection by name", func(t *testing.T) {
+		out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+		cmd := NewRunCommand()
+		cmd.SetOut(out)
+		cmd.SetErr(errOut)
+		cmd.SetArgs([]string{"smoke tests"})
+
+		require.NoError(t, cmd.Execute())
+		assert.Contains(t, out.String(), "Requests: 1/1 passed")
+		assert.Contains(t, errOut.String(), "Using saved collection")
+	})
+
+	t.Run("runs a collection directory by path", func(t *testing.T) {
+		for _, path := range []string{
+			filepath.Join(".currier", "collections", "team-api"),
+			filepath.Join(".currier", "collections", "team-api", "_collection.yaml"),
+		} {
+			out := &bytes.Buffer{}
+			cmd := NewRunCommand()
+			cmd.SetOut(out)
+			cmd.SetErr(&bytes.Buffer{})
+			cmd.SetArgs([]string{path})
+
+			require.NoError(t, cmd.Execute(), path)
+			assert.Contains(t, out.String(), "Requests: 1/1 passed", path)
+		}
+	})
+
+	t.Run("reports unknown collections", func(t *testing.T) {
+		cmd := NewRunCommand()
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetArgs([]string{"missing"})
+
+		err := cmd.Execute()
+		assert.ErrorContains(t, err, "failed to read collection file")
+	})
+}
+
+func TestRunCommand_DirectoryLayout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	dir := t.TempDir()
+	store, err := filesystem.NewCollectionStore(dir, filesystem.WithLayout(filesystem.LayoutDirectory))
+	require.NoError(t, err)
+
+	t.Run("requires modules from the collection directory", func(t *testing.T) {
+		req := core.NewRequestDefinition("Health", "GET", server.URL+"/health")
+		req.SetPostScript(`
+			var helpers = require('./lib/helpers');
+			currier.test("helper loads", function() {
+				currier.expect(helpers.ok).toBe(true);
+			});
+		`)
+		c := core.NewCollection("Modules")
+		c.AddRequest(req)
+		require.NoError(t, store.Save(context.Background(), c))
+		collDir := filepath.Join(dir, "modules")
+		require.NoError(t, os.MkdirAll(filepath.Join(collDir, "lib"), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(collDir, "lib", "helpers.js"), []byte("exports.ok = true;"), 0644))
+
+		out := &bytes.Buffer{}
+		cmd := NewRunCommand()
+		cmd.SetOut(out)
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetArgs([]string{collDir})
+
+		require.NoError(t, cmd.Execute())
+		assert.Contains(t, out.String(), "Tests: 1/1 passed")
+	})
+	t.Run("resolves the contract against the collection directory", func(t *testing.T) {
+		c := core.NewCollection("Contracts")
+		c.AddRequest(core.NewRequestDefinition("Health", "GET", server.URL+"/health"))
+		c.SetContract("openapi.yaml")
+		require.NoError(t, store.Save(context.Background(), c))
+		collDir := filepath.Join(dir, "contracts")
+		spec := `
+openapi: 3.0.3
+info: {title: Health, version: "1"}
+paths:
+  /health:
+    get:
+      responses:
+        "200": {description: Healthy}
+`
+		require.NoError(t, os.WriteFile(filepath.Join(collDir, "openapi.yaml"), []byte(spec), 0644))
+
+		out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+		cmd := NewRunCommand()
+		cmd.SetOut(out)
+		cmd.SetErr(errOut)
+		cmd.SetArgs([]string{collDir})
+
+		require.NoError(t, cmd.Execute())
+		assert.Contains(t, errOut.String(), "Using contract: "+filepath.Join(collDir, "openapi.yaml"))
+		assert.Contains(t, out.String(), "Requests: 1/1 passed")
+	})
+}
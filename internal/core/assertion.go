@@ -0,0 +1,49 @@
+package core
+
+// AssertionSource is the part of a response an assertion or extraction
+// reads from.
+type AssertionSource string
+
+const (
+	AssertionSourceStatus       AssertionSource = "status"
+	AssertionSourceHeader       AssertionSource = "header"
+	AssertionSourceJSONPath     AssertionSource = "jsonpath"
+	AssertionSourceXPath        AssertionSource = "xpath"
+	AssertionSourceResponseTime AssertionSource = "response_time"
+	AssertionSourceBody         AssertionSource = "body"
+	AssertionSourceSchema       AssertionSource = "schema"
+	AssertionSourceRegex        AssertionSource = "regex" // Extractions only
+)
+
+// AssertionOperator is the comparison an assertion applies.
+type AssertionOperator string
+
+const (
+	AssertionEquals   AssertionOperator = "equals"
+	AssertionIn       AssertionOperator = "in"
+	AssertionMatches  AssertionOperator = "matches"
+	AssertionContains AssertionOperator = "contains"
+	AssertionExists   AssertionOperator = "exists"
+	AssertionType     AssertionOperator = "type"
+	AssertionBelow    AssertionOperator = "below"
+	AssertionValid    AssertionOperator = "valid"
+)
+
+// Assertion is a declarative check on a response, evaluated without
+// scripts. Property names the header, JSONPath or XPath the source needs;
+// Value is the operand, which may contain {{variables}}.
+type Assertion struct {
+	Source   AssertionSource
+	Property string
+	Operator AssertionOperator
+	Value    string
+}
+
+// Extraction captures a value from a response into a variable. Expression
+// is a JSONPath, a header name or a regular expression, whose first capture
+// group is used when it has one.
+type Extraction struct {
+	Variable   string
+	Source     AssertionSource
+	Expression string
+}
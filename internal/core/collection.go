@@ -2,6 +2,7 @@ package core
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/url"
 	"strings"
 	"time"
@@ -23,6 +24,7 @@ type Collection struct {
 	auth        AuthConfig
 	preScript   string
 	postScript  string
+	contract    string // Path of an OpenAPI spec responses are validated against
 	createdAt   time.Time
 	updatedAt   time.Time
 }
@@ -68,6 +70,7 @@ func (c *Collection) UpdatedAt() time.Time { return c.updatedAt }
 func (c *Collection) Auth() AuthConfig    { return c.auth }
 func (c *Collection) PreScript() string   { return c.preScript }
 func (c *Collection) PostScript() string  { return c.postScript }
+func (c *Collection) Contract() string    { return c.contract }
 
 func (c *Collection) SetDescription(desc string) {
 	c.description = desc
@@ -99,6 +102,13 @@ func (c *Collection) SetPostScript(script string) {
 	c.touch()
 }
 
+// SetContract attaches an OpenAPI spec file that responses to the
+// collection's requests are validated against. An empty path detaches it.
+func (c *Collection) SetContract(path string) {
+	c.contract = path
+	c.touch()
+}
+
 func (c *Collection) touch() {
 	c.updatedAt = time.Now()
 }
@@ -203,6 +213,43 @@ func (c *Collection) FindFolder(id string) *Folder {
 	return nil
 }
 
+// FolderPath returns the folders enclosing the request with the given ID,
+// outermost first. It is empty for root-level and unknown requests.
+func (c *Collection) FolderPath(requestID string) []*Folder {
+	for _, f := range c.folders {
+		if path := f.pathTo(requestID); path != nil {
+			return path
+		}
+	}
+	return nil
+}
+
+// FolderVariables returns the variables of the folders enclosing the request
+// with the given ID, inner folders overriding outer ones.
+func (c *Collection) FolderVariables(requestID string) map[string]string {
+	result := make(map[string]string)
+	for _, f := range c.FolderPath(requestID) {
+		for k, v := range f.variables {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+// pathTo returns the folders from f down to the one holding the request, or
+// nil if the request isn't in f.
+func (f *Folder) pathTo(requestID string) []*Folder {
+	if _, ok := f.GetRequest(requestID); ok {
+		return []*Folder{f}
+	}
+	for _, sf := range f.folders {
+		if path := sf.pathTo(requestID); path != nil {
+			return append([]*Folder{f}, path...)
+		}
+	}
+	return nil
+}
+
 // Requests returns all root-level requests.
 func (c *Collection) Requests() []*RequestDefinition {
 	return c.requests
@@ -392,6 +439,7 @@ func (c *Collection) Clone() *Collection {
 	clone.auth = c.auth
 	clone.preScript = c.preScript
 	clone.postScript = c.postScript
+	clone.contract = c.contract
 
 	for k, v := range c.variables {
 		clone.variables[k] = v
@@ -417,6 +465,8 @@ type Folder struct {
 	id          string
 	name        string
 	description string
+	sequential  bool
+	variables   map[string]string
 	folders     []*Folder
 	requests    []*RequestDefinition
 }
@@ -455,6 +505,45 @@ func (f *Folder) SetDescription(desc string) {
 	f.description = desc
 }
 
+// Sequential reports whether the folder's requests must run in order, one at
+// a time, even when the collection runner is running requests in parallel.
+func (f *Folder) Sequential() bool {
+	return f.sequential
+}
+
+// SetSequential marks the folder as an ordered flow for the collection runner.
+func (f *Folder) SetSequential(sequential bool) {
+	f.sequential = sequential
+}
+
+// Variables returns the folder's variables. They apply to the requests in the
+// folder and its subfolders, shadowing collection variables.
+func (f *Folder) Variables() map[string]string {
+	result := make(map[string]string, len(f.variables))
+	for k, v := range f.variables {
+		result[k] = v
+	}
+	return result
+}
+
+// GetVariable returns a folder variable value.
+func (f *Folder) GetVariable(key string) string {
+	return f.variables[key]
+}
+
+// SetVariable sets a folder variable.
+func (f *Folder) SetVariable(key, value string) {
+	if f.variables == nil {
+		f.variables = make(map[string]string)
+	}
+	f.variables[key] = value
+}
+
+// DeleteVariable removes a folder variable.
+func (f *Folder) DeleteVariable(key string) {
+	delete(f.variables, key)
+}
+
 func (f *Folder) AddFolder(name string) *Folder {
 	folder := NewFolder(name)
 	f.folders = append(f.folders, folder)
@@ -610,6 +699,10 @@ func (f *Folder) FindFolder(id string) *Folder {
 func (f *Folder) Clone() *Folder {
 	clone := NewFolder(f.name)
 	clone.description = f.description
+	clone.sequential = f.sequential
+	for k, v := range f.variables {
+		clone.SetVariable(k, v)
+	}
 
 	for _, folder := range f.folders {
 		clone.folders = append(clone.folders, folder.Clone())
@@ -624,19 +717,23 @@ func (f *Folder) Clone() *Folder {
 
 // RequestDefinition represents a saved request definition.
 type RequestDefinition struct {
-	id          string
-	name        string
-	description string
-	method      string
-	url         string
-	headers     map[string]string
-	queryParams map[string]string
-	bodyType    string
-	bodyContent string
-	formFields  []FormField // For form-data body type
-	auth        *AuthConfig
-	preScript   string
-	postScript  string
+	id              string
+	name            string
+	description     string
+	method          string
+	url             string
+	headers         map[string]string
+	queryParams     map[string]string
+	bodyType        string
+	bodyContent     string
+	bodyContentType string      // Content type for raw and binary bodies
+	formFields      []FormField // For form-data and urlencoded body types
+	auth            *AuthConfig
+	preScript       string
+	postScript      string
+	skipCondition   string // Script expression; the runner skips the request when truthy
+	assertions      []Assertion
+	extractions     []Extraction
 }
 
 // NewRequestDefinition creates a new request definition.
@@ -715,6 +812,40 @@ func (r *RequestDefinition) SetBodyJSON(data any) error {
 func (r *RequestDefinition) SetBodyRaw(content, contentType string) {
 	r.bodyType = "raw"
 	r.bodyContent = content
+	r.bodyContentType = contentType
+}
+
+// BodyContentType returns the content type for raw and binary bodies.
+func (r *RequestDefinition) BodyContentType() string {
+	return r.bodyContentType
+}
+
+// SetBodyContentType sets the content type sent with raw and binary bodies.
+func (r *RequestDefinition) SetBodyContentType(contentType string) {
+	r.bodyContentType = contentType
+}
+
+// SetBodyURLEncoded sets the body type to application/x-www-form-urlencoded with the given fields.
+func (r *RequestDefinition) SetBodyURLEncoded(fields []FormField) {
+	r.bodyType = "urlencoded"
+	r.formFields = fields
+	r.bodyContent = ""
+}
+
+// SetBodyBinary sets the body to the contents of the file at filePath, streamed at send time.
+// An empty contentType is guessed from the file extension.
+func (r *RequestDefinition) SetBodyBinary(filePath, contentType string) {
+	r.bodyType = "binary"
+	r.bodyContent = filePath
+	r.bodyContentType = contentType
+}
+
+// BodyFile returns the file path for the binary body type.
+func (r *RequestDefinition) BodyFile() string {
+	if r.bodyType != "binary" {
+		return ""
+	}
+	return r.bodyContent
 }
 
 // SetBodyFormData sets the body type to form-data with the given fields.
@@ -746,7 +877,7 @@ func (r *RequestDefinition) AddFormFile(key, filePath string) {
 	})
 }
 
-// SetBodyType sets the body type (raw, json, form).
+// SetBodyType sets the body type (raw, json, form, urlencoded, binary).
 func (r *RequestDefinition) SetBodyType(bodyType string) {
 	r.bodyType = bodyType
 }
@@ -759,6 +890,36 @@ func (r *RequestDefinition) SetPostScript(script string) {
 	r.postScript = script
 }
 
+// SkipCondition returns the script expression that makes collection runs
+// skip this request when it evaluates to a truthy value.
+func (r *RequestDefinition) SkipCondition() string { return r.skipCondition }
+
+// SetSkipCondition sets the skip condition expression. An empty expression
+// never skips.
+func (r *RequestDefinition) SetSkipCondition(expr string) {
+	r.skipCondition = expr
+}
+
+// Assertions returns the request's declarative response checks.
+func (r *RequestDefinition) Assertions() []Assertion {
+	return r.assertions
+}
+
+// SetAssertions replaces the request's declarative response checks.
+func (r *RequestDefinition) SetAssertions(assertions []Assertion) {
+	r.assertions = append([]Assertion(nil), assertions...)
+}
+
+// Extractions returns the values captured from responses into variables.
+func (r *RequestDefinition) Extractions() []Extraction {
+	return r.extractions
+}
+
+// SetExtractions replaces the values captured from responses into variables.
+func (r *RequestDefinition) SetExtractions(extractions []Extraction) {
+	r.extractions = append([]Extraction(nil), extractions...)
+}
+
 func (r *RequestDefinition) SetAuth(auth AuthConfig) {
 	r.auth = &auth
 }
@@ -808,13 +969,33 @@ func (r *RequestDefinition) ToRequest() (*Request, error) {
 			req.SetBody(body)
 			req.SetHeader("Content-Type", body.ContentType())
 		}
+	case "urlencoded":
+		if len(r.formFields) > 0 {
+			body := NewURLEncodedBody(r.formFields)
+			req.SetBody(body)
+			setContentTypeIfMissing(req, body.ContentType())
+		}
+	case "binary":
+		if r.bodyContent != "" {
+			body, err := NewFileBody(r.bodyContent, r.bodyContentType)
+			if err != nil {
+				return nil, fmt.Errorf("failed to open body file: %w", err)
+			}
+			req.SetBody(body)
+			setContentTypeIfMissing(req, body.ContentType())
+		}
 	case "json":
 		if r.bodyContent != "" {
 			req.SetBody(NewRawBody([]byte(r.bodyContent), "application/json"))
 		}
 	case "raw":
 		if r.bodyContent != "" {
-			req.SetBody(NewRawBody([]byte(r.bodyContent), "text/plain"))
+			if contentType := r.rawContentType(); contentType != "" {
+				req.SetBody(NewRawBody([]byte(r.bodyContent), contentType))
+				setContentTypeIfMissing(req, contentType)
+			} else {
+				req.SetBody(NewRawBody([]byte(r.bodyContent), "text/plain"))
+			}
 		}
 	default:
 		if r.bodyContent != "" {
@@ -912,6 +1093,35 @@ func (r *RequestDefinition) ToRequestWithEnv(engine *interpolate.Engine) (*Reque
 			req.SetBody(body)
 			req.SetHeader("Content-Type", body.ContentType())
 		}
+	case "urlencoded":
+		if len(r.formFields) > 0 {
+			interpolatedFields := make([]FormField, len(r.formFields))
+			for i, field := range r.formFields {
+				interpolatedFields[i] = field
+				if interpolatedFields[i].Key, err = engine.Interpolate(field.Key); err != nil {
+					return nil, err
+				}
+				if interpolatedFields[i].Value, err = engine.Interpolate(field.Value); err != nil {
+					return nil, err
+				}
+			}
+			body := NewURLEncodedBody(interpolatedFields)
+			req.SetBody(body)
+			setContentTypeIfMissing(req, body.ContentType())
+		}
+	case "binary":
+		if r.bodyContent != "" {
+			filePath, err := engine.Interpolate(r.bodyContent)
+			if err != nil {
+				return nil, err
+			}
+			body, err := NewFileBody(filePath, r.bodyContentType)
+			if err != nil {
+				return nil, fmt.Errorf("failed to open body file: %w", err)
+			}
+			req.SetBody(body)
+			setContentTypeIfMissing(req, body.ContentType())
+		}
 	case "json":
 		if r.bodyContent != "" {
 			interpolatedBody, err := engine.Interpolate(r.bodyContent)
@@ -926,7 +1136,12 @@ func (r *RequestDefinition) ToRequestWithEnv(engine *interpolate.Engine) (*Reque
 			if err != nil {
 				return nil, err
 			}
-			req.SetBody(NewRawBody([]byte(interpolatedBody), "text/plain"))
+			if contentType := r.rawContentType(); contentType != "" {
+				req.SetBody(NewRawBody([]byte(interpolatedBody), contentType))
+				setContentTypeIfMissing(req, contentType)
+			} else {
+				req.SetBody(NewRawBody([]byte(interpolatedBody), "text/plain"))
+			}
 		}
 	default:
 		if r.bodyContent != "" {
@@ -941,13 +1156,33 @@ func (r *RequestDefinition) ToRequestWithEnv(engine *interpolate.Engine) (*Reque
 	return req, nil
 }
 
+// rawContentType returns the explicit content type for a raw body, or "" if none is set.
+func (r *RequestDefinition) rawContentType() string {
+	// Older callers passed a body type name ("raw", "json") instead of a MIME type
+	if strings.Contains(r.bodyContentType, "/") {
+		return r.bodyContentType
+	}
+	return ""
+}
+
+// setContentTypeIfMissing sets the Content-Type header unless the user already set one.
+func setContentTypeIfMissing(req *Request, contentType string) {
+	if req.Headers().Get("Content-Type") == "" {
+		req.SetHeader("Content-Type", contentType)
+	}
+}
+
 func (r *RequestDefinition) Clone() *RequestDefinition {
 	clone := NewRequestDefinition(r.name, r.method, r.url)
 	clone.description = r.description
 	clone.bodyType = r.bodyType
 	clone.bodyContent = r.bodyContent
+	clone.bodyContentType = r.bodyContentType
 	clone.preScript = r.preScript
 	clone.postScript = r.postScript
+	clone.skipCondition = r.skipCondition
+	clone.SetAssertions(r.assertions)
+	clone.SetExtractions(r.extractions)
 
 	for k, v := range r.headers {
 		clone.headers[k] = v
@@ -1,6 +1,8 @@
 package core
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -381,11 +383,13 @@ func TestCollection_Clone(t *testing.T) {
 		original := NewCollection("Original")
 		original.SetDescription("Original description")
 		original.SetVariable("key", "value")
+		original.SetContract("openapi.yaml")
 		folder := original.AddFolder("Folder1")
 		req := NewRequestDefinition("Req1", "GET", "/test")
 		folder.AddRequest(req)
 
 		clone := original.Clone()
+		assert.Equal(t, "openapi.yaml", clone.Contract())
 
 		// Verify it's a copy
 		assert.NotEqual(t, original.ID(), clone.ID())
@@ -484,6 +488,9 @@ func TestRequestDefinition_Clone(t *testing.T) {
 		original.SetAuth(AuthConfig{Type: "bearer", Token: "token"})
 		original.SetPreScript("pre")
 		original.SetPostScript("post")
+		original.SetSkipCondition("currier.iterationData.get('skip') === 'yes'")
+		original.SetAssertions([]Assertion{{Source: AssertionSourceStatus, Operator: AssertionEquals, Value: "200"}})
+		original.SetExtractions([]Extraction{{Variable: "id", Source: AssertionSourceJSONPath, Expression: "$.id"}})
 
 		clone := original.Clone()
 
@@ -496,10 +503,15 @@ func TestRequestDefinition_Clone(t *testing.T) {
 		assert.Equal(t, original.Body(), clone.Body())
 		assert.Equal(t, original.PreScript(), clone.PreScript())
 		assert.Equal(t, original.PostScript(), clone.PostScript())
+		assert.Equal(t, original.SkipCondition(), clone.SkipCondition())
+		assert.Equal(t, original.Assertions(), clone.Assertions())
+		assert.Equal(t, original.Extractions(), clone.Extractions())
 
 		// Verify modifications don't affect original
 		clone.SetDescription("Modified")
 		assert.Equal(t, "Test description", original.Description())
+		clone.Assertions()[0].Value = "404"
+		assert.Equal(t, "200", original.Assertions()[0].Value)
 	})
 }
 
@@ -517,6 +529,57 @@ func TestFolder_Clone(t *testing.T) {
 		assert.Equal(t, original.Description(), clone.Description())
 		assert.Len(t, clone.Folders(), 1)
 	})
+
+	t.Run("clones sequential marker", func(t *testing.T) {
+		original := NewFolder("Flow")
+		assert.False(t, original.Sequential())
+		original.SetSequential(true)
+
+		assert.True(t, original.Clone().Sequential())
+	})
+
+	t.Run("clones variables", func(t *testing.T) {
+		original := NewFolder("Admin")
+		original.SetVariable("role", "admin")
+
+		clone := original.Clone()
+		clone.SetVariable("role", "viewer")
+
+		assert.Equal(t, "admin", original.GetVariable("role"))
+		assert.Equal(t, "viewer", clone.GetVariable("role"))
+	})
+}
+
+func TestCollection_FolderVariables(t *testing.T) {
+	c := NewCollection("API")
+	root := NewRequestDefinition("Root", "GET", "/")
+	c.AddRequest(root)
+	outer := c.AddFolder("Admin")
+	outer.SetVariable("role", "admin")
+	outer.SetVariable("path", "/admin")
+	inner := outer.AddFolder("Users")
+	inner.SetVariable("path", "/admin/users")
+	req := NewRequestDefinition("List", "GET", "{{path}}")
+	inner.AddRequest(req)
+
+	t.Run("returns enclosing folders outermost first", func(t *testing.T) {
+		path := c.FolderPath(req.ID())
+		require.Len(t, path, 2)
+		assert.Equal(t, outer, path[0])
+		assert.Equal(t, inner, path[1])
+		assert.Empty(t, c.FolderPath(root.ID()))
+		assert.Empty(t, c.FolderPath("unknown"))
+	})
+
+	t.Run("inner folders override outer ones", func(t *testing.T) {
+		assert.Equal(t, map[string]string{"role": "admin", "path": "/admin/users"}, c.FolderVariables(req.ID()))
+		assert.Empty(t, c.FolderVariables(root.ID()))
+	})
+
+	t.Run("DeleteVariable removes a variable", func(t *testing.T) {
+		inner.DeleteVariable("path")
+		assert.Equal(t, "/admin", c.FolderVariables(req.ID())["path"])
+	})
 }
 
 func TestFolder_GetRequest(t *testing.T) {
@@ -2037,3 +2100,82 @@ func TestRequestDefinition_QueryParamCoverage(t *testing.T) {
 	})
 }
 
+
+func TestRequestDefinition_ToRequestWithBodyTypes(t *testing.T) {
+	t.Run("urlencoded body", func(t *testing.T) {
+		def := NewRequestDefinition("Login", "POST", "https://example.com/login")
+		def.SetBodyURLEncoded([]FormField{{Key: "user", Value: "john"}})
+
+		req, err := def.ToRequest()
+		require.NoError(t, err)
+		assert.Equal(t, "application/x-www-form-urlencoded", req.Headers().Get("Content-Type"))
+		assert.Equal(t, "user=john", req.Body().String())
+	})
+
+	t.Run("urlencoded body interpolates fields", func(t *testing.T) {
+		engine := interpolate.NewEngine()
+		engine.SetVariable("name", "john doe")
+
+		def := NewRequestDefinition("Login", "POST", "https://example.com/login")
+		def.SetBodyURLEncoded([]FormField{{Key: "user", Value: "{{name}}"}})
+
+		req, err := def.ToRequestWithEnv(engine)
+		require.NoError(t, err)
+		assert.Equal(t, "user=john+doe", req.Body().String())
+	})
+
+	t.Run("binary body", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "data.xml")
+		require.NoError(t, os.WriteFile(path, []byte("<a/>"), 0644))
+
+		engine := interpolate.NewEngine()
+		engine.SetVariable("file", path)
+
+		def := NewRequestDefinition("Upload", "PUT", "https://example.com/blob")
+		def.SetBodyBinary("{{file}}", "")
+		assert.Equal(t, "{{file}}", def.BodyFile())
+
+		req, err := def.ToRequestWithEnv(engine)
+		require.NoError(t, err)
+		assert.Equal(t, "binary", req.Body().Type())
+		assert.Contains(t, req.Headers().Get("Content-Type"), "xml")
+	})
+
+	t.Run("binary body with missing file", func(t *testing.T) {
+		def := NewRequestDefinition("Upload", "PUT", "https://example.com/blob")
+		def.SetBodyBinary("/does/not/exist", "")
+
+		_, err := def.ToRequest()
+		assert.Error(t, err)
+	})
+
+	t.Run("raw body with content type", func(t *testing.T) {
+		def := NewRequestDefinition("XML", "POST", "https://example.com")
+		def.SetBodyRaw("<a/>", "application/xml")
+
+		req, err := def.ToRequest()
+		require.NoError(t, err)
+		assert.Equal(t, "application/xml", req.Headers().Get("Content-Type"))
+		assert.Equal(t, "application/xml", req.Body().ContentType())
+	})
+
+	t.Run("raw body keeps user header", func(t *testing.T) {
+		def := NewRequestDefinition("XML", "POST", "https://example.com")
+		def.SetHeader("Content-Type", "text/xml")
+		def.SetBodyRaw("<a/>", "application/xml")
+
+		req, err := def.ToRequest()
+		require.NoError(t, err)
+		assert.Equal(t, "text/xml", req.Headers().Get("Content-Type"))
+	})
+
+	t.Run("raw body without content type", func(t *testing.T) {
+		def := NewRequestDefinition("Text", "POST", "https://example.com")
+		def.SetBodyRaw("hello", "")
+
+		req, err := def.ToRequest()
+		require.NoError(t, err)
+		assert.Empty(t, req.Headers().Get("Content-Type"))
+		assert.Equal(t, "text/plain", req.Body().ContentType())
+	})
+}
@@ -0,0 +1,159 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// dotenvKey matches the keys of a .env file.
+var dotenvKey = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.-]*$`)
+
+// LoadEnvironmentFromDotenv loads an environment from the contents of a
+// .env file. Each line is KEY=value, optionally prefixed with export, and #
+// starts a comment. Single-quoted values are taken literally; double-quoted
+// values may span lines and understand \n, \t, \" and \\. In unquoted and
+// double-quoted values, ${VAR} and ${VAR:-default} expand to a key defined
+// earlier in the file or to a process environment variable.
+func LoadEnvironmentFromDotenv(name string, data []byte) (*Environment, error) {
+	env := NewEnvironment(name)
+	values := make(map[string]string)
+	lookup := func(key string) (string, bool) {
+		if v, ok := values[key]; ok {
+			return v, true
+		}
+		return os.LookupEnv(key)
+	}
+
+	src := strings.ReplaceAll(string(data), "\r\n", "\n")
+	line := 1
+	for len(src) > 0 {
+		var text string
+		text, src, _ = strings.Cut(src, "\n")
+		start := line
+		line++
+
+		text = strings.TrimSpace(text)
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		text = strings.TrimPrefix(text, "export ")
+
+		key, rest, ok := strings.Cut(text, "=")
+		key = strings.TrimSpace(key)
+		if !ok || !dotenvKey.MatchString(key) {
+			return nil, fmt.Errorf("line %d: expected KEY=value", start)
+		}
+		rest = strings.TrimLeft(rest, " \t")
+
+		var value, after string
+		switch {
+		case strings.HasPrefix(rest, "'"):
+			end := strings.Index(rest[1:], "'")
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated quoted value", start)
+			}
+			value, after = rest[1:1+end], rest[2+end:]
+		case strings.HasPrefix(rest, `"`):
+			// A double-quoted value continues on the following lines until
+			// its closing quote
+			quoted := rest[1:]
+			for {
+				if end := closingQuote(quoted); end >= 0 {
+					value, after = unescapeDotenv(quoted[:end]), quoted[end+1:]
+					break
+				}
+				if src == "" {
+					return nil, fmt.Errorf("line %d: unterminated quoted value", start)
+				}
+				var next string
+				next, src, _ = strings.Cut(src, "\n")
+				quoted += "\n" + next
+				line++
+			}
+			value = expandDotenv(value, lookup)
+		default:
+			if i := strings.Index(rest, " #"); i >= 0 {
+				rest = rest[:i]
+			}
+			value = expandDotenv(strings.TrimSpace(rest), lookup)
+		}
+
+		if after = strings.TrimSpace(after); after != "" && !strings.HasPrefix(after, "#") {
+			return nil, fmt.Errorf("line %d: unexpected text after quoted value", start)
+		}
+
+		values[key] = value
+		env.SetVariable(key, value)
+	}
+
+	return env, nil
+}
+
+// closingQuote returns the index of the first unescaped double quote in s,
+// or -1.
+func closingQuote(s string) int {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
+
+// unescapeDotenv replaces the escapes of a double-quoted value.
+func unescapeDotenv(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n':
+			b.WriteByte('\n')
+		case 't':
+			b.WriteByte('\t')
+		case 'r':
+			b.WriteByte('\r')
+		case '"', '\\':
+			b.WriteByte(s[i])
+		default:
+			b.WriteByte('\\')
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+// expandDotenv replaces ${VAR} and ${VAR:-default} in s. A bare $VAR is left
+// alone, so {{$guid}} and other dynamic variables survive.
+func expandDotenv(s string, lookup func(string) (string, bool)) string {
+	var b strings.Builder
+	for {
+		start := strings.Index(s, "${")
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(s[start:], '}')
+		if end < 0 {
+			break
+		}
+		b.WriteString(s[:start])
+
+		name, fallback, hasFallback := strings.Cut(s[start+2:start+end], ":-")
+		if value, ok := lookup(name); ok && (value != "" || !hasFallback) {
+			b.WriteString(value)
+		} else {
+			b.WriteString(fallback)
+		}
+		s = s[start+end+1:]
+	}
+	b.WriteString(s)
+	return b.String()
+}
@@ -0,0 +1,68 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadEnvironmentFromDotenv(t *testing.T) {
+	t.Run("parses keys, quoting and comments", func(t *testing.T) {
+		data := []byte(`# API settings
+export HOST=api.example.com
+PORT = 8080   # inline comment
+PATH_PART=a#b
+SINGLE='literal ${HOST} \n'
+DOUBLE="say \"hi\"\tthere"
+EMPTY=
+ID={{$guid}}
+`)
+		env, err := LoadEnvironmentFromDotenv("dev", data)
+		require.NoError(t, err)
+		assert.Equal(t, "dev", env.Name())
+		assert.Equal(t, map[string]string{
+			"HOST":      "api.example.com",
+			"PORT":      "8080",
+			"PATH_PART": "a#b",
+			"SINGLE":    `literal ${HOST} \n`,
+			"DOUBLE":    "say \"hi\"\tthere",
+			"EMPTY":     "",
+			"ID":        "{{$guid}}",
+		}, env.Variables())
+	})
+
+	t.Run("expands earlier keys and process variables", func(t *testing.T) {
+		t.Setenv("CURRIER_TEST_REGION", "eu")
+		data := []byte(`HOST=api.example.com
+URL=https://${HOST}/v1
+REGIONAL="${CURRIER_TEST_REGION}.${HOST}"
+FALLBACK=${CURRIER_TEST_MISSING:-none}
+MISSING=${CURRIER_TEST_MISSING}
+`)
+		env, err := LoadEnvironmentFromDotenv("dev", data)
+		require.NoError(t, err)
+		assert.Equal(t, "https://api.example.com/v1", env.GetVariable("URL"))
+		assert.Equal(t, "eu.api.example.com", env.GetVariable("REGIONAL"))
+		assert.Equal(t, "none", env.GetVariable("FALLBACK"))
+		assert.Equal(t, "", env.GetVariable("MISSING"))
+	})
+
+	t.Run("reads multi-line double-quoted values", func(t *testing.T) {
+		env, err := LoadEnvironmentFromDotenv("dev", []byte("KEY=\"line one\nline two\"\nNEXT=1\n"))
+		require.NoError(t, err)
+		assert.Equal(t, "line one\nline two", env.GetVariable("KEY"))
+		assert.Equal(t, "1", env.GetVariable("NEXT"))
+	})
+
+	t.Run("reports malformed lines", func(t *testing.T) {
+		_, err := LoadEnvironmentFromDotenv("dev", []byte("OK=1\nnot a pair\n"))
+		assert.EqualError(t, err, "line 2: expected KEY=value")
+
+		_, err = LoadEnvironmentFromDotenv("dev", []byte("KEY=\"open\nstill open\n"))
+		assert.EqualError(t, err, "line 1: unterminated quoted value")
+
+		_, err = LoadEnvironmentFromDotenv("dev", []byte("KEY='a' b\n"))
+		assert.EqualError(t, err, "line 1: unexpected text after quoted value")
+	})
+}
@@ -1,8 +1,11 @@
 package core
 
 import (
+	"fmt"
+	"strings"
 	"time"
 
+	"github.com/artpar/currier/internal/secrets"
 	"github.com/google/uuid"
 )
 
@@ -15,6 +18,8 @@ type Environment struct {
 	secrets     map[string]string
 	isActive    bool
 	isGlobal    bool
+	extends     string       // Name of the environment this one inherits from
+	base        *Environment // The resolved extends environment, not persisted
 	createdAt   time.Time
 	updatedAt   time.Time
 }
@@ -39,6 +44,52 @@ func (e *Environment) CreatedAt() time.Time { return e.createdAt }
 func (e *Environment) UpdatedAt() time.Time { return e.updatedAt }
 func (e *Environment) IsActive() bool      { return e.isActive }
 func (e *Environment) IsGlobal() bool      { return e.isGlobal }
+func (e *Environment) Extends() string     { return e.extends }
+func (e *Environment) Base() *Environment  { return e.base }
+
+// SetExtends names the environment this one inherits variables and secrets
+// from. The name is resolved with ResolveBase.
+func (e *Environment) SetExtends(name string) {
+	e.extends = name
+	e.base = nil
+	e.touch()
+}
+
+// SetBase sets the resolved environment this one extends.
+func (e *Environment) SetBase(base *Environment) {
+	e.base = base
+}
+
+// ResolveBase sets the base of env, and of each environment it extends in
+// turn, to the environment lookup returns for its Extends name.
+func ResolveBase(env *Environment, lookup func(name string) (*Environment, error)) error {
+	chain := []string{env.name}
+	for current := env; current.extends != ""; current = current.base {
+		for _, name := range chain[1:] {
+			if name == current.extends {
+				return fmt.Errorf("circular environment inheritance: %s -> %s", strings.Join(chain, " -> "), current.extends)
+			}
+		}
+		chain = append(chain, current.extends)
+
+		base, err := lookup(current.extends)
+		if err != nil {
+			return fmt.Errorf("environment %s extends %s: %w", current.name, current.extends, err)
+		}
+		current.base = base
+	}
+	return nil
+}
+
+// Chain returns the names of the environment and those it inherits from,
+// nearest first.
+func (e *Environment) Chain() []string {
+	var names []string
+	for env := e; env != nil; env = env.base {
+		names = append(names, env.name)
+	}
+	return names
+}
 
 func (e *Environment) SetDescription(desc string) {
 	e.description = desc
@@ -117,12 +168,45 @@ func (e *Environment) SecretNames() []string {
 	return names
 }
 
+// SecretValues returns the values of the environment's secrets, including
+// inherited ones.
+func (e *Environment) SecretValues() []string {
+	var values []string
+	for env := e; env != nil; env = env.base {
+		for _, v := range env.secrets {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// Redactor returns a redactor masking the environment's secret values and
+// the values {{$secret "..."}} references have resolved to.
+func (e *Environment) Redactor() *secrets.Redactor {
+	return secrets.NewRedactor(append(secrets.ResolvedValues(), e.SecretValues()...)...)
+}
+
+// MergeGlobals combines the variables and secrets of global environments,
+// later environments overriding earlier ones. The result is layered beneath
+// the active environment.
+func MergeGlobals(globals []*Environment) map[string]string {
+	result := make(map[string]string)
+	for _, env := range globals {
+		for k, v := range env.ExportAll() {
+			result[k] = v
+		}
+	}
+	return result
+}
+
 // Clone creates a deep copy of the environment.
 func (e *Environment) Clone() *Environment {
 	clone := NewEnvironment(e.name)
 	clone.description = e.description
 	clone.isActive = e.isActive
 	clone.isGlobal = e.isGlobal
+	clone.extends = e.extends
+	clone.base = e.base
 
 	for k, v := range e.variables {
 		clone.variables[k] = v
@@ -135,9 +219,12 @@ func (e *Environment) Clone() *Environment {
 	return clone
 }
 
-// Merge merges variables and secrets from another environment.
-// Values from the other environment take precedence.
+// Merge merges variables and secrets from another environment, including
+// those it inherits. Values from the other environment take precedence.
 func (e *Environment) Merge(other *Environment) {
+	if other.base != nil {
+		e.Merge(other.base)
+	}
 	for k, v := range other.variables {
 		e.variables[k] = v
 	}
@@ -149,9 +236,13 @@ func (e *Environment) Merge(other *Environment) {
 	e.touch()
 }
 
-// ExportAll returns all variables and secrets combined for interpolation.
+// ExportAll returns all variables and secrets combined for interpolation,
+// over those inherited from the base environment.
 func (e *Environment) ExportAll() map[string]string {
 	result := make(map[string]string)
+	if e.base != nil {
+		result = e.base.ExportAll()
+	}
 	for k, v := range e.variables {
 		result[k] = v
 	}
@@ -161,9 +252,13 @@ func (e *Environment) ExportAll() map[string]string {
 	return result
 }
 
-// ExportVariablesOnly returns only variables (no secrets).
+// ExportVariablesOnly returns only variables (no secrets), including
+// inherited ones.
 func (e *Environment) ExportVariablesOnly() map[string]string {
 	result := make(map[string]string)
+	if e.base != nil {
+		result = e.base.ExportVariablesOnly()
+	}
 	for k, v := range e.variables {
 		result[k] = v
 	}
@@ -4,7 +4,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
+
+	"github.com/artpar/currier/internal/secrets"
+	"gopkg.in/yaml.v3"
 )
 
 // PostmanEnvironment represents a Postman environment file format.
@@ -23,20 +27,128 @@ type PostmanEnvValue struct {
 }
 
 // SimpleEnvironment represents a simple key-value environment format.
+// Secrets may be stored encrypted, see EncryptEnvironmentFile. Extends
+// names another environment file to inherit values from.
 type SimpleEnvironment struct {
-	Name      string            `json:"name"`
-	Variables map[string]string `json:"variables,omitempty"`
-	Secrets   map[string]string `json:"secrets,omitempty"`
+	Name             string            `json:"name"`
+	Extends          string            `json:"extends,omitempty"`
+	Variables        map[string]string `json:"variables,omitempty"`
+	Secrets          map[string]string `json:"secrets,omitempty"`
+	EncryptedSecrets *secrets.Sealed   `json:"secrets_encrypted,omitempty"`
 }
 
-// LoadEnvironmentFromFile loads an environment from a file path.
+// environmentExtensions are tried, in order, to find the file an extends
+// name refers to.
+var environmentExtensions = []string{".json", ".yaml", ".yml", ".env"}
+
+// LoadEnvironmentFromFile loads an environment from a file path. JSON, YAML
+// (.yaml, .yml) and dotenv (.env, .env.*) files are supported. An extends
+// name is looked up next to the file that declares it, as a path with or
+// without its extension or as .env.<name>.
 func LoadEnvironmentFromFile(path string) (*Environment, error) {
+	env, err := loadEnvironmentFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Dir(path)
+	err = ResolveBase(env, func(name string) (*Environment, error) {
+		basePath, err := findEnvironmentFile(dir, name)
+		if err != nil {
+			return nil, err
+		}
+		dir = filepath.Dir(basePath)
+		return loadEnvironmentFile(basePath)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return env, nil
+}
+
+// loadEnvironmentFile loads a single environment file in the format its
+// name indicates.
+func loadEnvironmentFile(path string) (*Environment, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read environment file: %w", err)
 	}
 
-	return LoadEnvironmentFromJSON(data)
+	base := filepath.Base(path)
+	switch {
+	case base == ".env" || strings.HasPrefix(base, ".env.") || strings.HasSuffix(base, ".env"):
+		return LoadEnvironmentFromDotenv(dotenvName(base), data)
+	case strings.HasSuffix(base, ".yaml") || strings.HasSuffix(base, ".yml"):
+		return LoadEnvironmentFromYAML(data)
+	default:
+		return LoadEnvironmentFromJSON(data)
+	}
+}
+
+// dotenvName derives an environment name from a dotenv file name, such as
+// staging from .env.staging or staging.env.
+func dotenvName(base string) string {
+	name := strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(base, ".env"), "."), ".env")
+	if name == "" {
+		return "Environment"
+	}
+	return name
+}
+
+// findEnvironmentFile finds the file an extends name refers to in dir.
+func findEnvironmentFile(dir, name string) (string, error) {
+	path := name
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, name)
+	}
+	candidates := []string{path}
+	for _, ext := range environmentExtensions {
+		candidates = append(candidates, path+ext)
+	}
+	candidates = append(candidates, filepath.Join(dir, ".env."+name))
+
+	for _, candidate := range candidates {
+		if info, err := os.Stat(candidate); err == nil && info.Mode().IsRegular() {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("environment file not found in %s", dir)
+}
+
+// LoadEnvironmentFromYAML loads an environment from YAML data in any of the
+// formats LoadEnvironmentFromJSON supports. Numbers and booleans in
+// variables are read as strings.
+func LoadEnvironmentFromYAML(data []byte) (*Environment, error) {
+	var doc map[string]yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid YAML: %w", err)
+	}
+
+	raw := make(map[string]any, len(doc))
+	for key, node := range doc {
+		var value any
+		var err error
+		switch {
+		case node.Kind == yaml.ScalarNode:
+			value = node.Value
+		case key == "variables" || key == "secrets":
+			var values map[string]string
+			err = node.Decode(&values)
+			value = values
+		default:
+			err = node.Decode(&value)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", key, err)
+		}
+		raw[key] = value
+	}
+
+	converted, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert YAML environment: %w", err)
+	}
+	return LoadEnvironmentFromJSON(converted)
 }
 
 // LoadEnvironmentFromJSON loads an environment from JSON data.
@@ -60,6 +172,9 @@ func LoadEnvironmentFromJSON(data []byte) (*Environment, error) {
 	if _, hasSecrets := raw["secrets"]; hasSecrets {
 		return loadSimpleEnvironment(data)
 	}
+	if _, hasEncrypted := raw["secrets_encrypted"]; hasEncrypted {
+		return loadSimpleEnvironment(data)
+	}
 
 	// Try to treat it as a flat key-value object
 	return loadFlatEnvironment(data, raw)
@@ -108,6 +223,7 @@ func loadSimpleEnvironment(data []byte) (*Environment, error) {
 	}
 
 	env := NewEnvironment(name)
+	env.SetExtends(simple.Extends)
 
 	for k, v := range simple.Variables {
 		env.SetVariable(k, v)
@@ -117,9 +233,81 @@ func loadSimpleEnvironment(data []byte) (*Environment, error) {
 		env.SetSecret(k, v)
 	}
 
+	if simple.EncryptedSecrets != nil {
+		key, err := secrets.KeyFromEnv()
+		if err != nil {
+			return nil, err
+		}
+		values, err := key.Open(simple.EncryptedSecrets)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt secrets of %s: %w", name, err)
+		}
+		for k, v := range values {
+			env.SetSecret(k, v)
+		}
+	}
+
 	return env, nil
 }
 
+// EncryptEnvironmentFile rewrites the secrets of an environment file in the
+// simple format encrypted with key. Secrets that are already encrypted are
+// re-encrypted along with any plaintext ones. It reports whether the file
+// had secrets.
+func EncryptEnvironmentFile(path string, key *secrets.Key) (bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false, fmt.Errorf("failed to read environment file: %w", err)
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return false, fmt.Errorf("invalid JSON: %w", err)
+	}
+	if _, hasValues := raw["values"]; hasValues {
+		return false, fmt.Errorf("%s is a Postman environment; only the simple format supports encrypted secrets", path)
+	}
+	_, hasSecrets := raw["secrets"]
+	_, hasEncrypted := raw["secrets_encrypted"]
+	if !hasSecrets && !hasEncrypted {
+		return false, nil
+	}
+
+	var simple SimpleEnvironment
+	if err := json.Unmarshal(data, &simple); err != nil {
+		return false, fmt.Errorf("failed to parse simple environment: %w", err)
+	}
+	values := make(map[string]string)
+	if simple.EncryptedSecrets != nil {
+		opened, err := key.Open(simple.EncryptedSecrets)
+		if err != nil {
+			return false, fmt.Errorf("failed to decrypt secrets: %w", err)
+		}
+		values = opened
+	}
+	for k, v := range simple.Secrets {
+		values[k] = v
+	}
+	if len(values) == 0 {
+		return false, nil
+	}
+
+	sealed, err := key.Seal(values)
+	if err != nil {
+		return false, err
+	}
+	simple.Secrets = nil
+	simple.EncryptedSecrets = sealed
+
+	out, err := json.MarshalIndent(simple, "", "  ")
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal environment: %w", err)
+	}
+	if err := os.WriteFile(path, append(out, '\n'), 0644); err != nil {
+		return false, fmt.Errorf("failed to write environment file: %w", err)
+	}
+	return true, nil
+}
+
 // loadFlatEnvironment loads a flat key-value JSON object as an environment.
 func loadFlatEnvironment(data []byte, raw map[string]json.RawMessage) (*Environment, error) {
 	env := NewEnvironment("Environment")
@@ -131,6 +319,12 @@ func loadFlatEnvironment(data []byte, raw map[string]json.RawMessage) (*Environm
 			env = NewEnvironment(name)
 		}
 	}
+	if extendsRaw, hasExtends := raw["extends"]; hasExtends {
+		var extends string
+		if err := json.Unmarshal(extendsRaw, &extends); err == nil {
+			env.SetExtends(extends)
+		}
+	}
 
 	// Load all string values as variables
 	var flat map[string]interface{}
@@ -139,8 +333,8 @@ func loadFlatEnvironment(data []byte, raw map[string]json.RawMessage) (*Environm
 	}
 
 	for k, v := range flat {
-		if k == "name" {
-			continue // Skip name field
+		if k == "name" || k == "extends" {
+			continue // Skip name and extends fields
 		}
 		if str, ok := v.(string); ok {
 			env.SetVariable(k, str)
@@ -179,31 +373,3 @@ func LoadMultipleEnvironments(paths []string) (*Environment, error) {
 
 	return merged, nil
 }
-
-// MergeCollectionVariables merges collection variables into an environment.
-// Collection variables have lower precedence (environment overrides them).
-func MergeCollectionVariables(env *Environment, collections []*Collection) *Environment {
-	// If no environment provided, create one with collection name
-	if env == nil {
-		name := "Collection Variables"
-		if len(collections) == 1 {
-			name = collections[0].Name() + " (vars)"
-		}
-		env = NewEnvironment(name)
-	}
-
-	// Create a new environment with collection variables first
-	merged := NewEnvironment(env.Name())
-
-	// Add collection variables (lower precedence)
-	for _, coll := range collections {
-		for k, v := range coll.Variables() {
-			merged.SetVariable(k, v)
-		}
-	}
-
-	// Merge environment variables on top (higher precedence)
-	merged.Merge(env)
-
-	return merged
-}
@@ -2,8 +2,10 @@ package core
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/artpar/currier/internal/secrets"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -365,6 +367,120 @@ func TestLoadEnvironmentFromFile(t *testing.T) {
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "failed to read environment file")
 	})
+
+	writeFile := func(t *testing.T, dir, name, content string) string {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		return path
+	}
+
+	t.Run("loads YAML files", func(t *testing.T) {
+		dir := t.TempDir()
+		path := writeFile(t, dir, "dev.yaml", "name: Dev\nvariables:\n  host: localhost\n  port: 8080\n  debug: true\nsecrets:\n  token: abc\n")
+
+		env, err := LoadEnvironmentFromFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, "Dev", env.Name())
+		assert.Equal(t, map[string]string{"host": "localhost", "port": "8080", "debug": "true"}, env.Variables())
+		assert.Equal(t, "abc", env.GetSecret("token"))
+
+		flat := writeFile(t, dir, "flat.yml", "name: Flat\nhost: example.com\nretries: 3\n")
+		env, err = LoadEnvironmentFromFile(flat)
+		require.NoError(t, err)
+		assert.Equal(t, "Flat", env.Name())
+		assert.Equal(t, map[string]string{"host": "example.com", "retries": "3"}, env.Variables())
+	})
+
+	t.Run("loads dotenv files", func(t *testing.T) {
+		dir := t.TempDir()
+		env, err := LoadEnvironmentFromFile(writeFile(t, dir, ".env.staging", "HOST=staging.example.com\n"))
+		require.NoError(t, err)
+		assert.Equal(t, "staging", env.Name())
+		assert.Equal(t, "staging.example.com", env.GetVariable("HOST"))
+
+		env, err = LoadEnvironmentFromFile(writeFile(t, dir, ".env", "HOST=localhost\n"))
+		require.NoError(t, err)
+		assert.Equal(t, "Environment", env.Name())
+	})
+
+	t.Run("inherits from the environments it extends", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFile(t, dir, ".env.shared", "TIMEOUT=30\nHOST=shared.example.com\n")
+		writeFile(t, dir, "base.yaml", "name: base\nextends: shared\nvariables:\n  host: base.example.com\n  scheme: https\nsecrets:\n  token: base-token\n")
+		path := writeFile(t, dir, "staging.json", `{"name": "staging", "extends": "base", "variables": {"host": "staging.example.com"}}`)
+
+		env, err := LoadEnvironmentFromFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"staging", "base", "shared"}, env.Chain())
+		assert.Equal(t, map[string]string{"host": "staging.example.com"}, env.Variables())
+		assert.Equal(t, map[string]string{
+			"host":    "staging.example.com",
+			"scheme":  "https",
+			"token":   "base-token",
+			"TIMEOUT": "30",
+			"HOST":    "shared.example.com",
+		}, env.ExportAll())
+		assert.Equal(t, "Bearer ********", env.Redactor().Redact("Bearer base-token"))
+	})
+
+	t.Run("reads extends from flat files", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFile(t, dir, "base.json", `{"host": "base.example.com", "scheme": "https"}`)
+		env, err := LoadEnvironmentFromFile(writeFile(t, dir, "dev.json", `{"extends": "base", "host": "localhost"}`))
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"host": "localhost"}, env.Variables())
+		assert.Equal(t, "https", env.ExportAll()["scheme"])
+	})
+
+	t.Run("rejects missing and circular bases", func(t *testing.T) {
+		dir := t.TempDir()
+		_, err := LoadEnvironmentFromFile(writeFile(t, dir, "orphan.json", `{"name": "orphan", "extends": "nowhere", "variables": {}}`))
+		assert.ErrorContains(t, err, "environment orphan extends nowhere: environment file not found")
+
+		writeFile(t, dir, "a.json", `{"name": "a", "extends": "b", "variables": {}}`)
+		writeFile(t, dir, "b.json", `{"name": "b", "extends": "a", "variables": {}}`)
+		_, err = LoadEnvironmentFromFile(filepath.Join(dir, "a.json"))
+		assert.ErrorContains(t, err, "circular environment inheritance")
+	})
+}
+
+func TestEncryptEnvironmentFile(t *testing.T) {
+	t.Setenv(secrets.KeyFileEnv, "")
+	path := filepath.Join(t.TempDir(), "prod.json")
+	content := `{"name": "Prod", "variables": {"host": "api.example.com"}, "secrets": {"token": "s3cr3t-token"}}`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+	encrypted, err := EncryptEnvironmentFile(path, secrets.NewPassphraseKey("passphrase"))
+	require.NoError(t, err)
+	assert.True(t, encrypted)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"secrets_encrypted"`)
+	assert.NotContains(t, string(data), "s3cr3t-token")
+
+	t.Run("fails without a key", func(t *testing.T) {
+		t.Setenv(secrets.PassphraseEnv, "")
+		_, err := LoadEnvironmentFromFile(path)
+		assert.ErrorIs(t, err, secrets.ErrLocked)
+	})
+
+	t.Run("loads with the key from the environment", func(t *testing.T) {
+		t.Setenv(secrets.PassphraseEnv, "passphrase")
+		env, err := LoadEnvironmentFromFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, "api.example.com", env.GetVariable("host"))
+		assert.Equal(t, "s3cr3t-token", env.GetSecret("token"))
+		assert.Equal(t, "Bearer ********", env.Redactor().Redact("Bearer s3cr3t-token"))
+	})
+
+	t.Run("skips files without secrets", func(t *testing.T) {
+		flat := filepath.Join(t.TempDir(), "flat.json")
+		require.NoError(t, os.WriteFile(flat, []byte(`{"host": "x"}`), 0644))
+		encrypted, err := EncryptEnvironmentFile(flat, secrets.NewPassphraseKey("passphrase"))
+		require.NoError(t, err)
+		assert.False(t, encrypted)
+	})
 }
 
 func TestLoadMultipleEnvironments(t *testing.T) {
@@ -425,35 +541,32 @@ func TestLoadMultipleEnvironments(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+func TestEnvironment_Redactor(t *testing.T) {
+	t.Run("masks values resolved from secret providers", func(t *testing.T) {
+		t.Setenv("CURRIER_TEST_PROVIDED", "provided-s3cr3t")
+		_, err := secrets.Resolve("env:CURRIER_TEST_PROVIDED")
+		require.NoError(t, err)
 
-func TestMergeCollectionVariables(t *testing.T) {
-	t.Run("creates environment when nil", func(t *testing.T) {
-		c := NewCollection("Test API")
-		c.SetVariable("api_key", "collection-key")
-
-		result := MergeCollectionVariables(nil, []*Collection{c})
-		assert.NotNil(t, result)
-		assert.Equal(t, "collection-key", result.GetVariable("api_key"))
-	})
-
-	t.Run("environment overrides collection vars", func(t *testing.T) {
-		c := NewCollection("Test API")
-		c.SetVariable("api_key", "collection-key")
-		c.SetVariable("other_var", "collection-value")
-
-		env := NewEnvironment("My Env")
-		env.SetVariable("api_key", "env-key")
-
-		result := MergeCollectionVariables(env, []*Collection{c})
-		assert.Equal(t, "env-key", result.GetVariable("api_key"))
-		assert.Equal(t, "collection-value", result.GetVariable("other_var"))
+		env := NewEnvironment("test")
+		env.SetSecret("token", "env-s3cr3t")
+		assert.Equal(t, "******** ********", env.Redactor().Redact("provided-s3cr3t env-s3cr3t"))
 	})
+}
 
-	t.Run("uses single collection name", func(t *testing.T) {
-		c := NewCollection("My API")
-
-		result := MergeCollectionVariables(nil, []*Collection{c})
-		assert.Contains(t, result.Name(), "My API")
+func TestMergeGlobals(t *testing.T) {
+	t.Run("later globals override earlier ones", func(t *testing.T) {
+		company := NewEnvironment("company")
+		company.SetVariable("host", "api.example.com")
+		company.SetVariable("user", "shared")
+		personal := NewEnvironment("personal")
+		personal.SetVariable("user", "alice")
+		personal.SetSecret("token", "s3cr3t")
+
+		assert.Equal(t, map[string]string{
+			"host":  "api.example.com",
+			"user":  "alice",
+			"token": "s3cr3t",
+		}, MergeGlobals([]*Environment{company, personal}))
+		assert.Empty(t, MergeGlobals(nil))
 	})
 }
-
@@ -5,7 +5,9 @@ import (
 	"encoding/json"
 	"errors"
 	"io"
+	"mime"
 	"mime/multipart"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
@@ -375,6 +377,111 @@ func (b *formBody) Fields() []FormField {
 	return b.fields
 }
 
+// urlEncodedBody represents an application/x-www-form-urlencoded body.
+type urlEncodedBody struct {
+	fields  []FormField
+	encoded []byte
+}
+
+// NewURLEncodedBody creates a URL-encoded form body from the given fields.
+// Field order is preserved and file fields are ignored.
+func NewURLEncodedBody(fields []FormField) Body {
+	parts := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if field.IsFile {
+			continue
+		}
+		parts = append(parts, url.QueryEscape(field.Key)+"="+url.QueryEscape(field.Value))
+	}
+	return &urlEncodedBody{
+		fields:  fields,
+		encoded: []byte(strings.Join(parts, "&")),
+	}
+}
+
+func (b *urlEncodedBody) Type() string        { return "urlencoded" }
+func (b *urlEncodedBody) ContentType() string { return "application/x-www-form-urlencoded" }
+func (b *urlEncodedBody) IsEmpty() bool       { return len(b.encoded) == 0 }
+func (b *urlEncodedBody) Size() int64         { return int64(len(b.encoded)) }
+func (b *urlEncodedBody) Bytes() []byte       { return b.encoded }
+func (b *urlEncodedBody) String() string      { return string(b.encoded) }
+func (b *urlEncodedBody) Reader() io.Reader   { return bytes.NewReader(b.encoded) }
+func (b *urlEncodedBody) JSON() (any, error) {
+	return b.fields, nil
+}
+
+// Fields returns the form fields.
+func (b *urlEncodedBody) Fields() []FormField {
+	return b.fields
+}
+
+// fileBody streams the contents of a file from disk.
+type fileBody struct {
+	path        string
+	contentType string
+	size        int64
+}
+
+// NewFileBody creates a body that streams the file at path. If contentType
+// is empty it is guessed from the file extension.
+func NewFileBody(path, contentType string) (Body, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return nil, errors.New("body file is a directory: " + path)
+	}
+	if contentType == "" {
+		contentType = mime.TypeByExtension(filepath.Ext(path))
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	return &fileBody{
+		path:        path,
+		contentType: contentType,
+		size:        info.Size(),
+	}, nil
+}
+
+func (b *fileBody) Type() string        { return "binary" }
+func (b *fileBody) ContentType() string { return b.contentType }
+func (b *fileBody) IsEmpty() bool       { return b.size == 0 }
+func (b *fileBody) Size() int64         { return b.size }
+func (b *fileBody) Path() string        { return b.path }
+
+// Bytes reads the whole file. Prefer Reader for large files.
+func (b *fileBody) Bytes() []byte {
+	data, _ := os.ReadFile(b.path)
+	return data
+}
+
+func (b *fileBody) String() string { return string(b.Bytes()) }
+
+// Reader opens the file for streaming. The returned reader is an *os.File
+// that the caller (normally net/http) is responsible for closing.
+func (b *fileBody) Reader() io.Reader {
+	f, err := os.Open(b.path)
+	if err != nil {
+		return &errReader{err: err}
+	}
+	return f
+}
+
+func (b *fileBody) JSON() (any, error) {
+	var result any
+	err := json.Unmarshal(b.Bytes(), &result)
+	return result, err
+}
+
+// errReader is a reader that always fails with err.
+type errReader struct {
+	err error
+}
+
+func (r *errReader) Read([]byte) (int, error) { return 0, r.err }
+
 // Status represents an HTTP status code and text.
 type Status struct {
 	code int
@@ -3,6 +3,8 @@ package core
 import (
 	"bytes"
 	"io"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -509,3 +511,77 @@ func BenchmarkBody_JSON(b *testing.B) {
 		_, _ = body.JSON()
 	}
 }
+
+func TestURLEncodedBody(t *testing.T) {
+	t.Run("encodes fields in order", func(t *testing.T) {
+		body := NewURLEncodedBody([]FormField{
+			{Key: "b", Value: "two words"},
+			{Key: "a", Value: "x&y"},
+		})
+
+		assert.Equal(t, "urlencoded", body.Type())
+		assert.Equal(t, "application/x-www-form-urlencoded", body.ContentType())
+		assert.Equal(t, "b=two+words&a=x%26y", body.String())
+		assert.Equal(t, int64(len(body.Bytes())), body.Size())
+	})
+
+	t.Run("skips file fields", func(t *testing.T) {
+		body := NewURLEncodedBody([]FormField{
+			{Key: "name", Value: "test"},
+			{Key: "upload", IsFile: true, FilePath: "/tmp/x"},
+		})
+		assert.Equal(t, "name=test", body.String())
+	})
+
+	t.Run("empty body", func(t *testing.T) {
+		assert.True(t, NewURLEncodedBody(nil).IsEmpty())
+	})
+}
+
+func TestFileBody(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("streams file contents", func(t *testing.T) {
+		path := filepath.Join(dir, "data.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{"a":1}`), 0644))
+
+		body, err := NewFileBody(path, "")
+		require.NoError(t, err)
+
+		assert.Equal(t, "binary", body.Type())
+		assert.Equal(t, "application/json", body.ContentType())
+		assert.Equal(t, int64(7), body.Size())
+
+		data, err := io.ReadAll(body.Reader())
+		require.NoError(t, err)
+		assert.Equal(t, `{"a":1}`, string(data))
+	})
+
+	t.Run("explicit content type wins", func(t *testing.T) {
+		path := filepath.Join(dir, "image.bin")
+		require.NoError(t, os.WriteFile(path, []byte{0, 1, 2}, 0644))
+
+		body, err := NewFileBody(path, "image/png")
+		require.NoError(t, err)
+		assert.Equal(t, "image/png", body.ContentType())
+	})
+
+	t.Run("unknown extension falls back to octet-stream", func(t *testing.T) {
+		path := filepath.Join(dir, "blob.unknownext")
+		require.NoError(t, os.WriteFile(path, []byte{0}, 0644))
+
+		body, err := NewFileBody(path, "")
+		require.NoError(t, err)
+		assert.Equal(t, "application/octet-stream", body.ContentType())
+	})
+
+	t.Run("missing file errors", func(t *testing.T) {
+		_, err := NewFileBody(filepath.Join(dir, "missing"), "")
+		assert.Error(t, err)
+	})
+
+	t.Run("directory errors", func(t *testing.T) {
+		_, err := NewFileBody(dir, "")
+		assert.Error(t, err)
+	})
+}
@@ -5,6 +5,13 @@ import (
 	"github.com/google/uuid"
 )
 
+// Response metadata keys set when a body is streamed to disk.
+const (
+	MetaDownloadPath  = "download_path"  // string: file holding the full body
+	MetaDownloadSize  = "download_size"  // int64: bytes written to the file
+	MetaBodyTruncated = "body_truncated" // bool: Body() holds only a preview
+)
+
 // Response implements the interfaces.Response interface.
 type Response struct {
 	id        string
@@ -67,6 +74,25 @@ func (r *Response) Metadata() map[string]any {
 	return result
 }
 
+// DownloadPath returns the file the response body was streamed to, or "" if
+// the body was read into memory.
+func (r *Response) DownloadPath() string {
+	path, _ := r.metadata[MetaDownloadPath].(string)
+	return path
+}
+
+// DownloadSize returns the number of body bytes written to DownloadPath.
+func (r *Response) DownloadSize() int64 {
+	size, _ := r.metadata[MetaDownloadSize].(int64)
+	return size
+}
+
+// IsBodyTruncated reports whether Body holds only a preview of the full body.
+func (r *Response) IsBodyTruncated() bool {
+	truncated, _ := r.metadata[MetaBodyTruncated].(bool)
+	return truncated
+}
+
 // WithHeaders sets the response headers and returns the response for chaining.
 func (r *Response) WithHeaders(h *Headers) *Response {
 	r.headers = h
@@ -7,13 +7,15 @@ import (
 	"strings"
 
 	"github.com/artpar/currier/internal/core"
+	"github.com/artpar/currier/internal/secrets"
 )
 
 // CurlExporter exports collections and requests to curl commands.
 type CurlExporter struct {
 	// Options
-	Pretty      bool // Use line continuations for readability
-	IncludeAuth bool // Include auth in output
+	Pretty      bool              // Use line continuations for readability
+	IncludeAuth bool              // Include auth in output
+	Redactor    *secrets.Redactor // Masks secret values, if set
 }
 
 // NewCurlExporter creates a new curl exporter.
@@ -118,10 +120,34 @@ func (c *CurlExporter) ExportRequest(ctx context.Context, req *core.RequestDefin
 	}
 
 	// Body
-	body := req.Body()
-	if body != "" {
-		// Use --data-raw for safety
-		parts = append(parts, "--data-raw", body)
+	switch req.BodyType() {
+	case "form":
+		for _, f := range req.FormFields() {
+			if f.IsFile {
+				parts = append(parts, "-F", fmt.Sprintf("%s=@%s", f.Key, f.FilePath))
+			} else {
+				parts = append(parts, "-F", fmt.Sprintf("%s=%s", f.Key, f.Value))
+			}
+		}
+	case "urlencoded":
+		for _, f := range req.FormFields() {
+			parts = append(parts, "--data-urlencode", fmt.Sprintf("%s=%s", f.Key, f.Value))
+		}
+	case "binary":
+		if file := req.BodyFile(); file != "" {
+			if _, ok := headers["Content-Type"]; !ok && req.BodyContentType() != "" {
+				parts = append(parts, "-H", "Content-Type: "+req.BodyContentType())
+			}
+			parts = append(parts, "--data-binary", "@"+file)
+		}
+	default:
+		if body := req.Body(); body != "" {
+			if _, ok := headers["Content-Type"]; !ok && req.BodyType() == "raw" && req.BodyContentType() != "" {
+				parts = append(parts, "-H", "Content-Type: "+req.BodyContentType())
+			}
+			// Use --data-raw for safety
+			parts = append(parts, "--data-raw", body)
+		}
 	}
 
 	// Auth
@@ -142,6 +168,10 @@ func (c *CurlExporter) ExportRequest(ctx context.Context, req *core.RequestDefin
 	// URL (always last)
 	parts = append(parts, req.URL())
 
+	for i := range parts {
+		parts[i] = c.Redactor.Redact(parts[i])
+	}
+
 	// Format output
 	if c.Pretty {
 		return []byte(formatPrettyCurl(parts)), nil
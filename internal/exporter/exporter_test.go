@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/artpar/currier/internal/core"
+	"github.com/artpar/currier/internal/secrets"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -140,6 +141,40 @@ func TestCurlExporter_ExportRequest_WithBody(t *testing.T) {
 	assert.Contains(t, cmd, `{"name": "John"}`)
 }
 
+func TestCurlExporter_ExportRequest_BodyTypes(t *testing.T) {
+	exp := NewCurlExporter()
+	exp.Pretty = false
+	ctx := context.Background()
+
+	t.Run("urlencoded", func(t *testing.T) {
+		req := core.NewRequestDefinition("Login", "POST", "https://api.example.com/login")
+		req.SetBodyURLEncoded([]core.FormField{{Key: "user", Value: "john doe"}, {Key: "pw", Value: "x"}})
+
+		result, err := exp.ExportRequest(ctx, req)
+		require.NoError(t, err)
+		assert.Contains(t, string(result), "--data-urlencode 'user=john doe' --data-urlencode pw=x")
+	})
+
+	t.Run("binary", func(t *testing.T) {
+		req := core.NewRequestDefinition("Upload", "PUT", "https://api.example.com/blob")
+		req.SetBodyBinary("/tmp/data.bin", "application/octet-stream")
+
+		result, err := exp.ExportRequest(ctx, req)
+		require.NoError(t, err)
+		assert.Contains(t, string(result), "-H 'Content-Type: application/octet-stream'")
+		assert.Contains(t, string(result), "--data-binary @/tmp/data.bin")
+	})
+
+	t.Run("raw with content type", func(t *testing.T) {
+		req := core.NewRequestDefinition("XML", "POST", "https://api.example.com/xml")
+		req.SetBodyRaw("<a/>", "application/xml")
+
+		result, err := exp.ExportRequest(ctx, req)
+		require.NoError(t, err)
+		assert.Contains(t, string(result), "-H 'Content-Type: application/xml' --data-raw '<a/>'")
+	})
+}
+
 func TestCurlExporter_ExportRequest_WithBasicAuth(t *testing.T) {
 	exp := NewCurlExporter()
 	exp.Pretty = false
@@ -178,6 +213,24 @@ func TestCurlExporter_ExportRequest_WithBearerAuth(t *testing.T) {
 	assert.Contains(t, cmd, "Authorization: Bearer mytoken123")
 }
 
+func TestCurlExporter_ExportRequest_Redacted(t *testing.T) {
+	exp := NewCurlExporter()
+	exp.Pretty = false
+	exp.Redactor = secrets.NewRedactor("mytoken123", "p@ss word")
+	ctx := context.Background()
+
+	req := core.NewRequestDefinition("Test", "GET", "https://api.example.com?key=p%40ss+word")
+	req.SetAuth(core.AuthConfig{Type: "bearer", Token: "mytoken123"})
+
+	result, err := exp.ExportRequest(ctx, req)
+	require.NoError(t, err)
+
+	cmd := string(result)
+	assert.Contains(t, cmd, "Authorization: Bearer ********")
+	assert.Contains(t, cmd, "key=********")
+	assert.NotContains(t, cmd, "mytoken123")
+}
+
 func TestCurlExporter_ExportRequest_Pretty(t *testing.T) {
 	exp := NewCurlExporter()
 	exp.Pretty = true
@@ -717,6 +770,44 @@ func TestPostmanExporter_Export_WithURLEncodedBody(t *testing.T) {
 	assert.Equal(t, "secret", fields["password"])
 }
 
+func TestPostmanExporter_Export_BinaryAndRawContentType(t *testing.T) {
+	exp := NewPostmanExporter()
+	ctx := context.Background()
+
+	coll := core.NewCollection("Test")
+	upload := core.NewRequestDefinition("Upload", "PUT", "https://api.example.com/blob")
+	upload.SetBodyBinary("/tmp/data.bin", "")
+	coll.AddRequest(upload)
+	form := core.NewRequestDefinition("Login", "POST", "https://api.example.com/login")
+	form.SetBodyURLEncoded([]core.FormField{{Key: "user", Value: "john"}})
+	coll.AddRequest(form)
+	xml := core.NewRequestDefinition("XML", "POST", "https://api.example.com/xml")
+	xml.SetBodyRaw("<a/>", "application/xml")
+	coll.AddRequest(xml)
+
+	result, err := exp.Export(ctx, coll)
+	require.NoError(t, err)
+
+	var pm map[string]interface{}
+	require.NoError(t, json.Unmarshal(result, &pm))
+	items := pm["item"].([]interface{})
+	require.Len(t, items, 3)
+
+	bodyOf := func(i int) map[string]interface{} {
+		return items[i].(map[string]interface{})["request"].(map[string]interface{})["body"].(map[string]interface{})
+	}
+
+	assert.Equal(t, "file", bodyOf(0)["mode"])
+	assert.Equal(t, "/tmp/data.bin", bodyOf(0)["file"].(map[string]interface{})["src"])
+
+	assert.Equal(t, "urlencoded", bodyOf(1)["mode"])
+	assert.Len(t, bodyOf(1)["urlencoded"], 1)
+
+	assert.Equal(t, "raw", bodyOf(2)["mode"])
+	options := bodyOf(2)["options"].(map[string]interface{})["raw"].(map[string]interface{})
+	assert.Equal(t, "xml", options["language"])
+}
+
 func TestPostmanExporter_Export_WithQueryParams(t *testing.T) {
 	exp := NewPostmanExporter()
 	ctx := context.Background()
@@ -101,6 +101,14 @@ func (p *PostmanExporter) convertFolder(folder *core.Folder) postmanItem {
 		Item:        make([]postmanItem, 0),
 	}
 
+	for key, value := range folder.Variables() {
+		item.Variable = append(item.Variable, postmanVar{
+			Key:   key,
+			Value: value,
+			Type:  "string",
+		})
+	}
+
 	// Add requests
 	for _, req := range folder.Requests() {
 		item.Item = append(item.Item, p.convertRequest(req))
@@ -163,26 +171,12 @@ func (p *PostmanExporter) convertRequest(req *core.RequestDefinition) postmanIte
 			}
 		}
 	case "urlencoded":
-		// URL-encoded body - parse from body content
-		if bodyContent != "" {
+		item.Request.Body = p.convertURLEncodedBody(req)
+	case "binary":
+		if file := req.BodyFile(); file != "" {
 			item.Request.Body = &postmanBody{
-				Mode:       "urlencoded",
-				URLEncoded: make([]postmanURLEncoded, 0),
-			}
-			pairs := strings.Split(bodyContent, "&")
-			for _, pair := range pairs {
-				kv := strings.SplitN(pair, "=", 2)
-				if len(kv) == 2 {
-					item.Request.Body.URLEncoded = append(item.Request.Body.URLEncoded, postmanURLEncoded{
-						Key:   kv[0],
-						Value: kv[1],
-					})
-				} else if len(kv) == 1 && kv[0] != "" {
-					item.Request.Body.URLEncoded = append(item.Request.Body.URLEncoded, postmanURLEncoded{
-						Key:   kv[0],
-						Value: "",
-					})
-				}
+				Mode: "file",
+				File: &postmanFile{Src: file},
 			}
 		}
 	default:
@@ -193,13 +187,17 @@ func (p *PostmanExporter) convertRequest(req *core.RequestDefinition) postmanIte
 				Raw:  bodyContent,
 			}
 
-			// Detect JSON and set options
-			if strings.HasPrefix(strings.TrimSpace(bodyContent), "{") || strings.HasPrefix(strings.TrimSpace(bodyContent), "[") {
+			// Use the explicit content type, falling back to detecting JSON
+			language := contentTypeLanguage(req.BodyContentType())
+			if language == "" && (strings.HasPrefix(strings.TrimSpace(bodyContent), "{") || strings.HasPrefix(strings.TrimSpace(bodyContent), "[")) {
+				language = "json"
+			}
+			if language != "" {
 				item.Request.Body.Options = &postmanBodyOptions{
 					Raw: struct {
 						Language string `json:"language,omitempty"`
 					}{
-						Language: "json",
+						Language: language,
 					},
 				}
 			}
@@ -237,6 +235,63 @@ func (p *PostmanExporter) convertRequest(req *core.RequestDefinition) postmanIte
 	return item
 }
 
+// convertURLEncodedBody converts urlencoded form fields, falling back to
+// splitting a pre-encoded body string.
+func (p *PostmanExporter) convertURLEncodedBody(req *core.RequestDefinition) *postmanBody {
+	body := &postmanBody{
+		Mode:       "urlencoded",
+		URLEncoded: make([]postmanURLEncoded, 0),
+	}
+	if fields := req.FormFields(); len(fields) > 0 {
+		for _, field := range fields {
+			body.URLEncoded = append(body.URLEncoded, postmanURLEncoded{
+				Key:   field.Key,
+				Value: field.Value,
+			})
+		}
+		return body
+	}
+	if req.Body() == "" {
+		return nil
+	}
+	for _, pair := range strings.Split(req.Body(), "&") {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) == 2 {
+			body.URLEncoded = append(body.URLEncoded, postmanURLEncoded{
+				Key:   kv[0],
+				Value: kv[1],
+			})
+		} else if len(kv) == 1 && kv[0] != "" {
+			body.URLEncoded = append(body.URLEncoded, postmanURLEncoded{
+				Key:   kv[0],
+				Value: "",
+			})
+		}
+	}
+	return body
+}
+
+// contentTypeLanguage maps a raw body content type to a Postman raw language.
+func contentTypeLanguage(contentType string) string {
+	ct := strings.ToLower(contentType)
+	switch {
+	case ct == "":
+		return ""
+	case strings.Contains(ct, "json"):
+		return "json"
+	case strings.Contains(ct, "xml"):
+		return "xml"
+	case strings.Contains(ct, "html"):
+		return "html"
+	case strings.Contains(ct, "javascript"):
+		return "javascript"
+	case strings.HasPrefix(ct, "text/"):
+		return "text"
+	default:
+		return ""
+	}
+}
+
 func (p *PostmanExporter) convertURL(req *core.RequestDefinition) interface{} {
 	queryParams := req.QueryParams()
 
@@ -352,6 +407,7 @@ type postmanItem struct {
 	Item        []postmanItem   `json:"item,omitempty"`
 	Request     *postmanRequest `json:"request,omitempty"`
 	Event       []postmanEvent  `json:"event,omitempty"`
+	Variable    []postmanVar    `json:"variable,omitempty"`
 }
 
 type postmanRequest struct {
@@ -386,6 +442,11 @@ type postmanBody struct {
 	Options    *postmanBodyOptions `json:"options,omitempty"`
 	FormData   []postmanFormData   `json:"formdata,omitempty"`
 	URLEncoded []postmanURLEncoded `json:"urlencoded,omitempty"`
+	File       *postmanFile        `json:"file,omitempty"`
+}
+
+type postmanFile struct {
+	Src string `json:"src,omitempty"`
 }
 
 type postmanFormData struct {
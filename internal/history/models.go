@@ -2,6 +2,7 @@ package history
 
 import (
 	"time"
+	"unicode/utf8"
 )
 
 // Entry represents a single request/response history entry.
@@ -102,3 +103,51 @@ type PruneResult struct {
 	DeletedCount int64 `json:"deleted_count"`
 	FreedBytes   int64 `json:"freed_bytes"`
 }
+
+// Metadata keys used for responses that were streamed to disk.
+const (
+	MetaResponseFile      = "response_file"      // Path of the file holding the full body
+	MetaResponseTruncated = "response_truncated" // "true" when ResponseBody is a preview
+)
+
+// DefaultMaxStoredBody is the largest preview of a downloaded response body
+// kept in history; the full body stays in the downloaded file.
+const DefaultMaxStoredBody = 64 * 1024 // 64KB
+
+// SetResponseBody stores body on the entry, truncating it to maxSize bytes.
+// When the full body lives on disk, filePath is recorded so it can still be
+// opened from history. A maxSize of 0 keeps the whole body.
+func (e *Entry) SetResponseBody(body string, maxSize int, filePath string) {
+	truncated := false
+	if maxSize > 0 && len(body) > maxSize {
+		// Back up to a rune boundary so the stored preview stays valid UTF-8
+		for maxSize > 0 && !utf8.RuneStart(body[maxSize]) {
+			maxSize--
+		}
+		body = body[:maxSize]
+		truncated = true
+	}
+	e.ResponseBody = body
+
+	if truncated || filePath != "" {
+		if e.Metadata == nil {
+			e.Metadata = make(map[string]string)
+		}
+	}
+	if truncated {
+		e.Metadata[MetaResponseTruncated] = "true"
+	}
+	if filePath != "" {
+		e.Metadata[MetaResponseFile] = filePath
+	}
+}
+
+// ResponseFile returns the path of the file holding the full response body, if any.
+func (e Entry) ResponseFile() string {
+	return e.Metadata[MetaResponseFile]
+}
+
+// IsResponseTruncated reports whether ResponseBody holds only part of the body.
+func (e Entry) IsResponseTruncated() bool {
+	return e.Metadata[MetaResponseTruncated] == "true"
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -331,3 +332,42 @@ func TestCacheStats_JSONSerialization(t *testing.T) {
 	assert.Equal(t, stats.MissCount, decoded.MissCount)
 	assert.InDelta(t, stats.HitRate, decoded.HitRate, 0.001)
 }
+
+func TestEntry_SetResponseBody(t *testing.T) {
+	t.Run("keeps small bodies verbatim", func(t *testing.T) {
+		var e Entry
+		e.SetResponseBody(`{"ok":true}`, 0, "")
+		assert.Equal(t, `{"ok":true}`, e.ResponseBody)
+		assert.False(t, e.IsResponseTruncated())
+		assert.Empty(t, e.ResponseFile())
+		assert.Nil(t, e.Metadata)
+	})
+
+	t.Run("truncates large bodies", func(t *testing.T) {
+		var e Entry
+		e.SetResponseBody("abcdefghij", 4, "")
+		assert.Equal(t, "abcd", e.ResponseBody)
+		assert.True(t, e.IsResponseTruncated())
+	})
+
+	t.Run("keeps the whole body without a limit", func(t *testing.T) {
+		var e Entry
+		body := strings.Repeat("a", DefaultMaxStoredBody+1)
+		e.SetResponseBody(body, 0, "")
+		assert.Equal(t, body, e.ResponseBody)
+		assert.False(t, e.IsResponseTruncated())
+	})
+
+	t.Run("truncates on a rune boundary", func(t *testing.T) {
+		var e Entry
+		e.SetResponseBody("aé", 2, "")
+		assert.Equal(t, "a", e.ResponseBody)
+	})
+
+	t.Run("records downloaded file", func(t *testing.T) {
+		e := Entry{Metadata: map[string]string{"k": "v"}}
+		e.SetResponseBody("preview", 0, "/tmp/out.bin")
+		assert.Equal(t, "/tmp/out.bin", e.ResponseFile())
+		assert.Equal(t, "v", e.Metadata["k"])
+	})
+}
@@ -0,0 +1,385 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/artpar/currier/internal/jsonschema"
+	"gopkg.in/yaml.v3"
+)
+
+// Names of the checks a contract runs against a response.
+const (
+	ContractCheckOperation   = "Operation is declared"
+	ContractCheckStatus      = "Status code is declared"
+	ContractCheckContentType = "Content type is declared"
+	ContractCheckBody        = "Body matches schema"
+)
+
+// Contract validates HTTP responses against the operations of an OpenAPI 3.x
+// spec: the status code must be declared, the content type must match the
+// declared media types and JSON bodies must match the response schema.
+type Contract struct {
+	doc        any // Generic form of the spec, for $ref resolution
+	draft      jsonschema.Draft
+	operations []*contractOperation
+}
+
+// contractOperation is one operation of the spec with its path matcher.
+type contractOperation struct {
+	method  string
+	path    string
+	pattern *regexp.Regexp
+	literal int    // Length of the non-parameter part, to prefer specific paths
+	pointer string // JSON pointer to the operation in the spec
+}
+
+// ContractViolation is one problem found by a contract check. Pointer is a
+// JSON pointer into the response body, empty for the whole response.
+type ContractViolation struct {
+	Pointer string
+	Message string
+}
+
+func (v ContractViolation) String() string {
+	return jsonschema.FormatPointer(v.Pointer) + ": " + v.Message
+}
+
+// ContractCheck is the outcome of one contract check.
+type ContractCheck struct {
+	Name       string
+	Violations []ContractViolation
+}
+
+// Passed reports whether the check found no violations.
+func (c ContractCheck) Passed() bool {
+	return len(c.Violations) == 0
+}
+
+// LoadContract reads an OpenAPI spec file for contract testing.
+func LoadContract(path string) (*Contract, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read contract: %w", err)
+	}
+	contract, err := ParseContract(content)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return contract, nil
+}
+
+// ParseContract parses an OpenAPI 3.x spec in JSON or YAML for contract
+// testing.
+func ParseContract(content []byte) (*Contract, error) {
+	doc, err := parseOpenAPIDocument(content)
+	if err != nil {
+		return nil, err
+	}
+
+	root, _ := doc.(map[string]any)
+	version, _ := root["openapi"].(string)
+	if !strings.HasPrefix(version, "3.") {
+		return nil, fmt.Errorf("%w: expected OpenAPI 3.x, got %s", ErrUnsupportedVersion, version)
+	}
+
+	c := &Contract{doc: doc, draft: jsonschema.Draft2020}
+	if strings.HasPrefix(version, "3.0") {
+		c.draft = jsonschema.OpenAPI30
+	}
+
+	// Schemas stay generic: OpenAPI 3.1 allows JSON Schema forms, such as
+	// type arrays, that the importer's typed structs don't model
+	paths, _ := root["paths"].(map[string]any)
+	for path, item := range paths {
+		operations, _ := item.(map[string]any)
+		for _, method := range contractMethods {
+			if _, ok := operations[strings.ToLower(method)].(map[string]any); ok {
+				c.operations = append(c.operations, newContractOperation(pathOperation{Path: path, Method: method}))
+			}
+		}
+	}
+	sort.Slice(c.operations, func(i, j int) bool {
+		if c.operations[i].path == c.operations[j].path {
+			return c.operations[i].method < c.operations[j].method
+		}
+		return c.operations[i].path < c.operations[j].path
+	})
+
+	return c, nil
+}
+
+// parseOpenAPIDocument decodes JSON or YAML into maps with string keys.
+func parseOpenAPIDocument(content []byte) (any, error) {
+	var doc any
+	if err := json.Unmarshal(content, &doc); err != nil {
+		if err := yaml.Unmarshal(content, &doc); err != nil {
+			return nil, fmt.Errorf("%w: failed to parse OpenAPI spec: %v", ErrParseError, err)
+		}
+	}
+	return stringKeys(doc), nil
+}
+
+// stringKeys converts YAML maps with non-string keys, such as unquoted
+// status codes, into JSON-style maps.
+func stringKeys(v any) any {
+	switch node := v.(type) {
+	case map[string]any:
+		for k, child := range node {
+			node[k] = stringKeys(child)
+		}
+		return node
+	case map[any]any:
+		out := make(map[string]any, len(node))
+		for k, child := range node {
+			out[fmt.Sprint(k)] = stringKeys(child)
+		}
+		return out
+	case []any:
+		for i, child := range node {
+			node[i] = stringKeys(child)
+		}
+		return node
+	}
+	return v
+}
+
+// contractMethods are the operation methods of a path item, as in
+// extractOperations.
+var contractMethods = []string{"GET", "POST", "PUT", "DELETE", "PATCH", "HEAD", "OPTIONS"}
+
+// pathParamPattern matches {param} placeholders in OpenAPI paths.
+var pathParamPattern = regexp.MustCompile(`\{[^/{}]+\}`)
+
+func newContractOperation(op pathOperation) *contractOperation {
+	var pattern strings.Builder
+	literal := 0
+	last := 0
+	for _, loc := range pathParamPattern.FindAllStringIndex(op.Path, -1) {
+		pattern.WriteString(regexp.QuoteMeta(op.Path[last:loc[0]]))
+		pattern.WriteString(`[^/]+`)
+		literal += loc[0] - last
+		last = loc[1]
+	}
+	pattern.WriteString(regexp.QuoteMeta(op.Path[last:]))
+	literal += len(op.Path) - last
+
+	// Server URLs may add a base path, so match the operation path as a suffix
+	expr := `^(?:/.*)?` + strings.TrimSuffix(pattern.String(), "/") + `/?$`
+	if strings.Trim(op.Path, "/") == "" {
+		expr = `^/?$`
+	}
+
+	return &contractOperation{
+		method:  op.Method,
+		path:    op.Path,
+		pattern: regexp.MustCompile(expr),
+		literal: literal,
+		pointer: "/paths/" + jsonschema.EscapePointerToken(op.Path) + "/" + strings.ToLower(op.Method),
+	}
+}
+
+// Check validates a response to method and rawURL. It returns one check per
+// contract rule that applies; a failed check stops the later ones.
+func (c *Contract) Check(method, rawURL string, status int, contentType string, body []byte) []ContractCheck {
+	method = strings.ToUpper(method)
+	path := rawURL
+	if u, err := url.Parse(rawURL); err == nil {
+		path = u.Path
+	}
+	if path == "" {
+		path = "/"
+	}
+
+	op, methods := c.findOperation(method, path)
+	if op == nil {
+		message := fmt.Sprintf("%s %s is not declared in the spec", method, path)
+		if len(methods) > 0 {
+			message += fmt.Sprintf(" (declared methods: %s)", strings.Join(methods, ", "))
+		}
+		return []ContractCheck{{Name: ContractCheckOperation, Violations: []ContractViolation{{Message: message}}}}
+	}
+	operation := op.method + " " + op.path
+
+	// Status code
+	responsePointer, declared := c.findResponse(op, status)
+	if responsePointer == "" {
+		return []ContractCheck{{Name: ContractCheckStatus, Violations: []ContractViolation{{
+			Message: fmt.Sprintf("status %d is not declared for %s (declared: %s)", status, operation, strings.Join(declared, ", ")),
+		}}}}
+	}
+	checks := []ContractCheck{{Name: ContractCheckStatus}}
+
+	// Content type
+	response, _ := c.resolve(responsePointer)
+	responseObj, _ := response.(map[string]any)
+	content, _ := responseObj["content"].(map[string]any)
+	hasBody := len(strings.TrimSpace(string(body))) > 0
+
+	if len(content) == 0 {
+		if hasBody {
+			checks = append(checks, ContractCheck{Name: ContractCheckContentType, Violations: []ContractViolation{{
+				Message: fmt.Sprintf("response has a body but %s %d declares no content", operation, status),
+			}}})
+		}
+		return checks
+	}
+	if !hasBody {
+		return checks
+	}
+
+	mediaKey := matchMediaType(content, contentType)
+	if mediaKey == "" {
+		mediaTypes := make([]string, 0, len(content))
+		for k := range content {
+			mediaTypes = append(mediaTypes, k)
+		}
+		sort.Strings(mediaTypes)
+		got := contentType
+		if got == "" {
+			got = "none"
+		}
+		checks = append(checks, ContractCheck{Name: ContractCheckContentType, Violations: []ContractViolation{{
+			Message: fmt.Sprintf("content type %s is not declared for %s %d (declared: %s)", got, operation, status, strings.Join(mediaTypes, ", ")),
+		}}})
+		return checks
+	}
+	checks = append(checks, ContractCheck{Name: ContractCheckContentType})
+
+	// Body schema, for JSON media types
+	if !isJSONMediaType(mediaKey) && !isJSONMediaType(contentType) {
+		return checks
+	}
+	media, _ := content[mediaKey].(map[string]any)
+	schema, ok := media["schema"]
+	if !ok {
+		return checks
+	}
+	checks = append(checks, c.checkBody(schema, body))
+
+	return checks
+}
+
+// checkBody validates a JSON body against a response schema.
+func (c *Contract) checkBody(schema any, body []byte) ContractCheck {
+	check := ContractCheck{Name: ContractCheckBody}
+
+	validator, err := jsonschema.New(schema, jsonschema.WithRoot(c.doc), jsonschema.WithDraft(c.draft))
+	if err != nil {
+		check.Violations = append(check.Violations, ContractViolation{Message: "invalid schema in spec: " + err.Error()})
+		return check
+	}
+	errs, err := validator.ValidateJSON(body)
+	if err != nil {
+		check.Violations = append(check.Violations, ContractViolation{Message: "body is not valid JSON"})
+		return check
+	}
+	for _, e := range errs {
+		check.Violations = append(check.Violations, ContractViolation{Pointer: e.Pointer, Message: e.Message})
+	}
+	return check
+}
+
+// findOperation returns the most specific operation whose path matches. When
+// only other methods match the path, it returns them instead.
+func (c *Contract) findOperation(method, path string) (*contractOperation, []string) {
+	var best *contractOperation
+	var methods []string
+	for _, op := range c.operations {
+		if !op.pattern.MatchString(path) {
+			continue
+		}
+		if op.method != method {
+			methods = append(methods, op.method)
+			continue
+		}
+		if best == nil || op.literal > best.literal {
+			best = op
+		}
+	}
+	return best, methods
+}
+
+// findResponse returns the JSON pointer of the response declared for status:
+// an exact code, a range such as 2XX, or default. It also returns the
+// declared keys for error messages.
+func (c *Contract) findResponse(op *contractOperation, status int) (string, []string) {
+	node, _ := c.resolve(op.pointer + "/responses")
+	responses, _ := node.(map[string]any)
+
+	declared := make([]string, 0, len(responses))
+	for key := range responses {
+		declared = append(declared, key)
+	}
+	sort.Strings(declared)
+
+	code := strconv.Itoa(status)
+	candidates := []string{code, code[:1] + "XX", code[:1] + "xx", "default"}
+	for _, key := range candidates {
+		if _, ok := responses[key]; ok {
+			return op.pointer + "/responses/" + jsonschema.EscapePointerToken(key), declared
+		}
+	}
+	return "", declared
+}
+
+// resolve looks up a JSON pointer in the spec, following $ref objects.
+func (c *Contract) resolve(pointer string) (any, bool) {
+	node, ok := jsonschema.ResolvePointer(c.doc, pointer)
+	for i := 0; ok && i < 32; i++ {
+		obj, isObj := node.(map[string]any)
+		ref, hasRef := obj["$ref"].(string)
+		if !isObj || !hasRef || !strings.HasPrefix(ref, "#") {
+			break
+		}
+		node, ok = jsonschema.ResolvePointer(c.doc, strings.TrimPrefix(ref, "#"))
+	}
+	return node, ok
+}
+
+// matchMediaType returns the declared media type key matching contentType,
+// honouring wildcards like application/* and */*.
+func matchMediaType(content map[string]any, contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = strings.ToLower(strings.TrimSpace(contentType))
+	}
+	if mediaType == "" {
+		return ""
+	}
+
+	major, _, _ := strings.Cut(mediaType, "/")
+	var wildcard, catchAll string
+	for key := range content {
+		declared, _, err := mime.ParseMediaType(key)
+		if err != nil {
+			declared = strings.ToLower(key)
+		}
+		switch declared {
+		case mediaType:
+			return key
+		case major + "/*":
+			wildcard = key
+		case "*/*":
+			catchAll = key
+		}
+	}
+	if wildcard != "" {
+		return wildcard
+	}
+	return catchAll
+}
+
+// isJSONMediaType reports whether a media type carries JSON.
+func isJSONMediaType(mediaType string) bool {
+	mediaType, _, _ = strings.Cut(strings.ToLower(mediaType), ";")
+	mediaType = strings.TrimSpace(mediaType)
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
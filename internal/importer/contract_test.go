@@ -0,0 +1,216 @@
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const contractSpec = `
+openapi: 3.0.3
+info:
+  title: Pets
+  version: "1.0"
+servers:
+  - url: https://api.example.com/v1
+paths:
+  /pets:
+    get:
+      responses:
+        200:
+          description: List of pets
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: '#/components/schemas/Pet'
+    post:
+      responses:
+        "201":
+          description: Created
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Pet'
+  /pets/{petId}:
+    get:
+      responses:
+        "200":
+          description: A pet
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Pet'
+        "404":
+          $ref: '#/components/responses/NotFound'
+        4XX:
+          description: Client error
+    delete:
+      responses:
+        "204":
+          description: Deleted
+  /pets/mine:
+    get:
+      responses:
+        "200":
+          description: My pets
+          content:
+            text/plain:
+              schema:
+                type: string
+components:
+  responses:
+    NotFound:
+      description: Not found
+      content:
+        application/problem+json:
+          schema:
+            type: object
+            required: [title]
+  schemas:
+    Pet:
+      type: object
+      required: [id, name]
+      properties:
+        id:
+          type: integer
+          format: int64
+        name:
+          type: string
+        tag:
+          type: string
+          nullable: true
+`
+
+func TestContract_Check(t *testing.T) {
+	contract, err := ParseContract([]byte(contractSpec))
+	require.NoError(t, err)
+
+	names := func(checks []ContractCheck) []string {
+		var out []string
+		for _, c := range checks {
+			out = append(out, c.Name)
+		}
+		return out
+	}
+
+	t.Run("valid response passes every check", func(t *testing.T) {
+		checks := contract.Check("GET", "https://api.example.com/v1/pets/7", 200, "application/json; charset=utf-8",
+			[]byte(`{"id": 7, "name": "Rex", "tag": null}`))
+
+		assert.Equal(t, []string{ContractCheckStatus, ContractCheckContentType, ContractCheckBody}, names(checks))
+		for _, c := range checks {
+			assert.True(t, c.Passed(), "%s: %v", c.Name, c.Violations)
+		}
+	})
+
+	t.Run("reports schema violations with JSON pointers", func(t *testing.T) {
+		checks := contract.Check("get", "http://localhost:8080/v1/pets", 200, "application/json",
+			[]byte(`[{"id": 1, "name": "Rex"}, {"id": "2"}]`))
+
+		require.Len(t, checks, 3)
+		body := checks[2]
+		assert.False(t, body.Passed())
+		var got []string
+		for _, v := range body.Violations {
+			got = append(got, v.String())
+		}
+		assert.Equal(t, []string{`/1: missing required property "name"`, "/1/id: expected integer, got string"}, got)
+	})
+
+	t.Run("undeclared operation", func(t *testing.T) {
+		checks := contract.Check("PATCH", "https://api.example.com/v1/pets/7", 200, "", nil)
+
+		require.Len(t, checks, 1)
+		assert.Equal(t, ContractCheckOperation, checks[0].Name)
+		assert.Contains(t, checks[0].Violations[0].Message, "PATCH /v1/pets/7 is not declared")
+		assert.Contains(t, checks[0].Violations[0].Message, "declared methods: DELETE, GET")
+
+		checks = contract.Check("GET", "https://api.example.com/v1/owners", 200, "", nil)
+		assert.Contains(t, checks[0].Violations[0].Message, "GET /v1/owners is not declared in the spec")
+	})
+
+	t.Run("undeclared status", func(t *testing.T) {
+		checks := contract.Check("GET", "/pets", 500, "application/json", []byte(`{}`))
+
+		require.Len(t, checks, 1)
+		assert.Equal(t, ContractCheckStatus, checks[0].Name)
+		assert.Equal(t, "(root): status 500 is not declared for GET /pets (declared: 200)", checks[0].Violations[0].String())
+	})
+
+	t.Run("status ranges and response refs", func(t *testing.T) {
+		checks := contract.Check("GET", "/pets/1", 404, "application/problem+json", []byte(`{"detail": "gone"}`))
+		require.Len(t, checks, 3)
+		assert.Equal(t, []ContractViolation{{Message: `missing required property "title"`}}, checks[2].Violations)
+
+		checks = contract.Check("GET", "/pets/1", 409, "", nil)
+		require.Len(t, checks, 1)
+		assert.True(t, checks[0].Passed(), "409 matches 4XX")
+	})
+
+	t.Run("undeclared content type", func(t *testing.T) {
+		checks := contract.Check("GET", "/pets/1", 200, "text/html", []byte("<p>hi</p>"))
+
+		require.Len(t, checks, 2)
+		assert.False(t, checks[1].Passed())
+		assert.Contains(t, checks[1].Violations[0].Message, "content type text/html is not declared for GET /pets/{petId} 200 (declared: application/json)")
+	})
+
+	t.Run("body without declared content", func(t *testing.T) {
+		checks := contract.Check("DELETE", "/pets/1", 204, "", nil)
+		assert.Equal(t, []string{ContractCheckStatus}, names(checks))
+
+		checks = contract.Check("DELETE", "/pets/1", 204, "text/plain", []byte("ok"))
+		require.Len(t, checks, 2)
+		assert.Contains(t, checks[1].Violations[0].Message, "declares no content")
+	})
+
+	t.Run("prefers literal paths over templates", func(t *testing.T) {
+		checks := contract.Check("GET", "/pets/mine", 200, "text/plain", []byte("Rex"))
+
+		assert.Equal(t, []string{ContractCheckStatus, ContractCheckContentType}, names(checks))
+		assert.True(t, checks[1].Passed())
+	})
+
+	t.Run("invalid JSON body", func(t *testing.T) {
+		checks := contract.Check("POST", "/pets", 201, "application/json", []byte(`{"id":`))
+		require.Len(t, checks, 3)
+		assert.Equal(t, "body is not valid JSON", checks[2].Violations[0].Message)
+	})
+}
+
+func TestParseContract_Errors(t *testing.T) {
+	_, err := ParseContract([]byte(`swagger: "2.0"`))
+	assert.ErrorIs(t, err, ErrUnsupportedVersion)
+
+	_, err = ParseContract([]byte("{not yaml: ["))
+	assert.ErrorIs(t, err, ErrParseError)
+
+	_, err = LoadContract(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.Error(t, err)
+}
+
+func TestLoadContract(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "openapi.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{
+		"openapi": "3.1.0",
+		"info": {"title": "T", "version": "1"},
+		"paths": {"/": {"get": {"responses": {"200": {"description": "ok",
+			"content": {"application/json": {"schema": {"type": ["object", "null"]}}}}}}}}
+	}`), 0644))
+
+	contract, err := LoadContract(path)
+	require.NoError(t, err)
+
+	checks := contract.Check("GET", "https://example.com/", 200, "application/json", []byte(`null`))
+	require.Len(t, checks, 3)
+	assert.True(t, checks[2].Passed())
+
+	checks = contract.Check("GET", "https://example.com", 200, "application/json", []byte(`[]`))
+	require.Len(t, checks, 3)
+	assert.Equal(t, "expected object or null, got array", checks[2].Violations[0].Message)
+}
@@ -3,6 +3,7 @@ package importer
 import (
 	"context"
 	"fmt"
+	"net/url"
 	"regexp"
 	"strings"
 
@@ -58,7 +59,14 @@ func (c *CurlImporter) Import(ctx context.Context, content []byte) (*core.Collec
 	}
 
 	// Set body
-	if parsed.body != "" {
+	switch {
+	case parsed.bodyFile != "":
+		req.SetBodyBinary(parsed.bodyFile, parsed.headers["Content-Type"])
+	case len(parsed.formFields) > 0:
+		req.SetBodyURLEncoded(parsed.formFields)
+	case parsed.body != "" && isURLEncodedContentType(parsed.headers["Content-Type"]):
+		req.SetBodyURLEncoded(parseURLEncodedFields(parsed.body))
+	case parsed.body != "":
 		req.SetBody(parsed.body)
 	}
 
@@ -72,12 +80,14 @@ func (c *CurlImporter) Import(ctx context.Context, content []byte) (*core.Collec
 }
 
 type parsedCurl struct {
-	name    string
-	method  string
-	url     string
-	headers map[string]string
-	body    string
-	auth    core.AuthConfig
+	name       string
+	method     string
+	url        string
+	headers    map[string]string
+	body       string
+	bodyFile   string           // --data-binary @file
+	formFields []core.FormField // --data-urlencode fields
+	auth       core.AuthConfig
 }
 
 func parseCurlCommand(cmd string) (*parsedCurl, error) {
@@ -126,7 +136,12 @@ func parseCurlCommand(cmd string) (*parsedCurl, error) {
 
 		case "-d", "--data", "--data-raw", "--data-binary":
 			if i+1 < len(tokens) {
-				result.body = tokens[i+1]
+				data := tokens[i+1]
+				if token == "--data-binary" && strings.HasPrefix(data, "@") && len(data) > 1 {
+					result.bodyFile = data[1:]
+				} else {
+					result.body = data
+				}
 				// Data implies POST if not specified
 				if result.method == "GET" {
 					result.method = "POST"
@@ -138,10 +153,7 @@ func parseCurlCommand(cmd string) (*parsedCurl, error) {
 
 		case "--data-urlencode":
 			if i+1 < len(tokens) {
-				if result.body != "" {
-					result.body += "&"
-				}
-				result.body += tokens[i+1]
+				result.formFields = append(result.formFields, parseURLEncodeArg(tokens[i+1]))
 				if result.method == "GET" {
 					result.method = "POST"
 				}
@@ -268,6 +280,39 @@ func parseCurlCommand(cmd string) (*parsedCurl, error) {
 	return result, nil
 }
 
+// parseURLEncodeArg converts a --data-urlencode argument ("name=value",
+// "=value" or "value") into a form field with an unencoded value.
+func parseURLEncodeArg(arg string) core.FormField {
+	if idx := strings.Index(arg, "="); idx >= 0 {
+		return core.FormField{Key: arg[:idx], Value: arg[idx+1:]}
+	}
+	return core.FormField{Key: arg}
+}
+
+// parseURLEncodedFields splits an already-encoded form body into fields,
+// preserving their order.
+func parseURLEncodedFields(body string) []core.FormField {
+	var fields []core.FormField
+	for _, pair := range strings.Split(body, "&") {
+		if pair == "" {
+			continue
+		}
+		key, value, _ := strings.Cut(pair, "=")
+		if k, err := url.QueryUnescape(key); err == nil {
+			key = k
+		}
+		if v, err := url.QueryUnescape(value); err == nil {
+			value = v
+		}
+		fields = append(fields, core.FormField{Key: key, Value: value})
+	}
+	return fields
+}
+
+func isURLEncodedContentType(contentType string) bool {
+	return strings.HasPrefix(strings.ToLower(strings.TrimSpace(contentType)), "application/x-www-form-urlencoded")
+}
+
 // tokenize splits the command respecting quotes
 func tokenize(cmd string) []string {
 	var tokens []string
@@ -4,6 +4,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/artpar/currier/internal/core"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -561,7 +562,36 @@ func TestCurlImporter_Import_AdditionalCases(t *testing.T) {
 
 		req := coll.Requests()[0]
 		assert.Equal(t, "POST", req.Method())
-		assert.Contains(t, req.Body(), "name=John Doe")
+		assert.Equal(t, "urlencoded", req.BodyType())
+		assert.Equal(t, []core.FormField{
+			{Key: "name", Value: "John Doe"},
+			{Key: "city", Value: "New York"},
+		}, req.FormFields())
+	})
+
+	t.Run("data-binary file", func(t *testing.T) {
+		content := []byte(`curl -H "Content-Type: image/png" --data-binary @./logo.png https://api.example.com/upload`)
+		coll, err := imp.Import(ctx, content)
+		require.NoError(t, err)
+
+		req := coll.Requests()[0]
+		assert.Equal(t, "POST", req.Method())
+		assert.Equal(t, "binary", req.BodyType())
+		assert.Equal(t, "./logo.png", req.BodyFile())
+		assert.Equal(t, "image/png", req.BodyContentType())
+	})
+
+	t.Run("data with urlencoded content type", func(t *testing.T) {
+		content := []byte(`curl -H "Content-Type: application/x-www-form-urlencoded" -d "a=1&b=two%20words" https://api.example.com`)
+		coll, err := imp.Import(ctx, content)
+		require.NoError(t, err)
+
+		req := coll.Requests()[0]
+		assert.Equal(t, "urlencoded", req.BodyType())
+		assert.Equal(t, []core.FormField{
+			{Key: "a", Value: "1"},
+			{Key: "b", Value: "two words"},
+		}, req.FormFields())
 	})
 
 	t.Run("get flag after data", func(t *testing.T) {
@@ -779,7 +809,8 @@ func TestCurlImporter_Import_AdditionalCases(t *testing.T) {
 
 		req := coll.Requests()[0]
 		assert.Equal(t, "POST", req.Method())
-		assert.Contains(t, req.Body(), "name=John")
-		assert.Contains(t, req.Body(), "age=30")
+		require.Len(t, req.FormFields(), 2)
+		assert.Equal(t, "name", req.FormFields()[0].Key)
+		assert.Equal(t, "30", req.FormFields()[1].Value)
 	})
 }
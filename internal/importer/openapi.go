@@ -309,8 +309,7 @@ func (o *OpenAPIImporter) createRequest(op pathOperation, baseURL string, compon
 				req.SetBody(example)
 			} else if content, ok := body.Content["application/x-www-form-urlencoded"]; ok {
 				req.SetHeader("Content-Type", "application/x-www-form-urlencoded")
-				example := o.generateFormExample(content.Schema, components)
-				req.SetBody(example)
+				req.SetBodyURLEncoded(o.generateFormFields(content.Schema, components))
 			} else {
 				// Use first available content type
 				for contentType, content := range body.Content {
@@ -531,24 +530,32 @@ func (o *OpenAPIImporter) generateSchemaExample(schema *openAPISchema, component
 	return nil
 }
 
-func (o *OpenAPIImporter) generateFormExample(schema *openAPISchema, components *openAPIComponents) string {
+// generateFormFields builds example urlencoded form fields from an object schema.
+func (o *OpenAPIImporter) generateFormFields(schema *openAPISchema, components *openAPIComponents) []core.FormField {
 	if schema == nil {
-		return ""
+		return nil
 	}
 
 	schema = o.resolveSchemaRef(schema, components)
 	if schema == nil {
-		return ""
+		return nil
 	}
 
-	var pairs []string
-	for propName, propSchema := range schema.Properties {
-		value := o.getExampleValue(&propSchema, nil)
-		pairs = append(pairs, url.QueryEscape(propName)+"="+url.QueryEscape(value))
+	names := make([]string, 0, len(schema.Properties))
+	for propName := range schema.Properties {
+		names = append(names, propName)
 	}
+	sort.Strings(names) // Deterministic order
 
-	sort.Strings(pairs) // Deterministic order
-	return strings.Join(pairs, "&")
+	fields := make([]core.FormField, 0, len(names))
+	for _, propName := range names {
+		propSchema := schema.Properties[propName]
+		fields = append(fields, core.FormField{
+			Key:   propName,
+			Value: o.getExampleValue(&propSchema, nil),
+		})
+	}
+	return fields
 }
 
 func (o *OpenAPIImporter) extractAuth(security []map[string][]string, schemes map[string]openAPISecurityScheme) core.AuthConfig {
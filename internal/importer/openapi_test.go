@@ -626,8 +626,10 @@ func TestOpenAPIImporter_Import_FormURLEncoded(t *testing.T) {
 
 	req := coll.Requests()[0]
 	assert.Equal(t, "application/x-www-form-urlencoded", req.GetHeader("Content-Type"))
-	assert.Contains(t, req.Body(), "username=")
-	assert.Contains(t, req.Body(), "password=")
+	assert.Equal(t, "urlencoded", req.BodyType())
+	require.Len(t, req.FormFields(), 2)
+	assert.Equal(t, "password", req.FormFields()[0].Key)
+	assert.Equal(t, "username", req.FormFields()[1].Key)
 }
 
 func TestOpenAPIImporter_Import_WithParameterRef(t *testing.T) {
@@ -103,6 +103,9 @@ func (p *PostmanImporter) importItem(coll *core.Collection, folder *core.Folder,
 			newFolder = folder.AddFolder(item.Name)
 		}
 		newFolder.SetDescription(item.Description)
+		for _, v := range item.Variable {
+			newFolder.SetVariable(v.Key, v.Value)
+		}
 
 		// Recursively import sub-items
 		for _, subItem := range item.Item {
@@ -149,18 +152,36 @@ func (p *PostmanImporter) convertRequest(item postmanItem) *core.RequestDefiniti
 		switch pm.Body.Mode {
 		case "raw":
 			req.SetBody(pm.Body.Raw)
+			if pm.Body.Options != nil {
+				if ct := postmanLanguageContentType(pm.Body.Options.Raw.Language); ct != "" {
+					req.SetBodyRaw(pm.Body.Raw, ct)
+				}
+			}
 		case "urlencoded":
-			var pairs []string
+			var fields []core.FormField
 			for _, p := range pm.Body.URLEncoded {
 				if !p.Disabled {
-					pairs = append(pairs, fmt.Sprintf("%s=%s", p.Key, p.Value))
+					fields = append(fields, core.FormField{Key: p.Key, Value: p.Value})
 				}
 			}
-			req.SetBody(strings.Join(pairs, "&"))
+			req.SetBodyURLEncoded(fields)
 		case "formdata":
-			// Store as JSON representation for now
-			data, _ := json.Marshal(pm.Body.FormData)
-			req.SetBody(string(data))
+			var fields []core.FormField
+			for _, fd := range pm.Body.FormData {
+				if fd.Disabled {
+					continue
+				}
+				if fd.Type == "file" {
+					fields = append(fields, core.FormField{Key: fd.Key, IsFile: true, FilePath: fd.Src})
+				} else {
+					fields = append(fields, core.FormField{Key: fd.Key, Value: fd.Value})
+				}
+			}
+			req.SetBodyFormData(fields)
+		case "file":
+			if pm.Body.File != nil && pm.Body.File.Src != "" {
+				req.SetBodyBinary(pm.Body.File.Src, "")
+			}
 		case "graphql":
 			if pm.Body.GraphQL != nil {
 				body := map[string]interface{}{
@@ -197,6 +218,24 @@ func (p *PostmanImporter) convertRequest(item postmanItem) *core.RequestDefiniti
 	return req
 }
 
+// postmanLanguageContentType maps a Postman raw body language to a content type.
+func postmanLanguageContentType(language string) string {
+	switch language {
+	case "json":
+		return "application/json"
+	case "xml":
+		return "application/xml"
+	case "html":
+		return "text/html"
+	case "javascript":
+		return "application/javascript"
+	case "text":
+		return "text/plain"
+	default:
+		return ""
+	}
+}
+
 func extractURL(url interface{}) string {
 	switch v := url.(type) {
 	case string:
@@ -343,6 +382,7 @@ type postmanItem struct {
 	Request     *postmanRequest `json:"request,omitempty"`
 	Response    []interface{}   `json:"response,omitempty"`
 	Event       []postmanEvent  `json:"event,omitempty"`
+	Variable    []postmanVar    `json:"variable,omitempty"`
 }
 
 type postmanRequest struct {
@@ -366,9 +406,14 @@ type postmanBody struct {
 	URLEncoded []postmanURLEncoded  `json:"urlencoded,omitempty"`
 	FormData   []postmanFormData    `json:"formdata,omitempty"`
 	GraphQL    *postmanGraphQL      `json:"graphql,omitempty"`
+	File       *postmanFile         `json:"file,omitempty"`
 	Options    *postmanBodyOptions  `json:"options,omitempty"`
 }
 
+type postmanFile struct {
+	Src string `json:"src,omitempty"`
+}
+
 type postmanURLEncoded struct {
 	Key      string `json:"key"`
 	Value    string `json:"value"`
@@ -4,6 +4,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/artpar/currier/internal/core"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -166,6 +167,7 @@ func TestPostmanImporter_Import_WithFolders(t *testing.T) {
 			{
 				"name": "Users",
 				"description": "User endpoints",
+				"variable": [{"key": "resource", "value": "users"}],
 				"item": [
 					{
 						"name": "Get Users",
@@ -199,6 +201,7 @@ func TestPostmanImporter_Import_WithFolders(t *testing.T) {
 
 	assert.Equal(t, "Users", folders[0].Name())
 	assert.Equal(t, "User endpoints", folders[0].Description())
+	assert.Equal(t, "users", folders[0].GetVariable("resource"))
 	require.Len(t, folders[0].Requests(), 1)
 	assert.Equal(t, "Get Users", folders[0].Requests()[0].Name())
 
@@ -500,7 +503,11 @@ func TestPostmanImporter_Import_URLEncodedBody(t *testing.T) {
 
 	requests := coll.Requests()
 	require.Len(t, requests, 1)
-	assert.Equal(t, "username=john&password=secret", requests[0].Body())
+	assert.Equal(t, "urlencoded", requests[0].BodyType())
+	assert.Equal(t, []core.FormField{
+		{Key: "username", Value: "john"},
+		{Key: "password", Value: "secret"},
+	}, requests[0].FormFields())
 }
 
 func TestPostmanImporter_Import_InvalidJSON(t *testing.T) {
@@ -755,8 +762,11 @@ func TestPostmanImporter_Import_FormDataBody(t *testing.T) {
 	requests := coll.Requests()
 	require.Len(t, requests, 1)
 	assert.Equal(t, "POST", requests[0].Method())
-	// Body should contain the formdata fields as JSON
-	assert.Contains(t, requests[0].Body(), "file")
+	assert.Equal(t, "form", requests[0].BodyType())
+	assert.Equal(t, []core.FormField{
+		{Key: "file", IsFile: true, FilePath: "/path/to/file.txt"},
+		{Key: "name", Value: "document.txt"},
+	}, requests[0].FormFields())
 }
 
 func TestPostmanImporter_Import_GraphQLBody(t *testing.T) {
@@ -875,3 +885,44 @@ func TestPostmanImporter_Import_WithPreAndPostScripts(t *testing.T) {
 	assert.Contains(t, requests[0].PreScript(), "pre-request")
 	assert.Contains(t, requests[0].PostScript(), "pm.test")
 }
+
+func TestPostmanImporter_Import_FileAndRawLanguageBodies(t *testing.T) {
+	imp := NewPostmanImporter()
+	ctx := context.Background()
+
+	content := []byte(`{
+		"info": {
+			"name": "Test",
+			"schema": "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+		},
+		"item": [
+			{
+				"name": "Upload",
+				"request": {
+					"method": "PUT",
+					"url": "https://example.com/blob",
+					"body": {"mode": "file", "file": {"src": "/tmp/data.bin"}}
+				}
+			},
+			{
+				"name": "XML",
+				"request": {
+					"method": "POST",
+					"url": "https://example.com/xml",
+					"body": {"mode": "raw", "raw": "<a/>", "options": {"raw": {"language": "xml"}}}
+				}
+			}
+		]
+	}`)
+
+	coll, err := imp.Import(ctx, content)
+	require.NoError(t, err)
+
+	requests := coll.Requests()
+	require.Len(t, requests, 2)
+	assert.Equal(t, "binary", requests[0].BodyType())
+	assert.Equal(t, "/tmp/data.bin", requests[0].BodyFile())
+	assert.Equal(t, "raw", requests[1].BodyType())
+	assert.Equal(t, "<a/>", requests[1].Body())
+	assert.Equal(t, "application/xml", requests[1].BodyContentType())
+}
@@ -0,0 +1,479 @@
+package interpolate
+
+import (
+	"encoding/base64"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/artpar/currier/internal/secrets"
+	"github.com/google/uuid"
+)
+
+// random is the random source behind dynamic variables. Seeding it makes
+// the values of a run reproducible. It is safe for concurrent use.
+type random struct {
+	mu sync.Mutex
+	r  *rand.Rand
+}
+
+// newRandom creates a source seeded with seed.
+func newRandom(seed uint64) *random {
+	return &random{r: rand.New(rand.NewPCG(seed, seed^0x9e3779b97f4a7c15))}
+}
+
+// seed restarts the source from seed.
+func (r *random) seed(seed uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.r = rand.New(rand.NewPCG(seed, seed^0x9e3779b97f4a7c15))
+}
+
+// intn returns a number in [0, n).
+func (r *random) intn(n int) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.r.IntN(n)
+}
+
+// between returns a number in [min, max].
+func (r *random) between(min, max int) int {
+	return min + r.intn(max-min+1)
+}
+
+// float returns a number in [0, 1).
+func (r *random) float() float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.r.Float64()
+}
+
+// bytes returns n random bytes.
+func (r *random) bytes(n int) []byte {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte(r.r.UintN(256))
+	}
+	return b
+}
+
+// pick returns a random element of list.
+func (r *random) pick(list []string) string {
+	return list[r.intn(len(list))]
+}
+
+// chars returns n characters drawn from alphabet.
+func (r *random) chars(alphabet string, n int) string {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = alphabet[r.intn(len(alphabet))]
+	}
+	return string(b)
+}
+
+// uuid returns a version 4 UUID drawn from the source.
+func (r *random) uuid() string {
+	b := r.bytes(16)
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return uuid.Must(uuid.FromBytes(b)).String()
+}
+
+// dynamicFunc generates the value of a dynamic variable from its arguments,
+// as in {{$randomInt 1 100}}.
+type dynamicFunc func(r *random, args []string) (string, error)
+
+// fixed adapts a generator without arguments.
+func fixed(fn func(r *random) string) dynamicFunc {
+	return func(r *random, args []string) (string, error) {
+		if len(args) > 0 {
+			return "", fmt.Errorf("takes no arguments")
+		}
+		return fn(r), nil
+	}
+}
+
+// secret resolves a reference to a secret kept outside Currier, such as
+// {{$secret "env:API_KEY"}}, when the expression is interpolated.
+func secret(_ *random, args []string) (string, error) {
+	if len(args) != 1 {
+		return "", fmt.Errorf("takes one secret reference, such as \"env:API_KEY\"")
+	}
+	return secrets.Resolve(args[0])
+}
+
+// choice picks from a word list.
+func choice(list []string) dynamicFunc {
+	return fixed(func(r *random) string { return r.pick(list) })
+}
+
+// intArgs parses up to len(defaults) integer arguments, using defaults for
+// the missing ones.
+func intArgs(args []string, defaults ...int) ([]int, error) {
+	if len(args) > len(defaults) {
+		return nil, fmt.Errorf("takes at most %d argument(s)", len(defaults))
+	}
+	values := append([]int(nil), defaults...)
+	for i, arg := range args {
+		n, err := strconv.Atoi(arg)
+		if err != nil {
+			return nil, fmt.Errorf("argument %q is not an integer", arg)
+		}
+		values[i] = n
+	}
+	return values, nil
+}
+
+// ranged generates a value from an inclusive [min, max] range argument pair.
+func ranged(min, max int, fn func(r *random, min, max int) string) dynamicFunc {
+	return func(r *random, args []string) (string, error) {
+		bounds, err := intArgs(args, min, max)
+		if err != nil {
+			return "", err
+		}
+		if bounds[0] > bounds[1] {
+			return "", fmt.Errorf("min %d is greater than max %d", bounds[0], bounds[1])
+		}
+		return fn(r, bounds[0], bounds[1]), nil
+	}
+}
+
+// counted generates a value from an optional count argument.
+func counted(defaultCount int, fn func(r *random, n int) string) dynamicFunc {
+	return func(r *random, args []string) (string, error) {
+		count, err := intArgs(args, defaultCount)
+		if err != nil {
+			return "", err
+		}
+		if count[0] < 1 || count[0] > 1000 {
+			return "", fmt.Errorf("count must be between 1 and 1000")
+		}
+		return fn(r, count[0]), nil
+	}
+}
+
+// words joins n words picked from list.
+func words(r *random, list []string, n int, sep string) string {
+	picked := make([]string, n)
+	for i := range picked {
+		picked[i] = r.pick(list)
+	}
+	return strings.Join(picked, sep)
+}
+
+// sentence builds a capitalized sentence of lorem words.
+func sentence(r *random) string {
+	s := words(r, loremWords, r.between(5, 10), " ")
+	return strings.ToUpper(s[:1]) + s[1:] + "."
+}
+
+// paragraph builds a paragraph of lorem sentences.
+func paragraph(r *random) string {
+	sentences := make([]string, r.between(3, 5))
+	for i := range sentences {
+		sentences[i] = sentence(r)
+	}
+	return strings.Join(sentences, " ")
+}
+
+// daysFrom returns a time offset from now by a random number of days in
+// [min, max], as an ISO timestamp.
+func daysFrom(min, max int) dynamicFunc {
+	return fixed(func(r *random) string {
+		offset := time.Duration(r.between(min*24*60, max*24*60)) * time.Minute
+		return time.Now().Add(offset).UTC().Format(time.RFC3339)
+	})
+}
+
+// image returns an image URL for a category.
+func image(category string) dynamicFunc {
+	return fixed(func(r *random) string {
+		return fmt.Sprintf("https://picsum.photos/seed/%s-%d/640/480", category, r.intn(100000))
+	})
+}
+
+const (
+	lowerAlphaNumeric = "abcdefghijklmnopqrstuvwxyz0123456789"
+	hexDigits         = "0123456789abcdef"
+	passwordChars     = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789!@#$%^&*"
+)
+
+// dynamicVariables are the {{$...}} variables compatible with Postman's
+// dynamic variables, plus currier's own ($uuid, $date, $secret).
+var dynamicVariables = map[string]dynamicFunc{
+	// Common
+	"$guid":         fixed(func(r *random) string { return r.uuid() }),
+	"$uuid":         fixed(func(r *random) string { return r.uuid() }),
+	"$randomUUID":   fixed(func(r *random) string { return r.uuid() }),
+	"$timestamp":    fixed(func(*random) string { return strconv.FormatInt(time.Now().Unix(), 10) }),
+	"$isoTimestamp": fixed(func(*random) string { return time.Now().UTC().Format("2006-01-02T15:04:05.000Z") }),
+	"$date":         fixed(func(*random) string { return time.Now().Format("2006-01-02") }),
+	"$secret":       secret,
+
+	// Text, numbers and colors
+	"$randomAlphaNumeric": counted(1, func(r *random, n int) string { return r.chars(lowerAlphaNumeric, n) }),
+	"$randomBoolean":      fixed(func(r *random) string { return strconv.FormatBool(r.intn(2) == 1) }),
+	"$randomInt":          ranged(0, 1000, func(r *random, min, max int) string { return strconv.Itoa(r.between(min, max)) }),
+	"$randomColor":        choice(colors),
+	"$randomHexColor":     fixed(func(r *random) string { return "#" + r.chars(hexDigits, 6) }),
+	"$randomAbbreviation": choice(abbreviations),
+
+	// Internet and IP addresses
+	"$randomIP": fixed(func(r *random) string {
+		return fmt.Sprintf("%d.%d.%d.%d", r.between(1, 254), r.intn(256), r.intn(256), r.between(1, 254))
+	}),
+	"$randomIPV6": fixed(func(r *random) string {
+		groups := make([]string, 8)
+		for i := range groups {
+			groups[i] = r.chars(hexDigits, 4)
+		}
+		return strings.Join(groups, ":")
+	}),
+	"$randomMACAddress": fixed(func(r *random) string {
+		parts := make([]string, 6)
+		for i := range parts {
+			parts[i] = r.chars(hexDigits, 2)
+		}
+		return strings.Join(parts, ":")
+	}),
+	"$randomPassword":  counted(15, func(r *random, n int) string { return r.chars(passwordChars, n) }),
+	"$randomLocale":    choice(locales),
+	"$randomUserAgent": choice(userAgents),
+	"$randomProtocol":  choice([]string{"http", "https"}),
+	"$randomSemver": fixed(func(r *random) string {
+		return fmt.Sprintf("%d.%d.%d", r.intn(10), r.intn(20), r.intn(50))
+	}),
+
+	// Names
+	"$randomFirstName":  choice(firstNames),
+	"$randomLastName":   choice(lastNames),
+	"$randomFullName":   fixed(func(r *random) string { return r.pick(firstNames) + " " + r.pick(lastNames) }),
+	"$randomName":       fixed(func(r *random) string { return r.pick(firstNames) + " " + r.pick(lastNames) }),
+	"$randomNamePrefix": choice([]string{"Mr.", "Mrs.", "Ms.", "Miss", "Dr."}),
+	"$randomNameSuffix": choice([]string{"Jr.", "Sr.", "I", "II", "III", "IV", "V", "MD", "DDS", "PhD", "DVM"}),
+
+	// Profession
+	"$randomJobArea":       choice(jobAreas),
+	"$randomJobDescriptor": choice(jobDescriptors),
+	"$randomJobTitle": fixed(func(r *random) string {
+		return r.pick(jobDescriptors) + " " + r.pick(jobAreas) + " " + r.pick(jobTypes)
+	}),
+	"$randomJobType": choice(jobTypes),
+
+	// Phone, address and location
+	"$randomPhoneNumber": fixed(func(r *random) string {
+		return fmt.Sprintf("%03d-%03d-%04d", r.between(200, 999), r.intn(1000), r.intn(10000))
+	}),
+	"$randomPhoneNumberExt": fixed(func(r *random) string {
+		return fmt.Sprintf("%d-%03d-%03d-%04d", r.between(1, 99), r.between(200, 999), r.intn(1000), r.intn(10000))
+	}),
+	"$randomCity":       choice(cities),
+	"$randomStreetName": fixed(func(r *random) string { return r.pick(lastNames) + " " + r.pick(streetSuffixes) }),
+	"$randomStreetAddress": fixed(func(r *random) string {
+		return fmt.Sprintf("%d %s %s", r.between(1, 99999), r.pick(lastNames), r.pick(streetSuffixes))
+	}),
+	"$randomCountry":     choice(countries),
+	"$randomCountryCode": choice(countryCodes),
+	"$randomLatitude":    fixed(func(r *random) string { return fmt.Sprintf("%.4f", r.float()*180-90) }),
+	"$randomLongitude":   fixed(func(r *random) string { return fmt.Sprintf("%.4f", r.float()*360-180) }),
+
+	// Images
+	"$randomAvatarImage":    image("avatar"),
+	"$randomImageUrl":       image("image"),
+	"$randomAbstractImage":  image("abstract"),
+	"$randomAnimalsImage":   image("animals"),
+	"$randomBusinessImage":  image("business"),
+	"$randomCatsImage":      image("cats"),
+	"$randomCityImage":      image("city"),
+	"$randomFoodImage":      image("food"),
+	"$randomNightlifeImage": image("nightlife"),
+	"$randomFashionImage":   image("fashion"),
+	"$randomPeopleImage":    image("people"),
+	"$randomNatureImage":    image("nature"),
+	"$randomSportsImage":    image("sports"),
+	"$randomTransportImage": image("transport"),
+	"$randomImageDataUri": fixed(func(r *random) string {
+		svg := fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="64" height="64"><rect width="64" height="64" fill="#%s"/></svg>`, r.chars(hexDigits, 6))
+		return "data:image/svg+xml;base64," + base64.StdEncoding.EncodeToString([]byte(svg))
+	}),
+
+	// Finance
+	"$randomBankAccount":     fixed(func(r *random) string { return r.chars("0123456789", 8) }),
+	"$randomBankAccountName": choice([]string{"Checking Account", "Savings Account", "Money Market Account", "Investment Account", "Credit Card Account", "Personal Loan Account", "Home Loan Account", "Auto Loan Account"}),
+	"$randomCreditCardMask":  fixed(func(r *random) string { return r.chars("0123456789", 4) }),
+	"$randomBankAccountBic": fixed(func(r *random) string {
+		return r.chars("ABCDEFGHIJKLMNOPQRSTUVWXYZ", 4) + r.pick(countryCodes) + r.chars("ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789", 2)
+	}),
+	"$randomBankAccountIban": fixed(func(r *random) string {
+		return r.pick(countryCodes) + r.chars("0123456789", 2) + r.chars("ABCDEFGHIJKLMNOPQRSTUVWXYZ", 4) + r.chars("0123456789", 14)
+	}),
+	"$randomTransactionType": choice([]string{"deposit", "withdrawal", "payment", "invoice"}),
+	"$randomCurrencyCode":    choice([]string{"USD", "EUR", "GBP", "JPY", "CHF", "CAD", "AUD", "CNY", "INR", "BRL", "MXN", "SEK"}),
+	"$randomCurrencyName":    choice([]string{"US Dollar", "Euro", "Pound Sterling", "Yen", "Swiss Franc", "Canadian Dollar", "Australian Dollar", "Yuan Renminbi", "Indian Rupee", "Brazilian Real"}),
+	"$randomCurrencySymbol":  choice([]string{"$", "€", "£", "¥", "₹", "₩", "₽", "R$", "Fr"}),
+	"$randomBitcoin": fixed(func(r *random) string {
+		return "1" + r.chars("123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz", 33)
+	}),
+
+	// Business
+	"$randomCompanyName":   fixed(func(r *random) string { return r.pick(lastNames) + " " + r.pick(companySuffixes) }),
+	"$randomCompanySuffix": choice(companySuffixes),
+	"$randomBs": fixed(func(r *random) string {
+		return r.pick(bsBuzz) + " " + r.pick(bsAdjectives) + " " + r.pick(bsNouns)
+	}),
+	"$randomBsAdjective": choice(bsAdjectives),
+	"$randomBsBuzz":      choice(bsBuzz),
+	"$randomBsNoun":      choice(bsNouns),
+
+	// Catchphrases
+	"$randomCatchPhrase": fixed(func(r *random) string {
+		return r.pick(catchPhraseAdjectives) + " " + r.pick(catchPhraseDescriptors) + " " + r.pick(catchPhraseNouns)
+	}),
+	"$randomCatchPhraseAdjective":  choice(catchPhraseAdjectives),
+	"$randomCatchPhraseDescriptor": choice(catchPhraseDescriptors),
+	"$randomCatchPhraseNoun":       choice(catchPhraseNouns),
+
+	// Databases
+	"$randomDatabaseColumn":    choice([]string{"id", "title", "name", "email", "password", "token", "group", "category", "status", "comment", "createdAt", "updatedAt"}),
+	"$randomDatabaseType":      choice([]string{"int", "varchar", "text", "date", "datetime", "timestamp", "boolean", "float", "double", "decimal", "bigint", "blob", "json"}),
+	"$randomDatabaseCollation": choice([]string{"utf8_unicode_ci", "utf8_general_ci", "utf8_bin", "ascii_bin", "ascii_general_ci", "cp1250_bin", "cp1250_general_ci"}),
+	"$randomDatabaseEngine":    choice([]string{"InnoDB", "MyISAM", "MEMORY", "CSV", "BLACKHOLE", "ARCHIVE"}),
+
+	// Dates
+	"$randomDateFuture": daysFrom(1, 365),
+	"$randomDatePast":   daysFrom(-365, -1),
+	"$randomDateRecent": daysFrom(-1, 0),
+	"$randomWeekday":    choice([]string{"Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday", "Sunday"}),
+	"$randomMonth":      choice([]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"}),
+
+	// Domains, emails and usernames
+	"$randomDomainName":   fixed(func(r *random) string { return r.pick(domainWords) + "." + r.pick(domainSuffixes) }),
+	"$randomDomainSuffix": choice(domainSuffixes),
+	"$randomDomainWord":   choice(domainWords),
+	"$randomEmail": fixed(func(r *random) string {
+		return strings.ToLower(r.pick(firstNames)+"."+r.pick(lastNames)) + strconv.Itoa(r.intn(100)) + "@" + r.pick(emailDomains)
+	}),
+	"$randomExampleEmail": fixed(func(r *random) string {
+		return strings.ToLower(r.pick(firstNames)+"."+r.pick(lastNames)) + strconv.Itoa(r.intn(100)) + "@example." + r.pick([]string{"com", "net", "org"})
+	}),
+	"$randomUserName": fixed(func(r *random) string {
+		return r.pick(firstNames) + r.pick([]string{".", "_", ""}) + r.pick(lastNames) + strconv.Itoa(r.intn(100))
+	}),
+	"$randomUrl": fixed(func(r *random) string {
+		return "https://" + r.pick(domainWords) + "." + r.pick(domainSuffixes)
+	}),
+
+	// Files and directories
+	"$randomFileName": fixed(func(r *random) string {
+		return words(r, loremWords, 2, "_") + "." + r.pick(fileExtensions)
+	}),
+	"$randomFileType":       choice([]string{"application", "audio", "image", "text", "video", "font", "model"}),
+	"$randomFileExt":        choice(fileExtensions),
+	"$randomCommonFileName": fixed(func(r *random) string { return words(r, loremWords, 2, "_") + "." + r.pick(commonFileExtensions) }),
+	"$randomCommonFileType": choice([]string{"application", "audio", "image", "text", "video"}),
+	"$randomCommonFileExt":  choice(commonFileExtensions),
+	"$randomFilePath": fixed(func(r *random) string {
+		return r.pick(directories) + "/" + words(r, loremWords, 2, "_") + "." + r.pick(fileExtensions)
+	}),
+	"$randomDirectoryPath": choice(directories),
+	"$randomMimeType":      choice([]string{"application/json", "application/xml", "application/pdf", "application/zip", "text/plain", "text/html", "text/csv", "image/png", "image/jpeg", "image/gif", "audio/mpeg", "video/mp4"}),
+
+	// Stores
+	"$randomPrice": fixed(func(r *random) string {
+		return fmt.Sprintf("%d.%02d", r.between(1, 999), r.intn(100))
+	}),
+	"$randomProduct":          choice(products),
+	"$randomProductAdjective": choice(productAdjectives),
+	"$randomProductMaterial":  choice(productMaterials),
+	"$randomProductName": fixed(func(r *random) string {
+		return r.pick(productAdjectives) + " " + r.pick(productMaterials) + " " + r.pick(products)
+	}),
+	"$randomDepartment": choice([]string{"Books", "Movies", "Music", "Games", "Electronics", "Computers", "Home", "Garden", "Tools", "Grocery", "Health", "Beauty", "Toys", "Kids", "Baby", "Clothing", "Shoes", "Jewelery", "Sports", "Outdoors", "Automotive", "Industrial"}),
+
+	// Grammar
+	"$randomNoun":      choice(nouns),
+	"$randomVerb":      choice(verbs),
+	"$randomIngverb":   choice(ingVerbs),
+	"$randomAdjective": choice(adjectives),
+	"$randomWord":      choice(loremWords),
+	"$randomWords":     counted(3, func(r *random, n int) string { return words(r, loremWords, n, " ") }),
+	"$randomPhrase": fixed(func(r *random) string {
+		return "If we " + r.pick(verbs) + " the " + r.pick(nouns) + ", we can get to the " + r.pick(adjectives) + " " + r.pick(nouns) + " through the " + r.pick(adjectives) + " " + r.pick(nouns) + "!"
+	}),
+
+	// Lorem ipsum
+	"$randomLoremWord":      choice(loremWords),
+	"$randomLoremWords":     counted(3, func(r *random, n int) string { return words(r, loremWords, n, " ") }),
+	"$randomLoremSentence":  fixed(sentence),
+	"$randomLoremSentences": counted(3, func(r *random, n int) string { return repeat(r, n, " ", sentence) }),
+	"$randomLoremParagraph": fixed(paragraph),
+	"$randomLoremParagraphs": counted(3, func(r *random, n int) string {
+		return repeat(r, n, "\n\n", paragraph)
+	}),
+	"$randomLoremText":  fixed(func(r *random) string { return sentence(r) + " " + sentence(r) }),
+	"$randomLoremSlug":  counted(3, func(r *random, n int) string { return words(r, loremWords, n, "-") }),
+	"$randomLoremLines": counted(3, func(r *random, n int) string { return repeat(r, n, "\n", sentence) }),
+}
+
+// repeat joins n generated values.
+func repeat(r *random, n int, sep string, fn func(*random) string) string {
+	parts := make([]string, n)
+	for i := range parts {
+		parts[i] = fn(r)
+	}
+	return strings.Join(parts, sep)
+}
+
+var (
+	colors        = []string{"red", "orange", "yellow", "green", "blue", "indigo", "violet", "purple", "pink", "black", "white", "grey", "silver", "gold", "teal", "cyan", "magenta", "maroon", "olive", "navy"}
+	abbreviations = []string{"API", "HTTP", "SQL", "JSON", "XML", "TCP", "UDP", "SSL", "TLS", "CSS", "HTML", "SMTP", "FTP", "SSH", "PCI", "AGP", "RAM", "SAS", "SCSI", "USB"}
+	locales       = []string{"en", "en_US", "en_GB", "de", "fr", "es", "it", "pt_BR", "nl", "sv", "pl", "ru", "ja", "ko", "zh_CN", "tr"}
+	userAgents    = []string{
+		"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+		"Mozilla/5.0 (Macintosh; Intel Mac OS X 14_2) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.2 Safari/605.1.15",
+		"Mozilla/5.0 (X11; Linux x86_64; rv:121.0) Gecko/20100101 Firefox/121.0",
+		"Mozilla/5.0 (iPhone; CPU iPhone OS 17_2 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.2 Mobile/15E148 Safari/604.1",
+		"Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36",
+	}
+	firstNames = []string{"Alice", "Bob", "Charlie", "Diana", "Eve", "Frank", "Grace", "Henry", "Ivy", "Jack", "Karen", "Liam", "Maya", "Noah", "Olivia", "Peter", "Quinn", "Rosa", "Sam", "Tara", "Umar", "Vera", "Walter", "Xena", "Yusuf", "Zoe"}
+	lastNames  = []string{"Smith", "Johnson", "Williams", "Brown", "Jones", "Garcia", "Miller", "Davis", "Rodriguez", "Martinez", "Hernandez", "Lopez", "Wilson", "Anderson", "Thomas", "Taylor", "Moore", "Jackson", "Martin", "Lee", "Thompson", "White", "Harris", "Clark"}
+
+	jobAreas       = []string{"Solutions", "Program", "Brand", "Security", "Research", "Marketing", "Directives", "Implementation", "Integration", "Functionality", "Response", "Paradigm", "Tactics", "Identity", "Markets", "Group", "Division", "Applications", "Optimization", "Operations", "Infrastructure", "Intranet", "Communications", "Web", "Branding", "Quality", "Assurance", "Mobility", "Accounts", "Factors", "Creative", "Configuration", "Accountability", "Interactions", "Usability", "Metrics"}
+	jobDescriptors = []string{"Lead", "Senior", "Direct", "Corporate", "Dynamic", "Future", "Product", "National", "Regional", "District", "Central", "Global", "Customer", "Investor", "Internal", "International", "Legacy", "Forward", "Chief", "Principal"}
+	jobTypes       = []string{"Supervisor", "Associate", "Executive", "Liaison", "Officer", "Manager", "Engineer", "Specialist", "Director", "Coordinator", "Administrator", "Architect", "Analyst", "Designer", "Planner", "Orchestrator", "Technician", "Developer", "Producer", "Consultant", "Assistant", "Facilitator", "Agent", "Representative", "Strategist"}
+
+	cities         = []string{"Springfield", "Riverside", "Franklin", "Greenville", "Bristol", "Clinton", "Fairview", "Salem", "Madison", "Georgetown", "Arlington", "Ashland", "Oxford", "Jackson", "Burlington", "Manchester", "Milton", "Newport", "Auburn", "Dayton"}
+	streetSuffixes = []string{"Street", "Avenue", "Road", "Boulevard", "Lane", "Drive", "Court", "Place", "Square", "Way", "Terrace", "Parkway"}
+	countries      = []string{"United States", "Canada", "Mexico", "Brazil", "Argentina", "United Kingdom", "Ireland", "France", "Germany", "Spain", "Portugal", "Italy", "Netherlands", "Sweden", "Norway", "Poland", "India", "China", "Japan", "South Korea", "Australia", "New Zealand", "South Africa", "Nigeria", "Egypt"}
+	countryCodes   = []string{"US", "CA", "MX", "BR", "AR", "GB", "IE", "FR", "DE", "ES", "PT", "IT", "NL", "SE", "NO", "PL", "IN", "CN", "JP", "KR", "AU", "NZ", "ZA", "NG", "EG"}
+
+	companySuffixes        = []string{"Inc", "and Sons", "LLC", "Group", "Corp", "Ltd"}
+	bsAdjectives           = []string{"clicks-and-mortar", "value-added", "vertical", "proactive", "robust", "revolutionary", "scalable", "leading-edge", "innovative", "intuitive", "strategic", "e-business", "mission-critical", "sticky", "one-to-one", "24/7", "end-to-end", "global", "B2B", "B2C", "granular", "frictionless", "virtual", "viral", "dynamic", "real-time", "cross-platform", "wireless", "best-of-breed", "killer", "magnetic", "bleeding-edge", "web-enabled", "interactive", "dot-com", "seamless", "next-generation", "holistic", "synergistic"}
+	bsBuzz                 = []string{"synergize", "strategize", "empower", "enable", "orchestrate", "leverage", "reinvent", "aggregate", "architect", "enhance", "incentivize", "morph", "transform", "integrate", "iterate", "embrace", "monetize", "harness", "facilitate", "seize", "disintermediate", "utilize", "extend", "deploy", "streamline", "maximize", "implement", "generate", "engage", "scale", "target", "optimize", "evolve", "brand", "grow", "cultivate", "redefine", "recontextualize"}
+	bsNouns                = []string{"synergies", "web-readiness", "paradigms", "markets", "partnerships", "infrastructures", "platforms", "initiatives", "channels", "eyeballs", "communities", "ROI", "solutions", "action-items", "portals", "niches", "technologies", "content", "supply-chains", "convergence", "relationships", "architectures", "interfaces", "e-markets", "e-commerce", "systems", "bandwidth", "models", "mindshare", "deliverables", "users", "schemas", "networks", "applications", "metrics", "functionalities", "experiences", "web services", "methodologies"}
+	catchPhraseAdjectives  = []string{"Adaptive", "Advanced", "Ameliorated", "Assimilated", "Automated", "Balanced", "Business-focused", "Centralized", "Cloned", "Compatible", "Configurable", "Cross-platform", "Customer-focused", "Customizable", "Decentralized", "De-engineered", "Devolved", "Digitized", "Distributed", "Diverse", "Enhanced", "Enterprise-wide", "Ergonomic", "Exclusive", "Expanded", "Extended", "Face to face", "Focused", "Front-line", "Fully-configurable", "Fundamental", "Future-proofed", "Grass-roots", "Horizontal", "Implemented", "Innovative", "Integrated", "Intuitive", "Inverse", "Managed", "Mandatory", "Monitored", "Multi-channelled", "Multi-layered", "Networked", "Object-based", "Open-architected", "Open-source", "Operative", "Optimized", "Optional", "Organic", "Organized", "Persevering", "Persistent", "Phased", "Polarised", "Pre-emptive", "Proactive", "Profit-focused", "Profound", "Programmable", "Progressive", "Public-key", "Quality-focused", "Reactive", "Realigned", "Re-contextualized", "Re-engineered", "Reduced", "Reverse-engineered", "Right-sized", "Robust", "Seamless", "Secured", "Self-enabling", "Sharable", "Stand-alone", "Streamlined", "Switchable", "Synchronised", "Synergistic", "Synergized", "Team-oriented", "Total", "Triple-buffered", "Universal", "Up-sized", "Upgradable", "User-centric", "User-friendly", "Versatile", "Virtual", "Visionary", "Vision-oriented"}
+	catchPhraseDescriptors = []string{"24 hour", "24/7", "3rd generation", "4th generation", "5th generation", "6th generation", "actuating", "analyzing", "asymmetric", "asynchronous", "attitude-oriented", "background", "bandwidth-monitored", "bi-directional", "bifurcated", "bottom-line", "clear-thinking", "client-driven", "client-server", "coherent", "cohesive", "composite", "context-sensitive", "contextually-based", "content-based", "dedicated", "demand-driven", "didactic", "directional", "discrete", "disintermediate", "dynamic", "eco-centric", "empowering", "encompassing", "even-keeled", "executive", "explicit", "exuding", "fault-tolerant", "foreground", "fresh-thinking", "full-range", "global", "grid-enabled", "heuristic", "high-level", "holistic", "homogeneous", "human-resource", "hybrid", "impactful", "incremental", "intangible", "interactive", "intermediate", "leading edge", "local", "logistical", "maximized", "methodical", "mission-critical", "mobile", "modular", "motivating", "multimedia", "multi-state", "multi-tasking", "national", "needs-based", "neutral", "next generation", "non-volatile", "object-oriented", "optimal", "optimizing", "radical", "real-time", "reciprocal", "regional", "responsive", "scalable", "secondary", "solution-oriented", "stable", "static", "systematic", "systemic", "system-worthy", "tangible", "tertiary", "transitional", "uniform", "upward-trending", "user-facing", "value-added", "web-enabled", "well-modulated", "zero administration", "zero defect", "zero tolerance"}
+	catchPhraseNouns       = []string{"ability", "access", "adapter", "algorithm", "alliance", "analyzer", "application", "approach", "architecture", "archive", "artificial intelligence", "array", "attitude", "benchmark", "budgetary management", "capability", "capacity", "challenge", "circuit", "collaboration", "complexity", "concept", "conglomeration", "contingency", "core", "customer loyalty", "database", "data-warehouse", "definition", "emulation", "encoding", "encryption", "extranet", "firmware", "flexibility", "focus group", "forecast", "frame", "framework", "function", "functionalities", "Graphic Interface", "groupware", "Graphical User Interface", "hardware", "help-desk", "hierarchy", "hub", "implementation", "info-mediaries", "infrastructure", "initiative", "installation", "instruction set", "interface", "internet solution", "intranet", "knowledge user", "knowledge base", "local area network", "leverage", "matrices", "matrix", "methodology", "middleware", "migration", "model", "moderator", "monitoring", "moratorium", "neural-net", "open architecture", "open system", "orchestration", "paradigm", "parallelism", "policy", "portal", "pricing structure", "process improvement", "product", "productivity", "project", "projection", "protocol", "secured line", "service-desk", "software", "solution", "standardization", "strategy", "structure", "success", "superstructure", "support", "synergy", "system engine", "task-force", "throughput", "time-frame", "toolset", "utilisation", "website", "workforce"}
+
+	domainWords          = []string{"acme", "example", "globex", "initech", "umbrella", "hooli", "stark", "wayne", "wonka", "tyrell", "cyberdyne", "soylent", "vandelay", "dunder", "pied-piper"}
+	domainSuffixes       = []string{"com", "net", "org", "io", "info", "biz", "name", "dev", "app"}
+	emailDomains         = []string{"gmail.com", "yahoo.com", "hotmail.com", "outlook.com", "example.com"}
+	fileExtensions       = []string{"json", "xml", "txt", "csv", "pdf", "png", "jpg", "gif", "mp3", "mp4", "zip", "html", "css", "js", "go", "yaml", "md", "doc", "xls"}
+	commonFileExtensions = []string{"pdf", "mpeg", "wav", "png", "jpeg", "gif", "mp4", "html", "m2v"}
+	directories          = []string{"/usr", "/usr/local", "/usr/share", "/etc", "/var/log", "/var/lib", "/opt", "/home/user", "/tmp", "/srv", "/lib", "/bin", "/dev", "/mnt", "/boot"}
+
+	products          = []string{"Chair", "Car", "Computer", "Keyboard", "Mouse", "Bike", "Ball", "Gloves", "Pants", "Shirt", "Table", "Shoes", "Hat", "Towels", "Soap", "Tuna", "Chicken", "Fish", "Cheese", "Bacon", "Pizza", "Salad", "Sausages", "Chips"}
+	productAdjectives = []string{"Small", "Ergonomic", "Rustic", "Intelligent", "Gorgeous", "Incredible", "Fantastic", "Practical", "Sleek", "Awesome", "Generic", "Handcrafted", "Handmade", "Licensed", "Refined", "Unbranded", "Tasty"}
+	productMaterials  = []string{"Steel", "Wooden", "Concrete", "Plastic", "Cotton", "Granite", "Rubber", "Metal", "Soft", "Fresh", "Frozen"}
+
+	nouns      = []string{"driver", "protocol", "bandwidth", "panel", "microchip", "program", "port", "card", "array", "interface", "system", "sensor", "firewall", "hard drive", "pixel", "alarm", "feed", "monitor", "application", "transmitter", "bus", "circuit", "capacitor", "matrix"}
+	verbs      = []string{"back up", "bypass", "hack", "override", "compress", "copy", "navigate", "index", "connect", "generate", "quantify", "calculate", "synthesize", "input", "transmit", "program", "reboot", "parse"}
+	ingVerbs   = []string{"backing up", "bypassing", "hacking", "overriding", "compressing", "copying", "navigating", "indexing", "connecting", "generating", "quantifying", "calculating", "synthesizing", "transmitting", "programming", "parsing"}
+	adjectives = []string{"auxiliary", "primary", "back-end", "digital", "open-source", "virtual", "cross-platform", "redundant", "online", "haptic", "multi-byte", "bluetooth", "wireless", "1080p", "neural", "optical", "solid state", "mobile"}
+	loremWords = []string{"lorem", "ipsum", "dolor", "sit", "amet", "consectetur", "adipiscing", "elit", "sed", "do", "eiusmod", "tempor", "incididunt", "ut", "labore", "et", "dolore", "magna", "aliqua", "enim", "ad", "minim", "veniam", "quis", "nostrud", "exercitation", "ullamco", "laboris", "nisi", "aliquip", "ex", "ea", "commodo", "consequat", "duis", "aute", "irure", "in", "reprehenderit", "voluptate", "velit", "esse", "cillum", "fugiat", "nulla", "pariatur", "excepteur", "sint", "occaecat", "cupidatat", "non", "proident", "sunt", "culpa", "qui", "officia", "deserunt", "mollit", "anim", "id", "est", "laborum"}
+)
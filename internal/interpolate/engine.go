@@ -2,12 +2,9 @@ package interpolate
 
 import (
 	"fmt"
-	"regexp"
+	"math/rand/v2"
 	"strings"
 	"sync"
-	"time"
-
-	"github.com/google/uuid"
 )
 
 // Option keys for engine configuration.
@@ -16,116 +13,139 @@ const (
 	OptionKeepUndefined  = "keepUndefined"
 )
 
-// BuiltinFunc is a function that generates a dynamic value.
+// BuiltinFunc is a function that generates a dynamic value. Registered
+// builtins take precedence over the dynamic variables every engine has.
 type BuiltinFunc func() string
 
-// Engine handles variable interpolation.
+// Engine handles variable interpolation. Variables resolve through a
+// layered Scope; the variables set on the engine itself live at its request
+// level.
 type Engine struct {
-	mu        sync.RWMutex
-	variables map[string]string
-	builtins  map[string]BuiltinFunc
-	options   map[string]bool
+	mu       sync.RWMutex
+	scope    *Scope
+	builtins map[string]BuiltinFunc
+	rng      *random
+	options  map[string]bool
 }
 
-// variablePattern matches {{variable}} or {{ variable }} syntax.
-var variablePattern = regexp.MustCompile(`\{\{\s*([a-zA-Z_$][a-zA-Z0-9_\-$]*)\s*\}\}`)
-
 // NewEngine creates a new interpolation engine.
 func NewEngine() *Engine {
-	e := &Engine{
-		variables: make(map[string]string),
-		builtins:  make(map[string]BuiltinFunc),
-		options:   make(map[string]bool),
-	}
-	e.registerDefaultBuiltins()
-	return e
+	return NewEngineWithScope(NewScope())
 }
 
-func (e *Engine) registerDefaultBuiltins() {
-	e.builtins["$uuid"] = func() string {
-		return uuid.New().String()
-	}
-
-	e.builtins["$timestamp"] = func() string {
-		return fmt.Sprintf("%d", time.Now().Unix())
-	}
-
-	e.builtins["$isoTimestamp"] = func() string {
-		return time.Now().Format(time.RFC3339)
-	}
-
-	e.builtins["$randomInt"] = func() string {
-		return fmt.Sprintf("%d", time.Now().UnixNano()%10000)
-	}
-
-	e.builtins["$date"] = func() string {
-		return time.Now().Format("2006-01-02")
+// NewEngineWithScope creates an engine resolving variables through scope.
+// Variables set on the engine are written to the scope's request level.
+func NewEngineWithScope(scope *Scope) *Engine {
+	if scope.Variables(LevelRequest) == nil {
+		scope.SetRequest(NewVariableSet())
 	}
-
-	e.builtins["$randomEmail"] = func() string {
-		return fmt.Sprintf("user%d@example.com", time.Now().UnixNano()%10000)
+	e := &Engine{
+		scope:    scope,
+		builtins: make(map[string]BuiltinFunc),
+		rng:      newRandom(rand.Uint64()),
+		options:  make(map[string]bool),
 	}
+	return e
+}
 
-	e.builtins["$randomName"] = func() string {
-		names := []string{"Alice", "Bob", "Charlie", "Diana", "Eve", "Frank"}
-		return names[time.Now().UnixNano()%int64(len(names))]
-	}
+// Scope returns the layered scope the engine resolves variables through.
+func (e *Engine) Scope() *Scope {
+	return e.scope
 }
 
-// SetVariable sets a variable value.
+// SetVariable sets a request-level variable.
 func (e *Engine) SetVariable(name, value string) {
-	e.mu.Lock()
-	defer e.mu.Unlock()
-	e.variables[name] = value
+	e.scope.SetAt(LevelRequest, name, value)
 }
 
-// GetVariable gets a variable value.
+// GetVariable gets a variable value from any level but the iteration's.
 func (e *Engine) GetVariable(name string) string {
-	e.mu.RLock()
-	defer e.mu.RUnlock()
-	return e.variables[name]
+	value, _, _ := e.scope.resolveBelow(name, LevelRequest)
+	return value
 }
 
-// HasVariable checks if a variable exists.
+// HasVariable checks if a variable exists at any level but the iteration's.
 func (e *Engine) HasVariable(name string) bool {
-	e.mu.RLock()
-	defer e.mu.RUnlock()
-	_, exists := e.variables[name]
-	return exists
+	_, _, ok := e.scope.resolveBelow(name, LevelRequest)
+	return ok
 }
 
-// DeleteVariable removes a variable.
+// DeleteVariable removes a request-level variable.
 func (e *Engine) DeleteVariable(name string) {
-	e.mu.Lock()
-	defer e.mu.Unlock()
-	delete(e.variables, name)
+	if vs := e.scope.Variables(LevelRequest); vs != nil {
+		vs.Delete(name)
+	}
 }
 
-// SetVariables sets multiple variables at once.
+// SetVariables sets multiple request-level variables at once.
 func (e *Engine) SetVariables(vars map[string]string) {
-	e.mu.Lock()
-	defer e.mu.Unlock()
 	for k, v := range vars {
-		e.variables[k] = v
+		e.scope.SetAt(LevelRequest, k, v)
 	}
 }
 
-// Variables returns a copy of all variables.
+// Variables returns a copy of all variables, merged by precedence, without
+// the iteration layer.
 func (e *Engine) Variables() map[string]string {
+	return e.scope.below(LevelRequest)
+}
+
+// Clear removes all variables.
+func (e *Engine) Clear() {
+	e.scope.Clear()
+	e.scope.SetRequest(NewVariableSet())
+}
+
+// SetIterationData sets the iteration-scoped variable layer used by data-driven
+// collection runs. Iteration variables take precedence over regular variables
+// and are replaced as a whole on each call; nil clears the layer.
+func (e *Engine) SetIterationData(data map[string]string) {
+	if data == nil {
+		e.scope.SetIteration(nil)
+		return
+	}
+	e.scope.SetIteration(NewVariableSetFrom(data))
+}
+
+// IterationData returns a copy of the iteration-scoped variables.
+func (e *Engine) IterationData() map[string]string {
+	if vs := e.scope.Variables(LevelIteration); vs != nil {
+		return vs.All()
+	}
+	return make(map[string]string)
+}
+
+// Resolve returns a variable's value and the level it resolved from.
+func (e *Engine) Resolve(name string) (string, Level, bool) {
+	return e.scope.Resolve(name)
+}
+
+// With returns an engine sharing this engine's levels and options, except
+// for level, which holds vars. Variables set on either engine are seen by
+// both, unless level is LevelRequest.
+func (e *Engine) With(level Level, vars map[string]string) *Engine {
 	e.mu.RLock()
 	defer e.mu.RUnlock()
-	result := make(map[string]string, len(e.variables))
-	for k, v := range e.variables {
-		result[k] = v
+
+	derived := &Engine{
+		scope:    e.scope.With(level, NewVariableSetFrom(vars)),
+		builtins: make(map[string]BuiltinFunc, len(e.builtins)),
+		rng:      e.rng,
+		options:  make(map[string]bool, len(e.options)),
 	}
-	return result
+	for k, v := range e.builtins {
+		derived.builtins[k] = v
+	}
+	for k, v := range e.options {
+		derived.options[k] = v
+	}
+	return derived
 }
 
-// Clear removes all variables.
-func (e *Engine) Clear() {
-	e.mu.Lock()
-	defer e.mu.Unlock()
-	e.variables = make(map[string]string)
+// lookup resolves a variable through the scope.
+func (e *Engine) lookup(name string) (string, bool) {
+	value, _, ok := e.scope.Resolve(name)
+	return value, ok
 }
 
 // SetOption sets an engine option.
@@ -149,29 +169,70 @@ func (e *Engine) RegisterBuiltin(name string, fn BuiltinFunc) {
 	e.builtins[name] = fn
 }
 
-// Interpolate replaces all {{variable}} placeholders in the input string.
+// SetSeed seeds the random source of the {{$random*}} dynamic variables, so
+// a run produces the same values each time. Engines derived with With or
+// Clone share the source.
+func (e *Engine) SetSeed(seed uint64) {
+	e.rng.seed(seed)
+}
+
+// isBuiltin reports whether name is a registered builtin or a dynamic
+// variable. Caller must hold at least RLock.
+func (e *Engine) isBuiltin(name string) bool {
+	if _, ok := e.builtins[name]; ok {
+		return true
+	}
+	_, ok := dynamicVariables[name]
+	return ok
+}
+
+// builtin generates the value of a builtin or dynamic variable with args.
+// Caller must hold at least RLock.
+func (e *Engine) builtin(name string, args []string) (string, error) {
+	if fn, ok := e.builtins[name]; ok {
+		if len(args) > 0 {
+			return "", fmt.Errorf("%s takes no arguments", name)
+		}
+		return fn(), nil
+	}
+	value, err := dynamicVariables[name](e.rng, args)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", name, err)
+	}
+	return value, nil
+}
+
+// Interpolate replaces all {{ }} expressions in the input string. An
+// expression names a variable, optionally reads into its JSON value
+// ({{user.id}}, {{items[0]}}) and pipes the result through filters
+// ({{token | base64}}). Variables whose values contain {{ }} are expanded in
+// turn; circular references are an error.
 func (e *Engine) Interpolate(input string) (string, error) {
 	e.mu.RLock()
 	defer e.mu.RUnlock()
+	return e.interpolate(input, nil)
+}
 
+// interpolate expands input; stack holds the variables being expanded.
+// Caller must hold at least RLock.
+func (e *Engine) interpolate(input string, stack []string) (string, error) {
 	var lastErr error
-	result := variablePattern.ReplaceAllStringFunc(input, func(match string) string {
-		// Extract variable name
-		submatch := variablePattern.FindStringSubmatch(match)
-		if len(submatch) < 2 {
+	result := expressionPattern.ReplaceAllStringFunc(input, func(match string) string {
+		expr, ok, err := parseExpression(match[2 : len(match)-2])
+		if !ok {
 			return match
 		}
-		varName := submatch[1]
-
-		// Check builtins first
-		if strings.HasPrefix(varName, "$") {
-			if fn, ok := e.builtins[varName]; ok {
-				return fn()
-			}
+		if err != nil {
+			lastErr = err
+			return match
 		}
 
-		// Check user variables
-		if value, ok := e.variables[varName]; ok {
+		value, defined, err := e.evaluate(expr, stack)
+		if err != nil {
+			lastErr = err
+			return match
+		}
+		if defined {
 			return value
 		}
 
@@ -183,7 +244,7 @@ func (e *Engine) Interpolate(input string) (string, error) {
 			return ""
 		}
 
-		lastErr = fmt.Errorf("undefined variable: %s", varName)
+		lastErr = fmt.Errorf("undefined variable: %s", expr.path)
 		return match
 	})
 
@@ -207,44 +268,66 @@ func (e *Engine) InterpolateMap(input map[string]string) (map[string]string, err
 	return result, nil
 }
 
-// ExtractVariables returns all variable names found in the input string.
+// expressions returns the expressions in input, skipping text that isn't
+// one. The first malformed expression is returned as an error.
+func expressions(input string) ([]expression, error) {
+	var result []expression
+	var firstErr error
+	for _, match := range expressionPattern.FindAllStringSubmatch(input, -1) {
+		expr, ok, err := parseExpression(match[1])
+		if !ok {
+			continue
+		}
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		result = append(result, expr)
+	}
+	return result, firstErr
+}
+
+// ExtractVariables returns the names of the variables used in the input
+// string, without accessors or filters: {{user.id | upper}} uses user.
 func (e *Engine) ExtractVariables(input string) []string {
-	matches := variablePattern.FindAllStringSubmatch(input, -1)
+	exprs, _ := expressions(input)
 	seen := make(map[string]bool)
 	var result []string
 
-	for _, match := range matches {
-		if len(match) >= 2 {
-			varName := match[1]
-			if !seen[varName] {
-				seen[varName] = true
-				result = append(result, varName)
-			}
+	for _, expr := range exprs {
+		name := expr.root()
+		if !seen[name] {
+			seen[name] = true
+			result = append(result, name)
 		}
 	}
 
 	return result
 }
 
-// Validate checks if all variables in the input string are defined.
+// Validate checks that every expression in the input string is well formed
+// and that its variable is defined, or has a default.
 func (e *Engine) Validate(input string) error {
 	e.mu.RLock()
 	defer e.mu.RUnlock()
 
-	vars := e.ExtractVariables(input)
-	var missing []string
+	exprs, err := expressions(input)
+	if err != nil {
+		return err
+	}
 
-	for _, varName := range vars {
-		// Builtins are always valid
-		if strings.HasPrefix(varName, "$") {
-			if _, ok := e.builtins[varName]; ok {
-				continue
-			}
+	var missing []string
+	seen := make(map[string]bool)
+	for _, expr := range exprs {
+		_, defined, err := e.evaluate(expr, nil)
+		if err != nil {
+			return err
 		}
-
-		// Check user variables
-		if _, ok := e.variables[varName]; !ok {
-			missing = append(missing, varName)
+		if !defined && !seen[expr.path] {
+			seen[expr.path] = true
+			missing = append(missing, expr.path)
 		}
 	}
 
@@ -255,14 +338,15 @@ func (e *Engine) Validate(input string) error {
 	return nil
 }
 
-// Clone creates a copy of the engine with the same variables.
+// Clone creates a copy of the engine with the same variables and iteration data.
 func (e *Engine) Clone() *Engine {
 	e.mu.RLock()
 	defer e.mu.RUnlock()
 
-	clone := NewEngine()
-	for k, v := range e.variables {
-		clone.variables[k] = v
+	clone := NewEngineWithScope(e.scope.Clone())
+	clone.rng = e.rng
+	for k, v := range e.builtins {
+		clone.builtins[k] = v
 	}
 	for k, v := range e.options {
 		clone.options[k] = v
@@ -270,3 +354,41 @@ func (e *Engine) Clone() *Engine {
 
 	return clone
 }
+
+// Reference is a {{variable}} used in a template and where it resolves from.
+type Reference struct {
+	Name    string
+	Value   string
+	Level   Level   // Level the value comes from; LevelNone for builtins and undefined variables
+	Shadows []Level // Lower levels that also define the variable
+	Builtin bool
+	Defined bool
+}
+
+// References returns the variables used in input, in order of first use,
+// with the level each resolves from. Value is the variable's raw value,
+// before nested expansion, accessors and filters.
+func (e *Engine) References(input string) []Reference {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	exprs, _ := expressions(input)
+	seen := make(map[string]bool)
+	var refs []Reference
+	for _, expr := range exprs {
+		ref := Reference{Name: expr.root()}
+		if strings.HasPrefix(expr.path, "$") && e.isBuiltin(expr.path) {
+			ref.Name = expr.path
+			ref.Builtin, ref.Defined = true, true
+		} else if name, _, value, level, ok := e.lookupPath(expr.path); ok {
+			ref.Name, ref.Value, ref.Level = name, value, level
+			ref.Shadows = e.scope.Sources(name)[1:]
+			ref.Defined = true
+		}
+		if !seen[ref.Name] {
+			seen[ref.Name] = true
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
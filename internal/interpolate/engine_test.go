@@ -703,3 +703,305 @@ func TestEngine_ValidateExtended(t *testing.T) {
 		assert.NoError(t, err)
 	})
 }
+
+func TestEngine_IterationData(t *testing.T) {
+	t.Run("iteration data overrides variables", func(t *testing.T) {
+		engine := NewEngine()
+		engine.SetVariable("user", "env-user")
+		engine.SetVariable("host", "example.com")
+		engine.SetIterationData(map[string]string{"user": "row-user"})
+
+		result, err := engine.Interpolate("{{host}}/{{user}}")
+		require.NoError(t, err)
+		assert.Equal(t, "example.com/row-user", result)
+	})
+
+	t.Run("nil clears iteration data", func(t *testing.T) {
+		engine := NewEngine()
+		engine.SetVariable("user", "env-user")
+		engine.SetIterationData(map[string]string{"user": "row-user"})
+		engine.SetIterationData(nil)
+
+		result, err := engine.Interpolate("{{user}}")
+		require.NoError(t, err)
+		assert.Equal(t, "env-user", result)
+		assert.Empty(t, engine.IterationData())
+	})
+
+	t.Run("Validate resolves iteration variables", func(t *testing.T) {
+		engine := NewEngine()
+		engine.SetIterationData(map[string]string{"id": "1"})
+		assert.NoError(t, engine.Validate("/items/{{id}}"))
+	})
+
+	t.Run("Clone copies iteration data independently", func(t *testing.T) {
+		engine := NewEngine()
+		engine.SetIterationData(map[string]string{"id": "1"})
+		clone := engine.Clone()
+		clone.SetIterationData(map[string]string{"id": "2"})
+
+		assert.Equal(t, "1", engine.IterationData()["id"])
+		assert.Equal(t, "2", clone.IterationData()["id"])
+	})
+}
+
+func TestEngine_Scope(t *testing.T) {
+	t.Run("engine variables live at the request level", func(t *testing.T) {
+		engine := NewEngine()
+		engine.Scope().SetEnvironment(NewVariableSetFrom(map[string]string{"host": "env.example.com", "id": "1"}))
+		engine.SetVariable("id", "42")
+
+		result, err := engine.Interpolate("{{host}}/{{id}}")
+		require.NoError(t, err)
+		assert.Equal(t, "env.example.com/42", result)
+
+		_, level, _ := engine.Resolve("id")
+		assert.Equal(t, LevelRequest, level)
+		assert.Equal(t, map[string]string{"host": "env.example.com", "id": "42"}, engine.Variables())
+	})
+
+	t.Run("With layers variables without changing the engine", func(t *testing.T) {
+		engine := NewEngine()
+		engine.Scope().SetCollection(NewVariableSetFrom(map[string]string{"host": "collection"}))
+
+		folder := engine.With(LevelFolder, map[string]string{"host": "folder"})
+		folder.SetVariable("token", "abc")
+
+		assert.Equal(t, "folder", folder.GetVariable("host"))
+		assert.Equal(t, "collection", engine.GetVariable("host"))
+		assert.Equal(t, "abc", engine.GetVariable("token"))
+	})
+
+	t.Run("References reports sources", func(t *testing.T) {
+		engine := NewEngine()
+		engine.Scope().SetEnvironment(NewVariableSetFrom(map[string]string{"host": "env"}))
+		engine.Scope().SetCollection(NewVariableSetFrom(map[string]string{"host": "collection"}))
+
+		refs := engine.References("{{host}}/{{$uuid}}/{{missing}}/{{host}}")
+		require.Len(t, refs, 3)
+
+		assert.Equal(t, Reference{Name: "host", Value: "env", Level: LevelEnvironment, Shadows: []Level{LevelCollection}, Defined: true}, refs[0])
+		assert.True(t, refs[1].Builtin)
+		assert.Equal(t, LevelNone, refs[1].Level)
+		assert.False(t, refs[2].Defined)
+	})
+}
+
+func TestEngine_Expressions(t *testing.T) {
+	t.Run("expands variables inside variable values", func(t *testing.T) {
+		engine := NewEngine()
+		engine.SetVariable("host", "api.example.com")
+		engine.SetVariable("base", "https://{{host}}/v1")
+		engine.SetVariable("users", "{{base}}/users")
+
+		result, err := engine.Interpolate("GET {{users}}")
+		require.NoError(t, err)
+		assert.Equal(t, "GET https://api.example.com/v1/users", result)
+	})
+
+	t.Run("detects circular references", func(t *testing.T) {
+		engine := NewEngine()
+		engine.SetVariable("a", "{{b}}")
+		engine.SetVariable("b", "x{{a}}")
+
+		_, err := engine.Interpolate("{{a}}")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "circular variable reference: a -> b -> a")
+	})
+
+	t.Run("reads into JSON values", func(t *testing.T) {
+		engine := NewEngine()
+		engine.SetVariable("user", `{"id": 42, "name": "Ada", "roles": ["admin", "dev"], "team": {"lead": true}}`)
+		engine.SetVariable("api.key", "dotted")
+
+		result, err := engine.Interpolate("{{user.id}} {{user.name}} {{user.roles[1]}} {{user.roles.0}} {{user.team}} {{api.key}}")
+		require.NoError(t, err)
+		assert.Equal(t, `42 Ada dev admin {"lead":true} dotted`, result)
+
+		_, err = engine.Interpolate("{{user.missing}}")
+		assert.EqualError(t, err, "undefined variable: user.missing")
+	})
+
+	t.Run("applies filters", func(t *testing.T) {
+		engine := NewEngine()
+		engine.SetVariable("token", "user:pass")
+		engine.SetVariable("query", "a b&c")
+		engine.SetVariable("quote", `say "hi"`)
+
+		tests := map[string]string{
+			"{{token | base64}}":                  "dXNlcjpwYXNz",
+			"{{ token | base64 | base64Decode }}": "user:pass",
+			"{{query | urlencode}}":               "a+b%26c",
+			"{{token | upper}}":                   "USER:PASS",
+			"{{token | sha256}}":                  "ef4c914c591698b268db3c64163eafda7209a630f236ebf0eebf045460df723a",
+			"{{quote | jsonEscape}}":              `say \"hi\"`,
+			`{{missing | default "fallback"}}`:    "fallback",
+			`{{token | default "unused"}}`:        "user:pass",
+		}
+		for input, want := range tests {
+			result, err := engine.Interpolate(input)
+			require.NoError(t, err, input)
+			assert.Equal(t, want, result, input)
+		}
+	})
+
+	t.Run("formats and shifts times", func(t *testing.T) {
+		engine := NewEngine()
+		engine.SetVariable("ts", "1700000000")
+		engine.SetVariable("day", "2024-02-28")
+
+		tests := map[string]string{
+			`{{ts | add "1h"}}`:                    "1700003600",
+			`{{ts | add "1h" | format "RFC1123"}}`: "Tue, 14 Nov 2023 23:13:20 GMT",
+			`{{ts | format "RFC3339"}}`:            "2023-11-14T22:13:20Z",
+			`{{day | add "2d"}}`:                   "2024-03-01",
+			`{{day | format "2006/01/02"}}`:        "2024/02/28",
+			`{{ts | add "-30m" | format "unix"}}`:  "1699998200",
+		}
+		for input, want := range tests {
+			result, err := engine.Interpolate(input)
+			require.NoError(t, err, input)
+			assert.Equal(t, want, result, input)
+		}
+
+		result, err := engine.Interpolate(`{{$timestamp | add "1h" | format "RFC1123"}}`)
+		require.NoError(t, err)
+		assert.Contains(t, result, "GMT")
+	})
+
+	t.Run("reports filter errors", func(t *testing.T) {
+		engine := NewEngine()
+		engine.SetVariable("name", "x")
+
+		_, err := engine.Interpolate("{{name | shout}}")
+		assert.EqualError(t, err, `unknown filter "shout" in {{name | shout}}`)
+
+		_, err = engine.Interpolate(`{{name | add "1h"}}`)
+		assert.ErrorContains(t, err, `filter add on {{name}}: "x" is not a timestamp or date`)
+	})
+
+	t.Run("leaves text that is not an expression alone", func(t *testing.T) {
+		engine := NewEngine()
+
+		result, err := engine.Interpolate("{{#each items}} {{ }} {{not an expression}}")
+		require.NoError(t, err)
+		assert.Equal(t, "{{#each items}} {{ }} {{not an expression}}", result)
+	})
+
+	t.Run("Validate and ExtractVariables understand expressions", func(t *testing.T) {
+		engine := NewEngine()
+		engine.SetVariable("user", `{"id": 1}`)
+
+		input := `{{user.id | upper}} {{missing | default "x"}} {{other}} {{$uuid | upper}}`
+		assert.Equal(t, []string{"user", "missing", "other", "$uuid"}, engine.ExtractVariables(input))
+		assert.EqualError(t, engine.Validate(input), "undefined variables: other")
+		assert.EqualError(t, engine.Validate("{{user | nope}}"), `unknown filter "nope" in {{user | nope}}`)
+	})
+}
+
+func TestEngine_DynamicVariables(t *testing.T) {
+	t.Run("seeded engines generate the same values", func(t *testing.T) {
+		input := "{{$randomUUID}} {{$randomFullName}} {{$randomEmail}} {{$randomInt}} {{$randomLoremSentence}}"
+		a, b := NewEngine(), NewEngine()
+		a.SetSeed(42)
+		b.SetSeed(42)
+
+		first, err := a.Interpolate(input)
+		require.NoError(t, err)
+		second, err := b.Interpolate(input)
+		require.NoError(t, err)
+		assert.Equal(t, first, second)
+
+		again, err := a.Interpolate(input)
+		require.NoError(t, err)
+		assert.NotEqual(t, first, again)
+	})
+
+	t.Run("derived engines share the seeded source", func(t *testing.T) {
+		a, b := NewEngine(), NewEngine()
+		a.SetSeed(7)
+		b.SetSeed(7)
+
+		fromClone, _ := a.Clone().Interpolate("{{$guid}}")
+		fromWith, _ := a.With(LevelFolder, nil).Interpolate("{{$guid}}")
+		first, _ := b.Interpolate("{{$guid}}")
+		second, _ := b.Interpolate("{{$guid}}")
+		assert.Equal(t, []string{first, second}, []string{fromClone, fromWith})
+	})
+
+	t.Run("generates Postman variables", func(t *testing.T) {
+		engine := NewEngine()
+		cases := map[string]string{
+			"{{$guid}}":               `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`,
+			"{{$randomIP}}":           `^\d+\.\d+\.\d+\.\d+$`,
+			"{{$randomHexColor}}":     `^#[0-9a-f]{6}$`,
+			"{{$randomBoolean}}":      `^(true|false)$`,
+			"{{$randomMACAddress}}":   `^([0-9a-f]{2}:){5}[0-9a-f]{2}$`,
+			"{{$randomPrice}}":        `^\d+\.\d{2}$`,
+			"{{$randomExampleEmail}}": `@example\.(com|net|org)$`,
+			"{{$randomDateFuture}}":   `^\d{4}-\d{2}-\d{2}T`,
+			"{{$randomLoremSlug}}":    `^[a-z]+-[a-z]+-[a-z]+$`,
+			"{{$randomCompanyName}}":  `^\w+ `,
+		}
+		for input, pattern := range cases {
+			result, err := engine.Interpolate(input)
+			require.NoError(t, err, input)
+			assert.Regexp(t, pattern, result, input)
+		}
+	})
+
+	t.Run("takes arguments", func(t *testing.T) {
+		engine := NewEngine()
+
+		for i := 0; i < 50; i++ {
+			result, err := engine.Interpolate("{{$randomInt 5 7}}")
+			require.NoError(t, err)
+			assert.Contains(t, []string{"5", "6", "7"}, result)
+		}
+
+		result, err := engine.Interpolate("{{$randomAlphaNumeric 12}} {{$randomPassword 8 | upper}}")
+		require.NoError(t, err)
+		assert.Regexp(t, `^[a-z0-9]{12} \S{8}$`, result)
+
+		result, err = engine.Interpolate("{{$randomWords 4}}")
+		require.NoError(t, err)
+		assert.Len(t, strings.Fields(result), 4)
+	})
+
+	t.Run("rejects bad arguments", func(t *testing.T) {
+		engine := NewEngine()
+
+		_, err := engine.Interpolate("{{$randomInt 9 1}}")
+		assert.EqualError(t, err, "$randomInt: min 9 is greater than max 1")
+		_, err = engine.Interpolate("{{$randomInt x}}")
+		assert.EqualError(t, err, `$randomInt: argument "x" is not an integer`)
+		_, err = engine.Interpolate("{{$guid 1}}")
+		assert.EqualError(t, err, "$guid: takes no arguments")
+		_, err = engine.Interpolate("{{$nope 1}}")
+		assert.EqualError(t, err, "unknown dynamic variable $nope")
+	})
+
+	t.Run("registered builtins take precedence", func(t *testing.T) {
+		engine := NewEngine()
+		engine.RegisterBuiltin("$randomInt", func() string { return "4" })
+
+		result, err := engine.Interpolate("{{$randomInt}}")
+		require.NoError(t, err)
+		assert.Equal(t, "4", result)
+		assert.True(t, engine.References("{{$randomCity}}")[0].Builtin)
+	})
+
+	t.Run("resolves secret references", func(t *testing.T) {
+		t.Setenv("CURRIER_TEST_TOKEN", "t0ken")
+		engine := NewEngine()
+		engine.SetVariable("token", `{{$secret "env:CURRIER_TEST_TOKEN"}}`)
+
+		result, err := engine.Interpolate("Bearer {{token}}")
+		require.NoError(t, err)
+		assert.Equal(t, "Bearer t0ken", result)
+		assert.Equal(t, `{{$secret "env:CURRIER_TEST_TOKEN"}}`, engine.GetVariable("token"))
+
+		_, err = engine.Interpolate("{{$secret}}")
+		assert.ErrorContains(t, err, "$secret: takes one secret reference")
+	})
+}
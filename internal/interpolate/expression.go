@@ -0,0 +1,282 @@
+package interpolate
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// maxDepth limits how deeply variables may refer to other variables.
+const maxDepth = 10
+
+// expressionPattern matches brace-free text between {{ and }}. Whether it is
+// an expression is decided by parseExpression.
+var expressionPattern = regexp.MustCompile(`\{\{([^{}]*)\}\}`)
+
+// pathPattern matches a variable reference: a name optionally followed by
+// .field and [index] accessors into a JSON value.
+var pathPattern = regexp.MustCompile(`^[a-zA-Z_$][a-zA-Z0-9_\-$]*(?:\.[a-zA-Z0-9_\-$]+|\[\d+\])*$`)
+
+// filterNamePattern matches a filter name.
+var filterNamePattern = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9]*$`)
+
+// expression is a parsed {{ }} expression: a variable reference followed by
+// filters, as in {{ user.name | upper }}. Dynamic variables may take
+// arguments, as in {{$randomInt 1 100}}.
+type expression struct {
+	path    string
+	args    []string
+	filters []filterCall
+}
+
+// filterCall is one filter of an expression with its arguments.
+type filterCall struct {
+	name string
+	args []string
+}
+
+// root returns the name the expression's path starts with.
+func (x expression) root() string {
+	if i := strings.IndexAny(x.path, ".["); i > 0 {
+		return x.path[:i]
+	}
+	return x.path
+}
+
+// hasDefault reports whether a default filter handles undefined values.
+func (x expression) hasDefault() bool {
+	for _, f := range x.filters {
+		if f.name == "default" {
+			return true
+		}
+	}
+	return false
+}
+
+// token is a word, a quoted string or a pipe in an expression.
+type token struct {
+	text   string
+	quoted bool
+	pipe   bool
+}
+
+// tokenize splits an expression into tokens. It fails on unterminated
+// quotes.
+func tokenize(src string) ([]token, bool) {
+	var tokens []token
+	for i := 0; i < len(src); {
+		switch c := src[i]; {
+		case c == ' ' || c == '\t':
+			i++
+		case c == '|':
+			tokens = append(tokens, token{text: "|", pipe: true})
+			i++
+		case c == '"' || c == '\'':
+			end := i + 1
+			for end < len(src) && src[end] != c {
+				if src[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(src) {
+				return nil, false
+			}
+			text := src[i+1 : end]
+			if c == '"' {
+				unquoted, err := strconv.Unquote(src[i : end+1])
+				if err != nil {
+					return nil, false
+				}
+				text = unquoted
+			}
+			tokens = append(tokens, token{text: text, quoted: true})
+			i = end + 1
+		default:
+			end := i
+			for end < len(src) && !strings.ContainsRune(" \t|\"'", rune(src[end])) {
+				end++
+			}
+			tokens = append(tokens, token{text: src[i:end]})
+			i = end
+		}
+	}
+	return tokens, true
+}
+
+// parseExpression parses the text between {{ and }}. ok is false when the
+// text isn't an expression, such as a template meant for another engine; it
+// is then left untouched. An expression with a malformed filter is an error.
+func parseExpression(src string) (expression, bool, error) {
+	tokens, ok := tokenize(src)
+	if !ok || len(tokens) == 0 || tokens[0].quoted || tokens[0].pipe || !pathPattern.MatchString(tokens[0].text) {
+		return expression{}, false, nil
+	}
+	expr := expression{path: tokens[0].text}
+	rest := tokens[1:]
+	if strings.HasPrefix(expr.path, "$") {
+		for len(rest) > 0 && !rest[0].pipe {
+			expr.args = append(expr.args, rest[0].text)
+			rest = rest[1:]
+		}
+	}
+	if len(rest) > 0 && !rest[0].pipe {
+		return expression{}, false, nil
+	}
+
+	for len(rest) > 0 {
+		rest = rest[1:] // The pipe
+		if len(rest) == 0 || rest[0].pipe || rest[0].quoted {
+			return expression{}, true, fmt.Errorf("missing filter name in {{%s}}", strings.TrimSpace(src))
+		}
+		call := filterCall{name: rest[0].text}
+		if !filterNamePattern.MatchString(call.name) {
+			return expression{}, true, fmt.Errorf("invalid filter name %q in {{%s}}", call.name, strings.TrimSpace(src))
+		}
+		if _, ok := filters[call.name]; !ok {
+			return expression{}, true, fmt.Errorf("unknown filter %q in {{%s}}", call.name, strings.TrimSpace(src))
+		}
+		rest = rest[1:]
+		for len(rest) > 0 && !rest[0].pipe {
+			call.args = append(call.args, rest[0].text)
+			rest = rest[1:]
+		}
+		expr.filters = append(expr.filters, call)
+	}
+	return expr, true, nil
+}
+
+// lookupPath finds the variable a path refers to. The longest prefix of the
+// path naming a variable wins, so variables whose names contain dots still
+// resolve; the remainder is accessed as JSON. Caller must hold at least
+// RLock.
+func (e *Engine) lookupPath(path string) (name, rest, value string, level Level, ok bool) {
+	cuts := []int{len(path)}
+	for i := len(path) - 1; i > 0; i-- {
+		if path[i] == '.' || path[i] == '[' {
+			cuts = append(cuts, i)
+		}
+	}
+	for _, cut := range cuts {
+		if value, level, ok := e.scope.Resolve(path[:cut]); ok {
+			return path[:cut], path[cut:], value, level, true
+		}
+	}
+	return "", "", "", LevelNone, false
+}
+
+// evaluate resolves an expression's variable and applies its filters.
+// defined is false when the variable doesn't exist and no default filter
+// stands in for it. stack holds the variables being expanded, to detect
+// cycles. Caller must hold at least RLock.
+func (e *Engine) evaluate(expr expression, stack []string) (value string, defined bool, err error) {
+	value, defined, err = e.resolve(expr.path, expr.args, stack)
+	if err != nil {
+		return "", false, err
+	}
+	if !defined {
+		if !expr.hasDefault() {
+			return "", false, nil
+		}
+		value = ""
+	}
+	for _, f := range expr.filters {
+		if value, err = filters[f.name](value, f.args); err != nil {
+			return "", false, fmt.Errorf("filter %s on {{%s}}: %w", f.name, expr.path, err)
+		}
+	}
+	return value, true, nil
+}
+
+// resolve returns the value a path refers to: a builtin's value generated
+// from args, or a variable's with any {{ }} in it expanded and any accessors
+// applied.
+func (e *Engine) resolve(path string, args []string, stack []string) (string, bool, error) {
+	if strings.HasPrefix(path, "$") {
+		if e.isBuiltin(path) {
+			value, err := e.builtin(path, args)
+			return value, err == nil, err
+		}
+		if len(args) > 0 {
+			return "", false, fmt.Errorf("unknown dynamic variable %s", path)
+		}
+	}
+
+	name, rest, value, _, ok := e.lookupPath(path)
+	if !ok {
+		return "", false, nil
+	}
+
+	if strings.Contains(value, "{{") {
+		for _, s := range stack {
+			if s == name {
+				return "", false, fmt.Errorf("circular variable reference: %s -> %s", strings.Join(stack, " -> "), name)
+			}
+		}
+		if len(stack) >= maxDepth {
+			return "", false, fmt.Errorf("variables nested more than %d deep at %s", maxDepth, name)
+		}
+		var err error
+		if value, err = e.interpolate(value, append(stack, name)); err != nil {
+			return "", false, err
+		}
+	}
+
+	if rest == "" {
+		return value, true, nil
+	}
+	return accessJSON(name, value, rest)
+}
+
+// accessJSON applies .field and [index] accessors to a JSON value. A missing
+// field or index is undefined rather than an error.
+func accessJSON(name, value, accessors string) (string, bool, error) {
+	decoder := json.NewDecoder(strings.NewReader(value))
+	decoder.UseNumber()
+	var current interface{}
+	if err := decoder.Decode(&current); err != nil {
+		return "", false, fmt.Errorf("variable %s is not valid JSON, cannot read %s", name, accessors)
+	}
+
+	for _, key := range splitAccessors(accessors) {
+		switch node := current.(type) {
+		case map[string]interface{}:
+			v, ok := node[key]
+			if !ok {
+				return "", false, nil
+			}
+			current = v
+		case []interface{}:
+			index, err := strconv.Atoi(key)
+			if err != nil || index < 0 || index >= len(node) {
+				return "", false, nil
+			}
+			current = node[index]
+		default:
+			return "", false, nil
+		}
+	}
+
+	switch v := current.(type) {
+	case string:
+		return v, true, nil
+	case json.Number:
+		return v.String(), true, nil
+	case nil:
+		return "null", true, nil
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return "", false, err
+		}
+		return string(data), true, nil
+	}
+}
+
+// splitAccessors splits ".a[0].b" into "a", "0" and "b".
+func splitAccessors(accessors string) []string {
+	accessors = strings.NewReplacer("[", ".", "]", "").Replace(accessors)
+	return strings.Split(strings.TrimPrefix(accessors, "."), ".")
+}
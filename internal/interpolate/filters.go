@@ -0,0 +1,195 @@
+package interpolate
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// filterFunc transforms a value with a filter's arguments.
+type filterFunc func(value string, args []string) (string, error)
+
+// filters are the transforms available after a pipe in {{ }} expressions.
+var filters = map[string]filterFunc{
+	"upper": noArgs(strings.ToUpper),
+	"lower": noArgs(strings.ToLower),
+	"trim":  noArgs(strings.TrimSpace),
+	"base64": noArgs(func(v string) string {
+		return base64.StdEncoding.EncodeToString([]byte(v))
+	}),
+	"base64Decode": func(value string, args []string) (string, error) {
+		if err := argCount(args, 0); err != nil {
+			return "", err
+		}
+		data, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return "", fmt.Errorf("invalid base64: %w", err)
+		}
+		return string(data), nil
+	},
+	"urlencode": noArgs(url.QueryEscape),
+	"jsonEscape": noArgs(func(v string) string {
+		data, _ := json.Marshal(v)
+		return string(data[1 : len(data)-1])
+	}),
+	"sha256": noArgs(func(v string) string {
+		sum := sha256.Sum256([]byte(v))
+		return hex.EncodeToString(sum[:])
+	}),
+	"md5": noArgs(func(v string) string {
+		sum := md5.Sum([]byte(v))
+		return hex.EncodeToString(sum[:])
+	}),
+	"default": func(value string, args []string) (string, error) {
+		if err := argCount(args, 1); err != nil {
+			return "", err
+		}
+		if value == "" {
+			return args[0], nil
+		}
+		return value, nil
+	},
+	"add":    addFilter,
+	"format": formatFilter,
+}
+
+// noArgs adapts a function of the value alone to a filter.
+func noArgs(fn func(string) string) filterFunc {
+	return func(value string, args []string) (string, error) {
+		if err := argCount(args, 0); err != nil {
+			return "", err
+		}
+		return fn(value), nil
+	}
+}
+
+// argCount checks a filter got exactly n arguments.
+func argCount(args []string, n int) error {
+	if len(args) != n {
+		return fmt.Errorf("expected %d argument(s), got %d", n, len(args))
+	}
+	return nil
+}
+
+// timeKind records how a time value was written, so add can keep it.
+type timeKind int
+
+const (
+	timeUnix timeKind = iota
+	timeUnixMilli
+	timeRFC3339
+	timeDate
+)
+
+// parseTime reads a Unix timestamp in seconds or milliseconds, an RFC 3339
+// time or a date.
+func parseTime(value string) (time.Time, timeKind, error) {
+	if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if len(strings.TrimPrefix(value, "-")) >= 13 {
+			return time.UnixMilli(n), timeUnixMilli, nil
+		}
+		return time.Unix(n, 0), timeUnix, nil
+	}
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t, timeRFC3339, nil
+	}
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, timeDate, nil
+	}
+	return time.Time{}, 0, fmt.Errorf("%q is not a timestamp or date", value)
+}
+
+// addFilter shifts a time by a duration such as "1h", "-30m" or "7d",
+// keeping the value's format.
+func addFilter(value string, args []string) (string, error) {
+	if err := argCount(args, 1); err != nil {
+		return "", err
+	}
+	t, kind, err := parseTime(value)
+	if err != nil {
+		return "", err
+	}
+	d, err := parseDuration(args[0])
+	if err != nil {
+		return "", err
+	}
+
+	t = t.Add(d)
+	switch kind {
+	case timeUnixMilli:
+		return strconv.FormatInt(t.UnixMilli(), 10), nil
+	case timeRFC3339:
+		return t.Format(time.RFC3339), nil
+	case timeDate:
+		return t.Format("2006-01-02"), nil
+	default:
+		return strconv.FormatInt(t.Unix(), 10), nil
+	}
+}
+
+// parseDuration parses a Go duration, also accepting days ("7d").
+func parseDuration(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	return d, nil
+}
+
+// timeLayouts are the layout names format accepts besides Go layouts.
+var timeLayouts = map[string]string{
+	"ANSIC":    time.ANSIC,
+	"RFC822":   time.RFC822,
+	"RFC850":   time.RFC850,
+	"RFC1123":  time.RFC1123,
+	"RFC1123Z": time.RFC1123Z,
+	"RFC3339":  time.RFC3339,
+	"Kitchen":  time.Kitchen,
+	"date":     "2006-01-02",
+	"time":     "15:04:05",
+}
+
+// formatFilter formats a time with a layout name (RFC1123, RFC3339, date,
+// unix, unixMilli, ...) or a Go layout. Times are formatted in UTC; RFC1123
+// uses GMT as HTTP dates require.
+func formatFilter(value string, args []string) (string, error) {
+	if err := argCount(args, 1); err != nil {
+		return "", err
+	}
+	t, _, err := parseTime(value)
+	if err != nil {
+		return "", err
+	}
+	t = t.UTC()
+
+	switch layout := args[0]; layout {
+	case "unix":
+		return strconv.FormatInt(t.Unix(), 10), nil
+	case "unixMilli":
+		return strconv.FormatInt(t.UnixMilli(), 10), nil
+	case "RFC1123":
+		return t.Format(http1123), nil
+	default:
+		if named, ok := timeLayouts[layout]; ok {
+			layout = named
+		}
+		return t.Format(layout), nil
+	}
+}
+
+// http1123 is RFC 1123 with the GMT zone HTTP dates use.
+const http1123 = "Mon, 02 Jan 2006 15:04:05 GMT"
@@ -4,15 +4,18 @@ import (
 	"sync"
 )
 
-// Level represents the precedence level of variables.
+// Level represents the precedence level of variables. Higher levels are
+// narrower and shadow lower ones.
 type Level int
 
 const (
 	LevelNone Level = iota
 	LevelGlobal
-	LevelEnvironment
 	LevelCollection
+	LevelEnvironment
+	LevelFolder
 	LevelRequest
+	LevelIteration
 )
 
 // String returns the string representation of a level.
@@ -24,8 +27,12 @@ func (l Level) String() string {
 		return "environment"
 	case LevelCollection:
 		return "collection"
+	case LevelFolder:
+		return "folder"
 	case LevelRequest:
 		return "request"
+	case LevelIteration:
+		return "iteration"
 	default:
 		return "none"
 	}
@@ -44,6 +51,15 @@ func NewVariableSet() *VariableSet {
 	}
 }
 
+// NewVariableSetFrom creates a variable set holding a copy of vars.
+func NewVariableSetFrom(vars map[string]string) *VariableSet {
+	vs := NewVariableSet()
+	for k, v := range vars {
+		vs.data[k] = v
+	}
+	return vs
+}
+
 // Set sets a variable value.
 func (vs *VariableSet) Set(key, value string) {
 	vs.mu.Lock()
@@ -125,21 +141,30 @@ func (vs *VariableSet) Merge(other *VariableSet) {
 }
 
 // Scope manages variables across multiple precedence levels.
-// Precedence order (highest to lowest): Request > Collection > Environment > Global
+// Precedence order (highest to lowest):
+// Iteration > Request > Folder > Environment > Collection > Global
+//
+// Global holds values shared by every workspace, Collection the defaults
+// defined on the request's collection, Environment the active environment,
+// Folder the variables of the request's enclosing folders, Request the values
+// set while sending (extractions, script variables and command-line
+// overrides), and Iteration the current data row of a data-driven run. As in
+// Postman, the environment overrides collection defaults, so one collection
+// can target staging or production.
 type Scope struct {
 	mu          sync.RWMutex
 	global      *VariableSet
 	environment *VariableSet
 	collection  *VariableSet
+	folder      *VariableSet
 	request     *VariableSet
-	engine      *Engine
+	iteration   *VariableSet
 }
 
 // NewScope creates a new scope with an empty global level.
 func NewScope() *Scope {
 	return &Scope{
 		global: NewVariableSet(),
-		engine: NewEngine(),
 	}
 }
 
@@ -148,77 +173,134 @@ func (s *Scope) Global() *VariableSet {
 	return s.global
 }
 
+// SetGlobal sets the global-level variables.
+func (s *Scope) SetGlobal(vs *VariableSet) {
+	s.SetLevel(LevelGlobal, vs)
+}
+
 // SetEnvironment sets the environment-level variables.
 func (s *Scope) SetEnvironment(vs *VariableSet) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	s.environment = vs
+	s.SetLevel(LevelEnvironment, vs)
 }
 
 // SetCollection sets the collection-level variables.
 func (s *Scope) SetCollection(vs *VariableSet) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	s.collection = vs
+	s.SetLevel(LevelCollection, vs)
+}
+
+// SetFolder sets the folder-level variables.
+func (s *Scope) SetFolder(vs *VariableSet) {
+	s.SetLevel(LevelFolder, vs)
 }
 
 // SetRequest sets the request-level variables.
 func (s *Scope) SetRequest(vs *VariableSet) {
+	s.SetLevel(LevelRequest, vs)
+}
+
+// SetIteration sets the iteration-level variables.
+func (s *Scope) SetIteration(vs *VariableSet) {
+	s.SetLevel(LevelIteration, vs)
+}
+
+// SetLevel replaces the variable set of a level; nil removes the level.
+func (s *Scope) SetLevel(level Level, vs *VariableSet) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	s.request = vs
+	if slot := s.slot(level); slot != nil {
+		*slot = vs
+	}
 }
 
-// Get gets a variable value using precedence order.
-func (s *Scope) Get(key string) string {
+// Variables returns the variable set of a level, or nil if it isn't set.
+func (s *Scope) Variables(level Level) *VariableSet {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
-
-	// Check in precedence order (highest first)
-	if s.request != nil && s.request.Has(key) {
-		return s.request.Get(key)
-	}
-	if s.collection != nil && s.collection.Has(key) {
-		return s.collection.Get(key)
+	if slot := s.slot(level); slot != nil {
+		return *slot
 	}
-	if s.environment != nil && s.environment.Has(key) {
-		return s.environment.Get(key)
-	}
-	if s.global != nil && s.global.Has(key) {
-		return s.global.Get(key)
+	return nil
+}
+
+// slot returns the field holding a level's variable set.
+func (s *Scope) slot(level Level) **VariableSet {
+	switch level {
+	case LevelGlobal:
+		return &s.global
+	case LevelEnvironment:
+		return &s.environment
+	case LevelCollection:
+		return &s.collection
+	case LevelFolder:
+		return &s.folder
+	case LevelRequest:
+		return &s.request
+	case LevelIteration:
+		return &s.iteration
+	default:
+		return nil
 	}
-	return ""
 }
 
-// Has checks if a variable exists at any level.
-func (s *Scope) Has(key string) bool {
+// levels lists the levels from highest to lowest precedence.
+var levels = []Level{LevelIteration, LevelRequest, LevelFolder, LevelEnvironment, LevelCollection, LevelGlobal}
+
+// Resolve returns a variable's value and the level it resolved from.
+func (s *Scope) Resolve(key string) (string, Level, bool) {
+	return s.resolveBelow(key, LevelIteration)
+}
+
+// resolveBelow resolves key from the levels at or below max.
+func (s *Scope) resolveBelow(key string, max Level) (string, Level, bool) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	if s.request != nil && s.request.Has(key) {
-		return true
-	}
-	if s.collection != nil && s.collection.Has(key) {
-		return true
-	}
-	if s.environment != nil && s.environment.Has(key) {
-		return true
+	for _, level := range levels {
+		if level > max {
+			continue
+		}
+		if vs := *s.slot(level); vs != nil {
+			vs.mu.RLock()
+			value, ok := vs.data[key]
+			vs.mu.RUnlock()
+			if ok {
+				return value, level, true
+			}
+		}
 	}
-	if s.global != nil && s.global.Has(key) {
-		return true
+	return "", LevelNone, false
+}
+
+// Sources returns every level defining key, highest precedence first. The
+// first level is the one key resolves from; the others are shadowed by it.
+func (s *Scope) Sources(key string) []Level {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var sources []Level
+	for _, level := range levels {
+		if vs := *s.slot(level); vs != nil && vs.Has(key) {
+			sources = append(sources, level)
+		}
 	}
-	return false
+	return sources
+}
+
+// Get gets a variable value using precedence order.
+func (s *Scope) Get(key string) string {
+	value, _, _ := s.Resolve(key)
+	return value
+}
+
+// Has checks if a variable exists at any level.
+func (s *Scope) Has(key string) bool {
+	_, _, ok := s.Resolve(key)
+	return ok
 }
 
 // Set sets a variable at the request level (or creates request level if needed).
 func (s *Scope) Set(key, value string) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-
-	if s.request == nil {
-		s.request = NewVariableSet()
-	}
-	s.request.Set(key, value)
+	s.SetAt(LevelRequest, key, value)
 }
 
 // SetAt sets a variable at a specific level.
@@ -226,79 +308,43 @@ func (s *Scope) SetAt(level Level, key, value string) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	switch level {
-	case LevelGlobal:
-		if s.global == nil {
-			s.global = NewVariableSet()
-		}
-		s.global.Set(key, value)
-	case LevelEnvironment:
-		if s.environment == nil {
-			s.environment = NewVariableSet()
-		}
-		s.environment.Set(key, value)
-	case LevelCollection:
-		if s.collection == nil {
-			s.collection = NewVariableSet()
-		}
-		s.collection.Set(key, value)
-	case LevelRequest:
-		if s.request == nil {
-			s.request = NewVariableSet()
-		}
-		s.request.Set(key, value)
+	slot := s.slot(level)
+	if slot == nil {
+		return
 	}
+	if *slot == nil {
+		*slot = NewVariableSet()
+	}
+	(*slot).Set(key, value)
 }
 
 // GetSource returns the level where a variable is defined (highest precedence).
 func (s *Scope) GetSource(key string) Level {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
-	if s.request != nil && s.request.Has(key) {
-		return LevelRequest
-	}
-	if s.collection != nil && s.collection.Has(key) {
-		return LevelCollection
-	}
-	if s.environment != nil && s.environment.Has(key) {
-		return LevelEnvironment
-	}
-	if s.global != nil && s.global.Has(key) {
-		return LevelGlobal
-	}
-	return LevelNone
+	_, level, _ := s.Resolve(key)
+	return level
 }
 
 // All returns all variables merged with correct precedence.
 func (s *Scope) All() map[string]string {
+	return s.below(LevelIteration)
+}
+
+// below merges the levels at or below max, higher levels overwriting lower.
+func (s *Scope) below(max Level) map[string]string {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
 	result := make(map[string]string)
-
-	// Add in order of lowest to highest precedence (so higher overwrites)
-	if s.global != nil {
-		for k, v := range s.global.All() {
-			result[k] = v
+	for i := len(levels) - 1; i >= 0; i-- {
+		if levels[i] > max {
+			continue
 		}
-	}
-	if s.environment != nil {
-		for k, v := range s.environment.All() {
-			result[k] = v
+		if vs := *s.slot(levels[i]); vs != nil {
+			for k, v := range vs.All() {
+				result[k] = v
+			}
 		}
 	}
-	if s.collection != nil {
-		for k, v := range s.collection.All() {
-			result[k] = v
-		}
-	}
-	if s.request != nil {
-		for k, v := range s.request.All() {
-			result[k] = v
-		}
-	}
-
 	return result
 }
 
@@ -312,7 +358,9 @@ func (s *Scope) Clear() {
 	}
 	s.environment = nil
 	s.collection = nil
+	s.folder = nil
 	s.request = nil
+	s.iteration = nil
 }
 
 // ClearLevel clears a specific level.
@@ -325,28 +373,21 @@ func (s *Scope) ClearLevel(level Level) {
 		if s.global != nil {
 			s.global.Clear()
 		}
-	case LevelEnvironment:
-		s.environment = nil
-	case LevelCollection:
-		s.collection = nil
-	case LevelRequest:
-		s.request = nil
+	default:
+		if slot := s.slot(level); slot != nil {
+			*slot = nil
+		}
 	}
 }
 
 // Interpolate interpolates a string using scoped variables.
 func (s *Scope) Interpolate(input string) (string, error) {
-	// Create engine with all scoped variables
-	engine := NewEngine()
-	engine.SetVariables(s.All())
-	return engine.Interpolate(input)
+	return NewEngineWithScope(s).Interpolate(input)
 }
 
 // InterpolateMap interpolates all values in a string map.
 func (s *Scope) InterpolateMap(input map[string]string) (map[string]string, error) {
-	engine := NewEngine()
-	engine.SetVariables(s.All())
-	return engine.InterpolateMap(input)
+	return NewEngineWithScope(s).InterpolateMap(input)
 }
 
 // Clone creates a copy of the scope.
@@ -355,19 +396,28 @@ func (s *Scope) Clone() *Scope {
 	defer s.mu.RUnlock()
 
 	clone := NewScope()
-
-	if s.global != nil {
-		clone.global = s.global.Clone()
-	}
-	if s.environment != nil {
-		clone.environment = s.environment.Clone()
+	for _, level := range levels {
+		if vs := *s.slot(level); vs != nil {
+			*clone.slot(level) = vs.Clone()
+		}
 	}
-	if s.collection != nil {
-		clone.collection = s.collection.Clone()
+	return clone
+}
+
+// With returns a scope that shares this scope's variable sets except for
+// level, which is replaced by vs. Writes to the shared levels are seen by
+// both scopes, so a request can get its own folder level while extractions
+// still reach the run's request level.
+func (s *Scope) With(level Level, vs *VariableSet) *Scope {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	derived := &Scope{}
+	for _, l := range levels {
+		*derived.slot(l) = *s.slot(l)
 	}
-	if s.request != nil {
-		clone.request = s.request.Clone()
+	if slot := derived.slot(level); slot != nil {
+		*slot = vs
 	}
-
-	return clone
+	return derived
 }
@@ -66,7 +66,7 @@ func TestScope_Precedence(t *testing.T) {
 		assert.Equal(t, "request_value", scope.Get("name"))
 	})
 
-	t.Run("collection overrides environment", func(t *testing.T) {
+	t.Run("environment overrides collection", func(t *testing.T) {
 		scope := NewScope()
 
 		env := NewVariableSet()
@@ -77,7 +77,7 @@ func TestScope_Precedence(t *testing.T) {
 		coll.Set("name", "collection_value")
 		scope.SetCollection(coll)
 
-		assert.Equal(t, "collection_value", scope.Get("name"))
+		assert.Equal(t, "env_value", scope.Get("name"))
 	})
 
 	t.Run("environment overrides global", func(t *testing.T) {
@@ -153,7 +153,7 @@ func TestScope_All(t *testing.T) {
 
 		assert.Equal(t, "global_a", all["a"])
 		assert.Equal(t, "env_b", all["b"])
-		assert.Equal(t, "coll_c", all["c"])
+		assert.Equal(t, "env_c", all["c"])
 		assert.Equal(t, "coll_d", all["d"])
 	})
 }
@@ -759,13 +759,62 @@ func TestScope_SetAtCoverage(t *testing.T) {
 		scope.SetAt(LevelGlobal, "key", "global")
 		assert.Equal(t, "global", scope.Get("key"))
 
-		scope.SetAt(LevelEnvironment, "key", "environment")
-		assert.Equal(t, "environment", scope.Get("key"))
-
 		scope.SetAt(LevelCollection, "key", "collection")
 		assert.Equal(t, "collection", scope.Get("key"))
 
+		scope.SetAt(LevelEnvironment, "key", "environment")
+		assert.Equal(t, "environment", scope.Get("key"))
+
 		scope.SetAt(LevelRequest, "key", "request")
 		assert.Equal(t, "request", scope.Get("key"))
 	})
 }
+
+func TestScope_FolderAndIterationLevels(t *testing.T) {
+	t.Run("resolves narrowest level first", func(t *testing.T) {
+		scope := NewScope()
+		scope.Global().Set("host", "global.example.com")
+		scope.SetAt(LevelEnvironment, "host", "env.example.com")
+		scope.SetAt(LevelCollection, "host", "collection.example.com")
+		scope.SetAt(LevelFolder, "host", "folder.example.com")
+
+		value, level, ok := scope.Resolve("host")
+		assert.True(t, ok)
+		assert.Equal(t, "folder.example.com", value)
+		assert.Equal(t, LevelFolder, level)
+
+		scope.SetAt(LevelRequest, "host", "request.example.com")
+		assert.Equal(t, LevelRequest, scope.GetSource("host"))
+
+		scope.SetAt(LevelIteration, "host", "row.example.com")
+		assert.Equal(t, "row.example.com", scope.Get("host"))
+		assert.Equal(t, LevelIteration, scope.GetSource("host"))
+	})
+
+	t.Run("Sources lists shadowed levels", func(t *testing.T) {
+		scope := NewScope()
+		scope.Global().Set("token", "g")
+		scope.SetAt(LevelFolder, "token", "f")
+
+		assert.Equal(t, []Level{LevelFolder, LevelGlobal}, scope.Sources("token"))
+		assert.Empty(t, scope.Sources("missing"))
+	})
+
+	t.Run("With shares other levels", func(t *testing.T) {
+		scope := NewScope()
+		scope.SetAt(LevelEnvironment, "host", "env")
+		scope.SetAt(LevelRequest, "seen", "no")
+
+		derived := scope.With(LevelFolder, NewVariableSetFrom(map[string]string{"host": "folder"}))
+		derived.Set("seen", "yes")
+
+		assert.Equal(t, "folder", derived.Get("host"))
+		assert.Equal(t, "env", scope.Get("host"))
+		assert.Equal(t, "yes", scope.Get("seen"))
+	})
+
+	t.Run("level names", func(t *testing.T) {
+		assert.Equal(t, "folder", LevelFolder.String())
+		assert.Equal(t, "iteration", LevelIteration.String())
+	})
+}
@@ -0,0 +1,210 @@
+// Package jsonpath implements the JSONPath subset used across currier:
+// $.a.b, $.items[0], $.items[-1], $.items[*].id, $.meta.*, $['odd key']
+// and $..id for a key at any depth.
+package jsonpath
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Path is a parsed JSONPath expression.
+type Path struct {
+	raw      string
+	segments []segment
+}
+
+// segment is one step of a path.
+type segment struct {
+	key       string
+	index     int
+	isIndex   bool
+	wildcard  bool
+	recursive bool // Matches at any depth (..)
+}
+
+func (s segment) matchesKey(key string) bool {
+	return s.wildcard || !s.isIndex && s.key == key
+}
+
+func (s segment) matchesIndex(i, n int) bool {
+	if s.wildcard {
+		return true
+	}
+	if !s.isIndex {
+		return false
+	}
+	if s.index < 0 {
+		return s.index+n == i
+	}
+	return s.index == i
+}
+
+// Parse parses a JSONPath expression. It must start with $; "$" alone
+// selects the whole document.
+func Parse(p string) (*Path, error) {
+	p = strings.TrimSpace(p)
+	if p == "" {
+		return nil, fmt.Errorf("path is empty")
+	}
+	if p[0] != '$' {
+		return nil, fmt.Errorf("path must start with $")
+	}
+
+	path := &Path{raw: p}
+	for i := 1; i < len(p); {
+		seg := segment{}
+		switch {
+		case strings.HasPrefix(p[i:], ".."):
+			seg.recursive = true
+			i += 2
+		case p[i] == '.':
+			i++
+		case p[i] == '[':
+		default:
+			return nil, fmt.Errorf("unexpected %q at offset %d", p[i], i)
+		}
+
+		if i < len(p) && p[i] == '[' {
+			end := strings.IndexByte(p[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed [")
+			}
+			inner := strings.TrimSpace(p[i+1 : i+end])
+			i += end + 1
+
+			switch {
+			case inner == "*":
+				seg.wildcard = true
+			case len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0]:
+				seg.key = inner[1 : len(inner)-1]
+			default:
+				n, err := strconv.Atoi(inner)
+				if err != nil {
+					return nil, fmt.Errorf("invalid index %q", inner)
+				}
+				seg.index = n
+				seg.isIndex = true
+			}
+		} else {
+			j := i
+			for j < len(p) && p[j] != '.' && p[j] != '[' {
+				j++
+			}
+			name := p[i:j]
+			if name == "" {
+				return nil, fmt.Errorf("missing key at offset %d", i)
+			}
+			i = j
+			if name == "*" {
+				seg.wildcard = true
+			} else {
+				seg.key = name
+			}
+		}
+
+		path.segments = append(path.segments, seg)
+	}
+
+	return path, nil
+}
+
+// MustParse is like Parse but panics on an invalid path.
+func MustParse(p string) *Path {
+	path, err := Parse(p)
+	if err != nil {
+		panic(fmt.Sprintf("jsonpath: %q: %v", p, err))
+	}
+	return path
+}
+
+// String returns the expression the path was parsed from.
+func (p *Path) String() string {
+	return p.raw
+}
+
+// IsRoot reports whether the path selects the whole document.
+func (p *Path) IsRoot() bool {
+	return len(p.segments) == 0
+}
+
+// Select returns every value in doc matched by the path, in document order
+// with object keys sorted. doc is a decoded JSON value.
+func (p *Path) Select(doc any) []any {
+	var out []any
+	selectSegments(doc, p.segments, &out)
+	return out
+}
+
+func selectSegments(value any, segments []segment, out *[]any) {
+	if len(segments) == 0 {
+		*out = append(*out, value)
+		return
+	}
+	seg, rest := segments[0], segments[1:]
+
+	switch node := value.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(node))
+		for key := range node {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if seg.matchesKey(key) {
+				selectSegments(node[key], rest, out)
+			}
+			if seg.recursive {
+				selectSegments(node[key], segments, out)
+			}
+		}
+	case []any:
+		for i := range node {
+			if seg.matchesIndex(i, len(node)) {
+				selectSegments(node[i], rest, out)
+			}
+			if seg.recursive {
+				selectSegments(node[i], segments, out)
+			}
+		}
+	}
+}
+
+// Replace replaces every value in doc matched by the path with the result
+// of fn, modifying doc in place. It returns the updated document, which
+// differs from doc only for the root path.
+func (p *Path) Replace(doc any, fn func(any) any) any {
+	return replaceSegments(doc, p.segments, fn)
+}
+
+func replaceSegments(value any, segments []segment, fn func(any) any) any {
+	if len(segments) == 0 {
+		return fn(value)
+	}
+	seg, rest := segments[0], segments[1:]
+
+	switch node := value.(type) {
+	case map[string]any:
+		for key := range node {
+			if seg.matchesKey(key) {
+				node[key] = replaceSegments(node[key], rest, fn)
+			}
+			if seg.recursive {
+				node[key] = replaceSegments(node[key], segments, fn)
+			}
+		}
+	case []any:
+		for i := range node {
+			if seg.matchesIndex(i, len(node)) {
+				node[i] = replaceSegments(node[i], rest, fn)
+			}
+			if seg.recursive {
+				node[i] = replaceSegments(node[i], segments, fn)
+			}
+		}
+	}
+
+	return value
+}
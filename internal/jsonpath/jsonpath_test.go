@@ -0,0 +1,76 @@
+package jsonpath
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const doc = `{"id":0,"items":[{"id":1,"at":"x"},{"id":2,"at":"y"}],"meta":{"id":3,"odd key":true}}`
+
+func decode(t *testing.T, s string) any {
+	t.Helper()
+	var v any
+	require.NoError(t, json.Unmarshal([]byte(s), &v))
+	return v
+}
+
+func TestPath_Select(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"$", doc},
+		{"$.id", `[0]`},
+		{"$.items[1].at", `["y"]`},
+		{"$.items[-1].id", `[2]`},
+		{"$.items[*].id", `[1,2]`},
+		{"$..id", `[0,1,2,3]`},
+		{"$['meta']['odd key']", `[true]`},
+		{"$.meta.*", `[3,true]`},
+		{"$.items.length", `null`},
+		{"$.missing.id", `null`},
+		{"$.items[5]", `null`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			path, err := Parse(tt.path)
+			require.NoError(t, err)
+
+			got := path.Select(decode(t, doc))
+			if tt.path == "$" {
+				require.Len(t, got, 1)
+				assert.Equal(t, decode(t, tt.want), got[0])
+				return
+			}
+			want, _ := decode(t, tt.want).([]any)
+			assert.Equal(t, want, got)
+		})
+	}
+}
+
+func TestPath_Replace(t *testing.T) {
+	mask := func(any) any { return "<x>" }
+
+	got := MustParse("$..id").Replace(decode(t, doc), mask)
+	assert.Equal(t, decode(t, `{"id":"<x>","items":[{"id":"<x>","at":"x"},{"id":"<x>","at":"y"}],"meta":{"id":"<x>","odd key":true}}`), got)
+
+	got = MustParse("$.items[-1]").Replace(decode(t, doc), mask)
+	assert.Equal(t, decode(t, `{"id":0,"items":[{"id":1,"at":"x"},"<x>"],"meta":{"id":3,"odd key":true}}`), got)
+
+	assert.Equal(t, "<x>", MustParse("$").Replace(decode(t, doc), mask))
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, p := range []string{"", "id", "$.", "$.items[", "$.items[x]", "$x"} {
+		_, err := Parse(p)
+		assert.Error(t, err, p)
+	}
+
+	assert.Panics(t, func() { MustParse("$[") })
+	assert.Equal(t, "$.a", MustParse(" $.a ").String())
+	assert.True(t, MustParse("$").IsRoot())
+}
@@ -0,0 +1,69 @@
+package jsonschema
+
+import (
+	"math"
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	uuidPattern     = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	hostnamePattern = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9-]{0,61}[A-Za-z0-9])?(\.[A-Za-z0-9]([A-Za-z0-9-]{0,61}[A-Za-z0-9])?)*\.?$`)
+	durationPattern = regexp.MustCompile(`^P(\d+W|(\d+Y)?(\d+M)?(\d+D)?(T(\d+H)?(\d+M)?(\d+(\.\d+)?S)?)?)$`)
+)
+
+// checkStringFormat reports whether s is valid for a known string format.
+// Unknown formats always pass.
+func checkStringFormat(format, s string) bool {
+	switch format {
+	case "date-time":
+		_, err := time.Parse(time.RFC3339Nano, strings.ToUpper(s))
+		return err == nil
+	case "date":
+		_, err := time.Parse("2006-01-02", s)
+		return err == nil
+	case "time":
+		_, err := time.Parse(time.RFC3339Nano, "2000-01-01T"+strings.ToUpper(s))
+		return err == nil
+	case "duration":
+		return s != "P" && !strings.HasSuffix(s, "T") && durationPattern.MatchString(s)
+	case "email", "idn-email":
+		addr, err := mail.ParseAddress(s)
+		return err == nil && addr.Address == s
+	case "uuid":
+		return uuidPattern.MatchString(s)
+	case "uri", "iri":
+		u, err := url.Parse(s)
+		return err == nil && u.Scheme != ""
+	case "uri-reference", "iri-reference":
+		_, err := url.Parse(s)
+		return err == nil
+	case "ipv4":
+		ip := net.ParseIP(s)
+		return ip != nil && ip.To4() != nil && !strings.Contains(s, ":")
+	case "ipv6":
+		return net.ParseIP(s) != nil && strings.Contains(s, ":")
+	case "hostname", "idn-hostname":
+		return len(s) <= 253 && hostnamePattern.MatchString(s)
+	case "regex":
+		_, err := regexp.Compile(s)
+		return err == nil
+	}
+	return true
+}
+
+// checkNumberFormat reports whether a number is valid for the OpenAPI
+// numeric formats int32 and int64. Other formats always pass.
+func checkNumberFormat(format string, n float64, instance any) bool {
+	switch format {
+	case "int32":
+		return isInteger(instance) && n >= math.MinInt32 && n <= math.MaxInt32
+	case "int64":
+		return isInteger(instance) && n >= math.MinInt64 && n <= math.MaxInt64
+	}
+	return true
+}
@@ -0,0 +1,252 @@
+// Package jsonschema validates JSON values against JSON Schema documents.
+// It supports drafts 7 and 2020-12, and the OpenAPI 3.0 dialect with its
+// nullable keyword and boolean exclusive bounds. Schemas and instances are
+// the values produced by encoding/json or yaml.v3 decoding into any.
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Draft identifies a JSON Schema dialect.
+type Draft int
+
+const (
+	Draft2020 Draft = iota // JSON Schema 2020-12 (also used for 2019-09)
+	Draft7                 // JSON Schema draft 7 (also used for drafts 4 and 6)
+	OpenAPI30              // OpenAPI 3.0 schema objects
+)
+
+// maxDepth limits $ref recursion so self-referencing schemas can't loop.
+const maxDepth = 256
+
+// ValidationError describes one way an instance fails its schema.
+type ValidationError struct {
+	Pointer string // JSON pointer to the failing value in the instance
+	Keyword string // Schema keyword that failed, e.g. "type" or "required"
+	Message string
+}
+
+func (e ValidationError) Error() string {
+	return FormatPointer(e.Pointer) + ": " + e.Message
+}
+
+// FormatPointer returns a JSON pointer for display, using "(root)" for the
+// whole document.
+func FormatPointer(pointer string) string {
+	if pointer == "" {
+		return "(root)"
+	}
+	return pointer
+}
+
+// Schema is a compiled schema ready for validation.
+type Schema struct {
+	schema any
+	root   any
+	draft  Draft
+	ids    map[string]any // Subschemas by $id and $anchor
+
+	patterns sync.Map // Compiled pattern regexps by source
+}
+
+// Option configures a Schema.
+type Option func(*Schema)
+
+// WithDraft sets the dialect, overriding detection from $schema.
+func WithDraft(d Draft) Option {
+	return func(s *Schema) {
+		s.draft = d
+	}
+}
+
+// WithRoot sets the document that $ref pointers are resolved against, for
+// schemas embedded in a larger document such as an OpenAPI spec.
+func WithRoot(root any) Option {
+	return func(s *Schema) {
+		s.root = root
+	}
+}
+
+// New compiles a decoded schema. The dialect is detected from $schema and
+// defaults to 2020-12.
+func New(schema any, opts ...Option) (*Schema, error) {
+	switch schema.(type) {
+	case map[string]any, bool:
+	default:
+		return nil, fmt.Errorf("schema must be an object or boolean, got %s", typeName(schema))
+	}
+
+	s := &Schema{schema: schema, root: schema, draft: detectDraft(schema)}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.ids = make(map[string]any)
+	indexIDs(s.root, s.ids)
+	indexIDs(s.schema, s.ids)
+
+	return s, nil
+}
+
+// Parse decodes and compiles a JSON schema.
+func Parse(data []byte, opts ...Option) (*Schema, error) {
+	var schema any
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, fmt.Errorf("invalid schema JSON: %w", err)
+	}
+	return New(schema, opts...)
+}
+
+// detectDraft reads the dialect from a schema's $schema keyword.
+func detectDraft(schema any) Draft {
+	m, ok := schema.(map[string]any)
+	if !ok {
+		return Draft2020
+	}
+	uri, _ := m["$schema"].(string)
+	switch {
+	case strings.Contains(uri, "draft-07"), strings.Contains(uri, "draft-06"), strings.Contains(uri, "draft-04"):
+		return Draft7
+	default:
+		return Draft2020
+	}
+}
+
+// indexIDs records subschemas with an $id or $anchor so $ref can find them.
+func indexIDs(node any, ids map[string]any) {
+	switch n := node.(type) {
+	case map[string]any:
+		if id, ok := n["$id"].(string); ok && id != "" {
+			ids[strings.TrimSuffix(id, "#")] = n
+			if u, err := url.Parse(id); err == nil && u.Path != "" {
+				ids[lastSegment(u.Path)] = n
+			}
+		}
+		if anchor, ok := n["$anchor"].(string); ok && anchor != "" {
+			ids["#"+anchor] = n
+		}
+		// Draft 7 anchors are plain-name fragments in $id
+		if id, ok := n["$id"].(string); ok && strings.HasPrefix(id, "#") && len(id) > 1 {
+			ids[id] = n
+		}
+		for key, child := range n {
+			if key == "enum" || key == "const" || key == "examples" || key == "example" || key == "default" {
+				continue
+			}
+			indexIDs(child, ids)
+		}
+	case []any:
+		for _, child := range n {
+			indexIDs(child, ids)
+		}
+	}
+}
+
+func lastSegment(p string) string {
+	if i := strings.LastIndex(p, "/"); i >= 0 {
+		return p[i+1:]
+	}
+	return p
+}
+
+// Validate checks an instance against the schema and returns every
+// violation, ordered by instance location.
+func (s *Schema) Validate(instance any) []ValidationError {
+	var errs []ValidationError
+	s.validate(s.schema, instance, "", 0, &errs)
+	sort.SliceStable(errs, func(i, j int) bool {
+		return errs[i].Pointer < errs[j].Pointer
+	})
+	return errs
+}
+
+// ValidateJSON decodes a JSON document and validates it.
+func (s *Schema) ValidateJSON(data []byte) ([]ValidationError, error) {
+	instance, err := DecodeJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	return s.Validate(instance), nil
+}
+
+// DecodeJSON decodes a JSON document keeping numbers exact.
+func DecodeJSON(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var instance any
+	if err := dec.Decode(&instance); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("invalid JSON: unexpected data after the document")
+	}
+	return instance, nil
+}
+
+// ResolvePointer looks up a JSON pointer ("/a/b/0") in a decoded document.
+func ResolvePointer(doc any, pointer string) (any, bool) {
+	if pointer == "" {
+		return doc, true
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, false
+	}
+
+	node := doc
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		switch n := node.(type) {
+		case map[string]any:
+			child, ok := n[token]
+			if !ok {
+				return nil, false
+			}
+			node = child
+		case []any:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(n) {
+				return nil, false
+			}
+			node = n[i]
+		default:
+			return nil, false
+		}
+	}
+	return node, true
+}
+
+// EscapePointerToken escapes one reference token of a JSON pointer.
+func EscapePointerToken(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+// Equal reports whether two decoded JSON values are equal, comparing numbers
+// by value.
+func Equal(a, b any) bool {
+	return equal(a, b)
+}
+
+// IsType reports whether a decoded JSON value has the named JSON Schema
+// type; integers are also numbers.
+func IsType(v any, name string) bool {
+	return isType(v, name)
+}
+
+// TypeName returns the JSON type of a decoded value, reporting integral
+// numbers as integer.
+func TypeName(v any) string {
+	return typeName(v)
+}
+
+// FormatValue renders a decoded value as compact JSON for messages.
+func FormatValue(v any) string {
+	return formatValue(v)
+}
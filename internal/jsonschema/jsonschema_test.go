@@ -0,0 +1,176 @@
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func validate(t *testing.T, schema, instance string, opts ...Option) []ValidationError {
+	t.Helper()
+	s, err := Parse([]byte(schema), opts...)
+	require.NoError(t, err)
+	errs, err := s.ValidateJSON([]byte(instance))
+	require.NoError(t, err)
+	return errs
+}
+
+func TestSchema_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   string
+		instance string
+		want     []string // Expected errors as "pointer: message"
+	}{
+		{"type ok", `{"type":"integer"}`, `42`, nil},
+		{"type mismatch", `{"type":"integer"}`, `4.5`, []string{"(root): expected integer, got number"}},
+		{"integer-valued float", `{"type":"integer"}`, `4.0`, nil},
+		{"type union", `{"type":["string","null"]}`, `true`, []string{"(root): expected string or null, got boolean"}},
+		{"enum", `{"enum":["a",1]}`, `"b"`, []string{`(root): must be one of "a", 1`}},
+		{"enum numeric", `{"enum":[1]}`, `1.0`, nil},
+		{"const", `{"const":{"a":1}}`, `{"a":2}`, []string{`(root): must be {"a":1}`}},
+		{"minimum", `{"minimum":5}`, `3`, []string{"(root): must be at least 5"}},
+		{"exclusive bounds", `{"exclusiveMinimum":0,"exclusiveMaximum":10}`, `10`, []string{"(root): must be less than 10"}},
+		{"multipleOf", `{"multipleOf":0.1}`, `0.3`, nil},
+		{"multipleOf fails", `{"multipleOf":3}`, `10`, []string{"(root): must be a multiple of 3"}},
+		{"string length", `{"minLength":2,"maxLength":3}`, `"héllo"`, []string{"(root): must be at most 3 characters long"}},
+		{"pattern", `{"pattern":"^[a-z]+$"}`, `"abc1"`, []string{`(root): must match pattern "^[a-z]+$"`}},
+		{"format date-time", `{"format":"date-time"}`, `"2024-01-02T03:04:05Z"`, nil},
+		{"format email", `{"format":"email"}`, `"not-an-email"`, []string{"(root): is not a valid email"}},
+		{"format uuid", `{"format":"uuid"}`, `"550e8400-e29b-41d4-a716-446655440000"`, nil},
+		{"unknown format", `{"format":"color"}`, `"blue"`, nil},
+		{
+			"required and nested properties",
+			`{"type":"object","required":["id","name"],"properties":{"id":{"type":"integer"},"tags":{"type":"array","items":{"type":"string"}}}}`,
+			`{"id":"7","tags":["a",2]}`,
+			[]string{`(root): missing required property "name"`, "/id: expected integer, got string", "/tags/1: expected string, got integer"},
+		},
+		{
+			"additionalProperties false",
+			`{"properties":{"a":{}},"additionalProperties":false}`,
+			`{"a":1,"b/c":2}`,
+			[]string{`/b~1c: property "b/c" is not allowed`},
+		},
+		{
+			"patternProperties",
+			`{"patternProperties":{"^x-":{"type":"string"}},"additionalProperties":{"type":"integer"}}`,
+			`{"x-a":"ok","n":1,"m":"no"}`,
+			[]string{"/m: expected integer, got string"},
+		},
+		{"propertyNames", `{"propertyNames":{"maxLength":2}}`, `{"abc":1}`, []string{"/abc: invalid property name: must be at most 2 characters long"}},
+		{"min properties", `{"minProperties":1}`, `{}`, []string{"(root): must have at least 1 properties"}},
+		{"dependentRequired", `{"dependentRequired":{"card":["cvv"]}}`, `{"card":"1"}`, []string{`(root): missing property "cvv", required when "card" is present`}},
+		{"draft 7 dependencies", `{"$schema":"http://json-schema.org/draft-07/schema#","dependencies":{"a":{"required":["b"]}}}`, `{"a":1}`, []string{`(root): missing required property "b"`}},
+		{"array bounds", `{"minItems":2,"uniqueItems":true}`, `[1,1]`, []string{"(root): items 0 and 1 are equal"}},
+		{"prefixItems", `{"prefixItems":[{"type":"string"}],"items":{"type":"integer"}}`, `["a",1,"b"]`, []string{"/2: expected integer, got string"}},
+		{"draft 7 tuple", `{"$schema":"http://json-schema.org/draft-07/schema#","items":[{"type":"string"}],"additionalItems":false}`, `["a",1]`, []string{"/1: no value is allowed here"}},
+		{"contains", `{"contains":{"const":3},"maxContains":1}`, `[1,3,3]`, []string{"(root): must contain at most 1 matching items, found 2"}},
+		{"contains missing", `{"contains":{"const":3}}`, `[1]`, []string{"(root): must contain at least 1 matching items, found 0"}},
+		{"allOf", `{"allOf":[{"minimum":1},{"maximum":2}]}`, `3`, []string{"(root): must be at most 2"}},
+		{"anyOf", `{"anyOf":[{"type":"string"},{"type":"integer"}]}`, `true`, []string{"(root): does not match any of the anyOf schemas"}},
+		{"oneOf ambiguous", `{"oneOf":[{"type":"integer"},{"minimum":0}]}`, `1`, []string{"(root): matches oneOf schemas 0, 1, expected exactly one"}},
+		{"not", `{"not":{"type":"null"}}`, `null`, []string{"(root): must not match the schema in not"}},
+		{"if then else", `{"if":{"properties":{"kind":{"const":"a"}}},"then":{"required":["a"]},"else":{"required":["b"]}}`, `{"kind":"z"}`, []string{`(root): missing required property "b"`}},
+		{"false schema", `false`, `1`, []string{"(root): no value is allowed here"}},
+		{
+			"local $ref",
+			`{"$defs":{"pos":{"type":"integer","minimum":1}},"properties":{"n":{"$ref":"#/$defs/pos"}}}`,
+			`{"n":0}`,
+			[]string{"/n: must be at least 1"},
+		},
+		{
+			"recursive $ref",
+			`{"type":"object","properties":{"name":{"type":"string"},"children":{"type":"array","items":{"$ref":"#"}}}}`,
+			`{"name":"a","children":[{"name":"b","children":[{"name":3}]}]}`,
+			[]string{"/children/0/children/0/name: expected string, got integer"},
+		},
+		{"$anchor", `{"$defs":{"s":{"$anchor":"str","type":"string"}},"items":{"$ref":"#str"}}`, `[1]`, []string{"/0: expected string, got integer"}},
+		{"unresolvable $ref", `{"$ref":"#/nope"}`, `1`, []string{`(root): cannot resolve $ref "#/nope"`}},
+		{"$ref siblings apply in 2020-12", `{"$defs":{"n":{"type":"integer"}},"$ref":"#/$defs/n","minimum":5}`, `1`, []string{"(root): must be at least 5"}},
+		{"$ref siblings ignored in draft 7", `{"$schema":"http://json-schema.org/draft-07/schema#","definitions":{"n":{"type":"integer"}},"$ref":"#/definitions/n","minimum":5}`, `1`, nil},
+		{
+			"unevaluatedProperties",
+			`{"allOf":[{"properties":{"a":{}}}],"properties":{"b":{}},"unevaluatedProperties":false}`,
+			`{"a":1,"b":2,"c":3}`,
+			[]string{`/c: property "c" is not allowed`},
+		},
+		{"unevaluatedItems", `{"prefixItems":[{}],"unevaluatedItems":false}`, `[1,2]`, []string{"/1: item is not allowed"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := validate(t, tt.schema, tt.instance)
+			var got []string
+			for _, e := range errs {
+				got = append(got, e.Error())
+			}
+			assert.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
+
+func TestSchema_OpenAPI30(t *testing.T) {
+	schema := `{"type":"string","nullable":true,"minimum":1}`
+
+	assert.Empty(t, validate(t, schema, `null`, WithDraft(OpenAPI30)))
+	assert.Len(t, validate(t, schema, `null`), 1, "nullable only applies to OpenAPI 3.0 schemas")
+
+	errs := validate(t, `{"maximum":10,"exclusiveMaximum":true}`, `10`, WithDraft(OpenAPI30))
+	require.Len(t, errs, 1)
+	assert.Equal(t, "exclusiveMaximum", errs[0].Keyword)
+
+	errs = validate(t, `{"type":"integer","format":"int32"}`, `3000000000`, WithDraft(OpenAPI30))
+	require.Len(t, errs, 1)
+	assert.Equal(t, "(root): is not a valid int32", errs[0].Error())
+}
+
+func TestSchema_WithRoot(t *testing.T) {
+	doc := map[string]any{
+		"components": map[string]any{
+			"schemas": map[string]any{
+				"Pet": map[string]any{"type": "object", "required": []any{"name"}},
+			},
+		},
+	}
+	s, err := New(map[string]any{"type": "array", "items": map[string]any{"$ref": "#/components/schemas/Pet"}}, WithRoot(doc))
+	require.NoError(t, err)
+
+	errs := s.Validate([]any{map[string]any{"name": "Rex"}, map[string]any{}})
+	require.Len(t, errs, 1)
+	assert.Equal(t, `/1: missing required property "name"`, errs[0].Error())
+}
+
+func TestSchema_SelfReferenceLoop(t *testing.T) {
+	errs := validate(t, `{"$ref":"#"}`, `1`)
+	require.NotEmpty(t, errs)
+	assert.Contains(t, errs[0].Message, "too deep")
+}
+
+func TestNew_Invalid(t *testing.T) {
+	_, err := New("nope")
+	assert.Error(t, err)
+
+	_, err = Parse([]byte(`{`))
+	assert.Error(t, err)
+
+	s, err := Parse([]byte(`{}`))
+	require.NoError(t, err)
+	_, err = s.ValidateJSON([]byte(`{} {}`))
+	assert.Error(t, err)
+}
+
+func TestResolvePointer(t *testing.T) {
+	doc := map[string]any{"a/b": map[string]any{"c~d": []any{"x", "y"}}}
+
+	v, ok := ResolvePointer(doc, "/a~1b/c~0d/1")
+	assert.True(t, ok)
+	assert.Equal(t, "y", v)
+
+	_, ok = ResolvePointer(doc, "/missing")
+	assert.False(t, ok)
+
+	v, ok = ResolvePointer(doc, "")
+	assert.True(t, ok)
+	assert.Equal(t, doc, v)
+}
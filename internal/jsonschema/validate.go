@@ -0,0 +1,673 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// annotations records which parts of an instance a schema evaluated, for
+// unevaluatedProperties and unevaluatedItems.
+type annotations struct {
+	props    map[string]bool
+	items    int          // Leading items evaluated by prefixItems or tuple items
+	allItems bool         // items or additionalItems evaluated every item
+	indexes  map[int]bool // Items matched by contains
+}
+
+func newAnnotations() *annotations {
+	return &annotations{props: make(map[string]bool), indexes: make(map[int]bool)}
+}
+
+func (a *annotations) merge(other *annotations) {
+	for k := range other.props {
+		a.props[k] = true
+	}
+	for i := range other.indexes {
+		a.indexes[i] = true
+	}
+	if other.items > a.items {
+		a.items = other.items
+	}
+	a.allItems = a.allItems || other.allItems
+}
+
+func addError(errs *[]ValidationError, pointer, keyword, format string, args ...any) {
+	*errs = append(*errs, ValidationError{Pointer: pointer, Keyword: keyword, Message: fmt.Sprintf(format, args...)})
+}
+
+func childPointer(pointer string, token any) string {
+	switch t := token.(type) {
+	case int:
+		return pointer + "/" + strconv.Itoa(t)
+	default:
+		return pointer + "/" + EscapePointerToken(fmt.Sprint(t))
+	}
+}
+
+// validate checks instance against schema, appending violations to errs,
+// and returns the annotations collected along the way.
+func (s *Schema) validate(schema, instance any, pointer string, depth int, errs *[]ValidationError) *annotations {
+	ann := newAnnotations()
+	if depth > maxDepth {
+		addError(errs, pointer, "$ref", "schema recursion is too deep")
+		return ann
+	}
+
+	var sch map[string]any
+	switch typed := schema.(type) {
+	case bool:
+		if !typed {
+			addError(errs, pointer, "false", "no value is allowed here")
+		}
+		return ann
+	case map[string]any:
+		sch = typed
+	default:
+		return ann
+	}
+
+	for _, key := range []string{"$ref", "$dynamicRef", "$recursiveRef"} {
+		ref, ok := sch[key].(string)
+		if !ok {
+			continue
+		}
+		target, found := s.resolve(ref)
+		if !found {
+			addError(errs, pointer, "$ref", "cannot resolve $ref %q", ref)
+			continue
+		}
+		ann.merge(s.validate(target, instance, pointer, depth+1, errs))
+		if s.draft != Draft2020 {
+			// Before 2019-09, keywords next to $ref are ignored
+			return ann
+		}
+	}
+
+	if s.draft == OpenAPI30 && instance == nil && sch["nullable"] == true {
+		return ann
+	}
+
+	if t, ok := sch["type"]; ok {
+		s.checkType(sch, t, instance, pointer, errs)
+	}
+	if enum, ok := sch["enum"].([]any); ok {
+		found := false
+		for _, e := range enum {
+			if equal(e, instance) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			addError(errs, pointer, "enum", "must be one of %s", formatValues(enum))
+		}
+	}
+	if c, ok := sch["const"]; ok && !equal(c, instance) {
+		addError(errs, pointer, "const", "must be %s", formatValue(c))
+	}
+
+	switch inst := instance.(type) {
+	case string:
+		s.validateString(sch, inst, pointer, errs)
+	case []any:
+		s.validateArray(sch, inst, pointer, depth, errs, ann)
+	case map[string]any:
+		s.validateObject(sch, inst, pointer, depth, errs, ann)
+	default:
+		if n, ok := toNumber(instance); ok {
+			s.validateNumber(sch, n, instance, pointer, errs)
+		}
+	}
+
+	s.validateCombinators(sch, instance, pointer, depth, errs, ann)
+
+	if s.draft == Draft2020 {
+		s.validateUnevaluated(sch, instance, pointer, depth, errs, ann)
+	}
+
+	return ann
+}
+
+// resolve finds the schema a $ref points to: a JSON pointer into the root
+// document, an $anchor, or an $id optionally followed by a fragment.
+func (s *Schema) resolve(ref string) (any, bool) {
+	if target, ok := s.ids[ref]; ok {
+		return target, true
+	}
+
+	base, fragment, _ := strings.Cut(ref, "#")
+	doc := s.root
+	if base != "" {
+		target, ok := s.ids[base]
+		if !ok {
+			target, ok = s.ids[lastSegment(base)]
+		}
+		if !ok {
+			return nil, false
+		}
+		doc = target
+	}
+
+	if fragment == "" {
+		return doc, true
+	}
+	if unescaped, err := url.PathUnescape(fragment); err == nil {
+		fragment = unescaped
+	}
+	if strings.HasPrefix(fragment, "/") {
+		return ResolvePointer(doc, fragment)
+	}
+	target, ok := s.ids["#"+fragment]
+	return target, ok
+}
+
+func (s *Schema) checkType(sch map[string]any, t any, instance any, pointer string, errs *[]ValidationError) {
+	var types []string
+	switch tt := t.(type) {
+	case string:
+		types = []string{tt}
+	case []any:
+		for _, x := range tt {
+			if name, ok := x.(string); ok {
+				types = append(types, name)
+			}
+		}
+	}
+	if s.draft == OpenAPI30 && sch["nullable"] == true {
+		types = append(types, "null")
+	}
+
+	for _, name := range types {
+		if isType(instance, name) {
+			return
+		}
+	}
+	addError(errs, pointer, "type", "expected %s, got %s", strings.Join(types, " or "), typeName(instance))
+}
+
+func isType(instance any, name string) bool {
+	switch name {
+	case "null":
+		return instance == nil
+	case "boolean":
+		_, ok := instance.(bool)
+		return ok
+	case "string":
+		_, ok := instance.(string)
+		return ok
+	case "array":
+		_, ok := instance.([]any)
+		return ok
+	case "object":
+		_, ok := instance.(map[string]any)
+		return ok
+	case "number":
+		_, ok := toNumber(instance)
+		return ok
+	case "integer":
+		return isInteger(instance)
+	}
+	return false
+}
+
+// typeName returns the JSON type of a value, reporting integral numbers as
+// integer.
+func typeName(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	if isInteger(v) {
+		return "integer"
+	}
+	if _, ok := toNumber(v); ok {
+		return "number"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func toNumber(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func isInteger(v any) bool {
+	switch n := v.(type) {
+	case int, int64, int32, uint64, uint:
+		return true
+	case json.Number:
+		if !strings.ContainsAny(string(n), ".eE") {
+			return true
+		}
+	}
+	f, ok := toNumber(v)
+	return ok && !math.IsInf(f, 0) && f == math.Trunc(f)
+}
+
+// equal compares JSON values, treating numbers by value.
+func equal(a, b any) bool {
+	if na, ok := toNumber(a); ok {
+		nb, ok := toNumber(b)
+		return ok && na == nb
+	}
+	switch x := a.(type) {
+	case []any:
+		y, ok := b.([]any)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if !equal(x[i], y[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]any:
+		y, ok := b.(map[string]any)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for k, xv := range x {
+			yv, ok := y[k]
+			if !ok || !equal(xv, yv) {
+				return false
+			}
+		}
+		return true
+	default:
+		return a == b
+	}
+}
+
+func formatValue(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+func formatValues(values []any) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = formatValue(v)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (s *Schema) validateNumber(sch map[string]any, n float64, instance any, pointer string, errs *[]ValidationError) {
+	if min, ok := toNumber(sch["minimum"]); ok {
+		if sch["exclusiveMinimum"] == true {
+			if n <= min {
+				addError(errs, pointer, "exclusiveMinimum", "must be greater than %s", formatValue(sch["minimum"]))
+			}
+		} else if n < min {
+			addError(errs, pointer, "minimum", "must be at least %s", formatValue(sch["minimum"]))
+		}
+	}
+	if max, ok := toNumber(sch["maximum"]); ok {
+		if sch["exclusiveMaximum"] == true {
+			if n >= max {
+				addError(errs, pointer, "exclusiveMaximum", "must be less than %s", formatValue(sch["maximum"]))
+			}
+		} else if n > max {
+			addError(errs, pointer, "maximum", "must be at most %s", formatValue(sch["maximum"]))
+		}
+	}
+	if min, ok := toNumber(sch["exclusiveMinimum"]); ok && n <= min {
+		addError(errs, pointer, "exclusiveMinimum", "must be greater than %s", formatValue(sch["exclusiveMinimum"]))
+	}
+	if max, ok := toNumber(sch["exclusiveMaximum"]); ok && n >= max {
+		addError(errs, pointer, "exclusiveMaximum", "must be less than %s", formatValue(sch["exclusiveMaximum"]))
+	}
+	if m, ok := toNumber(sch["multipleOf"]); ok && m > 0 {
+		q := n / m
+		if math.IsInf(q, 0) || math.Abs(q-math.Round(q)) > 1e-9*math.Max(1, math.Abs(q)) {
+			addError(errs, pointer, "multipleOf", "must be a multiple of %s", formatValue(sch["multipleOf"]))
+		}
+	}
+	if format, ok := sch["format"].(string); ok {
+		if !checkNumberFormat(format, n, instance) {
+			addError(errs, pointer, "format", "is not a valid %s", format)
+		}
+	}
+}
+
+func (s *Schema) validateString(sch map[string]any, str, pointer string, errs *[]ValidationError) {
+	length := utf8.RuneCountInString(str)
+	if min, ok := toNumber(sch["minLength"]); ok && float64(length) < min {
+		addError(errs, pointer, "minLength", "must be at least %s characters long", formatValue(sch["minLength"]))
+	}
+	if max, ok := toNumber(sch["maxLength"]); ok && float64(length) > max {
+		addError(errs, pointer, "maxLength", "must be at most %s characters long", formatValue(sch["maxLength"]))
+	}
+	if pattern, ok := sch["pattern"].(string); ok {
+		re, err := s.compilePattern(pattern)
+		if err != nil {
+			addError(errs, pointer, "pattern", "invalid pattern %q in schema: %v", pattern, err)
+		} else if !re.MatchString(str) {
+			addError(errs, pointer, "pattern", "must match pattern %q", pattern)
+		}
+	}
+	if format, ok := sch["format"].(string); ok {
+		if !checkStringFormat(format, str) {
+			addError(errs, pointer, "format", "is not a valid %s", format)
+		}
+	}
+}
+
+func (s *Schema) compilePattern(pattern string) (*regexp.Regexp, error) {
+	if cached, ok := s.patterns.Load(pattern); ok {
+		return cached.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	s.patterns.Store(pattern, re)
+	return re, nil
+}
+
+func (s *Schema) validateArray(sch map[string]any, arr []any, pointer string, depth int, errs *[]ValidationError, ann *annotations) {
+	if min, ok := toNumber(sch["minItems"]); ok && float64(len(arr)) < min {
+		addError(errs, pointer, "minItems", "must have at least %s items", formatValue(sch["minItems"]))
+	}
+	if max, ok := toNumber(sch["maxItems"]); ok && float64(len(arr)) > max {
+		addError(errs, pointer, "maxItems", "must have at most %s items", formatValue(sch["maxItems"]))
+	}
+	if sch["uniqueItems"] == true {
+	unique:
+		for i := range arr {
+			for j := i + 1; j < len(arr); j++ {
+				if equal(arr[i], arr[j]) {
+					addError(errs, pointer, "uniqueItems", "items %d and %d are equal", i, j)
+					break unique
+				}
+			}
+		}
+	}
+
+	// Tuple validation: prefixItems (2020-12) or an items array (draft 7)
+	prefix, _ := sch["prefixItems"].([]any)
+	rest := sch["items"]
+	if tuple, ok := sch["items"].([]any); ok {
+		prefix = tuple
+		rest = sch["additionalItems"]
+	}
+	for i, itemSchema := range prefix {
+		if i >= len(arr) {
+			break
+		}
+		s.validate(itemSchema, arr[i], childPointer(pointer, i), depth+1, errs)
+	}
+	if len(prefix) > 0 {
+		ann.items = min(len(prefix), len(arr))
+	}
+	if rest != nil {
+		for i := len(prefix); i < len(arr); i++ {
+			s.validate(rest, arr[i], childPointer(pointer, i), depth+1, errs)
+		}
+		ann.allItems = true
+	}
+
+	if contains, ok := sch["contains"]; ok {
+		matches := 0
+		for i, item := range arr {
+			var sub []ValidationError
+			s.validate(contains, item, childPointer(pointer, i), depth+1, &sub)
+			if len(sub) == 0 {
+				matches++
+				ann.indexes[i] = true
+			}
+		}
+		minContains := 1.0
+		if m, ok := toNumber(sch["minContains"]); ok {
+			minContains = m
+		}
+		if float64(matches) < minContains {
+			addError(errs, pointer, "contains", "must contain at least %v matching items, found %d", minContains, matches)
+		}
+		if m, ok := toNumber(sch["maxContains"]); ok && float64(matches) > m {
+			addError(errs, pointer, "maxContains", "must contain at most %v matching items, found %d", m, matches)
+		}
+	}
+}
+
+func (s *Schema) validateObject(sch map[string]any, obj map[string]any, pointer string, depth int, errs *[]ValidationError, ann *annotations) {
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	if min, ok := toNumber(sch["minProperties"]); ok && float64(len(obj)) < min {
+		addError(errs, pointer, "minProperties", "must have at least %s properties", formatValue(sch["minProperties"]))
+	}
+	if max, ok := toNumber(sch["maxProperties"]); ok && float64(len(obj)) > max {
+		addError(errs, pointer, "maxProperties", "must have at most %s properties", formatValue(sch["maxProperties"]))
+	}
+
+	if required, ok := sch["required"].([]any); ok {
+		for _, r := range required {
+			if name, ok := r.(string); ok {
+				if _, present := obj[name]; !present {
+					addError(errs, pointer, "required", "missing required property %q", name)
+				}
+			}
+		}
+	}
+
+	properties, _ := sch["properties"].(map[string]any)
+	patternProps, _ := sch["patternProperties"].(map[string]any)
+	additional, hasAdditional := sch["additionalProperties"]
+
+	for _, key := range keys {
+		value := obj[key]
+		matched := false
+
+		if propSchema, ok := properties[key]; ok {
+			s.validate(propSchema, value, childPointer(pointer, key), depth+1, errs)
+			ann.props[key] = true
+			matched = true
+		}
+		for pattern, propSchema := range patternProps {
+			re, err := s.compilePattern(pattern)
+			if err != nil || !re.MatchString(key) {
+				continue
+			}
+			s.validate(propSchema, value, childPointer(pointer, key), depth+1, errs)
+			ann.props[key] = true
+			matched = true
+		}
+		if !matched && hasAdditional {
+			if additional == false {
+				addError(errs, childPointer(pointer, key), "additionalProperties", "property %q is not allowed", key)
+			} else {
+				s.validate(additional, value, childPointer(pointer, key), depth+1, errs)
+			}
+			ann.props[key] = true
+		}
+	}
+
+	if names, ok := sch["propertyNames"]; ok {
+		for _, key := range keys {
+			var sub []ValidationError
+			s.validate(names, key, "", depth+1, &sub)
+			for _, e := range sub {
+				addError(errs, childPointer(pointer, key), "propertyNames", "invalid property name: %s", e.Message)
+			}
+		}
+	}
+
+	dependentRequired, _ := sch["dependentRequired"].(map[string]any)
+	dependentSchemas, _ := sch["dependentSchemas"].(map[string]any)
+	if deps, ok := sch["dependencies"].(map[string]any); ok {
+		// Draft 7 combines both forms in one keyword
+		for name, dep := range deps {
+			if _, isList := dep.([]any); isList {
+				if dependentRequired == nil {
+					dependentRequired = make(map[string]any)
+				}
+				dependentRequired[name] = dep
+			} else {
+				if dependentSchemas == nil {
+					dependentSchemas = make(map[string]any)
+				}
+				dependentSchemas[name] = dep
+			}
+		}
+	}
+	for name, dep := range dependentRequired {
+		if _, present := obj[name]; !present {
+			continue
+		}
+		list, _ := dep.([]any)
+		for _, r := range list {
+			if req, ok := r.(string); ok {
+				if _, present := obj[req]; !present {
+					addError(errs, pointer, "dependentRequired", "missing property %q, required when %q is present", req, name)
+				}
+			}
+		}
+	}
+	for name, dep := range dependentSchemas {
+		if _, present := obj[name]; present {
+			ann.merge(s.validate(dep, obj, pointer, depth+1, errs))
+		}
+	}
+}
+
+func (s *Schema) validateCombinators(sch map[string]any, instance any, pointer string, depth int, errs *[]ValidationError, ann *annotations) {
+	if all, ok := sch["allOf"].([]any); ok {
+		for _, sub := range all {
+			ann.merge(s.validate(sub, instance, pointer, depth+1, errs))
+		}
+	}
+
+	if anyOf, ok := sch["anyOf"].([]any); ok {
+		matched := false
+		for _, sub := range anyOf {
+			var subErrs []ValidationError
+			subAnn := s.validate(sub, instance, pointer, depth+1, &subErrs)
+			if len(subErrs) == 0 {
+				matched = true
+				ann.merge(subAnn)
+			}
+		}
+		if !matched {
+			addError(errs, pointer, "anyOf", "does not match any of the anyOf schemas")
+		}
+	}
+
+	if oneOf, ok := sch["oneOf"].([]any); ok {
+		var matches []string
+		for i, sub := range oneOf {
+			var subErrs []ValidationError
+			subAnn := s.validate(sub, instance, pointer, depth+1, &subErrs)
+			if len(subErrs) == 0 {
+				matches = append(matches, strconv.Itoa(i))
+				ann.merge(subAnn)
+			}
+		}
+		switch {
+		case len(matches) == 0:
+			addError(errs, pointer, "oneOf", "does not match any of the oneOf schemas")
+		case len(matches) > 1:
+			addError(errs, pointer, "oneOf", "matches oneOf schemas %s, expected exactly one", strings.Join(matches, ", "))
+		}
+	}
+
+	if not, ok := sch["not"]; ok {
+		var subErrs []ValidationError
+		s.validate(not, instance, pointer, depth+1, &subErrs)
+		if len(subErrs) == 0 {
+			addError(errs, pointer, "not", "must not match the schema in not")
+		}
+	}
+
+	if cond, ok := sch["if"]; ok {
+		var condErrs []ValidationError
+		condAnn := s.validate(cond, instance, pointer, depth+1, &condErrs)
+		if len(condErrs) == 0 {
+			ann.merge(condAnn)
+			if then, ok := sch["then"]; ok {
+				ann.merge(s.validate(then, instance, pointer, depth+1, errs))
+			}
+		} else if els, ok := sch["else"]; ok {
+			ann.merge(s.validate(els, instance, pointer, depth+1, errs))
+		}
+	}
+}
+
+// validateUnevaluated applies unevaluatedProperties and unevaluatedItems to
+// whatever no other keyword of the schema evaluated.
+func (s *Schema) validateUnevaluated(sch map[string]any, instance any, pointer string, depth int, errs *[]ValidationError, ann *annotations) {
+	if unevaluated, ok := sch["unevaluatedProperties"]; ok {
+		if obj, ok := instance.(map[string]any); ok {
+			keys := make([]string, 0, len(obj))
+			for k := range obj {
+				if !ann.props[k] {
+					keys = append(keys, k)
+				}
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				if unevaluated == false {
+					addError(errs, childPointer(pointer, key), "unevaluatedProperties", "property %q is not allowed", key)
+				} else {
+					s.validate(unevaluated, obj[key], childPointer(pointer, key), depth+1, errs)
+				}
+				ann.props[key] = true
+			}
+		}
+	}
+
+	if unevaluated, ok := sch["unevaluatedItems"]; ok {
+		if arr, ok := instance.([]any); ok && !ann.allItems {
+			for i := ann.items; i < len(arr); i++ {
+				if ann.indexes[i] {
+					continue
+				}
+				if unevaluated == false {
+					addError(errs, childPointer(pointer, i), "unevaluatedItems", "item is not allowed")
+				} else {
+					s.validate(unevaluated, arr[i], childPointer(pointer, i), depth+1, errs)
+				}
+			}
+			ann.allItems = true
+		}
+	}
+}
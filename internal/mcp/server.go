@@ -18,7 +18,9 @@ import (
 	protohttp "github.com/artpar/currier/internal/protocol/http"
 	protows "github.com/artpar/currier/internal/protocol/websocket"
 	"github.com/artpar/currier/internal/runner"
+	"github.com/artpar/currier/internal/secrets"
 	"github.com/artpar/currier/internal/storage/filesystem"
+	"github.com/artpar/currier/internal/workspace"
 )
 
 // Server is the MCP server for Currier
@@ -57,6 +59,10 @@ type resourceDef struct {
 // ServerConfig holds configuration for the MCP server
 type ServerConfig struct {
 	DataDir string // Base directory for data (collections, environments, etc.)
+
+	// Workspace is a project workspace whose collections and environments
+	// are layered over those in DataDir. Optional.
+	Workspace *workspace.Workspace
 }
 
 // NewServer creates a new MCP server
@@ -72,17 +78,37 @@ func NewServer(config ServerConfig) (*Server, error) {
 	}
 
 	// Initialize stores
+	var collectionOpts []filesystem.CollectionOption
+	var envOpts []filesystem.EnvironmentOption
+	if ws := config.Workspace; ws != nil {
+		layout, err := filesystem.ParseLayout(ws.CollectionLayout())
+		if err != nil {
+			return nil, fmt.Errorf("invalid workspace settings: %w", err)
+		}
+		collectionOpts = append(collectionOpts, filesystem.WithCollectionWorkspace(ws.CollectionsDir()), filesystem.WithLayout(layout))
+		envOpts = append(envOpts, filesystem.WithEnvironmentWorkspace(ws.EnvironmentsDir(), ws.Settings.Environment))
+	}
+
 	collectionsPath := filepath.Join(dataDir, "collections")
-	collectionStore, err := filesystem.NewCollectionStore(collectionsPath)
+	collectionStore, err := filesystem.NewCollectionStore(collectionsPath, collectionOpts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create collection store: %w", err)
 	}
 
 	envsPath := filepath.Join(dataDir, "environments")
-	envStore, err := filesystem.NewEnvironmentStore(envsPath)
+	envStore, err := filesystem.NewEnvironmentStore(envsPath, envOpts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create environment store: %w", err)
 	}
+	key, err := secrets.KeyFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	if key != nil {
+		if err := envStore.Unlock(context.Background(), key); err != nil {
+			return nil, fmt.Errorf("failed to unlock secrets: %w", err)
+		}
+	}
 
 	historyPath := filepath.Join(dataDir, "history.db")
 	historyStore, err := historysqlite.New(historyPath)
@@ -235,6 +261,14 @@ func (s *Server) handleToolsCall(req *Request) *Response {
 		}
 	}
 
+	// Secret values never leave the server
+	if s.envStore != nil {
+		redactor := s.envStore.Redactor(context.Background())
+		for i := range result.Content {
+			result.Content[i].Text = redactor.Redact(result.Content[i].Text)
+		}
+	}
+
 	data, _ := json.Marshal(result)
 	return &Response{Result: data}
 }
@@ -308,7 +342,25 @@ func (s *Server) getEnvironment(name string) (map[string]string, error) {
 		return nil, fmt.Errorf("failed to get environment %s: %w", name, err)
 	}
 
-	return env.Variables(), nil
+	return env.ExportVariablesOnly(), nil
+}
+
+// globalVariables returns the variables of the global environments.
+func (s *Server) globalVariables() map[string]string {
+	if s.envStore == nil {
+		return nil
+	}
+	globals, err := s.envStore.Globals(context.Background())
+	if err != nil {
+		return nil
+	}
+	vars := make(map[string]string)
+	for _, env := range globals {
+		for k, v := range env.ExportVariablesOnly() {
+			vars[k] = v
+		}
+	}
+	return vars
 }
 
 // Helper to create and send an HTTP request
@@ -320,11 +372,11 @@ func (s *Server) sendRequest(ctx context.Context, method, url string, headers ma
 	}
 
 	// Interpolate variables in URL
-	if envVars != nil {
+	globals := s.globalVariables()
+	if envVars != nil || len(globals) > 0 {
 		engine := interpolate.NewEngine()
-		for k, v := range envVars {
-			engine.SetVariable(k, v)
-		}
+		engine.Scope().SetGlobal(interpolate.NewVariableSetFrom(globals))
+		engine.Scope().SetEnvironment(interpolate.NewVariableSetFrom(envVars))
 		url, _ = engine.Interpolate(url)
 		for k, v := range headers {
 			headers[k], _ = engine.Interpolate(v)
@@ -353,7 +405,7 @@ func (s *Server) sendRequest(ctx context.Context, method, url string, headers ma
 }
 
 // Helper to run a collection
-func (s *Server) runCollection(ctx context.Context, collectionName, envName string) (*runner.RunSummary, error) {
+func (s *Server) runCollection(ctx context.Context, collectionName, envName string, concurrency int) (*runner.RunSummary, error) {
 	// Find collection
 	collections, err := s.collections.List(ctx)
 	if err != nil {
@@ -378,6 +430,8 @@ func (s *Server) runCollection(ctx context.Context, collectionName, envName stri
 	// Build runner options
 	opts := []runner.Option{
 		runner.WithCookieJar(s.cookieJar),
+		runner.WithConcurrency(concurrency),
+		runner.WithGlobals(s.globalVariables()),
 	}
 
 	// Get environment
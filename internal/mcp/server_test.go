@@ -9,6 +9,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/artpar/currier/internal/core"
+	"github.com/artpar/currier/internal/workspace"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -57,6 +59,18 @@ func TestNewServer(t *testing.T) {
 		}
 		// It's OK if this fails on CI without home dir
 	})
+
+	t.Run("layers a workspace over the data directory", func(t *testing.T) {
+		ws, err := workspace.Init(t.TempDir())
+		require.NoError(t, err)
+		server, err := NewServer(ServerConfig{DataDir: t.TempDir(), Workspace: ws})
+		require.NoError(t, err)
+		defer server.Close()
+
+		c := core.NewCollection("Shared")
+		require.NoError(t, server.collections.Save(context.Background(), c))
+		assert.DirExists(t, filepath.Join(ws.CollectionsDir(), "shared"))
+	})
 }
 
 func TestServer_handleInitialize(t *testing.T) {
@@ -277,6 +291,30 @@ func TestServer_handleToolsCall(t *testing.T) {
 	})
 }
 
+func TestServer_handleToolsCall_RedactsSecrets(t *testing.T) {
+	server, cleanup := createTestServer(t)
+	defer cleanup()
+
+	env := core.NewEnvironment("Production")
+	env.SetSecret("token", "s3cr3t-token")
+	require.NoError(t, server.envStore.Save(context.Background(), env))
+
+	server.tools["echo"] = &toolDef{
+		tool: Tool{Name: "echo"},
+		handler: func(json.RawMessage) (*ToolCallResult, error) {
+			return &ToolCallResult{Content: []ContentBlock{TextContent("Authorization: Bearer s3cr3t-token")}}, nil
+		},
+	}
+
+	paramsJSON, _ := json.Marshal(ToolCallParams{Name: "echo", Arguments: json.RawMessage(`{}`)})
+	resp := server.handleToolsCall(&Request{JSONRPC: "2.0", ID: json.RawMessage(`1`), Method: MethodToolsCall, Params: paramsJSON})
+	require.NotNil(t, resp)
+
+	var result ToolCallResult
+	require.NoError(t, json.Unmarshal(resp.Result, &result))
+	assert.Equal(t, "Authorization: Bearer ********", result.Content[0].Text)
+}
+
 func TestServer_handleResourcesList(t *testing.T) {
 	server, cleanup := createTestServer(t)
 	defer cleanup()
@@ -1045,7 +1083,7 @@ func TestServer_RunCollection(t *testing.T) {
 
 	t.Run("returns error for non-existent collection", func(t *testing.T) {
 		ctx := context.Background()
-		_, err := server.runCollection(ctx, "nonexistent", "")
+		_, err := server.runCollection(ctx, "nonexistent", "", 1)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "collection not found")
 	})
@@ -1067,7 +1105,7 @@ func TestServer_RunCollection(t *testing.T) {
 		server.handleToolsCall(req)
 
 		// Try to run with non-existent environment
-		_, err := server.runCollection(ctx, "test-collection", "nonexistent-env")
+		_, err := server.runCollection(ctx, "test-collection", "nonexistent-env", 1)
 		assert.Error(t, err)
 	})
 }
@@ -1512,6 +1550,28 @@ func TestServer_RunCollectionWithRequests(t *testing.T) {
 		require.NotNil(t, resp)
 		// Will have errors due to unreachable hosts, but code path is exercised
 	})
+
+	t.Run("run_collection accepts concurrency", func(t *testing.T) {
+		params := ToolCallParams{
+			Name:      "run_collection",
+			Arguments: json.RawMessage(`{"name": "RunnerTestColl", "concurrency": 4}`),
+		}
+		paramsJSON, _ := json.Marshal(params)
+
+		resp := server.handleToolsCall(&Request{
+			JSONRPC: "2.0",
+			ID:      json.RawMessage(`4`),
+			Method:  MethodToolsCall,
+			Params:  paramsJSON,
+		})
+		require.NotNil(t, resp)
+		assert.Nil(t, resp.Error)
+
+		var result ToolCallResult
+		require.NoError(t, json.Unmarshal(resp.Result, &result))
+		require.NotEmpty(t, result.Content)
+		assert.Contains(t, result.Content[0].Text, `"total_requests": 1`)
+	})
 }
 
 func TestServer_InvalidToolArguments(t *testing.T) {
@@ -778,6 +778,7 @@ func (s *Server) registerSaveRequest() {
 type runCollectionArgs struct {
 	Name        string `json:"name"`
 	Environment string `json:"environment,omitempty"`
+	Concurrency int    `json:"concurrency,omitempty"`
 }
 
 func (s *Server) registerRunCollection() {
@@ -791,6 +792,11 @@ func (s *Server) registerRunCollection() {
 			"environment": {
 				"type": "string",
 				"description": "Environment to use"
+			},
+			"concurrency": {
+				"type": "integer",
+				"description": "Number of requests to run in parallel (default 1). Folders marked sequential always run in order",
+				"minimum": 1
 			}
 		},
 		"required": ["name"]
@@ -811,7 +817,7 @@ func (s *Server) registerRunCollection() {
 			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
 			defer cancel()
 
-			summary, err := s.runCollection(ctx, params.Name, params.Environment)
+			summary, err := s.runCollection(ctx, params.Name, params.Environment, params.Concurrency)
 			if err != nil {
 				return nil, err
 			}
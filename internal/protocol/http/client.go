@@ -68,7 +68,11 @@ func (c *Client) configureTransport() {
 		return
 	}
 
+	// Build on a transport set with WithTransport, so its pooling survives
 	transport := &http.Transport{}
+	if t, ok := c.httpClient.Transport.(*http.Transport); ok {
+		transport = t.Clone()
+	}
 
 	// Configure proxy
 	if c.config.ProxyURL != "" {
@@ -242,6 +246,12 @@ func (c *Client) toHTTPRequest(ctx context.Context, req *core.Request) (*http.Re
 		return nil, err
 	}
 
+	// Streaming bodies (e.g. files) are not sized by net/http; send a
+	// Content-Length instead of falling back to chunked encoding.
+	if bodyReader != nil && httpReq.ContentLength == 0 {
+		httpReq.ContentLength = req.Body().Size()
+	}
+
 	// Copy headers
 	for _, key := range req.Headers().Keys() {
 		for _, value := range req.Headers().GetAll(key) {
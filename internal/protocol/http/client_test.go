@@ -1,12 +1,15 @@
 package http
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"io"
 	"net/http"
 	"net/http/cookiejar"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -34,6 +37,16 @@ func TestNewClient(t *testing.T) {
 		client := NewClient(WithTransport(transport))
 		assert.NotNil(t, client)
 	})
+
+	t.Run("keeps a custom transport with proxy and TLS options", func(t *testing.T) {
+		transport := &http.Transport{MaxIdleConnsPerHost: 50}
+		client := NewClient(WithTransport(transport), WithProxy("http://proxy:8080"), WithInsecureSkipVerify())
+
+		got := client.httpClient.Transport.(*http.Transport)
+		assert.Equal(t, 50, got.MaxIdleConnsPerHost)
+		assert.NotNil(t, got.Proxy)
+		assert.True(t, got.TLSClientConfig.InsecureSkipVerify)
+	})
 }
 
 func TestClient_Protocol(t *testing.T) {
@@ -153,6 +166,32 @@ func TestClient_Send_POST(t *testing.T) {
 		require.NoError(t, err)
 		assert.Equal(t, 200, resp.Status().Code())
 	})
+
+	t.Run("streams binary file body with content length", func(t *testing.T) {
+		payload := bytes.Repeat([]byte{0xde, 0xad, 0xbe, 0xef}, 1024)
+		path := filepath.Join(t.TempDir(), "payload.bin")
+		require.NoError(t, os.WriteFile(path, payload, 0644))
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			assert.Equal(t, int64(len(payload)), r.ContentLength)
+			assert.Equal(t, payload, body)
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		body, err := core.NewFileBody(path, "")
+		require.NoError(t, err)
+
+		client := NewClient()
+		req, _ := core.NewRequest("http", "POST", server.URL+"/upload")
+		req.SetBody(body)
+
+		resp, err := client.Send(context.Background(), req)
+
+		require.NoError(t, err)
+		assert.Equal(t, 200, resp.Status().Code())
+	})
 }
 
 func TestClient_Send_PUT(t *testing.T) {
@@ -0,0 +1,222 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/artpar/currier/internal/core"
+)
+
+// DefaultPreviewLimit is the default number of body bytes kept in memory
+// when a response is streamed to disk.
+const DefaultPreviewLimit int64 = 1 << 20 // 1MB
+
+// partialSuffix is appended to the destination path while a download is in flight.
+const partialSuffix = ".part"
+
+// ErrDownloadIncomplete is returned when a download stops before the body is fully written.
+var ErrDownloadIncomplete = errors.New("download incomplete")
+
+// DownloadProgress describes the state of an in-flight download.
+type DownloadProgress struct {
+	Written int64         // Bytes written to disk so far
+	Total   int64         // Expected size from Content-Length, or -1 if unknown
+	Elapsed time.Duration // Time since the first body byte was requested
+	Done    bool          // True on the final progress report
+}
+
+// Percent returns the completion percentage, or -1 if the total is unknown.
+func (p DownloadProgress) Percent() float64 {
+	if p.Total <= 0 {
+		return -1
+	}
+	return float64(p.Written) / float64(p.Total) * 100
+}
+
+// BytesPerSecond returns the average throughput so far.
+func (p DownloadProgress) BytesPerSecond() float64 {
+	if p.Elapsed <= 0 {
+		return 0
+	}
+	return float64(p.Written) / p.Elapsed.Seconds()
+}
+
+// ProgressFunc receives download progress updates.
+type ProgressFunc func(DownloadProgress)
+
+// DownloadOptions configures a streaming download.
+type DownloadOptions struct {
+	Path             string        // Destination file path
+	PreviewLimit     int64         // Max bytes kept in memory for the response body (0 = DefaultPreviewLimit)
+	OnProgress       ProgressFunc  // Optional progress callback
+	ProgressInterval time.Duration // Minimum time between progress callbacks (0 = 100ms)
+}
+
+// Download executes an HTTP request and streams the response body to disk.
+// The body is written to Path+".part" and renamed to Path on success. If the
+// transfer fails or ctx is cancelled, the partial file is flushed and left in
+// place and an error wrapping ErrDownloadIncomplete is returned along with a
+// response describing what was received.
+//
+// The returned response body holds at most PreviewLimit bytes; the download
+// path, written size and truncation flag are recorded in the response metadata.
+func (c *Client) Download(ctx context.Context, req *core.Request, opts DownloadOptions) (*core.Response, error) {
+	if opts.Path == "" {
+		return nil, errors.New("download path cannot be empty")
+	}
+	previewLimit := opts.PreviewLimit
+	if previewLimit <= 0 {
+		previewLimit = DefaultPreviewLimit
+	}
+	interval := opts.ProgressInterval
+	if interval <= 0 {
+		interval = 100 * time.Millisecond
+	}
+
+	startTime := time.Now()
+
+	httpReq, err := c.toHTTPRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// The client-wide timeout covers reading the whole body, which is not
+	// meaningful for large downloads. Rely on ctx for cancellation instead.
+	streamClient := *c.httpClient
+	streamClient.Timeout = 0
+
+	httpResp, err := streamClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResp.Body.Close()
+
+	if dir := filepath.Dir(opts.Path); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create download directory: %w", err)
+		}
+	}
+
+	partPath := opts.Path + partialSuffix
+	file, err := os.Create(partPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create download file: %w", err)
+	}
+
+	preview := &limitedBuffer{limit: previewLimit}
+	counter := &progressWriter{
+		total:    httpResp.ContentLength,
+		start:    time.Now(),
+		interval: interval,
+		report:   opts.OnProgress,
+	}
+
+	_, copyErr := io.Copy(io.MultiWriter(file, preview, counter), httpResp.Body)
+	if copyErr == nil {
+		copyErr = ctx.Err()
+	}
+
+	// Always flush what we have so a partial file is left in a consistent state.
+	syncErr := file.Sync()
+	closeErr := file.Close()
+
+	endTime := time.Now()
+	counter.finish()
+
+	resp := c.fromHTTPResponse(req, httpResp, preview.Bytes(), startTime, endTime).
+		WithMetadata(core.MetaDownloadSize, counter.written).
+		WithMetadata(core.MetaBodyTruncated, preview.truncated)
+
+	if copyErr != nil {
+		resp.WithMetadata(core.MetaDownloadPath, partPath)
+		return resp, fmt.Errorf("%w: %d bytes written to %s: %v", ErrDownloadIncomplete, counter.written, partPath, copyErr)
+	}
+	if syncErr != nil {
+		return resp, fmt.Errorf("failed to flush download file: %w", syncErr)
+	}
+	if closeErr != nil {
+		return resp, fmt.Errorf("failed to close download file: %w", closeErr)
+	}
+
+	if err := os.Rename(partPath, opts.Path); err != nil {
+		resp.WithMetadata(core.MetaDownloadPath, partPath)
+		return resp, fmt.Errorf("failed to finalize download: %w", err)
+	}
+	resp.WithMetadata(core.MetaDownloadPath, opts.Path)
+
+	return resp, nil
+}
+
+// limitedBuffer keeps the first limit bytes written to it and discards the rest.
+type limitedBuffer struct {
+	buf       []byte
+	limit     int64
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	remaining := b.limit - int64(len(b.buf))
+	if remaining <= 0 {
+		if len(p) > 0 {
+			b.truncated = true
+		}
+		return len(p), nil
+	}
+	if int64(len(p)) > remaining {
+		b.buf = append(b.buf, p[:remaining]...)
+		b.truncated = true
+		return len(p), nil
+	}
+	b.buf = append(b.buf, p...)
+	return len(p), nil
+}
+
+func (b *limitedBuffer) Bytes() []byte {
+	return b.buf
+}
+
+// progressWriter counts bytes and reports progress at most once per interval.
+type progressWriter struct {
+	written    int64
+	total      int64
+	start      time.Time
+	lastReport time.Time
+	interval   time.Duration
+	report     ProgressFunc
+}
+
+func (w *progressWriter) Write(p []byte) (int, error) {
+	w.written += int64(len(p))
+	if w.report != nil {
+		now := time.Now()
+		if now.Sub(w.lastReport) >= w.interval {
+			w.lastReport = now
+			w.report(w.progress(false))
+		}
+	}
+	return len(p), nil
+}
+
+func (w *progressWriter) finish() {
+	if w.report != nil {
+		w.report(w.progress(true))
+	}
+}
+
+func (w *progressWriter) progress(done bool) DownloadProgress {
+	total := w.total
+	if total < 0 {
+		total = -1
+	}
+	return DownloadProgress{
+		Written: w.written,
+		Total:   total,
+		Elapsed: time.Since(w.start),
+		Done:    done,
+	}
+}
@@ -0,0 +1,145 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/artpar/currier/internal/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Download(t *testing.T) {
+	t.Run("streams body to file and keeps a capped preview", func(t *testing.T) {
+		payload := bytes.Repeat([]byte("0123456789"), 10000) // 100KB
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/octet-stream")
+			w.Header().Set("Content-Length", strconv.Itoa(len(payload)))
+			w.Write(payload)
+		}))
+		defer server.Close()
+
+		dest := filepath.Join(t.TempDir(), "out.bin")
+		var reports []DownloadProgress
+
+		client := NewClient()
+		req, _ := core.NewRequest("http", "GET", server.URL)
+		resp, err := client.Download(context.Background(), req, DownloadOptions{
+			Path:         dest,
+			PreviewLimit: 1024,
+			OnProgress:   func(p DownloadProgress) { reports = append(reports, p) },
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 200, resp.Status().Code())
+		assert.Equal(t, dest, resp.DownloadPath())
+		assert.Equal(t, int64(len(payload)), resp.DownloadSize())
+		assert.True(t, resp.IsBodyTruncated())
+		assert.Equal(t, int64(1024), resp.Body().Size())
+		assert.Equal(t, payload[:1024], resp.Body().Bytes())
+
+		written, err := os.ReadFile(dest)
+		require.NoError(t, err)
+		assert.Equal(t, payload, written)
+
+		_, err = os.Stat(dest + partialSuffix)
+		assert.True(t, os.IsNotExist(err), "partial file should be renamed")
+
+		require.NotEmpty(t, reports)
+		last := reports[len(reports)-1]
+		assert.True(t, last.Done)
+		assert.Equal(t, int64(len(payload)), last.Written)
+		assert.Equal(t, int64(len(payload)), last.Total)
+		assert.InDelta(t, 100, last.Percent(), 0.001)
+	})
+
+	t.Run("small body is not truncated", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"ok":true}`))
+		}))
+		defer server.Close()
+
+		dest := filepath.Join(t.TempDir(), "nested", "small.json")
+		client := NewClient()
+		req, _ := core.NewRequest("http", "GET", server.URL)
+		resp, err := client.Download(context.Background(), req, DownloadOptions{Path: dest})
+
+		require.NoError(t, err)
+		assert.False(t, resp.IsBodyTruncated())
+		assert.Equal(t, `{"ok":true}`, resp.Body().String())
+		assert.FileExists(t, dest)
+	})
+
+	t.Run("cancellation leaves a flushed partial file", func(t *testing.T) {
+		chunk := bytes.Repeat([]byte("x"), 4096)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Length", strconv.Itoa(len(chunk)*1000))
+			flusher := w.(http.Flusher)
+			for i := 0; i < 1000; i++ {
+				if _, err := w.Write(chunk); err != nil {
+					return
+				}
+				flusher.Flush()
+				time.Sleep(5 * time.Millisecond)
+			}
+		}))
+		defer server.Close()
+
+		dest := filepath.Join(t.TempDir(), "cancel.bin")
+		ctx, cancel := context.WithCancel(context.Background())
+
+		client := NewClient()
+		req, _ := core.NewRequest("http", "GET", server.URL)
+		resp, err := client.Download(ctx, req, DownloadOptions{
+			Path: dest,
+			OnProgress: func(p DownloadProgress) {
+				if p.Written >= int64(len(chunk)*3) {
+					cancel()
+				}
+			},
+			ProgressInterval: time.Millisecond,
+		})
+
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, ErrDownloadIncomplete))
+		require.NotNil(t, resp)
+		assert.Equal(t, dest+partialSuffix, resp.DownloadPath())
+
+		info, statErr := os.Stat(dest + partialSuffix)
+		require.NoError(t, statErr)
+		assert.Equal(t, resp.DownloadSize(), info.Size())
+		assert.Less(t, info.Size(), int64(len(chunk)*1000))
+
+		_, statErr = os.Stat(dest)
+		assert.True(t, os.IsNotExist(statErr), "final file should not exist")
+	})
+
+	t.Run("requires a path", func(t *testing.T) {
+		client := NewClient()
+		req, _ := core.NewRequest("http", "GET", "http://localhost")
+		_, err := client.Download(context.Background(), req, DownloadOptions{})
+		assert.Error(t, err)
+	})
+}
+
+func TestDownloadProgress(t *testing.T) {
+	t.Run("unknown total", func(t *testing.T) {
+		p := DownloadProgress{Written: 10, Total: -1, Elapsed: time.Second}
+		assert.Equal(t, float64(-1), p.Percent())
+		assert.Equal(t, float64(10), p.BytesPerSecond())
+	})
+
+	t.Run("zero elapsed", func(t *testing.T) {
+		p := DownloadProgress{Written: 10, Total: 20}
+		assert.Equal(t, float64(50), p.Percent())
+		assert.Equal(t, float64(0), p.BytesPerSecond())
+	})
+}
@@ -0,0 +1,219 @@
+package reporter
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"time"
+
+	"github.com/artpar/currier/internal/runner"
+)
+
+// HTMLReporter writes a single self-contained HTML page with run totals,
+// per-request timing, test failures and request/response details.
+type HTMLReporter struct{}
+
+// NewHTMLReporter creates a new HTML reporter.
+func NewHTMLReporter() *HTMLReporter {
+	return &HTMLReporter{}
+}
+
+func (h *HTMLReporter) Name() string          { return "html" }
+func (h *HTMLReporter) FileExtension() string { return ".html" }
+
+type htmlReport struct {
+	Summary    *runner.RunSummary
+	Started    string
+	Duration   string
+	Iterations []htmlIteration
+	Multi      bool
+}
+
+type htmlIteration struct {
+	Index    int
+	Data     []htmlPair
+	Summary  runner.IterationSummary
+	Duration string
+	Results  []htmlResult
+}
+
+type htmlResult struct {
+	runner.RunResult
+	Name            string
+	Class           string // "passed", "failed" or "skipped"
+	Error           string
+	Duration        string
+	BarWidth        int // Percentage of the slowest request
+	FailedTests     int
+	RequestHeaders  []htmlPair
+	ResponseHeaders []htmlPair
+	Truncated       bool
+}
+
+type htmlPair struct {
+	Key   string
+	Value string
+}
+
+// Report writes the summary as HTML.
+func (h *HTMLReporter) Report(w io.Writer, summary *runner.RunSummary) error {
+	iters := iterations(summary)
+
+	var slowest time.Duration
+	for _, r := range summary.Results {
+		if r.Duration > slowest {
+			slowest = r.Duration
+		}
+	}
+
+	report := htmlReport{
+		Summary:  summary,
+		Duration: htmlDuration(summary.TotalDuration),
+		Multi:    len(iters) > 1,
+	}
+	if !summary.StartTime.IsZero() {
+		report.Started = summary.StartTime.Format(time.RFC1123)
+	}
+
+	for _, it := range iters {
+		iter := htmlIteration{
+			Index:    it.Index + 1,
+			Data:     htmlPairs(it.Data),
+			Summary:  it,
+			Duration: htmlDuration(it.Duration),
+		}
+		for _, r := range it.Results {
+			iter.Results = append(iter.Results, newHTMLResult(r, slowest))
+		}
+		report.Iterations = append(report.Iterations, iter)
+	}
+
+	return htmlTemplate.Execute(w, report)
+}
+
+func newHTMLResult(r runner.RunResult, slowest time.Duration) htmlResult {
+	result := htmlResult{
+		RunResult:       r,
+		Name:            resultName(r),
+		Class:           "passed",
+		Duration:        htmlDuration(r.Duration),
+		FailedTests:     failedTests(r),
+		RequestHeaders:  htmlPairs(r.RequestHeaders),
+		ResponseHeaders: htmlPairs(r.ResponseHeaders),
+		Truncated:       r.ResponseSize > int64(len(r.ResponseBody)) && len(r.ResponseBody) >= runner.MaxRecordedBodySize,
+	}
+	if r.Error != nil {
+		result.Error = r.Error.Error()
+	}
+	switch {
+	case r.Skipped:
+		result.Class = "skipped"
+	case r.Error != nil || result.FailedTests > 0:
+		result.Class = "failed"
+	}
+	if slowest > 0 {
+		result.BarWidth = int(100 * r.Duration / slowest)
+	}
+	return result
+}
+
+func htmlPairs(m map[string]string) []htmlPair {
+	pairs := make([]htmlPair, 0, len(m))
+	for _, k := range sortedKeys(m) {
+		pairs = append(pairs, htmlPair{Key: k, Value: m[k]})
+	}
+	return pairs
+}
+
+func htmlDuration(d time.Duration) string {
+	if d < time.Second {
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	}
+	return fmt.Sprintf("%.2fs", d.Seconds())
+}
+
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Summary.CollectionName}} - Currier run report</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; margin: 0; padding: 24px; background: #f6f8fa; color: #24292f; }
+h1 { margin: 0 0 4px; font-size: 24px; }
+h2 { font-size: 18px; margin: 28px 0 8px; }
+.meta { color: #57606a; font-size: 13px; }
+.cards { display: flex; flex-wrap: wrap; gap: 12px; margin: 20px 0; }
+.card { background: #fff; border: 1px solid #d0d7de; border-radius: 6px; padding: 12px 16px; min-width: 120px; }
+.card .value { font-size: 22px; font-weight: 600; }
+.card .label { font-size: 12px; color: #57606a; text-transform: uppercase; }
+.passed .value, .status.passed { color: #1a7f37; }
+.failed .value, .status.failed { color: #cf222e; }
+.skipped .value, .status.skipped { color: #9a6700; }
+details.request { background: #fff; border: 1px solid #d0d7de; border-left: 4px solid #1a7f37; border-radius: 6px; margin: 8px 0; }
+details.request.failed { border-left-color: #cf222e; }
+details.request.skipped { border-left-color: #9a6700; }
+summary { cursor: pointer; padding: 10px 12px; display: flex; align-items: center; gap: 12px; }
+summary .name { flex: 1; font-weight: 600; }
+.method { font-family: monospace; font-weight: 700; }
+.bar { width: 120px; height: 6px; background: #eaeef2; border-radius: 3px; overflow: hidden; }
+.bar span { display: block; height: 100%; background: #0969da; }
+.body { padding: 0 16px 12px; }
+.error { color: #cf222e; white-space: pre-wrap; }
+ul.tests { list-style: none; padding: 0; margin: 8px 0; }
+ul.tests li { padding: 2px 0; }
+ul.tests .message { color: #cf222e; margin-left: 20px; font-size: 13px; }
+table { border-collapse: collapse; font-size: 13px; margin: 4px 0 8px; }
+td { border-top: 1px solid #eaeef2; padding: 2px 12px 2px 0; vertical-align: top; font-family: monospace; word-break: break-all; }
+pre { background: #f6f8fa; border: 1px solid #eaeef2; border-radius: 4px; padding: 8px; overflow: auto; max-height: 320px; font-size: 12px; }
+h4 { margin: 12px 0 4px; font-size: 13px; }
+</style>
+</head>
+<body>
+<h1>{{.Summary.CollectionName}}</h1>
+<div class="meta">{{if .Started}}Started {{.Started}} &middot; {{end}}Duration {{.Duration}}{{if .Summary.Stopped}} &middot; Run stopped by script{{end}}</div>
+<div class="cards">
+  <div class="card"><div class="value">{{.Summary.TotalRequests}}</div><div class="label">Requests</div></div>
+  <div class="card passed"><div class="value">{{.Summary.Passed}}</div><div class="label">Passed</div></div>
+  <div class="card failed"><div class="value">{{.Summary.Failed}}</div><div class="label">Failed</div></div>
+  <div class="card skipped"><div class="value">{{.Summary.Skipped}}</div><div class="label">Skipped</div></div>
+  <div class="card"><div class="value">{{.Summary.TestsPassed}}/{{.Summary.TotalTests}}</div><div class="label">Tests passed</div></div>
+</div>
+{{range .Iterations}}
+{{if $.Multi}}<h2>Iteration {{.Index}} <span class="meta">{{.Summary.Passed}}/{{.Summary.Executed}} requests, {{.Summary.TestsPassed}}/{{.Summary.TotalTests}} tests, {{.Duration}}{{range .Data}} &middot; {{.Key}}={{.Value}}{{end}}</span></h2>{{end}}
+{{range .Results}}
+<details class="request {{.Class}}"{{if eq .Class "failed"}} open{{end}}>
+  <summary>
+    <span class="status {{.Class}}">{{if eq .Class "passed"}}&#10003;{{else if eq .Class "failed"}}&#10007;{{else}}&#8631;{{end}}</span>
+    <span class="name"><span class="method">{{.Method}}</span> {{.RequestName}}</span>
+    {{if .Skipped}}<span class="meta">skipped</span>{{else}}<span class="meta">{{if .Status}}{{.Status}} {{.StatusText}} &middot; {{end}}{{.Duration}}</span>
+    <span class="bar"><span style="width: {{.BarWidth}}%"></span></span>{{end}}
+  </summary>
+  <div class="body">
+    {{if .URL}}<div class="meta">{{.URL}}</div>{{end}}
+    {{if .Skipped}}<p class="meta">{{.SkipReason}}</p>{{end}}
+    {{if .Error}}<p class="error">{{.Error}}</p>{{end}}
+    {{if .TestResults}}
+    <h4>Tests ({{.FailedTests}} failed)</h4>
+    <ul class="tests">
+      {{range .TestResults}}<li>{{if .Passed}}<span class="status passed">&#10003;</span>{{else}}<span class="status failed">&#10007;</span>{{end}} {{.Name}}{{if .Error}}<div class="message">{{.Error}}</div>{{end}}</li>
+      {{end}}
+    </ul>
+    {{end}}
+    {{if or .RequestHeaders .RequestBody}}
+    <h4>Request</h4>
+    {{if .RequestHeaders}}<table>{{range .RequestHeaders}}<tr><td>{{.Key}}</td><td>{{.Value}}</td></tr>{{end}}</table>{{end}}
+    {{if .RequestBody}}<pre>{{.RequestBody}}</pre>{{end}}
+    {{end}}
+    {{if or .ResponseHeaders .ResponseBody}}
+    <h4>Response{{if .ResponseSize}} ({{.ResponseSize}} bytes{{if .Truncated}}, truncated{{end}}){{end}}</h4>
+    {{if .ResponseHeaders}}<table>{{range .ResponseHeaders}}<tr><td>{{.Key}}</td><td>{{.Value}}</td></tr>{{end}}</table>{{end}}
+    {{if .ResponseBody}}<pre>{{.ResponseBody}}</pre>{{end}}
+    {{end}}
+  </div>
+</details>
+{{end}}
+{{end}}
+</body>
+</html>
+`))
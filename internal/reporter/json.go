@@ -0,0 +1,169 @@
+package reporter
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/artpar/currier/internal/runner"
+)
+
+// JSONReporter writes the run summary as indented JSON.
+type JSONReporter struct{}
+
+// NewJSONReporter creates a new JSON reporter.
+func NewJSONReporter() *JSONReporter {
+	return &JSONReporter{}
+}
+
+func (j *JSONReporter) Name() string          { return "json" }
+func (j *JSONReporter) FileExtension() string { return ".json" }
+
+type jsonReport struct {
+	Collection    string          `json:"collection"`
+	StartTime     time.Time       `json:"start_time"`
+	TotalRequests int             `json:"total_requests"`
+	Executed      int             `json:"executed"`
+	Passed        int             `json:"passed"`
+	Failed        int             `json:"failed"`
+	Skipped       int             `json:"skipped"`
+	Stopped       bool            `json:"stopped"`
+	TotalTests    int             `json:"total_tests"`
+	TestsPassed   int             `json:"tests_passed"`
+	TestsFailed   int             `json:"tests_failed"`
+	TotalDuration int64           `json:"total_duration"`
+	Snapshots     *jsonSnapshots  `json:"snapshots,omitempty"`
+	Results       []jsonResult    `json:"results"`
+	Iterations    []jsonIteration `json:"iterations"`
+}
+
+type jsonSnapshots struct {
+	Matched    int `json:"matched"`
+	Mismatched int `json:"mismatched"`
+	Recorded   int `json:"recorded"`
+	Updated    int `json:"updated"`
+}
+
+type jsonIteration struct {
+	Iteration   int               `json:"iteration"`
+	Data        map[string]string `json:"data,omitempty"`
+	Executed    int               `json:"executed"`
+	Passed      int               `json:"passed"`
+	Failed      int               `json:"failed"`
+	Skipped     int               `json:"skipped"`
+	TotalTests  int               `json:"total_tests"`
+	TestsPassed int               `json:"tests_passed"`
+	TestsFailed int               `json:"tests_failed"`
+	DurationMS  int64             `json:"duration_ms"`
+	Results     []jsonResult      `json:"results"`
+}
+
+type jsonResult struct {
+	Iteration  int          `json:"iteration"`
+	Name       string       `json:"name"`
+	Method     string       `json:"method"`
+	URL        string       `json:"url"`
+	Status     int          `json:"status"`
+	StatusText string       `json:"status_text"`
+	DurationMS int64        `json:"duration_ms"`
+	Error      string       `json:"error,omitempty"`
+	Skipped    bool         `json:"skipped,omitempty"`
+	SkipReason string       `json:"skip_reason,omitempty"`
+	Snapshot   string       `json:"snapshot,omitempty"`
+	Tests      []jsonTest   `json:"tests,omitempty"`
+	Request    *jsonMessage `json:"request,omitempty"`
+	Response   *jsonMessage `json:"response,omitempty"`
+}
+
+type jsonTest struct {
+	Name   string `json:"name"`
+	Passed bool   `json:"passed"`
+	Error  string `json:"error,omitempty"`
+}
+
+type jsonMessage struct {
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    string            `json:"body,omitempty"`
+	Size    int64             `json:"size,omitempty"`
+}
+
+// Report writes the summary as JSON.
+func (j *JSONReporter) Report(w io.Writer, summary *runner.RunSummary) error {
+	report := jsonReport{
+		Collection:    summary.CollectionName,
+		StartTime:     summary.StartTime,
+		TotalRequests: summary.TotalRequests,
+		Executed:      summary.Executed,
+		Passed:        summary.Passed,
+		Failed:        summary.Failed,
+		Skipped:       summary.Skipped,
+		Stopped:       summary.Stopped,
+		TotalTests:    summary.TotalTests,
+		TestsPassed:   summary.TestsPassed,
+		TestsFailed:   summary.TestsFailed,
+		TotalDuration: summary.TotalDuration.Milliseconds(),
+		Results:       jsonResults(summary.Results),
+		Iterations:    make([]jsonIteration, 0, len(summary.Iterations)),
+	}
+
+	if snaps := summary.Snapshots; snaps.Total() > 0 {
+		report.Snapshots = &jsonSnapshots{
+			Matched:    snaps.Matched,
+			Mismatched: snaps.Mismatched,
+			Recorded:   snaps.Recorded,
+			Updated:    snaps.Updated,
+		}
+	}
+
+	for _, it := range summary.Iterations {
+		report.Iterations = append(report.Iterations, jsonIteration{
+			Iteration:   it.Index + 1,
+			Data:        it.Data,
+			Executed:    it.Executed,
+			Passed:      it.Passed,
+			Failed:      it.Failed,
+			Skipped:     it.Skipped,
+			TotalTests:  it.TotalTests,
+			TestsPassed: it.TestsPassed,
+			TestsFailed: it.TestsFailed,
+			DurationMS:  it.Duration.Milliseconds(),
+			Results:     jsonResults(it.Results),
+		})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
+
+func jsonResults(results []runner.RunResult) []jsonResult {
+	out := make([]jsonResult, 0, len(results))
+	for _, r := range results {
+		result := jsonResult{
+			Iteration:  r.Iteration + 1,
+			Name:       r.RequestName,
+			Method:     r.Method,
+			URL:        r.URL,
+			Status:     r.Status,
+			StatusText: r.StatusText,
+			DurationMS: r.Duration.Milliseconds(),
+			Skipped:    r.Skipped,
+			SkipReason: r.SkipReason,
+			Snapshot:   string(r.Snapshot),
+		}
+		if r.Error != nil {
+			result.Error = r.Error.Error()
+		}
+		for _, tr := range r.TestResults {
+			result.Tests = append(result.Tests, jsonTest{Name: tr.Name, Passed: tr.Passed, Error: tr.Error})
+		}
+		if len(r.RequestHeaders) > 0 || r.RequestBody != "" {
+			result.Request = &jsonMessage{Headers: r.RequestHeaders, Body: r.RequestBody}
+		}
+		if len(r.ResponseHeaders) > 0 || r.ResponseBody != "" {
+			result.Response = &jsonMessage{Headers: r.ResponseHeaders, Body: r.ResponseBody, Size: r.ResponseSize}
+		}
+		out = append(out, result)
+	}
+	return out
+}
@@ -0,0 +1,184 @@
+package reporter
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/artpar/currier/internal/runner"
+)
+
+// JUnitReporter writes JUnit XML for CI systems. Each request becomes a
+// testcase; each failed assertion becomes a failure element of that case.
+// Iterations of data-driven runs become separate testsuites.
+type JUnitReporter struct{}
+
+// NewJUnitReporter creates a new JUnit XML reporter.
+func NewJUnitReporter() *JUnitReporter {
+	return &JUnitReporter{}
+}
+
+func (j *JUnitReporter) Name() string          { return "junit" }
+func (j *JUnitReporter) FileExtension() string { return ".xml" }
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name       string           `xml:"name,attr"`
+	Tests      int              `xml:"tests,attr"`
+	Failures   int              `xml:"failures,attr"`
+	Errors     int              `xml:"errors,attr"`
+	Skipped    int              `xml:"skipped,attr"`
+	Time       string           `xml:"time,attr"`
+	Timestamp  string           `xml:"timestamp,attr,omitempty"`
+	Properties *junitProperties `xml:"properties,omitempty"`
+	Cases      []junitTestCase  `xml:"testcase"`
+}
+
+type junitProperties struct {
+	Properties []junitProperty `xml:"property"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitTestCase struct {
+	Name      string         `xml:"name,attr"`
+	ClassName string         `xml:"classname,attr"`
+	Time      string         `xml:"time,attr"`
+	Failures  []junitFailure `xml:"failure"`
+	Error     *junitFailure  `xml:"error,omitempty"`
+	Skipped   *junitSkipped  `xml:"skipped,omitempty"`
+	SystemOut string         `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr,omitempty"`
+}
+
+// Report writes the summary as JUnit XML.
+func (j *JUnitReporter) Report(w io.Writer, summary *runner.RunSummary) error {
+	iters := iterations(summary)
+	doc := junitTestSuites{
+		Name: summary.CollectionName,
+		Time: junitSeconds(summary.TotalDuration),
+	}
+
+	for _, it := range iters {
+		suite := junitTestSuite{
+			Name: summary.CollectionName,
+			Time: junitSeconds(it.Duration),
+		}
+		if len(iters) > 1 {
+			suite.Name = fmt.Sprintf("%s (iteration %d)", summary.CollectionName, it.Index+1)
+		}
+		if !summary.StartTime.IsZero() {
+			suite.Timestamp = summary.StartTime.UTC().Format("2006-01-02T15:04:05")
+		}
+		if len(it.Data) > 0 {
+			suite.Properties = &junitProperties{}
+			for _, k := range sortedKeys(it.Data) {
+				suite.Properties.Properties = append(suite.Properties.Properties, junitProperty{Name: k, Value: it.Data[k]})
+			}
+		}
+
+		for _, r := range it.Results {
+			tc := junitCase(summary.CollectionName, r)
+			suite.Tests++
+			switch {
+			case tc.Skipped != nil:
+				suite.Skipped++
+			case tc.Error != nil:
+				suite.Errors++
+			case len(tc.Failures) > 0:
+				suite.Failures++
+			}
+			suite.Cases = append(suite.Cases, tc)
+		}
+
+		doc.Tests += suite.Tests
+		doc.Failures += suite.Failures
+		doc.Errors += suite.Errors
+		doc.Skipped += suite.Skipped
+		doc.Suites = append(doc.Suites, suite)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func junitCase(className string, r runner.RunResult) junitTestCase {
+	tc := junitTestCase{
+		Name:      resultName(r),
+		ClassName: className,
+		Time:      junitSeconds(r.Duration),
+	}
+
+	if r.Skipped {
+		tc.Skipped = &junitSkipped{Message: r.SkipReason}
+		return tc
+	}
+
+	if r.Error != nil {
+		tc.Error = &junitFailure{
+			Message: r.Error.Error(),
+			Type:    "RequestError",
+			Text:    fmt.Sprintf("%s %s\n%s", r.Method, r.URL, r.Error.Error()),
+		}
+	}
+
+	var out strings.Builder
+	if r.URL != "" {
+		fmt.Fprintf(&out, "%s %s -> %d %s (%dms)\n", r.Method, r.URL, r.Status, r.StatusText, r.Duration.Milliseconds())
+	}
+	for _, tr := range r.TestResults {
+		if tr.Passed {
+			fmt.Fprintf(&out, "✓ %s\n", tr.Name)
+			continue
+		}
+		fmt.Fprintf(&out, "✗ %s\n", tr.Name)
+		message := tr.Name
+		if tr.Error != "" {
+			message = tr.Name + ": " + tr.Error
+		}
+		tc.Failures = append(tc.Failures, junitFailure{
+			Message: message,
+			Type:    "AssertionFailure",
+			Text:    tr.Error,
+		})
+	}
+	tc.SystemOut = out.String()
+
+	return tc
+}
+
+func junitSeconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
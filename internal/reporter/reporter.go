@@ -0,0 +1,213 @@
+// Package reporter writes collection run results in formats such as JUnit
+// XML, TAP, JSON and HTML.
+package reporter
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/artpar/currier/internal/runner"
+)
+
+// Reporter writes a collection run summary in a specific format.
+type Reporter interface {
+	// Name returns the name used to select this reporter (e.g. "junit").
+	Name() string
+
+	// FileExtension returns the file extension for report files.
+	FileExtension() string
+
+	// Report writes the summary to w.
+	Report(w io.Writer, summary *runner.RunSummary) error
+}
+
+// Spec selects a reporter and its destination. An empty Path writes to
+// standard output.
+type Spec struct {
+	Name string
+	Path string
+}
+
+// ParseSpecs parses reporter flag values of the form "name" or "name=path".
+// Each value may hold several comma-separated entries. At most one reporter
+// may write to standard output.
+func ParseSpecs(values []string) ([]Spec, error) {
+	var specs []Spec
+	stdout := ""
+
+	for _, value := range values {
+		for _, entry := range strings.Split(value, ",") {
+			entry = strings.TrimSpace(entry)
+			if entry == "" {
+				continue
+			}
+
+			name, path, _ := strings.Cut(entry, "=")
+			spec := Spec{
+				Name: strings.ToLower(strings.TrimSpace(name)),
+				Path: strings.TrimSpace(path),
+			}
+			if spec.Name == "" {
+				return nil, fmt.Errorf("invalid reporter %q: missing name", entry)
+			}
+			if spec.Path == "" {
+				if stdout != "" {
+					return nil, fmt.Errorf("reporters %q and %q both write to stdout; give one an output path with name=path", stdout, spec.Name)
+				}
+				stdout = spec.Name
+			}
+			specs = append(specs, spec)
+		}
+	}
+
+	return specs, nil
+}
+
+// Registry holds the available reporters.
+type Registry struct {
+	reporters map[string]Reporter
+}
+
+// NewRegistry creates an empty reporter registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		reporters: make(map[string]Reporter),
+	}
+}
+
+// NewDefaultRegistry creates a registry with the built-in file reporters:
+// json, junit, tap and html.
+func NewDefaultRegistry() *Registry {
+	r := NewRegistry()
+	r.Register(NewJSONReporter())
+	r.Register(NewJUnitReporter())
+	r.Register(NewTAPReporter())
+	r.Register(NewHTMLReporter())
+	return r
+}
+
+// Register adds a reporter, replacing any reporter with the same name.
+func (r *Registry) Register(rep Reporter) {
+	r.reporters[rep.Name()] = rep
+}
+
+// Get returns a reporter by name.
+func (r *Registry) Get(name string) (Reporter, bool) {
+	rep, ok := r.reporters[name]
+	return rep, ok
+}
+
+// ListNames returns the names of all registered reporters, sorted.
+func (r *Registry) ListNames() []string {
+	names := make([]string, 0, len(r.reporters))
+	for name := range r.reporters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Validate checks that every spec names a registered reporter.
+func (r *Registry) Validate(specs []Spec) error {
+	for _, spec := range specs {
+		if _, ok := r.reporters[spec.Name]; !ok {
+			return fmt.Errorf("unknown reporter %q (available: %s)", spec.Name, strings.Join(r.ListNames(), ", "))
+		}
+	}
+	return nil
+}
+
+// Write runs each selected reporter. Reporters with a path write to that
+// file, creating parent directories; the others write to stdout. All
+// reporters run even if one fails; the first error is returned.
+func (r *Registry) Write(specs []Spec, summary *runner.RunSummary, stdout io.Writer) error {
+	if err := r.Validate(specs); err != nil {
+		return err
+	}
+
+	var firstErr error
+	for _, spec := range specs {
+		if err := r.write(spec, summary, stdout); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (r *Registry) write(spec Spec, summary *runner.RunSummary, stdout io.Writer) error {
+	rep := r.reporters[spec.Name]
+	if spec.Path == "" {
+		return rep.Report(stdout, summary)
+	}
+
+	if dir := filepath.Dir(spec.Path); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create report directory: %w", err)
+		}
+	}
+	f, err := os.Create(spec.Path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s report: %w", spec.Name, err)
+	}
+	if err := rep.Report(f, summary); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write %s report: %w", spec.Name, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write %s report: %w", spec.Name, err)
+	}
+	return nil
+}
+
+// resultName returns the display name of a result, e.g. "GET Get Users".
+func resultName(r runner.RunResult) string {
+	if r.Method == "" {
+		return r.RequestName
+	}
+	return r.Method + " " + r.RequestName
+}
+
+// failedTests returns the failed assertions of a result.
+func failedTests(r runner.RunResult) int {
+	failed := 0
+	for _, tr := range r.TestResults {
+		if !tr.Passed {
+			failed++
+		}
+	}
+	return failed
+}
+
+// sortedKeys returns the keys of m in sorted order.
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// iterations returns the iteration groups of a summary. Summaries built
+// without iteration data are treated as a single iteration.
+func iterations(summary *runner.RunSummary) []runner.IterationSummary {
+	if len(summary.Iterations) > 0 {
+		return summary.Iterations
+	}
+	it := runner.IterationSummary{
+		Executed:    summary.Executed,
+		Passed:      summary.Passed,
+		Failed:      summary.Failed,
+		Skipped:     summary.Skipped,
+		TotalTests:  summary.TotalTests,
+		TestsPassed: summary.TestsPassed,
+		TestsFailed: summary.TestsFailed,
+		Duration:    summary.TotalDuration,
+		Results:     summary.Results,
+	}
+	return []runner.IterationSummary{it}
+}
@@ -0,0 +1,250 @@
+package reporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/artpar/currier/internal/runner"
+	"github.com/artpar/currier/internal/script"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sampleSummary() *runner.RunSummary {
+	results := []runner.RunResult{
+		{
+			RequestName: "List Users",
+			Method:      "GET",
+			URL:         "https://api.example.com/users",
+			Status:      200,
+			StatusText:  "200 OK",
+			Duration:    120 * time.Millisecond,
+			TestResults: []script.TestResult{
+				{Name: "status is 200", Passed: true},
+				{Name: "has <users>", Passed: false, Error: "expected 0 to be above 0"},
+			},
+			RequestHeaders:  map[string]string{"Accept": "application/json"},
+			ResponseHeaders: map[string]string{"Content-Type": "application/json"},
+			ResponseBody:    `{"users":[]}`,
+			ResponseSize:    12,
+		},
+		{
+			RequestName: "Create User",
+			Method:      "POST",
+			URL:         "https://api.example.com/users",
+			Duration:    30 * time.Millisecond,
+			Error:       errors.New("connection refused"),
+		},
+		{
+			RequestName: "Delete User",
+			Method:      "DELETE",
+			Skipped:     true,
+			SkipReason:  "skip condition: true",
+		},
+	}
+
+	return &runner.RunSummary{
+		CollectionName: "Users API",
+		TotalRequests:  3,
+		Executed:       2,
+		Passed:         0,
+		Failed:         2,
+		Skipped:        1,
+		TotalTests:     2,
+		TestsPassed:    1,
+		TestsFailed:    1,
+		TotalDuration:  150 * time.Millisecond,
+		Results:        results,
+		StartTime:      time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+}
+
+func TestParseSpecs(t *testing.T) {
+	t.Run("parses names and paths", func(t *testing.T) {
+		specs, err := ParseSpecs([]string{"cli,JUnit=out/results.xml", " html = report.html "})
+		require.NoError(t, err)
+		assert.Equal(t, []Spec{
+			{Name: "cli"},
+			{Name: "junit", Path: "out/results.xml"},
+			{Name: "html", Path: "report.html"},
+		}, specs)
+	})
+
+	t.Run("skips empty entries", func(t *testing.T) {
+		specs, err := ParseSpecs([]string{"tap,,"})
+		require.NoError(t, err)
+		assert.Equal(t, []Spec{{Name: "tap"}}, specs)
+	})
+
+	t.Run("rejects missing name", func(t *testing.T) {
+		_, err := ParseSpecs([]string{"=out.xml"})
+		assert.Error(t, err)
+	})
+
+	t.Run("rejects two stdout reporters", func(t *testing.T) {
+		_, err := ParseSpecs([]string{"cli", "json"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "both write to stdout")
+	})
+}
+
+func TestRegistry(t *testing.T) {
+	t.Run("default reporters", func(t *testing.T) {
+		r := NewDefaultRegistry()
+		assert.Equal(t, []string{"html", "json", "junit", "tap"}, r.ListNames())
+
+		rep, ok := r.Get("junit")
+		require.True(t, ok)
+		assert.Equal(t, ".xml", rep.FileExtension())
+	})
+
+	t.Run("validate rejects unknown reporter", func(t *testing.T) {
+		err := NewDefaultRegistry().Validate([]Spec{{Name: "xml"}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `unknown reporter "xml"`)
+		assert.Contains(t, err.Error(), "html, json, junit, tap")
+	})
+
+	t.Run("write sends reports to stdout and files", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "nested", "results.tap")
+		stdout := &bytes.Buffer{}
+
+		err := NewDefaultRegistry().Write([]Spec{{Name: "json"}, {Name: "tap", Path: path}}, sampleSummary(), stdout)
+		require.NoError(t, err)
+
+		assert.True(t, json.Valid(stdout.Bytes()))
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(string(data), "TAP version 13\n"))
+	})
+}
+
+func TestJSONReporter(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, NewJSONReporter().Report(&buf, sampleSummary()))
+
+	var report jsonReport
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &report))
+
+	assert.Equal(t, "Users API", report.Collection)
+	assert.Equal(t, 1, report.Skipped)
+	require.Len(t, report.Results, 3)
+	assert.Equal(t, "List Users", report.Results[0].Name)
+	require.Len(t, report.Results[0].Tests, 2)
+	assert.False(t, report.Results[0].Tests[1].Passed)
+	require.NotNil(t, report.Results[0].Response)
+	assert.Equal(t, `{"users":[]}`, report.Results[0].Response.Body)
+	assert.Equal(t, "connection refused", report.Results[1].Error)
+	assert.True(t, report.Results[2].Skipped)
+	assert.Nil(t, report.Snapshots)
+
+	summary := sampleSummary()
+	summary.Results[0].Snapshot = runner.SnapshotMismatched
+	summary.Snapshots = runner.SnapshotStats{Mismatched: 1}
+	buf.Reset()
+	require.NoError(t, NewJSONReporter().Report(&buf, summary))
+	report = jsonReport{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &report))
+	require.NotNil(t, report.Snapshots)
+	assert.Equal(t, 1, report.Snapshots.Mismatched)
+	assert.Equal(t, "mismatched", report.Results[0].Snapshot)
+}
+
+func TestJUnitReporter(t *testing.T) {
+	t.Run("single iteration", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, NewJUnitReporter().Report(&buf, sampleSummary()))
+
+		var doc junitTestSuites
+		require.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+
+		assert.Equal(t, 3, doc.Tests)
+		assert.Equal(t, 1, doc.Failures)
+		assert.Equal(t, 1, doc.Errors)
+		assert.Equal(t, 1, doc.Skipped)
+		require.Len(t, doc.Suites, 1)
+
+		suite := doc.Suites[0]
+		assert.Equal(t, "Users API", suite.Name)
+		assert.Equal(t, "2024-01-02T03:04:05", suite.Timestamp)
+		require.Len(t, suite.Cases, 3)
+
+		listCase := suite.Cases[0]
+		assert.Equal(t, "GET List Users", listCase.Name)
+		assert.Equal(t, "Users API", listCase.ClassName)
+		assert.Equal(t, "0.120", listCase.Time)
+		require.Len(t, listCase.Failures, 1)
+		assert.Equal(t, "has <users>: expected 0 to be above 0", listCase.Failures[0].Message)
+		assert.Equal(t, "AssertionFailure", listCase.Failures[0].Type)
+		assert.Contains(t, listCase.SystemOut, "✓ status is 200")
+
+		require.NotNil(t, suite.Cases[1].Error)
+		assert.Equal(t, "connection refused", suite.Cases[1].Error.Message)
+
+		require.NotNil(t, suite.Cases[2].Skipped)
+		assert.Equal(t, "skip condition: true", suite.Cases[2].Skipped.Message)
+	})
+
+	t.Run("one suite per iteration", func(t *testing.T) {
+		summary := sampleSummary()
+		summary.Iterations = []runner.IterationSummary{
+			{Index: 0, Data: map[string]string{"user": "alice"}, Results: summary.Results[:1]},
+			{Index: 1, Data: map[string]string{"user": "bob"}, Results: summary.Results[1:2]},
+		}
+
+		var buf bytes.Buffer
+		require.NoError(t, NewJUnitReporter().Report(&buf, summary))
+
+		var doc junitTestSuites
+		require.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+		require.Len(t, doc.Suites, 2)
+		assert.Equal(t, "Users API (iteration 2)", doc.Suites[1].Name)
+		require.NotNil(t, doc.Suites[1].Properties)
+		assert.Equal(t, []junitProperty{{Name: "user", Value: "bob"}}, doc.Suites[1].Properties.Properties)
+	})
+}
+
+func TestTAPReporter(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, NewTAPReporter().Report(&buf, sampleSummary()))
+	out := buf.String()
+
+	assert.True(t, strings.HasPrefix(out, "TAP version 13\n1..3\n"))
+	assert.Contains(t, out, "    # Subtest: GET List Users\n")
+	assert.Contains(t, out, "    ok 1 - status is 200\n")
+	assert.Contains(t, out, "    not ok 2 - has <users>\n")
+	assert.Contains(t, out, "      message: \"expected 0 to be above 0\"\n")
+	assert.Contains(t, out, "not ok 1 - GET List Users\n")
+	assert.Contains(t, out, "not ok 2 - POST Create User\n")
+	assert.Contains(t, out, "  error: \"connection refused\"\n")
+	assert.Contains(t, out, "ok 3 - DELETE Delete User # SKIP skip condition: true\n")
+}
+
+func TestTAPEscape(t *testing.T) {
+	assert.Equal(t, `issue \#1 fix`, tapEscape("issue #1\nfix"))
+}
+
+func TestHTMLReporter(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, NewHTMLReporter().Report(&buf, sampleSummary()))
+	out := buf.String()
+
+	assert.True(t, strings.HasPrefix(out, "<!DOCTYPE html>"))
+	assert.Contains(t, out, "<title>Users API - Currier run report</title>")
+	assert.Contains(t, out, `<details class="request failed" open>`)
+	assert.Contains(t, out, `<details class="request skipped">`)
+	assert.Contains(t, out, "has &lt;users&gt;")
+	assert.Contains(t, out, "expected 0 to be above 0")
+	assert.Contains(t, out, "connection refused")
+	assert.Contains(t, out, "{&#34;users&#34;:[]}")
+	assert.Contains(t, out, `style="width: 100%"`)
+	assert.NotContains(t, out, "<users>")
+}
@@ -0,0 +1,107 @@
+package reporter
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/artpar/currier/internal/runner"
+)
+
+// TAPReporter writes Test Anything Protocol (version 13) output. Each
+// request is a test point; its assertions are reported as a subtest.
+type TAPReporter struct{}
+
+// NewTAPReporter creates a new TAP reporter.
+func NewTAPReporter() *TAPReporter {
+	return &TAPReporter{}
+}
+
+func (t *TAPReporter) Name() string          { return "tap" }
+func (t *TAPReporter) FileExtension() string { return ".tap" }
+
+// Report writes the summary as TAP.
+func (t *TAPReporter) Report(w io.Writer, summary *runner.RunSummary) error {
+	var b strings.Builder
+	iters := iterations(summary)
+
+	b.WriteString("TAP version 13\n")
+	fmt.Fprintf(&b, "1..%d\n", len(summary.Results))
+	fmt.Fprintf(&b, "# %s\n", tapEscape(summary.CollectionName))
+
+	n := 0
+	for _, it := range iters {
+		if len(iters) > 1 {
+			fmt.Fprintf(&b, "# Iteration %d\n", it.Index+1)
+		}
+		for _, r := range it.Results {
+			n++
+			writeTAPResult(&b, n, r)
+		}
+	}
+
+	fmt.Fprintf(&b, "# requests %d, passed %d, failed %d, skipped %d\n",
+		summary.TotalRequests, summary.Passed, summary.Failed, summary.Skipped)
+	fmt.Fprintf(&b, "# tests %d, passed %d, failed %d\n",
+		summary.TotalTests, summary.TestsPassed, summary.TestsFailed)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeTAPResult(b *strings.Builder, n int, r runner.RunResult) {
+	name := tapEscape(resultName(r))
+
+	if r.Skipped {
+		fmt.Fprintf(b, "ok %d - %s # SKIP %s\n", n, name, tapEscape(r.SkipReason))
+		return
+	}
+
+	// Assertions as a subtest
+	if len(r.TestResults) > 0 {
+		fmt.Fprintf(b, "    # Subtest: %s\n", name)
+		for i, tr := range r.TestResults {
+			status := "ok"
+			if !tr.Passed {
+				status = "not ok"
+			}
+			fmt.Fprintf(b, "    %s %d - %s\n", status, i+1, tapEscape(tr.Name))
+			if !tr.Passed && tr.Error != "" {
+				b.WriteString("      ---\n")
+				fmt.Fprintf(b, "      message: %s\n", yamlQuote(tr.Error))
+				b.WriteString("      ...\n")
+			}
+		}
+		fmt.Fprintf(b, "    1..%d\n", len(r.TestResults))
+	}
+
+	status := "ok"
+	if r.Error != nil || failedTests(r) > 0 {
+		status = "not ok"
+	}
+	fmt.Fprintf(b, "%s %d - %s\n", status, n, name)
+
+	b.WriteString("  ---\n")
+	if r.URL != "" {
+		fmt.Fprintf(b, "  url: %s\n", yamlQuote(r.URL))
+	}
+	if r.Status != 0 {
+		fmt.Fprintf(b, "  status: %d\n", r.Status)
+	}
+	fmt.Fprintf(b, "  duration_ms: %d\n", r.Duration.Milliseconds())
+	if r.Error != nil {
+		fmt.Fprintf(b, "  error: %s\n", yamlQuote(r.Error.Error()))
+	}
+	b.WriteString("  ...\n")
+}
+
+// tapEscape keeps descriptions on one line and escapes the directive marker.
+func tapEscape(s string) string {
+	s = strings.ReplaceAll(s, "\n", " ")
+	return strings.ReplaceAll(s, "#", "\\#")
+}
+
+// yamlQuote returns s as a double-quoted YAML scalar.
+func yamlQuote(s string) string {
+	return fmt.Sprintf("%q", s)
+}
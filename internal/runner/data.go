@@ -0,0 +1,116 @@
+package runner
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LoadIterationData reads a data file for data-driven runs. CSV files use the
+// header row as variable names; JSON files must contain an array of objects.
+// Each row becomes the variable layer for one iteration.
+func LoadIterationData(path string) ([]map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read data file: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return ParseCSVData(data)
+	case ".json":
+		return ParseJSONData(data)
+	}
+
+	// Unknown extension: detect from content
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		return ParseJSONData(data)
+	}
+	return ParseCSVData(data)
+}
+
+// ParseCSVData parses CSV iteration data. The first row holds variable names.
+func ParseCSVData(data []byte) ([]map[string]string, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CSV header: %w", err)
+	}
+	for i := range header {
+		header[i] = strings.TrimSpace(header[i])
+	}
+	if len(header) > 0 {
+		// Strip UTF-8 BOM written by spreadsheet tools
+		header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	}
+
+	var rows []map[string]string
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CSV: %w", err)
+		}
+		if len(record) > len(header) {
+			line, _ := reader.FieldPos(0)
+			return nil, fmt.Errorf("CSV line %d has %d fields, header has %d", line, len(record), len(header))
+		}
+
+		row := make(map[string]string, len(header))
+		for i, name := range header {
+			if name == "" {
+				continue
+			}
+			if i < len(record) {
+				row[name] = record[i]
+			} else {
+				row[name] = ""
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// ParseJSONData parses JSON iteration data: an array of objects. String
+// values are used as-is; other values are stored as their JSON encoding.
+func ParseJSONData(data []byte) ([]map[string]string, error) {
+	var raw []map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON data (expected an array of objects): %w", err)
+	}
+
+	rows := make([]map[string]string, 0, len(raw))
+	for _, obj := range raw {
+		row := make(map[string]string, len(obj))
+		for k, v := range obj {
+			var s string
+			if err := json.Unmarshal(v, &s); err == nil {
+				row[k] = s
+				continue
+			}
+			if string(v) == "null" {
+				row[k] = ""
+				continue
+			}
+			row[k] = string(v)
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,94 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadIterationData(t *testing.T) {
+	t.Run("loads CSV with header row", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "users.csv")
+		content := "\ufeffuser, id\nalice,1\n\"bob, jr\",2\ncarol\n"
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		rows, err := LoadIterationData(path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(rows) != 3 {
+			t.Fatalf("expected 3 rows, got %d", len(rows))
+		}
+		if rows[0]["user"] != "alice" || rows[0]["id"] != "1" {
+			t.Errorf("unexpected first row: %v", rows[0])
+		}
+		if rows[1]["user"] != "bob, jr" {
+			t.Errorf("expected quoted field, got %q", rows[1]["user"])
+		}
+		if v, ok := rows[2]["id"]; !ok || v != "" {
+			t.Errorf("expected missing field to be empty, got %q (present=%v)", v, ok)
+		}
+	})
+
+	t.Run("loads JSON array of objects", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "users.json")
+		content := `[{"user": "alice", "id": 1, "admin": true, "tags": ["a"], "note": null}]`
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		rows, err := LoadIterationData(path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(rows) != 1 {
+			t.Fatalf("expected 1 row, got %d", len(rows))
+		}
+		want := map[string]string{"user": "alice", "id": "1", "admin": "true", "tags": `["a"]`, "note": ""}
+		for k, v := range want {
+			if rows[0][k] != v {
+				t.Errorf("%s: expected %q, got %q", k, v, rows[0][k])
+			}
+		}
+	})
+
+	t.Run("detects format for unknown extension", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "data.txt")
+		if err := os.WriteFile(path, []byte(`[{"a": "b"}]`), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		rows, err := LoadIterationData(path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(rows) != 1 || rows[0]["a"] != "b" {
+			t.Errorf("unexpected rows: %v", rows)
+		}
+	})
+
+	t.Run("rejects invalid JSON", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "data.json")
+		if err := os.WriteFile(path, []byte(`{"not": "an array"}`), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := LoadIterationData(path); err == nil {
+			t.Error("expected error for non-array JSON")
+		}
+	})
+
+	t.Run("rejects rows with extra fields", func(t *testing.T) {
+		if _, err := ParseCSVData([]byte("a\n1,2\n")); err == nil {
+			t.Error("expected error for extra CSV fields")
+		}
+	})
+
+	t.Run("returns error for missing file", func(t *testing.T) {
+		if _, err := LoadIterationData(filepath.Join(t.TempDir(), "missing.csv")); err == nil {
+			t.Error("expected error for missing file")
+		}
+	})
+}
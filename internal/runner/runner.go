@@ -5,12 +5,18 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/cookiejar"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/artpar/currier/internal/assertion"
 	"github.com/artpar/currier/internal/core"
+	"github.com/artpar/currier/internal/importer"
 	"github.com/artpar/currier/internal/interpolate"
 	httpclient "github.com/artpar/currier/internal/protocol/http"
 	"github.com/artpar/currier/internal/script"
+	"github.com/artpar/currier/internal/secrets"
 )
 
 // RunResult represents the result of a single request execution.
@@ -24,6 +30,40 @@ type RunResult struct {
 	Duration    time.Duration
 	TestResults []script.TestResult
 	Error       error
+	Iteration   int  // Zero-based iteration index
+	Skipped     bool // Request was not sent (skip condition or skipRequest)
+	SkipReason  string
+
+	// Request and response details for reporters. Bodies are truncated to
+	// MaxRecordedBodySize bytes; ResponseSize is the full size.
+	RequestHeaders  map[string]string
+	RequestBody     string
+	ResponseHeaders map[string]string
+	ResponseBody    string
+	ResponseSize    int64
+
+	// Snapshot is the outcome of the snapshot comparison, empty when
+	// snapshots are off or no response was received.
+	Snapshot     SnapshotStatus
+	SnapshotFile string
+}
+
+// MaxRecordedBodySize is the maximum number of body bytes kept on a RunResult.
+const MaxRecordedBodySize = 64 * 1024
+
+// IterationSummary groups the results of one iteration of a data-driven run.
+type IterationSummary struct {
+	Index       int
+	Data        map[string]string
+	Executed    int
+	Passed      int
+	Failed      int
+	Skipped     int
+	TotalTests  int
+	TestsPassed int
+	TestsFailed int
+	Duration    time.Duration
+	Results     []RunResult
 }
 
 // RunSummary represents the summary of a collection run.
@@ -33,26 +73,63 @@ type RunSummary struct {
 	Executed       int
 	Passed         int
 	Failed         int
+	Skipped        int
+	Stopped        bool // A script stopped the run early
 	TotalTests     int
 	TestsPassed    int
 	TestsFailed    int
 	TotalDuration  time.Duration
 	Results        []RunResult
+	Iterations     []IterationSummary
+	Snapshots      SnapshotStats
 	StartTime      time.Time
 	EndTime        time.Time
 }
 
-// ProgressCallback is called after each request is executed.
+// ProgressCallback is called after each request is executed. current is the
+// number of requests completed so far. Calls are serialized by the runner, so
+// a callback never runs concurrently with itself, even in parallel runs.
 type ProgressCallback func(current int, total int, result *RunResult)
 
 // Runner executes all requests in a collection.
 type Runner struct {
-	collection *core.Collection
-	env        *core.Environment
-	engine     *interpolate.Engine
-	httpClient *httpclient.Client
-	cookieJar  http.CookieJar
-	onProgress ProgressCallback
+	collection  *core.Collection
+	env         *core.Environment
+	globals     map[string]string
+	secrets     []string // Secret values of global environments, masked in results
+	engine      *interpolate.Engine
+	httpClient  *httpclient.Client
+	cookieJar   http.CookieJar
+	onProgress  ProgressCallback
+	concurrency int
+	iterations  int
+	data        []map[string]string
+	maxRuns     int
+	snapshots   *SnapshotStore
+	contract    *importer.Contract
+	moduleDir   string
+	modules     *script.ModuleLoader // Set by Run, shared by the run's scripts
+	// snapshotNames maps requests to their snapshot names; set by Run
+	snapshotNames map[*core.RequestDefinition]string
+}
+
+// defaultMaxRequestRuns limits how often one request may run per iteration
+// when scripts jump with setNextRequest, to break accidental infinite loops.
+const defaultMaxRequestRuns = 100
+
+// flow is the run flow control requested by a request's scripts.
+type flow struct {
+	next string // Request name or ID to run next
+	jump bool
+	stop bool
+}
+
+// iteration holds the per-iteration state shared by its requests.
+type iteration struct {
+	index  int
+	count  int
+	data   map[string]string
+	engine *interpolate.Engine
 }
 
 // Option configures the Runner.
@@ -63,11 +140,36 @@ func WithEnvironment(env *core.Environment) Option {
 	return func(r *Runner) {
 		r.env = env
 		if env != nil {
-			r.engine.SetVariables(env.ExportAll())
+			r.engine.Scope().SetEnvironment(interpolate.NewVariableSetFrom(env.ExportAll()))
 		}
 	}
 }
 
+// WithGlobals sets the variables of global environments, which resolve
+// beneath the environment's.
+func WithGlobals(globals map[string]string) Option {
+	return func(r *Runner) {
+		r.globals = globals
+		r.engine.Scope().SetGlobal(interpolate.NewVariableSetFrom(globals))
+	}
+}
+
+// WithSecrets sets secret values, such as those of global environments, that
+// are masked in results along with the environment's secrets.
+func WithSecrets(values ...string) Option {
+	return func(r *Runner) {
+		r.secrets = append(r.secrets, values...)
+	}
+}
+
+// WithSeed seeds the random source of {{$random*}} dynamic variables, so
+// runs generate the same values each time.
+func WithSeed(seed uint64) Option {
+	return func(r *Runner) {
+		r.engine.SetSeed(seed)
+	}
+}
+
 // WithHTTPClient sets a custom HTTP client.
 func WithHTTPClient(client *httpclient.Client) Option {
 	return func(r *Runner) {
@@ -89,6 +191,67 @@ func WithProgressCallback(cb ProgressCallback) Option {
 	}
 }
 
+// WithConcurrency sets how many requests may run at the same time. Values
+// below 2 run the collection sequentially. Requests inside folders marked
+// sequential always run in order, one at a time.
+func WithConcurrency(n int) Option {
+	return func(r *Runner) {
+		r.concurrency = n
+	}
+}
+
+// WithIterations sets how many times the collection is run. When iteration
+// data is set and n is 0, one iteration runs per data row; when n exceeds the
+// number of rows, rows are reused from the start.
+func WithIterations(n int) Option {
+	return func(r *Runner) {
+		r.iterations = n
+	}
+}
+
+// WithIterationData sets the data rows for a data-driven run. Each row is
+// exposed as iteration-scoped variables to interpolation and scripts.
+func WithIterationData(rows []map[string]string) Option {
+	return func(r *Runner) {
+		r.data = rows
+	}
+}
+
+// WithMaxRequestRuns sets how many times a single request may run within one
+// iteration when scripts jump with setNextRequest. When the limit is hit the
+// iteration ends with an error. Defaults to 100.
+func WithMaxRequestRuns(n int) Option {
+	return func(r *Runner) {
+		r.maxRuns = n
+	}
+}
+
+// WithSnapshots enables response snapshot testing. Each response is compared
+// with its stored snapshot and a mismatch is reported as a failed test with a
+// unified diff; responses without a snapshot are recorded.
+func WithSnapshots(store *SnapshotStore) Option {
+	return func(r *Runner) {
+		r.snapshots = store
+	}
+}
+
+// WithContract validates every response against an OpenAPI contract. The
+// outcome of each contract check is added to the request's test results.
+func WithContract(contract *importer.Contract) Option {
+	return func(r *Runner) {
+		r.contract = contract
+	}
+}
+
+// WithModuleDir sets the directory scripts' require() resolves local
+// modules against, usually the collection file's directory. Without it,
+// scripts can only require built-in modules.
+func WithModuleDir(dir string) Option {
+	return func(r *Runner) {
+		r.moduleDir = dir
+	}
+}
+
 // NewRunner creates a new collection runner.
 func NewRunner(collection *core.Collection, opts ...Option) *Runner {
 	// Create cookie jar for this run
@@ -98,22 +261,28 @@ func NewRunner(collection *core.Collection, opts ...Option) *Runner {
 		collection: collection,
 		engine:     interpolate.NewEngine(),
 		cookieJar:  jar,
+		maxRuns:    defaultMaxRequestRuns,
 	}
 
-	// Create default HTTP client with cookie jar
-	r.httpClient = httpclient.NewClient(
-		httpclient.WithCookieJar(r.cookieJar),
-		httpclient.WithTimeout(30*time.Second),
-	)
-
 	for _, opt := range opts {
 		opt(r)
 	}
+	r.engine.Scope().SetCollection(interpolate.NewVariableSetFrom(collection.Variables()))
+
+	// Create default HTTP client with the run's cookie jar
+	if r.httpClient == nil {
+		r.httpClient = httpclient.NewClient(
+			httpclient.WithCookieJar(r.cookieJar),
+			httpclient.WithTimeout(30*time.Second),
+		)
+	}
 
 	return r
 }
 
-// Run executes all requests in the collection sequentially.
+// Run executes all requests in the collection once per iteration. Requests
+// run sequentially unless a concurrency above 1 is configured; results are
+// always reported in collection order, grouped by iteration.
 func (r *Runner) Run(ctx context.Context) *RunSummary {
 	summary := &RunSummary{
 		CollectionName: r.collection.Name(),
@@ -123,37 +292,46 @@ func (r *Runner) Run(ctx context.Context) *RunSummary {
 
 	// Collect all requests from collection
 	requests := r.walkRequests(r.collection)
-	summary.TotalRequests = len(requests)
+	count := r.iterationCount()
+	if r.snapshots != nil {
+		r.snapshotNames = snapshotNames(r.collection)
+	}
+	// Modules are compiled once per run
+	r.modules = script.NewModuleLoader(r.moduleDir)
+	summary.TotalRequests = len(requests) * count
 
-	for i, reqDef := range requests {
+	for i := 0; i < count; i++ {
 		// Check for context cancellation
 		if ctx.Err() != nil {
 			break
 		}
 
-		result := r.executeRequest(ctx, reqDef)
-		summary.Results = append(summary.Results, result)
-		summary.Executed++
+		iter := r.newIteration(i, count)
+		iterSummary := IterationSummary{
+			Index:   i,
+			Data:    iter.data,
+			Results: make([]RunResult, 0, len(requests)),
+		}
+		iterStart := time.Now()
 
-		// Update statistics
-		if result.Error == nil {
-			summary.Passed++
+		var results []RunResult
+		var stopped bool
+		if r.concurrency > 1 {
+			results, stopped = r.runParallel(ctx, iter, requests, summary.TotalRequests)
 		} else {
-			summary.Failed++
+			results, stopped = r.runSequential(ctx, iter, requests, summary.TotalRequests)
 		}
 
-		for _, tr := range result.TestResults {
-			summary.TotalTests++
-			if tr.Passed {
-				summary.TestsPassed++
-			} else {
-				summary.TestsFailed++
-			}
+		for _, result := range results {
+			summary.addResult(result)
+			iterSummary.addResult(result)
 		}
+		iterSummary.Duration = time.Since(iterStart)
+		summary.Iterations = append(summary.Iterations, iterSummary)
 
-		// Call progress callback
-		if r.onProgress != nil {
-			r.onProgress(i+1, len(requests), &result)
+		if stopped {
+			summary.Stopped = true
+			break
 		}
 	}
 
@@ -163,6 +341,228 @@ func (r *Runner) Run(ctx context.Context) *RunSummary {
 	return summary
 }
 
+// iterationCount returns the number of iterations to run.
+func (r *Runner) iterationCount() int {
+	if r.iterations > 0 {
+		return r.iterations
+	}
+	if len(r.data) > 0 {
+		return len(r.data)
+	}
+	return 1
+}
+
+// newIteration prepares the state for iteration i. Iterations with data get
+// their own interpolation engine so rows never leak between iterations.
+func (r *Runner) newIteration(i, count int) *iteration {
+	iter := &iteration{index: i, count: count, engine: r.engine}
+	if len(r.data) > 0 {
+		iter.data = r.data[i%len(r.data)]
+		iter.engine = r.engine.Clone()
+		iter.engine.SetIterationData(iter.data)
+	}
+	return iter
+}
+
+// runSequential executes requests one at a time in collection order,
+// following setNextRequest jumps. It reports whether a script stopped the run.
+func (r *Runner) runSequential(ctx context.Context, iter *iteration, requests []*core.RequestDefinition, total int) ([]RunResult, bool) {
+	results := make([]RunResult, 0, len(requests))
+	offset := iter.index * len(requests)
+	runs := make([]int, len(requests))
+
+	for i := 0; i < len(requests); {
+		// Check for context cancellation
+		if ctx.Err() != nil {
+			break
+		}
+
+		reqDef := requests[i]
+		runs[i]++
+
+		var result RunResult
+		var f flow
+		looped := r.maxRuns > 0 && runs[i] > r.maxRuns
+		if looped {
+			// Loop protection: end this iteration
+			result = RunResult{
+				RequestID:   reqDef.ID(),
+				RequestName: reqDef.Name(),
+				Method:      reqDef.Method(),
+				Iteration:   iter.index,
+				Error:       fmt.Errorf("loop protection: %q already ran %d times in this iteration", reqDef.Name(), r.maxRuns),
+			}
+		} else {
+			result, f = r.executeRequest(ctx, iter, reqDef)
+		}
+
+		next := i + 1
+		switch {
+		case looped:
+			next = -1
+		case f.jump && !f.stop:
+			next = findRequest(requests, f.next)
+			if next < 0 && result.Error == nil {
+				result.Error = fmt.Errorf("setNextRequest: no request named %q", f.next)
+			}
+		}
+
+		results = append(results, result)
+
+		// Call progress callback
+		if r.onProgress != nil {
+			current := offset + len(results)
+			if current > total {
+				current = total
+			}
+			r.onProgress(current, total, &result)
+		}
+
+		if f.stop {
+			return results, true
+		}
+		if next < 0 {
+			break
+		}
+		i = next
+	}
+
+	return results, false
+}
+
+// findRequest returns the index of the first request whose name or ID
+// matches target, or -1.
+func findRequest(requests []*core.RequestDefinition, target string) int {
+	for i, req := range requests {
+		if req.Name() == target || req.ID() == target {
+			return i
+		}
+	}
+	return -1
+}
+
+// runParallel executes requests in a worker pool. Each work unit is either a
+// single request or all requests of a sequential folder, run in order.
+// setNextRequest jumps need a defined order and are ignored here; stopping
+// the run prevents requests that have not started yet from running.
+func (r *Runner) runParallel(ctx context.Context, iter *iteration, requests []*core.RequestDefinition, total int) ([]RunResult, bool) {
+	results := make([]*RunResult, len(requests))
+	units := r.workUnits(r.collection)
+
+	work := make(chan []int)
+	stop := make(chan struct{})
+	var stopOnce sync.Once
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	completed := iter.index * len(requests)
+
+	workers := r.concurrency
+	if workers > len(units) {
+		workers = len(units)
+	}
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for unit := range work {
+				for _, idx := range unit {
+					if ctx.Err() != nil || isClosed(stop) {
+						break
+					}
+					result, f := r.executeRequest(ctx, iter, requests[idx])
+					if f.stop {
+						stopOnce.Do(func() { close(stop) })
+					}
+
+					mu.Lock()
+					results[idx] = &result
+					completed++
+					if r.onProgress != nil {
+						r.onProgress(completed, total, &result)
+					}
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+
+dispatch:
+	for _, unit := range units {
+		select {
+		case work <- unit:
+		case <-ctx.Done():
+			break dispatch
+		case <-stop:
+			break dispatch
+		}
+	}
+	close(work)
+	wg.Wait()
+
+	// Report results in collection order regardless of completion order
+	ordered := make([]RunResult, 0, len(requests))
+	for _, result := range results {
+		if result != nil {
+			ordered = append(ordered, *result)
+		}
+	}
+	return ordered, isClosed(stop)
+}
+
+// isClosed reports whether ch has been closed.
+func isClosed(ch chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
+
+// workUnits groups request indexes (in walkRequests order) into units that
+// can run independently of each other.
+func (r *Runner) workUnits(coll *core.Collection) [][]int {
+	var units [][]int
+	next := 0
+
+	for range coll.Requests() {
+		units = append(units, []int{next})
+		next++
+	}
+	for _, folder := range coll.Folders() {
+		units = r.folderUnits(folder, units, &next)
+	}
+
+	return units
+}
+
+// folderUnits appends the units for a folder. A sequential folder becomes a
+// single unit covering all of its requests, including those in subfolders.
+func (r *Runner) folderUnits(folder *core.Folder, units [][]int, next *int) [][]int {
+	if folder.Sequential() {
+		count := len(r.walkFolder(folder))
+		unit := make([]int, count)
+		for i := range unit {
+			unit[i] = *next
+			*next++
+		}
+		if count > 0 {
+			units = append(units, unit)
+		}
+		return units
+	}
+
+	for range folder.Requests() {
+		units = append(units, []int{*next})
+		*next++
+	}
+	for _, subfolder := range folder.Folders() {
+		units = r.folderUnits(subfolder, units, next)
+	}
+
+	return units
+}
+
 // walkRequests recursively collects all requests from a collection.
 func (r *Runner) walkRequests(coll *core.Collection) []*core.RequestDefinition {
 	var requests []*core.RequestDefinition
@@ -195,20 +595,69 @@ func (r *Runner) walkFolder(folder *core.Folder) []*core.RequestDefinition {
 	return requests
 }
 
-// executeRequest executes a single request and returns the result.
-func (r *Runner) executeRequest(ctx context.Context, reqDef *core.RequestDefinition) RunResult {
+// redact masks secrets in the request and response details of result, which
+// reporters write to files.
+func (r *Runner) redact(result *RunResult) {
+	values := append(secrets.ResolvedValues(), r.secrets...)
+	if r.env != nil {
+		values = append(values, r.env.SecretValues()...)
+	}
+	redactor := secrets.NewRedactor(values...)
+	if redactor == nil {
+		return
+	}
+
+	result.URL = redactor.Redact(result.URL)
+	result.RequestHeaders = redactor.RedactMap(result.RequestHeaders)
+	result.RequestBody = redactor.Redact(result.RequestBody)
+	result.ResponseHeaders = redactor.RedactMap(result.ResponseHeaders)
+	result.ResponseBody = redactor.Redact(result.ResponseBody)
+	if result.Error != nil {
+		if msg := redactor.Redact(result.Error.Error()); msg != result.Error.Error() {
+			result.Error = &redactedError{msg: msg, err: result.Error}
+		}
+	}
+}
+
+// redactedError is an error whose message has secrets masked.
+type redactedError struct {
+	msg string
+	err error
+}
+
+func (e *redactedError) Error() string { return e.msg }
+
+func (e *redactedError) Unwrap() error { return e.err }
+
+// executeRequest executes a single request and returns the result along
+// with any flow control its scripts requested.
+func (r *Runner) executeRequest(ctx context.Context, iter *iteration, reqDef *core.RequestDefinition) (RunResult, flow) {
 	result := RunResult{
 		RequestID:   reqDef.ID(),
 		RequestName: reqDef.Name(),
 		Method:      reqDef.Method(),
+		Iteration:   iter.index,
 	}
 
 	startTime := time.Now()
 
 	// Create script scope for this request
 	scriptScope := script.NewScopeWithAssertions()
+	scriptScope.SetModuleLoader(r.modules)
+	scriptScope.SetRequestSender(ScriptRequestSender(ctx, r.httpClient))
+	scriptScope.SetCookieJar(r.cookieJar)
+
+	finish := func() (RunResult, flow) {
+		result.Duration = time.Since(startTime)
+		r.redact(&result)
+		next, jump := scriptScope.NextRequest()
+		return result, flow{next: next, jump: jump, stop: scriptScope.StopRequested()}
+	}
 
-	// Set up script context with environment variables
+	// Set up script context with global and environment variables
+	for k, v := range r.globals {
+		scriptScope.SetVariable(k, v)
+	}
 	if r.env != nil {
 		scriptScope.SetEnvironmentName(r.env.Name())
 		for k, v := range r.env.ExportAll() {
@@ -216,36 +665,67 @@ func (r *Runner) executeRequest(ctx context.Context, reqDef *core.RequestDefinit
 			scriptScope.SetEnvironmentVariable(k, v)
 		}
 	}
+	// Variables resolve through the run's scope plus the request's folders;
+	// extractions still land in the run's request level
+	engine := iter.engine.With(interpolate.LevelFolder, r.collection.FolderVariables(reqDef.ID()))
+	for k, v := range engine.Variables() {
+		scriptScope.SetVariable(k, v)
+	}
+	scriptScope.SetIterationInfo(iter.index, iter.count)
+	if iter.data != nil {
+		scriptScope.SetIterationData(iter.data)
+	}
+
+	// Evaluate the skip condition before any script runs
+	if cond := strings.TrimSpace(reqDef.SkipCondition()); cond != "" {
+		value, err := scriptScope.Execute(ctx, "!!("+cond+"\n)")
+		if err != nil {
+			result.Error = fmt.Errorf("skip condition error: %w", err)
+			return finish()
+		}
+		if skip, _ := value.(bool); skip {
+			result.Skipped = true
+			result.SkipReason = "skip condition: " + cond
+			return finish()
+		}
+	}
 
 	// Run pre-request script
 	if preScript := reqDef.PreScript(); preScript != "" {
 		if _, err := scriptScope.Execute(ctx, preScript); err != nil {
 			result.Error = fmt.Errorf("pre-request script error: %w", err)
-			result.Duration = time.Since(startTime)
-			return result
+			return finish()
+		}
+		if scriptScope.SkipRequested() {
+			result.Skipped = true
+			result.SkipReason = "skipped by pre-request script"
+			return finish()
 		}
 	}
 
 	// Convert RequestDefinition to Request with interpolation
-	req, err := reqDef.ToRequestWithEnv(r.engine)
+	req, err := reqDef.ToRequestWithEnv(engine)
 	if err != nil {
 		result.Error = fmt.Errorf("failed to create request: %w", err)
-		result.Duration = time.Since(startTime)
-		return result
+		return finish()
 	}
 
 	result.URL = req.Endpoint()
+	result.RequestHeaders = headerMap(req.Headers())
+	result.RequestBody = recordBody(req.Body())
 
 	// Execute request
 	resp, err := r.httpClient.Send(ctx, req)
 	if err != nil {
 		result.Error = fmt.Errorf("request failed: %w", err)
-		result.Duration = time.Since(startTime)
-		return result
+		return finish()
 	}
 
 	result.Status = resp.Status().Code()
 	result.StatusText = resp.Status().Text()
+	result.ResponseHeaders = headerMap(resp.Headers())
+	result.ResponseBody = recordBody(resp.Body())
+	result.ResponseSize = resp.Body().Size()
 
 	// Run post-request script (tests)
 	if postScript := reqDef.PostScript(); postScript != "" {
@@ -262,6 +742,7 @@ func (r *Runner) executeRequest(ctx context.Context, reqDef *core.RequestDefinit
 			respHeaders[key] = resp.Headers().Get(key)
 		}
 		scriptScope.SetResponseHeaders(respHeaders)
+		scriptScope.SetResponseCookies(script.ParseSetCookies(resp.Headers().GetAll("Set-Cookie")))
 
 		if _, err := scriptScope.Execute(ctx, postScript); err != nil {
 			result.Error = fmt.Errorf("test script error: %w", err)
@@ -271,10 +752,100 @@ func (r *Runner) executeRequest(ctx context.Context, reqDef *core.RequestDefinit
 		result.TestResults = scriptScope.GetTestResults()
 	}
 
-	result.Duration = time.Since(startTime)
+	// Extract variables first so assertions can refer to them
+	vars, extracted := assertion.Extract(reqDef.Extractions(), resp)
+	iter.engine.SetVariables(vars)
+	result.TestResults = append(result.TestResults, assertion.Evaluate(reqDef.Assertions(), resp, engine)...)
+	result.TestResults = append(result.TestResults, extracted...)
+
+	if r.contract != nil {
+		checks := r.contract.Check(req.Method(), req.Endpoint(), resp.Status().Code(),
+			resp.Headers().Get("Content-Type"), resp.Body().Bytes())
+		result.TestResults = append(result.TestResults, ContractTestResults(checks)...)
+	}
+
+	if r.snapshots != nil {
+		r.checkSnapshot(&result, iter, reqDef, resp)
+	}
+
+	return finish()
+}
+
+// checkSnapshot compares the response with the request's snapshot and adds
+// the outcome to the result as a test. Iterations of a multi-iteration run
+// have their own snapshots, since their data usually differs.
+func (r *Runner) checkSnapshot(result *RunResult, iter *iteration, reqDef *core.RequestDefinition, resp *core.Response) {
+	name := r.snapshotNames[reqDef]
+	if name == "" {
+		name = snapshotFileName(reqDef.Name())
+	}
+	if iter.count > 1 {
+		name += ".iteration-" + strconv.Itoa(iter.index+1)
+	}
+	name += SnapshotExt
+	result.SnapshotFile = r.snapshots.Path(name)
+
+	actual := r.snapshots.Normalize(resp.Status().Code(), resp.Headers(), resp.Body().Bytes())
+	status, diff, err := r.snapshots.Check(name, actual)
+	if err != nil {
+		result.TestResults = append(result.TestResults, script.TestResult{Name: SnapshotTestName, Error: err.Error()})
+		return
+	}
+
+	result.Snapshot = status
+	switch status {
+	case SnapshotMatched:
+		result.TestResults = append(result.TestResults, script.TestResult{Name: SnapshotTestName, Passed: true})
+	case SnapshotMismatched:
+		result.TestResults = append(result.TestResults, script.TestResult{
+			Name:  SnapshotTestName,
+			Error: fmt.Sprintf("response differs from %s\n%s", name, strings.TrimRight(diff, "\n")),
+		})
+	}
+}
+
+// ContractTestResults converts contract checks into test results named
+// "Contract: <check>". Each violation is one line of the error, prefixed with
+// its JSON pointer into the response body.
+func ContractTestResults(checks []importer.ContractCheck) []script.TestResult {
+	results := make([]script.TestResult, 0, len(checks))
+	for _, check := range checks {
+		tr := script.TestResult{Name: "Contract: " + check.Name, Passed: check.Passed()}
+		lines := make([]string, 0, len(check.Violations))
+		for _, v := range check.Violations {
+			lines = append(lines, v.String())
+		}
+		tr.Error = strings.Join(lines, "\n")
+		results = append(results, tr)
+	}
+	return results
+}
+
+// headerMap flattens headers into a map, joining repeated values.
+func headerMap(headers *core.Headers) map[string]string {
+	result := make(map[string]string)
+	for _, key := range headers.Keys() {
+		result[key] = strings.Join(headers.GetAll(key), ", ")
+	}
 	return result
 }
 
+// recordBody returns the body as text for reports, truncated to
+// MaxRecordedBodySize. File bodies are described rather than read.
+func recordBody(body core.Body) string {
+	if body == nil || body.IsEmpty() {
+		return ""
+	}
+	if fb, ok := body.(interface{ Path() string }); ok {
+		return fmt.Sprintf("[file: %s, %d bytes]", fb.Path(), body.Size())
+	}
+	data := body.Bytes()
+	if len(data) > MaxRecordedBodySize {
+		return string(data[:MaxRecordedBodySize])
+	}
+	return string(data)
+}
+
 // IsSuccess returns true if the result had no errors.
 func (r *RunResult) IsSuccess() bool {
 	return r.Error == nil
@@ -290,6 +861,63 @@ func (r *RunResult) AllTestsPassed() bool {
 	return true
 }
 
+// addResult appends a result and updates the summary statistics.
+func (s *RunSummary) addResult(result RunResult) {
+	s.Results = append(s.Results, result)
+	if result.Skipped {
+		s.Skipped++
+		return
+	}
+	s.Executed++
+	s.Snapshots.add(result.Snapshot)
+
+	if result.Error == nil {
+		s.Passed++
+	} else {
+		s.Failed++
+	}
+
+	for _, tr := range result.TestResults {
+		s.TotalTests++
+		if tr.Passed {
+			s.TestsPassed++
+		} else {
+			s.TestsFailed++
+		}
+	}
+}
+
+// addResult appends a result and updates the iteration statistics.
+func (s *IterationSummary) addResult(result RunResult) {
+	s.Results = append(s.Results, result)
+	if result.Skipped {
+		s.Skipped++
+		return
+	}
+	s.Executed++
+
+	if result.Error == nil {
+		s.Passed++
+	} else {
+		s.Failed++
+	}
+
+	for _, tr := range result.TestResults {
+		s.TotalTests++
+		if tr.Passed {
+			s.TestsPassed++
+		} else {
+			s.TestsFailed++
+		}
+	}
+}
+
+// IsSuccess returns true if all requests in the iteration passed and all
+// of their tests passed.
+func (s *IterationSummary) IsSuccess() bool {
+	return s.Failed == 0 && s.TestsFailed == 0
+}
+
 // IsSuccess returns true if all requests passed.
 func (s *RunSummary) IsSuccess() bool {
 	return s.Failed == 0
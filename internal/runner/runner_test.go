@@ -3,12 +3,19 @@ package runner
 import (
 	"context"
 	"net/http"
+	"net/http/cookiejar"
 	"net/http/httptest"
 	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/artpar/currier/internal/core"
+	"github.com/artpar/currier/internal/importer"
 	"github.com/artpar/currier/internal/script"
 )
 
@@ -435,6 +442,129 @@ func TestRunner_WithEnvironment(t *testing.T) {
 	})
 }
 
+func TestRunner_VariableScopes(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.Write([]byte(`{"token":"t-1"}`))
+	}))
+	defer server.Close()
+
+	env := core.NewEnvironment("test")
+	env.SetVariable("host", server.URL)
+	env.SetVariable("version", "env")
+
+	coll := core.NewCollection("Scopes")
+	coll.SetVariable("version", "v1")
+	coll.SetVariable("section", "public")
+
+	login := core.NewRequestDefinition("Login", "GET", "{{host}}/{{version}}/{{section}}")
+	login.SetExtractions([]core.Extraction{{Variable: "token", Source: core.AssertionSourceJSONPath, Expression: "$.token"}})
+	coll.AddRequest(login)
+
+	admin := coll.AddFolder("Admin")
+	admin.SetVariable("section", "admin")
+	users := admin.AddFolder("Users")
+	users.SetVariable("version", "v2")
+	users.AddRequest(core.NewRequestDefinition("List", "GET", "{{host}}/{{version}}/{{section}}/{{token}}"))
+
+	summary := NewRunner(coll, WithEnvironment(env)).Run(context.Background())
+	for _, result := range summary.Results {
+		if result.Error != nil {
+			t.Fatalf("%s: unexpected error: %v", result.RequestName, result.Error)
+		}
+	}
+
+	// The environment beats the collection's default version; the folder's
+	// own version beats the environment
+	want := []string{"/env/public", "/v2/admin/t-1"}
+	if len(paths) != len(want) {
+		t.Fatalf("expected %d requests, got %v", len(want), paths)
+	}
+	for i := range want {
+		if paths[i] != want[i] {
+			t.Errorf("request %d: expected path %q, got %q", i, want[i], paths[i])
+		}
+	}
+}
+
+func TestRunner_WithSeed(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+	}))
+	defer server.Close()
+
+	coll := core.NewCollection("Seeded")
+	coll.AddRequest(core.NewRequestDefinition("Create", "GET", server.URL+"/{{$randomUUID}}/{{$randomInt 1 1000000}}"))
+
+	for i := 0; i < 2; i++ {
+		NewRunner(coll, WithSeed(42), WithIterations(2)).Run(context.Background())
+	}
+
+	if len(paths) != 4 {
+		t.Fatalf("expected 4 requests, got %v", paths)
+	}
+	if paths[0] == paths[1] {
+		t.Errorf("expected iterations to get different values, got %q twice", paths[0])
+	}
+	if paths[0] != paths[2] || paths[1] != paths[3] {
+		t.Errorf("expected seeded runs to repeat their values, got %v", paths)
+	}
+}
+
+func TestRunner_WithGlobals(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+	}))
+	defer server.Close()
+
+	coll := core.NewCollection("Globals")
+	coll.AddRequest(core.NewRequestDefinition("Get", "GET", server.URL+"/{{tenant}}/{{region}}"))
+
+	env := core.NewEnvironment("staging")
+	env.SetVariable("region", "eu")
+	globals := map[string]string{"tenant": "acme", "region": "us"}
+
+	NewRunner(coll, WithGlobals(globals), WithEnvironment(env)).Run(context.Background())
+
+	if len(paths) != 1 || paths[0] != "/acme/eu" {
+		t.Errorf("expected globals beneath the environment, got %v", paths)
+	}
+}
+
+func TestRunner_RedactsSecrets(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"echo":"` + r.Header.Get("X-Api-Key") + `"}`))
+	}))
+	defer server.Close()
+
+	coll := core.NewCollection("Secrets")
+	echo := core.NewRequestDefinition("Echo", "POST", server.URL+"/{{token}}")
+	echo.SetHeader("X-Api-Key", "{{token}}")
+	echo.SetBody(`{"token":"{{token}}","shared":"{{shared}}"}`)
+	coll.AddRequest(echo)
+	coll.AddRequest(core.NewRequestDefinition("Down", "GET", "http://127.0.0.1:1/{{token}}"))
+
+	env := core.NewEnvironment("ci")
+	env.SetSecret("token", "tok-12345")
+	summary := NewRunner(coll, WithEnvironment(env),
+		WithGlobals(map[string]string{"shared": "global-secret"}), WithSecrets("global-secret")).Run(context.Background())
+
+	sent := summary.Results[0]
+	if sent.URL != server.URL+"/********" || sent.RequestHeaders["X-Api-Key"] != "********" {
+		t.Errorf("expected the secret masked in the request, got %s %v", sent.URL, sent.RequestHeaders)
+	}
+	if sent.RequestBody != `{"token":"********","shared":"********"}` || sent.ResponseBody != `{"echo":"********"}` {
+		t.Errorf("expected the secrets masked in the bodies, got %s and %s", sent.RequestBody, sent.ResponseBody)
+	}
+	failed := summary.Results[1]
+	if failed.Error == nil || strings.Contains(failed.Error.Error(), "tok-12345") {
+		t.Errorf("expected the secret masked in the error, got %v", failed.Error)
+	}
+}
+
 // mockCookieJar implements http.CookieJar for testing
 type mockCookieJar struct {
 	cookies []*http.Cookie
@@ -651,3 +781,694 @@ func TestRunResult(t *testing.T) {
 		}
 	})
 }
+
+func TestRunner_Concurrency(t *testing.T) {
+	t.Run("runs requests in parallel and keeps collection order", func(t *testing.T) {
+		var inFlight, maxInFlight int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			n := atomic.AddInt32(&inFlight, 1)
+			defer atomic.AddInt32(&inFlight, -1)
+			for {
+				m := atomic.LoadInt32(&maxInFlight)
+				if n <= m || atomic.CompareAndSwapInt32(&maxInFlight, m, n) {
+					break
+				}
+			}
+			// Earlier requests finish last
+			switch r.URL.Path {
+			case "/1":
+				time.Sleep(60 * time.Millisecond)
+			case "/2":
+				time.Sleep(40 * time.Millisecond)
+			case "/3":
+				time.Sleep(20 * time.Millisecond)
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		coll := core.NewCollection("Parallel")
+		coll.AddRequest(core.NewRequestDefinition("Request 1", "GET", server.URL+"/1"))
+		folder := coll.AddFolder("Folder")
+		folder.AddRequest(core.NewRequestDefinition("Request 2", "GET", server.URL+"/2"))
+		folder.AddRequest(core.NewRequestDefinition("Request 3", "GET", server.URL+"/3"))
+		folder.AddRequest(core.NewRequestDefinition("Request 4", "GET", server.URL+"/4"))
+
+		var currents []int
+		runner := NewRunner(coll,
+			WithConcurrency(4),
+			WithProgressCallback(func(current, total int, result *RunResult) {
+				currents = append(currents, current)
+			}),
+		)
+		summary := runner.Run(context.Background())
+
+		if summary.Executed != 4 || summary.Passed != 4 {
+			t.Fatalf("expected 4 passed requests, got executed=%d passed=%d", summary.Executed, summary.Passed)
+		}
+		for i, result := range summary.Results {
+			expected := "Request " + string(rune('1'+i))
+			if result.RequestName != expected {
+				t.Errorf("result %d: expected %s, got %s", i, expected, result.RequestName)
+			}
+		}
+		if atomic.LoadInt32(&maxInFlight) < 2 {
+			t.Errorf("expected requests to overlap, max in flight was %d", maxInFlight)
+		}
+		if len(currents) != 4 {
+			t.Fatalf("expected 4 progress calls, got %d", len(currents))
+		}
+		for i, c := range currents {
+			if c != i+1 {
+				t.Errorf("expected progress current %d, got %d", i+1, c)
+			}
+		}
+	})
+
+	t.Run("runs sequential folders in order", func(t *testing.T) {
+		var mu sync.Mutex
+		var order []string
+		var seqInFlight, seqOverlap int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if strings.HasPrefix(r.URL.Path, "/flow/") {
+				if atomic.AddInt32(&seqInFlight, 1) > 1 {
+					atomic.StoreInt32(&seqOverlap, 1)
+				}
+				defer atomic.AddInt32(&seqInFlight, -1)
+				mu.Lock()
+				order = append(order, r.URL.Path)
+				mu.Unlock()
+			}
+			time.Sleep(10 * time.Millisecond)
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		coll := core.NewCollection("Flows")
+		flow := coll.AddFolder("Login flow")
+		flow.SetSequential(true)
+		flow.AddRequest(core.NewRequestDefinition("Login", "POST", server.URL+"/flow/1"))
+		flow.AddRequest(core.NewRequestDefinition("Profile", "GET", server.URL+"/flow/2"))
+		nested := flow.AddFolder("Nested")
+		nested.AddRequest(core.NewRequestDefinition("Logout", "POST", server.URL+"/flow/3"))
+		other := coll.AddFolder("Other")
+		for i := 0; i < 4; i++ {
+			other.AddRequest(core.NewRequestDefinition("Other", "GET", server.URL+"/other"))
+		}
+
+		runner := NewRunner(coll, WithConcurrency(8))
+		summary := runner.Run(context.Background())
+
+		if summary.Executed != 7 {
+			t.Fatalf("expected 7 executed, got %d", summary.Executed)
+		}
+		if atomic.LoadInt32(&seqOverlap) != 0 {
+			t.Error("expected sequential folder requests not to overlap")
+		}
+		if strings.Join(order, ",") != "/flow/1,/flow/2,/flow/3" {
+			t.Errorf("unexpected sequential order: %v", order)
+		}
+		if summary.Results[0].RequestName != "Login" || summary.Results[2].RequestName != "Logout" {
+			t.Errorf("expected results in collection order, got %s ... %s",
+				summary.Results[0].RequestName, summary.Results[2].RequestName)
+		}
+	})
+
+	t.Run("respects context cancellation", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(100 * time.Millisecond)
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		coll := core.NewCollection("Cancel")
+		for i := 0; i < 10; i++ {
+			coll.AddRequest(core.NewRequestDefinition("Request", "GET", server.URL))
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		runner := NewRunner(coll, WithConcurrency(2))
+		summary := runner.Run(ctx)
+
+		if summary.Executed >= 10 {
+			t.Errorf("expected cancellation to stop the run, executed %d", summary.Executed)
+		}
+	})
+}
+
+func TestRunner_workUnits(t *testing.T) {
+	coll := core.NewCollection("Units")
+	coll.AddRequest(core.NewRequestDefinition("A", "GET", "/a"))
+	seq := coll.AddFolder("Seq")
+	seq.SetSequential(true)
+	seq.AddRequest(core.NewRequestDefinition("B", "GET", "/b"))
+	seq.AddFolder("Sub").AddRequest(core.NewRequestDefinition("C", "GET", "/c"))
+	coll.AddFolder("Empty").SetSequential(true)
+	par := coll.AddFolder("Par")
+	par.AddRequest(core.NewRequestDefinition("D", "GET", "/d"))
+	par.AddRequest(core.NewRequestDefinition("E", "GET", "/e"))
+
+	runner := NewRunner(coll)
+	units := runner.workUnits(coll)
+
+	expected := [][]int{{0}, {1, 2}, {3}, {4}}
+	if len(units) != len(expected) {
+		t.Fatalf("expected %d units, got %v", len(expected), units)
+	}
+	for i := range expected {
+		if len(units[i]) != len(expected[i]) {
+			t.Fatalf("unit %d: expected %v, got %v", i, expected[i], units[i])
+		}
+		for j := range expected[i] {
+			if units[i][j] != expected[i][j] {
+				t.Errorf("unit %d: expected %v, got %v", i, expected[i], units[i])
+			}
+		}
+	}
+}
+
+func TestRunner_Iterations(t *testing.T) {
+	t.Run("runs once per data row with iteration variables", func(t *testing.T) {
+		var mu sync.Mutex
+		var paths []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			paths = append(paths, r.URL.Path)
+			mu.Unlock()
+			w.Write([]byte(r.URL.Path))
+		}))
+		defer server.Close()
+
+		coll := core.NewCollection("Data")
+		req := core.NewRequestDefinition("Get User", "GET", server.URL+"/users/{{user}}")
+		req.SetPostScript(`
+			currier.test("body matches row", function() {
+				currier.expect(currier.response.body).toBe("/users/" + currier.iterationData.get("user"));
+			});
+			currier.test("info", function() {
+				currier.expect(pm.info.iterationCount).toBe(2);
+			});
+		`)
+		coll.AddRequest(req)
+
+		env := core.NewEnvironment("env")
+		env.SetVariable("user", "default")
+
+		var currents, totals []int
+		runner := NewRunner(coll,
+			WithEnvironment(env),
+			WithIterationData([]map[string]string{{"user": "alice"}, {"user": "bob"}}),
+			WithProgressCallback(func(current, total int, result *RunResult) {
+				currents = append(currents, current)
+				totals = append(totals, total)
+			}),
+		)
+		summary := runner.Run(context.Background())
+
+		if summary.TotalRequests != 2 || summary.Executed != 2 {
+			t.Fatalf("expected 2 executed of 2, got %d of %d", summary.Executed, summary.TotalRequests)
+		}
+		if len(summary.Iterations) != 2 {
+			t.Fatalf("expected 2 iterations, got %d", len(summary.Iterations))
+		}
+		if strings.Join(paths, ",") != "/users/alice,/users/bob" {
+			t.Errorf("unexpected request paths: %v", paths)
+		}
+		for i, iter := range summary.Iterations {
+			if iter.Index != i || len(iter.Results) != 1 || iter.Results[0].Iteration != i {
+				t.Errorf("iteration %d has unexpected results: %+v", i, iter)
+			}
+			if !iter.IsSuccess() {
+				t.Errorf("iteration %d failed: %+v", i, iter.Results[0].TestResults)
+			}
+		}
+		if summary.Iterations[1].Data["user"] != "bob" {
+			t.Errorf("expected second iteration data to be bob, got %v", summary.Iterations[1].Data)
+		}
+		if len(currents) != 2 || currents[1] != 2 || totals[0] != 2 {
+			t.Errorf("unexpected progress: currents=%v totals=%v", currents, totals)
+		}
+	})
+
+	t.Run("cycles rows when iterations exceed data", func(t *testing.T) {
+		var paths []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			paths = append(paths, r.URL.Path)
+		}))
+		defer server.Close()
+
+		coll := core.NewCollection("Cycle")
+		coll.AddRequest(core.NewRequestDefinition("Get", "GET", server.URL+"/{{id}}"))
+
+		runner := NewRunner(coll,
+			WithIterations(3),
+			WithIterationData([]map[string]string{{"id": "1"}, {"id": "2"}}),
+		)
+		summary := runner.Run(context.Background())
+
+		if len(summary.Iterations) != 3 {
+			t.Fatalf("expected 3 iterations, got %d", len(summary.Iterations))
+		}
+		if strings.Join(paths, ",") != "/1,/2,/1" {
+			t.Errorf("unexpected request paths: %v", paths)
+		}
+	})
+
+	t.Run("repeats collection without data", func(t *testing.T) {
+		var count int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&count, 1)
+		}))
+		defer server.Close()
+
+		coll := core.NewCollection("Repeat")
+		coll.AddRequest(core.NewRequestDefinition("A", "GET", server.URL+"/a"))
+		coll.AddRequest(core.NewRequestDefinition("B", "GET", server.URL+"/b"))
+
+		summary := NewRunner(coll, WithIterations(2), WithConcurrency(2)).Run(context.Background())
+
+		if count != 4 || summary.TotalRequests != 4 || summary.Passed != 4 {
+			t.Errorf("expected 4 passed requests, got count=%d total=%d passed=%d", count, summary.TotalRequests, summary.Passed)
+		}
+		if len(summary.Iterations) != 2 || summary.Iterations[1].Results[0].RequestName != "A" {
+			t.Errorf("unexpected iterations: %+v", summary.Iterations)
+		}
+	})
+}
+
+func TestRunner_FlowControl(t *testing.T) {
+	newServer := func(paths *[]string) *httptest.Server {
+		var mu sync.Mutex
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			*paths = append(*paths, r.URL.Path)
+			mu.Unlock()
+		}))
+	}
+
+	t.Run("setNextRequest jumps to a named request", func(t *testing.T) {
+		var paths []string
+		server := newServer(&paths)
+		defer server.Close()
+
+		coll := core.NewCollection("Jump")
+		first := core.NewRequestDefinition("First", "GET", server.URL+"/first")
+		first.SetPostScript(`postman.setNextRequest("Third")`)
+		coll.AddRequest(first)
+		coll.AddRequest(core.NewRequestDefinition("Second", "GET", server.URL+"/second"))
+		coll.AddRequest(core.NewRequestDefinition("Third", "GET", server.URL+"/third"))
+
+		summary := NewRunner(coll).Run(context.Background())
+
+		if strings.Join(paths, ",") != "/first,/third" {
+			t.Errorf("unexpected request paths: %v", paths)
+		}
+		if summary.Executed != 2 || summary.Passed != 2 {
+			t.Errorf("expected 2 passed requests, got executed=%d passed=%d", summary.Executed, summary.Passed)
+		}
+	})
+
+	t.Run("setNextRequest(null) stops the run", func(t *testing.T) {
+		var paths []string
+		server := newServer(&paths)
+		defer server.Close()
+
+		coll := core.NewCollection("Stop")
+		first := core.NewRequestDefinition("First", "GET", server.URL+"/first")
+		first.SetPostScript(`pm.execution.setNextRequest(null)`)
+		coll.AddRequest(first)
+		coll.AddRequest(core.NewRequestDefinition("Second", "GET", server.URL+"/second"))
+
+		summary := NewRunner(coll, WithIterations(3)).Run(context.Background())
+
+		if len(paths) != 1 {
+			t.Errorf("expected one request before stop, got %v", paths)
+		}
+		if !summary.Stopped || len(summary.Iterations) != 1 {
+			t.Errorf("expected run to stop after first iteration, stopped=%v iterations=%d", summary.Stopped, len(summary.Iterations))
+		}
+	})
+
+	t.Run("loop protection ends the iteration", func(t *testing.T) {
+		var paths []string
+		server := newServer(&paths)
+		defer server.Close()
+
+		coll := core.NewCollection("Loop")
+		poll := core.NewRequestDefinition("Poll", "GET", server.URL+"/poll")
+		poll.SetPostScript(`currier.execution.setNextRequest("Poll")`)
+		coll.AddRequest(poll)
+		coll.AddRequest(core.NewRequestDefinition("After", "GET", server.URL+"/after"))
+
+		summary := NewRunner(coll, WithMaxRequestRuns(3)).Run(context.Background())
+
+		if len(paths) != 3 {
+			t.Errorf("expected 3 polls, got %v", paths)
+		}
+		if summary.Failed != 1 {
+			t.Fatalf("expected loop protection failure, got failed=%d", summary.Failed)
+		}
+		last := summary.Results[len(summary.Results)-1]
+		if last.Error == nil || !strings.Contains(last.Error.Error(), "loop protection") {
+			t.Errorf("expected loop protection error, got %v", last.Error)
+		}
+	})
+
+	t.Run("unknown setNextRequest target fails the request", func(t *testing.T) {
+		var paths []string
+		server := newServer(&paths)
+		defer server.Close()
+
+		coll := core.NewCollection("Unknown")
+		first := core.NewRequestDefinition("First", "GET", server.URL+"/first")
+		first.SetPreScript(`pm.execution.setNextRequest("Nope")`)
+		coll.AddRequest(first)
+		coll.AddRequest(core.NewRequestDefinition("Second", "GET", server.URL+"/second"))
+
+		summary := NewRunner(coll).Run(context.Background())
+
+		if len(paths) != 1 || summary.Failed != 1 {
+			t.Errorf("expected failure after first request, paths=%v failed=%d", paths, summary.Failed)
+		}
+	})
+
+	t.Run("skipRequest and skip condition report skipped requests", func(t *testing.T) {
+		var paths []string
+		server := newServer(&paths)
+		defer server.Close()
+
+		coll := core.NewCollection("Skip")
+		skipped := core.NewRequestDefinition("Scripted Skip", "GET", server.URL+"/scripted")
+		skipped.SetPreScript(`pm.execution.skipRequest()`)
+		skipped.SetPostScript(`pm.test("never runs", function() {})`)
+		coll.AddRequest(skipped)
+		conditional := core.NewRequestDefinition("Prod Only", "GET", server.URL+"/prod")
+		conditional.SetSkipCondition(`currier.environment.get("stage") !== "prod"`)
+		coll.AddRequest(conditional)
+		coll.AddRequest(core.NewRequestDefinition("Always", "GET", server.URL+"/always"))
+
+		env := core.NewEnvironment("dev")
+		env.SetVariable("stage", "dev")
+
+		summary := NewRunner(coll, WithEnvironment(env), WithConcurrency(2)).Run(context.Background())
+
+		if strings.Join(paths, ",") != "/always" {
+			t.Errorf("unexpected request paths: %v", paths)
+		}
+		if summary.Skipped != 2 || summary.Passed != 1 || summary.Executed != 1 || summary.TotalTests != 0 {
+			t.Errorf("unexpected summary: skipped=%d passed=%d executed=%d tests=%d",
+				summary.Skipped, summary.Passed, summary.Executed, summary.TotalTests)
+		}
+		if !summary.Results[0].Skipped || summary.Results[0].SkipReason == "" {
+			t.Errorf("expected first result to be skipped with a reason: %+v", summary.Results[0])
+		}
+		if summary.Iterations[0].Skipped != 2 {
+			t.Errorf("expected iteration to count 2 skipped, got %d", summary.Iterations[0].Skipped)
+		}
+	})
+
+	t.Run("invalid skip condition fails the request", func(t *testing.T) {
+		coll := core.NewCollection("Bad")
+		req := core.NewRequestDefinition("Bad", "GET", "http://127.0.0.1:1/")
+		req.SetSkipCondition(`this is not javascript`)
+		coll.AddRequest(req)
+
+		summary := NewRunner(coll).Run(context.Background())
+
+		if summary.Failed != 1 || !strings.Contains(summary.Results[0].Error.Error(), "skip condition") {
+			t.Errorf("expected skip condition error, got %+v", summary.Results)
+		}
+	})
+}
+
+func TestRunner_Contract(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/users/1":
+			w.Write([]byte(`{"id": 1, "name": "Ada"}`))
+		case "/users/2":
+			w.Write([]byte(`{"id": "2"}`))
+		default:
+			w.WriteHeader(http.StatusTeapot)
+		}
+	}))
+	defer server.Close()
+
+	contract, err := importer.ParseContract([]byte(`{
+		"openapi": "3.0.0",
+		"info": {"title": "Users", "version": "1"},
+		"paths": {"/users/{id}": {"get": {"responses": {"200": {
+			"description": "A user",
+			"content": {"application/json": {"schema": {
+				"type": "object",
+				"required": ["id", "name"],
+				"properties": {"id": {"type": "integer"}, "name": {"type": "string"}}
+			}}}
+		}}}}}
+	}`))
+	if err != nil {
+		t.Fatalf("ParseContract: %v", err)
+	}
+
+	coll := core.NewCollection("Users")
+	coll.AddRequest(core.NewRequestDefinition("Valid", "GET", server.URL+"/users/1"))
+	coll.AddRequest(core.NewRequestDefinition("Invalid", "GET", server.URL+"/users/2"))
+	coll.AddRequest(core.NewRequestDefinition("Teapot", "GET", server.URL+"/users/3"))
+
+	summary := NewRunner(coll, WithContract(contract)).Run(context.Background())
+
+	valid := summary.Results[0]
+	if len(valid.TestResults) != 3 || !valid.AllTestsPassed() {
+		t.Errorf("expected 3 passing contract tests, got %+v", valid.TestResults)
+	}
+
+	invalid := summary.Results[1].TestResults
+	if len(invalid) != 3 {
+		t.Fatalf("expected 3 contract tests, got %+v", invalid)
+	}
+	body := invalid[2]
+	if body.Name != "Contract: Body matches schema" || body.Passed {
+		t.Errorf("unexpected body test %+v", body)
+	}
+	want := "(root): missing required property \"name\"\n/id: expected integer, got string"
+	if body.Error != want {
+		t.Errorf("got error %q, want %q", body.Error, want)
+	}
+
+	teapot := summary.Results[2].TestResults
+	if len(teapot) != 1 || teapot[0].Passed || !strings.Contains(teapot[0].Error, "status 418 is not declared") {
+		t.Errorf("unexpected status test %+v", teapot)
+	}
+
+	if summary.TestsFailed != 2 {
+		t.Errorf("expected 2 failed tests, got %d", summary.TestsFailed)
+	}
+}
+
+func TestRunner_AssertionsAndExtraction(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/login":
+			w.Write([]byte(`{"token": "t-42"}`))
+		case "/me":
+			if r.Header.Get("Authorization") != "Bearer t-42" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte(`{"name": "Ada"}`))
+		}
+	}))
+	defer server.Close()
+
+	login := core.NewRequestDefinition("Login", "POST", server.URL+"/login")
+	login.SetExtractions([]core.Extraction{{Variable: "token", Source: core.AssertionSourceJSONPath, Expression: "$.token"}})
+	login.SetAssertions([]core.Assertion{{Source: core.AssertionSourceStatus, Operator: core.AssertionEquals, Value: "200"}})
+
+	me := core.NewRequestDefinition("Me", "GET", server.URL+"/me")
+	me.SetHeader("Authorization", "Bearer {{token}}")
+	me.SetPostScript(`currier.test("script sees token", function() { currier.expect(currier.getVariable("token")).toBe("t-42"); });`)
+	me.SetAssertions([]core.Assertion{
+		{Source: core.AssertionSourceJSONPath, Property: "$.name", Operator: core.AssertionEquals, Value: "Ada"},
+		{Source: core.AssertionSourceHeader, Property: "X-Missing", Operator: core.AssertionExists},
+	})
+
+	coll := core.NewCollection("Auth")
+	coll.AddRequest(login)
+	coll.AddRequest(me)
+
+	summary := NewRunner(coll).Run(context.Background())
+
+	first := summary.Results[0].TestResults
+	if len(first) != 2 || !first[0].Passed || first[1].Name != "extract token jsonpath $.token" || !first[1].Passed {
+		t.Errorf("unexpected login tests %+v", first)
+	}
+
+	second := summary.Results[1]
+	if second.Status != http.StatusOK {
+		t.Fatalf("expected the extracted token to authorize /me, got status %d", second.Status)
+	}
+	if len(second.TestResults) != 3 {
+		t.Fatalf("expected 3 tests, got %+v", second.TestResults)
+	}
+	if !second.TestResults[0].Passed || !second.TestResults[1].Passed {
+		t.Errorf("expected script and jsonpath tests to pass, got %+v", second.TestResults)
+	}
+	if got := second.TestResults[2]; got.Passed || got.Error != "header X-Missing is missing" {
+		t.Errorf("unexpected header test %+v", got)
+	}
+	if summary.TestsFailed != 1 {
+		t.Errorf("expected 1 failed test, got %d", summary.TestsFailed)
+	}
+}
+
+func TestRunner_RequireModules(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"items": [1, 2, 3]}`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	helpers := `exports.total = function(items) { return require('lodash').sum(items); };`
+	if err := os.MkdirAll(filepath.Join(dir, "lib"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "lib", "helpers.js"), []byte(helpers), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	req := core.NewRequestDefinition("Items", "GET", server.URL)
+	req.SetPostScript(`
+		var helpers = require('./lib/helpers');
+		currier.test("total is 6", function() {
+			currier.expect(helpers.total(currier.response.json().items)).toBe(6);
+		});
+	`)
+	coll := core.NewCollection("Modules")
+	coll.AddRequest(req)
+
+	summary := NewRunner(coll, WithModuleDir(dir)).Run(context.Background())
+	if summary.Results[0].Error != nil {
+		t.Fatalf("unexpected error: %v", summary.Results[0].Error)
+	}
+	if tests := summary.Results[0].TestResults; len(tests) != 1 || !tests[0].Passed {
+		t.Errorf("expected the helper test to pass, got %+v", tests)
+	}
+
+	// Without a module directory only built-in modules load
+	summary = NewRunner(coll).Run(context.Background())
+	if err := summary.Results[0].Error; err == nil || !strings.Contains(err.Error(), "local modules need a collection directory") {
+		t.Errorf("expected a module error, got %v", err)
+	}
+}
+
+func TestRunner_AsyncScripts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/token" {
+			w.Write([]byte(`{"token": "` + r.Header.Get("X-Client") + `-token"}`))
+			return
+		}
+		w.Write([]byte(`{"ok": true}`))
+	}))
+	defer server.Close()
+
+	req := core.NewRequestDefinition("Async", "GET", server.URL+"/items")
+	req.SetPreScript(`
+		currier.sendRequest({url: "` + server.URL + `/token", header: {"X-Client": "cli"}}, function(err, res) {
+			currier.setVariable("token", res.json().token);
+		});
+	`)
+	req.SetPostScript(`
+		currier.test("token from pre-request", function() {
+			currier.expect(currier.getVariable("token")).toBe("cli-token");
+		});
+		currier.test("awaited request", async function() {
+			var res = await currier.sendRequest("` + server.URL + `/token");
+			currier.expect(res.code).toBe(200);
+		});
+		setTimeout(function() {
+			currier.test("timer test", function() {
+				currier.expect(currier.response.json().ok).toBe(true);
+			});
+		}, 10);
+	`)
+	coll := core.NewCollection("Async")
+	coll.AddRequest(req)
+
+	summary := NewRunner(coll).Run(context.Background())
+	if summary.Results[0].Error != nil {
+		t.Fatalf("unexpected error: %v", summary.Results[0].Error)
+	}
+	tests := summary.Results[0].TestResults
+	if len(tests) != 3 {
+		t.Fatalf("expected 3 tests, got %+v", tests)
+	}
+	for _, test := range tests {
+		if !test.Passed {
+			t.Errorf("expected %q to pass: %s", test.Name, test.Error)
+		}
+	}
+}
+
+func TestRunner_ScriptCookies(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			http.SetCookie(w, &http.Cookie{Name: "csrf", Value: "tok-1", Path: "/"})
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "s-1", Path: "/", HttpOnly: true})
+		case "/transfer":
+			session, _ := r.Cookie("session")
+			flag, _ := r.Cookie("flag")
+			if session == nil || r.Header.Get("X-CSRF-Token") != "tok-1" || flag == nil {
+				w.WriteHeader(http.StatusForbidden)
+			}
+		}
+	}))
+	defer server.Close()
+
+	login := core.NewRequestDefinition("Login", "POST", server.URL+"/login")
+	login.SetPostScript(`
+		currier.test("session cookie set", function() {
+			currier.expect(currier.cookies.has("session")).toBe(true);
+		});
+		var jar = currier.cookies.jar();
+		jar.set("` + server.URL + `", "flag", "on");
+		currier.sendRequest({
+			url: "` + server.URL + `/transfer",
+			method: "POST",
+			header: {"X-CSRF-Token": jar.get("` + server.URL + `", "csrf")}
+		}, function(err, res) {
+			currier.test("transfer accepted", function() {
+				currier.expect(res.code).toBe(200);
+			});
+		});
+	`)
+	coll := core.NewCollection("Cookies")
+	coll.AddRequest(login)
+
+	jar, _ := cookiejar.New(nil)
+	summary := NewRunner(coll, WithCookieJar(jar)).Run(context.Background())
+	result := summary.Results[0]
+	if result.Error != nil {
+		t.Fatalf("unexpected error: %v", result.Error)
+	}
+	if len(result.TestResults) != 2 {
+		t.Fatalf("expected 2 tests, got %+v", result.TestResults)
+	}
+	for _, test := range result.TestResults {
+		if !test.Passed {
+			t.Errorf("expected %q to pass: %s", test.Name, test.Error)
+		}
+	}
+
+	// Cookies stay in the run's jar
+	u, _ := url.Parse(server.URL)
+	if got := len(jar.Cookies(u)); got != 3 {
+		t.Errorf("expected 3 cookies in the run's jar, got %d", got)
+	}
+}
@@ -0,0 +1,113 @@
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/artpar/currier/internal/core"
+	httpclient "github.com/artpar/currier/internal/protocol/http"
+	"github.com/artpar/currier/internal/script"
+)
+
+// ScriptRequestSender returns the sender behind currier.sendRequest, sending
+// requests through client until ctx ends. Options follow Postman's: url,
+// method, header (an object or a list of {key, value}) and body (a string,
+// or {mode: "raw", raw} or {mode: "urlencoded", urlencoded: [{key, value}]}).
+func ScriptRequestSender(ctx context.Context, client *httpclient.Client) script.RequestSender {
+	return func(options map[string]interface{}) (map[string]interface{}, error) {
+		req, err := scriptRequest(options)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := client.Send(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return scriptResponse(resp), nil
+	}
+}
+
+// scriptRequest builds a request from sendRequest options.
+func scriptRequest(options map[string]interface{}) (*core.Request, error) {
+	url, _ := options["url"].(string)
+	method, _ := options["method"].(string)
+	if method == "" {
+		method = "GET"
+	}
+	req, err := core.NewRequest("http", strings.ToUpper(method), url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+
+	switch headers := options["header"].(type) {
+	case map[string]interface{}:
+		for k, v := range headers {
+			req.SetHeader(k, fmt.Sprint(v))
+		}
+	case []interface{}:
+		for _, h := range headers {
+			if pair, ok := h.(map[string]interface{}); ok {
+				req.SetHeader(fmt.Sprint(pair["key"]), fmt.Sprint(pair["value"]))
+			}
+		}
+	}
+
+	contentType := req.Headers().Get("Content-Type")
+	switch body := options["body"].(type) {
+	case nil:
+	case string:
+		req.SetBody(core.NewRawBody([]byte(body), contentType))
+	case map[string]interface{}:
+		switch mode, _ := body["mode"].(string); mode {
+		case "", "raw":
+			raw, _ := body["raw"].(string)
+			req.SetBody(core.NewRawBody([]byte(raw), contentType))
+		case "urlencoded":
+			var fields []core.FormField
+			list, _ := body["urlencoded"].([]interface{})
+			for _, f := range list {
+				if pair, ok := f.(map[string]interface{}); ok {
+					fields = append(fields, core.FormField{Key: fmt.Sprint(pair["key"]), Value: fmt.Sprint(pair["value"])})
+				}
+			}
+			req.SetBody(core.NewURLEncodedBody(fields))
+		default:
+			return nil, fmt.Errorf("unsupported body mode %q (use raw or urlencoded)", mode)
+		}
+	default:
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("invalid body: %w", err)
+		}
+		req.SetBody(core.NewRawBody(data, "application/json"))
+	}
+	return req, nil
+}
+
+// scriptResponse converts a response to the object scripts receive.
+func scriptResponse(resp *core.Response) map[string]interface{} {
+	headers := make(map[string]interface{})
+	for _, key := range resp.Headers().Keys() {
+		headers[key] = resp.Headers().Get(key)
+	}
+	body := resp.Body().String()
+	return map[string]interface{}{
+		"code":         resp.Status().Code(),
+		"status":       resp.Status().Text(),
+		"headers":      headers,
+		"body":         body,
+		"responseTime": resp.Timing().Total.Milliseconds(),
+		"text": func() string {
+			return body
+		},
+		"json": func() (interface{}, error) {
+			var v interface{}
+			if err := json.Unmarshal([]byte(body), &v); err != nil {
+				return nil, fmt.Errorf("response is not valid JSON: %w", err)
+			}
+			return v, nil
+		},
+	}
+}
@@ -0,0 +1,305 @@
+package runner
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/artpar/currier/internal/core"
+	"github.com/artpar/currier/internal/jsonpath"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// SnapshotExt is the file extension of response snapshots.
+const SnapshotExt = ".snap"
+
+// SnapshotTestName is the name of the test result added for each snapshot
+// comparison.
+const SnapshotTestName = "Response matches snapshot"
+
+// ignoredValue replaces JSON values matched by an ignore path.
+const ignoredValue = "<ignored>"
+
+// DefaultSnapshotHeaders are the response headers recorded when no headers
+// are configured.
+var DefaultSnapshotHeaders = []string{"Content-Type"}
+
+// SnapshotStatus is the outcome of comparing a response with its snapshot.
+type SnapshotStatus string
+
+const (
+	SnapshotRecorded   SnapshotStatus = "recorded"   // No snapshot existed; the response was saved
+	SnapshotMatched    SnapshotStatus = "matched"    // The response matched the snapshot
+	SnapshotUpdated    SnapshotStatus = "updated"    // The snapshot differed and was rewritten
+	SnapshotMismatched SnapshotStatus = "mismatched" // The response differed from the snapshot
+)
+
+// SnapshotStats counts snapshot outcomes in a run.
+type SnapshotStats struct {
+	Recorded   int
+	Matched    int
+	Updated    int
+	Mismatched int
+}
+
+// Total returns the number of snapshots checked.
+func (s SnapshotStats) Total() int {
+	return s.Recorded + s.Matched + s.Updated + s.Mismatched
+}
+
+func (s *SnapshotStats) add(status SnapshotStatus) {
+	switch status {
+	case SnapshotRecorded:
+		s.Recorded++
+	case SnapshotMatched:
+		s.Matched++
+	case SnapshotUpdated:
+		s.Updated++
+	case SnapshotMismatched:
+		s.Mismatched++
+	}
+}
+
+// SnapshotConfig configures response snapshot testing.
+type SnapshotConfig struct {
+	Dir     string   // Directory holding the snapshot files
+	Update  bool     // Rewrite snapshots that differ instead of failing
+	Headers []string // Response headers recorded in snapshots (default Content-Type)
+	Ignore  []string // JSON paths masked before comparing, e.g. $.id or $..createdAt
+}
+
+// SnapshotStore records normalized responses as golden files and compares
+// later responses against them. Snapshots are plain text: the status line,
+// the selected headers and the body, with JSON bodies pretty-printed with
+// sorted keys so diffs stay readable and stable.
+type SnapshotStore struct {
+	dir     string
+	update  bool
+	headers []string
+	ignore  []*jsonpath.Path
+}
+
+// NewSnapshotStore creates a snapshot store. It fails when an ignore path is
+// not valid.
+func NewSnapshotStore(cfg SnapshotConfig) (*SnapshotStore, error) {
+	if cfg.Dir == "" {
+		return nil, fmt.Errorf("snapshot directory is required")
+	}
+
+	s := &SnapshotStore{dir: cfg.Dir, update: cfg.Update}
+
+	s.headers = cfg.Headers
+	if len(s.headers) == 0 {
+		s.headers = DefaultSnapshotHeaders
+	}
+	s.headers = append([]string(nil), s.headers...)
+	sort.Slice(s.headers, func(i, j int) bool {
+		return strings.ToLower(s.headers[i]) < strings.ToLower(s.headers[j])
+	})
+
+	for _, p := range cfg.Ignore {
+		path, err := parseJSONPath(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid snapshot ignore path %q: %w", p, err)
+		}
+		s.ignore = append(s.ignore, path)
+	}
+
+	return s, nil
+}
+
+// Dir returns the directory holding the snapshot files.
+func (s *SnapshotStore) Dir() string {
+	return s.dir
+}
+
+// Normalize renders a response in snapshot form.
+func (s *SnapshotStore) Normalize(status int, headers *core.Headers, body []byte) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "HTTP %d\n", status)
+	for _, name := range s.headers {
+		if headers == nil {
+			break
+		}
+		if values := headers.GetAll(name); len(values) > 0 {
+			fmt.Fprintf(&b, "%s: %s\n", name, strings.Join(values, ", "))
+		}
+	}
+	b.WriteString("\n")
+	b.WriteString(s.normalizeBody(body))
+	return b.String()
+}
+
+// normalizeBody pretty-prints JSON bodies with ignored paths masked. Other
+// text is kept as is; binary bodies are reduced to their size and hash.
+func (s *SnapshotStore) normalizeBody(body []byte) string {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 {
+		return ""
+	}
+
+	if trimmed[0] == '{' || trimmed[0] == '[' {
+		dec := json.NewDecoder(bytes.NewReader(trimmed))
+		dec.UseNumber()
+		var value any
+		if err := dec.Decode(&value); err == nil && !dec.More() {
+			for _, path := range s.ignore {
+				value = maskPath(value, path)
+			}
+			var buf bytes.Buffer
+			enc := json.NewEncoder(&buf)
+			enc.SetEscapeHTML(false)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(value); err == nil {
+				return buf.String()
+			}
+		}
+	}
+
+	if !utf8.Valid(body) {
+		return fmt.Sprintf("<binary %d bytes, sha256 %x>\n", len(body), sha256.Sum256(body))
+	}
+	text := strings.ReplaceAll(string(body), "\r\n", "\n")
+	return strings.TrimRight(text, "\n") + "\n"
+}
+
+// Check compares a normalized response with the snapshot stored under name,
+// a slash-separated path relative to the snapshot directory. Missing
+// snapshots are recorded. On a mismatch it returns a unified diff from the
+// snapshot to the response, unless the store updates snapshots.
+func (s *SnapshotStore) Check(name, actual string) (SnapshotStatus, string, error) {
+	file := s.Path(name)
+
+	existing, err := os.ReadFile(file)
+	if errors.Is(err, fs.ErrNotExist) {
+		if err := s.write(file, actual); err != nil {
+			return "", "", err
+		}
+		return SnapshotRecorded, "", nil
+	}
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read snapshot: %w", err)
+	}
+
+	expected := strings.ReplaceAll(string(existing), "\r\n", "\n")
+	if expected == actual {
+		return SnapshotMatched, "", nil
+	}
+
+	if s.update {
+		if err := s.write(file, actual); err != nil {
+			return "", "", err
+		}
+		return SnapshotUpdated, "", nil
+	}
+
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(expected),
+		B:        difflib.SplitLines(actual),
+		FromFile: "snapshot",
+		ToFile:   "response",
+		Context:  3,
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("failed to diff snapshot: %w", err)
+	}
+	return SnapshotMismatched, diff, nil
+}
+
+// Path returns the file path of the snapshot stored under name.
+func (s *SnapshotStore) Path(name string) string {
+	return filepath.Join(s.dir, filepath.FromSlash(name))
+}
+
+func (s *SnapshotStore) write(file, content string) error {
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+	if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	return nil
+}
+
+// snapshotNames assigns each request of a collection a snapshot name that
+// mirrors its folder path. Requests with the same name in the same folder
+// get a numeric suffix in collection order.
+func snapshotNames(coll *core.Collection) map[*core.RequestDefinition]string {
+	names := make(map[*core.RequestDefinition]string)
+	used := make(map[string]bool)
+
+	var walk func(dir string, requests []*core.RequestDefinition, folders []*core.Folder)
+	walk = func(dir string, requests []*core.RequestDefinition, folders []*core.Folder) {
+		for _, req := range requests {
+			base := path.Join(dir, snapshotFileName(req.Name()))
+			name := base
+			for n := 2; used[strings.ToLower(name)]; n++ {
+				name = base + "-" + strconv.Itoa(n)
+			}
+			used[strings.ToLower(name)] = true
+			names[req] = name
+		}
+		for _, folder := range folders {
+			walk(path.Join(dir, snapshotFileName(folder.Name())), folder.Requests(), folder.Folders())
+		}
+	}
+	walk("", coll.Requests(), coll.Folders())
+
+	return names
+}
+
+// snapshotFileName turns a request or folder name into a file name, replacing
+// runs of characters other than letters, digits, '-', '_' and '.' with '-'.
+func snapshotFileName(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range name {
+		if r == '-' || r == '_' || r == '.' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' {
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		if !dash {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	result := strings.Trim(b.String(), "-.")
+	if result == "" {
+		return "request"
+	}
+	return result
+}
+
+// parseJSONPath parses an ignore rule. Rules use the jsonpath package
+// syntax, and a path without a leading $ is shorthand for a key at any
+// depth, so "id" is $..id.
+func parseJSONPath(p string) (*jsonpath.Path, error) {
+	p = strings.TrimSpace(p)
+	if p != "" && !strings.HasPrefix(p, "$") {
+		p = "$.." + p
+	}
+	path, err := jsonpath.Parse(p)
+	if err != nil {
+		return nil, err
+	}
+	if path.IsRoot() {
+		return nil, fmt.Errorf("path must not match the whole body")
+	}
+	return path, nil
+}
+
+// maskPath replaces every value matched by path with ignoredValue.
+func maskPath(value any, path *jsonpath.Path) any {
+	return path.Replace(value, func(any) any { return ignoredValue })
+}
@@ -0,0 +1,240 @@
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/artpar/currier/internal/core"
+)
+
+func TestRunner_Snapshots(t *testing.T) {
+	var calls int32
+	var name atomic.Value
+	name.Store("Ada")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&calls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Date", fmt.Sprintf("call %d", n))
+		fmt.Fprintf(w, `{"id":%d,"name":%q,"meta":{"createdAt":"t%d"},"tags":["a","b"]}`, n, name.Load(), n)
+	}))
+	defer server.Close()
+
+	coll := core.NewCollection("Snapshots")
+	coll.AddRequest(core.NewRequestDefinition("Get user", "GET", server.URL+"/user"))
+	folder := coll.AddFolder("Admin")
+	folder.AddRequest(core.NewRequestDefinition("Get user", "GET", server.URL+"/admin"))
+
+	dir := t.TempDir()
+	run := func(update bool) *RunSummary {
+		t.Helper()
+		store, err := NewSnapshotStore(SnapshotConfig{Dir: dir, Update: update, Ignore: []string{"$.id", "createdAt"}})
+		if err != nil {
+			t.Fatalf("NewSnapshotStore: %v", err)
+		}
+		return NewRunner(coll, WithSnapshots(store)).Run(context.Background())
+	}
+
+	t.Run("records missing snapshots", func(t *testing.T) {
+		summary := run(false)
+
+		if summary.Snapshots.Recorded != 2 {
+			t.Fatalf("expected 2 recorded snapshots, got %+v", summary.Snapshots)
+		}
+		if summary.TotalTests != 0 {
+			t.Errorf("recording should not add tests, got %d", summary.TotalTests)
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, "Get-user.snap"))
+		if err != nil {
+			t.Fatalf("snapshot not written: %v", err)
+		}
+		want := "HTTP 200\nContent-Type: application/json\n\n{\n  \"id\": \"<ignored>\",\n  \"meta\": {\n    \"createdAt\": \"<ignored>\"\n  },\n  \"name\": \"Ada\",\n  \"tags\": [\n    \"a\",\n    \"b\"\n  ]\n}\n"
+		if string(data) != want {
+			t.Errorf("unexpected snapshot:\n%s", data)
+		}
+		if _, err := os.Stat(filepath.Join(dir, "Admin", "Get-user.snap")); err != nil {
+			t.Errorf("expected folder snapshot: %v", err)
+		}
+		if summary.Results[0].SnapshotFile != filepath.Join(dir, "Get-user.snap") {
+			t.Errorf("unexpected snapshot file %q", summary.Results[0].SnapshotFile)
+		}
+	})
+
+	t.Run("matches despite ignored fields", func(t *testing.T) {
+		summary := run(false)
+
+		if summary.Snapshots.Matched != 2 {
+			t.Fatalf("expected 2 matched snapshots, got %+v", summary.Snapshots)
+		}
+		if summary.TestsPassed != 2 || summary.TestsFailed != 0 {
+			t.Errorf("expected 2 passing snapshot tests, got %d passed %d failed", summary.TestsPassed, summary.TestsFailed)
+		}
+	})
+
+	t.Run("reports a diff on mismatch", func(t *testing.T) {
+		name.Store("Grace")
+		summary := run(false)
+
+		if summary.Snapshots.Mismatched != 2 {
+			t.Fatalf("expected 2 mismatches, got %+v", summary.Snapshots)
+		}
+		if summary.AllTestsPassed() {
+			t.Fatal("expected snapshot tests to fail")
+		}
+
+		result := summary.Results[0]
+		if result.Snapshot != SnapshotMismatched {
+			t.Errorf("expected mismatched status, got %q", result.Snapshot)
+		}
+		tr := result.TestResults[0]
+		if tr.Name != SnapshotTestName || tr.Passed {
+			t.Errorf("unexpected test result %+v", tr)
+		}
+		for _, want := range []string{"--- snapshot", "+++ response", `-  "name": "Ada",`, `+  "name": "Grace",`} {
+			if !strings.Contains(tr.Error, want) {
+				t.Errorf("diff missing %q:\n%s", want, tr.Error)
+			}
+		}
+		if strings.Contains(tr.Error, "createdAt\": \"t") {
+			t.Errorf("ignored field leaked into diff:\n%s", tr.Error)
+		}
+	})
+
+	t.Run("updates snapshots", func(t *testing.T) {
+		summary := run(true)
+		if summary.Snapshots.Updated != 2 || summary.TestsFailed != 0 {
+			t.Fatalf("expected 2 updated snapshots, got %+v", summary.Snapshots)
+		}
+
+		summary = run(false)
+		if summary.Snapshots.Matched != 2 {
+			t.Errorf("expected updated snapshots to match, got %+v", summary.Snapshots)
+		}
+	})
+
+	t.Run("keeps one snapshot per iteration", func(t *testing.T) {
+		iterDir := t.TempDir()
+		store, err := NewSnapshotStore(SnapshotConfig{Dir: iterDir})
+		if err != nil {
+			t.Fatal(err)
+		}
+		single := core.NewCollection("Iterations")
+		single.AddRequest(core.NewRequestDefinition("Get user", "GET", server.URL))
+		NewRunner(single, WithSnapshots(store), WithIterations(2)).Run(context.Background())
+
+		for _, file := range []string{"Get-user.iteration-1.snap", "Get-user.iteration-2.snap"} {
+			if _, err := os.Stat(filepath.Join(iterDir, file)); err != nil {
+				t.Errorf("expected %s: %v", file, err)
+			}
+		}
+	})
+}
+
+func TestSnapshotStore_Normalize(t *testing.T) {
+	store, err := NewSnapshotStore(SnapshotConfig{Dir: t.TempDir(), Headers: []string{"X-Version", "content-type"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	headers := core.NewHeaders()
+	headers.Set("Content-Type", "text/plain")
+	headers.Set("X-Version", "2")
+	headers.Set("Date", "today")
+
+	t.Run("selected headers and text body", func(t *testing.T) {
+		got := store.Normalize(201, headers, []byte("hello\r\nworld\n\n"))
+		want := "HTTP 201\ncontent-type: text/plain\nX-Version: 2\n\nhello\nworld\n"
+		if got != want {
+			t.Errorf("got %q, want %q", got, want)
+		}
+	})
+
+	t.Run("keeps number precision and HTML", func(t *testing.T) {
+		got := store.Normalize(200, nil, []byte(`{"big":12345678901234567890,"html":"<b>"}`))
+		if !strings.Contains(got, `"big": 12345678901234567890`) || !strings.Contains(got, `"html": "<b>"`) {
+			t.Errorf("unexpected body %q", got)
+		}
+	})
+
+	t.Run("binary body", func(t *testing.T) {
+		got := store.Normalize(200, nil, []byte{0xff, 0xfe, 0x00})
+		if !strings.Contains(got, "<binary 3 bytes, sha256 ") {
+			t.Errorf("unexpected body %q", got)
+		}
+	})
+}
+
+func TestMaskPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"$.id", `{"id":"<ignored>","items":[{"id":1,"at":"x"},{"id":2,"at":"y"}],"meta":{"id":3}}`},
+		{"$.items[1].at", `{"id":0,"items":[{"id":1,"at":"x"},{"id":2,"at":"<ignored>"}],"meta":{"id":3}}`},
+		{"$.items[*].id", `{"id":0,"items":[{"id":"<ignored>","at":"x"},{"id":"<ignored>","at":"y"}],"meta":{"id":3}}`},
+		{"$..id", `{"id":"<ignored>","items":[{"id":"<ignored>","at":"x"},{"id":"<ignored>","at":"y"}],"meta":{"id":"<ignored>"}}`},
+		{"id", `{"id":"<ignored>","items":[{"id":"<ignored>","at":"x"},{"id":"<ignored>","at":"y"}],"meta":{"id":"<ignored>"}}`},
+		{"$['meta'].*", `{"id":0,"items":[{"id":1,"at":"x"},{"id":2,"at":"y"}],"meta":{"id":"<ignored>"}}`},
+		{"$.missing.id", `{"id":0,"items":[{"id":1,"at":"x"},{"id":2,"at":"y"}],"meta":{"id":3}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			segments, err := parseJSONPath(tt.path)
+			if err != nil {
+				t.Fatalf("parseJSONPath: %v", err)
+			}
+			var value, want any
+			json.Unmarshal([]byte(`{"id":0,"items":[{"id":1,"at":"x"},{"id":2,"at":"y"}],"meta":{"id":3}}`), &value)
+			json.Unmarshal([]byte(tt.want), &want)
+
+			got, _ := json.Marshal(maskPath(value, segments))
+			expected, _ := json.Marshal(want)
+			if string(got) != string(expected) {
+				t.Errorf("got %s, want %s", got, expected)
+			}
+		})
+	}
+}
+
+func TestParseJSONPath_Invalid(t *testing.T) {
+	for _, path := range []string{"", "$", "$.", "$.items[", "$.items[x]", "$x"} {
+		if _, err := parseJSONPath(path); err == nil {
+			t.Errorf("expected %q to be rejected", path)
+		}
+	}
+
+	if _, err := NewSnapshotStore(SnapshotConfig{Dir: "snaps", Ignore: []string{"$.items["}}); err == nil {
+		t.Error("expected NewSnapshotStore to reject an invalid ignore path")
+	}
+	if _, err := NewSnapshotStore(SnapshotConfig{}); err == nil {
+		t.Error("expected NewSnapshotStore to require a directory")
+	}
+}
+
+func TestSnapshotNames(t *testing.T) {
+	coll := core.NewCollection("Names")
+	a := core.NewRequestDefinition("List users", "GET", "/users")
+	b := core.NewRequestDefinition("List users", "GET", "/users?page=2")
+	coll.AddRequest(a)
+	coll.AddRequest(b)
+	folder := coll.AddFolder("Auth / Tokens")
+	c := core.NewRequestDefinition("Refresh: token?", "POST", "/refresh")
+	folder.AddRequest(c)
+
+	names := snapshotNames(coll)
+
+	if names[a] != "List-users" || names[b] != "List-users-2" {
+		t.Errorf("unexpected duplicate names %q, %q", names[a], names[b])
+	}
+	if names[c] != "Auth-Tokens/Refresh-token" {
+		t.Errorf("unexpected folder name %q", names[c])
+	}
+}
@@ -47,6 +47,7 @@ func (s *ScopeWithAssertions) initAssertions() {
 	// Register the test function (only needs to be done once, but is idempotent)
 	if !s.initialized {
 		s.engine.RegisterFunction("__currier_test", s.testFunc())
+		s.engine.RegisterFunction("__currier_validate_schema", validateSchema)
 		s.initialized = true
 	}
 
@@ -57,7 +58,14 @@ func (s *ScopeWithAssertions) initAssertions() {
 			if (typeof assertion === 'function') {
 				try {
 					var result = assertion();
-					if (result === false) {
+					// Async tests are recorded when their promise settles
+					if (result && typeof result.then === 'function') {
+						result.then(function(value) {
+							__currier_test(name, value !== false, "");
+						}, function(e) {
+							__currier_test(name, false, (e && e.message) || String(e));
+						});
+					} else if (result === false) {
 						__currier_test(name, false, "");
 					} else {
 						__currier_test(name, true, "");
@@ -78,6 +86,28 @@ func (s *ScopeWithAssertions) initAssertions() {
 		pm.test = currier.test;
 		pm.expect = currier.expect;
 
+		// pm.response.to.have.jsonSchema(schema) validates the response body
+		currier.response.to = {
+			have: {
+				jsonSchema: function(schema) {
+					__currier_assert_schema(currier.response.body, schema, false);
+				}
+			}
+		};
+
+		function __currier_assert_schema(json, schema, negated) {
+			var errors = __currier_validate_schema(json, JSON.stringify(schema));
+			if (errors.indexOf("invalid schema: ") === 0) {
+				throw new Error(errors);
+			}
+			if (negated && errors === "") {
+				throw new Error("Expected value not to match schema");
+			}
+			if (!negated && errors !== "") {
+				throw new Error("Expected value to match schema: " + errors);
+			}
+		}
+
 		function CurrierExpect(actual, negated) {
 			this.actual = actual;
 			this.negated = negated;
@@ -87,6 +117,16 @@ func (s *ScopeWithAssertions) initAssertions() {
 					return new CurrierExpect(actual, !negated);
 				}
 			});
+
+			// Chai-style chains: expect(x).to.have.jsonSchema(schema)
+			var self = this;
+			['to', 'be', 'have'].forEach(function(word) {
+				Object.defineProperty(self, word, {
+					get: function() {
+						return self;
+					}
+				});
+			});
 		}
 
 		CurrierExpect.prototype._assert = function(passed, message) {
@@ -186,6 +226,12 @@ func (s *ScopeWithAssertions) initAssertions() {
 			this._assert(passed, "Expected value to be instance of " + (constructor.name || constructor));
 		};
 
+		CurrierExpect.prototype.matchSchema = function(schema) {
+			var json = this.actual === undefined ? "null" : JSON.stringify(this.actual);
+			__currier_assert_schema(json, schema, this.negated);
+		};
+		CurrierExpect.prototype.jsonSchema = CurrierExpect.prototype.matchSchema;
+
 		CurrierExpect.prototype.toThrow = function(message) {
 			var passed = false;
 			var error = null;
@@ -382,6 +382,134 @@ func TestAssertions_ResponseTests(t *testing.T) {
 	})
 }
 
+func TestAssertions_JSONSchema(t *testing.T) {
+	const userSchema = `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"required": ["id", "name"],
+		"properties": {
+			"id": {"type": "integer"},
+			"name": {"type": "string"},
+			"tags": {"type": "array", "items": {"type": "string"}}
+		}
+	}`
+
+	t.Run("currier.expect matchSchema passes for a valid value", func(t *testing.T) {
+		scope := NewScopeWithAssertions()
+		scope.SetResponseBody(`{"id": 1, "name": "Ada", "tags": ["x"]}`)
+
+		_, err := scope.Execute(context.Background(), `
+			currier.test("User matches schema", function() {
+				currier.expect(currier.response.json()).to.matchSchema(`+userSchema+`);
+			});
+		`)
+
+		require.NoError(t, err)
+		results := scope.GetTestResults()
+		require.Len(t, results, 1)
+		assert.True(t, results[0].Passed, results[0].Error)
+	})
+
+	t.Run("matchSchema reports every violation with its path", func(t *testing.T) {
+		scope := NewScopeWithAssertions()
+		scope.SetResponseBody(`{"id": "1", "tags": ["x", 2]}`)
+
+		_, err := scope.Execute(context.Background(), `
+			currier.test("User matches schema", function() {
+				currier.expect(currier.response.json()).to.matchSchema(`+userSchema+`);
+			});
+		`)
+
+		require.NoError(t, err)
+		results := scope.GetTestResults()
+		require.Len(t, results, 1)
+		assert.False(t, results[0].Passed)
+		assert.Equal(t, "Expected value to match schema: (root): missing required property \"name\"\n"+
+			"/id: expected integer, got string\n"+
+			"/tags/1: expected string, got integer", results[0].Error)
+	})
+
+	t.Run("not.matchSchema and draft 7", func(t *testing.T) {
+		scope := NewScopeWithAssertions()
+
+		_, err := scope.Execute(context.Background(), `
+			var schema = {"$schema": "http://json-schema.org/draft-07/schema#", "items": [{"type": "string"}], "additionalItems": false};
+			currier.test("tuple", function() {
+				currier.expect(["a"]).to.matchSchema(schema);
+			});
+			currier.test("too long", function() {
+				currier.expect(["a", "b"]).not.to.matchSchema(schema);
+			});
+			currier.test("negated failure", function() {
+				currier.expect(["a"]).not.to.matchSchema(schema);
+			});
+		`)
+
+		require.NoError(t, err)
+		results := scope.GetTestResults()
+		require.Len(t, results, 3)
+		assert.True(t, results[0].Passed, results[0].Error)
+		assert.True(t, results[1].Passed, results[1].Error)
+		assert.False(t, results[2].Passed)
+		assert.Equal(t, "Expected value not to match schema", results[2].Error)
+	})
+
+	t.Run("invalid schema fails even when negated", func(t *testing.T) {
+		scope := NewScopeWithAssertions()
+
+		_, err := scope.Execute(context.Background(), `
+			currier.test("bad schema", function() {
+				currier.expect({}).not.to.matchSchema("object");
+			});
+		`)
+
+		require.NoError(t, err)
+		results := scope.GetTestResults()
+		require.Len(t, results, 1)
+		assert.False(t, results[0].Passed)
+		assert.Contains(t, results[0].Error, "invalid schema: ")
+	})
+
+	t.Run("pm.response.to.have.jsonSchema validates the body", func(t *testing.T) {
+		scope := NewScopeWithAssertions()
+		scope.SetResponseBody(`{"id": 1}`)
+
+		_, err := scope.Execute(context.Background(), `
+			var schema = {"type": "object", "required": ["id", "name"]};
+			pm.test("Schema is valid", function() {
+				pm.response.to.have.jsonSchema(schema);
+			});
+			pm.test("expect jsonSchema", function() {
+				pm.expect(pm.response.json()).to.have.jsonSchema({"required": ["id"]});
+			});
+		`)
+
+		require.NoError(t, err)
+		results := scope.GetTestResults()
+		require.Len(t, results, 2)
+		assert.False(t, results[0].Passed)
+		assert.Equal(t, `Expected value to match schema: (root): missing required property "name"`, results[0].Error)
+		assert.True(t, results[1].Passed, results[1].Error)
+	})
+
+	t.Run("non-JSON response body fails jsonSchema", func(t *testing.T) {
+		scope := NewScopeWithAssertions()
+		scope.SetResponseBody(`<html></html>`)
+
+		_, err := scope.Execute(context.Background(), `
+			pm.test("Schema is valid", function() {
+				pm.response.to.have.jsonSchema({"type": "object"});
+			});
+		`)
+
+		require.NoError(t, err)
+		results := scope.GetTestResults()
+		require.Len(t, results, 1)
+		assert.False(t, results[0].Passed)
+		assert.Contains(t, results[0].Error, "value is not JSON")
+	})
+}
+
 func TestAssertions_ClearResults(t *testing.T) {
 	t.Run("clear test results", func(t *testing.T) {
 		scope := NewScopeWithAssertions()
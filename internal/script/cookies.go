@@ -0,0 +1,275 @@
+package script
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/dop251/goja"
+)
+
+// SetCookieJar gives scripts access to jar through currier.cookies.jar().
+// Cookies set there are sent with later requests using the same jar, and
+// persist when the jar does.
+func (s *Scope) SetCookieJar(jar http.CookieJar) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cookieJar = jar
+}
+
+// SetResponseCookies sets the cookies the response set, read by scripts
+// through currier.cookies.
+func (s *Scope) SetResponseCookies(cookies []*http.Cookie) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.responseCookies = append([]*http.Cookie(nil), cookies...)
+}
+
+// ParseSetCookies parses Set-Cookie header values, skipping invalid ones.
+func ParseSetCookies(values []string) []*http.Cookie {
+	var cookies []*http.Cookie
+	for _, v := range values {
+		if c, err := http.ParseSetCookie(v); err == nil {
+			cookies = append(cookies, c)
+		}
+	}
+	return cookies
+}
+
+// cookieObject converts a cookie to the object scripts see.
+func cookieObject(c *http.Cookie) map[string]interface{} {
+	obj := map[string]interface{}{
+		"name":     c.Name,
+		"value":    c.Value,
+		"domain":   c.Domain,
+		"path":     c.Path,
+		"httpOnly": c.HttpOnly,
+		"secure":   c.Secure,
+	}
+	if !c.Expires.IsZero() {
+		obj["expires"] = c.Expires.UTC().Format(time.RFC3339)
+	}
+	return obj
+}
+
+// createCookiesObjectLocked creates the currier.cookies object: the
+// response's cookies plus jar() for the run's cookie jar. Caller must hold
+// at least RLock.
+func (s *Scope) createCookiesObjectLocked() map[string]interface{} {
+	cookies := append([]*http.Cookie(nil), s.responseCookies...)
+	find := func(name string) *http.Cookie {
+		for _, c := range cookies {
+			if c.Name == name {
+				return c
+			}
+		}
+		return nil
+	}
+
+	return map[string]interface{}{
+		"get": func(name string) interface{} {
+			if c := find(name); c != nil {
+				return c.Value
+			}
+			return nil
+		},
+		"has": func(name string) bool {
+			return find(name) != nil
+		},
+		"one": func(name string) interface{} {
+			if c := find(name); c != nil {
+				return cookieObject(c)
+			}
+			return nil
+		},
+		"all": func() []interface{} {
+			all := make([]interface{}, len(cookies))
+			for i, c := range cookies {
+				all[i] = cookieObject(c)
+			}
+			return all
+		},
+		"toObject": func() map[string]interface{} {
+			obj := make(map[string]interface{})
+			for _, c := range cookies {
+				obj[c.Name] = c.Value
+			}
+			return obj
+		},
+		"jar": func() map[string]interface{} {
+			return s.createCookieJarObject()
+		},
+	}
+}
+
+// createCookieJarObject creates the object returned by currier.cookies.jar().
+// Each method returns its result and, as in Postman, also passes
+// (error, result) to an optional trailing callback.
+func (s *Scope) createCookieJarObject() map[string]interface{} {
+	jarFunc := func(minArgs int, fn func(jar http.CookieJar, u *url.URL, args []goja.Value) (interface{}, error)) func(goja.FunctionCall) goja.Value {
+		return func(call goja.FunctionCall) goja.Value {
+			rt := s.engine.runtime
+			args := call.Arguments
+			var callback goja.Callable
+			if n := len(args); n > minArgs {
+				if cb, ok := goja.AssertFunction(args[n-1]); ok {
+					callback = cb
+					args = args[:n-1]
+				}
+			}
+
+			result, err := s.withCookieJar(args, minArgs, fn)
+			if callback != nil {
+				errValue, resultValue := goja.Null(), goja.Null()
+				if err != nil {
+					errValue = rt.NewGoError(err)
+				} else if result != nil {
+					resultValue = rt.ToValue(result)
+				}
+				if _, cbErr := callback(goja.Undefined(), errValue, resultValue); cbErr != nil {
+					var exception *goja.Exception
+					if errors.As(cbErr, &exception) {
+						panic(exception)
+					}
+					panic(rt.NewGoError(cbErr))
+				}
+				return rt.ToValue(result)
+			}
+			if err != nil {
+				panic(rt.NewGoError(err))
+			}
+			return rt.ToValue(result)
+		}
+	}
+
+	return map[string]interface{}{
+		"get": jarFunc(2, func(jar http.CookieJar, u *url.URL, args []goja.Value) (interface{}, error) {
+			name := args[1].String()
+			for _, c := range jar.Cookies(u) {
+				if c.Name == name {
+					return c.Value, nil
+				}
+			}
+			return nil, nil
+		}),
+		"getAll": jarFunc(1, func(jar http.CookieJar, u *url.URL, args []goja.Value) (interface{}, error) {
+			all := []interface{}{}
+			for _, c := range jar.Cookies(u) {
+				all = append(all, cookieObject(c))
+			}
+			return all, nil
+		}),
+		"set": jarFunc(2, func(jar http.CookieJar, u *url.URL, args []goja.Value) (interface{}, error) {
+			c, err := jarCookie(args[1:])
+			if err != nil {
+				return nil, err
+			}
+			jar.SetCookies(u, []*http.Cookie{c})
+			return cookieObject(c), nil
+		}),
+		"unset": jarFunc(2, func(jar http.CookieJar, u *url.URL, args []goja.Value) (interface{}, error) {
+			unsetCookie(jar, u, args[1].String())
+			return nil, nil
+		}),
+		"clear": jarFunc(1, func(jar http.CookieJar, u *url.URL, args []goja.Value) (interface{}, error) {
+			for _, c := range jar.Cookies(u) {
+				unsetCookie(jar, u, c.Name)
+			}
+			return nil, nil
+		}),
+	}
+}
+
+// withCookieJar checks the jar and URL arguments and runs fn.
+func (s *Scope) withCookieJar(args []goja.Value, minArgs int, fn func(http.CookieJar, *url.URL, []goja.Value) (interface{}, error)) (interface{}, error) {
+	s.mu.RLock()
+	jar := s.cookieJar
+	s.mu.RUnlock()
+
+	if jar == nil {
+		return nil, errors.New("no cookie jar is available")
+	}
+	if len(args) < minArgs {
+		return nil, fmt.Errorf("expected at least %d arguments", minArgs)
+	}
+	u, err := url.Parse(args[0].String())
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid cookie URL %q", args[0].String())
+	}
+	return fn(jar, u, args)
+}
+
+// jarCookie reads the cookie passed to jar.set, either (name, value) or an
+// object with name, value, path, domain, expires, maxAge, httpOnly and
+// secure.
+func jarCookie(args []goja.Value) (*http.Cookie, error) {
+	if obj, ok := args[0].Export().(map[string]interface{}); ok {
+		o := scriptOptions(obj)
+		c := &http.Cookie{
+			Name:   o.str("name"),
+			Value:  o.str("value"),
+			Path:   o.str("path"),
+			Domain: o.str("domain"),
+			MaxAge: o.int("maxAge", 0),
+		}
+		c.HttpOnly, _ = obj["httpOnly"].(bool)
+		c.Secure, _ = obj["secure"].(bool)
+		if expires := o.str("expires"); expires != "" {
+			t, err := time.Parse(time.RFC3339, expires)
+			if err != nil {
+				return nil, fmt.Errorf("invalid cookie expiry %q (use RFC 3339)", expires)
+			}
+			c.Expires = t
+		}
+		if c.Name == "" {
+			return nil, errors.New("cookie name is required")
+		}
+		return c, nil
+	}
+
+	name := args[0].String()
+	if name == "" {
+		return nil, errors.New("cookie name is required")
+	}
+	value := ""
+	if len(args) > 1 {
+		value = args[1].String()
+	}
+	return &http.Cookie{Name: name, Value: value}, nil
+}
+
+// unsetCookie deletes a cookie by name. Jars only report names and values,
+// so every domain and path that could hold the cookie for u is expired.
+func unsetCookie(jar http.CookieJar, u *url.URL, name string) {
+	host := u.Hostname()
+	domains := []string{""}
+	if net.ParseIP(host) == nil {
+		for d := host; strings.Contains(d, "."); d = d[strings.Index(d, ".")+1:] {
+			domains = append(domains, d)
+		}
+	}
+
+	paths := []string{"/"}
+	p := ""
+	for _, segment := range strings.Split(strings.Trim(u.Path, "/"), "/") {
+		if segment == "" {
+			continue
+		}
+		p += "/" + segment
+		paths = append(paths, p)
+	}
+
+	var expired []*http.Cookie
+	for _, domain := range domains {
+		for _, path := range paths {
+			expired = append(expired, &http.Cookie{Name: name, Domain: domain, Path: path, MaxAge: -1})
+		}
+	}
+	for _, c := range expired {
+		jar.SetCookies(u, []*http.Cookie{c})
+	}
+}
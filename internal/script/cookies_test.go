@@ -0,0 +1,140 @@
+package script
+
+import (
+	"context"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"testing"
+
+	"github.com/artpar/currier/internal/cookies"
+	cookiestore "github.com/artpar/currier/internal/cookies/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCookies_ResponseCookies(t *testing.T) {
+	scope := NewScope()
+	scope.SetResponseCookies(ParseSetCookies([]string{
+		"session=abc123; Path=/; HttpOnly",
+		"csrf=tok; Path=/api; Secure",
+		"invalid",
+	}))
+
+	tests := []struct {
+		name     string
+		script   string
+		expected interface{}
+	}{
+		{"get", `currier.cookies.get("csrf")`, "tok"},
+		{"get missing", `currier.cookies.get("missing")`, nil},
+		{"has", `currier.cookies.has("session") && !currier.cookies.has("invalid")`, true},
+		{"one", `currier.cookies.one("session").httpOnly`, true},
+		{"all", `currier.cookies.all().map(function(c) { return c.name + ":" + c.path; }).join(",")`, "session:/,csrf:/api"},
+		{"toObject", `var o = pm.cookies.toObject(); o.session + "," + o.csrf`, "abc123,tok"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := scope.Execute(context.Background(), tt.script)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestCookies_Jar(t *testing.T) {
+	newScope := func(t *testing.T) (*Scope, http.CookieJar) {
+		jar, err := cookiejar.New(nil)
+		require.NoError(t, err)
+		scope := NewScope()
+		scope.SetCookieJar(jar)
+		return scope, jar
+	}
+	u, _ := url.Parse("https://api.example.com/v1/login")
+
+	t.Run("set and get", func(t *testing.T) {
+		scope, jar := newScope(t)
+
+		result, err := scope.Execute(context.Background(), `
+			var jar = pm.cookies.jar();
+			jar.set("https://api.example.com/", "token", "t1");
+			jar.set("https://api.example.com/", {name: "pref", value: "dark", path: "/v1", httpOnly: true});
+			jar.get("https://api.example.com/v1/x", "pref") + "," + jar.get("https://api.example.com/", "token");
+		`)
+
+		require.NoError(t, err)
+		assert.Equal(t, "dark,t1", result)
+		assert.Len(t, jar.Cookies(u), 2)
+	})
+
+	t.Run("callbacks receive error and result", func(t *testing.T) {
+		scope, _ := newScope(t)
+
+		result, err := scope.Execute(context.Background(), `
+			var out = [];
+			var jar = currier.cookies.jar();
+			jar.set("https://api.example.com/", "a", "1", function(err, cookie) { out.push(err === null, cookie.name); });
+			jar.getAll("https://api.example.com/", function(err, cookies) { out.push(cookies.length); });
+			jar.get("not a url", "a", function(err) { out.push(err.message); });
+			out.join(",");
+		`)
+
+		require.NoError(t, err)
+		assert.Equal(t, `true,a,1,invalid cookie URL "not a url"`, result)
+	})
+
+	t.Run("unset and clear", func(t *testing.T) {
+		scope, jar := newScope(t)
+		jar.SetCookies(u, []*http.Cookie{
+			{Name: "session", Value: "s", Path: "/"},
+			{Name: "csrf", Value: "c", Path: "/v1", Domain: "example.com"},
+			{Name: "other", Value: "o"},
+		})
+
+		result, err := scope.Execute(context.Background(), `
+			var jar = pm.cookies.jar();
+			jar.unset("https://api.example.com/v1/login", "csrf");
+			var names = jar.getAll("https://api.example.com/v1/login").map(function(c) { return c.name; }).sort().join(",");
+			jar.clear("https://api.example.com/v1/login");
+			names + "|" + jar.getAll("https://api.example.com/v1/login").length;
+		`)
+
+		require.NoError(t, err)
+		assert.Equal(t, "other,session|0", result)
+	})
+
+	t.Run("no jar", func(t *testing.T) {
+		scope := NewScope()
+
+		_, err := scope.Execute(context.Background(), `pm.cookies.jar().get("https://example.com", "a")`)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no cookie jar is available")
+	})
+
+	t.Run("changes persist through the cookie store", func(t *testing.T) {
+		store, err := cookiestore.NewInMemory()
+		require.NoError(t, err)
+		defer store.Close()
+		jar, err := cookies.NewPersistentJar(store)
+		require.NoError(t, err)
+		scope := NewScope()
+		scope.SetCookieJar(jar)
+
+		_, err = scope.Execute(context.Background(), `
+			var jar = pm.cookies.jar();
+			jar.set("https://example.com/", {name: "keep", value: "1", path: "/"});
+			jar.set("https://example.com/", {name: "drop", value: "2", path: "/"});
+			jar.unset("https://example.com/", "drop");
+		`)
+		require.NoError(t, err)
+
+		stored, err := jar.ListAll()
+		require.NoError(t, err)
+		require.Len(t, stored, 1)
+		assert.Equal(t, "keep", stored[0].Name)
+		assert.Equal(t, "example.com", stored[0].Domain)
+	})
+}
@@ -0,0 +1,580 @@
+package script
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"hash"
+	"math/big"
+	"strings"
+)
+
+// maxRandomBytes caps randomBytes so a script can't exhaust memory.
+const maxRandomBytes = 1 << 16
+
+// hashes maps the digest names scripts use to their implementations.
+var hashes = map[string]crypto.Hash{
+	"md5":    crypto.MD5,
+	"sha1":   crypto.SHA1,
+	"sha224": crypto.SHA224,
+	"sha256": crypto.SHA256,
+	"sha384": crypto.SHA384,
+	"sha512": crypto.SHA512,
+}
+
+// lookupHash finds a digest by name, ignoring case and dashes ("SHA-256").
+func lookupHash(name string) (crypto.Hash, error) {
+	h, ok := hashes[strings.ToLower(strings.ReplaceAll(name, "-", ""))]
+	if !ok {
+		return 0, fmt.Errorf("unsupported hash algorithm %q (use md5, sha1, sha224, sha256, sha384 or sha512)", name)
+	}
+	return h, nil
+}
+
+func hashFunc(h crypto.Hash) func() hash.Hash {
+	switch h {
+	case crypto.MD5:
+		return md5.New
+	case crypto.SHA1:
+		return sha1.New
+	case crypto.SHA224:
+		return sha256.New224
+	case crypto.SHA256:
+		return sha256.New
+	case crypto.SHA384:
+		return sha512.New384
+	}
+	return sha512.New
+}
+
+func digest(h crypto.Hash, data []byte) []byte {
+	d := hashFunc(h)()
+	d.Write(data)
+	return d.Sum(nil)
+}
+
+// encodeBytes renders bytes as hex, base64, base64url, utf8 or latin1.
+func encodeBytes(b []byte, encoding string) (string, error) {
+	switch strings.ToLower(encoding) {
+	case "hex":
+		return hex.EncodeToString(b), nil
+	case "base64":
+		return base64.StdEncoding.EncodeToString(b), nil
+	case "base64url":
+		return base64.RawURLEncoding.EncodeToString(b), nil
+	case "utf8", "utf-8", "text":
+		return string(b), nil
+	case "latin1", "binary":
+		runes := make([]rune, len(b))
+		for i, c := range b {
+			runes[i] = rune(c)
+		}
+		return string(runes), nil
+	}
+	return "", fmt.Errorf("unsupported encoding %q (use hex, base64, base64url, utf8 or latin1)", encoding)
+}
+
+// decodeBytes reads a string written in one of the encodeBytes encodings.
+// Base64 input may be padded or not, standard or URL-safe.
+func decodeBytes(s, encoding string) ([]byte, error) {
+	switch strings.ToLower(encoding) {
+	case "hex":
+		b, err := hex.DecodeString(strings.TrimSpace(s))
+		if err != nil {
+			return nil, fmt.Errorf("invalid hex: %w", err)
+		}
+		return b, nil
+	case "base64", "base64url":
+		s = strings.TrimRight(strings.TrimSpace(s), "=")
+		s = strings.NewReplacer("-", "+", "_", "/").Replace(s)
+		b, err := base64.RawStdEncoding.DecodeString(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid base64: %w", err)
+		}
+		return b, nil
+	case "utf8", "utf-8", "text":
+		return []byte(s), nil
+	case "latin1", "binary":
+		b := make([]byte, 0, len(s))
+		for _, r := range s {
+			if r > 0xff {
+				return nil, fmt.Errorf("character %q is not latin1", r)
+			}
+			b = append(b, byte(r))
+		}
+		return b, nil
+	}
+	return nil, fmt.Errorf("unsupported encoding %q (use hex, base64, base64url, utf8 or latin1)", encoding)
+}
+
+func orDefault(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
+
+// scriptOptions reads an options object passed from a script, such as the
+// ones encrypt and sign take.
+type scriptOptions map[string]interface{}
+
+func (o scriptOptions) str(key string) string {
+	switch v := o[key].(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func (o scriptOptions) bytes(key, defaultEncoding string) ([]byte, error) {
+	b, err := decodeBytes(o.str(key), orDefault(o.str(key+"Encoding"), defaultEncoding))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", key, err)
+	}
+	return b, nil
+}
+
+func (o scriptOptions) int(key string, def int) int {
+	switch v := o[key].(type) {
+	case int64:
+		return int(v)
+	case float64:
+		return int(v)
+	case int:
+		return v
+	}
+	return def
+}
+
+// createCryptoObject creates the currier.crypto object.
+func (s *Scope) createCryptoObject() map[string]interface{} {
+	hashFn := func(name string) func(string, string) (string, error) {
+		return func(input, encoding string) (string, error) {
+			h, _ := lookupHash(name)
+			return encodeBytes(digest(h, []byte(input)), orDefault(encoding, "hex"))
+		}
+	}
+
+	return map[string]interface{}{
+		"md5":    hashFn("md5"),
+		"sha1":   hashFn("sha1"),
+		"sha256": hashFn("sha256"),
+		"sha384": hashFn("sha384"),
+		"sha512": hashFn("sha512"),
+		"hash": func(algorithm, input, encoding string) (string, error) {
+			h, err := lookupHash(algorithm)
+			if err != nil {
+				return "", err
+			}
+			return encodeBytes(digest(h, []byte(input)), orDefault(encoding, "hex"))
+		},
+		"hmac": func(algorithm, key, data, encoding string) (string, error) {
+			h, err := lookupHash(orDefault(algorithm, "sha256"))
+			if err != nil {
+				return "", err
+			}
+			mac := hmac.New(hashFunc(h), []byte(key))
+			mac.Write([]byte(data))
+			return encodeBytes(mac.Sum(nil), orDefault(encoding, "hex"))
+		},
+		"randomBytes": func(n int, encoding string) (string, error) {
+			return randomBytes(n, orDefault(encoding, "hex"))
+		},
+		"pbkdf2": func(opts map[string]interface{}) (string, error) {
+			return pbkdf2Key(scriptOptions(opts))
+		},
+		"encrypt": func(opts map[string]interface{}) (string, error) {
+			return encrypt(scriptOptions(opts))
+		},
+		"decrypt": func(opts map[string]interface{}) (string, error) {
+			return decrypt(scriptOptions(opts))
+		},
+		"sign": func(opts map[string]interface{}) (string, error) {
+			return sign(scriptOptions(opts))
+		},
+		"verify": func(opts map[string]interface{}) (bool, error) {
+			return verify(scriptOptions(opts))
+		},
+	}
+}
+
+// randomBytes returns n cryptographically random bytes.
+func randomBytes(n int, encoding string) (string, error) {
+	if n <= 0 || n > maxRandomBytes {
+		return "", fmt.Errorf("randomBytes size must be between 1 and %d", maxRandomBytes)
+	}
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return encodeBytes(b, encoding)
+}
+
+func pbkdf2Key(o scriptOptions) (string, error) {
+	h, err := lookupHash(orDefault(o.str("hash"), "sha256"))
+	if err != nil {
+		return "", err
+	}
+	salt, err := o.bytes("salt", "utf8")
+	if err != nil {
+		return "", err
+	}
+	key, err := pbkdf2.Key(hashFunc(h), o.str("password"), salt, o.int("iterations", 10000), o.int("keyLength", 32))
+	if err != nil {
+		return "", fmt.Errorf("pbkdf2: %w", err)
+	}
+	return encodeBytes(key, orDefault(o.str("encoding"), "hex"))
+}
+
+// aesAlgorithm parses names such as aes-256-gcm, aes-128-cbc or aes-gcm,
+// checking the key size when the name gives one.
+func aesAlgorithm(name string, key []byte) (cipher.Block, string, error) {
+	parts := strings.Split(strings.ToLower(name), "-")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] != "aes" {
+		return nil, "", fmt.Errorf("unsupported algorithm %q (use aes-128-gcm, aes-256-gcm, aes-128-cbc, aes-256-cbc, ...)", name)
+	}
+	mode := parts[len(parts)-1]
+	if mode != "gcm" && mode != "cbc" && mode != "ctr" {
+		return nil, "", fmt.Errorf("unsupported AES mode %q (use gcm, cbc or ctr)", mode)
+	}
+	if len(parts) == 3 && parts[1] != fmt.Sprint(len(key)*8) {
+		return nil, "", fmt.Errorf("%s needs a %s-bit key, got %d bits", name, parts[1], len(key)*8)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid AES key: %w", err)
+	}
+	return block, mode, nil
+}
+
+// encrypt encrypts data with AES. GCM output is the ciphertext followed by
+// the 16-byte tag; CBC uses PKCS#7 padding.
+func encrypt(o scriptOptions) (string, error) {
+	key, err := o.bytes("key", "hex")
+	if err != nil {
+		return "", err
+	}
+	iv, err := o.bytes("iv", "hex")
+	if err != nil {
+		return "", err
+	}
+	data, err := o.bytes("data", "utf8")
+	if err != nil {
+		return "", err
+	}
+	block, mode, err := aesAlgorithm(orDefault(o.str("algorithm"), "aes-256-gcm"), key)
+	if err != nil {
+		return "", err
+	}
+
+	out, err := aesCrypt(block, mode, iv, data, []byte(o.str("aad")), true)
+	if err != nil {
+		return "", err
+	}
+	return encodeBytes(out, orDefault(o.str("encoding"), "base64"))
+}
+
+// decrypt reverses encrypt.
+func decrypt(o scriptOptions) (string, error) {
+	key, err := o.bytes("key", "hex")
+	if err != nil {
+		return "", err
+	}
+	iv, err := o.bytes("iv", "hex")
+	if err != nil {
+		return "", err
+	}
+	data, err := o.bytes("data", "base64")
+	if err != nil {
+		return "", err
+	}
+	block, mode, err := aesAlgorithm(orDefault(o.str("algorithm"), "aes-256-gcm"), key)
+	if err != nil {
+		return "", err
+	}
+
+	out, err := aesCrypt(block, mode, iv, data, []byte(o.str("aad")), false)
+	if err != nil {
+		return "", err
+	}
+	return encodeBytes(out, orDefault(o.str("encoding"), "utf8"))
+}
+
+func aesCrypt(block cipher.Block, mode string, iv, data, aad []byte, encrypting bool) ([]byte, error) {
+	switch mode {
+	case "gcm":
+		if len(iv) == 0 {
+			return nil, errors.New("iv is required")
+		}
+		gcm, err := cipher.NewGCMWithNonceSize(block, len(iv))
+		if err != nil {
+			return nil, err
+		}
+		if encrypting {
+			return gcm.Seal(nil, iv, data, aad), nil
+		}
+		out, err := gcm.Open(nil, iv, data, aad)
+		if err != nil {
+			return nil, errors.New("decryption failed: message authentication failed")
+		}
+		return out, nil
+
+	case "cbc":
+		if len(iv) != aes.BlockSize {
+			return nil, fmt.Errorf("iv must be %d bytes, got %d", aes.BlockSize, len(iv))
+		}
+		if encrypting {
+			data = pkcs7Pad(data, aes.BlockSize)
+			out := make([]byte, len(data))
+			cipher.NewCBCEncrypter(block, iv).CryptBlocks(out, data)
+			return out, nil
+		}
+		if len(data) == 0 || len(data)%aes.BlockSize != 0 {
+			return nil, errors.New("decryption failed: ciphertext is not a multiple of the block size")
+		}
+		out := make([]byte, len(data))
+		cipher.NewCBCDecrypter(block, iv).CryptBlocks(out, data)
+		return pkcs7Unpad(out, aes.BlockSize)
+
+	default: // ctr
+		if len(iv) != aes.BlockSize {
+			return nil, fmt.Errorf("iv must be %d bytes, got %d", aes.BlockSize, len(iv))
+		}
+		out := make([]byte, len(data))
+		cipher.NewCTR(block, iv).XORKeyStream(out, data)
+		return out, nil
+	}
+}
+
+func pkcs7Pad(data []byte, size int) []byte {
+	n := size - len(data)%size
+	return append(append([]byte{}, data...), bytes.Repeat([]byte{byte(n)}, n)...)
+}
+
+func pkcs7Unpad(data []byte, size int) ([]byte, error) {
+	n := int(data[len(data)-1])
+	if n == 0 || n > size || n > len(data) {
+		return nil, errors.New("decryption failed: invalid padding")
+	}
+	for _, c := range data[len(data)-n:] {
+		if int(c) != n {
+			return nil, errors.New("decryption failed: invalid padding")
+		}
+	}
+	return data[:len(data)-n], nil
+}
+
+// signatureAlgorithm describes a signing scheme.
+type signatureAlgorithm struct {
+	kind string // rsa, pss, ecdsa or ed25519
+	hash crypto.Hash
+	raw  bool // ECDSA signature as fixed-size r||s (JWS) instead of DER
+}
+
+// parseSignatureAlgorithm accepts JWS names (RS256, PS384, ES512, EdDSA)
+// and OpenSSL-style names (RSA-SHA256, RSA-PSS-SHA256, ECDSA-SHA256).
+func parseSignatureAlgorithm(name string) (signatureAlgorithm, error) {
+	upper := strings.ToUpper(name)
+	if upper == "EDDSA" || upper == "ED25519" {
+		return signatureAlgorithm{kind: "ed25519"}, nil
+	}
+	if len(upper) == 5 {
+		h, err := lookupHash("sha" + upper[2:])
+		if err == nil {
+			switch upper[:2] {
+			case "RS":
+				return signatureAlgorithm{kind: "rsa", hash: h}, nil
+			case "PS":
+				return signatureAlgorithm{kind: "pss", hash: h}, nil
+			case "ES":
+				return signatureAlgorithm{kind: "ecdsa", hash: h, raw: true}, nil
+			}
+		}
+	}
+	for prefix, kind := range map[string]string{"RSA-PSS-": "pss", "RSA-": "rsa", "ECDSA-": "ecdsa"} {
+		if rest, ok := strings.CutPrefix(upper, prefix); ok {
+			if kind == "rsa" && strings.HasPrefix(rest, "PSS-") {
+				continue
+			}
+			h, err := lookupHash(rest)
+			if err != nil {
+				return signatureAlgorithm{}, err
+			}
+			return signatureAlgorithm{kind: kind, hash: h}, nil
+		}
+	}
+	return signatureAlgorithm{}, fmt.Errorf("unsupported signature algorithm %q (use RS256, PS256, ES256, EdDSA, RSA-SHA256, ECDSA-SHA256, ...)", name)
+}
+
+// parsePrivateKey reads a PKCS#8, PKCS#1 or SEC 1 PEM private key.
+func parsePrivateKey(data string) (crypto.Signer, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, errors.New("key is not PEM encoded")
+	}
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		if signer, ok := key.(crypto.Signer); ok {
+			return signer, nil
+		}
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unsupported private key (%s)", block.Type)
+}
+
+// parsePublicKey reads a PKIX or PKCS#1 public key, a certificate, or the
+// public half of a private key.
+func parsePublicKey(data string) (crypto.PublicKey, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, errors.New("key is not PEM encoded")
+	}
+	if key, err := x509.ParsePKIXPublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	if cert, err := x509.ParseCertificate(block.Bytes); err == nil {
+		return cert.PublicKey, nil
+	}
+	if signer, err := parsePrivateKey(data); err == nil {
+		return signer.Public(), nil
+	}
+	return nil, fmt.Errorf("unsupported public key (%s)", block.Type)
+}
+
+// sign signs data with a PEM private key.
+func sign(o scriptOptions) (string, error) {
+	alg, err := parseSignatureAlgorithm(o.str("algorithm"))
+	if err != nil {
+		return "", err
+	}
+	key, err := parsePrivateKey(o.str("key"))
+	if err != nil {
+		return "", err
+	}
+	data, err := o.bytes("data", "utf8")
+	if err != nil {
+		return "", err
+	}
+
+	var sig []byte
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		sum := digest(alg.hash, data)
+		switch alg.kind {
+		case "rsa":
+			sig, err = rsa.SignPKCS1v15(rand.Reader, k, alg.hash, sum)
+		case "pss":
+			sig, err = rsa.SignPSS(rand.Reader, k, alg.hash, sum, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+		default:
+			err = fmt.Errorf("%s needs an RSA key", o.str("algorithm"))
+		}
+	case *ecdsa.PrivateKey:
+		if alg.kind != "ecdsa" {
+			return "", fmt.Errorf("%s cannot sign with an EC key", o.str("algorithm"))
+		}
+		sum := digest(alg.hash, data)
+		if alg.raw {
+			var r, s *big.Int
+			r, s, err = ecdsa.Sign(rand.Reader, k, sum)
+			if err == nil {
+				size := (k.Curve.Params().BitSize + 7) / 8
+				sig = make([]byte, 2*size)
+				r.FillBytes(sig[:size])
+				s.FillBytes(sig[size:])
+			}
+		} else {
+			sig, err = ecdsa.SignASN1(rand.Reader, k, sum)
+		}
+	case ed25519.PrivateKey:
+		if alg.kind != "ed25519" {
+			return "", fmt.Errorf("%s cannot sign with an Ed25519 key", o.str("algorithm"))
+		}
+		sig = ed25519.Sign(k, data)
+	default:
+		err = errors.New("unsupported private key type")
+	}
+	if err != nil {
+		return "", fmt.Errorf("sign: %w", err)
+	}
+	return encodeBytes(sig, orDefault(o.str("encoding"), "base64"))
+}
+
+// verify checks a signature made by sign.
+func verify(o scriptOptions) (bool, error) {
+	alg, err := parseSignatureAlgorithm(o.str("algorithm"))
+	if err != nil {
+		return false, err
+	}
+	key, err := parsePublicKey(o.str("key"))
+	if err != nil {
+		return false, err
+	}
+	data, err := o.bytes("data", "utf8")
+	if err != nil {
+		return false, err
+	}
+	sig, err := o.bytes("signature", "base64")
+	if err != nil {
+		return false, err
+	}
+
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		sum := digest(alg.hash, data)
+		switch alg.kind {
+		case "rsa":
+			return rsa.VerifyPKCS1v15(k, alg.hash, sum, sig) == nil, nil
+		case "pss":
+			return rsa.VerifyPSS(k, alg.hash, sum, sig, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthAuto}) == nil, nil
+		}
+		return false, fmt.Errorf("%s needs an RSA key", o.str("algorithm"))
+	case *ecdsa.PublicKey:
+		if alg.kind != "ecdsa" {
+			return false, fmt.Errorf("%s cannot verify with an EC key", o.str("algorithm"))
+		}
+		sum := digest(alg.hash, data)
+		if alg.raw {
+			size := (k.Curve.Params().BitSize + 7) / 8
+			if len(sig) != 2*size {
+				return false, nil
+			}
+			r := new(big.Int).SetBytes(sig[:size])
+			s := new(big.Int).SetBytes(sig[size:])
+			return ecdsa.Verify(k, sum, r, s), nil
+		}
+		return ecdsa.VerifyASN1(k, sum, sig), nil
+	case ed25519.PublicKey:
+		if alg.kind != "ed25519" {
+			return false, fmt.Errorf("%s cannot verify with an Ed25519 key", o.str("algorithm"))
+		}
+		return ed25519.Verify(k, data, sig), nil
+	}
+	return false, errors.New("unsupported public key type")
+}
@@ -0,0 +1,259 @@
+package script
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCrypto_HashesAndEncodings(t *testing.T) {
+	tests := []struct {
+		name     string
+		script   string
+		expected interface{}
+	}{
+		{"sha1", `currier.crypto.sha1("abc")`, "a9993e364706816aba3e25717850c26c9cd0d89d"},
+		{"sha384", `currier.crypto.sha384("abc")`, "cb00753f45a35e8bb5a03d699ac65007272c32ab0eded1631a8b605a43ff5bed8086072ba1e7cc2358baeca134c825a7"},
+		{"sha512 base64", `currier.crypto.sha512("", "base64")`, "z4PhNX7vuL3xVChQ1m2AB9Yg5AULVxXcg/SpIdNs6c5H0NE8XYXysP+DGNKHfuwvY7kxvUdBeoGlODJ6+SfaPg=="},
+		{"hash by name", `currier.crypto.hash("SHA-256", "abc")`, "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"},
+		{"hmac sha1", `currier.crypto.hmac("sha1", "key", "The quick brown fox jumps over the lazy dog")`, "de7c9b85b8b78aa6bc8a7a36f70a90701c9db4d9"},
+		{"hmac base64", `currier.crypto.hmac("sha256", "key", "The quick brown fox jumps over the lazy dog", "base64")`, "97yD9DBThCSxMpjmqm+xQ+9NWaFJRhdZl0edvC0aPNg="},
+		{"hmac default algorithm", `currier.crypto.hmac("", "key", "data") === currier.crypto.hmac("sha256", "key", "data")`, true},
+		{"hex encode", `currier.hex.encode("hi!")`, "686921"},
+		{"hex decode", `currier.hex.decode("686921")`, "hi!"},
+		{"base64url encode", `currier.base64.encodeURL("ÿþ?")`, "w7_Dvj8"},
+		{"base64url decode", `currier.base64.decodeURL("w7_Dvj8")`, "ÿþ?"},
+		{"random bytes", `currier.crypto.randomBytes(16).length + currier.crypto.randomBytes(16, "base64url").length`, int64(54)},
+		{"pbkdf2", `currier.crypto.pbkdf2({password: "password", salt: "salt", iterations: 1, keyLength: 20, hash: "sha1"})`, "0c60c80f961f0e71f3a9b524af6012062fe037a6"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scope := NewScope()
+
+			result, err := scope.Execute(context.Background(), tt.script)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+
+	t.Run("unsupported algorithm", func(t *testing.T) {
+		scope := NewScope()
+
+		_, err := scope.Execute(context.Background(), `currier.crypto.hmac("sha3", "key", "data")`)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `unsupported hash algorithm "sha3"`)
+	})
+
+	t.Run("random bytes size is bounded", func(t *testing.T) {
+		scope := NewScope()
+
+		_, err := scope.Execute(context.Background(), `currier.crypto.randomBytes(1 << 20)`)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "randomBytes size must be between 1 and 65536")
+	})
+}
+
+func TestCrypto_AES(t *testing.T) {
+	key := "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f"
+
+	t.Run("GCM round trip", func(t *testing.T) {
+		scope := NewScope()
+		scope.Engine().SetGlobal("key", key)
+
+		result, err := scope.Execute(context.Background(), `
+			var opts = {key: key, iv: "000000000000000000000000", aad: "v1"};
+			var sealed = currier.crypto.encrypt(Object.assign({data: "secret"}, opts));
+			currier.crypto.decrypt(Object.assign({data: sealed}, opts));
+		`)
+
+		require.NoError(t, err)
+		assert.Equal(t, "secret", result)
+	})
+
+	t.Run("GCM rejects tampered data", func(t *testing.T) {
+		scope := NewScope()
+		scope.Engine().SetGlobal("key", key)
+
+		_, err := scope.Execute(context.Background(), `
+			var sealed = currier.crypto.encrypt({key: key, iv: "000000000000000000000000", data: "secret", encoding: "hex"});
+			var tampered = (sealed[0] === "0" ? "1" : "0") + sealed.slice(1);
+			currier.crypto.decrypt({key: key, iv: "000000000000000000000000", data: tampered, dataEncoding: "hex"});
+		`)
+
+		require.Error(t, err)
+	})
+
+	t.Run("CBC matches Go", func(t *testing.T) {
+		keyBytes, _ := hex.DecodeString(key)
+		iv := make([]byte, aes.BlockSize)
+		block, _ := aes.NewCipher(keyBytes)
+		expected := make([]byte, aes.BlockSize)
+		cipher.NewCBCEncrypter(block, iv).CryptBlocks(expected, pkcs7Pad([]byte("hello"), aes.BlockSize))
+
+		scope := NewScope()
+		scope.Engine().SetGlobal("key", key)
+		result, err := scope.Execute(context.Background(), `
+			currier.crypto.encrypt({algorithm: "aes-256-cbc", key: key, iv: "00000000000000000000000000000000", data: "hello"})
+		`)
+
+		require.NoError(t, err)
+		assert.Equal(t, base64.StdEncoding.EncodeToString(expected), result)
+	})
+
+	t.Run("key size must match the algorithm", func(t *testing.T) {
+		scope := NewScope()
+		scope.Engine().SetGlobal("key", key)
+
+		_, err := scope.Execute(context.Background(), `
+			currier.crypto.encrypt({algorithm: "aes-128-gcm", key: key, iv: "000000000000000000000000", data: "x"})
+		`)
+
+		require.Error(t, err)
+	})
+}
+
+func TestCrypto_Signatures(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	rsaPrivate, _ := x509.MarshalPKCS8PrivateKey(rsaKey)
+	rsaPublic, _ := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	ecPrivate, _ := x509.MarshalECPrivateKey(ecKey)
+	ecPublic, _ := x509.MarshalPKIXPublicKey(&ecKey.PublicKey)
+	encode := func(kind string, der []byte) string {
+		return string(pem.EncodeToMemory(&pem.Block{Type: kind, Bytes: der}))
+	}
+
+	keys := map[string][2]string{
+		"rsa": {encode("PRIVATE KEY", rsaPrivate), encode("PUBLIC KEY", rsaPublic)},
+		"ec":  {encode("EC PRIVATE KEY", ecPrivate), encode("PUBLIC KEY", ecPublic)},
+	}
+
+	tests := []struct {
+		algorithm string
+		key       string
+		sigLen    int
+	}{
+		{"RS256", "rsa", 256},
+		{"PS384", "rsa", 256},
+		{"RSA-SHA256", "rsa", 256},
+		{"ES256", "ec", 64},
+		{"ECDSA-SHA256", "ec", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.algorithm, func(t *testing.T) {
+			scope := NewScope()
+			scope.Engine().SetGlobal("privateKey", keys[tt.key][0])
+			scope.Engine().SetGlobal("publicKey", keys[tt.key][1])
+			scope.Engine().SetGlobal("algorithm", tt.algorithm)
+
+			result, err := scope.Execute(context.Background(), `
+				var sig = currier.crypto.sign({algorithm: algorithm, key: privateKey, data: "header.payload", encoding: "base64url"});
+				var ok = currier.crypto.verify({algorithm: algorithm, key: publicKey, data: "header.payload", signature: sig});
+				var forged = currier.crypto.verify({algorithm: algorithm, key: publicKey, data: "header.other", signature: sig});
+				[ok, forged, sig];
+			`)
+
+			require.NoError(t, err)
+			values := result.([]interface{})
+			assert.Equal(t, true, values[0])
+			assert.Equal(t, false, values[1])
+			if tt.sigLen > 0 {
+				sig, err := base64.RawURLEncoding.DecodeString(values[2].(string))
+				require.NoError(t, err)
+				assert.Len(t, sig, tt.sigLen)
+			}
+		})
+	}
+
+	t.Run("wrong key type", func(t *testing.T) {
+		scope := NewScope()
+		scope.Engine().SetGlobal("privateKey", keys["ec"][0])
+
+		_, err := scope.Execute(context.Background(), `currier.crypto.sign({algorithm: "RS256", key: privateKey, data: "x"})`)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "RS256 cannot sign with an EC key")
+	})
+
+	t.Run("invalid PEM", func(t *testing.T) {
+		scope := NewScope()
+
+		_, err := scope.Execute(context.Background(), `currier.crypto.sign({algorithm: "RS256", key: "not a key", data: "x"})`)
+
+		require.Error(t, err)
+	})
+}
+
+func TestCrypto_CryptoJS(t *testing.T) {
+	tests := []struct {
+		name     string
+		script   string
+		expected interface{}
+	}{
+		{"SHA256 hex", `CryptoJS.SHA256("abc").toString()`, "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"},
+		{"MD5", `CryptoJS.MD5("abc").toString(CryptoJS.enc.Hex)`, "900150983cd24fb0d6963f7d28e17f72"},
+		{"HmacSHA256 base64", `CryptoJS.HmacSHA256("The quick brown fox jumps over the lazy dog", "key").toString(CryptoJS.enc.Base64)`, "97yD9DBThCSxMpjmqm+xQ+9NWaFJRhdZl0edvC0aPNg="},
+		{"Base64 stringify", `CryptoJS.enc.Base64.stringify(CryptoJS.enc.Utf8.parse("hello"))`, "aGVsbG8="},
+		{"Utf8 round trip", `CryptoJS.enc.Base64.parse("aGVsbG8=").toString(CryptoJS.enc.Utf8)`, "hello"},
+		{"words", `CryptoJS.enc.Hex.parse("0102030405").words.join(",")`, "16909060,83886080"},
+		{"WordArray.create", `CryptoJS.lib.WordArray.create([0x68656c6c, 0x6f000000], 5).toString(CryptoJS.enc.Utf8)`, "hello"},
+		{"PBKDF2", `CryptoJS.PBKDF2("password", "salt", {keySize: 5, iterations: 1}).toString()`, "0c60c80f961f0e71f3a9b524af6012062fe037a6"},
+		{"passphrase round trip", `
+			var ct = CryptoJS.AES.encrypt("secret message", "passphrase").toString();
+			ct.indexOf("U2FsdGVkX1") === 0 && CryptoJS.AES.decrypt(ct, "passphrase").toString(CryptoJS.enc.Utf8);
+		`, "secret message"},
+		{"key and iv round trip", `
+			var key = CryptoJS.enc.Hex.parse("000102030405060708090a0b0c0d0e0f");
+			var iv = CryptoJS.enc.Hex.parse("0f0e0d0c0b0a09080706050403020100");
+			var ct = CryptoJS.AES.encrypt("data", key, {iv: iv}).toString();
+			CryptoJS.AES.decrypt(ct, key, {iv: iv}).toString(CryptoJS.enc.Utf8);
+		`, "data"},
+		{"ECB no padding", `
+			var key = CryptoJS.enc.Hex.parse("000102030405060708090a0b0c0d0e0f");
+			CryptoJS.AES.encrypt(CryptoJS.enc.Hex.parse("00112233445566778899aabbccddeeff"), key,
+				{mode: CryptoJS.mode.ECB, padding: CryptoJS.pad.NoPadding}).ciphertext.toString();
+		`, "69c4e0d86a7b0430d8cdb78070b4c55a"},
+		{"require crypto-js", `require('crypto-js').SHA1("abc").toString()`, "a9993e364706816aba3e25717850c26c9cd0d89d"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scope := NewScopeWithAssertions()
+			scope.SetModuleLoader(NewModuleLoader(""))
+
+			result, err := scope.Execute(context.Background(), tt.script)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+
+	t.Run("available in sandboxed scopes", func(t *testing.T) {
+		scope := NewSandboxedScope()
+
+		result, err := scope.Execute(context.Background(), `CryptoJS.SHA256("abc").toString().substr(0, 8)`)
+
+		require.NoError(t, err)
+		assert.Equal(t, "ba7816bf", result)
+	})
+}
@@ -0,0 +1,346 @@
+package script
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/pbkdf2"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/dop251/goja"
+)
+
+// cryptoJSNatives are the byte-level primitives behind the CryptoJS shim.
+// Binary values cross into Go as hex strings, which is also how the shim's
+// WordArrays store them.
+var cryptoJSNatives = map[string]interface{}{
+	"convert": func(input, from, to string) (string, error) {
+		b, err := decodeBytes(input, from)
+		if err != nil {
+			return "", err
+		}
+		return encodeBytes(b, to)
+	},
+	"hash": func(algorithm, dataHex string) (string, error) {
+		h, err := lookupHash(algorithm)
+		if err != nil {
+			return "", err
+		}
+		data, err := hex.DecodeString(dataHex)
+		if err != nil {
+			return "", err
+		}
+		return hex.EncodeToString(digest(h, data)), nil
+	},
+	"hmac": func(algorithm, keyHex, dataHex string) (string, error) {
+		h, err := lookupHash(algorithm)
+		if err != nil {
+			return "", err
+		}
+		key, err := hex.DecodeString(keyHex)
+		if err != nil {
+			return "", err
+		}
+		data, err := hex.DecodeString(dataHex)
+		if err != nil {
+			return "", err
+		}
+		mac := hmac.New(hashFunc(h), key)
+		mac.Write(data)
+		return hex.EncodeToString(mac.Sum(nil)), nil
+	},
+	"random": func(n int) (string, error) {
+		return randomBytes(n, "hex")
+	},
+	"pbkdf2": func(algorithm, passwordHex, saltHex string, iterations, keyLength int) (string, error) {
+		h, err := lookupHash(algorithm)
+		if err != nil {
+			return "", err
+		}
+		password, err := hex.DecodeString(passwordHex)
+		if err != nil {
+			return "", err
+		}
+		salt, err := hex.DecodeString(saltHex)
+		if err != nil {
+			return "", err
+		}
+		key, err := pbkdf2.Key(hashFunc(h), string(password), salt, iterations, keyLength)
+		if err != nil {
+			return "", err
+		}
+		return hex.EncodeToString(key), nil
+	},
+	"evpKDF": func(passwordHex, saltHex string, keyLength, ivLength int) (string, error) {
+		password, err := hex.DecodeString(passwordHex)
+		if err != nil {
+			return "", err
+		}
+		salt, err := hex.DecodeString(saltHex)
+		if err != nil {
+			return "", err
+		}
+		return hex.EncodeToString(evpBytesToKey(password, salt, keyLength+ivLength)), nil
+	},
+	"cipher": func(encrypting bool, mode, keyHex, ivHex, dataHex string, padding bool) (string, error) {
+		key, err := hex.DecodeString(keyHex)
+		if err != nil {
+			return "", err
+		}
+		iv, err := hex.DecodeString(ivHex)
+		if err != nil {
+			return "", err
+		}
+		data, err := hex.DecodeString(dataHex)
+		if err != nil {
+			return "", err
+		}
+		out, err := cryptoJSCipher(encrypting, mode, key, iv, data, padding)
+		if err != nil {
+			return "", err
+		}
+		return hex.EncodeToString(out), nil
+	},
+}
+
+// evpBytesToKey derives key material from a passphrase the way OpenSSL's
+// EVP_BytesToKey does with MD5 and one iteration, as CryptoJS does for
+// passphrase encryption.
+func evpBytesToKey(password, salt []byte, length int) []byte {
+	var out, prev []byte
+	for len(out) < length {
+		h := md5.New()
+		h.Write(prev)
+		h.Write(password)
+		h.Write(salt)
+		prev = h.Sum(nil)
+		out = append(out, prev...)
+	}
+	return out[:length]
+}
+
+// cryptoJSCipher runs AES in the CBC, ECB or CTR modes CryptoJS offers.
+func cryptoJSCipher(encrypting bool, mode string, key, iv, data []byte, padding bool) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid AES key: %w", err)
+	}
+
+	if mode == "ctr" {
+		if len(iv) != aes.BlockSize {
+			return nil, fmt.Errorf("iv must be %d bytes, got %d", aes.BlockSize, len(iv))
+		}
+		out := make([]byte, len(data))
+		cipher.NewCTR(block, iv).XORKeyStream(out, data)
+		return out, nil
+	}
+
+	if encrypting && padding {
+		data = pkcs7Pad(data, aes.BlockSize)
+	}
+	if len(data)%aes.BlockSize != 0 {
+		return nil, errors.New("data is not a multiple of the block size (use padding)")
+	}
+
+	out := make([]byte, len(data))
+	switch mode {
+	case "cbc":
+		if len(iv) != aes.BlockSize {
+			return nil, fmt.Errorf("iv must be %d bytes, got %d", aes.BlockSize, len(iv))
+		}
+		if encrypting {
+			cipher.NewCBCEncrypter(block, iv).CryptBlocks(out, data)
+		} else {
+			cipher.NewCBCDecrypter(block, iv).CryptBlocks(out, data)
+		}
+	case "ecb":
+		for i := 0; i < len(data); i += aes.BlockSize {
+			if encrypting {
+				block.Encrypt(out[i:], data[i:i+aes.BlockSize])
+			} else {
+				block.Decrypt(out[i:], data[i:i+aes.BlockSize])
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unsupported mode %q", mode)
+	}
+
+	if !encrypting && padding {
+		if len(out) == 0 {
+			return nil, errors.New("decryption failed: invalid padding")
+		}
+		return pkcs7Unpad(out, aes.BlockSize)
+	}
+	return out, nil
+}
+
+var (
+	cryptoJSOnce    sync.Once
+	cryptoJSProgram *goja.Program
+)
+
+// installCryptoJS defines the CryptoJS global, a subset of the crypto-js
+// library compatible with Postman scripts.
+func (s *Scope) installCryptoJS() {
+	cryptoJSOnce.Do(func() {
+		cryptoJSProgram = goja.MustCompile("crypto-js", cryptoJSSource, false)
+	})
+	s.engine.RegisterObject("__currier_cryptojs", cryptoJSNatives)
+	s.engine.mu.Lock()
+	defer s.engine.mu.Unlock()
+	_, _ = s.engine.runtime.RunProgram(cryptoJSProgram)
+}
+
+const cryptoJSSource = `
+var CryptoJS = (function(native) {
+	function WordArray(hex) {
+		this._hex = hex || '';
+	}
+	Object.defineProperty(WordArray.prototype, 'sigBytes', {
+		get: function() { return this._hex.length / 2; }
+	});
+	Object.defineProperty(WordArray.prototype, 'words', {
+		get: function() {
+			var words = [];
+			for (var i = 0; i < this._hex.length; i += 8) {
+				words.push(parseInt((this._hex.substr(i, 8) + '00000000').substr(0, 8), 16) | 0);
+			}
+			return words;
+		}
+	});
+	WordArray.prototype.toString = function(encoder) {
+		return (encoder || C.enc.Hex).stringify(this);
+	};
+	WordArray.prototype.concat = function(other) {
+		this._hex += other._hex;
+		return this;
+	};
+	WordArray.prototype.clone = function() {
+		return new WordArray(this._hex);
+	};
+
+	function encoder(name) {
+		return {
+			stringify: function(wordArray) { return native.convert(wordArray._hex, 'hex', name); },
+			parse: function(s) { return new WordArray(native.convert(String(s), name, 'hex')); }
+		};
+	}
+
+	function toHex(v) {
+		return v instanceof WordArray ? v._hex : native.convert(String(v), 'utf8', 'hex');
+	}
+
+	function CipherParams(cfg) {
+		for (var k in cfg) this[k] = cfg[k];
+	}
+	CipherParams.prototype.toString = function(formatter) {
+		return (formatter || this.formatter || C.format.OpenSSL).stringify(this);
+	};
+
+	var C = {
+		lib: {
+			WordArray: {
+				create: function(words, sigBytes) {
+					words = words || [];
+					var hex = '';
+					for (var i = 0; i < words.length; i++) {
+						hex += ('00000000' + (words[i] >>> 0).toString(16)).slice(-8);
+					}
+					if (sigBytes === undefined) sigBytes = words.length * 4;
+					return new WordArray(hex.substr(0, sigBytes * 2));
+				},
+				random: function(n) { return new WordArray(native.random(n)); }
+			},
+			CipherParams: {
+				create: function(cfg) { return new CipherParams(cfg); }
+			}
+		},
+		enc: {
+			Hex: encoder('hex'),
+			Base64: encoder('base64'),
+			Base64url: encoder('base64url'),
+			Utf8: encoder('utf8'),
+			Latin1: encoder('latin1')
+		},
+		mode: { CBC: { name: 'cbc' }, ECB: { name: 'ecb' }, CTR: { name: 'ctr' } },
+		pad: { Pkcs7: { name: 'pkcs7' }, NoPadding: { name: 'none' } },
+		algo: {},
+		format: {}
+	};
+
+	['MD5', 'SHA1', 'SHA224', 'SHA256', 'SHA384', 'SHA512'].forEach(function(name) {
+		var alg = name.toLowerCase();
+		C[name] = function(message) { return new WordArray(native.hash(alg, toHex(message))); };
+		C['Hmac' + name] = function(message, key) { return new WordArray(native.hmac(alg, toHex(key), toHex(message))); };
+		C.algo[name] = { name: alg };
+	});
+
+	// keySize is in 32-bit words, as in crypto-js
+	C.PBKDF2 = function(password, salt, cfg) {
+		cfg = cfg || {};
+		var hasher = (cfg.hasher && cfg.hasher.name) || 'sha1';
+		return new WordArray(native.pbkdf2(hasher, toHex(password), toHex(salt), cfg.iterations || 1, (cfg.keySize || 4) * 4));
+	};
+
+	C.format.OpenSSL = {
+		stringify: function(params) {
+			var hex = params.ciphertext._hex;
+			if (params.salt) hex = '53616c7465645f5f' + params.salt._hex + hex;
+			return native.convert(hex, 'hex', 'base64');
+		},
+		parse: function(s) {
+			var hex = native.convert(s, 'base64', 'hex');
+			if (hex.indexOf('53616c7465645f5f') === 0) {
+				return new CipherParams({ salt: new WordArray(hex.substr(16, 16)), ciphertext: new WordArray(hex.substr(32)) });
+			}
+			return new CipherParams({ ciphertext: new WordArray(hex) });
+		}
+	};
+	C.format.Hex = {
+		stringify: function(params) { return params.ciphertext._hex; },
+		parse: function(s) { return new CipherParams({ ciphertext: C.enc.Hex.parse(s) }); }
+	};
+
+	// A string key is a passphrase: key and IV are derived from it and a
+	// salt like OpenSSL's EVP_BytesToKey.
+	function cipherKey(key, cfg, salt) {
+		if (key instanceof WordArray) {
+			return { key: key, iv: cfg.iv };
+		}
+		var derived = native.evpKDF(toHex(key), salt._hex, 32, 16);
+		return { key: new WordArray(derived.substr(0, 64)), iv: new WordArray(derived.substr(64)), salt: salt };
+	}
+
+	function run(encrypting, data, key, cfg, salt) {
+		var mode = cfg.mode || C.mode.CBC;
+		var padding = cfg.padding || C.pad.Pkcs7;
+		var k = cipherKey(key, cfg, salt);
+		var out = native.cipher(encrypting, mode.name, k.key._hex, k.iv ? k.iv._hex : '', data, padding.name === 'pkcs7');
+		return { out: new WordArray(out), key: k, mode: mode, padding: padding };
+	}
+
+	C.AES = {
+		encrypt: function(message, key, cfg) {
+			cfg = cfg || {};
+			var r = run(true, toHex(message), key, cfg, C.lib.WordArray.random(8));
+			return new CipherParams({
+				ciphertext: r.out, key: r.key.key, iv: r.key.iv, salt: r.key.salt,
+				mode: r.mode, padding: r.padding, formatter: cfg.format
+			});
+		},
+		decrypt: function(ciphertext, key, cfg) {
+			cfg = cfg || {};
+			if (typeof ciphertext === 'string') {
+				ciphertext = (cfg.format || C.format.OpenSSL).parse(ciphertext);
+			}
+			return run(false, ciphertext.ciphertext._hex, key, cfg, ciphertext.salt || new WordArray('')).out;
+		}
+	};
+
+	return C;
+})(__currier_cryptojs);
+`
@@ -20,6 +20,8 @@ type Engine struct {
 	globals        map[string]interface{}
 	functions      map[string]interface{}
 	consoleHandler ConsoleHandler
+	loop           *eventLoop
+	timeLimit      time.Duration
 }
 
 // NewEngine creates a new JavaScript execution engine.
@@ -27,6 +29,7 @@ func NewEngine() *Engine {
 	e := &Engine{
 		globals:   make(map[string]interface{}),
 		functions: make(map[string]interface{}),
+		timeLimit: DefaultTimeLimit,
 	}
 	e.initRuntime()
 	return e
@@ -40,6 +43,9 @@ func (e *Engine) initRuntime() {
 	// Setup console
 	e.setupConsole()
 
+	// Timers and async callbacks
+	e.loop = newEventLoop(e.runtime)
+
 	// Re-register all globals
 	for name, value := range e.globals {
 		e.runtime.Set(name, value)
@@ -105,11 +111,24 @@ func (e *Engine) SetConsoleHandler(handler ConsoleHandler) {
 	e.consoleHandler = handler
 }
 
-// Execute runs a JavaScript script and returns the result.
+// SetTimeLimit bounds the total wall-clock time of each Execute, including
+// waiting for timers and async requests. Zero removes the bound.
+func (e *Engine) SetTimeLimit(limit time.Duration) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.timeLimit = limit
+}
+
+// Execute runs a JavaScript script and returns the result. Execution ends
+// once the script and all the timers, promises and async requests it
+// started have finished.
 func (e *Engine) Execute(ctx context.Context, script string) (interface{}, error) {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
+	ctx, cancel, reason := limitContext(ctx, e.timeLimit)
+	defer cancel()
+
 	// Set up context cancellation handling
 	if ctx.Done() != nil {
 		// Check if already cancelled
@@ -119,14 +138,20 @@ func (e *Engine) Execute(ctx context.Context, script string) (interface{}, error
 		default:
 		}
 
-		// Set up interrupt for long-running scripts
+		// Set up interrupt for long-running scripts. Wait for the watcher
+		// to exit so it can't interrupt a later execution.
 		done := make(chan struct{})
-		defer close(done)
+		exited := make(chan struct{})
+		defer func() {
+			close(done)
+			<-exited
+		}()
 
 		go func() {
+			defer close(exited)
 			select {
 			case <-ctx.Done():
-				e.runtime.Interrupt("context cancelled")
+				e.runtime.Interrupt(reason())
 			case <-done:
 				// Script completed normally
 			}
@@ -142,12 +167,20 @@ func (e *Engine) Execute(ctx context.Context, script string) (interface{}, error
 		return nil, fmt.Errorf("syntax error: %w", err)
 	}
 
-	value, err := e.runtime.RunProgram(program)
+	var value goja.Value
+	if e.loop != nil {
+		value, err = e.loop.runScript(ctx, program)
+	} else {
+		value, err = e.runtime.RunProgram(program)
+	}
 	if err != nil {
 		// Check if it was an interrupt
 		if exception, ok := err.(*goja.InterruptedError); ok {
 			return nil, fmt.Errorf("execution interrupted: %v", exception.Value())
 		}
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("execution interrupted: %s", reason())
+		}
 		return nil, fmt.Errorf("runtime error: %w", err)
 	}
 
@@ -217,6 +250,7 @@ func (e *Engine) Clone() *Engine {
 		globals:        make(map[string]interface{}),
 		functions:      make(map[string]interface{}),
 		consoleHandler: e.consoleHandler,
+		timeLimit:      e.timeLimit,
 	}
 
 	// Copy globals
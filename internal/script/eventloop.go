@@ -0,0 +1,312 @@
+package script
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/dop251/goja"
+)
+
+// DefaultTimeLimit bounds how long a script may run, including the time it
+// spends waiting for its timers and asynchronous requests.
+const DefaultTimeLimit = 30 * time.Second
+
+// maxTimers caps the number of timers a script may have pending.
+const maxTimers = 1000
+
+// errIterationLimit is returned when a sandboxed script runs more timer and
+// async callbacks than its iteration limit allows.
+var errIterationLimit = errors.New("iteration limit exceeded")
+
+// timer is a pending setTimeout, setInterval or setImmediate callback.
+type timer struct {
+	id       int64
+	when     time.Time
+	interval time.Duration // Zero for one-shot timers
+	fn       goja.Callable
+	args     []goja.Value
+}
+
+// eventLoop runs the timers and asynchronous callbacks a script schedules.
+// JavaScript only runs on the goroutine executing the script; background
+// work hands its result back through post. Promise jobs are drained by goja
+// whenever a callback returns.
+type eventLoop struct {
+	rt      *goja.Runtime
+	timers  map[int64]*timer
+	nextID  int64
+	pending int // Async operations whose result hasn't been handled yet
+
+	// Callbacks posted by background goroutines
+	mu         sync.Mutex
+	queue      []func() error
+	generation int // Bumped on reset so late results of old runs are dropped
+	wakeup     chan struct{}
+
+	callbackLimit int64 // Maximum callbacks per run, 0 for no limit
+	callbacks     int64
+
+	rejections []*goja.Promise // Rejected promises nobody handled yet
+}
+
+// newEventLoop creates a loop and installs the timer globals in rt.
+func newEventLoop(rt *goja.Runtime) *eventLoop {
+	l := &eventLoop{
+		rt:     rt,
+		timers: make(map[int64]*timer),
+		wakeup: make(chan struct{}, 1),
+	}
+	rt.Set("setTimeout", l.setTimer(false))
+	rt.Set("setInterval", l.setTimer(true))
+	rt.Set("setImmediate", func(call goja.FunctionCall) goja.Value {
+		args := append([]goja.Value{call.Argument(0), l.rt.ToValue(0)}, call.Arguments[min(1, len(call.Arguments)):]...)
+		return l.setTimer(false)(goja.FunctionCall{This: call.This, Arguments: args})
+	})
+	rt.Set("clearTimeout", l.clearTimer)
+	rt.Set("clearInterval", l.clearTimer)
+	rt.Set("clearImmediate", l.clearTimer)
+	rt.SetPromiseRejectionTracker(l.trackRejection)
+	return l
+}
+
+// setTimer returns setTimeout(fn, delay, ...args) or, when repeat is set,
+// setInterval.
+func (l *eventLoop) setTimer(repeat bool) func(goja.FunctionCall) goja.Value {
+	return func(call goja.FunctionCall) goja.Value {
+		fn, ok := goja.AssertFunction(call.Argument(0))
+		if !ok {
+			panic(l.rt.NewTypeError("callback must be a function"))
+		}
+		if len(l.timers) >= maxTimers {
+			panic(l.rt.NewGoError(fmt.Errorf("too many pending timers (max %d)", maxTimers)))
+		}
+
+		delay := time.Duration(call.Argument(1).ToInteger()) * time.Millisecond
+		if delay < 0 {
+			delay = 0
+		}
+		t := &timer{fn: fn, when: time.Now().Add(delay)}
+		if repeat {
+			t.interval = max(delay, time.Millisecond)
+		}
+		if len(call.Arguments) > 2 {
+			t.args = append([]goja.Value(nil), call.Arguments[2:]...)
+		}
+
+		l.nextID++
+		t.id = l.nextID
+		l.timers[t.id] = t
+		return l.rt.ToValue(t.id)
+	}
+}
+
+// clearTimer cancels a timer by the ID its set function returned.
+func (l *eventLoop) clearTimer(call goja.FunctionCall) goja.Value {
+	delete(l.timers, call.Argument(0).ToInteger())
+	return goja.Undefined()
+}
+
+// trackRejection records promises rejected without a handler, so a failed
+// async script doesn't pass silently.
+func (l *eventLoop) trackRejection(p *goja.Promise, op goja.PromiseRejectionOperation) {
+	switch op {
+	case goja.PromiseRejectionReject:
+		l.rejections = append(l.rejections, p)
+	case goja.PromiseRejectionHandle:
+		for i, r := range l.rejections {
+			if r == p {
+				l.rejections = append(l.rejections[:i], l.rejections[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// goAsync runs work on a new goroutine and then done on the loop with its
+// result. The loop doesn't finish until done has run. Must be called from
+// JavaScript.
+func (l *eventLoop) goAsync(work func() (interface{}, error), done func(interface{}, error) error) {
+	l.pending++
+	l.mu.Lock()
+	generation := l.generation
+	l.mu.Unlock()
+
+	go func() {
+		result, err := work()
+		l.post(generation, func() error {
+			return done(result, err)
+		})
+	}()
+}
+
+// post queues fn to run on the loop.
+func (l *eventLoop) post(generation int, fn func() error) {
+	l.mu.Lock()
+	if generation != l.generation {
+		l.mu.Unlock()
+		return
+	}
+	l.queue = append(l.queue, fn)
+	l.mu.Unlock()
+
+	select {
+	case l.wakeup <- struct{}{}:
+	default:
+	}
+}
+
+// nextTimer returns the timer due first, or nil.
+func (l *eventLoop) nextTimer() *timer {
+	var next *timer
+	for _, t := range l.timers {
+		if next == nil || t.when.Before(next.when) || (t.when.Equal(next.when) && t.id < next.id) {
+			next = t
+		}
+	}
+	return next
+}
+
+// callback counts a callback against the limit and runs it.
+func (l *eventLoop) callback(fn func() error) error {
+	l.callbacks++
+	if l.callbackLimit > 0 && l.callbacks > l.callbackLimit {
+		return errIterationLimit
+	}
+	return fn()
+}
+
+// fire runs a due timer, rescheduling intervals.
+func (l *eventLoop) fire(t *timer) error {
+	if t.interval > 0 {
+		t.when = time.Now().Add(t.interval)
+	} else {
+		delete(l.timers, t.id)
+	}
+	return l.callback(func() error {
+		_, err := t.fn(goja.Undefined(), t.args...)
+		return err
+	})
+}
+
+// run processes timers and async results until nothing is pending, a
+// callback throws, or ctx ends. Whatever is still pending when it returns is
+// discarded.
+func (l *eventLoop) run(ctx context.Context) error {
+	defer l.reset()
+
+	for {
+		l.mu.Lock()
+		queue := l.queue
+		l.queue = nil
+		l.mu.Unlock()
+
+		for _, fn := range queue {
+			l.pending--
+			if err := l.callback(fn); err != nil {
+				return err
+			}
+		}
+		if len(queue) > 0 {
+			continue
+		}
+
+		next := l.nextTimer()
+		if next == nil && l.pending == 0 {
+			return l.unhandledRejection()
+		}
+
+		wait := time.Hour
+		if next != nil {
+			wait = time.Until(next.when)
+			if wait <= 0 {
+				if err := l.fire(next); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		t := time.NewTimer(wait)
+		select {
+		case <-t.C:
+		case <-l.wakeup:
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		}
+		t.Stop()
+	}
+}
+
+// runScript runs a compiled script followed by the loop, and returns the
+// script's completion value.
+func (l *eventLoop) runScript(ctx context.Context, program *goja.Program) (goja.Value, error) {
+	value, err := l.rt.RunProgram(program)
+	if err != nil {
+		l.reset()
+		return nil, err
+	}
+	if err := l.run(ctx); err != nil {
+		return nil, err
+	}
+	return settle(value)
+}
+
+// unhandledRejection reports the first rejected promise nobody handled.
+func (l *eventLoop) unhandledRejection() error {
+	if len(l.rejections) == 0 {
+		return nil
+	}
+	reason := l.rejections[0].Result()
+	if obj, ok := reason.(*goja.Object); ok {
+		if msg := obj.Get("message"); msg != nil && !goja.IsUndefined(msg) {
+			return fmt.Errorf("unhandled promise rejection: %s", msg.String())
+		}
+	}
+	return fmt.Errorf("unhandled promise rejection: %v", reason)
+}
+
+// reset drops pending timers and results, ready for the next script.
+func (l *eventLoop) reset() {
+	l.timers = make(map[int64]*timer)
+	l.pending = 0
+	l.callbacks = 0
+	l.rejections = nil
+
+	l.mu.Lock()
+	l.queue = nil
+	l.generation++
+	l.mu.Unlock()
+}
+
+// settle returns the result of a fulfilled promise in place of the
+// promise, so a script ending in an async call yields the call's value.
+// Rejections are already reported by the loop.
+func settle(value goja.Value) (goja.Value, error) {
+	if obj, ok := value.(*goja.Object); ok {
+		if p, ok := obj.Export().(*goja.Promise); ok && p.State() == goja.PromiseStateFulfilled {
+			return p.Result(), nil
+		}
+	}
+	return value, nil
+}
+
+// limitContext applies a time limit to ctx. The returned reason describes
+// why ctx ended, for interrupt errors.
+func limitContext(ctx context.Context, limit time.Duration) (context.Context, context.CancelFunc, func() string) {
+	parent := ctx
+	cancel := context.CancelFunc(func() {})
+	if limit > 0 {
+		ctx, cancel = context.WithTimeout(ctx, limit)
+	}
+	reason := func() string {
+		if parent.Err() == nil && ctx.Err() != nil {
+			return fmt.Sprintf("time limit of %s exceeded", limit)
+		}
+		return "context cancelled"
+	}
+	return ctx, cancel, reason
+}
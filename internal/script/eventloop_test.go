@@ -0,0 +1,208 @@
+package script
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventLoop_Timers(t *testing.T) {
+	tests := []struct {
+		name     string
+		script   string
+		expected interface{}
+	}{
+		{"runs timeouts in order", `
+			var order = [];
+			setTimeout(function() { order.push("b"); }, 20);
+			setTimeout(function() { order.push("a"); }, 5);
+			setImmediate(function() { order.push("now"); });
+			setTimeout(function() { order.push("args " + [].slice.call(arguments).join(",")); }, 30, 1, 2);
+			order`, []interface{}{"now", "a", "b", "args 1,2"}},
+		{"clearTimeout cancels", `
+			var state = {fired: false};
+			clearTimeout(setTimeout(function() { state.fired = true; }, 1));
+			state`, map[string]interface{}{"fired": false}},
+		{"intervals repeat until cleared", `
+			var state = {count: 0};
+			var id = setInterval(function() { if (++state.count === 3) clearInterval(id); }, 1);
+			state`, map[string]interface{}{"count": int64(3)}},
+		{"promise jobs drain", `
+			var log = [];
+			Promise.resolve(1).then(function(v) { log.push(v); return v + 1; }).then(function(v) { log.push(v); });
+			log`, []interface{}{int64(1), int64(2)}},
+		{"async functions await timers", `
+			function sleep(ms) { return new Promise(function(resolve) { setTimeout(resolve, ms); }); }
+			(async function() { await sleep(5); return "done"; })()`, "done"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := NewEngine()
+
+			// Objects are exported after the loop, so they show what the
+			// callbacks did
+			result, err := engine.Execute(context.Background(), tt.script)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+
+	t.Run("timer errors fail the script", func(t *testing.T) {
+		engine := NewEngine()
+
+		_, err := engine.Execute(context.Background(), `setTimeout(function() { throw new Error("boom"); }, 1)`)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "boom")
+	})
+
+	t.Run("unhandled rejections fail the script", func(t *testing.T) {
+		engine := NewEngine()
+
+		_, err := engine.Execute(context.Background(), `(async function() { throw new Error("async boom"); })()`)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unhandled promise rejection: async boom")
+	})
+
+	t.Run("pending timers don't leak into the next execution", func(t *testing.T) {
+		engine := NewEngine()
+		_, err := engine.Execute(context.Background(), `var fired = false; setTimeout(function() { fired = true; }, 1); throw new Error("stop");`)
+		require.Error(t, err)
+
+		result, err := engine.Execute(context.Background(), `fired`)
+
+		require.NoError(t, err)
+		assert.Equal(t, false, result)
+	})
+}
+
+func TestEventLoop_Limits(t *testing.T) {
+	t.Run("ExecuteWithTimeout bounds pending timers", func(t *testing.T) {
+		engine := NewEngine()
+		start := time.Now()
+
+		_, err := engine.ExecuteWithTimeout(`setInterval(function() {}, 5)`, 50*time.Millisecond)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "execution interrupted")
+		assert.Less(t, time.Since(start), time.Second)
+	})
+
+	t.Run("time limit bounds the whole execution", func(t *testing.T) {
+		engine := NewEngine()
+		engine.SetTimeLimit(30 * time.Millisecond)
+
+		_, err := engine.Execute(context.Background(), `setTimeout(function() {}, 10000)`)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "time limit of 30ms exceeded")
+	})
+
+	t.Run("sandbox iteration limit caps callbacks", func(t *testing.T) {
+		scope := NewSandboxedScope()
+		scope.SetIterationLimit(10)
+
+		_, err := scope.Execute(context.Background(), `setInterval(function() {}, 1)`)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "iteration limit exceeded")
+	})
+
+	t.Run("sandbox honours context timeout", func(t *testing.T) {
+		scope := NewSandboxedScope()
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+		defer cancel()
+
+		_, err := scope.Execute(ctx, `setTimeout(function() {}, 10000)`)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "context cancelled")
+	})
+}
+
+func TestEventLoop_SendRequest(t *testing.T) {
+	sender := func(options map[string]interface{}) (map[string]interface{}, error) {
+		if options["url"] == "http://fail" {
+			return nil, errors.New("connection refused")
+		}
+		time.Sleep(5 * time.Millisecond)
+		return map[string]interface{}{"code": 200, "body": options["url"]}, nil
+	}
+
+	t.Run("callback receives the response", func(t *testing.T) {
+		scope := NewScopeWithAssertions()
+		scope.SetRequestSender(sender)
+
+		_, err := scope.Execute(context.Background(), `
+			currier.sendRequest("http://example.com", function(err, res) {
+				currier.test("callback", function() {
+					currier.expect(err).toBeNull();
+					currier.expect(res.body).toBe("http://example.com");
+				});
+			});
+			currier.sendRequest({url: "http://fail"}, function(err, res) {
+				currier.test("callback error", function() {
+					currier.expect(err.message).toContain("connection refused");
+					currier.expect(res).toBeNull();
+				});
+			});
+		`)
+
+		require.NoError(t, err)
+		results := scope.GetTestResults()
+		require.Len(t, results, 2)
+		for _, r := range results {
+			assert.True(t, r.Passed, "%s: %s", r.Name, r.Error)
+		}
+	})
+
+	t.Run("async tests", func(t *testing.T) {
+		scope := NewScopeWithAssertions()
+		scope.SetRequestSender(sender)
+
+		_, err := scope.Execute(context.Background(), `
+			function get(url) {
+				return new Promise(function(resolve, reject) {
+					currier.sendRequest(url, function(err, res) { err ? reject(err) : resolve(res); });
+				});
+			}
+			currier.test("awaits", async function() {
+				var res = await get("http://example.com");
+				currier.expect(res.code).toBe(200);
+			});
+			currier.test("rejected", async function() {
+				await get("http://fail");
+			});
+		`)
+
+		require.NoError(t, err)
+		// Async tests are recorded as they settle
+		results := make(map[string]TestResult)
+		for _, r := range scope.GetTestResults() {
+			results[r.Name] = r
+		}
+		require.Len(t, results, 2)
+		assert.True(t, results["awaits"].Passed)
+		assert.False(t, results["rejected"].Passed)
+		assert.Contains(t, results["rejected"].Error, "connection refused")
+	})
+
+	t.Run("callback without a sender", func(t *testing.T) {
+		scope := NewScope()
+
+		result, err := scope.Execute(context.Background(), `
+			var state = {};
+			currier.sendRequest("http://example.com", function(err) { state.message = err.message; });
+			state`)
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{"message": "sendRequest is not available in this context"}, result)
+	})
+}
@@ -0,0 +1,753 @@
+package script
+
+// builtinModules holds the source of the modules require() resolves by bare
+// name. They cover the parts of the npm packages of the same names that
+// request scripts commonly use.
+var builtinModules = map[string]string{
+	"lodash":      lodashModule,
+	"moment":      momentModule,
+	"uuid":        uuidModule,
+	"querystring": querystringModule,
+	"crypto-js":   "module.exports = CryptoJS;",
+}
+
+const lodashModule = `
+var _ = {};
+
+function isObjectLike(v) { return v !== null && typeof v === 'object'; }
+
+function toPath(path) {
+	if (Array.isArray(path)) return path;
+	var parts = [];
+	String(path).replace(/[^.[\]]+|\[(?:(\d+)|(["'])(.*?)\2)\]/g, function(match, index, quote, key) {
+		parts.push(index !== undefined ? index : (quote ? key : match));
+	});
+	return parts;
+}
+
+function iteratee(fn) {
+	if (typeof fn === 'function') return fn;
+	if (fn === undefined || fn === null) return function(v) { return v; };
+	if (typeof fn === 'string' || typeof fn === 'number') return function(v) { return _.get(v, fn); };
+	if (Array.isArray(fn)) return function(v) { return _.isEqual(_.get(v, fn[0]), fn[1]); };
+	return function(v) {
+		for (var k in fn) {
+			if (!_.isEqual(_.get(v, k), fn[k])) return false;
+		}
+		return true;
+	};
+}
+
+function eachOf(collection, fn) {
+	if (Array.isArray(collection) || typeof collection === 'string') {
+		for (var i = 0; i < collection.length; i++) {
+			if (fn(collection[i], i, collection) === false) return;
+		}
+	} else if (isObjectLike(collection)) {
+		var keys = Object.keys(collection);
+		for (var j = 0; j < keys.length; j++) {
+			if (fn(collection[keys[j]], keys[j], collection) === false) return;
+		}
+	}
+}
+
+function words(s) {
+	return String(s)
+		.replace(/([a-z0-9])([A-Z])/g, '$1 $2')
+		.split(/[^A-Za-z0-9]+/)
+		.filter(function(w) { return w.length > 0; });
+}
+
+_.get = function(obj, path, defaultValue) {
+	var parts = toPath(path);
+	var v = obj;
+	for (var i = 0; i < parts.length; i++) {
+		if (v === null || v === undefined) return defaultValue;
+		v = v[parts[i]];
+	}
+	return v === undefined ? defaultValue : v;
+};
+
+_.set = function(obj, path, value) {
+	var parts = toPath(path);
+	var v = obj;
+	for (var i = 0; i < parts.length - 1; i++) {
+		if (!isObjectLike(v[parts[i]])) {
+			v[parts[i]] = /^\d+$/.test(parts[i + 1]) ? [] : {};
+		}
+		v = v[parts[i]];
+	}
+	v[parts[parts.length - 1]] = value;
+	return obj;
+};
+
+_.has = function(obj, path) {
+	var parts = toPath(path);
+	var v = obj;
+	for (var i = 0; i < parts.length; i++) {
+		if (!isObjectLike(v) || !Object.prototype.hasOwnProperty.call(v, parts[i])) return false;
+		v = v[parts[i]];
+	}
+	return true;
+};
+
+_.pick = function(obj, keys) {
+	keys = Array.isArray(keys) ? keys : Array.prototype.slice.call(arguments, 1);
+	var out = {};
+	keys.forEach(function(k) { if (_.has(obj, k)) _.set(out, k, _.get(obj, k)); });
+	return out;
+};
+
+_.omit = function(obj, keys) {
+	keys = Array.isArray(keys) ? keys : Array.prototype.slice.call(arguments, 1);
+	var out = {};
+	for (var k in obj) {
+		if (keys.indexOf(k) === -1) out[k] = obj[k];
+	}
+	return out;
+};
+
+_.keys = function(obj) { return isObjectLike(obj) ? Object.keys(obj) : []; };
+_.values = function(obj) { return _.keys(obj).map(function(k) { return obj[k]; }); };
+_.entries = _.toPairs = function(obj) { return _.keys(obj).map(function(k) { return [k, obj[k]]; }); };
+_.fromPairs = function(pairs) {
+	var out = {};
+	pairs.forEach(function(p) { out[p[0]] = p[1]; });
+	return out;
+};
+
+_.isArray = Array.isArray;
+_.isString = function(v) { return typeof v === 'string'; };
+_.isNumber = function(v) { return typeof v === 'number'; };
+_.isBoolean = function(v) { return typeof v === 'boolean'; };
+_.isFunction = function(v) { return typeof v === 'function'; };
+_.isObject = function(v) { return v !== null && (typeof v === 'object' || typeof v === 'function'); };
+_.isPlainObject = function(v) { return isObjectLike(v) && !Array.isArray(v); };
+_.isNil = function(v) { return v === null || v === undefined; };
+_.isNull = function(v) { return v === null; };
+_.isUndefined = function(v) { return v === undefined; };
+_.isInteger = function(v) { return typeof v === 'number' && isFinite(v) && Math.floor(v) === v; };
+
+_.isEmpty = function(v) {
+	if (v === null || v === undefined) return true;
+	if (Array.isArray(v) || typeof v === 'string') return v.length === 0;
+	if (typeof v === 'object') return Object.keys(v).length === 0;
+	return true;
+};
+
+_.isEqual = function(a, b) {
+	if (a === b) return true;
+	if (!isObjectLike(a) || !isObjectLike(b)) return a !== a && b !== b;
+	if (Array.isArray(a) !== Array.isArray(b)) return false;
+	var ka = Object.keys(a), kb = Object.keys(b);
+	if (ka.length !== kb.length) return false;
+	for (var i = 0; i < ka.length; i++) {
+		if (!Object.prototype.hasOwnProperty.call(b, ka[i]) || !_.isEqual(a[ka[i]], b[ka[i]])) return false;
+	}
+	return true;
+};
+
+_.clone = function(v) {
+	if (Array.isArray(v)) return v.slice();
+	if (isObjectLike(v)) return _.assign({}, v);
+	return v;
+};
+
+_.cloneDeep = function(v) {
+	if (v instanceof Date) return new Date(v.getTime());
+	if (Array.isArray(v)) return v.map(_.cloneDeep);
+	if (isObjectLike(v)) {
+		var out = {};
+		for (var k in v) out[k] = _.cloneDeep(v[k]);
+		return out;
+	}
+	return v;
+};
+
+_.assign = _.extend = function(target) {
+	for (var i = 1; i < arguments.length; i++) {
+		var src = arguments[i];
+		for (var k in src) {
+			if (Object.prototype.hasOwnProperty.call(src, k)) target[k] = src[k];
+		}
+	}
+	return target;
+};
+
+_.merge = function(target) {
+	for (var i = 1; i < arguments.length; i++) {
+		var src = arguments[i];
+		for (var k in src) {
+			if (_.isPlainObject(src[k]) && _.isPlainObject(target[k])) {
+				_.merge(target[k], src[k]);
+			} else if (src[k] !== undefined) {
+				target[k] = _.cloneDeep(src[k]);
+			}
+		}
+	}
+	return target;
+};
+
+_.defaults = function(target) {
+	for (var i = 1; i < arguments.length; i++) {
+		var src = arguments[i];
+		for (var k in src) {
+			if (target[k] === undefined) target[k] = src[k];
+		}
+	}
+	return target;
+};
+
+_.each = _.forEach = function(collection, fn) {
+	eachOf(collection, fn);
+	return collection;
+};
+
+_.map = function(collection, fn) {
+	var f = iteratee(fn), out = [];
+	eachOf(collection, function(v, k, c) { out.push(f(v, k, c)); });
+	return out;
+};
+
+_.mapValues = function(obj, fn) {
+	var f = iteratee(fn), out = {};
+	eachOf(obj, function(v, k, c) { out[k] = f(v, k, c); });
+	return out;
+};
+
+_.filter = function(collection, fn) {
+	var f = iteratee(fn), out = [];
+	eachOf(collection, function(v, k, c) { if (f(v, k, c)) out.push(v); });
+	return out;
+};
+
+_.reject = function(collection, fn) {
+	var f = iteratee(fn);
+	return _.filter(collection, function(v, k, c) { return !f(v, k, c); });
+};
+
+_.find = function(collection, fn) {
+	var f = iteratee(fn), found;
+	eachOf(collection, function(v, k, c) {
+		if (f(v, k, c)) { found = v; return false; }
+	});
+	return found;
+};
+
+_.findIndex = function(array, fn) {
+	var f = iteratee(fn);
+	for (var i = 0; i < array.length; i++) {
+		if (f(array[i], i, array)) return i;
+	}
+	return -1;
+};
+
+_.some = function(collection, fn) {
+	var f = iteratee(fn), found = false;
+	eachOf(collection, function(v, k, c) {
+		if (f(v, k, c)) { found = true; return false; }
+	});
+	return found;
+};
+_.every = function(collection, fn) {
+	var f = iteratee(fn);
+	return _.filter(collection, function(v, k, c) { return !f(v, k, c); }).length === 0;
+};
+
+_.reduce = function(collection, fn, acc) {
+	var first = arguments.length < 3;
+	eachOf(collection, function(v, k, c) {
+		if (first) { acc = v; first = false; } else { acc = fn(acc, v, k, c); }
+	});
+	return acc;
+};
+
+_.includes = function(collection, value) {
+	if (typeof collection === 'string') return collection.indexOf(value) !== -1;
+	return _.values(collection).some(function(v) { return v === value || (v !== v && value !== value); });
+};
+
+_.size = function(v) {
+	if (Array.isArray(v) || typeof v === 'string') return v.length;
+	return _.keys(v).length;
+};
+
+_.sortBy = function(collection, fns) {
+	fns = (Array.isArray(fns) ? fns : [fns]).map(iteratee);
+	return _.map(collection, function(v, i) { return { v: v, i: i }; }).sort(function(a, b) {
+		for (var j = 0; j < fns.length; j++) {
+			var x = fns[j](a.v), y = fns[j](b.v);
+			if (x < y) return -1;
+			if (x > y) return 1;
+		}
+		return a.i - b.i;
+	}).map(function(e) { return e.v; });
+};
+
+_.orderBy = function(collection, fns, orders) {
+	fns = (Array.isArray(fns) ? fns : [fns]).map(iteratee);
+	orders = orders || [];
+	return _.map(collection, function(v, i) { return { v: v, i: i }; }).sort(function(a, b) {
+		for (var j = 0; j < fns.length; j++) {
+			var x = fns[j](a.v), y = fns[j](b.v), dir = orders[j] === 'desc' ? -1 : 1;
+			if (x < y) return -dir;
+			if (x > y) return dir;
+		}
+		return a.i - b.i;
+	}).map(function(e) { return e.v; });
+};
+
+_.groupBy = function(collection, fn) {
+	var f = iteratee(fn), out = {};
+	eachOf(collection, function(v) {
+		var k = f(v);
+		(out[k] = out[k] || []).push(v);
+	});
+	return out;
+};
+
+_.keyBy = function(collection, fn) {
+	var f = iteratee(fn), out = {};
+	eachOf(collection, function(v) { out[f(v)] = v; });
+	return out;
+};
+
+_.countBy = function(collection, fn) {
+	var f = iteratee(fn), out = {};
+	eachOf(collection, function(v) {
+		var k = f(v);
+		out[k] = (out[k] || 0) + 1;
+	});
+	return out;
+};
+
+_.uniq = function(array) {
+	var out = [];
+	array.forEach(function(v) { if (out.indexOf(v) === -1) out.push(v); });
+	return out;
+};
+
+_.uniqBy = function(array, fn) {
+	var f = iteratee(fn), seen = [], out = [];
+	array.forEach(function(v) {
+		var k = f(v);
+		if (seen.indexOf(k) === -1) { seen.push(k); out.push(v); }
+	});
+	return out;
+};
+
+_.flatten = function(array) {
+	return array.reduce(function(out, v) { return out.concat(v); }, []);
+};
+
+_.flattenDeep = function(array) {
+	return array.reduce(function(out, v) { return out.concat(Array.isArray(v) ? _.flattenDeep(v) : v); }, []);
+};
+
+_.compact = function(array) { return array.filter(Boolean); };
+_.first = _.head = function(array) { return array ? array[0] : undefined; };
+_.last = function(array) { return array ? array[array.length - 1] : undefined; };
+_.difference = function(array, other) { return array.filter(function(v) { return other.indexOf(v) === -1; }); };
+_.intersection = function(array, other) { return _.uniq(array.filter(function(v) { return other.indexOf(v) !== -1; })); };
+_.union = function() { return _.uniq(_.flatten(Array.prototype.slice.call(arguments))); };
+
+_.chunk = function(array, size) {
+	size = Math.max(size || 1, 1);
+	var out = [];
+	for (var i = 0; i < array.length; i += size) out.push(array.slice(i, i + size));
+	return out;
+};
+
+_.range = function(start, end, step) {
+	if (end === undefined) { end = start; start = 0; }
+	step = step || (end < start ? -1 : 1);
+	var out = [];
+	for (var i = start; step > 0 ? i < end : i > end; i += step) out.push(i);
+	return out;
+};
+
+_.times = function(n, fn) {
+	var f = iteratee(fn), out = [];
+	for (var i = 0; i < n; i++) out.push(f(i));
+	return out;
+};
+
+_.sum = function(array) { return array.reduce(function(a, b) { return a + b; }, 0); };
+_.sumBy = function(array, fn) { return _.sum(_.map(array, fn)); };
+_.max = function(array) { return array.length ? Math.max.apply(null, array) : undefined; };
+_.min = function(array) { return array.length ? Math.min.apply(null, array) : undefined; };
+_.mean = function(array) { return array.length ? _.sum(array) / array.length : NaN; };
+_.clamp = function(n, lower, upper) { return Math.min(Math.max(n, lower), upper); };
+
+_.random = function(lower, upper, floating) {
+	if (upper === undefined) { upper = lower === undefined ? 1 : lower; lower = 0; }
+	if (floating || lower % 1 !== 0 || upper % 1 !== 0) return lower + Math.random() * (upper - lower);
+	return lower + Math.floor(Math.random() * (upper - lower + 1));
+};
+
+_.shuffle = function(collection) {
+	var out = _.values(collection);
+	for (var i = out.length - 1; i > 0; i--) {
+		var j = Math.floor(Math.random() * (i + 1));
+		var t = out[i]; out[i] = out[j]; out[j] = t;
+	}
+	return out;
+};
+
+_.sample = function(collection) {
+	var values = _.values(collection);
+	return values[Math.floor(Math.random() * values.length)];
+};
+
+_.camelCase = function(s) {
+	return words(s).map(function(w, i) {
+		w = w.toLowerCase();
+		return i === 0 ? w : w.charAt(0).toUpperCase() + w.slice(1);
+	}).join('');
+};
+_.snakeCase = function(s) { return words(s).map(function(w) { return w.toLowerCase(); }).join('_'); };
+_.kebabCase = function(s) { return words(s).map(function(w) { return w.toLowerCase(); }).join('-'); };
+_.startCase = function(s) { return words(s).map(function(w) { return w.charAt(0).toUpperCase() + w.slice(1); }).join(' '); };
+_.capitalize = function(s) { s = String(s); return s.charAt(0).toUpperCase() + s.slice(1).toLowerCase(); };
+_.upperFirst = function(s) { s = String(s); return s.charAt(0).toUpperCase() + s.slice(1); };
+_.trim = function(s) { return String(s).trim(); };
+
+function padding(length, chars) {
+	chars = chars === undefined ? ' ' : String(chars);
+	var out = '';
+	while (out.length < length && chars) out += chars;
+	return out.slice(0, Math.max(length, 0));
+}
+_.pad = function(s, length, chars) {
+	s = String(s);
+	var total = length - s.length;
+	var left = Math.floor(total / 2);
+	return padding(left, chars) + s + padding(total - left, chars);
+};
+_.padStart = function(s, length, chars) { s = String(s); return padding(length - s.length, chars) + s; };
+_.padEnd = function(s, length, chars) { s = String(s); return s + padding(length - s.length, chars); };
+
+_.escape = function(s) {
+	return String(s).replace(/&/g, '&amp;').replace(/</g, '&lt;').replace(/>/g, '&gt;')
+		.replace(/"/g, '&quot;').replace(/'/g, '&#39;');
+};
+
+var idCounter = 0;
+_.uniqueId = function(prefix) { idCounter++; return (prefix || '') + idCounter; };
+_.identity = function(v) { return v; };
+_.noop = function() {};
+
+module.exports = _;
+`
+
+const momentModule = `
+var UNITS = {
+	ms: 'millisecond', millisecond: 'millisecond', milliseconds: 'millisecond',
+	s: 'second', second: 'second', seconds: 'second',
+	m: 'minute', minute: 'minute', minutes: 'minute',
+	h: 'hour', hour: 'hour', hours: 'hour',
+	d: 'day', day: 'day', days: 'day',
+	w: 'week', week: 'week', weeks: 'week',
+	M: 'month', month: 'month', months: 'month',
+	y: 'year', year: 'year', years: 'year'
+};
+var MS = { millisecond: 1, second: 1e3, minute: 6e4, hour: 36e5, day: 864e5, week: 6048e5 };
+var MONTHS = ['January', 'February', 'March', 'April', 'May', 'June', 'July', 'August', 'September', 'October', 'November', 'December'];
+var DAYS = ['Sunday', 'Monday', 'Tuesday', 'Wednesday', 'Thursday', 'Friday', 'Saturday'];
+
+function pad(n, width) {
+	var s = String(Math.abs(n));
+	while (s.length < width) s = '0' + s;
+	return (n < 0 ? '-' : '') + s;
+}
+
+function normalizeUnit(unit) {
+	var u = UNITS[unit] || UNITS[String(unit).toLowerCase()];
+	if (!u) throw new Error('moment: unknown unit ' + unit);
+	return u;
+}
+
+function Moment(date, utc) {
+	this._d = date;
+	this._utc = !!utc;
+}
+
+function parse(input, format) {
+	if (input === undefined) return new Date();
+	if (input instanceof Moment) return new Date(input.valueOf());
+	if (input instanceof Date) return new Date(input.getTime());
+	if (typeof input === 'number') return new Date(input);
+	if (format) return parseFormat(String(input), format);
+	return new Date(String(input));
+}
+
+// parseFormat reads dates written with the numeric YYYY MM DD HH mm ss tokens.
+function parseFormat(input, format) {
+	var parts = { YYYY: 1970, MM: 1, DD: 1, HH: 0, mm: 0, ss: 0 };
+	var tokens = format.match(/YYYY|MM|DD|HH|mm|ss|./g);
+	var pos = 0;
+	for (var i = 0; i < tokens.length; i++) {
+		var t = tokens[i];
+		if (parts.hasOwnProperty(t)) {
+			var len = t.length;
+			var n = parseInt(input.substr(pos, len), 10);
+			if (isNaN(n)) return new Date(NaN);
+			parts[t] = n;
+			pos += len;
+		} else {
+			pos += t.length;
+		}
+	}
+	return new Date(parts.YYYY, parts.MM - 1, parts.DD, parts.HH, parts.mm, parts.ss);
+}
+
+function moment(input, format) {
+	return new Moment(parse(input, format), false);
+}
+
+moment.utc = function(input, format) {
+	var d = parse(input, format);
+	if (format) d = new Date(d.getTime() - d.getTimezoneOffset() * 6e4);
+	return new Moment(d, true);
+};
+
+moment.unix = function(seconds) {
+	return new Moment(new Date(seconds * 1000), false);
+};
+
+moment.isMoment = function(v) {
+	return v instanceof Moment;
+};
+
+moment.duration = function(n, unit) {
+	var ms = n * MS[normalizeUnit(unit || 'ms')];
+	return {
+		asMilliseconds: function() { return ms; },
+		asSeconds: function() { return ms / 1e3; },
+		asMinutes: function() { return ms / 6e4; },
+		asHours: function() { return ms / 36e5; },
+		asDays: function() { return ms / 864e5; }
+	};
+};
+
+Moment.prototype._get = function(name) {
+	return this._d[(this._utc ? 'getUTC' : 'get') + name]();
+};
+
+Moment.prototype._set = function(name, value) {
+	this._d[(this._utc ? 'setUTC' : 'set') + name](value);
+};
+
+Moment.prototype.isValid = function() { return !isNaN(this._d.getTime()); };
+Moment.prototype.valueOf = function() { return this._d.getTime(); };
+Moment.prototype.unix = function() { return Math.floor(this._d.getTime() / 1000); };
+Moment.prototype.toDate = function() { return new Date(this._d.getTime()); };
+Moment.prototype.toISOString = function() { return this._d.toISOString(); };
+Moment.prototype.toJSON = Moment.prototype.toISOString;
+Moment.prototype.toString = function() { return this.format('ddd MMM DD YYYY HH:mm:ss [GMT]ZZ'); };
+Moment.prototype.clone = function() { return new Moment(new Date(this._d.getTime()), this._utc); };
+Moment.prototype.utc = function() { return new Moment(new Date(this._d.getTime()), true); };
+Moment.prototype.local = function() { return new Moment(new Date(this._d.getTime()), false); };
+
+Moment.prototype.year = function() { return this._get('FullYear'); };
+Moment.prototype.month = function() { return this._get('Month'); };
+Moment.prototype.date = function() { return this._get('Date'); };
+Moment.prototype.day = function() { return this._get('Day'); };
+Moment.prototype.hour = function() { return this._get('Hours'); };
+Moment.prototype.minute = function() { return this._get('Minutes'); };
+Moment.prototype.second = function() { return this._get('Seconds'); };
+Moment.prototype.millisecond = function() { return this._get('Milliseconds'); };
+
+Moment.prototype.add = function(n, unit) {
+	if (typeof n === 'object') {
+		for (var k in n) this.add(n[k], k);
+		return this;
+	}
+	unit = normalizeUnit(unit || 'ms');
+	if (unit === 'month' || unit === 'year') {
+		var months = unit === 'year' ? n * 12 : n;
+		var day = this.date();
+		this._set('Date', 1);
+		this._set('Month', this.month() + months);
+		var last = new Date(Date.UTC(this.year(), this.month() + 1, 0)).getUTCDate();
+		this._set('Date', Math.min(day, last));
+	} else if (unit === 'day' || unit === 'week') {
+		this._set('Date', this.date() + (unit === 'week' ? n * 7 : n));
+	} else {
+		this._d = new Date(this._d.getTime() + n * MS[unit]);
+	}
+	return this;
+};
+
+Moment.prototype.subtract = function(n, unit) {
+	if (typeof n === 'object') {
+		for (var k in n) this.add(-n[k], k);
+		return this;
+	}
+	return this.add(-n, unit);
+};
+
+Moment.prototype.startOf = function(unit) {
+	unit = normalizeUnit(unit);
+	switch (unit) {
+	case 'year': this._set('Month', 0); /* falls through */
+	case 'month': this._set('Date', 1); /* falls through */
+	case 'week':
+	case 'day':
+		if (unit === 'week') this._set('Date', this.date() - this.day());
+		this._set('Hours', 0); /* falls through */
+	case 'hour': this._set('Minutes', 0); /* falls through */
+	case 'minute': this._set('Seconds', 0); /* falls through */
+	case 'second': this._set('Milliseconds', 0);
+	}
+	return this;
+};
+
+Moment.prototype.endOf = function(unit) {
+	return this.startOf(unit).add(1, unit).subtract(1, 'ms');
+};
+
+Moment.prototype.diff = function(other, unit, precise) {
+	var b = moment(other);
+	var result;
+	unit = normalizeUnit(unit || 'ms');
+	if (unit === 'month' || unit === 'year') {
+		result = (this.year() - b.year()) * 12 + (this.month() - b.month());
+		var anchor = b.clone().add(result, 'month');
+		if (anchor.valueOf() > this.valueOf() && result > 0) result--;
+		if (anchor.valueOf() < this.valueOf() && result < 0) result++;
+		if (unit === 'year') result = result / 12;
+	} else {
+		result = (this.valueOf() - b.valueOf()) / MS[unit];
+	}
+	return precise ? result : (result < 0 ? Math.ceil(result) : Math.floor(result));
+};
+
+Moment.prototype.isBefore = function(other) { return this.valueOf() < moment(other).valueOf(); };
+Moment.prototype.isAfter = function(other) { return this.valueOf() > moment(other).valueOf(); };
+Moment.prototype.isSame = function(other) { return this.valueOf() === moment(other).valueOf(); };
+
+Moment.prototype.utcOffset = function() {
+	return this._utc ? 0 : -this._d.getTimezoneOffset();
+};
+
+Moment.prototype.format = function(pattern) {
+	if (!this.isValid()) return 'Invalid date';
+	var self = this;
+	var offset = this.utcOffset();
+	var zone = function(sep) {
+		return (offset < 0 ? '-' : '+') + pad(Math.floor(Math.abs(offset) / 60), 2) + sep + pad(Math.abs(offset) % 60, 2);
+	};
+	var h12 = function() { return self.hour() % 12 || 12; };
+	var tokens = {
+		YYYY: function() { return pad(self.year(), 4); },
+		YY: function() { return pad(self.year() % 100, 2); },
+		MMMM: function() { return MONTHS[self.month()]; },
+		MMM: function() { return MONTHS[self.month()].slice(0, 3); },
+		MM: function() { return pad(self.month() + 1, 2); },
+		M: function() { return String(self.month() + 1); },
+		DD: function() { return pad(self.date(), 2); },
+		D: function() { return String(self.date()); },
+		dddd: function() { return DAYS[self.day()]; },
+		ddd: function() { return DAYS[self.day()].slice(0, 3); },
+		HH: function() { return pad(self.hour(), 2); },
+		H: function() { return String(self.hour()); },
+		hh: function() { return pad(h12(), 2); },
+		h: function() { return String(h12()); },
+		mm: function() { return pad(self.minute(), 2); },
+		m: function() { return String(self.minute()); },
+		ss: function() { return pad(self.second(), 2); },
+		s: function() { return String(self.second()); },
+		SSS: function() { return pad(self.millisecond(), 3); },
+		A: function() { return self.hour() < 12 ? 'AM' : 'PM'; },
+		a: function() { return self.hour() < 12 ? 'am' : 'pm'; },
+		ZZ: function() { return zone(''); },
+		Z: function() { return zone(':'); },
+		X: function() { return String(self.unix()); },
+		x: function() { return String(self.valueOf()); }
+	};
+	pattern = pattern || (this._utc ? 'YYYY-MM-DDTHH:mm:ss[Z]' : 'YYYY-MM-DDTHH:mm:ssZ');
+	return pattern.replace(/\[([^\]]*)\]|YYYY|YY|MMMM|MMM|MM|M|DD|D|dddd|ddd|HH|H|hh|h|mm|m|ss|s|SSS|A|a|ZZ|Z|X|x/g, function(match, literal) {
+		if (literal !== undefined) return literal;
+		return tokens[match]();
+	});
+};
+
+module.exports = moment;
+`
+
+const uuidModule = `
+var PATTERN = /^[0-9a-f]{8}-[0-9a-f]{4}-[1-8][0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$/i;
+
+module.exports = {
+	v4: function() { return __native.uuid(); },
+	validate: function(s) { return typeof s === 'string' && PATTERN.test(s); },
+	NIL: '00000000-0000-0000-0000-000000000000'
+};
+`
+
+const querystringModule = `
+function escape(s) {
+	return encodeURIComponent(s);
+}
+
+function unescape(s) {
+	try {
+		return decodeURIComponent(String(s).replace(/\+/g, ' '));
+	} catch (e) {
+		return s;
+	}
+}
+
+function stringifyValue(v) {
+	if (typeof v === 'string') return v;
+	if (typeof v === 'number' && isFinite(v)) return String(v);
+	if (typeof v === 'boolean') return v ? 'true' : 'false';
+	return '';
+}
+
+function stringify(obj, sep, eq) {
+	sep = sep || '&';
+	eq = eq || '=';
+	if (obj === null || typeof obj !== 'object') return '';
+	var parts = [];
+	Object.keys(obj).forEach(function(k) {
+		var v = obj[k];
+		var values = Array.isArray(v) ? v : [v];
+		values.forEach(function(item) {
+			parts.push(escape(k) + eq + escape(stringifyValue(item)));
+		});
+	});
+	return parts.join(sep);
+}
+
+function parse(str, sep, eq) {
+	sep = sep || '&';
+	eq = eq || '=';
+	var out = {};
+	if (typeof str !== 'string' || str.length === 0) return out;
+	str.split(sep).forEach(function(pair) {
+		if (!pair) return;
+		var i = pair.indexOf(eq);
+		var k = unescape(i >= 0 ? pair.slice(0, i) : pair);
+		var v = i >= 0 ? unescape(pair.slice(i + eq.length)) : '';
+		if (!Object.prototype.hasOwnProperty.call(out, k)) {
+			out[k] = v;
+		} else if (Array.isArray(out[k])) {
+			out[k].push(v);
+		} else {
+			out[k] = [out[k], v];
+		}
+	});
+	return out;
+}
+
+module.exports = {
+	parse: parse,
+	decode: parse,
+	stringify: stringify,
+	encode: stringify,
+	escape: escape,
+	unescape: unescape
+};
+`
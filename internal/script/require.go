@@ -0,0 +1,290 @@
+package script
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/dop251/goja"
+	"github.com/google/uuid"
+)
+
+// maxModuleSize caps the size of a local module file.
+const maxModuleSize = 1 << 20
+
+// ModuleLoader resolves require() calls in scripts. Relative paths such as
+// "./lib/helpers.js" load files below the root directory; bare names load
+// built-in modules (lodash, moment, uuid, querystring). Compiled modules are
+// cached for the loader's lifetime, so a collection run shares one loader
+// across its requests while each script scope evaluates a module once.
+type ModuleLoader struct {
+	root string
+
+	mu       sync.Mutex
+	programs map[string]*goja.Program // Compiled local modules by path
+}
+
+// NewModuleLoader creates a loader for modules below root, usually the
+// collection's directory. An empty root allows only built-in modules.
+func NewModuleLoader(root string) *ModuleLoader {
+	if root != "" {
+		if abs, err := filepath.Abs(root); err == nil {
+			root = abs
+		}
+	}
+	return &ModuleLoader{
+		root:     root,
+		programs: make(map[string]*goja.Program),
+	}
+}
+
+// Root returns the directory local modules are resolved against.
+func (l *ModuleLoader) Root() string {
+	return l.root
+}
+
+// resolve returns the absolute path of a local module required from dir.
+// Paths may omit the .js extension or name a directory with an index.js.
+func (l *ModuleLoader) resolve(dir, name string) (string, error) {
+	if l.root == "" {
+		return "", fmt.Errorf("cannot require %q: local modules need a collection directory", name)
+	}
+
+	path := name
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, filepath.FromSlash(name))
+	}
+	path = filepath.Clean(path)
+
+	candidates := []string{path, path + ".js", path + ".json", filepath.Join(path, "index.js")}
+	for _, candidate := range candidates {
+		info, err := os.Stat(candidate)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		if err := l.checkInsideRoot(candidate); err != nil {
+			return "", fmt.Errorf("cannot require %q: %w", name, err)
+		}
+		return candidate, nil
+	}
+	return "", fmt.Errorf("cannot find module %q", name)
+}
+
+// checkInsideRoot rejects paths that leave the root, following symlinks.
+func (l *ModuleLoader) checkInsideRoot(path string) error {
+	root, err := filepath.EvalSymlinks(l.root)
+	if err != nil {
+		return fmt.Errorf("invalid module root: %w", err)
+	}
+	real, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return err
+	}
+	rel, err := filepath.Rel(root, real)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return errors.New("module is outside the collection directory")
+	}
+	return nil
+}
+
+// program returns the compiled wrapper function of a local module.
+func (l *ModuleLoader) program(path string) (*goja.Program, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if p, ok := l.programs[path]; ok {
+		return p, nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() > maxModuleSize {
+		return nil, fmt.Errorf("module %s is larger than %d bytes", filepath.Base(path), maxModuleSize)
+	}
+	source, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read module: %w", err)
+	}
+
+	p, err := compileModule(path, string(source))
+	if err != nil {
+		return nil, err
+	}
+	l.programs[path] = p
+	return p, nil
+}
+
+// compileModule wraps CommonJS source in a function taking the module
+// variables, and compiles it.
+func compileModule(name, source string) (*goja.Program, error) {
+	wrapped := "(function(exports, require, module, __filename, __dirname, __native) {" + source + "\n})"
+	p, err := goja.Compile(name, wrapped, false)
+	if err != nil {
+		return nil, fmt.Errorf("syntax error in module %s: %w", filepath.Base(name), err)
+	}
+	return p, nil
+}
+
+var (
+	builtinMu       sync.Mutex
+	builtinPrograms = make(map[string]*goja.Program)
+)
+
+// builtinProgram returns the compiled source of a built-in module.
+func builtinProgram(name string) (*goja.Program, bool, error) {
+	source, ok := builtinModules[name]
+	if !ok {
+		return nil, false, nil
+	}
+
+	builtinMu.Lock()
+	defer builtinMu.Unlock()
+	if p, ok := builtinPrograms[name]; ok {
+		return p, true, nil
+	}
+	p, err := compileModule(name, source)
+	if err != nil {
+		return nil, true, err
+	}
+	builtinPrograms[name] = p
+	return p, true, nil
+}
+
+// isLocalModule reports whether a require() argument names a file.
+func isLocalModule(name string) bool {
+	return strings.HasPrefix(name, "./") || strings.HasPrefix(name, "../") || strings.HasPrefix(name, "/")
+}
+
+// SetModuleLoader enables require() in this scope's scripts. Scopes without
+// a loader have no require.
+func (s *Scope) SetModuleLoader(loader *ModuleLoader) {
+	s.mu.Lock()
+	s.modules = loader
+	s.moduleCache = make(map[string]*goja.Object)
+	s.mu.Unlock()
+
+	s.engine.RegisterFunction("require", s.requireFunc(loader.Root()))
+}
+
+// requireFunc returns a require() resolving local modules against dir.
+func (s *Scope) requireFunc(dir string) func(goja.FunctionCall) goja.Value {
+	return func(call goja.FunctionCall) goja.Value {
+		rt := s.engine.runtime
+		name := call.Argument(0).String()
+		exports, err := s.loadModule(dir, name)
+		if err != nil {
+			var exception *goja.Exception
+			if errors.As(err, &exception) {
+				panic(exception)
+			}
+			panic(rt.NewGoError(err))
+		}
+		return exports
+	}
+}
+
+// loadModule evaluates a module once per scope and returns its exports.
+func (s *Scope) loadModule(dir, name string) (goja.Value, error) {
+	rt := s.engine.runtime
+
+	var key, filename, moduleDir string
+	var program *goja.Program
+	native := goja.Undefined()
+	if isLocalModule(name) {
+		path, err := s.modules.resolve(dir, name)
+		if err != nil {
+			return nil, err
+		}
+		key, filename, moduleDir = path, path, filepath.Dir(path)
+	} else {
+		p, ok, err := builtinProgram(name)
+		if !ok {
+			return nil, fmt.Errorf("cannot find module %q", name)
+		}
+		if err != nil {
+			return nil, err
+		}
+		key, filename, moduleDir, program = "builtin:"+name, name, s.modules.Root(), p
+		native = rt.ToValue(nativeHelpers)
+	}
+
+	// A module being loaded returns its partial exports, breaking cycles
+	if module, ok := s.moduleCache[key]; ok {
+		return module.Get("exports"), nil
+	}
+
+	module := rt.NewObject()
+	exports := rt.NewObject()
+	_ = module.Set("exports", exports)
+	_ = module.Set("id", filename)
+	s.moduleCache[key] = module
+
+	if strings.HasSuffix(filename, ".json") {
+		value, err := s.loadJSONModule(filename)
+		if err != nil {
+			delete(s.moduleCache, key)
+			return nil, err
+		}
+		_ = module.Set("exports", value)
+		return value, nil
+	}
+
+	if program == nil {
+		p, err := s.modules.program(filename)
+		if err != nil {
+			delete(s.moduleCache, key)
+			return nil, err
+		}
+		program = p
+	}
+
+	wrapper, err := rt.RunProgram(program)
+	if err != nil {
+		delete(s.moduleCache, key)
+		return nil, err
+	}
+	fn, _ := goja.AssertFunction(wrapper)
+	_, err = fn(goja.Undefined(),
+		exports,
+		rt.ToValue(s.requireFunc(moduleDir)),
+		module,
+		rt.ToValue(filename),
+		rt.ToValue(moduleDir),
+		native,
+	)
+	if err != nil {
+		delete(s.moduleCache, key)
+		return nil, err
+	}
+	return module.Get("exports"), nil
+}
+
+// loadJSONModule reads a .json module with JSON.parse.
+func (s *Scope) loadJSONModule(path string) (goja.Value, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read module: %w", err)
+	}
+	if len(data) > maxModuleSize {
+		return nil, fmt.Errorf("module %s is larger than %d bytes", filepath.Base(path), maxModuleSize)
+	}
+	if !json.Valid(data) {
+		return nil, fmt.Errorf("invalid JSON in module %s", filepath.Base(path))
+	}
+	rt := s.engine.runtime
+	parse, _ := goja.AssertFunction(rt.Get("JSON").ToObject(rt).Get("parse"))
+	return parse(goja.Undefined(), rt.ToValue(string(data)))
+}
+
+// nativeHelpers are Go functions available to built-in modules.
+var nativeHelpers = map[string]interface{}{
+	"uuid": func() string {
+		return uuid.NewString()
+	},
+}
@@ -0,0 +1,226 @@
+package script
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeModule(t *testing.T, dir, name, source string) {
+	t.Helper()
+	path := filepath.Join(dir, filepath.FromSlash(name))
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(source), 0644))
+}
+
+func TestRequire_LocalModules(t *testing.T) {
+	root := t.TempDir()
+	writeModule(t, root, "lib/helpers.js", `
+		var format = require('./format');
+		exports.greet = function(name) { return format.upper('hello ' + name); };
+	`)
+	writeModule(t, root, "lib/format.js", `
+		module.exports = { upper: function(s) { return s.toUpperCase(); } };
+	`)
+	writeModule(t, root, "lib/counter/index.js", `
+		var count = 0;
+		module.exports = { next: function() { return ++count; } };
+	`)
+	writeModule(t, root, "data/users.json", `{"admin": {"name": "Ada"}}`)
+
+	t.Run("loads modules relative to the root and to each module", func(t *testing.T) {
+		scope := NewScopeWithAssertions()
+		scope.SetModuleLoader(NewModuleLoader(root))
+
+		result, err := scope.Execute(context.Background(), `require('./lib/helpers.js').greet('ada')`)
+
+		require.NoError(t, err)
+		assert.Equal(t, "HELLO ADA", result)
+	})
+
+	t.Run("evaluates a module once per scope", func(t *testing.T) {
+		scope := NewScopeWithAssertions()
+		scope.SetModuleLoader(NewModuleLoader(root))
+
+		_, err := scope.Execute(context.Background(), `require('./lib/counter').next()`)
+		require.NoError(t, err)
+		result, err := scope.Execute(context.Background(), `require('./lib/counter/index.js').next()`)
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), result)
+	})
+
+	t.Run("loads JSON modules", func(t *testing.T) {
+		scope := NewScopeWithAssertions()
+		scope.SetModuleLoader(NewModuleLoader(root))
+
+		result, err := scope.Execute(context.Background(), `require('./data/users.json').admin.name`)
+
+		require.NoError(t, err)
+		assert.Equal(t, "Ada", result)
+	})
+
+	t.Run("caches compiled modules for the loader's lifetime", func(t *testing.T) {
+		dir := t.TempDir()
+		writeModule(t, dir, "version.js", `module.exports = 1;`)
+		loader := NewModuleLoader(dir)
+
+		first := NewScopeWithAssertions()
+		first.SetModuleLoader(loader)
+		_, err := first.Execute(context.Background(), `require('./version')`)
+		require.NoError(t, err)
+
+		writeModule(t, dir, "version.js", `module.exports = 2;`)
+		second := NewScopeWithAssertions()
+		second.SetModuleLoader(loader)
+		result, err := second.Execute(context.Background(), `require('./version')`)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), result)
+
+		fresh := NewScopeWithAssertions()
+		fresh.SetModuleLoader(NewModuleLoader(dir))
+		result, err = fresh.Execute(context.Background(), `require('./version')`)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), result)
+	})
+
+	t.Run("module errors reach the script", func(t *testing.T) {
+		dir := t.TempDir()
+		writeModule(t, dir, "broken.js", `throw new Error("not configured");`)
+		writeModule(t, dir, "syntax.js", `module.exports = {`)
+		scope := NewScopeWithAssertions()
+		scope.SetModuleLoader(NewModuleLoader(dir))
+
+		result, err := scope.Execute(context.Background(), `
+			try { require('./broken'); } catch (e) { e.message }
+		`)
+		require.NoError(t, err)
+		assert.Equal(t, "not configured", result)
+
+		_, err = scope.Execute(context.Background(), `require('./syntax')`)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "syntax error in module syntax.js")
+	})
+}
+
+func TestRequire_Restrictions(t *testing.T) {
+	parent := t.TempDir()
+	root := filepath.Join(parent, "collection")
+	writeModule(t, root, "ok.js", `module.exports = true;`)
+	writeModule(t, parent, "secret.js", `module.exports = "secret";`)
+
+	tests := []struct {
+		name   string
+		script string
+		error  string
+	}{
+		{"parent directory", `require('../secret.js')`, `cannot require "../secret.js": module is outside the collection directory`},
+		{"absolute path", `require('` + filepath.ToSlash(filepath.Join(parent, "secret.js")) + `')`, "module is outside the collection directory"},
+		{"missing module", `require('./missing')`, `cannot find module "./missing"`},
+		{"unknown package", `require('fs')`, `cannot find module "fs"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scope := NewScopeWithAssertions()
+			scope.SetModuleLoader(NewModuleLoader(root))
+
+			_, err := scope.Execute(context.Background(), tt.script)
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.error)
+		})
+	}
+
+	t.Run("symlinks out of the root", func(t *testing.T) {
+		link := filepath.Join(root, "link.js")
+		if err := os.Symlink(filepath.Join(parent, "secret.js"), link); err != nil {
+			t.Skip("symlinks not supported")
+		}
+		scope := NewScopeWithAssertions()
+		scope.SetModuleLoader(NewModuleLoader(root))
+
+		_, err := scope.Execute(context.Background(), `require('./link.js')`)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "module is outside the collection directory")
+	})
+
+	t.Run("no root allows only built-in modules", func(t *testing.T) {
+		scope := NewScopeWithAssertions()
+		scope.SetModuleLoader(NewModuleLoader(""))
+
+		_, err := scope.Execute(context.Background(), `require('./ok.js')`)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "local modules need a collection directory")
+
+		result, err := scope.Execute(context.Background(), `require('lodash').camelCase('user id')`)
+		require.NoError(t, err)
+		assert.Equal(t, "userId", result)
+	})
+
+	t.Run("scopes without a loader have no require", func(t *testing.T) {
+		scope := NewScopeWithAssertions()
+
+		result, err := scope.Execute(context.Background(), `typeof require`)
+
+		require.NoError(t, err)
+		assert.Equal(t, "undefined", result)
+	})
+
+	t.Run("sandboxed modules are interrupted by the script timeout", func(t *testing.T) {
+		writeModule(t, root, "loop.js", `while (true) {}`)
+		scope := NewSandboxedScope()
+		scope.SetModuleLoader(NewModuleLoader(root))
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		_, err := scope.Execute(ctx, `require('./loop')`)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "interrupted")
+	})
+}
+
+func TestRequire_BuiltinModules(t *testing.T) {
+	tests := []struct {
+		name     string
+		script   string
+		expected interface{}
+	}{
+		{"lodash get", `require('lodash').get({a: {b: [{c: 3}]}}, 'a.b[0].c')`, int64(3)},
+		{"lodash get default", `require('lodash').get({}, 'a.b', 'none')`, "none"},
+		{"lodash groupBy", `JSON.stringify(require('lodash').groupBy([{t: 'a', v: 1}, {t: 'b', v: 2}, {t: 'a', v: 3}], 't'))`,
+			`{"a":[{"t":"a","v":1},{"t":"a","v":3}],"b":[{"t":"b","v":2}]}`},
+		{"lodash sortBy", `JSON.stringify(require('lodash').sortBy([{n: 'b'}, {n: 'a'}], 'n'))`, `[{"n":"a"},{"n":"b"}]`},
+		{"lodash isEqual", `require('lodash').isEqual({a: [1, {b: 2}]}, {a: [1, {b: 2}]})`, true},
+		{"lodash snakeCase", `require('lodash').snakeCase('userId Value')`, "user_id_value"},
+		{"lodash padStart", `require('lodash').padStart('7', 3, '0')`, "007"},
+		{"moment format", `require('moment').utc('2024-03-05T14:07:09Z').format('YYYY-MM-DD HH:mm:ss [UTC] ddd MMM')`, "2024-03-05 14:07:09 UTC Tue Mar"},
+		{"moment add", `require('moment').utc('2024-01-31T00:00:00Z').add(1, 'month').format('YYYY-MM-DD')`, "2024-02-29"},
+		{"moment default format", `require('moment').utc(0).format()`, "1970-01-01T00:00:00Z"},
+		{"moment parse format", `require('moment').utc('05/03/2024', 'DD/MM/YYYY').toISOString()`, "2024-03-05T00:00:00.000Z"},
+		{"moment diff", `require('moment').utc('2024-03-05').diff('2024-03-01', 'days')`, int64(4)},
+		{"moment unix", `require('moment').unix(86400).utc().format('YYYY-MM-DD')`, "1970-01-02"},
+		{"uuid", `var uuid = require('uuid'); var id = uuid.v4(); uuid.validate(id) && id !== uuid.v4()`, true},
+		{"querystring stringify", `require('querystring').stringify({q: 'a b', tag: ['x', 'y'], n: 1})`, "q=a%20b&tag=x&tag=y&n=1"},
+		{"querystring parse", `JSON.stringify(require('querystring').parse('q=a+b&tag=x&tag=y&empty'))`, `{"q":"a b","tag":["x","y"],"empty":""}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scope := NewScopeWithAssertions()
+			scope.SetModuleLoader(NewModuleLoader(""))
+
+			result, err := scope.Execute(context.Background(), tt.script)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/dop251/goja"
 )
@@ -18,6 +19,8 @@ type SandboxedEngine struct {
 	iterationLimit int64
 	memoryLimit    int64
 	evalDisabled   bool
+	timeLimit      time.Duration
+	loop           *eventLoop
 }
 
 // NewSandboxedEngine creates a new sandboxed JavaScript engine.
@@ -26,6 +29,7 @@ func NewSandboxedEngine() *SandboxedEngine {
 		runtime:        goja.New(),
 		iterationLimit: 0, // 0 means no limit
 		memoryLimit:    0, // 0 means no limit
+		timeLimit:      DefaultTimeLimit,
 	}
 	e.setupSandbox()
 	return e
@@ -51,6 +55,9 @@ func (e *SandboxedEngine) setupSandbox() {
 
 	// Setup console
 	e.setupConsole()
+
+	// Timers and async callbacks
+	e.loop = newEventLoop(e.runtime)
 }
 
 // setupConsole configures the console object.
@@ -114,6 +121,14 @@ func (e *SandboxedEngine) SetMemoryLimit(limit int64) {
 	e.memoryLimit = limit
 }
 
+// SetTimeLimit bounds the total wall-clock time of each Execute, including
+// waiting for timers and async requests. Zero removes the bound.
+func (e *SandboxedEngine) SetTimeLimit(limit time.Duration) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.timeLimit = limit
+}
+
 // DisableEval disables eval() and Function constructor.
 func (e *SandboxedEngine) DisableEval() {
 	e.mu.Lock()
@@ -125,25 +140,37 @@ func (e *SandboxedEngine) DisableEval() {
 func (e *SandboxedEngine) Execute(ctx context.Context, script string) (interface{}, error) {
 	e.mu.RLock()
 	evalDisabled := e.evalDisabled
+	timeLimit := e.timeLimit
+	e.loop.callbackLimit = e.iterationLimit
 	e.mu.RUnlock()
 
+	ctx, cancel, reason := limitContext(ctx, timeLimit)
+	defer cancel()
+
 	// Clear any previous interrupt
 	e.runtime.ClearInterrupt()
 
-	// Setup interrupt handler for context cancellation
+	// Setup interrupt handler for context cancellation, waiting for it to
+	// exit so it can't interrupt a later execution
 	done := make(chan struct{})
-	defer close(done)
+	exited := make(chan struct{})
+	defer func() {
+		close(done)
+		<-exited
+	}()
 
 	go func() {
+		defer close(exited)
 		select {
 		case <-ctx.Done():
-			e.runtime.Interrupt("context cancelled")
+			e.runtime.Interrupt(reason())
 		case <-done:
 		}
 	}()
 
 	// Note: True iteration limiting would require Goja hooks.
-	// We rely on context timeout for infinite loop protection.
+	// We rely on context timeout for infinite loop protection; the
+	// iteration limit caps timer and async callbacks.
 	finalScript := script
 
 	// Wrap script to disable eval if needed
@@ -173,13 +200,16 @@ func (e *SandboxedEngine) Execute(ctx context.Context, script string) (interface
 		return nil, fmt.Errorf("compile error: %w", err)
 	}
 
-	result, err := e.runtime.RunProgram(program)
+	result, err := e.loop.runScript(ctx, program)
 	if err != nil {
 		// Check if it was an interrupt
 		var interrupt *goja.InterruptedError
 		if errors.As(err, &interrupt) {
 			return nil, fmt.Errorf("execution interrupted: %v", interrupt.Value())
 		}
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("execution interrupted: %s", reason())
+		}
 		// Check for iteration limit error
 		if strings.Contains(err.Error(), "iteration limit exceeded") {
 			return nil, fmt.Errorf("iteration limit exceeded")
@@ -227,6 +257,7 @@ func NewSandboxedScope() *SandboxedScope {
 		runtime:   sandboxEngine.runtime,
 		globals:   make(map[string]interface{}),
 		functions: make(map[string]interface{}),
+		loop:      sandboxEngine.loop,
 	}
 
 	// Create the scope with the underlying engine
@@ -0,0 +1,30 @@
+package script
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/artpar/currier/internal/jsonschema"
+)
+
+// validateSchema backs matchSchema and pm.response.to.have.jsonSchema. Both
+// arguments are JSON documents; the dialect (draft 7 or 2020-12) is read
+// from the schema's $schema keyword. It returns an empty string when the
+// instance is valid, and otherwise one line per violation prefixed with its
+// JSON pointer.
+func validateSchema(instance, schema string) string {
+	compiled, err := jsonschema.Parse([]byte(schema))
+	if err != nil {
+		return fmt.Sprintf("invalid schema: %v", err)
+	}
+	errs, err := compiled.ValidateJSON([]byte(strings.TrimSpace(instance)))
+	if err != nil {
+		return fmt.Sprintf("value is not JSON: %v", err)
+	}
+
+	lines := make([]string, len(errs))
+	for i, e := range errs {
+		lines[i] = e.Error()
+	}
+	return strings.Join(lines, "\n")
+}
@@ -2,15 +2,16 @@ package script
 
 import (
 	"context"
-	"crypto/hmac"
-	"crypto/md5"
-	"crypto/sha256"
 	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
 	"strings"
 	"sync"
+
+	"github.com/dop251/goja"
 )
 
 // LogHandler is a function that handles log output from scripts.
@@ -46,9 +47,28 @@ type Scope struct {
 	environmentName      string
 	environmentVariables map[string]string
 
+	// Iteration (data-driven collection runs)
+	iterationData  map[string]string
+	iteration      int
+	iterationCount int
+
+	// Flow control requested by scripts during collection runs
+	nextRequest    string
+	nextRequestSet bool
+	stopRequested  bool
+	skipRequested  bool
+
 	// Handlers
 	logHandler    LogHandler
 	requestSender RequestSender
+
+	// Cookies
+	cookieJar       http.CookieJar
+	responseCookies []*http.Cookie
+
+	// require() support; nil when scripts can't load modules
+	modules     *ModuleLoader
+	moduleCache map[string]*goja.Object // Evaluated modules by path
 }
 
 // NewScope creates a new script execution scope.
@@ -60,6 +80,8 @@ func NewScope() *Scope {
 		variables:            make(map[string]string),
 		localVariables:       make(map[string]string),
 		environmentVariables: make(map[string]string),
+		iterationData:        make(map[string]string),
+		iterationCount:       1,
 	}
 	s.setupCurrierAPI()
 	return s
@@ -76,6 +98,12 @@ func (s *Scope) setupCurrierAPI() {
 	s.engine.RegisterObject("currier", apiObject)
 	// Register pm.* alias for Postman script compatibility
 	s.engine.RegisterObject("pm", apiObject)
+	// Legacy postman.setNextRequest used by older Postman collections
+	s.engine.RegisterObject("postman", map[string]interface{}{
+		"setNextRequest": s.setNextRequestFunc(),
+	})
+	// CryptoJS global used by imported Postman scripts
+	s.installCryptoJS()
 }
 
 // buildCurrierObject builds the currier.* API object.
@@ -105,16 +133,30 @@ func (s *Scope) buildCurrierObjectLocked() map[string]interface{} {
 	// Environment
 	currier["environment"] = s.createEnvironmentObjectLocked()
 
+	// Response cookies and the cookie jar
+	currier["cookies"] = s.createCookiesObjectLocked()
+
+	// Iteration data and run info
+	currier["iterationData"] = s.createIterationDataObjectLocked()
+	currier["info"] = map[string]interface{}{
+		"iteration":      s.iteration,
+		"iterationCount": s.iterationCount,
+	}
+
 	// Logging
 	currier["log"] = s.logFunc()
 
 	// Utilities
 	currier["base64"] = s.createBase64Object()
+	currier["hex"] = s.createHexObject()
 	currier["crypto"] = s.createCryptoObject()
 
 	// Request sending
 	currier["sendRequest"] = s.sendRequestFunc()
 
+	// Run flow control
+	currier["execution"] = s.createExecutionObject()
+
 	return currier
 }
 
@@ -196,9 +238,40 @@ func (s *Scope) createVariablesProxyLocked() map[string]string {
 	for k, v := range s.variables {
 		result[k] = v
 	}
+	for k, v := range s.iterationData {
+		result[k] = v
+	}
 	return result
 }
 
+// createIterationDataObjectLocked creates the currier.iterationData object. Caller must hold at least RLock.
+func (s *Scope) createIterationDataObjectLocked() map[string]interface{} {
+	return map[string]interface{}{
+		"get": func(key string) string {
+			s.mu.RLock()
+			defer s.mu.RUnlock()
+			return s.iterationData[key]
+		},
+
+		"has": func(key string) bool {
+			s.mu.RLock()
+			defer s.mu.RUnlock()
+			_, ok := s.iterationData[key]
+			return ok
+		},
+
+		"toObject": func() map[string]string {
+			s.mu.RLock()
+			defer s.mu.RUnlock()
+			result := make(map[string]string, len(s.iterationData))
+			for k, v := range s.iterationData {
+				result[k] = v
+			}
+			return result
+		},
+	}
+}
+
 // createEnvironmentObjectLocked creates the currier.environment object. Caller must hold at least RLock.
 func (s *Scope) createEnvironmentObjectLocked() map[string]interface{} {
 	name := s.environmentName
@@ -225,6 +298,9 @@ func (s *Scope) getVariableFunc() func(string) string {
 		s.mu.RLock()
 		defer s.mu.RUnlock()
 
+		if v, ok := s.iterationData[key]; ok {
+			return v
+		}
 		if v, ok := s.variables[key]; ok {
 			return v
 		}
@@ -282,43 +358,119 @@ func (s *Scope) createBase64Object() map[string]interface{} {
 			}
 			return string(decoded)
 		},
+		// URL-safe alphabet without padding, as used by JWTs
+		"encodeURL": func(input string) string {
+			return base64.RawURLEncoding.EncodeToString([]byte(input))
+		},
+		"decodeURL": func(input string) string {
+			decoded, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(input, "="))
+			if err != nil {
+				return ""
+			}
+			return string(decoded)
+		},
 	}
 }
 
-// createCryptoObject creates the currier.crypto object.
-func (s *Scope) createCryptoObject() map[string]interface{} {
+// createHexObject creates the currier.hex object.
+func (s *Scope) createHexObject() map[string]interface{} {
 	return map[string]interface{}{
-		"md5": func(input string) string {
-			hash := md5.Sum([]byte(input))
-			return hex.EncodeToString(hash[:])
-		},
-		"sha256": func(input string) string {
-			hash := sha256.Sum256([]byte(input))
-			return hex.EncodeToString(hash[:])
+		"encode": func(input string) string {
+			return hex.EncodeToString([]byte(input))
 		},
-		"hmac": func(algorithm, key, data string) string {
-			mac := hmac.New(sha256.New, []byte(key))
-			mac.Write([]byte(data))
-			return hex.EncodeToString(mac.Sum(nil))
+		"decode": func(input string) string {
+			decoded, err := hex.DecodeString(input)
+			if err != nil {
+				return ""
+			}
+			return string(decoded)
 		},
 	}
 }
 
-func (s *Scope) sendRequestFunc() func(map[string]interface{}) interface{} {
-	return func(options map[string]interface{}) interface{} {
+// sendRequestFunc returns sendRequest(request, callback). The request is
+// a URL or an options object. With a callback the request is sent in the
+// background and the callback receives (err, response), as in Postman;
+// without one sendRequest blocks and returns the response, or null on error.
+func (s *Scope) sendRequestFunc() func(goja.FunctionCall) goja.Value {
+	return func(call goja.FunctionCall) goja.Value {
+		rt := s.engine.runtime
 		s.mu.RLock()
 		sender := s.requestSender
 		s.mu.RUnlock()
 
-		if sender == nil {
-			return nil
+		var options map[string]interface{}
+		switch v := call.Argument(0).Export().(type) {
+		case string:
+			options = map[string]interface{}{"url": v}
+		case map[string]interface{}:
+			options = v
+		default:
+			panic(rt.NewTypeError("sendRequest needs a URL or request options"))
+		}
+
+		callback, async := goja.AssertFunction(call.Argument(1))
+		if !async {
+			if sender == nil {
+				return goja.Null()
+			}
+			result, err := sender(options)
+			if err != nil {
+				return goja.Null()
+			}
+			return rt.ToValue(result)
 		}
 
-		result, err := sender(options)
-		if err != nil {
-			return nil
+		s.engine.loop.goAsync(func() (interface{}, error) {
+			if sender == nil {
+				return nil, errors.New("sendRequest is not available in this context")
+			}
+			return sender(options)
+		}, func(response interface{}, err error) error {
+			errValue, responseValue := goja.Null(), goja.Null()
+			if err != nil {
+				errValue = rt.NewGoError(err)
+			} else {
+				responseValue = rt.ToValue(response)
+			}
+			_, err = callback(goja.Undefined(), errValue, responseValue)
+			return err
+		})
+		return goja.Undefined()
+	}
+}
+
+// createExecutionObject creates the currier.execution flow control object.
+func (s *Scope) createExecutionObject() map[string]interface{} {
+	return map[string]interface{}{
+		"setNextRequest": s.setNextRequestFunc(),
+
+		"skipRequest": func() {
+			s.mu.Lock()
+			s.skipRequested = true
+			s.mu.Unlock()
+		},
+
+		"stop": func() {
+			s.mu.Lock()
+			s.stopRequested = true
+			s.mu.Unlock()
+		},
+	}
+}
+
+// setNextRequestFunc returns setNextRequest(nameOrID). Passing null stops
+// the run after the current request, as in Postman.
+func (s *Scope) setNextRequestFunc() func(interface{}) {
+	return func(target interface{}) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if target == nil {
+			s.stopRequested = true
+			return
 		}
-		return result
+		s.nextRequest = fmt.Sprintf("%v", target)
+		s.nextRequestSet = true
 	}
 }
 
@@ -473,6 +625,54 @@ func (s *Scope) GetEnvironmentVariable(key string) string {
 	return s.environmentVariables[key]
 }
 
+// SetIterationData sets the data row for the current iteration. Iteration
+// data takes precedence over regular variables, matching interpolation.
+func (s *Scope) SetIterationData(data map[string]string) {
+	s.mu.Lock()
+	s.iterationData = make(map[string]string, len(data))
+	for k, v := range data {
+		s.iterationData[k] = v
+	}
+	s.mu.Unlock()
+}
+
+// GetIterationData gets an iteration data value.
+func (s *Scope) GetIterationData(key string) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.iterationData[key]
+}
+
+// SetIterationInfo sets the zero-based iteration index and the total number
+// of iterations exposed as currier.info.
+func (s *Scope) SetIterationInfo(iteration, count int) {
+	s.mu.Lock()
+	s.iteration = iteration
+	s.iterationCount = count
+	s.mu.Unlock()
+}
+
+// NextRequest returns the request name or ID a script asked to run next.
+func (s *Scope) NextRequest() (string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.nextRequest, s.nextRequestSet
+}
+
+// StopRequested returns true if a script asked to stop the run.
+func (s *Scope) StopRequested() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.stopRequested
+}
+
+// SkipRequested returns true if a script asked to skip the current request.
+func (s *Scope) SkipRequested() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.skipRequested
+}
+
 // SetLogHandler sets the handler for log output.
 func (s *Scope) SetLogHandler(handler LogHandler) {
 	s.mu.Lock()
@@ -508,8 +708,17 @@ func (s *Scope) Clone() *Scope {
 		localVariables:       make(map[string]string),
 		environmentName:      s.environmentName,
 		environmentVariables: make(map[string]string),
+		iterationData:        make(map[string]string),
+		iteration:            s.iteration,
+		iterationCount:       s.iterationCount,
+		nextRequest:          s.nextRequest,
+		nextRequestSet:       s.nextRequestSet,
+		stopRequested:        s.stopRequested,
+		skipRequested:        s.skipRequested,
 		logHandler:           s.logHandler,
 		requestSender:        s.requestSender,
+		cookieJar:            s.cookieJar,
+		responseCookies:      s.responseCookies,
 	}
 
 	for k, v := range s.requestHeaders {
@@ -527,6 +736,9 @@ func (s *Scope) Clone() *Scope {
 	for k, v := range s.environmentVariables {
 		clone.environmentVariables[k] = v
 	}
+	for k, v := range s.iterationData {
+		clone.iterationData[k] = v
+	}
 
 	clone.setupCurrierAPI()
 	return clone
@@ -549,6 +761,14 @@ func (s *Scope) Reset() {
 	s.localVariables = make(map[string]string)
 	s.environmentName = ""
 	s.environmentVariables = make(map[string]string)
+	s.iterationData = make(map[string]string)
+	s.iteration = 0
+	s.iterationCount = 1
+	s.nextRequest = ""
+	s.nextRequestSet = false
+	s.stopRequested = false
+	s.skipRequested = false
+	s.responseCookies = nil
 	s.mu.Unlock()
 
 	s.engine.Reset()
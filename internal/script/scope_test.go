@@ -561,3 +561,91 @@ func TestScope_CloneWithAllFields(t *testing.T) {
 		assert.Equal(t, "original", headers["Header"])
 	})
 }
+
+func TestScope_IterationData(t *testing.T) {
+	t.Run("currier.iterationData exposes row values", func(t *testing.T) {
+		scope := NewScope()
+		scope.SetIterationData(map[string]string{"user": "alice"})
+
+		result, err := scope.Execute(context.Background(), `currier.iterationData.get("user")`)
+		require.NoError(t, err)
+		assert.Equal(t, "alice", result)
+
+		result, err = scope.Execute(context.Background(), `pm.iterationData.has("missing")`)
+		require.NoError(t, err)
+		assert.Equal(t, false, result)
+
+		result, err = scope.Execute(context.Background(), `pm.iterationData.toObject().user`)
+		require.NoError(t, err)
+		assert.Equal(t, "alice", result)
+	})
+
+	t.Run("iteration data overrides variables", func(t *testing.T) {
+		scope := NewScope()
+		scope.SetVariable("user", "env-user")
+		scope.SetIterationData(map[string]string{"user": "row-user"})
+
+		result, err := scope.Execute(context.Background(), `currier.getVariable("user") + "," + currier.variables.user`)
+		require.NoError(t, err)
+		assert.Equal(t, "row-user,row-user", result)
+	})
+
+	t.Run("currier.info reports iteration", func(t *testing.T) {
+		scope := NewScope()
+		scope.SetIterationInfo(2, 5)
+
+		result, err := scope.Execute(context.Background(), `pm.info.iteration + "/" + pm.info.iterationCount`)
+		require.NoError(t, err)
+		assert.Equal(t, "2/5", result)
+	})
+
+	t.Run("clone copies and reset clears iteration data", func(t *testing.T) {
+		scope := NewScope()
+		scope.SetIterationData(map[string]string{"id": "7"})
+
+		clone := scope.Clone()
+		assert.Equal(t, "7", clone.GetIterationData("id"))
+
+		scope.Reset()
+		assert.Equal(t, "", scope.GetIterationData("id"))
+	})
+}
+
+func TestScope_FlowControl(t *testing.T) {
+	t.Run("setNextRequest records target", func(t *testing.T) {
+		scope := NewScope()
+
+		_, err := scope.Execute(context.Background(), `pm.execution.setNextRequest("Login")`)
+		require.NoError(t, err)
+
+		next, ok := scope.NextRequest()
+		assert.True(t, ok)
+		assert.Equal(t, "Login", next)
+		assert.False(t, scope.StopRequested())
+	})
+
+	t.Run("legacy postman.setNextRequest(null) stops the run", func(t *testing.T) {
+		scope := NewScope()
+
+		_, err := scope.Execute(context.Background(), `postman.setNextRequest(null)`)
+		require.NoError(t, err)
+
+		_, ok := scope.NextRequest()
+		assert.False(t, ok)
+		assert.True(t, scope.StopRequested())
+	})
+
+	t.Run("skipRequest and stop", func(t *testing.T) {
+		scope := NewScope()
+
+		_, err := scope.Execute(context.Background(), `currier.execution.skipRequest(); currier.execution.stop();`)
+		require.NoError(t, err)
+
+		assert.True(t, scope.SkipRequested())
+		assert.True(t, scope.StopRequested())
+
+		scope.Reset()
+		assert.False(t, scope.SkipRequested())
+		assert.False(t, scope.StopRequested())
+	})
+}
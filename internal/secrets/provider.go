@@ -0,0 +1,181 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AllowCommandsEnv enables the cmd: provider. Commands are opt-in because a
+// reference in an imported collection would otherwise run on send.
+const AllowCommandsEnv = "CURRIER_SECRETS_COMMANDS"
+
+// commandTimeout bounds how long a cmd: reference may take, such as a
+// password manager waiting for input.
+const commandTimeout = 30 * time.Second
+
+// Provider resolves references to secrets kept outside Currier. The
+// reference is what follows the provider's scheme, as in env:API_KEY.
+type Provider interface {
+	Resolve(ref string) (string, error)
+}
+
+// ProviderFunc adapts a function to a Provider.
+type ProviderFunc func(ref string) (string, error)
+
+// Resolve calls f(ref).
+func (f ProviderFunc) Resolve(ref string) (string, error) {
+	return f(ref)
+}
+
+// Resolver resolves scheme:reference strings through registered providers
+// and remembers the values it returned so they can be redacted.
+type Resolver struct {
+	mu        sync.RWMutex
+	providers map[string]Provider
+	resolved  map[string]bool
+}
+
+// NewResolver creates a resolver with the env, file and cmd providers.
+func NewResolver() *Resolver {
+	r := &Resolver{
+		providers: make(map[string]Provider),
+		resolved:  make(map[string]bool),
+	}
+	r.Register("env", ProviderFunc(resolveEnv))
+	r.Register("file", ProviderFunc(resolveFile))
+	r.Register("cmd", &CommandProvider{})
+	return r
+}
+
+// Register adds or replaces the provider for scheme.
+func (r *Resolver) Register(scheme string, p Provider) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.providers[scheme] = p
+}
+
+// Resolve returns the secret a reference such as "env:API_KEY" names.
+func (r *Resolver) Resolve(reference string) (string, error) {
+	scheme, ref, ok := strings.Cut(reference, ":")
+	if !ok || scheme == "" || ref == "" {
+		return "", fmt.Errorf("invalid secret reference %q: expected provider:reference, such as env:API_KEY", reference)
+	}
+
+	r.mu.RLock()
+	p, ok := r.providers[scheme]
+	r.mu.RUnlock()
+	if !ok {
+		return "", fmt.Errorf("unknown secret provider %q", scheme)
+	}
+
+	value, err := p.Resolve(ref)
+	if err != nil {
+		return "", fmt.Errorf("secret %s: %w", reference, err)
+	}
+
+	r.mu.Lock()
+	r.resolved[value] = true
+	r.mu.Unlock()
+	return value, nil
+}
+
+// Values returns the secret values resolved so far.
+func (r *Resolver) Values() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	values := make([]string, 0, len(r.resolved))
+	for v := range r.resolved {
+		values = append(values, v)
+	}
+	return values
+}
+
+var defaultResolver = NewResolver()
+
+// RegisterProvider adds or replaces a provider of the session-wide resolver
+// used by {{$secret "..."}}.
+func RegisterProvider(scheme string, p Provider) {
+	defaultResolver.Register(scheme, p)
+}
+
+// Resolve resolves a reference through the session-wide resolver.
+func Resolve(reference string) (string, error) {
+	return defaultResolver.Resolve(reference)
+}
+
+// ResolvedValues returns the values the session-wide resolver returned, so
+// they can be redacted alongside environment secrets.
+func ResolvedValues() []string {
+	return defaultResolver.Values()
+}
+
+// resolveEnv reads a secret from an environment variable.
+func resolveEnv(name string) (string, error) {
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return "", fmt.Errorf("environment variable %s is not set", name)
+	}
+	return value, nil
+}
+
+// resolveFile reads a secret from a file, such as a mounted Docker or
+// Kubernetes secret. A trailing newline is dropped.
+func resolveFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// CommandProvider runs a shell command and uses its output, trimmed of a
+// trailing newline, as the secret. Each command runs once; its output is
+// cached for the rest of the session.
+type CommandProvider struct {
+	mu    sync.Mutex
+	cache map[string]string
+}
+
+// Resolve runs command, or returns its cached output.
+func (p *CommandProvider) Resolve(command string) (string, error) {
+	if allowed, _ := strconv.ParseBool(os.Getenv(AllowCommandsEnv)); !allowed {
+		return "", fmt.Errorf("commands are disabled: set %s=1 to allow them", AllowCommandsEnv)
+	}
+
+	// Held while the command runs, so concurrent requests don't run it twice
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if value, ok := p.cache[command]; ok {
+		return value, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("command failed: %w: %s", err, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", fmt.Errorf("command failed: %w", err)
+	}
+
+	value := strings.TrimRight(string(out), "\r\n")
+	if p.cache == nil {
+		p.cache = make(map[string]string)
+	}
+	p.cache[command] = value
+	return value, nil
+}
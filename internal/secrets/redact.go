@@ -0,0 +1,71 @@
+package secrets
+
+import (
+	"encoding/json"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// Mask replaces redacted secret values.
+const Mask = "********"
+
+// minRedactLength is the shortest value redacted; shorter values would mask
+// unrelated text.
+const minRedactLength = 4
+
+// Redactor replaces secret values in text with Mask. The values are also
+// matched as they appear JSON-escaped or URL-encoded. A nil Redactor leaves
+// text unchanged.
+type Redactor struct {
+	replacer *strings.Replacer
+}
+
+// NewRedactor creates a redactor for values.
+func NewRedactor(values ...string) *Redactor {
+	seen := make(map[string]bool)
+	var forms []string
+	for _, value := range values {
+		if len(value) < minRedactLength {
+			continue
+		}
+		escaped, _ := json.Marshal(value)
+		for _, form := range []string{value, string(escaped[1 : len(escaped)-1]), url.QueryEscape(value), url.PathEscape(value)} {
+			if !seen[form] {
+				seen[form] = true
+				forms = append(forms, form)
+			}
+		}
+	}
+	if len(forms) == 0 {
+		return nil
+	}
+
+	// Longer values first, so a secret containing another is masked whole
+	sort.Slice(forms, func(i, j int) bool { return len(forms[i]) > len(forms[j]) })
+	pairs := make([]string, 0, len(forms)*2)
+	for _, form := range forms {
+		pairs = append(pairs, form, Mask)
+	}
+	return &Redactor{replacer: strings.NewReplacer(pairs...)}
+}
+
+// Redact masks secret values in s.
+func (r *Redactor) Redact(s string) string {
+	if r == nil {
+		return s
+	}
+	return r.replacer.Replace(s)
+}
+
+// RedactMap returns a copy of m with secret values masked.
+func (r *Redactor) RedactMap(m map[string]string) map[string]string {
+	if r == nil || m == nil {
+		return m
+	}
+	result := make(map[string]string, len(m))
+	for k, v := range m {
+		result[k] = r.Redact(v)
+	}
+	return result
+}
@@ -0,0 +1,198 @@
+// Package secrets encrypts environment secrets at rest, resolves secrets
+// kept outside Currier and redacts secret values from output.
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hkdf"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Environment variables that unlock secrets without a prompt, as in CI.
+const (
+	PassphraseEnv = "CURRIER_SECRETS_PASSPHRASE"
+	KeyFileEnv    = "CURRIER_SECRETS_KEYFILE"
+)
+
+// Supported algorithms, recorded in Sealed so the format can evolve.
+const (
+	CipherAESGCM = "aes-256-gcm"
+	KDFPBKDF2    = "pbkdf2-sha256"
+	KDFHKDF      = "hkdf-sha256"
+)
+
+// pbkdf2Iterations follows OWASP's recommendation for PBKDF2-HMAC-SHA256.
+const pbkdf2Iterations = 600_000
+
+var (
+	// ErrLocked is returned when sealed secrets are used without a key.
+	ErrLocked = errors.New("secrets are locked: set " + PassphraseEnv + " or " + KeyFileEnv)
+	// ErrWrongKey is returned when a key doesn't open sealed secrets.
+	ErrWrongKey = errors.New("wrong passphrase or keyfile")
+)
+
+// Sealed is an encrypted set of secrets as stored on disk.
+type Sealed struct {
+	Cipher string `yaml:"cipher" json:"cipher"`
+	KDF    string `yaml:"kdf" json:"kdf"`
+	Salt   string `yaml:"salt" json:"salt"`
+	Data   string `yaml:"data" json:"data"` // Nonce followed by ciphertext, base64
+}
+
+// Key derives encryption keys from a passphrase or the contents of a
+// keyfile. Each Sealed value has its own salt; derived keys are cached
+// because passphrase derivation is deliberately slow.
+type Key struct {
+	secret []byte
+	kdf    string
+
+	mu      sync.Mutex
+	derived map[string][]byte
+}
+
+// NewPassphraseKey creates a key from a passphrase.
+func NewPassphraseKey(passphrase string) *Key {
+	return &Key{secret: []byte(passphrase), kdf: KDFPBKDF2, derived: make(map[string][]byte)}
+}
+
+// LoadKeyFile creates a key from the contents of a keyfile, which should
+// hold at least 32 random bytes.
+func LoadKeyFile(path string) (*Key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keyfile: %w", err)
+	}
+	data = []byte(strings.TrimSpace(string(data)))
+	if len(data) < 16 {
+		return nil, fmt.Errorf("keyfile %s is too short, use at least 32 random bytes", path)
+	}
+	return &Key{secret: data, kdf: KDFHKDF, derived: make(map[string][]byte)}, nil
+}
+
+// KeyFromEnv returns the key configured through PassphraseEnv or
+// KeyFileEnv, or nil when neither is set.
+func KeyFromEnv() (*Key, error) {
+	if path := os.Getenv(KeyFileEnv); path != "" {
+		return LoadKeyFile(path)
+	}
+	if passphrase := os.Getenv(PassphraseEnv); passphrase != "" {
+		return NewPassphraseKey(passphrase), nil
+	}
+	return nil, nil
+}
+
+// derive returns the AES key for a salt.
+func (k *Key) derive(salt []byte) ([]byte, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if key, ok := k.derived[string(salt)]; ok {
+		return key, nil
+	}
+	var key []byte
+	var err error
+	switch k.kdf {
+	case KDFPBKDF2:
+		key, err = pbkdf2.Key(sha256.New, string(k.secret), salt, pbkdf2Iterations, 32)
+	default:
+		key, err = hkdf.Key(sha256.New, k.secret, salt, "currier secrets", 32)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key: %w", err)
+	}
+	k.derived[string(salt)] = key
+	return key, nil
+}
+
+// Seal encrypts values.
+func (k *Key) Seal(values map[string]string) (*Sealed, error) {
+	plaintext, err := json.Marshal(values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode secrets: %w", err)
+	}
+
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+	gcm, err := k.aead(salt)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	return &Sealed{
+		Cipher: CipherAESGCM,
+		KDF:    k.kdf,
+		Salt:   base64.StdEncoding.EncodeToString(salt),
+		Data:   base64.StdEncoding.EncodeToString(gcm.Seal(nonce, nonce, plaintext, nil)),
+	}, nil
+}
+
+// Open decrypts sealed values. A nil key returns ErrLocked.
+func (k *Key) Open(sealed *Sealed) (map[string]string, error) {
+	if k == nil {
+		return nil, ErrLocked
+	}
+	if sealed.Cipher != CipherAESGCM {
+		return nil, fmt.Errorf("unsupported cipher %q", sealed.Cipher)
+	}
+	if sealed.KDF != k.kdf {
+		if sealed.KDF == KDFPBKDF2 {
+			return nil, fmt.Errorf("secrets were encrypted with a passphrase, not a keyfile: %w", ErrWrongKey)
+		}
+		return nil, fmt.Errorf("secrets were encrypted with a keyfile, not a passphrase: %w", ErrWrongKey)
+	}
+
+	salt, err := base64.StdEncoding.DecodeString(sealed.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid salt: %w", err)
+	}
+	data, err := base64.StdEncoding.DecodeString(sealed.Data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid encrypted data: %w", err)
+	}
+	gcm, err := k.aead(salt)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < gcm.NonceSize() {
+		return nil, fmt.Errorf("invalid encrypted data: too short")
+	}
+	plaintext, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+	if err != nil {
+		return nil, ErrWrongKey
+	}
+
+	var values map[string]string
+	if err := json.Unmarshal(plaintext, &values); err != nil {
+		return nil, fmt.Errorf("failed to decode secrets: %w", err)
+	}
+	return values, nil
+}
+
+// aead returns the cipher for a salt.
+func (k *Key) aead(salt []byte) (cipher.AEAD, error) {
+	key, err := k.derive(salt)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
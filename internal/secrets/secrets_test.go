@@ -0,0 +1,170 @@
+package secrets
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKey_SealOpen(t *testing.T) {
+	values := map[string]string{"token": "s3cr3t", "password": "hunter2!"}
+
+	t.Run("round trips with a passphrase", func(t *testing.T) {
+		key := NewPassphraseKey("correct horse")
+		sealed, err := key.Seal(values)
+		require.NoError(t, err)
+		assert.Equal(t, CipherAESGCM, sealed.Cipher)
+		assert.Equal(t, KDFPBKDF2, sealed.KDF)
+		assert.NotContains(t, sealed.Data, "s3cr3t")
+
+		opened, err := NewPassphraseKey("correct horse").Open(sealed)
+		require.NoError(t, err)
+		assert.Equal(t, values, opened)
+	})
+
+	t.Run("round trips with a keyfile", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "key")
+		require.NoError(t, os.WriteFile(path, []byte("0123456789abcdef0123456789abcdef\n"), 0600))
+		key, err := LoadKeyFile(path)
+		require.NoError(t, err)
+
+		sealed, err := key.Seal(values)
+		require.NoError(t, err)
+		assert.Equal(t, KDFHKDF, sealed.KDF)
+		opened, err := key.Open(sealed)
+		require.NoError(t, err)
+		assert.Equal(t, values, opened)
+
+		_, err = NewPassphraseKey("x").Open(sealed)
+		assert.ErrorIs(t, err, ErrWrongKey)
+	})
+
+	t.Run("rejects a wrong passphrase", func(t *testing.T) {
+		sealed, err := NewPassphraseKey("right").Seal(values)
+		require.NoError(t, err)
+
+		_, err = NewPassphraseKey("wrong").Open(sealed)
+		assert.ErrorIs(t, err, ErrWrongKey)
+	})
+
+	t.Run("is locked without a key", func(t *testing.T) {
+		var key *Key
+		_, err := key.Open(&Sealed{})
+		assert.ErrorIs(t, err, ErrLocked)
+	})
+
+	t.Run("rejects short keyfiles", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "key")
+		require.NoError(t, os.WriteFile(path, []byte("short"), 0600))
+		_, err := LoadKeyFile(path)
+		assert.Error(t, err)
+	})
+}
+
+func TestKeyFromEnv(t *testing.T) {
+	t.Run("returns nil without configuration", func(t *testing.T) {
+		t.Setenv(PassphraseEnv, "")
+		t.Setenv(KeyFileEnv, "")
+		key, err := KeyFromEnv()
+		require.NoError(t, err)
+		assert.Nil(t, key)
+	})
+
+	t.Run("prefers the keyfile", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "key")
+		require.NoError(t, os.WriteFile(path, []byte(strings.Repeat("k", 32)), 0600))
+		t.Setenv(PassphraseEnv, "passphrase")
+		t.Setenv(KeyFileEnv, path)
+
+		key, err := KeyFromEnv()
+		require.NoError(t, err)
+		assert.Equal(t, KDFHKDF, key.kdf)
+	})
+}
+
+func TestRedactor(t *testing.T) {
+	t.Run("masks values and their encoded forms", func(t *testing.T) {
+		r := NewRedactor("s3cr3t", `p@ss "word"`)
+
+		assert.Equal(t, "Bearer ********", r.Redact("Bearer s3cr3t"))
+		assert.Equal(t, `{"password":"********"}`, r.Redact(`{"password":"p@ss \"word\""}`))
+		assert.Equal(t, "?p=********", r.Redact("?p=p%40ss+%22word%22"))
+		assert.Equal(t, map[string]string{"X-Token": "********"}, r.RedactMap(map[string]string{"X-Token": "s3cr3t"}))
+	})
+
+	t.Run("masks the longest value first", func(t *testing.T) {
+		r := NewRedactor("abcd", "abcdefgh")
+		assert.Equal(t, "********", r.Redact("abcdefgh"))
+	})
+
+	t.Run("ignores short values", func(t *testing.T) {
+		assert.Nil(t, NewRedactor("a", "", "xyz"))
+		var r *Redactor
+		assert.Equal(t, "a b c", r.Redact("a b c"))
+	})
+}
+
+func TestResolver(t *testing.T) {
+	t.Run("resolves environment variables and files", func(t *testing.T) {
+		r := NewResolver()
+		t.Setenv("CURRIER_TEST_SECRET", "from-env")
+		path := filepath.Join(t.TempDir(), "secret")
+		require.NoError(t, os.WriteFile(path, []byte("from-file\n"), 0600))
+
+		value, err := r.Resolve("env:CURRIER_TEST_SECRET")
+		require.NoError(t, err)
+		assert.Equal(t, "from-env", value)
+
+		value, err = r.Resolve("file:" + path)
+		require.NoError(t, err)
+		assert.Equal(t, "from-file", value)
+
+		assert.ElementsMatch(t, []string{"from-env", "from-file"}, r.Values())
+	})
+
+	t.Run("rejects bad references", func(t *testing.T) {
+		r := NewResolver()
+		_, err := r.Resolve("API_KEY")
+		assert.ErrorContains(t, err, "invalid secret reference")
+		_, err = r.Resolve("vault:api")
+		assert.ErrorContains(t, err, "unknown secret provider")
+		_, err = r.Resolve("env:CURRIER_TEST_UNSET_SECRET")
+		assert.ErrorContains(t, err, "is not set")
+	})
+
+	t.Run("uses registered providers", func(t *testing.T) {
+		r := NewResolver()
+		r.Register("vault", ProviderFunc(func(ref string) (string, error) { return "vault-" + ref, nil }))
+		value, err := r.Resolve("vault:api/prod")
+		require.NoError(t, err)
+		assert.Equal(t, "vault-api/prod", value)
+	})
+
+	t.Run("runs commands once when allowed", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("uses a POSIX shell")
+		}
+		r := NewResolver()
+		counter := filepath.Join(t.TempDir(), "count")
+		command := "cmd:echo run >> " + counter + "; echo s3cr3t"
+
+		t.Setenv(AllowCommandsEnv, "")
+		_, err := r.Resolve(command)
+		assert.ErrorContains(t, err, "commands are disabled")
+
+		t.Setenv(AllowCommandsEnv, "1")
+		for i := 0; i < 2; i++ {
+			value, err := r.Resolve(command)
+			require.NoError(t, err)
+			assert.Equal(t, "s3cr3t", value)
+		}
+		data, err := os.ReadFile(counter)
+		require.NoError(t, err)
+		assert.Equal(t, "run\n", string(data))
+	})
+}
@@ -0,0 +1,524 @@
+package filesystem
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/artpar/currier/internal/core"
+	"gopkg.in/yaml.v3"
+)
+
+// Layout is how a collection is laid out on disk.
+type Layout string
+
+const (
+	// LayoutFile keeps a collection in a single YAML file named by its ID.
+	LayoutFile Layout = "file"
+	// LayoutDirectory keeps a collection in a directory tree mirroring its
+	// folders, with one file per request and no timestamps, so collections
+	// checked into a repository diff and merge like code.
+	LayoutDirectory Layout = "directory"
+)
+
+// ParseLayout parses a layout name.
+func ParseLayout(name string) (Layout, error) {
+	switch layout := Layout(name); layout {
+	case LayoutFile, LayoutDirectory:
+		return layout, nil
+	}
+	return "", fmt.Errorf("unknown collection layout %q: expected %s or %s", name, LayoutFile, LayoutDirectory)
+}
+
+// Files of the directory layout. A collection directory holds
+// _collection.yaml, a subdirectory with a _folder.yaml per folder, a
+// <name>.request.yaml per request, and an _order.txt per directory listing
+// its folders and requests in order.
+const (
+	collectionFile = "_collection.yaml"
+	folderFile     = "_folder.yaml"
+	orderFile      = "_order.txt"
+	requestSuffix  = ".request.yaml"
+)
+
+// sidecarLength is the length beyond which a body or script is kept in a
+// file of its own next to its request. Multi-line ones always are.
+const sidecarLength = 120
+
+// collectionDirData is the _collection.yaml of a collection directory: the
+// collection without its folders, requests or timestamps.
+type collectionDirData struct {
+	collectionData `yaml:",inline"`
+	PreScriptFile  string `yaml:"pre_script_file,omitempty"`
+	PostScriptFile string `yaml:"post_script_file,omitempty"`
+}
+
+// requestDirData is a request file of a collection directory.
+type requestDirData struct {
+	requestData    `yaml:",inline"`
+	BodyFile       string `yaml:"body_file,omitempty"`
+	PreScriptFile  string `yaml:"pre_script_file,omitempty"`
+	PostScriptFile string `yaml:"post_script_file,omitempty"`
+}
+
+// CollectionDirectory returns the collection directory path refers to, the
+// directory itself or its _collection.yaml, or "" if path is not a
+// collection in the directory layout.
+func CollectionDirectory(path string) string {
+	if filepath.Base(path) == collectionFile {
+		path = filepath.Dir(path)
+	}
+	if isCollectionDirectory(path) {
+		return path
+	}
+	return ""
+}
+
+// CollectionRoot returns the directory the relative paths of the collection
+// at path, such as its contract and script modules, resolve against: the
+// collection directory in the directory layout, else the directory holding
+// the collection file.
+func CollectionRoot(path string) string {
+	if dir := CollectionDirectory(path); dir != "" {
+		return dir
+	}
+	return filepath.Dir(path)
+}
+
+// isCollectionDirectory reports whether path is a collection directory.
+func isCollectionDirectory(path string) bool {
+	return fileExists(filepath.Join(path, collectionFile))
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+// findDirectory returns the collection directory in dir holding the
+// collection with the given ID, or "".
+func findDirectory(dir, id string) string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return ""
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		content, err := os.ReadFile(filepath.Join(path, collectionFile))
+		if err != nil {
+			continue
+		}
+		var header struct {
+			ID string `yaml:"id"`
+		}
+		if yaml.Unmarshal(content, &header) == nil && header.ID == id {
+			return path
+		}
+	}
+	return ""
+}
+
+// newDirectoryPath returns an unused path in dir named after a collection.
+func newDirectoryPath(dir, name string) string {
+	slug := slugify(name, "collection")
+	path := filepath.Join(dir, slug)
+	for i := 2; pathExists(path); i++ {
+		path = filepath.Join(dir, slug+"-"+strconv.Itoa(i))
+	}
+	return path
+}
+
+func pathExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// slugify turns a name into a file name: lowercase letters and digits
+// separated by dashes.
+func slugify(name, fallback string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+		} else {
+			dash = true
+		}
+		if b.Len() >= 60 {
+			break
+		}
+	}
+	if b.Len() == 0 {
+		return fallback
+	}
+	return b.String()
+}
+
+// uniqueSlug returns the slug of name, numbered if another entry of the same
+// directory already uses it.
+func uniqueSlug(name, fallback string, used map[string]bool) string {
+	slug := slugify(name, fallback)
+	unique := slug
+	for i := 2; used[unique]; i++ {
+		unique = slug + "-" + strconv.Itoa(i)
+	}
+	used[unique] = true
+	return unique
+}
+
+// bodyExtension returns the file extension for a request body sidecar.
+func bodyExtension(r requestData) string {
+	kind := strings.ToLower(r.BodyType + " " + r.BodyContentType)
+	for _, ext := range []string{"json", "xml", "graphql", "html", "yaml", "javascript"} {
+		if strings.Contains(kind, ext) {
+			if ext == "javascript" {
+				return ".js"
+			}
+			return "." + ext
+		}
+	}
+	return ".txt"
+}
+
+// Writing
+
+// dirWriter renders a collection into the files of its directory, keyed by
+// path relative to the collection directory.
+type dirWriter struct {
+	files map[string][]byte
+}
+
+func (w *dirWriter) yaml(path string, v any) error {
+	content, err := yaml.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", path, err)
+	}
+	w.files[path] = content
+	return nil
+}
+
+// sidecar moves long content into its own file, returning what stays inline
+// and the name of the file.
+func (w *dirWriter) sidecar(dir, name, content string) (string, string) {
+	if len(content) <= sidecarLength && !strings.Contains(content, "\n") {
+		return content, ""
+	}
+	w.files[filepath.Join(dir, name)] = []byte(content)
+	return "", name
+}
+
+func (w *dirWriter) collection(data *collectionData) error {
+	header := collectionDirData{collectionData: *data}
+	header.Folders, header.Requests = nil, nil
+	header.CreatedAt, header.UpdatedAt = time.Time{}, time.Time{}
+	header.WebSockets = nil
+	for _, ws := range data.WebSockets {
+		ws.CreatedAt, ws.UpdatedAt = time.Time{}, time.Time{}
+		header.WebSockets = append(header.WebSockets, ws)
+	}
+	header.PreScript, header.PreScriptFile = w.sidecar("", "_collection.pre.js", data.PreScript)
+	header.PostScript, header.PostScriptFile = w.sidecar("", "_collection.post.js", data.PostScript)
+
+	if err := w.yaml(collectionFile, header); err != nil {
+		return err
+	}
+	return w.entries("", data.Folders, data.Requests)
+}
+
+func (w *dirWriter) entries(dir string, folders []folderData, requests []requestData) error {
+	used := make(map[string]bool)
+	var order []string
+
+	for _, f := range folders {
+		name := uniqueSlug(f.Name, "folder", used)
+		sub := filepath.Join(dir, name)
+		meta := f
+		meta.Folders, meta.Requests = nil, nil
+		if err := w.yaml(filepath.Join(sub, folderFile), meta); err != nil {
+			return err
+		}
+		if err := w.entries(sub, f.Folders, f.Requests); err != nil {
+			return err
+		}
+		order = append(order, name+"/")
+	}
+
+	for _, r := range requests {
+		slug := uniqueSlug(r.Name, "request", used)
+		file := requestDirData{requestData: r}
+		// A binary body is the path of the file to send
+		if r.BodyType != "binary" {
+			file.BodyContent, file.BodyFile = w.sidecar(dir, slug+".body"+bodyExtension(r), r.BodyContent)
+		}
+		file.PreScript, file.PreScriptFile = w.sidecar(dir, slug+".pre.js", r.PreScript)
+		file.PostScript, file.PostScriptFile = w.sidecar(dir, slug+".post.js", r.PostScript)
+
+		name := slug + requestSuffix
+		if err := w.yaml(filepath.Join(dir, name), file); err != nil {
+			return err
+		}
+		order = append(order, name)
+	}
+
+	if len(order) > 0 {
+		w.files[filepath.Join(dir, orderFile)] = []byte(strings.Join(order, "\n") + "\n")
+	}
+	return nil
+}
+
+// saveDirectory writes a collection in the directory layout. Files whose
+// content is unchanged are left alone, and files of folders and requests
+// that no longer exist are removed.
+func (s *CollectionStore) saveDirectory(dir string, c *core.Collection) error {
+	w := &dirWriter{files: make(map[string][]byte)}
+	if err := w.collection(s.toStorageFormat(c)); err != nil {
+		return err
+	}
+
+	for rel, content := range w.files {
+		path := filepath.Join(dir, rel)
+		if existing, err := os.ReadFile(path); err == nil && bytes.Equal(existing, content) {
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fmt.Errorf("failed to create collection directory: %w", err)
+		}
+		if err := os.WriteFile(path, content, 0644); err != nil {
+			return fmt.Errorf("failed to write collection file: %w", err)
+		}
+	}
+
+	return removeStale(dir, w.files)
+}
+
+// removeStale removes the collection files under dir that aren't in keep,
+// then any directories left empty. Other files are left alone.
+func removeStale(dir string, keep map[string][]byte) error {
+	var dirs []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != dir {
+				dirs = append(dirs, path)
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if _, ok := keep[rel]; ok || !isCollectionFileName(d.Name()) {
+			return nil
+		}
+		return os.Remove(path)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to remove stale collection files: %w", err)
+	}
+
+	// Deepest first, so parents are empty by the time they're reached
+	sort.Sort(sort.Reverse(sort.StringSlice(dirs)))
+	for _, path := range dirs {
+		if entries, err := os.ReadDir(path); err == nil && len(entries) == 0 {
+			_ = os.Remove(path)
+		}
+	}
+	return nil
+}
+
+// isCollectionFileName reports whether a file name is one the directory
+// layout writes.
+func isCollectionFileName(name string) bool {
+	switch {
+	case name == collectionFile, name == folderFile, name == orderFile:
+		return true
+	case strings.HasSuffix(name, requestSuffix):
+		return true
+	case strings.HasSuffix(name, ".pre.js"), strings.HasSuffix(name, ".post.js"):
+		return true
+	}
+	return strings.Contains(name, ".body.")
+}
+
+// Reading
+
+// dirReader reads the files of a collection directory. As the layout keeps
+// no timestamps, the collection's are the oldest and newest modification
+// times of its files.
+type dirReader struct {
+	created time.Time
+	updated time.Time
+}
+
+func (r *dirReader) read(path string) ([]byte, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read collection file: %w", err)
+	}
+	if info, err := os.Stat(path); err == nil {
+		mod := info.ModTime()
+		if r.created.IsZero() || mod.Before(r.created) {
+			r.created = mod
+		}
+		if mod.After(r.updated) {
+			r.updated = mod
+		}
+	}
+	return content, nil
+}
+
+// sidecar returns the content of a sidecar file, or inline if there is none.
+func (r *dirReader) sidecar(dir, name, inline string) (string, error) {
+	if name == "" {
+		return inline, nil
+	}
+	content, err := r.read(filepath.Join(dir, filepath.Base(name)))
+	if err != nil {
+		return "", err
+	}
+	return string(content), nil
+}
+
+// loadDirectory loads a collection in the directory layout.
+func (s *CollectionStore) loadDirectory(dir string) (*core.Collection, error) {
+	r := &dirReader{}
+	content, err := r.read(filepath.Join(dir, collectionFile))
+	if err != nil {
+		return nil, err
+	}
+	doc, err := migrateCollection(content)
+	if err != nil {
+		return nil, err
+	}
+	var header collectionDirData
+	if err := doc.Decode(&header); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal collection: %w", err)
+	}
+
+	data := header.collectionData
+	if data.PreScript, err = r.sidecar(dir, header.PreScriptFile, data.PreScript); err != nil {
+		return nil, err
+	}
+	if data.PostScript, err = r.sidecar(dir, header.PostScriptFile, data.PostScript); err != nil {
+		return nil, err
+	}
+	if data.Folders, data.Requests, err = r.entries(dir); err != nil {
+		return nil, err
+	}
+
+	data.CreatedAt, data.UpdatedAt = r.created, r.updated
+	for i := range data.WebSockets {
+		data.WebSockets[i].CreatedAt, data.WebSockets[i].UpdatedAt = r.created, r.updated
+	}
+
+	return s.fromStorageFormat(&data), nil
+}
+
+func (r *dirReader) entries(dir string) ([]folderData, []requestData, error) {
+	names, err := entryOrder(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var folders []folderData
+	var requests []requestData
+	for _, name := range names {
+		if sub, ok := strings.CutSuffix(name, "/"); ok {
+			path := filepath.Join(dir, sub)
+			content, err := r.read(filepath.Join(path, folderFile))
+			if err != nil {
+				return nil, nil, err
+			}
+			var f folderData
+			if err := yaml.Unmarshal(content, &f); err != nil {
+				return nil, nil, fmt.Errorf("failed to unmarshal %s: %w", path, err)
+			}
+			if f.Folders, f.Requests, err = r.entries(path); err != nil {
+				return nil, nil, err
+			}
+			folders = append(folders, f)
+			continue
+		}
+
+		path := filepath.Join(dir, name)
+		content, err := r.read(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		var file requestDirData
+		if err := yaml.Unmarshal(content, &file); err != nil {
+			return nil, nil, fmt.Errorf("failed to unmarshal %s: %w", path, err)
+		}
+		req := file.requestData
+		if req.BodyContent, err = r.sidecar(dir, file.BodyFile, req.BodyContent); err != nil {
+			return nil, nil, err
+		}
+		if req.PreScript, err = r.sidecar(dir, file.PreScriptFile, req.PreScript); err != nil {
+			return nil, nil, err
+		}
+		if req.PostScript, err = r.sidecar(dir, file.PostScriptFile, req.PostScript); err != nil {
+			return nil, nil, err
+		}
+		requests = append(requests, req)
+	}
+	return folders, requests, nil
+}
+
+// entryOrder lists the folders ("name/") and request files of a collection
+// directory: those in its order file first, then any others by name, such
+// as one a merge added without its order entry.
+func entryOrder(dir string) ([]string, error) {
+	exists := func(name string) bool {
+		if sub, ok := strings.CutSuffix(name, "/"); ok {
+			return fileExists(filepath.Join(dir, sub, folderFile))
+		}
+		return strings.HasSuffix(name, requestSuffix) && fileExists(filepath.Join(dir, name))
+	}
+
+	listed := make(map[string]bool)
+	var names []string
+
+	content, err := os.ReadFile(filepath.Join(dir, orderFile))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read collection order: %w", err)
+	}
+	for _, line := range strings.Split(string(content), "\n") {
+		name := strings.TrimSpace(line)
+		if name == "" || listed[name] || !exists(name) {
+			continue
+		}
+		listed[name] = true
+		names = append(names, name)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read collection directory: %w", err)
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() {
+			name += "/"
+		}
+		if !listed[name] && exists(name) {
+			listed[name] = true
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
@@ -0,0 +1,267 @@
+package filesystem
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/artpar/currier/internal/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newDirectoryTestCollection() *core.Collection {
+	c := core.NewCollection("Orders API")
+	c.SetDescription("Order endpoints")
+	c.SetVariable("baseUrl", "https://api.example.com")
+	c.SetAuth(core.AuthConfig{Type: "bearer", Token: "{{token}}"})
+	c.SetPreScript("currier.variables.set('started', Date.now())\nconsole.log('run')")
+
+	orders := c.AddFolder("Orders")
+	orders.SetSequential(true)
+	orders.SetVariable("orderId", "7")
+	archive := orders.AddFolder("Archive")
+
+	create := core.NewRequestDefinition("Create Order", "POST", "{{baseUrl}}/orders")
+	create.SetHeader("Content-Type", "application/json")
+	create.SetQueryParam("notify", "true")
+	create.SetBody("{\n  \"item\": \"book\"\n}")
+	create.SetBodyType("json")
+	create.SetPostScript("currier.test('created', () => {\n  currier.expect(currier.response.status).to.equal(201)\n})")
+	create.SetAssertions([]core.Assertion{{Source: "status", Operator: "eq", Value: "201"}})
+	orders.AddRequest(create)
+
+	// Same name, so the second file is numbered
+	get := core.NewRequestDefinition("Get Order", "GET", "{{baseUrl}}/orders/1")
+	orders.AddRequest(get)
+	orders.AddRequest(core.NewRequestDefinition("Get Order", "GET", "{{baseUrl}}/orders/2"))
+
+	upload := core.NewRequestDefinition("Upload Receipt", "POST", "{{baseUrl}}/receipts")
+	upload.SetBodyFormData([]core.FormField{{Key: "file", IsFile: true, FilePath: "/tmp/receipt.pdf"}})
+	archive.AddRequest(upload)
+
+	c.AddRequest(core.NewRequestDefinition("Health", "GET", "{{baseUrl}}/health"))
+	c.AddWebSocket(core.NewWebSocketDefinition("Order Events", "wss://api.example.com/events"))
+	return c
+}
+
+// withTimestampsOf copies the timestamps the directory layout doesn't keep.
+func withTimestampsOf(loaded, original *core.Collection) *core.Collection {
+	loaded.SetTimestamps(original.CreatedAt(), original.UpdatedAt())
+	for i, ws := range loaded.WebSockets() {
+		ws.CreatedAt = original.WebSockets()[i].CreatedAt
+		ws.UpdatedAt = original.WebSockets()[i].UpdatedAt
+	}
+	return loaded
+}
+
+func TestCollectionStore_DirectoryLayout(t *testing.T) {
+	t.Run("saves and loads a collection losslessly", func(t *testing.T) {
+		dir := t.TempDir()
+		store, err := NewCollectionStore(dir, WithLayout(LayoutDirectory))
+		require.NoError(t, err)
+		ctx := context.Background()
+
+		c := newDirectoryTestCollection()
+		require.NoError(t, store.Save(ctx, c))
+
+		loaded, err := store.Get(ctx, c.ID())
+		require.NoError(t, err)
+		assert.Equal(t, c, withTimestampsOf(loaded, c))
+	})
+
+	t.Run("writes one file per request under folder directories", func(t *testing.T) {
+		dir := t.TempDir()
+		store, err := NewCollectionStore(dir, WithLayout(LayoutDirectory))
+		require.NoError(t, err)
+
+		require.NoError(t, store.Save(context.Background(), newDirectoryTestCollection()))
+
+		root := filepath.Join(dir, "orders-api")
+		for _, name := range []string{
+			"_collection.yaml",
+			"_collection.pre.js",
+			"_order.txt",
+			"health.request.yaml",
+			"orders/_folder.yaml",
+			"orders/create-order.request.yaml",
+			"orders/create-order.body.json",
+			"orders/create-order.post.js",
+			"orders/get-order.request.yaml",
+			"orders/get-order-2.request.yaml",
+			"orders/archive/_folder.yaml",
+			"orders/archive/upload-receipt.request.yaml",
+		} {
+			assert.FileExists(t, filepath.Join(root, name))
+		}
+
+		order, err := os.ReadFile(filepath.Join(root, "orders", "_order.txt"))
+		require.NoError(t, err)
+		assert.Equal(t, "archive/\ncreate-order.request.yaml\nget-order.request.yaml\nget-order-2.request.yaml\n", string(order))
+
+		body, err := os.ReadFile(filepath.Join(root, "orders", "create-order.body.json"))
+		require.NoError(t, err)
+		assert.Equal(t, "{\n  \"item\": \"book\"\n}", string(body))
+
+		err = filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+			require.NoError(t, err)
+			if !d.IsDir() {
+				content, err := os.ReadFile(path)
+				require.NoError(t, err)
+				assert.NotContains(t, string(content), "created_at", path)
+				assert.NotContains(t, string(content), "updated_at", path)
+			}
+			return nil
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("saving again removes files of deleted requests and keeps other files", func(t *testing.T) {
+		dir := t.TempDir()
+		store, err := NewCollectionStore(dir, WithLayout(LayoutDirectory))
+		require.NoError(t, err)
+		ctx := context.Background()
+
+		c := newDirectoryTestCollection()
+		require.NoError(t, store.Save(ctx, c))
+		root := filepath.Join(dir, "orders-api")
+		require.NoError(t, os.WriteFile(filepath.Join(root, "README.md"), []byte("docs"), 0644))
+
+		orders := c.Folders()[0]
+		orders.RemoveRequest(orders.Requests()[0].ID())
+		orders.RemoveFolder(orders.Folders()[0].ID())
+		require.NoError(t, store.Save(ctx, c))
+
+		assert.NoFileExists(t, filepath.Join(root, "orders", "create-order.request.yaml"))
+		assert.NoFileExists(t, filepath.Join(root, "orders", "create-order.body.json"))
+		assert.NoDirExists(t, filepath.Join(root, "orders", "archive"))
+		assert.FileExists(t, filepath.Join(root, "README.md"))
+
+		loaded, err := store.Get(ctx, c.ID())
+		require.NoError(t, err)
+		assert.Equal(t, c, withTimestampsOf(loaded, c))
+	})
+
+	t.Run("follows the order file and picks up unlisted requests", func(t *testing.T) {
+		dir := t.TempDir()
+		store, err := NewCollectionStore(dir, WithLayout(LayoutDirectory))
+		require.NoError(t, err)
+		ctx := context.Background()
+
+		c := core.NewCollection("Ordered")
+		c.AddRequest(core.NewRequestDefinition("First", "GET", "https://example.com/1"))
+		c.AddRequest(core.NewRequestDefinition("Second", "GET", "https://example.com/2"))
+		require.NoError(t, store.Save(ctx, c))
+
+		root := filepath.Join(dir, "ordered")
+		require.NoError(t, os.WriteFile(filepath.Join(root, "_order.txt"), []byte("second.request.yaml\nfirst.request.yaml\n"), 0644))
+		added := "id: added\nname: Added\nmethod: GET\nurl: https://example.com/added\n"
+		require.NoError(t, os.WriteFile(filepath.Join(root, "added.request.yaml"), []byte(added), 0644))
+
+		loaded, err := store.Get(ctx, c.ID())
+		require.NoError(t, err)
+		var names []string
+		for _, r := range loaded.Requests() {
+			names = append(names, r.Name())
+		}
+		assert.Equal(t, []string{"Second", "First", "Added"}, names)
+	})
+
+	t.Run("lists, loads by path and deletes collection directories", func(t *testing.T) {
+		dir := t.TempDir()
+		store, err := NewCollectionStore(dir)
+		require.NoError(t, err)
+		ctx := context.Background()
+
+		file := core.NewCollection("File")
+		require.NoError(t, store.Save(ctx, file))
+		tree, err := NewCollectionStore(dir, WithLayout(LayoutDirectory))
+		require.NoError(t, err)
+		c := newDirectoryTestCollection()
+		require.NoError(t, tree.Save(ctx, c))
+
+		metas, err := store.List(ctx)
+		require.NoError(t, err)
+		require.Len(t, metas, 2)
+		paths := map[string]string{}
+		for _, meta := range metas {
+			paths[meta.ID] = meta.Path
+		}
+		assert.Equal(t, filepath.Join(dir, "orders-api"), paths[c.ID()])
+
+		byPath, err := store.GetByPath(ctx, filepath.Join(dir, "orders-api", "_collection.yaml"))
+		require.NoError(t, err)
+		assert.Equal(t, c.ID(), byPath.ID())
+
+		require.NoError(t, store.Delete(ctx, c.ID()))
+		assert.NoDirExists(t, filepath.Join(dir, "orders-api"))
+	})
+
+	t.Run("rejects a newer schema", func(t *testing.T) {
+		dir := t.TempDir()
+		store, err := NewCollectionStore(dir)
+		require.NoError(t, err)
+
+		root := filepath.Join(dir, "future")
+		require.NoError(t, os.MkdirAll(root, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(root, "_collection.yaml"), []byte("schema: 99\nid: future\nname: Future\n"), 0644))
+
+		_, err = store.Get(context.Background(), "future")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "schema 99 is newer")
+	})
+}
+
+func TestCollectionStore_Convert(t *testing.T) {
+	t.Run("converts between layouts without losing data", func(t *testing.T) {
+		dir := t.TempDir()
+		store, err := NewCollectionStore(dir)
+		require.NoError(t, err)
+		ctx := context.Background()
+
+		c := newDirectoryTestCollection()
+		require.NoError(t, store.Save(ctx, c))
+
+		path, err := store.Convert(ctx, c.ID(), LayoutDirectory)
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join(dir, "orders-api"), path)
+		assert.NoFileExists(t, filepath.Join(dir, c.ID()+".yaml"))
+
+		loaded, err := store.Get(ctx, c.ID())
+		require.NoError(t, err)
+		assert.Equal(t, c, withTimestampsOf(loaded, c))
+
+		// Saving keeps the collection in its directory
+		c.SetDescription("Changed")
+		require.NoError(t, store.Save(ctx, c))
+		assert.NoFileExists(t, filepath.Join(dir, c.ID()+".yaml"))
+
+		path, err = store.Convert(ctx, c.ID(), LayoutFile)
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join(dir, c.ID()+".yaml"), path)
+		assert.NoDirExists(t, filepath.Join(dir, "orders-api"))
+
+		loaded, err = store.Get(ctx, c.ID())
+		require.NoError(t, err)
+		assert.Equal(t, "Changed", loaded.Description())
+		assert.Equal(t, c, withTimestampsOf(loaded, c))
+	})
+
+	t.Run("returns an error for unknown collections", func(t *testing.T) {
+		store := newTestStore(t)
+		_, err := store.Convert(context.Background(), "missing", LayoutDirectory)
+		assert.Error(t, err)
+	})
+}
+
+func TestParseLayout(t *testing.T) {
+	layout, err := ParseLayout("directory")
+	require.NoError(t, err)
+	assert.Equal(t, LayoutDirectory, layout)
+
+	_, err = ParseLayout("zip")
+	assert.Error(t, err)
+	assert.True(t, strings.Contains(err.Error(), "unknown collection layout"))
+}
@@ -19,69 +19,181 @@ type CollectionMeta struct {
 	Description  string
 	Path         string
 	RequestCount int
+	Workspace    bool // Whether the collection belongs to the workspace
 	CreatedAt    time.Time
 	UpdatedAt    time.Time
 }
 
 // CollectionStore manages collection persistence to the filesystem.
 type CollectionStore struct {
-	basePath string
+	basePath  string
+	workspace string // Directory of a workspace's collections, layered over basePath
+	layout    Layout // Layout new collections are saved in
 }
 
-// NewCollectionStore creates a new filesystem-based collection store.
-func NewCollectionStore(basePath string) (*CollectionStore, error) {
-	// Create directory if it doesn't exist
-	if err := os.MkdirAll(basePath, 0755); err != nil {
-		return nil, fmt.Errorf("failed to create collections directory: %w", err)
+// CollectionOption configures a CollectionStore.
+type CollectionOption func(*CollectionStore)
+
+// WithLayout sets the layout new collections are saved in. Existing
+// collections keep theirs until converted with Convert.
+func WithLayout(layout Layout) CollectionOption {
+	return func(s *CollectionStore) {
+		s.layout = layout
 	}
+}
 
-	return &CollectionStore{
-		basePath: basePath,
-	}, nil
+// WithCollectionWorkspace layers the collections of a project workspace in
+// dir over the store's own. They are listed first, and new collections are
+// saved there.
+func WithCollectionWorkspace(dir string) CollectionOption {
+	return func(s *CollectionStore) {
+		s.workspace = dir
+	}
 }
 
-// Save persists a collection to disk.
-func (s *CollectionStore) Save(ctx context.Context, c *core.Collection) error {
-	data := s.toStorageFormat(c)
+// NewCollectionStore creates a new filesystem-based collection store.
+func NewCollectionStore(basePath string, opts ...CollectionOption) (*CollectionStore, error) {
+	s := &CollectionStore{
+		basePath: basePath,
+		layout:   LayoutFile,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
 
-	content, err := yaml.Marshal(data)
-	if err != nil {
-		return fmt.Errorf("failed to marshal collection: %w", err)
+	// Create directories if they don't exist
+	for _, dir := range s.dirs() {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create collections directory: %w", err)
+		}
 	}
+	return s, nil
+}
 
-	path := s.collectionPath(c.ID())
-	if err := os.WriteFile(path, content, 0644); err != nil {
-		return fmt.Errorf("failed to write collection file: %w", err)
+// CollectionDir returns the directory a collection's relative paths, such as
+// its contract and script modules, resolve against: the directory holding its
+// file, or its own directory in the directory layout, as for `currier run`.
+// For a collection not saved yet it is the directory the collection will be
+// saved in.
+func (s *CollectionStore) CollectionDir(id string) string {
+	if path, layout := s.locate(id); path != "" {
+		if layout == LayoutDirectory {
+			return path
+		}
+		return filepath.Dir(path)
 	}
+	return s.saveDir()
+}
 
-	return nil
+// Save persists a collection to disk, in the layout it already has there or,
+// for a new collection, the store's layout.
+func (s *CollectionStore) Save(ctx context.Context, c *core.Collection) error {
+	path, layout := s.locate(c.ID())
+	if path == "" {
+		layout = s.layout
+		if layout == LayoutDirectory {
+			path = newDirectoryPath(s.saveDir(), c.Name())
+		} else {
+			path = s.collectionPath(c.ID())
+		}
+	}
+
+	if layout == LayoutDirectory {
+		return s.saveDirectory(path, c)
+	}
+	return s.saveFile(path, c)
 }
 
 // Get retrieves a collection by ID.
 func (s *CollectionStore) Get(ctx context.Context, id string) (*core.Collection, error) {
-	path := s.collectionPath(id)
+	path, _ := s.locate(id)
+	if path == "" {
+		path = s.collectionPath(id)
+	}
 	return s.loadFromPath(path)
 }
 
-// GetByPath retrieves a collection by file path.
+// GetByPath retrieves a collection by file path. The path of a collection
+// in the directory layout is its directory.
 func (s *CollectionStore) GetByPath(ctx context.Context, path string) (*core.Collection, error) {
 	return s.loadFromPath(path)
 }
 
-// List returns all collections.
+// Convert moves a collection to the given layout and returns its new path.
+func (s *CollectionStore) Convert(ctx context.Context, id string, layout Layout) (string, error) {
+	path, current := s.locate(id)
+	if path == "" {
+		return "", fmt.Errorf("collection not found: %s", id)
+	}
+	if current == layout {
+		return path, nil
+	}
+
+	c, err := s.loadFromPath(path)
+	if err != nil {
+		return "", err
+	}
+
+	// The converted collection stays in the same directory, workspace or not
+	dir := filepath.Dir(path)
+	var target string
+	switch layout {
+	case LayoutDirectory:
+		target = newDirectoryPath(dir, c.Name())
+		err = s.saveDirectory(target, c)
+	case LayoutFile:
+		target = filepath.Join(dir, id+".yaml")
+		err = s.saveFile(target, c)
+	default:
+		return "", fmt.Errorf("unknown collection layout: %s", layout)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.RemoveAll(path); err != nil {
+		return "", fmt.Errorf("failed to remove old collection: %w", err)
+	}
+	return target, nil
+}
+
+// List returns all collections, those of the workspace first.
 func (s *CollectionStore) List(ctx context.Context) ([]CollectionMeta, error) {
-	entries, err := os.ReadDir(s.basePath)
+	var collections []CollectionMeta
+	seen := make(map[string]bool)
+	for _, dir := range s.dirs() {
+		metas, err := s.list(dir)
+		if err != nil {
+			return nil, err
+		}
+		for _, meta := range metas {
+			// A workspace collection hides a copy of itself in the user's
+			if !seen[meta.ID] {
+				seen[meta.ID] = true
+				collections = append(collections, meta)
+			}
+		}
+	}
+	return collections, nil
+}
+
+func (s *CollectionStore) list(dir string) ([]CollectionMeta, error) {
+	entries, err := os.ReadDir(dir)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read collections directory: %w", err)
 	}
 
 	var collections []CollectionMeta
 	for _, entry := range entries {
-		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".yaml") {
+		path := filepath.Join(dir, entry.Name())
+		if entry.IsDir() {
+			if !isCollectionDirectory(path) {
+				continue
+			}
+		} else if !strings.HasSuffix(entry.Name(), ".yaml") {
 			continue
 		}
 
-		path := filepath.Join(s.basePath, entry.Name())
 		c, err := s.loadFromPath(path)
 		if err != nil {
 			continue // Skip invalid files
@@ -93,6 +205,7 @@ func (s *CollectionStore) List(ctx context.Context) ([]CollectionMeta, error) {
 			Description:  c.Description(),
 			Path:         path,
 			RequestCount: s.countRequests(c),
+			Workspace:    s.workspace != "" && dir == s.workspace,
 			CreatedAt:    c.CreatedAt(),
 			UpdatedAt:    c.UpdatedAt(),
 		})
@@ -103,13 +216,12 @@ func (s *CollectionStore) List(ctx context.Context) ([]CollectionMeta, error) {
 
 // Delete removes a collection.
 func (s *CollectionStore) Delete(ctx context.Context, id string) error {
-	path := s.collectionPath(id)
-
-	if _, err := os.Stat(path); os.IsNotExist(err) {
+	path, _ := s.locate(id)
+	if path == "" {
 		return fmt.Errorf("collection not found: %s", id)
 	}
 
-	if err := os.Remove(path); err != nil {
+	if err := os.RemoveAll(path); err != nil {
 		return fmt.Errorf("failed to delete collection: %w", err)
 	}
 
@@ -138,22 +250,70 @@ func (s *CollectionStore) Search(ctx context.Context, query string) ([]Collectio
 
 // Internal helpers
 
+// collectionPath returns the path of a new collection in the file layout.
 func (s *CollectionStore) collectionPath(id string) string {
-	return filepath.Join(s.basePath, id+".yaml")
+	return filepath.Join(s.saveDir(), id+".yaml")
+}
+
+// dirs returns the directories collections are read from, in order.
+func (s *CollectionStore) dirs() []string {
+	if s.workspace != "" {
+		return []string{s.workspace, s.basePath}
+	}
+	return []string{s.basePath}
+}
+
+// saveDir returns the directory new collections are saved in.
+func (s *CollectionStore) saveDir() string {
+	return s.dirs()[0]
+}
+
+// locate returns the path and layout of the collection with the given ID, or
+// an empty path if there is none.
+func (s *CollectionStore) locate(id string) (string, Layout) {
+	for _, dir := range s.dirs() {
+		if path := filepath.Join(dir, id+".yaml"); fileExists(path) {
+			return path, LayoutFile
+		}
+		if path := findDirectory(dir, id); path != "" {
+			return path, LayoutDirectory
+		}
+	}
+	return "", ""
+}
+
+func (s *CollectionStore) saveFile(path string, c *core.Collection) error {
+	content, err := yaml.Marshal(s.toStorageFormat(c))
+	if err != nil {
+		return fmt.Errorf("failed to marshal collection: %w", err)
+	}
+
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		return fmt.Errorf("failed to write collection file: %w", err)
+	}
+
+	return nil
 }
 
 func (s *CollectionStore) loadFromPath(path string) (*core.Collection, error) {
+	if filepath.Base(path) == collectionFile {
+		path = filepath.Dir(path)
+	}
+	if isCollectionDirectory(path) {
+		return s.loadDirectory(path)
+	}
+
 	content, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read collection file: %w", err)
 	}
 
-	var data collectionData
-	if err := yaml.Unmarshal(content, &data); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal collection: %w", err)
+	data, err := decodeCollection(content)
+	if err != nil {
+		return nil, err
 	}
 
-	return s.fromStorageFormat(&data), nil
+	return s.fromStorageFormat(data), nil
 }
 
 func (s *CollectionStore) countRequests(c *core.Collection) int {
@@ -174,7 +334,70 @@ func (s *CollectionStore) countFolderRequests(f *core.Folder) int {
 
 // Storage format types
 
+// collectionSchema is the version of the collection file format written by
+// Save. Files record it in their schema field; files without one are
+// version 1.
+const collectionSchema = 2
+
+// collectionMigrations upgrade a collection document from the version of
+// their index + 1 to the next one, in order.
+var collectionMigrations = []func(doc *yaml.Node) error{
+	// Version 2 added query params, request auth with OAuth 2.0 and AWS
+	// settings, and websockets. The new fields are optional, so version 1
+	// documents decode unchanged.
+	func(doc *yaml.Node) error { return nil },
+}
+
+// decodeCollection decodes a collection file, migrating it from the schema
+// it was written with.
+func decodeCollection(content []byte) (*collectionData, error) {
+	doc, err := migrateCollection(content)
+	if err != nil {
+		return nil, err
+	}
+
+	var data collectionData
+	if err := doc.Decode(&data); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal collection: %w", err)
+	}
+	return &data, nil
+}
+
+// migrateCollection parses a collection document and migrates it to the
+// current schema. Documents from a newer schema are rejected rather than
+// loaded without the fields this version doesn't know, which saving would
+// then drop. In the directory layout, the document is the collection's
+// _collection.yaml.
+func migrateCollection(content []byte) (*yaml.Node, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal collection: %w", err)
+	}
+
+	var header struct {
+		Schema int `yaml:"schema"`
+	}
+	if err := doc.Decode(&header); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal collection: %w", err)
+	}
+	version := header.Schema
+	if version == 0 {
+		version = 1
+	}
+	if version > collectionSchema {
+		return nil, fmt.Errorf("collection schema %d is newer than the supported schema %d; upgrade currier to open it", version, collectionSchema)
+	}
+
+	for ; version < collectionSchema; version++ {
+		if err := collectionMigrations[version-1](&doc); err != nil {
+			return nil, fmt.Errorf("failed to migrate collection from schema %d: %w", version, err)
+		}
+	}
+	return &doc, nil
+}
+
 type collectionData struct {
+	Schema      int               `yaml:"schema"`
 	ID          string            `yaml:"id"`
 	Name        string            `yaml:"name"`
 	Description string            `yaml:"description,omitempty"`
@@ -183,48 +406,136 @@ type collectionData struct {
 	Auth        authData          `yaml:"auth,omitempty"`
 	PreScript   string            `yaml:"pre_script,omitempty"`
 	PostScript  string            `yaml:"post_script,omitempty"`
+	Contract    string            `yaml:"contract,omitempty"`
 	Folders     []folderData      `yaml:"folders,omitempty"`
 	Requests    []requestData     `yaml:"requests,omitempty"`
-	CreatedAt   time.Time         `yaml:"created_at"`
-	UpdatedAt   time.Time         `yaml:"updated_at"`
+	WebSockets  []websocketData   `yaml:"websockets,omitempty"`
+	CreatedAt   time.Time         `yaml:"created_at,omitempty"`
+	UpdatedAt   time.Time         `yaml:"updated_at,omitempty"`
 }
 
 type folderData struct {
-	ID          string        `yaml:"id"`
-	Name        string        `yaml:"name"`
-	Description string        `yaml:"description,omitempty"`
-	Folders     []folderData  `yaml:"folders,omitempty"`
-	Requests    []requestData `yaml:"requests,omitempty"`
-}
-
-type requestData struct {
 	ID          string            `yaml:"id"`
 	Name        string            `yaml:"name"`
 	Description string            `yaml:"description,omitempty"`
-	Method      string            `yaml:"method"`
-	URL         string            `yaml:"url"`
-	Headers     map[string]string `yaml:"headers,omitempty"`
-	BodyType    string            `yaml:"body_type,omitempty"`
-	BodyContent string            `yaml:"body_content,omitempty"`
-	Auth        *authData         `yaml:"auth,omitempty"`
-	PreScript   string            `yaml:"pre_script,omitempty"`
-	PostScript  string            `yaml:"post_script,omitempty"`
+	Sequential  bool              `yaml:"sequential,omitempty"`
+	Variables   map[string]string `yaml:"variables,omitempty"`
+	Folders     []folderData      `yaml:"folders,omitempty"`
+	Requests    []requestData     `yaml:"requests,omitempty"`
 }
 
-type authData struct {
-	Type     string `yaml:"type,omitempty"`
-	Token    string `yaml:"token,omitempty"`
-	Username string `yaml:"username,omitempty"`
-	Password string `yaml:"password,omitempty"`
-	Key      string `yaml:"key,omitempty"`
+type requestData struct {
+	ID              string            `yaml:"id"`
+	Name            string            `yaml:"name"`
+	Description     string            `yaml:"description,omitempty"`
+	Method          string            `yaml:"method"`
+	URL             string            `yaml:"url"`
+	Headers         map[string]string `yaml:"headers,omitempty"`
+	QueryParams     map[string]string `yaml:"query_params,omitempty"`
+	BodyType        string            `yaml:"body_type,omitempty"`
+	BodyContent     string            `yaml:"body_content,omitempty"`
+	BodyContentType string            `yaml:"body_content_type,omitempty"`
+	FormFields      []formFieldData   `yaml:"form_fields,omitempty"`
+	Auth            *authData         `yaml:"auth,omitempty"`
+	PreScript       string            `yaml:"pre_script,omitempty"`
+	PostScript      string            `yaml:"post_script,omitempty"`
+	SkipIf          string            `yaml:"skip_if,omitempty"`
+	Assertions      []assertionData   `yaml:"assertions,omitempty"`
+	Extract         []extractionData  `yaml:"extract,omitempty"`
+}
+
+type assertionData struct {
+	Source   string `yaml:"source"`
+	Property string `yaml:"property,omitempty"`
+	Operator string `yaml:"operator"`
 	Value    string `yaml:"value,omitempty"`
-	In       string `yaml:"in,omitempty"`
+}
+
+type extractionData struct {
+	Variable   string `yaml:"variable"`
+	Source     string `yaml:"source"`
+	Expression string `yaml:"expression"`
+}
+
+type formFieldData struct {
+	Key      string `yaml:"key"`
+	Value    string `yaml:"value,omitempty"`
+	IsFile   bool   `yaml:"is_file,omitempty"`
+	FilePath string `yaml:"file_path,omitempty"`
+	FileName string `yaml:"file_name,omitempty"`
+}
+
+type authData struct {
+	Type     string      `yaml:"type,omitempty"`
+	Token    string      `yaml:"token,omitempty"`
+	Username string      `yaml:"username,omitempty"`
+	Password string      `yaml:"password,omitempty"`
+	Key      string      `yaml:"key,omitempty"`
+	Value    string      `yaml:"value,omitempty"`
+	In       string      `yaml:"in,omitempty"`
+	OAuth2   *oauth2Data `yaml:"oauth2,omitempty"`
+	AWS      *awsData    `yaml:"aws,omitempty"`
+}
+
+type oauth2Data struct {
+	GrantType        string `yaml:"grant_type,omitempty"`
+	AuthURL          string `yaml:"auth_url,omitempty"`
+	TokenURL         string `yaml:"token_url,omitempty"`
+	ClientID         string `yaml:"client_id,omitempty"`
+	ClientSecret     string `yaml:"client_secret,omitempty"`
+	Scope            string `yaml:"scope,omitempty"`
+	State            string `yaml:"state,omitempty"`
+	RedirectURI      string `yaml:"redirect_uri,omitempty"`
+	AccessToken      string `yaml:"access_token,omitempty"`
+	RefreshToken     string `yaml:"refresh_token,omitempty"`
+	TokenType        string `yaml:"token_type,omitempty"`
+	ExpiresIn        int64  `yaml:"expires_in,omitempty"`
+	HeaderPrefix     string `yaml:"header_prefix,omitempty"`
+	AddTokenTo       string `yaml:"add_token_to,omitempty"`
+	UsePKCE          bool   `yaml:"use_pkce,omitempty"`
+	PKCECodeVerifier string `yaml:"pkce_code_verifier,omitempty"`
+}
+
+type awsData struct {
+	AccessKeyID     string `yaml:"access_key_id,omitempty"`
+	SecretAccessKey string `yaml:"secret_access_key,omitempty"`
+	SessionToken    string `yaml:"session_token,omitempty"`
+	Region          string `yaml:"region,omitempty"`
+	Service         string `yaml:"service,omitempty"`
+}
+
+type websocketData struct {
+	ID                   string             `yaml:"id"`
+	Name                 string             `yaml:"name"`
+	Endpoint             string             `yaml:"endpoint"`
+	Headers              map[string]string  `yaml:"headers,omitempty"`
+	Subprotocols         []string           `yaml:"subprotocols,omitempty"`
+	Auth                 *authData          `yaml:"auth,omitempty"`
+	PreConnectScript     string             `yaml:"pre_connect_script,omitempty"`
+	PreMessageScript     string             `yaml:"pre_message_script,omitempty"`
+	PostMessageScript    string             `yaml:"post_message_script,omitempty"`
+	FilterScript         string             `yaml:"filter_script,omitempty"`
+	AutoResponseRules    []autoResponseData `yaml:"auto_response_rules,omitempty"`
+	PingInterval         int                `yaml:"ping_interval"`
+	ReconnectEnabled     bool               `yaml:"reconnect_enabled"`
+	MaxReconnectAttempts int                `yaml:"max_reconnect_attempts"`
+	CreatedAt            time.Time          `yaml:"created_at,omitempty"`
+	UpdatedAt            time.Time          `yaml:"updated_at,omitempty"`
+}
+
+type autoResponseData struct {
+	ID          string `yaml:"id"`
+	Name        string `yaml:"name"`
+	MatchScript string `yaml:"match_script"`
+	Response    string `yaml:"response"`
+	Enabled     bool   `yaml:"enabled"`
 }
 
 // Conversion functions
 
 func (s *CollectionStore) toStorageFormat(c *core.Collection) *collectionData {
 	data := &collectionData{
+		Schema:      collectionSchema,
 		ID:          c.ID(),
 		Name:        c.Name(),
 		Description: c.Description(),
@@ -233,6 +544,7 @@ func (s *CollectionStore) toStorageFormat(c *core.Collection) *collectionData {
 		Auth:        toAuthData(c.Auth()),
 		PreScript:   c.PreScript(),
 		PostScript:  c.PostScript(),
+		Contract:    c.Contract(),
 		CreatedAt:   c.CreatedAt(),
 		UpdatedAt:   c.UpdatedAt(),
 	}
@@ -245,6 +557,10 @@ func (s *CollectionStore) toStorageFormat(c *core.Collection) *collectionData {
 		data.Requests = append(data.Requests, s.toRequestData(r))
 	}
 
+	for _, ws := range c.WebSockets() {
+		data.WebSockets = append(data.WebSockets, toWebSocketData(ws))
+	}
+
 	return data
 }
 
@@ -253,6 +569,8 @@ func (s *CollectionStore) toFolderData(f *core.Folder) folderData {
 		ID:          f.ID(),
 		Name:        f.Name(),
 		Description: f.Description(),
+		Sequential:  f.Sequential(),
+		Variables:   f.Variables(),
 	}
 
 	for _, sf := range f.Folders() {
@@ -267,22 +585,58 @@ func (s *CollectionStore) toFolderData(f *core.Folder) folderData {
 }
 
 func (s *CollectionStore) toRequestData(r *core.RequestDefinition) requestData {
-	return requestData{
-		ID:          r.ID(),
-		Name:        r.Name(),
-		Description: r.Description(),
-		Method:      r.Method(),
-		URL:         r.URL(),
-		Headers:     r.Headers(),
-		BodyType:    r.BodyType(),
-		BodyContent: r.BodyContent(),
-		PreScript:   r.PreScript(),
-		PostScript:  r.PostScript(),
+	data := requestData{
+		ID:              r.ID(),
+		Name:            r.Name(),
+		Description:     r.Description(),
+		Method:          r.Method(),
+		URL:             r.URL(),
+		Headers:         r.Headers(),
+		QueryParams:     r.QueryParams(),
+		BodyType:        r.BodyType(),
+		BodyContent:     r.BodyContent(),
+		BodyContentType: r.BodyContentType(),
+		PreScript:       r.PreScript(),
+		PostScript:      r.PostScript(),
+		SkipIf:          r.SkipCondition(),
+	}
+	if auth := r.Auth(); auth != nil {
+		a := toAuthData(*auth)
+		data.Auth = &a
+	}
+
+	for _, f := range r.FormFields() {
+		data.FormFields = append(data.FormFields, formFieldData{
+			Key:      f.Key,
+			Value:    f.Value,
+			IsFile:   f.IsFile,
+			FilePath: f.FilePath,
+			FileName: f.FileName,
+		})
 	}
+
+	for _, a := range r.Assertions() {
+		data.Assertions = append(data.Assertions, assertionData{
+			Source:   string(a.Source),
+			Property: a.Property,
+			Operator: string(a.Operator),
+			Value:    a.Value,
+		})
+	}
+
+	for _, e := range r.Extractions() {
+		data.Extract = append(data.Extract, extractionData{
+			Variable:   e.Variable,
+			Source:     string(e.Source),
+			Expression: e.Expression,
+		})
+	}
+
+	return data
 }
 
 func toAuthData(a core.AuthConfig) authData {
-	return authData{
+	data := authData{
 		Type:     a.Type,
 		Token:    a.Token,
 		Username: a.Username,
@@ -291,6 +645,58 @@ func toAuthData(a core.AuthConfig) authData {
 		Value:    a.Value,
 		In:       a.In,
 	}
+	if o := a.OAuth2; o != nil {
+		data.OAuth2 = &oauth2Data{
+			GrantType:        string(o.GrantType),
+			AuthURL:          o.AuthURL,
+			TokenURL:         o.TokenURL,
+			ClientID:         o.ClientID,
+			ClientSecret:     o.ClientSecret,
+			Scope:            o.Scope,
+			State:            o.State,
+			RedirectURI:      o.RedirectURI,
+			AccessToken:      o.AccessToken,
+			RefreshToken:     o.RefreshToken,
+			TokenType:        o.TokenType,
+			ExpiresIn:        o.ExpiresIn,
+			HeaderPrefix:     o.HeaderPrefix,
+			AddTokenTo:       o.AddTokenTo,
+			UsePKCE:          o.UsePKCE,
+			PKCECodeVerifier: o.PKCECodeVerifier,
+		}
+	}
+	if a.AWS != nil {
+		aws := awsData(*a.AWS)
+		data.AWS = &aws
+	}
+	return data
+}
+
+func toWebSocketData(ws *core.WebSocketDefinition) websocketData {
+	data := websocketData{
+		ID:                   ws.ID,
+		Name:                 ws.Name,
+		Endpoint:             ws.Endpoint,
+		Headers:              ws.Headers,
+		Subprotocols:         ws.Subprotocols,
+		PreConnectScript:     ws.PreConnectScript,
+		PreMessageScript:     ws.PreMessageScript,
+		PostMessageScript:    ws.PostMessageScript,
+		FilterScript:         ws.FilterScript,
+		PingInterval:         ws.PingInterval,
+		ReconnectEnabled:     ws.ReconnectEnabled,
+		MaxReconnectAttempts: ws.MaxReconnectAttempts,
+		CreatedAt:            ws.CreatedAt,
+		UpdatedAt:            ws.UpdatedAt,
+	}
+	if ws.Auth != nil {
+		a := toAuthData(*ws.Auth)
+		data.Auth = &a
+	}
+	for _, rule := range ws.AutoResponseRules {
+		data.AutoResponseRules = append(data.AutoResponseRules, autoResponseData(rule))
+	}
+	return data
 }
 
 func (s *CollectionStore) fromStorageFormat(data *collectionData) *core.Collection {
@@ -300,7 +706,7 @@ func (s *CollectionStore) fromStorageFormat(data *collectionData) *core.Collecti
 	c.SetAuth(fromAuthData(data.Auth))
 	c.SetPreScript(data.PreScript)
 	c.SetPostScript(data.PostScript)
-	c.SetTimestamps(data.CreatedAt, data.UpdatedAt)
+	c.SetContract(data.Contract)
 
 	for k, v := range data.Variables {
 		c.SetVariable(k, v)
@@ -316,12 +722,23 @@ func (s *CollectionStore) fromStorageFormat(data *collectionData) *core.Collecti
 		c.AddRequest(r)
 	}
 
+	for _, wd := range data.WebSockets {
+		c.AddExistingWebSocket(fromWebSocketData(&wd))
+	}
+
+	// Last, as building the collection touches it
+	c.SetTimestamps(data.CreatedAt, data.UpdatedAt)
+
 	return c
 }
 
 func (s *CollectionStore) fromFolderData(data *folderData) *core.Folder {
 	f := core.NewFolderWithID(data.ID, data.Name)
 	f.SetDescription(data.Description)
+	f.SetSequential(data.Sequential)
+	for k, v := range data.Variables {
+		f.SetVariable(k, v)
+	}
 
 	for _, fd := range data.Folders {
 		sf := s.fromFolderData(&fd)
@@ -341,20 +758,63 @@ func (s *CollectionStore) fromRequestData(data *requestData) *core.RequestDefini
 	r.SetDescription(data.Description)
 	r.SetPreScript(data.PreScript)
 	r.SetPostScript(data.PostScript)
+	r.SetSkipCondition(data.SkipIf)
+
+	var assertions []core.Assertion
+	for _, a := range data.Assertions {
+		assertions = append(assertions, core.Assertion{
+			Source:   core.AssertionSource(a.Source),
+			Property: a.Property,
+			Operator: core.AssertionOperator(a.Operator),
+			Value:    a.Value,
+		})
+	}
+	r.SetAssertions(assertions)
+
+	var extractions []core.Extraction
+	for _, e := range data.Extract {
+		extractions = append(extractions, core.Extraction{
+			Variable:   e.Variable,
+			Source:     core.AssertionSource(e.Source),
+			Expression: e.Expression,
+		})
+	}
+	r.SetExtractions(extractions)
 
 	for k, v := range data.Headers {
 		r.SetHeader(k, v)
 	}
+	for k, v := range data.QueryParams {
+		r.SetQueryParam(k, v)
+	}
+	if data.Auth != nil {
+		r.SetAuth(fromAuthData(*data.Auth))
+	}
+
+	var fields []core.FormField
+	for _, f := range data.FormFields {
+		fields = append(fields, core.FormField{
+			Key:      f.Key,
+			Value:    f.Value,
+			IsFile:   f.IsFile,
+			FilePath: f.FilePath,
+			FileName: f.FileName,
+		})
+	}
 
-	if data.BodyContent != "" {
-		r.SetBodyRaw(data.BodyContent, data.BodyType)
+	// Restore the body exactly as saved, whatever its type
+	if fields != nil {
+		r.SetBodyFormData(fields)
 	}
+	r.SetBodyType(data.BodyType)
+	r.SetBody(data.BodyContent)
+	r.SetBodyContentType(data.BodyContentType)
 
 	return r
 }
 
 func fromAuthData(data authData) core.AuthConfig {
-	return core.AuthConfig{
+	auth := core.AuthConfig{
 		Type:     data.Type,
 		Token:    data.Token,
 		Username: data.Username,
@@ -363,4 +823,60 @@ func fromAuthData(data authData) core.AuthConfig {
 		Value:    data.Value,
 		In:       data.In,
 	}
+	if o := data.OAuth2; o != nil {
+		auth.OAuth2 = &core.OAuth2Config{
+			GrantType:        core.OAuth2GrantType(o.GrantType),
+			AuthURL:          o.AuthURL,
+			TokenURL:         o.TokenURL,
+			ClientID:         o.ClientID,
+			ClientSecret:     o.ClientSecret,
+			Scope:            o.Scope,
+			State:            o.State,
+			RedirectURI:      o.RedirectURI,
+			AccessToken:      o.AccessToken,
+			RefreshToken:     o.RefreshToken,
+			TokenType:        o.TokenType,
+			ExpiresIn:        o.ExpiresIn,
+			HeaderPrefix:     o.HeaderPrefix,
+			AddTokenTo:       o.AddTokenTo,
+			UsePKCE:          o.UsePKCE,
+			PKCECodeVerifier: o.PKCECodeVerifier,
+		}
+	}
+	if data.AWS != nil {
+		aws := core.AWSAuthConfig(*data.AWS)
+		auth.AWS = &aws
+	}
+	return auth
+}
+
+func fromWebSocketData(data *websocketData) *core.WebSocketDefinition {
+	ws := &core.WebSocketDefinition{
+		ID:                   data.ID,
+		Name:                 data.Name,
+		Endpoint:             data.Endpoint,
+		Headers:              make(map[string]string, len(data.Headers)),
+		Subprotocols:         append([]string{}, data.Subprotocols...),
+		PreConnectScript:     data.PreConnectScript,
+		PreMessageScript:     data.PreMessageScript,
+		PostMessageScript:    data.PostMessageScript,
+		FilterScript:         data.FilterScript,
+		AutoResponseRules:    make([]core.AutoResponseRule, 0, len(data.AutoResponseRules)),
+		PingInterval:         data.PingInterval,
+		ReconnectEnabled:     data.ReconnectEnabled,
+		MaxReconnectAttempts: data.MaxReconnectAttempts,
+		CreatedAt:            data.CreatedAt,
+		UpdatedAt:            data.UpdatedAt,
+	}
+	for k, v := range data.Headers {
+		ws.Headers[k] = v
+	}
+	if data.Auth != nil {
+		auth := fromAuthData(*data.Auth)
+		ws.Auth = &auth
+	}
+	for _, rule := range data.AutoResponseRules {
+		ws.AutoResponseRules = append(ws.AutoResponseRules, core.AutoResponseRule(rule))
+	}
+	return ws
 }
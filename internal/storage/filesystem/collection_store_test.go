@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/artpar/currier/internal/core"
 	"github.com/stretchr/testify/assert"
@@ -431,6 +432,45 @@ func TestCollectionStore_SaveLoadScripts(t *testing.T) {
 	})
 }
 
+func TestCollectionStore_SaveLoadContract(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	c := core.NewCollection("Contract API")
+	c.SetContract("/specs/openapi.yaml")
+	require.NoError(t, store.Save(ctx, c))
+
+	loaded, err := store.Get(ctx, c.ID())
+	require.NoError(t, err)
+	assert.Equal(t, "/specs/openapi.yaml", loaded.Contract())
+}
+
+func TestCollectionStore_SaveLoadAssertions(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	assertions := []core.Assertion{
+		{Source: core.AssertionSourceStatus, Operator: core.AssertionEquals, Value: "200"},
+		{Source: core.AssertionSourceJSONPath, Property: "$.id", Operator: core.AssertionExists},
+	}
+	extractions := []core.Extraction{
+		{Variable: "token", Source: core.AssertionSourceJSONPath, Expression: "$.token"},
+	}
+
+	c := core.NewCollection("Checks")
+	req := core.NewRequestDefinition("Login", "POST", "/login")
+	req.SetAssertions(assertions)
+	req.SetExtractions(extractions)
+	c.AddRequest(req)
+	require.NoError(t, store.Save(ctx, c))
+
+	loaded, err := store.Get(ctx, c.ID())
+	require.NoError(t, err)
+	require.Len(t, loaded.Requests(), 1)
+	assert.Equal(t, assertions, loaded.Requests()[0].Assertions())
+	assert.Equal(t, extractions, loaded.Requests()[0].Extractions())
+}
+
 func TestCollectionStore_SaveLoadRequestBody(t *testing.T) {
 	t.Run("saves and loads request body", func(t *testing.T) {
 		store := newTestStore(t)
@@ -451,6 +491,88 @@ func TestCollectionStore_SaveLoadRequestBody(t *testing.T) {
 	})
 }
 
+func TestCollectionStore_SaveLoadBodyTypes(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	c := core.NewCollection("Bodies")
+	raw := core.NewRequestDefinition("XML", "POST", "/xml")
+	raw.SetBodyRaw("<a/>", "application/xml")
+	c.AddRequest(raw)
+	form := core.NewRequestDefinition("Form", "POST", "/form")
+	form.SetBodyFormData([]core.FormField{
+		{Key: "name", Value: "test"},
+		{Key: "upload", IsFile: true, FilePath: "/tmp/a.txt", FileName: "a.txt"},
+	})
+	c.AddRequest(form)
+	urlencoded := core.NewRequestDefinition("Login", "POST", "/login")
+	urlencoded.SetBodyURLEncoded([]core.FormField{{Key: "user", Value: "john"}, {Key: "pw", Value: "x"}})
+	c.AddRequest(urlencoded)
+	binary := core.NewRequestDefinition("Upload", "PUT", "/blob")
+	binary.SetBodyBinary("./data.bin", "application/octet-stream")
+	c.AddRequest(binary)
+
+	require.NoError(t, store.Save(ctx, c))
+
+	loaded, err := store.Get(ctx, c.ID())
+	require.NoError(t, err)
+	requests := loaded.Requests()
+	require.Len(t, requests, 4)
+
+	assert.Equal(t, "raw", requests[0].BodyType())
+	assert.Equal(t, "<a/>", requests[0].BodyContent())
+	assert.Equal(t, "application/xml", requests[0].BodyContentType())
+
+	assert.Equal(t, "form", requests[1].BodyType())
+	assert.Equal(t, form.FormFields(), requests[1].FormFields())
+
+	assert.Equal(t, "urlencoded", requests[2].BodyType())
+	assert.Equal(t, urlencoded.FormFields(), requests[2].FormFields())
+
+	assert.Equal(t, "binary", requests[3].BodyType())
+	assert.Equal(t, "./data.bin", requests[3].BodyFile())
+	assert.Equal(t, "application/octet-stream", requests[3].BodyContentType())
+}
+
+func TestCollectionStore_SaveLoadSequentialFolder(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	c := core.NewCollection("Flows")
+	flow := c.AddFolder("Checkout")
+	flow.SetSequential(true)
+	flow.SetVariable("cart", "c-1")
+	flow.AddFolder("Nested")
+	c.AddFolder("Independent")
+
+	require.NoError(t, store.Save(ctx, c))
+
+	loaded, err := store.Get(ctx, c.ID())
+	require.NoError(t, err)
+	require.Len(t, loaded.Folders(), 2)
+	assert.True(t, loaded.Folders()[0].Sequential())
+	assert.Equal(t, map[string]string{"cart": "c-1"}, loaded.Folders()[0].Variables())
+	assert.False(t, loaded.Folders()[0].Folders()[0].Sequential())
+	assert.False(t, loaded.Folders()[1].Sequential())
+}
+
+func TestCollectionStore_SaveLoadSkipCondition(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	c := core.NewCollection("Conditional")
+	req := core.NewRequestDefinition("Cleanup", "DELETE", "https://example.com/items/1")
+	req.SetSkipCondition(`currier.environment.get("env") === "prod"`)
+	c.AddRequest(req)
+
+	require.NoError(t, store.Save(ctx, c))
+
+	loaded, err := store.Get(ctx, c.ID())
+	require.NoError(t, err)
+	require.Len(t, loaded.Requests(), 1)
+	assert.Equal(t, `currier.environment.get("env") === "prod"`, loaded.Requests()[0].SkipCondition())
+}
+
 func newTestStore(t *testing.T) *CollectionStore {
 	t.Helper()
 	tmpDir := t.TempDir()
@@ -807,3 +929,225 @@ func TestCollectionStore_GetByPathMore(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+func TestCollectionStore_LosslessRoundTrip(t *testing.T) {
+	t.Run("save and load returns an identical collection", func(t *testing.T) {
+		store := newTestStore(t)
+		ctx := context.Background()
+
+		c := core.NewCollection("Full API")
+		c.SetDescription("Every field set")
+		c.SetVersion("2.1.0")
+		c.SetVariable("baseUrl", "https://api.example.com")
+		c.SetAuth(core.AuthConfig{
+			Type: "oauth2",
+			OAuth2: &core.OAuth2Config{
+				GrantType:        core.OAuth2GrantAuthorizationCode,
+				AuthURL:          "https://auth.example.com/authorize",
+				TokenURL:         "https://auth.example.com/token",
+				ClientID:         "client",
+				ClientSecret:     "secret",
+				Scope:            "read write",
+				State:            "xyz",
+				RedirectURI:      "http://localhost:8080/callback",
+				AccessToken:      "access",
+				RefreshToken:     "refresh",
+				TokenType:        "Bearer",
+				ExpiresIn:        3600,
+				HeaderPrefix:     "Bearer",
+				AddTokenTo:       "header",
+				UsePKCE:          true,
+				PKCECodeVerifier: "verifier",
+			},
+		})
+		c.SetPreScript("console.log('pre')")
+		c.SetPostScript("console.log('post')")
+		c.SetContract("openapi.yaml")
+
+		folder := c.AddFolder("Users")
+		folder.SetDescription("User endpoints")
+		folder.SetSequential(true)
+		folder.SetVariable("userId", "42")
+		sub := folder.AddFolder("Admin")
+
+		create := core.NewRequestDefinition("Create User", "POST", "{{baseUrl}}/users")
+		create.SetDescription("Creates a user")
+		create.SetHeader("Content-Type", "application/json")
+		create.SetQueryParam("notify", "true")
+		create.SetBody(`{"name": "Ada"}`)
+		create.SetBodyType("json")
+		create.SetAuth(core.AuthConfig{
+			Type: "aws",
+			AWS: &core.AWSAuthConfig{
+				AccessKeyID:     "AKIA",
+				SecretAccessKey: "shh",
+				SessionToken:    "session",
+				Region:          "eu-west-1",
+				Service:         "execute-api",
+			},
+		})
+		create.SetPreScript("currier.variables.set('a', 1)")
+		create.SetPostScript("currier.test('ok', () => {})")
+		create.SetSkipCondition("env.skip")
+		create.SetAssertions([]core.Assertion{{Source: "status", Operator: "eq", Value: "201"}})
+		create.SetExtractions([]core.Extraction{{Variable: "id", Source: "body", Expression: "$.id"}})
+		folder.AddRequest(create)
+
+		upload := core.NewRequestDefinition("Upload", "POST", "{{baseUrl}}/files")
+		upload.SetBodyFormData([]core.FormField{
+			{Key: "title", Value: "Report"},
+			{Key: "file", IsFile: true, FilePath: "/tmp/report.pdf", FileName: "report.pdf"},
+		})
+		upload.SetAuth(core.AuthConfig{Type: "apikey", Key: "X-API-Key", Value: "k", In: "header"})
+		sub.AddRequest(upload)
+
+		login := core.NewRequestDefinition("Login", "POST", "{{baseUrl}}/login")
+		login.SetBodyURLEncoded([]core.FormField{{Key: "user", Value: "ada"}})
+		c.AddRequest(login)
+
+		binary := core.NewRequestDefinition("Image", "PUT", "{{baseUrl}}/image")
+		binary.SetBodyBinary("/tmp/image.png", "image/png")
+		c.AddRequest(binary)
+
+		ws := core.NewWebSocketDefinition("Events", "wss://api.example.com/events")
+		ws.Headers["Origin"] = "https://example.com"
+		ws.Subprotocols = []string{"graphql-ws"}
+		ws.Auth = &core.AuthConfig{Type: "bearer", Token: "ws-token"}
+		ws.PreConnectScript = "pre connect"
+		ws.PreMessageScript = "pre message"
+		ws.PostMessageScript = "post message"
+		ws.FilterScript = "filter"
+		ws.AutoResponseRules = []core.AutoResponseRule{{ID: "r1", Name: "Pong", MatchScript: "msg == 'ping'", Response: "pong", Enabled: true}}
+		ws.PingInterval = 0
+		ws.ReconnectEnabled = false
+		ws.MaxReconnectAttempts = 0
+		ws.CreatedAt = time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+		ws.UpdatedAt = time.Date(2024, 3, 2, 9, 0, 0, 0, time.UTC)
+		c.AddWebSocket(ws)
+
+		c.AddWebSocket(core.NewWebSocketDefinition("Defaults", "wss://api.example.com/plain"))
+		for _, w := range c.WebSockets() {
+			w.CreatedAt = w.CreatedAt.UTC().Truncate(time.Second)
+			w.UpdatedAt = w.UpdatedAt.UTC().Truncate(time.Second)
+		}
+
+		c.SetTimestamps(
+			time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+			time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC),
+		)
+
+		require.NoError(t, store.Save(ctx, c))
+		loaded, err := store.Get(ctx, c.ID())
+		require.NoError(t, err)
+
+		assert.Equal(t, c, loaded)
+	})
+
+	t.Run("writes the current schema version", func(t *testing.T) {
+		store := newTestStore(t)
+		ctx := context.Background()
+
+		c := core.NewCollection("Versioned")
+		require.NoError(t, store.Save(ctx, c))
+
+		content, err := os.ReadFile(store.collectionPath(c.ID()))
+		require.NoError(t, err)
+		assert.Contains(t, string(content), "schema: 2\n")
+	})
+}
+
+func TestCollectionStore_Migrations(t *testing.T) {
+	t.Run("loads files written before the schema was versioned", func(t *testing.T) {
+		store := newTestStore(t)
+		ctx := context.Background()
+
+		legacy := `id: legacy-1
+name: Legacy
+variables:
+  host: example.com
+auth:
+  type: bearer
+  token: abc
+requests:
+  - id: req-1
+    name: Get
+    method: GET
+    url: https://example.com/items
+    body_type: raw
+    body_content: hello
+    body_content_type: text/plain
+created_at: 2023-05-01T10:00:00Z
+updated_at: 2023-06-01T10:00:00Z
+`
+		require.NoError(t, os.WriteFile(store.collectionPath("legacy-1"), []byte(legacy), 0644))
+
+		c, err := store.Get(ctx, "legacy-1")
+		require.NoError(t, err)
+		assert.Equal(t, "Legacy", c.Name())
+		assert.Equal(t, "example.com", c.GetVariable("host"))
+		assert.Equal(t, "abc", c.Auth().Token)
+		assert.Equal(t, time.Date(2023, 6, 1, 10, 0, 0, 0, time.UTC), c.UpdatedAt())
+		require.Len(t, c.Requests(), 1)
+		assert.Equal(t, "hello", c.Requests()[0].Body())
+		assert.Equal(t, "text/plain", c.Requests()[0].BodyContentType())
+		assert.Nil(t, c.Requests()[0].Auth())
+		assert.Empty(t, c.WebSockets())
+	})
+
+	t.Run("rejects files from a newer schema", func(t *testing.T) {
+		store := newTestStore(t)
+
+		future := "schema: 99\nid: future-1\nname: Future\n"
+		require.NoError(t, os.WriteFile(store.collectionPath("future-1"), []byte(future), 0644))
+
+		_, err := store.Get(context.Background(), "future-1")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "schema 99 is newer")
+	})
+}
+
+func TestCollectionStore_Workspace(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("layers workspace collections over the user's", func(t *testing.T) {
+		userDir, workspaceDir := t.TempDir(), t.TempDir()
+		user, err := NewCollectionStore(userDir)
+		require.NoError(t, err)
+		personal := core.NewCollection("Personal")
+		require.NoError(t, user.Save(ctx, personal))
+
+		layered, err := NewCollectionStore(userDir, WithCollectionWorkspace(workspaceDir), WithLayout(LayoutDirectory))
+		require.NoError(t, err)
+		team := core.NewCollection("Team API")
+		require.NoError(t, layered.Save(ctx, team))
+		assert.FileExists(t, filepath.Join(workspaceDir, "team-api", "_collection.yaml"))
+		assert.Equal(t, filepath.Join(workspaceDir, "team-api"), layered.CollectionDir(team.ID()))
+		assert.Equal(t, userDir, layered.CollectionDir(personal.ID()))
+		assert.Equal(t, workspaceDir, layered.CollectionDir("unsaved"))
+
+		list, err := layered.List(ctx)
+		require.NoError(t, err)
+		require.Len(t, list, 2)
+		assert.Equal(t, "Team API", list[0].Name)
+		assert.True(t, list[0].Workspace)
+		assert.Equal(t, "Personal", list[1].Name)
+		assert.False(t, list[1].Workspace)
+
+		// User collections stay where they are
+		personal.SetDescription("Edited in a workspace")
+		require.NoError(t, layered.Save(ctx, personal))
+		loaded, err := user.Get(ctx, personal.ID())
+		require.NoError(t, err)
+		assert.Equal(t, "Edited in a workspace", loaded.Description())
+
+		// Converting keeps a collection in its directory
+		path, err := layered.Convert(ctx, team.ID(), LayoutFile)
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join(workspaceDir, team.ID()+".yaml"), path)
+
+		require.NoError(t, layered.Delete(ctx, team.ID()))
+		list, err = user.List(ctx)
+		require.NoError(t, err)
+		assert.Len(t, list, 1)
+	})
+}
@@ -2,53 +2,213 @@ package filesystem
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/artpar/currier/internal/core"
+	"github.com/artpar/currier/internal/secrets"
 	"gopkg.in/yaml.v3"
 )
 
+// ErrWorkspaceSecrets is returned when saving plaintext secrets in a
+// workspace, whose files are meant to be committed.
+var ErrWorkspaceSecrets = errors.New("plaintext secrets are not saved in a workspace: set " + secrets.PassphraseEnv + " or " + secrets.KeyFileEnv + " to encrypt them")
+
 // EnvironmentMeta contains metadata for listing environments.
 type EnvironmentMeta struct {
 	ID        string
 	Name      string
 	IsActive  bool
+	IsGlobal  bool
 	VarCount  int
 	VarNames  []string // Variable names for preview
+	Chain     []string // Names of the environments it inherits from, nearest first
+	Workspace bool     // Whether the environment belongs to the workspace
 	UpdatedAt time.Time
 }
 
 // EnvironmentStore manages environment persistence to the filesystem.
+// With a key set, secrets are encrypted at rest. Without one, encrypted
+// secrets stay locked: environments load without them and keep them when
+// saved.
 type EnvironmentStore struct {
 	basePath string
+
+	// A workspace's environments, layered over basePath. The environment
+	// chosen in the workspace is kept in its activeFile rather than in the
+	// environment files, which are shared.
+	workspace   string
+	defaultName string // Workspace environment active until one is chosen
+
+	mu     sync.Mutex
+	key    *secrets.Key
+	sealed map[string]*secrets.Sealed // Locked secrets by environment ID
 }
 
-// NewEnvironmentStore creates a new filesystem-based environment store.
-func NewEnvironmentStore(basePath string) (*EnvironmentStore, error) {
-	// Create directory if it doesn't exist
-	if err := os.MkdirAll(basePath, 0755); err != nil {
-		return nil, fmt.Errorf("failed to create environments directory: %w", err)
+// activeFile holds the ID of the environment chosen in a workspace, or
+// nothing when a user environment was chosen.
+const activeFile = ".active"
+
+// EnvironmentOption configures an EnvironmentStore.
+type EnvironmentOption func(*EnvironmentStore)
+
+// WithEnvironmentWorkspace layers the environments of a project workspace in
+// dir over the store's own. They hide user environments of the same name,
+// and new environments are saved there. defaultName names the workspace
+// environment that is active until another is chosen.
+func WithEnvironmentWorkspace(dir, defaultName string) EnvironmentOption {
+	return func(s *EnvironmentStore) {
+		s.workspace = dir
+		s.defaultName = defaultName
 	}
+}
 
-	return &EnvironmentStore{
+// NewEnvironmentStore creates a new filesystem-based environment store.
+func NewEnvironmentStore(basePath string, opts ...EnvironmentOption) (*EnvironmentStore, error) {
+	s := &EnvironmentStore{
 		basePath: basePath,
-	}, nil
+		sealed:   make(map[string]*secrets.Sealed),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	// Create directories if they don't exist
+	for _, dir := range s.dirs() {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create environments directory: %w", err)
+		}
+	}
+	return s, nil
+}
+
+// SetKey sets the key secrets are encrypted with. Plaintext secrets are
+// encrypted the next time their environment is saved.
+func (s *EnvironmentStore) SetKey(key *secrets.Key) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.key = key
+	s.sealed = make(map[string]*secrets.Sealed)
+}
+
+// HasKey reports whether a key is set.
+func (s *EnvironmentStore) HasKey() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.key != nil
+}
+
+// Unlock checks key against the stored encrypted secrets and sets it.
+func (s *EnvironmentStore) Unlock(ctx context.Context, key *secrets.Key) error {
+	paths, err := s.paths()
+	if err != nil {
+		return err
+	}
+	for _, path := range paths {
+		data, err := s.readData(path)
+		if err != nil || data.EncryptedSecrets == nil {
+			continue
+		}
+		if _, err := key.Open(data.EncryptedSecrets); err != nil {
+			return err
+		}
+	}
+	s.SetKey(key)
+	return nil
+}
+
+// EncryptAll re-saves the environments that have plaintext secrets, so they
+// are encrypted with the store's key. It returns how many were encrypted.
+func (s *EnvironmentStore) EncryptAll(ctx context.Context) (int, error) {
+	if !s.HasKey() {
+		return 0, secrets.ErrLocked
+	}
+	paths, err := s.paths()
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, path := range paths {
+		data, err := s.readData(path)
+		if err != nil || len(data.Secrets) == 0 {
+			continue
+		}
+		env, err := s.loadFromPath(path)
+		if err != nil {
+			return count, err
+		}
+		if err := s.Save(ctx, env); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+// Locked reports whether any environment has encrypted secrets and no key
+// is set.
+func (s *EnvironmentStore) Locked(ctx context.Context) bool {
+	if s.HasKey() {
+		return false
+	}
+	return s.anyData(func(data *environmentData) bool { return data.EncryptedSecrets != nil })
+}
+
+// HasSecrets reports whether any environment has secrets, encrypted or not.
+func (s *EnvironmentStore) HasSecrets(ctx context.Context) bool {
+	return s.anyData(func(data *environmentData) bool {
+		return data.EncryptedSecrets != nil || len(data.Secrets) > 0
+	})
+}
+
+// Redactor returns a redactor masking the secret values of every
+// environment that can be read and those resolved through
+// {{$secret "..."}}.
+func (s *EnvironmentStore) Redactor(ctx context.Context) *secrets.Redactor {
+	values := secrets.ResolvedValues()
+	paths, err := s.paths()
+	if err != nil {
+		return secrets.NewRedactor(values...)
+	}
+	for _, path := range paths {
+		env, err := s.loadFromPath(path)
+		if err != nil {
+			continue
+		}
+		for _, name := range env.SecretNames() {
+			values = append(values, env.GetSecret(name))
+		}
+	}
+	return secrets.NewRedactor(values...)
 }
 
 // Save persists an environment to disk.
 func (s *EnvironmentStore) Save(ctx context.Context, env *core.Environment) error {
-	data := s.toStorageFormat(env)
+	data, err := s.toStorageFormat(env)
+	if err != nil {
+		return err
+	}
+
+	path := s.environmentPath(env.ID())
+	if s.inWorkspace(path) {
+		// Workspace files are committed, so secrets only go in encrypted
+		if len(data.Secrets) > 0 {
+			return fmt.Errorf("cannot save secrets of %s: %w", env.Name(), ErrWorkspaceSecrets)
+		}
+		data.IsActive = false // Kept in the activeFile
+	}
 
 	content, err := yaml.Marshal(data)
 	if err != nil {
 		return fmt.Errorf("failed to marshal environment: %w", err)
 	}
 
-	path := s.environmentPath(env.ID())
 	if err := os.WriteFile(path, content, 0644); err != nil {
 		return fmt.Errorf("failed to write environment file: %w", err)
 	}
@@ -56,25 +216,44 @@ func (s *EnvironmentStore) Save(ctx context.Context, env *core.Environment) erro
 	return nil
 }
 
-// Get retrieves an environment by ID.
+// Get retrieves an environment by ID, with the environments it extends
+// resolved.
 func (s *EnvironmentStore) Get(ctx context.Context, id string) (*core.Environment, error) {
 	path := s.environmentPath(id)
-	return s.loadFromPath(path)
+	env, err := s.loadFromPath(path)
+	if err != nil {
+		return nil, err
+	}
+	return s.resolveBase(env)
 }
 
-// GetByName retrieves an environment by name.
+// GetByName retrieves an environment by name, with the environments it
+// extends resolved.
 func (s *EnvironmentStore) GetByName(ctx context.Context, name string) (*core.Environment, error) {
-	entries, err := os.ReadDir(s.basePath)
+	env, err := s.findByName(name)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read environments directory: %w", err)
+		return nil, err
 	}
+	return s.resolveBase(env)
+}
 
-	for _, entry := range entries {
-		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".yaml") {
-			continue
-		}
+// resolveBase resolves the environments env extends by name.
+func (s *EnvironmentStore) resolveBase(env *core.Environment) (*core.Environment, error) {
+	if err := core.ResolveBase(env, s.findByName); err != nil {
+		return nil, err
+	}
+	return env, nil
+}
 
-		path := filepath.Join(s.basePath, entry.Name())
+// findByName loads an environment by name without resolving its base. A
+// workspace environment is found before a user one.
+func (s *EnvironmentStore) findByName(name string) (*core.Environment, error) {
+	paths, err := s.paths()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, path := range paths {
 		env, err := s.loadFromPath(path)
 		if err != nil {
 			continue
@@ -88,23 +267,25 @@ func (s *EnvironmentStore) GetByName(ctx context.Context, name string) (*core.En
 	return nil, fmt.Errorf("environment not found: %s", name)
 }
 
-// List returns all environments.
+// List returns all environments, those of the workspace first.
 func (s *EnvironmentStore) List(ctx context.Context) ([]EnvironmentMeta, error) {
-	entries, err := os.ReadDir(s.basePath)
+	envs, err := s.environments()
 	if err != nil {
-		return nil, fmt.Errorf("failed to read environments directory: %w", err)
+		return nil, err
 	}
 
-	var environments []EnvironmentMeta
-	for _, entry := range entries {
-		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".yaml") {
-			continue
-		}
+	// An environment chosen in the workspace wins over the user's
+	workspaceActive := false
+	for _, item := range envs {
+		workspaceActive = workspaceActive || (item.workspace && item.env.IsActive())
+	}
 
-		path := filepath.Join(s.basePath, entry.Name())
-		env, err := s.loadFromPath(path)
-		if err != nil {
-			continue
+	var environments []EnvironmentMeta
+	extends := make(map[string]string)
+	for _, item := range envs {
+		env := item.env
+		if _, ok := extends[env.Name()]; !ok {
+			extends[env.Name()] = env.Extends()
 		}
 
 		// Extract variable names for preview
@@ -117,13 +298,24 @@ func (s *EnvironmentStore) List(ctx context.Context) ([]EnvironmentMeta, error)
 		environments = append(environments, EnvironmentMeta{
 			ID:        env.ID(),
 			Name:      env.Name(),
-			IsActive:  env.IsActive(),
+			IsActive:  env.IsActive() && (item.workspace || !workspaceActive),
+			IsGlobal:  env.IsGlobal(),
 			VarCount:  len(vars),
 			VarNames:  varNames,
+			Workspace: item.workspace,
 			UpdatedAt: env.UpdatedAt(),
 		})
 	}
 
+	// Follow extends by name, stopping at a missing environment or a cycle
+	for i := range environments {
+		seen := map[string]bool{environments[i].Name: true}
+		for name := extends[environments[i].Name]; name != "" && !seen[name]; name = extends[name] {
+			seen[name] = true
+			environments[i].Chain = append(environments[i].Chain, name)
+		}
+	}
+
 	return environments, nil
 }
 
@@ -142,33 +334,51 @@ func (s *EnvironmentStore) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
-// GetActive returns the currently active environment.
+// GetActive returns the currently active environment, with the
+// environments it extends resolved.
 func (s *EnvironmentStore) GetActive(ctx context.Context) (*core.Environment, error) {
-	entries, err := os.ReadDir(s.basePath)
+	envs, err := s.environments()
 	if err != nil {
-		return nil, fmt.Errorf("failed to read environments directory: %w", err)
+		return nil, err
 	}
 
-	for _, entry := range entries {
-		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".yaml") {
-			continue
+	for _, item := range envs {
+		if item.env.IsActive() {
+			return s.resolveBase(item.env)
 		}
+	}
 
-		path := filepath.Join(s.basePath, entry.Name())
-		env, err := s.loadFromPath(path)
-		if err != nil {
+	return nil, fmt.Errorf("no active environment")
+}
+
+// Globals returns the global environments, ordered by name, with the
+// environments they extend resolved. Their values are layered beneath the
+// active environment.
+func (s *EnvironmentStore) Globals(ctx context.Context) ([]*core.Environment, error) {
+	envs, err := s.environments()
+	if err != nil {
+		return nil, err
+	}
+
+	var globals []*core.Environment
+	for _, item := range envs {
+		env := item.env
+		if !env.IsGlobal() {
 			continue
 		}
-
-		if env.IsActive() {
-			return env, nil
+		if _, err := s.resolveBase(env); err != nil {
+			return nil, err
 		}
+		globals = append(globals, env)
 	}
 
-	return nil, fmt.Errorf("no active environment")
+	sort.Slice(globals, func(i, j int) bool { return globals[i].Name() < globals[j].Name() })
+	return globals, nil
 }
 
-// SetActive sets the active environment (deactivates all others).
+// SetActive sets the active environment (deactivates all others). Choosing
+// a workspace environment leaves the user's active environment alone for use
+// outside the workspace.
 func (s *EnvironmentStore) SetActive(ctx context.Context, id string) error {
 	// First verify the target environment exists
 	targetPath := s.environmentPath(id)
@@ -176,6 +386,19 @@ func (s *EnvironmentStore) SetActive(ctx context.Context, id string) error {
 		return fmt.Errorf("environment not found: %s", id)
 	}
 
+	if s.workspace != "" {
+		chosen := ""
+		if s.inWorkspace(targetPath) {
+			chosen = id
+		}
+		if err := os.WriteFile(filepath.Join(s.workspace, activeFile), []byte(chosen), 0644); err != nil {
+			return fmt.Errorf("failed to update active environment: %w", err)
+		}
+		if chosen != "" {
+			return nil
+		}
+	}
+
 	// Deactivate all environments
 	entries, err := os.ReadDir(s.basePath)
 	if err != nil {
@@ -208,11 +431,118 @@ func (s *EnvironmentStore) SetActive(ctx context.Context, id string) error {
 
 // Internal helpers
 
+// environmentPath returns the path of the environment with the given ID:
+// where it is saved, or where it would be saved if new.
 func (s *EnvironmentStore) environmentPath(id string) string {
-	return filepath.Join(s.basePath, id+".yaml")
+	dirs := s.dirs()
+	for _, dir := range dirs {
+		if path := filepath.Join(dir, id+".yaml"); fileExists(path) {
+			return path
+		}
+	}
+	return filepath.Join(dirs[0], id+".yaml")
+}
+
+// dirs returns the directories environments are read from, in order.
+func (s *EnvironmentStore) dirs() []string {
+	if s.workspace != "" {
+		return []string{s.workspace, s.basePath}
+	}
+	return []string{s.basePath}
+}
+
+func (s *EnvironmentStore) inWorkspace(path string) bool {
+	return s.workspace != "" && filepath.Dir(path) == s.workspace
+}
+
+// paths returns the paths of the environment files, the workspace's first.
+func (s *EnvironmentStore) paths() ([]string, error) {
+	var paths []string
+	for _, dir := range s.dirs() {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read environments directory: %w", err)
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".yaml") {
+				continue
+			}
+			paths = append(paths, filepath.Join(dir, entry.Name()))
+		}
+	}
+	return paths, nil
+}
+
+type storedEnvironment struct {
+	env       *core.Environment
+	workspace bool
+}
+
+// environments loads the readable environments, the workspace's first,
+// without the user environments that workspace ones of the same name hide.
+func (s *EnvironmentStore) environments() ([]storedEnvironment, error) {
+	paths, err := s.paths()
+	if err != nil {
+		return nil, err
+	}
+
+	var envs []storedEnvironment
+	shadowed := make(map[string]bool)
+	for _, path := range paths {
+		env, err := s.loadFromPath(path)
+		if err != nil {
+			continue
+		}
+		workspace := s.inWorkspace(path)
+		if workspace {
+			shadowed[env.Name()] = true
+		} else if shadowed[env.Name()] {
+			continue
+		}
+		envs = append(envs, storedEnvironment{env: env, workspace: workspace})
+	}
+	return envs, nil
+}
+
+// activeWorkspaceID returns the ID of the environment chosen in the
+// workspace, or the default one while none has been chosen.
+func (s *EnvironmentStore) activeWorkspaceID() string {
+	content, err := os.ReadFile(filepath.Join(s.workspace, activeFile))
+	if err == nil {
+		return strings.TrimSpace(string(content))
+	}
+	if s.defaultName == "" {
+		return ""
+	}
+
+	entries, err := os.ReadDir(s.workspace)
+	if err != nil {
+		return ""
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".yaml") {
+			continue
+		}
+		data, err := s.readData(filepath.Join(s.workspace, entry.Name()))
+		if err == nil && data.Name == s.defaultName {
+			return data.ID
+		}
+	}
+	return ""
 }
 
 func (s *EnvironmentStore) loadFromPath(path string) (*core.Environment, error) {
+	data, err := s.readData(path)
+	if err != nil {
+		return nil, err
+	}
+	if s.inWorkspace(path) {
+		data.IsActive = data.ID == s.activeWorkspaceID()
+	}
+	return s.fromStorageFormat(data)
+}
+
+func (s *EnvironmentStore) readData(path string) (*environmentData, error) {
 	content, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read environment file: %w", err)
@@ -222,38 +552,75 @@ func (s *EnvironmentStore) loadFromPath(path string) (*core.Environment, error)
 	if err := yaml.Unmarshal(content, &data); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal environment: %w", err)
 	}
+	return &data, nil
+}
 
-	return s.fromStorageFormat(&data), nil
+// anyData reports whether the stored data of any environment matches fn.
+func (s *EnvironmentStore) anyData(fn func(*environmentData) bool) bool {
+	paths, err := s.paths()
+	if err != nil {
+		return false
+	}
+	for _, path := range paths {
+		if data, err := s.readData(path); err == nil && fn(data) {
+			return true
+		}
+	}
+	return false
 }
 
 // Storage format types
 
 type environmentData struct {
-	ID          string            `yaml:"id"`
-	Name        string            `yaml:"name"`
-	Description string            `yaml:"description,omitempty"`
-	Variables   map[string]string `yaml:"variables,omitempty"`
-	Secrets     map[string]string `yaml:"secrets,omitempty"`
-	IsActive    bool              `yaml:"is_active"`
-	IsGlobal    bool              `yaml:"is_global"`
-	CreatedAt   time.Time         `yaml:"created_at"`
-	UpdatedAt   time.Time         `yaml:"updated_at"`
+	ID               string            `yaml:"id"`
+	Name             string            `yaml:"name"`
+	Description      string            `yaml:"description,omitempty"`
+	Extends          string            `yaml:"extends,omitempty"`
+	Variables        map[string]string `yaml:"variables,omitempty"`
+	Secrets          map[string]string `yaml:"secrets,omitempty"`
+	EncryptedSecrets *secrets.Sealed   `yaml:"secrets_encrypted,omitempty"`
+	IsActive         bool              `yaml:"is_active"`
+	IsGlobal         bool              `yaml:"is_global"`
+	CreatedAt        time.Time         `yaml:"created_at"`
+	UpdatedAt        time.Time         `yaml:"updated_at"`
 }
 
 // Conversion functions
 
-func (s *EnvironmentStore) toStorageFormat(env *core.Environment) *environmentData {
-	return &environmentData{
+func (s *EnvironmentStore) toStorageFormat(env *core.Environment) (*environmentData, error) {
+	data := &environmentData{
 		ID:          env.ID(),
 		Name:        env.Name(),
 		Description: env.Description(),
+		Extends:     env.Extends(),
 		Variables:   env.Variables(),
-		Secrets:     s.getSecrets(env),
 		IsActive:    env.IsActive(),
 		IsGlobal:    env.IsGlobal(),
 		CreatedAt:   env.CreatedAt(),
 		UpdatedAt:   env.UpdatedAt(),
 	}
+
+	s.mu.Lock()
+	key, locked := s.key, s.sealed[env.ID()]
+	s.mu.Unlock()
+
+	values := s.getSecrets(env)
+	switch {
+	case locked != nil && len(values) > 0:
+		return nil, fmt.Errorf("cannot save secrets of %s: %w", env.Name(), secrets.ErrLocked)
+	case locked != nil:
+		data.EncryptedSecrets = locked
+	case len(values) == 0:
+	case key != nil:
+		sealed, err := key.Seal(values)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt secrets: %w", err)
+		}
+		data.EncryptedSecrets = sealed
+	default:
+		data.Secrets = values
+	}
+	return data, nil
 }
 
 func (s *EnvironmentStore) getSecrets(env *core.Environment) map[string]string {
@@ -264,9 +631,10 @@ func (s *EnvironmentStore) getSecrets(env *core.Environment) map[string]string {
 	return secrets
 }
 
-func (s *EnvironmentStore) fromStorageFormat(data *environmentData) *core.Environment {
+func (s *EnvironmentStore) fromStorageFormat(data *environmentData) (*core.Environment, error) {
 	env := core.NewEnvironmentWithID(data.ID, data.Name)
 	env.SetDescription(data.Description)
+	env.SetExtends(data.Extends)
 	env.SetActive(data.IsActive)
 	env.SetGlobal(data.IsGlobal)
 	env.SetTimestamps(data.CreatedAt, data.UpdatedAt)
@@ -279,5 +647,24 @@ func (s *EnvironmentStore) fromStorageFormat(data *environmentData) *core.Enviro
 		env.SetSecret(k, v)
 	}
 
-	return env
+	if data.EncryptedSecrets != nil {
+		s.mu.Lock()
+		key := s.key
+		if key == nil {
+			s.sealed[data.ID] = data.EncryptedSecrets
+		}
+		s.mu.Unlock()
+
+		if key != nil {
+			values, err := key.Open(data.EncryptedSecrets)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decrypt secrets of %s: %w", data.Name, err)
+			}
+			for k, v := range values {
+				env.SetSecret(k, v)
+			}
+		}
+	}
+
+	return env, nil
 }
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/artpar/currier/internal/core"
+	"github.com/artpar/currier/internal/secrets"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -506,6 +507,92 @@ func TestEnvironmentStore_GetActiveCoverage(t *testing.T) {
 
 // Helper functions
 
+func TestEnvironmentStore_EncryptedSecrets(t *testing.T) {
+	newEnv := func() *core.Environment {
+		env := core.NewEnvironment("Production")
+		env.SetVariable("host", "api.example.com")
+		env.SetSecret("token", "s3cr3t-token")
+		return env
+	}
+
+	t.Run("encrypts secrets with a key", func(t *testing.T) {
+		store := newTestEnvStore(t)
+		store.SetKey(secrets.NewPassphraseKey("passphrase"))
+		ctx := context.Background()
+		env := newEnv()
+		require.NoError(t, store.Save(ctx, env))
+
+		content, err := os.ReadFile(store.environmentPath(env.ID()))
+		require.NoError(t, err)
+		assert.Contains(t, string(content), "secrets_encrypted:")
+		assert.Contains(t, string(content), "api.example.com")
+		assert.NotContains(t, string(content), "s3cr3t-token")
+
+		loaded, err := store.Get(ctx, env.ID())
+		require.NoError(t, err)
+		assert.Equal(t, "s3cr3t-token", loaded.GetSecret("token"))
+		assert.False(t, store.Locked(ctx))
+	})
+
+	t.Run("keeps locked secrets when saved without a key", func(t *testing.T) {
+		dir := t.TempDir()
+		ctx := context.Background()
+		store, err := NewEnvironmentStore(dir)
+		require.NoError(t, err)
+		store.SetKey(secrets.NewPassphraseKey("passphrase"))
+		env := newEnv()
+		require.NoError(t, store.Save(ctx, env))
+
+		locked, err := NewEnvironmentStore(dir)
+		require.NoError(t, err)
+		assert.True(t, locked.Locked(ctx))
+		loaded, err := locked.Get(ctx, env.ID())
+		require.NoError(t, err)
+		assert.Empty(t, loaded.SecretNames())
+		assert.Equal(t, "api.example.com", loaded.GetVariable("host"))
+
+		loaded.SetVariable("host", "new.example.com")
+		require.NoError(t, locked.Save(ctx, loaded))
+		loaded.SetSecret("other", "value")
+		assert.ErrorIs(t, locked.Save(ctx, loaded), secrets.ErrLocked)
+
+		assert.ErrorIs(t, locked.Unlock(ctx, secrets.NewPassphraseKey("wrong")), secrets.ErrWrongKey)
+		require.NoError(t, locked.Unlock(ctx, secrets.NewPassphraseKey("passphrase")))
+		unlocked, err := locked.Get(ctx, env.ID())
+		require.NoError(t, err)
+		assert.Equal(t, "new.example.com", unlocked.GetVariable("host"))
+		assert.Equal(t, "s3cr3t-token", unlocked.GetSecret("token"))
+	})
+
+	t.Run("encrypts plaintext secrets", func(t *testing.T) {
+		store := newTestEnvStore(t)
+		ctx := context.Background()
+		env := newEnv()
+		require.NoError(t, store.Save(ctx, env))
+		assert.True(t, store.HasSecrets(ctx))
+		assert.False(t, store.Locked(ctx))
+
+		_, err := store.EncryptAll(ctx)
+		assert.ErrorIs(t, err, secrets.ErrLocked)
+
+		store.SetKey(secrets.NewPassphraseKey("passphrase"))
+		count, err := store.EncryptAll(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+		content, err := os.ReadFile(store.environmentPath(env.ID()))
+		require.NoError(t, err)
+		assert.NotContains(t, string(content), "s3cr3t-token")
+	})
+
+	t.Run("redacts secrets of every environment", func(t *testing.T) {
+		store := newTestEnvStore(t)
+		ctx := context.Background()
+		require.NoError(t, store.Save(ctx, newEnv()))
+
+		assert.Equal(t, "Bearer ********", store.Redactor(ctx).Redact("Bearer s3cr3t-token"))
+	})
+}
+
 func newTestEnvStore(t *testing.T) *EnvironmentStore {
 	t.Helper()
 	tmpDir := t.TempDir()
@@ -513,3 +600,205 @@ func newTestEnvStore(t *testing.T) *EnvironmentStore {
 	require.NoError(t, err)
 	return store
 }
+
+func TestEnvironmentStore_Extends(t *testing.T) {
+	store := newTestEnvStore(t)
+	ctx := context.Background()
+
+	shared := core.NewEnvironment("shared")
+	shared.SetVariable("timeout", "30")
+	base := core.NewEnvironment("base")
+	base.SetExtends("shared")
+	base.SetVariable("host", "base.example.com")
+	base.SetVariable("scheme", "https")
+	staging := core.NewEnvironment("staging")
+	staging.SetExtends("base")
+	staging.SetVariable("host", "staging.example.com")
+	for _, env := range []*core.Environment{shared, base, staging} {
+		require.NoError(t, store.Save(ctx, env))
+	}
+
+	t.Run("resolves the chain on load", func(t *testing.T) {
+		loaded, err := store.Get(ctx, staging.ID())
+		require.NoError(t, err)
+		assert.Equal(t, []string{"staging", "base", "shared"}, loaded.Chain())
+		assert.Equal(t, map[string]string{
+			"host":    "staging.example.com",
+			"scheme":  "https",
+			"timeout": "30",
+		}, loaded.ExportAll())
+	})
+
+	t.Run("saves only its own values", func(t *testing.T) {
+		loaded, err := store.GetByName(ctx, "staging")
+		require.NoError(t, err)
+		require.NoError(t, store.Save(ctx, loaded))
+
+		data, err := store.readData(store.environmentPath(staging.ID()))
+		require.NoError(t, err)
+		assert.Equal(t, "base", data.Extends)
+		assert.Equal(t, map[string]string{"host": "staging.example.com"}, data.Variables)
+	})
+
+	t.Run("lists the chain", func(t *testing.T) {
+		list, err := store.List(ctx)
+		require.NoError(t, err)
+		chains := make(map[string][]string)
+		for _, meta := range list {
+			chains[meta.Name] = meta.Chain
+		}
+		assert.Equal(t, []string{"base", "shared"}, chains["staging"])
+		assert.Equal(t, []string{"shared"}, chains["base"])
+		assert.Empty(t, chains["shared"])
+	})
+
+	t.Run("fails for a missing base", func(t *testing.T) {
+		orphan := core.NewEnvironment("orphan")
+		orphan.SetExtends("nowhere")
+		require.NoError(t, store.Save(ctx, orphan))
+
+		_, err := store.Get(ctx, orphan.ID())
+		assert.ErrorContains(t, err, "environment orphan extends nowhere")
+	})
+}
+
+func TestEnvironmentStore_Globals(t *testing.T) {
+	store := newTestEnvStore(t)
+	ctx := context.Background()
+
+	for _, name := range []string{"personal", "company"} {
+		env := core.NewEnvironment(name)
+		env.SetGlobal(true)
+		require.NoError(t, store.Save(ctx, env))
+	}
+	require.NoError(t, store.Save(ctx, core.NewEnvironment("staging")))
+
+	globals, err := store.Globals(ctx)
+	require.NoError(t, err)
+	require.Len(t, globals, 2)
+	assert.Equal(t, "company", globals[0].Name())
+	assert.Equal(t, "personal", globals[1].Name())
+
+	list, err := store.List(ctx)
+	require.NoError(t, err)
+	for _, meta := range list {
+		assert.Equal(t, meta.Name != "staging", meta.IsGlobal, meta.Name)
+	}
+}
+
+func TestEnvironmentStore_Workspace(t *testing.T) {
+	ctx := context.Background()
+	newStores := func(t *testing.T, defaultName string) (*EnvironmentStore, *EnvironmentStore, string) {
+		userDir, workspaceDir := t.TempDir(), t.TempDir()
+		user, err := NewEnvironmentStore(userDir)
+		require.NoError(t, err)
+		layered, err := NewEnvironmentStore(userDir, WithEnvironmentWorkspace(workspaceDir, defaultName))
+		require.NoError(t, err)
+		return user, layered, workspaceDir
+	}
+
+	t.Run("saves secrets in a workspace only encrypted", func(t *testing.T) {
+		_, layered, workspaceDir := newStores(t, "")
+		env := core.NewEnvironment("staging")
+		env.SetSecret("token", "s3cr3t-token")
+		path := filepath.Join(workspaceDir, env.ID()+".yaml")
+
+		assert.ErrorIs(t, layered.Save(ctx, env), ErrWorkspaceSecrets)
+		assert.NoFileExists(t, path)
+
+		layered.SetKey(secrets.NewPassphraseKey("passphrase"))
+		require.NoError(t, layered.Save(ctx, env))
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Contains(t, string(content), "secrets_encrypted")
+		assert.NotContains(t, string(content), "s3cr3t-token")
+	})
+
+	t.Run("layers workspace environments over the user's", func(t *testing.T) {
+		user, layered, workspaceDir := newStores(t, "")
+
+		personal := core.NewEnvironment("personal")
+		require.NoError(t, user.Save(ctx, personal))
+		shadowed := core.NewEnvironment("staging")
+		shadowed.SetVariable("host", "user.example.com")
+		require.NoError(t, user.Save(ctx, shadowed))
+
+		staging := core.NewEnvironment("staging")
+		staging.SetVariable("host", "team.example.com")
+		staging.SetExtends("personal")
+		require.NoError(t, layered.Save(ctx, staging))
+		assert.FileExists(t, filepath.Join(workspaceDir, staging.ID()+".yaml"))
+
+		list, err := layered.List(ctx)
+		require.NoError(t, err)
+		require.Len(t, list, 2)
+		assert.Equal(t, "staging", list[0].Name)
+		assert.True(t, list[0].Workspace)
+		assert.Equal(t, "personal", list[1].Name)
+		assert.False(t, list[1].Workspace)
+
+		env, err := layered.GetByName(ctx, "staging")
+		require.NoError(t, err)
+		assert.Equal(t, "team.example.com", env.GetVariable("host"))
+		assert.Equal(t, []string{"staging", "personal"}, env.Chain())
+
+		// The user store doesn't see the workspace
+		env, err = user.GetByName(ctx, "staging")
+		require.NoError(t, err)
+		assert.Equal(t, "user.example.com", env.GetVariable("host"))
+	})
+
+	t.Run("keeps the workspace's active environment out of its files", func(t *testing.T) {
+		user, layered, workspaceDir := newStores(t, "")
+
+		personal := core.NewEnvironment("personal")
+		require.NoError(t, user.Save(ctx, personal))
+		require.NoError(t, user.SetActive(ctx, personal.ID()))
+		team := core.NewEnvironment("team")
+		require.NoError(t, layered.Save(ctx, team))
+
+		require.NoError(t, layered.SetActive(ctx, team.ID()))
+		active, err := layered.GetActive(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "team", active.Name())
+
+		content, err := os.ReadFile(filepath.Join(workspaceDir, team.ID()+".yaml"))
+		require.NoError(t, err)
+		assert.Contains(t, string(content), "is_active: false")
+
+		list, err := layered.List(ctx)
+		require.NoError(t, err)
+		for _, meta := range list {
+			assert.Equal(t, meta.Name == "team", meta.IsActive, meta.Name)
+		}
+
+		// Outside the workspace, the user's choice is untouched
+		active, err = user.GetActive(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "personal", active.Name())
+
+		// Choosing a user environment in the workspace takes over
+		require.NoError(t, layered.SetActive(ctx, personal.ID()))
+		active, err = layered.GetActive(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "personal", active.Name())
+	})
+
+	t.Run("activates the default environment until one is chosen", func(t *testing.T) {
+		_, layered, _ := newStores(t, "staging")
+
+		staging := core.NewEnvironment("staging")
+		require.NoError(t, layered.Save(ctx, staging))
+		local := core.NewEnvironment("local")
+		require.NoError(t, layered.Save(ctx, local))
+
+		active, err := layered.GetActive(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "staging", active.Name())
+
+		require.NoError(t, layered.SetActive(ctx, local.ID()))
+		active, err = layered.GetActive(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "local", active.Name())
+	})
+}
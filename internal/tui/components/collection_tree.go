@@ -48,6 +48,7 @@ const (
 	ItemFolder
 	ItemRequest
 	ItemWebSocket
+	ItemSection // Header grouping workspace and personal collections
 )
 
 // SelectionMsg is sent when a request is selected.
@@ -159,6 +160,13 @@ type ImportCollectionMsg struct {
 	FilePath string
 }
 
+// AttachContractMsg is sent when an OpenAPI contract is attached to a
+// collection. An empty Path detaches the current contract.
+type AttachContractMsg struct {
+	Collection *core.Collection
+	Path       string
+}
+
 // ReorderRequestMsg is sent when a request is reordered within its container.
 type ReorderRequestMsg struct {
 	Collection *core.Collection
@@ -240,6 +248,11 @@ type CollectionTree struct {
 	importing     bool   // True when entering file path for import
 	importBuffer  string // Buffer for the file path
 
+	// Contract state
+	attachingContract  bool             // True when entering a contract path
+	contractBuffer     string           // Buffer for the contract path
+	contractCollection *core.Collection // Collection the contract is attached to
+
 	// View mode (Collections or History)
 	viewMode ViewMode
 
@@ -272,6 +285,10 @@ type CollectionTree struct {
 	// Starred store for favorite requests
 	starredStore starred.Store
 	starredCache map[string]bool // Cache of starred request IDs for fast lookup
+
+	// Workspace grouping
+	workspace string          // Name of the open project workspace, "" for none
+	personal  map[string]bool // IDs of collections from the user's own store
 }
 
 // NewCollectionTree creates a new collection tree component.
@@ -368,6 +385,11 @@ func (c *CollectionTree) handleKeyMsg(msg tea.KeyMsg) (tui.Component, tea.Cmd) {
 		return c.handleImportInput(msg)
 	}
 
+	// Handle contract path input
+	if c.attachingContract {
+		return c.handleContractInput(msg)
+	}
+
 	// Handle visual/select mode input
 	if c.selectMode {
 		return c.handleVisualModeInput(msg)
@@ -463,6 +485,10 @@ func (c *CollectionTree) handleKeyMsg(msg tea.KeyMsg) (tui.Component, tea.Cmd) {
 			c.importing = true
 			c.importBuffer = ""
 			return c, nil
+		case "O":
+			// Attach an OpenAPI contract to the collection
+			c.gPressed = false
+			return c.startAttachContract()
 		case "K":
 			// Move request or folder up
 			c.gPressed = false
@@ -531,6 +557,7 @@ func (c *CollectionTree) handleKeyMsg(msg tea.KeyMsg) (tui.Component, tea.Cmd) {
 			if c.gPressed {
 				c.cursor = 0
 				c.offset = 0
+				c.skipSection(1)
 				c.gPressed = false
 			} else {
 				c.gPressed = true
@@ -2106,6 +2133,55 @@ func (c *CollectionTree) handleImportInput(msg tea.KeyMsg) (tui.Component, tea.C
 	return c, nil
 }
 
+func (c *CollectionTree) startAttachContract() (tui.Component, tea.Cmd) {
+	coll := c.GetSelectedCollection()
+	if coll == nil {
+		return c, nil
+	}
+	c.attachingContract = true
+	c.contractCollection = coll
+	c.contractBuffer = coll.Contract()
+	return c, nil
+}
+
+func (c *CollectionTree) handleContractInput(msg tea.KeyMsg) (tui.Component, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		c.attachingContract = false
+		c.contractBuffer = ""
+		c.contractCollection = nil
+		return c, nil
+
+	case tea.KeyEnter:
+		// An empty path detaches the contract
+		coll := c.contractCollection
+		path := strings.TrimSpace(c.contractBuffer)
+		c.attachingContract = false
+		c.contractBuffer = ""
+		c.contractCollection = nil
+
+		return c, func() tea.Msg {
+			return AttachContractMsg{Collection: coll, Path: path}
+		}
+
+	case tea.KeyBackspace:
+		if len(c.contractBuffer) > 0 {
+			c.contractBuffer = c.contractBuffer[:len(c.contractBuffer)-1]
+		}
+		return c, nil
+
+	case tea.KeyRunes:
+		c.contractBuffer += string(msg.Runes)
+		return c, nil
+
+	case tea.KeySpace:
+		c.contractBuffer += " "
+		return c, nil
+	}
+
+	return c, nil
+}
+
 // IsRenaming returns true if currently in rename mode.
 func (c *CollectionTree) IsRenaming() bool {
 	return c.renaming
@@ -2121,6 +2197,11 @@ func (c *CollectionTree) IsImporting() bool {
 	return c.importing
 }
 
+// IsAttachingContract returns true if currently entering a contract path.
+func (c *CollectionTree) IsAttachingContract() bool {
+	return c.attachingContract
+}
+
 func (c *CollectionTree) startMove() (tui.Component, tea.Cmd) {
 	displayItems := c.getDisplayItems()
 	if c.cursor < 0 || c.cursor >= len(displayItems) {
@@ -2490,9 +2571,31 @@ func (c *CollectionTree) moveCursorPos(delta int) {
 	displayItems := c.getDisplayItems()
 	// Use pure functions - explicit state changes
 	c.cursor = MoveCursor(c.cursor, delta, len(displayItems))
+	c.skipSection(delta)
 	c.offset = AdjustOffset(c.cursor, c.offset, c.contentHeight())
 }
 
+// skipSection moves the cursor off a section header, in the direction of
+// delta if there is an item there and the other way otherwise.
+func (c *CollectionTree) skipSection(delta int) {
+	displayItems := c.getDisplayItems()
+	if c.cursor < 0 || c.cursor >= len(displayItems) || displayItems[c.cursor].Type != ItemSection {
+		return
+	}
+	step := 1
+	if delta < 0 {
+		step = -1
+	}
+	for _, dir := range []int{step, -step} {
+		for i := c.cursor + dir; i >= 0 && i < len(displayItems); i += dir {
+			if displayItems[i].Type != ItemSection {
+				c.cursor = i
+				return
+			}
+		}
+	}
+}
+
 // getDisplayItems returns the items to display (filtered or all).
 func (c *CollectionTree) getDisplayItems() []TreeItem {
 	if c.search == "" {
@@ -2612,7 +2715,12 @@ func (c *CollectionTree) View() string {
 
 	// Render import mode overlay
 	if c.importing {
-		return c.renderImportMode(innerWidth, innerHeight)
+		return c.renderPathPrompt("Import Collection", "Enter file path (Postman JSON, OpenAPI JSON/YAML):", c.importBuffer, innerWidth, innerHeight)
+	}
+
+	// Render contract path overlay
+	if c.attachingContract {
+		return c.renderPathPrompt("Attach Contract", "OpenAPI spec to validate responses against (empty to detach):", c.contractBuffer, innerWidth, innerHeight)
 	}
 
 	// Section header styles
@@ -3143,25 +3251,26 @@ func (c *CollectionTree) renderMoveMode(innerWidth, innerHeight int) string {
 	return borderStyle.Render(content)
 }
 
-func (c *CollectionTree) renderImportMode(innerWidth, innerHeight int) string {
+// renderPathPrompt renders a full-pane prompt for entering a file path.
+func (c *CollectionTree) renderPathPrompt(title, prompt, buffer string, innerWidth, innerHeight int) string {
 	headerStyle := lipgloss.NewStyle().
 		Width(innerWidth).
 		Bold(true).
 		Foreground(lipgloss.Color("229")).
 		Background(lipgloss.Color("62"))
 
-	header := headerStyle.Render("Import Collection")
+	header := headerStyle.Render(title)
 
 	var lines []string
 	lines = append(lines, header)
 	lines = append(lines, "") // Empty line
-	lines = append(lines, "Enter file path (Postman JSON, OpenAPI JSON/YAML):")
+	lines = append(lines, prompt)
 	lines = append(lines, "")
 
 	// Show input with cursor
 	inputStyle := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("229"))
-	input := c.importBuffer + "▏"
+	input := buffer + "▏"
 	if len(input) > innerWidth-2 {
 		// Show end of path if too long
 		input = "..." + input[len(input)-innerWidth+5:]
@@ -3348,6 +3457,13 @@ func (c *CollectionTree) renderItem(item TreeItem, selected bool) string {
 	case ItemRequest:
 		icon = c.methodBadge(item.Method) + " "
 		iconWidth = 6 // method badge (5 chars) + space
+	case ItemSection:
+		sectionStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("243")).Bold(true)
+		line := " " + item.Name
+		if len(line) > width {
+			line = line[:width]
+		}
+		return sectionStyle.Render(line + strings.Repeat(" ", width-len(line)))
 	}
 
 	// Star indicator for favorited requests
@@ -3504,6 +3620,28 @@ func (c *CollectionTree) SetCollections(collections []*core.Collection) {
 	c.rebuildItems()
 	c.cursor = 0
 	c.offset = 0
+	c.skipSection(1)
+}
+
+// SetWorkspace groups the collections under a header for the project
+// workspace name and a "Personal" header for the collections whose IDs are
+// in personal, which come from the user's own store. Collections added later
+// belong to the workspace, where new collections are saved. An empty name
+// turns the grouping off.
+func (c *CollectionTree) SetWorkspace(name string, personal []string) {
+	c.workspace = name
+	c.personal = make(map[string]bool, len(personal))
+	for _, id := range personal {
+		c.personal[id] = true
+	}
+	c.rebuildItems()
+	c.skipSection(1)
+}
+
+// Workspace returns the name of the open project workspace, or "" if there
+// is none.
+func (c *CollectionTree) Workspace() string {
+	return c.workspace
 }
 
 // Collections returns the current collections.
@@ -3678,6 +3816,20 @@ func (c *CollectionTree) folderBelongsToFolder(folder *core.Folder, parent *core
 	return false
 }
 
+// CollectionForRequest returns the collection containing the request, or nil
+// if it is not part of any collection.
+func (c *CollectionTree) CollectionForRequest(req *core.RequestDefinition) *core.Collection {
+	if req == nil {
+		return nil
+	}
+	for _, coll := range c.collections {
+		if c.requestBelongsToCollection(req, coll) {
+			return coll
+		}
+	}
+	return nil
+}
+
 // requestBelongsToCollection checks if a request belongs to a collection.
 func (c *CollectionTree) requestBelongsToCollection(req *core.RequestDefinition, coll *core.Collection) bool {
 	for _, r := range coll.Requests() {
@@ -3830,7 +3982,7 @@ func (c *CollectionTree) VisibleItemCount() int {
 	count := 0
 	search := strings.ToLower(c.search)
 	for _, item := range c.items {
-		if strings.Contains(strings.ToLower(item.Name), search) {
+		if item.Type != ItemSection && strings.Contains(strings.ToLower(item.Name), search) {
 			count++
 		}
 	}
@@ -3878,9 +4030,29 @@ func (c *CollectionTree) RebuildItems() {
 func (c *CollectionTree) rebuildItems() {
 	c.items = nil
 
+	if c.workspace == "" {
+		for _, coll := range c.collections {
+			c.addCollectionItems(coll, 0)
+		}
+		return
+	}
+
+	// Workspace collections first, then the user's own
+	var personal []*core.Collection
+	c.items = append(c.items, TreeItem{ID: "section:workspace", Name: "Workspace: " + c.workspace, Type: ItemSection})
 	for _, coll := range c.collections {
+		if c.personal[coll.ID()] {
+			personal = append(personal, coll)
+			continue
+		}
 		c.addCollectionItems(coll, 0)
 	}
+	if len(personal) > 0 {
+		c.items = append(c.items, TreeItem{ID: "section:personal", Name: "Personal", Type: ItemSection})
+		for _, coll := range personal {
+			c.addCollectionItems(coll, 0)
+		}
+	}
 }
 
 func (c *CollectionTree) addCollectionItems(coll *core.Collection, level int) {
@@ -96,6 +96,58 @@ func TestCollectionTree_SetCollections(t *testing.T) {
 	})
 }
 
+func TestCollectionTree_SetWorkspace(t *testing.T) {
+	shared := core.NewCollection("Shared API")
+	mine := core.NewCollection("My API")
+
+	t.Run("groups workspace and personal collections under headers", func(t *testing.T) {
+		tree := NewCollectionTree()
+		tree.SetCollections([]*core.Collection{mine, shared})
+		tree.SetWorkspace("shop", []string{mine.ID()})
+
+		var names []string
+		for _, item := range tree.items {
+			names = append(names, item.Name)
+		}
+		assert.Equal(t, []string{"Workspace: shop", "Shared API", "Personal", "My API"}, names)
+		assert.Equal(t, ItemSection, tree.items[0].Type)
+		assert.Equal(t, 1, tree.Cursor())
+	})
+
+	t.Run("cursor skips section headers", func(t *testing.T) {
+		tree := NewCollectionTree()
+		tree.SetCollections([]*core.Collection{shared, mine})
+		tree.SetWorkspace("shop", []string{mine.ID()})
+		tree.viewMode = ViewCollections
+		tree.Focus()
+
+		tree = pressKey(tree, 'j')
+		assert.Equal(t, "My API", tree.Selected().Name)
+		tree = pressKey(tree, 'k')
+		assert.Equal(t, "Shared API", tree.Selected().Name)
+		tree = pressKey(tree, 'k')
+		assert.Equal(t, "Shared API", tree.Selected().Name)
+	})
+
+	t.Run("search ignores section headers", func(t *testing.T) {
+		tree := NewCollectionTree()
+		tree.SetCollections([]*core.Collection{shared, mine})
+		tree.SetWorkspace("shop", []string{mine.ID()})
+		tree.viewMode = ViewCollections
+		tree.Focus()
+
+		tree = typeSearch(tree, "shop")
+		assert.Equal(t, 0, tree.VisibleItemCount())
+	})
+
+	t.Run("has no headers without a workspace", func(t *testing.T) {
+		tree := NewCollectionTree()
+		tree.SetCollections([]*core.Collection{shared, mine})
+		tree.SetWorkspace("", nil)
+		assert.Equal(t, 2, tree.ItemCount())
+	})
+}
+
 func TestCollectionTree_Navigation(t *testing.T) {
 	t.Run("moves cursor down with j", func(t *testing.T) {
 		tree := newTestTree(t)
@@ -3689,6 +3741,69 @@ func TestCollectionTree_HandleImportInputDirect(t *testing.T) {
 	})
 }
 
+func TestCollectionTree_AttachContract(t *testing.T) {
+	setup := func() (*CollectionTree, *core.Collection, *core.RequestDefinition) {
+		tree := NewCollectionTree()
+		tree.SetSize(80, 30)
+		col := core.NewCollection("Pets")
+		req := core.NewRequestDefinition("List", "GET", "http://test.com/pets")
+		col.AddRequest(req)
+		col.SetContract("/specs/old.yaml")
+		tree.SetCollections([]*core.Collection{col})
+		tree.viewMode = ViewCollections
+		tree.Focus()
+		return tree, col, req
+	}
+
+	t.Run("O opens the prompt with the current contract", func(t *testing.T) {
+		tree, _, _ := setup()
+
+		updated, _ := tree.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'O'}})
+		tree = updated.(*CollectionTree)
+
+		assert.True(t, tree.IsAttachingContract())
+		assert.Equal(t, "/specs/old.yaml", tree.contractBuffer)
+		assert.Contains(t, tree.View(), "Attach Contract")
+	})
+
+	t.Run("enter sends AttachContractMsg", func(t *testing.T) {
+		tree, col, _ := setup()
+		tree.startAttachContract()
+		tree.contractBuffer = ""
+		for _, r := range "api.yaml" {
+			tree.handleContractInput(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		}
+
+		_, cmd := tree.handleContractInput(tea.KeyMsg{Type: tea.KeyEnter})
+		if !assert.NotNil(t, cmd) {
+			return
+		}
+		assert.False(t, tree.IsAttachingContract())
+
+		msg, ok := cmd().(AttachContractMsg)
+		assert.True(t, ok)
+		assert.Equal(t, col, msg.Collection)
+		assert.Equal(t, "api.yaml", msg.Path)
+	})
+
+	t.Run("escape cancels", func(t *testing.T) {
+		tree, _, _ := setup()
+		tree.startAttachContract()
+
+		_, cmd := tree.handleContractInput(tea.KeyMsg{Type: tea.KeyEsc})
+		assert.Nil(t, cmd)
+		assert.False(t, tree.IsAttachingContract())
+	})
+
+	t.Run("CollectionForRequest finds the owning collection", func(t *testing.T) {
+		tree, col, req := setup()
+
+		assert.Equal(t, col, tree.CollectionForRequest(req))
+		assert.Nil(t, tree.CollectionForRequest(core.NewRequestDefinition("Other", "GET", "http://x")))
+		assert.Nil(t, tree.CollectionForRequest(nil))
+	})
+}
+
 func TestCollectionTree_HandleBulkDelete(t *testing.T) {
 	t.Run("returns nil when no items selected", func(t *testing.T) {
 		tree := NewCollectionTree()
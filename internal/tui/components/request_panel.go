@@ -6,7 +6,9 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/artpar/currier/internal/assertion"
 	"github.com/artpar/currier/internal/core"
+	"github.com/artpar/currier/internal/interpolate"
 	"github.com/artpar/currier/internal/script"
 	"github.com/artpar/currier/internal/tui"
 )
@@ -107,9 +109,16 @@ type RequestPanel struct {
 	testScriptLines      []string // Test script split into lines
 	testScriptCursorLine int      // Current line
 	testScriptCursorCol  int      // Current column
+	editingAssertions    bool     // Test script editor holds assertions instead of the script
+	assertionError       string   // Parse error shown while editing assertions
 
 	// Body type state (for form-data support)
-	bodyTypeIndex int // 0=raw, 1=json, 2=form
+	bodyTypeIndex int // 0=raw, 1=json, 2=form, 3=urlencoded, 4=binary
+
+	// Content type editing state (for raw and binary body types)
+	editingContentType bool   // True when editing the body content type
+	contentTypeInput   string // Current content type input
+	contentTypeCursor  int    // Cursor position in content type
 
 	// Form field editing state (for form-data body type)
 	formFields        []core.FormField // Local copy of form fields
@@ -122,6 +131,10 @@ type RequestPanel struct {
 	formValueCursor   int              // Cursor position in value
 	formIsNew         bool             // True if adding new field
 	formIsFile        bool             // True if current field is a file
+
+	// resolver returns the engine a request's variables resolve through,
+	// used to show where each {{variable}} comes from
+	resolver func(*core.RequestDefinition) *interpolate.Engine
 }
 
 // NewRequestPanel creates a new request panel.
@@ -228,6 +241,11 @@ func (p *RequestPanel) handleKeyMsg(msg tea.KeyMsg) (tui.Component, tea.Cmd) {
 		return p.handleFormFieldEditInput(msg)
 	}
 
+	// Handle content type editing mode (for raw and binary body types)
+	if p.editingContentType {
+		return p.handleContentTypeEditInput(msg)
+	}
+
 	switch msg.Type {
 	case tea.KeyEnter:
 		// Send request from any tab when not in edit mode
@@ -282,7 +300,7 @@ func (p *RequestPanel) handleKeyMsg(msg tea.KeyMsg) (tui.Component, tea.Cmd) {
 			}
 			// Enter body edit mode
 			if p.activeTab == TabBody && p.request != nil {
-				if p.bodyTypeIndex == 2 {
+				if p.isFormBody() {
 					// Form-data mode: edit selected form field
 					if p.formCursor < len(p.formFields) {
 						field := p.formFields[p.formCursor]
@@ -354,9 +372,21 @@ func (p *RequestPanel) handleKeyMsg(msg tea.KeyMsg) (tui.Component, tea.Cmd) {
 				p.testScriptCursorLine = 0
 				p.testScriptCursorCol = 0
 				p.editingTestScript = true
+				p.editingAssertions = false
 				return p, nil
 			}
 		case "a":
+			// Edit declarative assertions and extractions
+			if p.activeTab == TabTests && p.request != nil {
+				text := assertion.Format(p.request.Assertions(), p.request.Extractions())
+				p.testScriptLines = strings.Split(text, "\n")
+				p.testScriptCursorLine = 0
+				p.testScriptCursorCol = 0
+				p.editingTestScript = true
+				p.editingAssertions = true
+				p.assertionError = ""
+				return p, nil
+			}
 			// Add new header
 			if p.activeTab == TabHeaders && p.request != nil {
 				p.editingHeader = true
@@ -382,7 +412,7 @@ func (p *RequestPanel) handleKeyMsg(msg tea.KeyMsg) (tui.Component, tea.Cmd) {
 				return p, nil
 			}
 			// Add new form field (text)
-			if p.activeTab == TabBody && p.request != nil && p.bodyTypeIndex == 2 {
+			if p.activeTab == TabBody && p.request != nil && p.isFormBody() {
 				p.editingFormField = true
 				p.formIsNew = true
 				p.formEditMode = "key"
@@ -407,9 +437,9 @@ func (p *RequestPanel) handleKeyMsg(msg tea.KeyMsg) (tui.Component, tea.Cmd) {
 				return p, nil
 			}
 		case "t":
-			// Cycle body type (raw -> json -> form)
+			// Cycle body type (raw -> json -> form -> urlencoded -> binary)
 			if p.activeTab == TabBody && p.request != nil {
-				p.bodyTypeIndex = (p.bodyTypeIndex + 1) % 3
+				p.bodyTypeIndex = (p.bodyTypeIndex + 1) % 5
 				// Sync body type to request
 				switch p.bodyTypeIndex {
 				case 0:
@@ -421,16 +451,30 @@ func (p *RequestPanel) handleKeyMsg(msg tea.KeyMsg) (tui.Component, tea.Cmd) {
 					// Sync form fields
 					p.formFields = p.request.FormFields()
 					p.formCursor = 0
+				case 3:
+					p.request.SetBodyType("urlencoded")
+					p.formFields = p.request.FormFields()
+					p.formCursor = 0
+				case 4:
+					p.request.SetBodyType("binary")
 				}
 				return p, nil
 			}
+		case "c":
+			// Edit content type for raw and binary bodies
+			if p.activeTab == TabBody && p.request != nil && (p.bodyTypeIndex == 0 || p.bodyTypeIndex == 4) {
+				p.editingContentType = true
+				p.contentTypeInput = p.request.BodyContentType()
+				p.contentTypeCursor = len(p.contentTypeInput)
+				return p, nil
+			}
 		case "T":
 			// Toggle field type (text <-> file) for form-data
 			if p.activeTab == TabBody && p.request != nil && p.bodyTypeIndex == 2 {
 				if p.formCursor < len(p.formFields) {
 					p.formFields[p.formCursor].IsFile = !p.formFields[p.formCursor].IsFile
 					// Sync back to request
-					p.request.SetBodyFormData(p.formFields)
+					p.syncFormFields()
 					return p, nil
 				}
 			}
@@ -462,11 +506,11 @@ func (p *RequestPanel) handleKeyMsg(msg tea.KeyMsg) (tui.Component, tea.Cmd) {
 				}
 			}
 			// Delete form field at cursor
-			if p.activeTab == TabBody && p.request != nil && p.bodyTypeIndex == 2 {
+			if p.activeTab == TabBody && p.request != nil && p.isFormBody() {
 				if p.formCursor < len(p.formFields) {
 					// Remove field at cursor
 					p.formFields = append(p.formFields[:p.formCursor], p.formFields[p.formCursor+1:]...)
-					p.request.SetBodyFormData(p.formFields)
+					p.syncFormFields()
 					if p.formCursor >= len(p.formFields) && p.formCursor > 0 {
 						p.formCursor--
 					}
@@ -864,6 +908,95 @@ func (p *RequestPanel) handleQueryEditInput(msg tea.KeyMsg) (tui.Component, tea.
 	return p, nil
 }
 
+// isFormBody reports whether the body tab is showing the form field editor.
+func (p *RequestPanel) isFormBody() bool {
+	return p.bodyTypeIndex == 2 || p.bodyTypeIndex == 3
+}
+
+// syncFormFields writes the local form fields back to the request using the
+// current body type.
+func (p *RequestPanel) syncFormFields() {
+	if p.bodyTypeIndex == 3 {
+		p.request.SetBodyURLEncoded(p.formFields)
+		return
+	}
+	p.request.SetBodyFormData(p.formFields)
+}
+
+// contentTypePresets are cycled with Tab while editing the body content type.
+var contentTypePresets = []string{
+	"text/plain",
+	"application/json",
+	"application/xml",
+	"text/xml",
+	"text/html",
+	"text/csv",
+	"application/javascript",
+	"application/graphql",
+	"application/octet-stream",
+}
+
+// handleContentTypeEditInput handles keyboard input while editing the body content type.
+func (p *RequestPanel) handleContentTypeEditInput(msg tea.KeyMsg) (tui.Component, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc, tea.KeyEnter:
+		// Save and exit
+		p.request.SetBodyContentType(strings.TrimSpace(p.contentTypeInput))
+		p.editingContentType = false
+		return p, nil
+
+	case tea.KeyTab:
+		// Replace input with the next preset
+		next := 0
+		for i, preset := range contentTypePresets {
+			if preset == p.contentTypeInput {
+				next = (i + 1) % len(contentTypePresets)
+				break
+			}
+		}
+		p.contentTypeInput = contentTypePresets[next]
+		p.contentTypeCursor = len(p.contentTypeInput)
+		return p, nil
+
+	case tea.KeyBackspace:
+		if p.contentTypeCursor > 0 {
+			p.contentTypeInput = p.contentTypeInput[:p.contentTypeCursor-1] + p.contentTypeInput[p.contentTypeCursor:]
+			p.contentTypeCursor--
+		}
+		return p, nil
+
+	case tea.KeyLeft:
+		if p.contentTypeCursor > 0 {
+			p.contentTypeCursor--
+		}
+		return p, nil
+
+	case tea.KeyRight:
+		if p.contentTypeCursor < len(p.contentTypeInput) {
+			p.contentTypeCursor++
+		}
+		return p, nil
+
+	case tea.KeyCtrlU:
+		p.contentTypeInput = ""
+		p.contentTypeCursor = 0
+		return p, nil
+
+	case tea.KeySpace:
+		p.contentTypeInput = p.contentTypeInput[:p.contentTypeCursor] + " " + p.contentTypeInput[p.contentTypeCursor:]
+		p.contentTypeCursor++
+		return p, nil
+
+	case tea.KeyRunes:
+		text := string(msg.Runes)
+		p.contentTypeInput = p.contentTypeInput[:p.contentTypeCursor] + text + p.contentTypeInput[p.contentTypeCursor:]
+		p.contentTypeCursor += len(text)
+		return p, nil
+	}
+
+	return p, nil
+}
+
 // handleFormFieldEditInput handles keyboard input while editing a form field.
 func (p *RequestPanel) handleFormFieldEditInput(msg tea.KeyMsg) (tui.Component, tea.Cmd) {
 	switch msg.Type {
@@ -885,7 +1018,7 @@ func (p *RequestPanel) handleFormFieldEditInput(msg tea.KeyMsg) (tui.Component,
 			} else {
 				p.formFields[p.formCursor] = field
 			}
-			p.request.SetBodyFormData(p.formFields)
+			p.syncFormFields()
 		}
 		p.editingFormField = false
 		return p, nil
@@ -908,7 +1041,7 @@ func (p *RequestPanel) handleFormFieldEditInput(msg tea.KeyMsg) (tui.Component,
 			} else {
 				p.formFields[p.formCursor] = field
 			}
-			p.request.SetBodyFormData(p.formFields)
+			p.syncFormFields()
 		}
 		p.editingFormField = false
 		return p, nil
@@ -1036,6 +1169,10 @@ func (p *RequestPanel) handleBodyEditInput(msg tea.KeyMsg) (tui.Component, tea.C
 	case tea.KeyEsc:
 		// Save and exit (vim-like: Esc returns to normal mode with changes saved)
 		body := strings.Join(p.bodyLines, "\n")
+		if p.bodyTypeIndex == 4 {
+			// Binary bodies hold a single file path
+			body = strings.TrimSpace(strings.Join(p.bodyLines, ""))
+		}
 		p.request.SetBody(body)
 		p.editingBody = false
 		return p, nil
@@ -1290,9 +1427,29 @@ func (p *RequestPanel) handleTestScriptEditInput(msg tea.KeyMsg) (tui.Component,
 
 	case tea.KeyEsc:
 		// Save and exit
-		script := strings.Join(p.testScriptLines, "\n")
-		p.request.SetPostScript(script)
+		text := strings.Join(p.testScriptLines, "\n")
+		if p.editingAssertions {
+			assertions, extractions, err := assertion.Parse(text)
+			if err != nil {
+				// Stay in the editor until the assertions parse
+				p.assertionError = err.Error()
+				return p, nil
+			}
+			p.request.SetAssertions(assertions)
+			p.request.SetExtractions(extractions)
+			p.editingAssertions = false
+			p.assertionError = ""
+		} else {
+			p.request.SetPostScript(text)
+		}
+		p.editingTestScript = false
+		return p, nil
+
+	case tea.KeyCtrlX:
+		// Discard edits, keeping the last saved script or assertions
 		p.editingTestScript = false
+		p.editingAssertions = false
+		p.assertionError = ""
 		return p, nil
 
 	case tea.KeyEnter:
@@ -1798,7 +1955,7 @@ func (p *RequestPanel) prevTab() {
 
 func (p *RequestPanel) moveCursor(delta int) {
 	// Handle form cursor separately for Body tab in form-data mode
-	if p.activeTab == TabBody && p.bodyTypeIndex == 2 {
+	if p.activeTab == TabBody && p.isFormBody() {
 		p.formCursor += delta
 		if p.formCursor < 0 {
 			p.formCursor = 0
@@ -1836,7 +1993,7 @@ func (p *RequestPanel) maxCursorForTab() int {
 	case TabQuery:
 		return len(p.request.QueryParams()) - 1
 	case TabBody:
-		if p.bodyTypeIndex == 2 {
+		if p.isFormBody() {
 			return len(p.formFields) - 1
 		}
 		return 0
@@ -2101,10 +2258,69 @@ func (p *RequestPanel) renderURLTab() []string {
 		return []string{"No request"}
 	}
 
-	return []string{
+	lines := []string{
 		fmt.Sprintf("URL: %s", p.request.FullURL()),
 		fmt.Sprintf("Method: %s", p.request.Method()),
 	}
+	return append(lines, p.renderVariableSources(p.request.FullURL())...)
+}
+
+// renderVariableSources lists the {{variables}} used in inputs with the scope
+// level each resolves from and the levels it shadows, so a value overridden
+// by a narrower scope is visible before the request is sent.
+func (p *RequestPanel) renderVariableSources(inputs ...string) []string {
+	if p.resolver == nil {
+		return nil
+	}
+	engine := p.resolver(p.request)
+	if engine == nil {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	var refs []interpolate.Reference
+	for _, input := range inputs {
+		for _, ref := range engine.References(input) {
+			if !seen[ref.Name] {
+				seen[ref.Name] = true
+				refs = append(refs, ref)
+			}
+		}
+	}
+	if len(refs) == 0 {
+		return nil
+	}
+
+	nameStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+	levelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("39"))
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("243"))
+	missingStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+
+	lines := []string{"", dimStyle.Render("Variables:")}
+	for _, ref := range refs {
+		var source string
+		switch {
+		case ref.Builtin:
+			source = dimStyle.Render("dynamic")
+		case !ref.Defined:
+			source = missingStyle.Render("undefined")
+		default:
+			value := ref.Value
+			if len(value) > 40 {
+				value = value[:39] + "…"
+			}
+			source = levelStyle.Render(ref.Level.String()) + " " + value
+			if len(ref.Shadows) > 0 {
+				shadowed := make([]string, len(ref.Shadows))
+				for i, level := range ref.Shadows {
+					shadowed[i] = level.String()
+				}
+				source += dimStyle.Render(" (shadows " + strings.Join(shadowed, ", ") + ")")
+			}
+		}
+		lines = append(lines, fmt.Sprintf("  %s %s", nameStyle.Render("{{"+ref.Name+"}}"), source))
+	}
+	return lines
 }
 
 func (p *RequestPanel) renderHeadersTab() []string {
@@ -2170,6 +2386,15 @@ func (p *RequestPanel) renderHeadersTab() []string {
 		}
 	}
 
+	// Where the variables in header values come from
+	if !p.editingHeader {
+		values := make([]string, len(p.headerKeys))
+		for i, key := range p.headerKeys {
+			values[i] = p.request.GetHeader(key)
+		}
+		lines = append(lines, p.renderVariableSources(values...)...)
+	}
+
 	// Empty state or hint
 	if len(p.headerKeys) == 0 && !p.editingHeader {
 		lines = append(lines, "")
@@ -2351,14 +2576,14 @@ func (p *RequestPanel) renderBodyTab() []string {
 	fileStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("178"))
 
 	// Body type names
-	bodyTypeNames := []string{"Raw", "JSON", "Form-data"}
+	bodyTypeNames := []string{"Raw", "JSON", "Form-data", "URL-encoded", "Binary"}
 
 	// Body type selector
 	bodyTypeLine := fmt.Sprintf("  Body Type: %s  ", selectedStyle.Render("◀ "+bodyTypeNames[p.bodyTypeIndex]+" ▶"))
 	lines = append(lines, bodyTypeLine)
 	lines = append(lines, lipgloss.NewStyle().Foreground(lipgloss.Color("238")).Render(strings.Repeat("─", innerWidth)))
 
-	if p.bodyTypeIndex == 2 {
+	if p.isFormBody() {
 		// Form-data mode
 		if p.editingFormField {
 			// Show form field editor
@@ -2410,7 +2635,11 @@ func (p *RequestPanel) renderBodyTab() []string {
 			// Show form fields list
 			if len(p.formFields) == 0 {
 				lines = append(lines, "")
-				lines = append(lines, hintStyle.Render("  No form fields. Press 'a' to add text, 'f' to add file."))
+				if p.bodyTypeIndex == 2 {
+					lines = append(lines, hintStyle.Render("  No form fields. Press 'a' to add text, 'f' to add file."))
+				} else {
+					lines = append(lines, hintStyle.Render("  No form fields. Press 'a' to add a field."))
+				}
 			} else {
 				for i, field := range p.formFields {
 					prefix := "  "
@@ -2445,11 +2674,18 @@ func (p *RequestPanel) renderBodyTab() []string {
 
 			if p.focused {
 				lines = append(lines, "")
-				lines = append(lines, hintStyle.Render("  t: cycle body type │ a: add text │ f: add file │ e: edit │ d: delete │ T: toggle type"))
+				if p.bodyTypeIndex == 2 {
+					lines = append(lines, hintStyle.Render("  t: cycle body type │ a: add text │ f: add file │ e: edit │ d: delete │ T: toggle type"))
+				} else {
+					lines = append(lines, hintStyle.Render("  t: cycle body type │ a: add field │ e: edit │ d: delete"))
+				}
 			}
 		}
 	} else {
-		// Raw or JSON mode
+		// Raw, JSON or binary mode
+		if p.bodyTypeIndex == 0 || p.bodyTypeIndex == 4 {
+			lines = append(lines, p.renderContentTypeLine(labelStyle, valueStyle, hintStyle))
+		}
 		if p.editingBody {
 			// Show editable body with cursor
 			for i, line := range p.bodyLines {
@@ -2479,7 +2715,14 @@ func (p *RequestPanel) renderBodyTab() []string {
 			lines = append(lines, hintStyle.Render("  Esc: save and exit │ ↑↓←→: navigate │ Enter: new line"))
 		} else {
 			body := p.request.Body()
-			if body == "" {
+			if p.bodyTypeIndex == 4 {
+				if body == "" {
+					lines = append(lines, "")
+					lines = append(lines, hintStyle.Render("  No file selected. Press 'e' to enter a file path."))
+				} else {
+					lines = append(lines, fmt.Sprintf("  %s %s", fileStyle.Render("[FILE]"), valueStyle.Render(body)))
+				}
+			} else if body == "" {
 				lines = append(lines, "")
 				lines = append(lines, hintStyle.Render("  No body defined. Press 'e' to edit."))
 			} else {
@@ -2495,7 +2738,14 @@ func (p *RequestPanel) renderBodyTab() []string {
 			// Add hint when focused
 			if p.focused {
 				lines = append(lines, "")
-				lines = append(lines, hintStyle.Render("  t: cycle body type │ e: edit body"))
+				switch p.bodyTypeIndex {
+				case 0:
+					lines = append(lines, hintStyle.Render("  t: cycle body type │ e: edit body │ c: content type"))
+				case 4:
+					lines = append(lines, hintStyle.Render("  t: cycle body type │ e: edit file path │ c: content type"))
+				default:
+					lines = append(lines, hintStyle.Render("  t: cycle body type │ e: edit body"))
+				}
 			}
 		}
 	}
@@ -2503,6 +2753,30 @@ func (p *RequestPanel) renderBodyTab() []string {
 	return lines
 }
 
+// renderContentTypeLine renders the content type selector for raw and binary bodies.
+func (p *RequestPanel) renderContentTypeLine(labelStyle, valueStyle, hintStyle lipgloss.Style) string {
+	if p.editingContentType {
+		content := p.contentTypeInput
+		if p.contentTypeCursor >= len(content) {
+			content += "▌"
+		} else {
+			content = content[:p.contentTypeCursor] + "▌" + content[p.contentTypeCursor:]
+		}
+		return fmt.Sprintf("> %s: %s  %s", labelStyle.Render("Content-Type"), content,
+			hintStyle.Render("Tab: next preset │ Enter/Esc: save"))
+	}
+
+	contentType := p.request.BodyContentType()
+	if contentType == "" {
+		if p.bodyTypeIndex == 4 {
+			contentType = "(from file extension)"
+		} else {
+			contentType = "text/plain"
+		}
+	}
+	return fmt.Sprintf("  %s: %s", labelStyle.Render("Content-Type"), valueStyle.Render(contentType))
+}
+
 func (p *RequestPanel) renderAuthTab() []string {
 	if p.request == nil {
 		return []string{"No auth"}
@@ -2706,6 +2980,9 @@ func (p *RequestPanel) renderTestsTab() []string {
 	var lines []string
 
 	if p.editingTestScript {
+		if p.editingAssertions {
+			lines = append(lines, lipgloss.NewStyle().Bold(true).Render("Assertions"))
+		}
 		// Show editable script with cursor
 		for i, line := range p.testScriptLines {
 			displayLine := line
@@ -2731,7 +3008,14 @@ func (p *RequestPanel) renderTestsTab() []string {
 
 		// Add hints
 		lines = append(lines, "")
-		lines = append(lines, hintStyle.Render("  Esc: save and exit │ ↑↓←→: navigate │ Enter: new line"))
+		if p.assertionError != "" {
+			errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+			lines = append(lines, errorStyle.Render("  ✗ "+p.assertionError))
+		}
+		if p.editingAssertions {
+			lines = append(lines, hintStyle.Render("  One per line: status equals 200 │ jsonpath $.id exists │ extract token jsonpath $.token"))
+		}
+		lines = append(lines, hintStyle.Render("  Esc: save and exit │ Ctrl+X: discard │ ↑↓←→: navigate │ Enter: new line"))
 	} else {
 		script := p.request.PostScript()
 		if script == "" {
@@ -2755,10 +3039,22 @@ func (p *RequestPanel) renderTestsTab() []string {
 			}
 		}
 
+		// Declarative checks run after the script
+		if text := assertion.Format(p.request.Assertions(), p.request.Extractions()); text != "" {
+			lines = append(lines, "")
+			lines = append(lines, lipgloss.NewStyle().Bold(true).Render("Assertions"))
+			for _, line := range strings.Split(text, "\n") {
+				if len(line) > innerWidth {
+					line = line[:innerWidth-1] + "…"
+				}
+				lines = append(lines, line)
+			}
+		}
+
 		// Add hint when focused
 		if p.focused {
 			lines = append(lines, "")
-			lines = append(lines, hintStyle.Render("  Press 'e' to edit (Esc saves and exits)"))
+			lines = append(lines, hintStyle.Render("  Press 'e' to edit the script, 'a' to edit assertions (Esc saves and exits)"))
 		}
 	}
 
@@ -2822,7 +3118,7 @@ func (p *RequestPanel) Blur() {
 
 // IsEditing returns true if the panel is in any editing mode.
 func (p *RequestPanel) IsEditing() bool {
-	return p.editingURL || p.editingHeader || p.editingQuery || p.editingBody || p.editingAuth || p.editingPreScript || p.editingTestScript
+	return p.editingURL || p.editingHeader || p.editingQuery || p.editingBody || p.editingAuth || p.editingPreScript || p.editingTestScript || p.editingContentType
 }
 
 // SetSize sets dimensions.
@@ -2841,6 +3137,13 @@ func (p *RequestPanel) Height() int {
 	return p.height
 }
 
+// SetVariableResolver sets the function returning the engine a request's
+// variables resolve through. The URL and Headers tabs use it to show where
+// each {{variable}} comes from.
+func (p *RequestPanel) SetVariableResolver(fn func(*core.RequestDefinition) *interpolate.Engine) {
+	p.resolver = fn
+}
+
 // Request returns the current request.
 func (p *RequestPanel) Request() *core.RequestDefinition {
 	return p.request
@@ -2860,6 +3163,12 @@ func (p *RequestPanel) SetRequest(req *core.RequestDefinition) {
 			p.bodyTypeIndex = 2
 			p.formFields = req.FormFields()
 			p.formCursor = 0
+		case "urlencoded":
+			p.bodyTypeIndex = 3
+			p.formFields = req.FormFields()
+			p.formCursor = 0
+		case "binary":
+			p.bodyTypeIndex = 4
 		default:
 			p.bodyTypeIndex = 0
 		}
@@ -3010,6 +3319,9 @@ func (p *RequestPanel) EditingField() string {
 	if p.editingTestScript {
 		return "test_script"
 	}
+	if p.editingContentType {
+		return "content_type"
+	}
 	return ""
 }
 
@@ -3036,6 +3348,9 @@ func (p *RequestPanel) CursorPosition() int {
 	if p.editingPreScript {
 		return p.preScriptCursorCol
 	}
+	if p.editingContentType {
+		return p.contentTypeCursor
+	}
 	if p.editingTestScript {
 		return p.testScriptCursorCol
 	}
@@ -6,6 +6,7 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/artpar/currier/internal/core"
+	"github.com/artpar/currier/internal/interpolate"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -3552,6 +3553,97 @@ func TestRequestPanel_TestScriptEditingViaKeys(t *testing.T) {
 	})
 }
 
+func TestRequestPanel_AssertionEditing(t *testing.T) {
+	newPanel := func() (*RequestPanel, *core.RequestDefinition) {
+		panel := NewRequestPanel()
+		req := core.NewRequestDefinition("Test", "GET", "https://example.com")
+		req.SetAssertions([]core.Assertion{{Source: core.AssertionSourceStatus, Operator: core.AssertionEquals, Value: "200"}})
+		panel.SetRequest(req)
+		panel.Focus()
+		panel.SetSize(100, 40)
+		panel.SetActiveTab(TabTests)
+		return panel, req
+	}
+	typeText := func(panel *RequestPanel, text string) *RequestPanel {
+		updated, _ := panel.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		panel = updated.(*RequestPanel)
+		updated, _ = panel.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(text)})
+		return updated.(*RequestPanel)
+	}
+
+	t.Run("shows assertions on the tests tab", func(t *testing.T) {
+		panel, _ := newPanel()
+		view := panel.View()
+		assert.Contains(t, view, "Assertions")
+		assert.Contains(t, view, "status equals 200")
+	})
+
+	t.Run("a opens the assertions editor", func(t *testing.T) {
+		panel, _ := newPanel()
+		updated, _ := panel.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+		panel = updated.(*RequestPanel)
+
+		assert.True(t, panel.editingTestScript)
+		assert.True(t, panel.editingAssertions)
+		assert.Equal(t, []string{"status equals 200"}, panel.testScriptLines)
+	})
+
+	t.Run("escape saves parsed assertions and extractions", func(t *testing.T) {
+		panel, req := newPanel()
+		updated, _ := panel.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+		panel = updated.(*RequestPanel)
+		panel.testScriptCursorLine = 0
+		panel.testScriptCursorCol = len(panel.testScriptLines[0])
+		panel = typeText(panel, "extract token jsonpath $.token")
+
+		updated, _ = panel.Update(tea.KeyMsg{Type: tea.KeyEsc})
+		panel = updated.(*RequestPanel)
+
+		assert.False(t, panel.editingTestScript)
+		assert.Len(t, req.Assertions(), 1)
+		assert.Equal(t, []core.Extraction{{Variable: "token", Source: core.AssertionSourceJSONPath, Expression: "$.token"}}, req.Extractions())
+		assert.Empty(t, req.PostScript())
+	})
+
+	t.Run("escape keeps the editor open on invalid input", func(t *testing.T) {
+		panel, req := newPanel()
+		updated, _ := panel.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+		panel = updated.(*RequestPanel)
+		panel.testScriptCursorLine = 0
+		panel.testScriptCursorCol = len(panel.testScriptLines[0])
+		panel = typeText(panel, "status below 200")
+
+		updated, _ = panel.Update(tea.KeyMsg{Type: tea.KeyEsc})
+		panel = updated.(*RequestPanel)
+
+		assert.True(t, panel.editingTestScript)
+		assert.Contains(t, panel.assertionError, "line 2")
+		assert.Contains(t, panel.View(), "line 2")
+		assert.Len(t, req.Assertions(), 1)
+	})
+
+	t.Run("ctrl+x discards invalid input", func(t *testing.T) {
+		panel, req := newPanel()
+		updated, _ := panel.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+		panel = updated.(*RequestPanel)
+		panel.testScriptCursorLine = 0
+		panel.testScriptCursorCol = len(panel.testScriptLines[0])
+		panel = typeText(panel, "status below 200")
+		updated, _ = panel.Update(tea.KeyMsg{Type: tea.KeyEsc})
+		panel = updated.(*RequestPanel)
+
+		updated, _ = panel.Update(tea.KeyMsg{Type: tea.KeyCtrlX})
+		panel = updated.(*RequestPanel)
+
+		assert.False(t, panel.editingTestScript)
+		assert.False(t, panel.editingAssertions)
+		assert.Empty(t, panel.assertionError)
+		assert.Len(t, req.Assertions(), 1)
+		assert.Empty(t, req.Extractions())
+		assert.NotContains(t, panel.View(), "line 2")
+	})
+}
+
 func TestRequestPanel_RenderTabs(t *testing.T) {
 	t.Run("renderQueryTab renders query params", func(t *testing.T) {
 		panel := NewRequestPanel()
@@ -3775,3 +3867,135 @@ func TestRequestPanel_MaxCursorForTabMore(t *testing.T) {
 		assert.GreaterOrEqual(t, max, 0)
 	})
 }
+
+func TestRequestPanel_URLEncodedAndBinaryBodies(t *testing.T) {
+	keyRunes := func(panel *RequestPanel, s string) *RequestPanel {
+		updated, _ := panel.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)})
+		return updated.(*RequestPanel)
+	}
+	keyType := func(panel *RequestPanel, k tea.KeyType) *RequestPanel {
+		updated, _ := panel.Update(tea.KeyMsg{Type: k})
+		return updated.(*RequestPanel)
+	}
+
+	t.Run("cycles through all body types", func(t *testing.T) {
+		panel := NewRequestPanel()
+		req := core.NewRequestDefinition("Test", "POST", "https://example.com")
+		panel.SetRequest(req)
+		panel.Focus()
+		panel.SetSize(100, 40)
+		panel.SetActiveTab(TabBody)
+
+		expected := []string{"json", "form", "urlencoded", "binary", "raw"}
+		for _, bodyType := range expected {
+			panel = keyRunes(panel, "t")
+			assert.Equal(t, bodyType, req.BodyType())
+		}
+	})
+
+	t.Run("urlencoded fields sync to request", func(t *testing.T) {
+		panel := NewRequestPanel()
+		req := core.NewRequestDefinition("Test", "POST", "https://example.com")
+		req.SetBodyURLEncoded(nil)
+		panel.SetRequest(req)
+		panel.Focus()
+		panel.SetSize(100, 40)
+		panel.SetActiveTab(TabBody)
+		assert.Equal(t, 3, panel.bodyTypeIndex)
+		assert.Contains(t, panel.View(), "URL-encoded")
+
+		panel = keyRunes(panel, "a")
+		panel = keyRunes(panel, "user")
+		panel = keyType(panel, tea.KeyTab)
+		panel = keyRunes(panel, "john")
+		panel = keyType(panel, tea.KeyEnter)
+
+		assert.Equal(t, "urlencoded", req.BodyType())
+		assert.Equal(t, []core.FormField{{Key: "user", Value: "john"}}, req.FormFields())
+
+		// File fields are multipart-only
+		panel = keyRunes(panel, "f")
+		assert.False(t, panel.editingFormField)
+	})
+
+	t.Run("binary body edits file path", func(t *testing.T) {
+		panel := NewRequestPanel()
+		req := core.NewRequestDefinition("Test", "PUT", "https://example.com")
+		req.SetBodyBinary("", "")
+		panel.SetRequest(req)
+		panel.Focus()
+		panel.SetSize(100, 40)
+		panel.SetActiveTab(TabBody)
+		assert.Equal(t, 4, panel.bodyTypeIndex)
+
+		panel = keyRunes(panel, "e")
+		panel = keyRunes(panel, "/tmp/data.bin")
+		panel = keyType(panel, tea.KeyEsc)
+
+		assert.Equal(t, "/tmp/data.bin", req.BodyFile())
+		assert.Contains(t, panel.View(), "/tmp/data.bin")
+	})
+
+	t.Run("raw content type editor", func(t *testing.T) {
+		panel := NewRequestPanel()
+		req := core.NewRequestDefinition("Test", "POST", "https://example.com")
+		req.SetBodyRaw("<a/>", "")
+		panel.SetRequest(req)
+		panel.Focus()
+		panel.SetSize(100, 40)
+		panel.SetActiveTab(TabBody)
+		assert.Contains(t, panel.View(), "text/plain")
+
+		panel = keyRunes(panel, "c")
+		assert.True(t, panel.IsEditing())
+		assert.Equal(t, "content_type", panel.EditingField())
+
+		// Tab cycles presets
+		panel = keyType(panel, tea.KeyTab)
+		assert.Equal(t, "text/plain", panel.contentTypeInput)
+		panel = keyType(panel, tea.KeyTab)
+		assert.Equal(t, "application/json", panel.contentTypeInput)
+
+		// Free text
+		panel = keyType(panel, tea.KeyCtrlU)
+		panel = keyRunes(panel, "application/xml")
+		panel = keyType(panel, tea.KeyEnter)
+
+		assert.False(t, panel.IsEditing())
+		assert.Equal(t, "application/xml", req.BodyContentType())
+		assert.Contains(t, panel.View(), "application/xml")
+	})
+}
+
+func TestRequestPanel_VariableSources(t *testing.T) {
+	engine := interpolate.NewEngine()
+	engine.Scope().SetEnvironment(interpolate.NewVariableSetFrom(map[string]string{"host": "env.example.com", "token": "env-token"}))
+	engine.Scope().SetCollection(interpolate.NewVariableSetFrom(map[string]string{"token": "collection-token"}))
+
+	req := core.NewRequestDefinition("Scoped", "GET", "https://{{host}}/{{missing}}")
+	req.SetHeader("Authorization", "Bearer {{token}}")
+
+	panel := NewRequestPanel()
+	panel.SetSize(100, 30)
+	panel.SetRequest(req)
+	panel.SetVariableResolver(func(*core.RequestDefinition) *interpolate.Engine { return engine })
+
+	t.Run("URL tab shows where variables resolve from", func(t *testing.T) {
+		view := panel.View()
+		assert.Contains(t, view, "{{host}} environment env.example.com")
+		assert.Contains(t, view, "{{missing}} undefined")
+	})
+
+	t.Run("Headers tab shows shadowed levels", func(t *testing.T) {
+		panel.SetActiveTab(TabHeaders)
+		view := panel.View()
+		assert.Contains(t, view, "{{token}} environment env-token (shadows collection)")
+	})
+
+	t.Run("nothing is shown without a resolver", func(t *testing.T) {
+		plain := NewRequestPanel()
+		plain.SetSize(100, 30)
+		plain.SetRequest(req)
+		assert.NotContains(t, plain.View(), "Variables:")
+	})
+}
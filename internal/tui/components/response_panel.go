@@ -58,6 +58,7 @@ type ResponsePanel struct {
 	scrollOffset    int
 	tabScrollOffset [6]int // Store scroll offset per tab
 	loading         bool
+	loadingText     string // Optional progress text shown while loading
 	err             error
 	consoleMessages []ConsoleMessage
 	gPressed        bool // For gg sequence
@@ -295,7 +296,11 @@ func (p *ResponsePanel) View() string {
 			Align(lipgloss.Center, lipgloss.Center).
 			Foreground(lipgloss.Color("214"))
 
-		content := loadingStyle.Render("Loading...")
+		text := "Loading..."
+		if p.loadingText != "" {
+			text = p.loadingText
+		}
+		content := loadingStyle.Render(text)
 		return p.wrapWithBorder(title + "\n" + content)
 	}
 
@@ -372,6 +377,17 @@ func (p *ResponsePanel) renderStatusLine() string {
 	// Size
 	sizeStr := p.formatSize(p.response.Body().Size())
 
+	// Streamed downloads show the on-disk size and where the body was saved
+	downloadBadge := ""
+	if path := p.response.DownloadPath(); path != "" {
+		sizeStr = p.formatSize(p.response.DownloadSize())
+		dlStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("39")) // Blue
+		downloadBadge = dlStyle.Render("  ⤓ " + path)
+		if p.response.IsBodyTruncated() {
+			downloadBadge += dlStyle.Render(" (preview)")
+		}
+	}
+
 	// Format indicator and pretty print status
 	formatBadge := ""
 	if p.detectedType != "" {
@@ -400,7 +416,7 @@ func (p *ResponsePanel) renderStatusLine() string {
 		}
 	}
 
-	return fmt.Sprintf("%s  %s  %s%s%s", statusStr, timeStr, sizeStr, formatBadge, testBadge)
+	return fmt.Sprintf("%s  %s  %s%s%s%s", statusStr, timeStr, sizeStr, formatBadge, testBadge, downloadBadge)
 }
 
 func (p *ResponsePanel) statusStyle(code int) lipgloss.Style {
@@ -943,6 +959,17 @@ func (p *ResponsePanel) IsLoading() bool {
 // SetLoading sets the loading state.
 func (p *ResponsePanel) SetLoading(loading bool) {
 	p.loading = loading
+	p.loadingText = ""
+}
+
+// SetLoadingText sets the progress text shown while loading.
+func (p *ResponsePanel) SetLoadingText(text string) {
+	p.loadingText = text
+}
+
+// LoadingText returns the progress text shown while loading.
+func (p *ResponsePanel) LoadingText() string {
+	return p.loadingText
 }
 
 // SetError sets an error to display.
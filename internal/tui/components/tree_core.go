@@ -68,6 +68,11 @@ func FilterItemsBySearch(items []TreeItem, search string) []TreeItem {
 // matchesSearch checks if an item matches the search query.
 // Searches name, and for requests: method, URL, body, and headers.
 func matchesSearch(item TreeItem, search string) bool {
+	// Section headers only group items
+	if item.Type == ItemSection {
+		return false
+	}
+
 	// Always search by name
 	if strings.Contains(strings.ToLower(item.Name), search) {
 		return true
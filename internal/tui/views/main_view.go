@@ -3,15 +3,22 @@ package views
 import (
 	"context"
 	"fmt"
+	"net/url"
 	"os"
+	"path"
+	"path/filepath"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/atotto/clipboard"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/artpar/currier/internal/assertion"
+	"github.com/artpar/currier/internal/bench"
 	"github.com/artpar/currier/internal/cookies"
 	"github.com/artpar/currier/internal/core"
 	"github.com/artpar/currier/internal/exporter"
@@ -24,6 +31,7 @@ import (
 	"github.com/artpar/currier/internal/proxy"
 	"github.com/artpar/currier/internal/runner"
 	"github.com/artpar/currier/internal/script"
+	"github.com/artpar/currier/internal/secrets"
 	"github.com/artpar/currier/internal/starred"
 	"github.com/artpar/currier/internal/storage/filesystem"
 	"github.com/artpar/currier/internal/tui"
@@ -68,6 +76,8 @@ type MainView struct {
 	notifyUntil  time.Time // When to clear notification
 	historyStore    history.Store             // Store for request history
 	collectionStore *filesystem.CollectionStore // Store for collection persistence
+	workspace       string                    // Name of the open project workspace
+	contracts       map[string]contractCache  // Parsed contracts by collection ID
 	lastRequest     *core.RequestDefinition   // Last sent request for history
 
 	// Environment switcher state
@@ -75,6 +85,13 @@ type MainView struct {
 	showEnvSwitcher  bool                          // Whether env switcher popup is visible
 	envList          []filesystem.EnvironmentMeta  // Available environments
 	envCursor        int                           // Current selection in env list
+	globals          []*core.Environment           // Global environments, layered beneath the active one
+
+	// Secrets passphrase prompt, shown once per session
+	showUnlockDialog bool
+	unlockInput      string
+	unlockError      string
+	unlockAsked      bool
 
 	// Environment editor state
 	showEnvEditor     bool              // Whether env editor popup is visible
@@ -116,6 +133,30 @@ type MainView struct {
 	runnerSummary     *runner.RunSummary
 	runnerCurrentReq  string
 	runnerCancelFunc  context.CancelFunc
+	runnerSetup       bool                // Showing the data file / iterations step
+	runnerSetupField  int                 // 0=data file, 1=iterations
+	runnerDataInput   string              // Data file path (kept between runs)
+	runnerIterInput   string              // Iterations (empty = one per row)
+	runnerData        []map[string]string // Rows loaded from the data file
+	runnerIterations  int
+
+	// Load test (bench) state
+	showBenchModal   bool
+	benchSetup       bool                    // Showing the options step
+	benchField       int                     // 0=concurrency, 1=rate, 2=duration
+	benchConcInput   string                  // Kept between runs
+	benchRateInput   string                  // Requests per second, empty = unlimited
+	benchDurInput    string
+	benchRequest     *core.RequestDefinition // Request being load tested
+	benchRunner      *bench.Bench            // Running benchmark, nil when idle
+	benchCancel      context.CancelFunc
+	benchReport      *bench.Report
+
+	// Streaming download state
+	downloadDir    string           // Directory for responses saved with Ctrl+O
+	previewLimit   int64            // Max body bytes kept in memory for downloads
+	download       *downloadTracker // In-flight download, nil when idle
+	downloadCancel context.CancelFunc
 
 	// Capture proxy server
 	captureProxy       *proxy.Server
@@ -152,6 +193,24 @@ type runnerCompleteMsg struct {
 	Summary *runner.RunSummary
 }
 
+// benchTickMsg is sent periodically to refresh load test progress.
+type benchTickMsg struct{}
+
+// benchCompleteMsg is sent when a load test finishes.
+type benchCompleteMsg struct {
+	Report *bench.Report
+	Error  error
+}
+
+// downloadTickMsg is sent periodically to refresh download progress.
+type downloadTickMsg struct{}
+
+// downloadCompleteMsg is sent when a streaming download finishes or fails.
+type downloadCompleteMsg struct {
+	Response *core.Response
+	Error    error
+}
+
 // NewMainView creates a new main view.
 func NewMainView() *MainView {
 	view := &MainView{
@@ -164,6 +223,7 @@ func NewMainView() *MainView {
 		viewMode:     ViewModeHTTP,
 		interpolator: interpolate.NewEngine(), // Default engine with builtins
 	}
+	view.request.SetVariableResolver(view.requestEngine)
 	view.tree.Focus()
 	return view
 }
@@ -197,6 +257,14 @@ func (v *MainView) Update(msg tea.Msg) (tui.Component, tea.Cmd) {
 		return v, nil
 	}
 
+	// Handle secrets passphrase prompt
+	if v.showUnlockDialog {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			return v.handleUnlockDialogKey(keyMsg)
+		}
+		return v, nil
+	}
+
 	// Handle environment editor overlay
 	if v.showEnvEditor {
 		if keyMsg, ok := msg.(tea.KeyMsg); ok {
@@ -228,6 +296,13 @@ func (v *MainView) Update(msg tea.Msg) (tui.Component, tea.Cmd) {
 		}
 	}
 
+	// Handle load test modal
+	if v.showBenchModal {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			return v.handleBenchKey(keyMsg)
+		}
+	}
+
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		v.width = msg.Width
@@ -424,6 +499,7 @@ func (v *MainView) Update(msg tea.Msg) (tui.Component, tea.Cmd) {
 		if msg.Request != nil {
 			curlExporter := exporter.NewCurlExporter()
 			curlExporter.Pretty = false // Single line for clipboard
+			curlExporter.Redactor = v.secretRedactor()
 			ctx := context.Background()
 			curlBytes, err := curlExporter.ExportRequest(ctx, msg.Request)
 			if err == nil {
@@ -448,6 +524,7 @@ func (v *MainView) Update(msg tea.Msg) (tui.Component, tea.Cmd) {
 		if len(msg.Requests) > 0 {
 			curlExporter := exporter.NewCurlExporter()
 			curlExporter.Pretty = true // Multi-line for readability
+			curlExporter.Redactor = v.secretRedactor()
 			ctx := context.Background()
 
 			var curlCommands []string
@@ -565,6 +642,7 @@ func (v *MainView) Update(msg tea.Msg) (tui.Component, tea.Cmd) {
 			if err != nil {
 				v.notification = "✗ Export failed"
 			} else {
+				data = []byte(v.secretRedactor().Redact(string(data)))
 				// Write to file in current directory
 				filename := sanitizeFilename(msg.Collection.Name()) + ".postman_collection.json"
 				err = writeFile(filename, data)
@@ -596,6 +674,11 @@ func (v *MainView) Update(msg tea.Msg) (tui.Component, tea.Cmd) {
 				if err != nil {
 					v.notification = fmt.Sprintf("✗ Import failed: %s", err.Error())
 				} else {
+					// An imported OpenAPI spec doubles as the collection's contract
+					if result.SourceFormat == importer.FormatOpenAPI {
+						result.Collection.SetContract(v.contractRef(result.Collection, msg.FilePath))
+					}
+
 					// Add to collections and save
 					collections := v.tree.Collections()
 					collections = append(collections, result.Collection)
@@ -616,6 +699,35 @@ func (v *MainView) Update(msg tea.Msg) (tui.Component, tea.Cmd) {
 			})
 		}
 
+	case components.AttachContractMsg:
+		if msg.Collection == nil {
+			return v, nil
+		}
+		if msg.Path == "" {
+			msg.Collection.SetContract("")
+			v.notification = fmt.Sprintf("Detached contract from %s", msg.Collection.Name())
+		} else if _, err := importer.LoadContract(msg.Path); err != nil {
+			v.notification = fmt.Sprintf("✗ Invalid contract: %s", err.Error())
+			v.notifyUntil = time.Now().Add(3 * time.Second)
+			return v, tea.Tick(3*time.Second, func(time.Time) tea.Msg {
+				return clearNotificationMsg{}
+			})
+		} else {
+			msg.Collection.SetContract(v.contractRef(msg.Collection, msg.Path))
+			v.notification = fmt.Sprintf("✓ Attached contract %s", filepath.Base(msg.Path))
+		}
+		if v.collectionStore != nil {
+			go func() {
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+				_ = v.collectionStore.Save(ctx, msg.Collection)
+			}()
+		}
+		v.notifyUntil = time.Now().Add(3 * time.Second)
+		return v, tea.Tick(3*time.Second, func(time.Time) tea.Msg {
+			return clearNotificationMsg{}
+		})
+
 	case components.ReorderRequestMsg:
 		// Persist collection after reorder
 		if v.collectionStore != nil && msg.Collection != nil {
@@ -767,7 +879,14 @@ func (v *MainView) Update(msg tea.Msg) (tui.Component, tea.Cmd) {
 			CAFile:       v.tlsCAFile,
 			InsecureSkip: v.tlsInsecureSkip,
 		}
-		return v, sendRequest(msg.Request, v.interpolator, httpConfig)
+		coll := v.tree.CollectionForRequest(msg.Request)
+		contract, err := v.collectionContract(coll)
+		if err != nil {
+			v.notification = fmt.Sprintf("✗ Contract not checked: %s", err.Error())
+			v.notifyUntil = time.Now().Add(3 * time.Second)
+		}
+		modules := script.NewModuleLoader(v.collectionDir(coll))
+		return v, sendRequest(msg.Request, v.requestEngine(msg.Request), httpConfig, contract, modules)
 
 	case components.ResponseReceivedMsg:
 		v.response.SetLoading(false)
@@ -795,6 +914,43 @@ func (v *MainView) Update(msg tea.Msg) (tui.Component, tea.Cmd) {
 		}
 		return v, nil
 
+	case benchTickMsg:
+		if v.benchRunner == nil {
+			return v, nil
+		}
+		return v, tea.Tick(250*time.Millisecond, func(time.Time) tea.Msg {
+			return benchTickMsg{}
+		})
+
+	case benchCompleteMsg:
+		v.benchRunner = nil
+		if v.benchCancel != nil {
+			v.benchCancel()
+			v.benchCancel = nil
+		}
+		if msg.Error != nil {
+			v.showBenchModal = false
+			v.notification = "Load test failed: " + msg.Error.Error()
+			v.notifyUntil = time.Now().Add(3 * time.Second)
+			return v, tea.Tick(3*time.Second, func(time.Time) tea.Msg {
+				return clearNotificationMsg{}
+			})
+		}
+		v.benchReport = msg.Report
+		return v, nil
+
+	case downloadTickMsg:
+		if v.download == nil {
+			return v, nil
+		}
+		v.response.SetLoadingText(formatDownloadProgress(v.download.Progress()))
+		return v, tea.Tick(200*time.Millisecond, func(time.Time) tea.Msg {
+			return downloadTickMsg{}
+		})
+
+	case downloadCompleteMsg:
+		return v.handleDownloadComplete(msg)
+
 	case components.CopyMsg:
 		return v.handleCopy(msg.Content)
 
@@ -808,6 +964,7 @@ func (v *MainView) Update(msg tea.Msg) (tui.Component, tea.Cmd) {
 	case environmentSwitchedMsg:
 		v.environment = msg.Environment
 		v.interpolator = msg.Engine
+		v.applyGlobals()
 		v.notification = fmt.Sprintf("Switched to '%s'", msg.Environment.Name())
 		v.notifyUntil = time.Now().Add(2 * time.Second)
 		return v, tea.Tick(2*time.Second, func(time.Time) tea.Msg {
@@ -983,7 +1140,7 @@ func (v *MainView) handleSaveToCollection() (tui.Component, tea.Cmd) {
 func (v *MainView) handleKeyMsg(msg tea.KeyMsg) (tui.Component, tea.Cmd) {
 	// Check if we're in INSERT mode (editing text in any pane)
 	// In INSERT mode, forward ALL keys to the focused pane except Ctrl+C
-	isEditing := v.request.IsEditing() || v.tree.IsSearching() || v.tree.IsRenaming() || v.tree.IsMoving()
+	isEditing := v.request.IsEditing() || v.tree.IsSearching() || v.tree.IsRenaming() || v.tree.IsMoving() || v.tree.IsAttachingContract()
 
 	// Ctrl+C always quits
 	if msg.Type == tea.KeyCtrlC {
@@ -1009,9 +1166,16 @@ func (v *MainView) handleKeyMsg(msg tea.KeyMsg) (tui.Component, tea.Cmd) {
 		return v, nil
 
 	case tea.KeyEsc:
-		// Already in normal mode, nothing to do
+		// Cancel an in-flight download, otherwise already in normal mode
+		if v.download != nil && v.downloadCancel != nil {
+			v.downloadCancel()
+		}
 		return v, nil
 
+	case tea.KeyCtrlO:
+		// Stream the current request's response to a file
+		return v.startDownload()
+
 	case tea.KeyCtrlK:
 		// Clear all cookies
 		if v.cookieJar != nil {
@@ -1101,7 +1265,12 @@ func (v *MainView) handleKeyMsg(msg tea.KeyMsg) (tui.Component, tea.Cmd) {
 
 	// Handle Ctrl+R to run collection
 	if msg.Type == tea.KeyCtrlR {
-		return v.startCollectionRunner()
+		return v.openRunnerSetup()
+	}
+
+	// Handle Ctrl+B to load test the current request
+	if msg.Type == tea.KeyCtrlB {
+		return v.openBenchSetup()
 	}
 
 	// Forward to focused pane for other keys
@@ -1237,6 +1406,11 @@ func (v *MainView) View() string {
 		return v.renderEnvSwitcher()
 	}
 
+	// Render secrets passphrase prompt if showing
+	if v.showUnlockDialog {
+		return v.renderUnlockDialog()
+	}
+
 	// Render environment editor overlay if showing
 	if v.showEnvEditor {
 		return v.renderEnvEditor()
@@ -1257,6 +1431,11 @@ func (v *MainView) View() string {
 		return v.renderRunnerModal()
 	}
 
+	// Render load test modal if showing
+	if v.showBenchModal {
+		return v.renderBenchModal()
+	}
+
 	// Render sidebar (Collections)
 	sidebar := v.tree.View()
 
@@ -1389,7 +1568,7 @@ func (v *MainView) renderStatusBar() string {
 	modeStyle := lipgloss.NewStyle().
 		Bold(true).
 		Padding(0, 1)
-	isEditing := v.request.IsEditing() || v.tree.IsSearching() || v.tree.IsRenaming() || v.tree.IsMoving()
+	isEditing := v.request.IsEditing() || v.tree.IsSearching() || v.tree.IsRenaming() || v.tree.IsMoving() || v.tree.IsAttachingContract()
 	if isEditing {
 		modeStyle = modeStyle.
 			Background(lipgloss.Color("214")).
@@ -1659,8 +1838,13 @@ func (v *MainView) getHelpContent() []string {
 			"IMPORT/EXPORT",
 			"   I          Import (Postman/OpenAPI/cURL/HAR)",
 			"   E          Export collection",
+			"   O          Attach OpenAPI contract (validates responses)",
 			"   c          Copy request as cURL command",
 			"",
+			"RUNNING",
+			"   Ctrl+R     Run collection (optional CSV/JSON data file",
+			"              and iteration count for data-driven runs)",
+			"",
 			"VIEW MODES",
 			"   H          Switch to History view",
 			"   C          Switch to Capture view",
@@ -1690,12 +1874,15 @@ func (v *MainView) getHelpContent() []string {
 			"   Tab        Switch between key and value",
 			"",
 			"BODY",
-			"   e          Edit body content",
-			"   Body types: JSON, Form, Raw, File",
+			"   e          Edit body content (file path for Binary)",
+			"   t          Cycle body type (Raw/JSON/Form-data/URL-encoded/Binary)",
+			"   c          Set content type (Raw/Binary, Tab cycles presets)",
 			"",
 			"SENDING",
 			"   Enter      Send request",
 			"   Alt+Enter  Send request (works everywhere)",
+			"   Ctrl+O     Stream response to a file (Esc cancels)",
+			"   Ctrl+B     Load test request (concurrency, rate, duration)",
 		}
 	case 4: // Response
 		return []string{
@@ -1932,10 +2119,13 @@ func (v *MainView) renderEnvSwitcher() string {
 			prefix = "→ "
 		}
 
-		// Active indicator
+		// Active and global indicators
 		active := ""
+		if env.IsGlobal {
+			active = " (global)"
+		}
 		if env.IsActive {
-			active = " ●"
+			active += " ●"
 		}
 
 		name := env.Name
@@ -1974,6 +2164,17 @@ func (v *MainView) renderEnvSwitcher() string {
 		Bold(true).
 		Foreground(lipgloss.Color("229")).
 		Underline(true)
+
+	// Inheritance chain, such as "staging → base → shared"
+	if v.envCursor >= 0 && v.envCursor < len(v.envList) && len(v.envList[v.envCursor].Chain) > 0 {
+		selectedEnv := v.envList[v.envCursor]
+		chain := strings.Join(append([]string{selectedEnv.Name}, selectedEnv.Chain...), " → ")
+		if runes := []rune(chain); len(runes) > rightWidth-2 {
+			chain = string(runes[:rightWidth-5]) + "..."
+		}
+		rightLines = append(rightLines, varHeaderStyle.Render("Extends:"))
+		rightLines = append(rightLines, lipgloss.NewStyle().Foreground(lipgloss.Color("180")).Render("  "+chain))
+	}
 	rightLines = append(rightLines, varHeaderStyle.Render("Variables:"))
 
 	if v.envCursor >= 0 && v.envCursor < len(v.envList) {
@@ -2038,7 +2239,7 @@ func (v *MainView) renderEnvSwitcher() string {
 		Foreground(lipgloss.Color("243")).
 		Width(boxWidth - 4).
 		Align(lipgloss.Center)
-	contentLines = append(contentLines, footerStyle.Render("j/k: navigate  Enter: select  e: edit  Esc: cancel"))
+	contentLines = append(contentLines, footerStyle.Render("j/k: navigate  Enter: select  e: edit  g: global  Esc: cancel"))
 
 	content := strings.Join(contentLines, "\n")
 
@@ -2059,6 +2260,73 @@ func (v *MainView) renderEnvSwitcher() string {
 	return containerStyle.Render(box)
 }
 
+// renderUnlockDialog renders the secrets passphrase prompt.
+func (v *MainView) renderUnlockDialog() string {
+	boxWidth := 60
+	if boxWidth > v.width-4 {
+		boxWidth = v.width - 4
+	}
+
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("229")).
+		Background(lipgloss.Color("62")).
+		Width(boxWidth - 4).
+		Align(lipgloss.Center).
+		Padding(0, 1)
+
+	labelStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("252"))
+
+	inputStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("229")).
+		Background(lipgloss.Color("236")).
+		Width(boxWidth - 8).
+		Padding(0, 1)
+
+	var lines []string
+	if v.environmentStore != nil && v.environmentStore.Locked(context.Background()) {
+		lines = append(lines, headerStyle.Render("Unlock Secrets"))
+		lines = append(lines, "")
+		lines = append(lines, labelStyle.Render("Passphrase for encrypted environment secrets:"))
+	} else {
+		lines = append(lines, headerStyle.Render("Encrypt Secrets"))
+		lines = append(lines, "")
+		lines = append(lines, labelStyle.Render("Choose a passphrase to encrypt environment secrets:"))
+	}
+	lines = append(lines, inputStyle.Render(strings.Repeat("•", len([]rune(v.unlockInput)))+"█"))
+	lines = append(lines, "")
+
+	if v.unlockError != "" {
+		errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+		lines = append(lines, errorStyle.Render(v.unlockError))
+	} else {
+		hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("243"))
+		lines = append(lines, hintStyle.Render("Set "+secrets.PassphraseEnv+" to skip this prompt"))
+	}
+	lines = append(lines, "")
+
+	footerStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("243")).
+		Width(boxWidth - 4).
+		Align(lipgloss.Center)
+	lines = append(lines, footerStyle.Render("Enter: unlock  Esc: continue without secrets"))
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("62")).
+		Padding(0, 1)
+
+	box := boxStyle.Render(strings.Join(lines, "\n"))
+
+	containerStyle := lipgloss.NewStyle().
+		Width(v.width).
+		Height(v.height).
+		Align(lipgloss.Center, lipgloss.Center)
+
+	return containerStyle.Render(box)
+}
+
 // renderProxyDialog renders the proxy settings dialog.
 func (v *MainView) renderProxyDialog() string {
 	boxWidth := 60
@@ -2306,6 +2574,30 @@ func (v *MainView) SetCollections(collections []*core.Collection) {
 func (v *MainView) SetEnvironment(env *core.Environment, engine *interpolate.Engine) {
 	v.environment = env
 	v.interpolator = engine
+	v.applyGlobals()
+}
+
+// loadGlobals reloads the global environments from the store.
+func (v *MainView) loadGlobals() {
+	if v.environmentStore == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	globals, err := v.environmentStore.Globals(ctx)
+	if err != nil {
+		return // Keep the globals already loaded
+	}
+	v.globals = globals
+	v.applyGlobals()
+}
+
+// applyGlobals layers the global environments beneath the active one.
+func (v *MainView) applyGlobals() {
+	if v.interpolator != nil {
+		v.interpolator.Scope().SetGlobal(interpolate.NewVariableSetFrom(core.MergeGlobals(v.globals)))
+	}
 }
 
 // SetHistoryStore sets the history store for browsing request history.
@@ -2326,22 +2618,37 @@ func (v *MainView) SetCollectionStore(store *filesystem.CollectionStore) {
 		metas, err := store.List(ctx)
 		if err == nil {
 			var collections []*core.Collection
+			var personal []string
 			for _, meta := range metas {
 				coll, err := store.Get(ctx, meta.ID)
 				if err == nil {
 					collections = append(collections, coll)
+					if !meta.Workspace {
+						personal = append(personal, meta.ID)
+					}
 				}
 			}
 			if len(collections) > 0 {
 				v.tree.SetCollections(collections)
 			}
+			if v.workspace != "" {
+				v.tree.SetWorkspace(v.workspace, personal)
+			}
 		}
 	}
 }
 
-// SetEnvironmentStore sets the environment store for switching environments.
+// SetWorkspace sets the name of the project workspace whose collections are
+// shown apart from the user's own. Call it before SetCollectionStore.
+func (v *MainView) SetWorkspace(name string) {
+	v.workspace = name
+}
+
+// SetEnvironmentStore sets the environment store for switching environments
+// and loads the global environments from it.
 func (v *MainView) SetEnvironmentStore(store *filesystem.EnvironmentStore) {
 	v.environmentStore = store
+	v.loadGlobals()
 }
 
 // SetCookieJar sets the cookie jar for automatic cookie handling.
@@ -2349,6 +2656,21 @@ func (v *MainView) SetCookieJar(jar *cookies.PersistentJar) {
 	v.cookieJar = jar
 }
 
+// SetDownloadDir sets the directory responses are streamed to with Ctrl+O.
+func (v *MainView) SetDownloadDir(dir string) {
+	v.downloadDir = dir
+}
+
+// SetPreviewLimit sets how many bytes of a streamed download are kept in memory.
+func (v *MainView) SetPreviewLimit(limit int64) {
+	v.previewLimit = limit
+}
+
+// IsDownloading returns true while a streaming download is in flight.
+func (v *MainView) IsDownloading() bool {
+	return v.download != nil
+}
+
 // SetStarredStore sets the starred store for favorite requests.
 func (v *MainView) SetStarredStore(store starred.Store) {
 	v.starredStore = store
@@ -2374,6 +2696,16 @@ func (v *MainView) openEnvSwitcher() (tui.Component, tea.Cmd) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
+	// Ask for the secrets passphrase once, to unlock encrypted secrets or
+	// to start encrypting plaintext ones
+	if !v.unlockAsked && !v.environmentStore.HasKey() && v.environmentStore.HasSecrets(ctx) {
+		v.unlockAsked = true
+		v.showUnlockDialog = true
+		v.unlockInput = ""
+		v.unlockError = ""
+		return v, nil
+	}
+
 	envList, err := v.environmentStore.List(ctx)
 	if err != nil {
 		v.notification = "Failed to load environments"
@@ -2406,6 +2738,60 @@ func (v *MainView) openEnvSwitcher() (tui.Component, tea.Cmd) {
 	return v, nil
 }
 
+// handleUnlockDialogKey handles keyboard input for the secrets passphrase prompt.
+func (v *MainView) handleUnlockDialogKey(msg tea.KeyMsg) (tui.Component, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		// Continue without secrets
+		v.showUnlockDialog = false
+		v.unlockInput = ""
+		return v.openEnvSwitcher()
+
+	case tea.KeyEnter:
+		if v.unlockInput == "" {
+			return v, nil
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		locked := v.environmentStore.Locked(ctx)
+		if err := v.environmentStore.Unlock(ctx, secrets.NewPassphraseKey(v.unlockInput)); err != nil {
+			v.unlockError = err.Error()
+			v.unlockInput = ""
+			return v, nil
+		}
+		v.showUnlockDialog = false
+		v.unlockInput = ""
+		v.notification = "Secrets unlocked"
+		if !locked {
+			if _, err := v.environmentStore.EncryptAll(ctx); err != nil {
+				v.notification = "Failed to encrypt secrets: " + err.Error()
+			} else {
+				v.notification = "Secrets encrypted"
+			}
+		}
+		v.notifyUntil = time.Now().Add(2 * time.Second)
+		_, cmd := v.openEnvSwitcher()
+		return v, tea.Batch(cmd, tea.Tick(2*time.Second, func(time.Time) tea.Msg {
+			return clearNotificationMsg{}
+		}))
+
+	case tea.KeyBackspace:
+		if len(v.unlockInput) > 0 {
+			runes := []rune(v.unlockInput)
+			v.unlockInput = string(runes[:len(runes)-1])
+		}
+
+	case tea.KeySpace:
+		v.unlockInput += " "
+
+	case tea.KeyRunes:
+		v.unlockInput += string(msg.Runes)
+	}
+
+	return v, nil
+}
+
 // handleEnvSwitcherKey handles keyboard input when the environment switcher is open.
 func (v *MainView) handleEnvSwitcherKey(msg tea.KeyMsg) (tui.Component, tea.Cmd) {
 	switch msg.Type {
@@ -2431,12 +2817,47 @@ func (v *MainView) handleEnvSwitcherKey(msg tea.KeyMsg) (tui.Component, tea.Cmd)
 		case "e":
 			// Open environment editor for selected environment
 			return v.openEnvEditor()
+		case "g":
+			return v.toggleGlobalEnvironment()
 		}
 	}
 
 	return v, nil
 }
 
+// toggleGlobalEnvironment makes the highlighted environment global, so its
+// values apply beneath whichever environment is selected, or makes it
+// ordinary again.
+func (v *MainView) toggleGlobalEnvironment() (tui.Component, tea.Cmd) {
+	if v.environmentStore == nil || v.envCursor < 0 || v.envCursor >= len(v.envList) {
+		return v, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	env, err := v.environmentStore.Get(ctx, v.envList[v.envCursor].ID)
+	if err == nil {
+		env.SetGlobal(!env.IsGlobal())
+		err = v.environmentStore.Save(ctx, env)
+	}
+	if err != nil {
+		v.notification = "Failed to update environment: " + err.Error()
+	} else if env.IsGlobal() {
+		v.notification = fmt.Sprintf("'%s' is now global", env.Name())
+	} else {
+		v.notification = fmt.Sprintf("'%s' is no longer global", env.Name())
+	}
+	v.notifyUntil = time.Now().Add(2 * time.Second)
+
+	if envList, err := v.environmentStore.List(ctx); err == nil {
+		v.envList = envList
+	}
+	v.loadGlobals()
+	return v, tea.Tick(2*time.Second, func(time.Time) tea.Msg {
+		return clearNotificationMsg{}
+	})
+}
+
 // selectEnvironment selects the currently highlighted environment.
 func (v *MainView) selectEnvironment() (tui.Component, tea.Cmd) {
 	if v.envCursor < 0 || v.envCursor >= len(v.envList) {
@@ -2466,7 +2887,7 @@ func (v *MainView) selectEnvironment() (tui.Component, tea.Cmd) {
 
 		// Create new interpolation engine
 		engine := interpolate.NewEngine()
-		engine.SetVariables(env.ExportAll())
+		engine.Scope().SetEnvironment(interpolate.NewVariableSetFrom(env.ExportAll()))
 
 		return environmentSwitchedMsg{
 			Environment: env,
@@ -2729,9 +3150,11 @@ func (v *MainView) saveAndCloseEnvEditor() (tui.Component, tea.Cmd) {
 		if v.environment != nil && v.environment.ID() == v.editingEnv.ID() {
 			v.environment = v.editingEnv
 			if v.interpolator != nil {
-				v.interpolator.SetVariables(v.editingEnv.ExportAll())
+				v.interpolator.Scope().SetEnvironment(interpolate.NewVariableSetFrom(v.editingEnv.ExportAll()))
 			}
 		}
+		// The edited environment may be a global one
+		v.loadGlobals()
 	}
 
 	v.editingEnv = nil
@@ -3005,11 +3428,10 @@ func (v *MainView) handleTLSDialogKey(msg tea.KeyMsg) (tui.Component, tea.Cmd) {
 	return v, nil
 }
 
-// startCollectionRunner starts running the current collection.
-func (v *MainView) startCollectionRunner() (tui.Component, tea.Cmd) {
-	// Get the current collection
-	coll := v.tree.GetSelectedCollection()
-	if coll == nil {
+// openRunnerSetup opens the runner modal on the setup step, where a data file
+// and iteration count can be chosen before the run starts.
+func (v *MainView) openRunnerSetup() (tui.Component, tea.Cmd) {
+	if v.tree.GetSelectedCollection() == nil {
 		v.notification = "No collection selected"
 		v.notifyUntil = time.Now().Add(2 * time.Second)
 		return v, tea.Tick(2*time.Second, func(time.Time) tea.Msg {
@@ -3017,48 +3439,228 @@ func (v *MainView) startCollectionRunner() (tui.Component, tea.Cmd) {
 		})
 	}
 
-	// Initialize runner state
 	v.showRunnerModal = true
-	v.runnerRunning = true
-	v.runnerProgress = 0
-	v.runnerTotal = 0
+	v.runnerSetup = true
+	v.runnerSetupField = 0
+	v.runnerRunning = false
 	v.runnerSummary = nil
-	v.runnerCurrentReq = ""
-
-	// Create context with cancel
-	ctx, cancel := context.WithCancel(context.Background())
-	v.runnerCancelFunc = cancel
+	return v, nil
+}
 
-	// Start runner in background
-	return v, func() tea.Msg {
-		// Build runner options
-		opts := []runner.Option{}
+// handleRunnerSetupKey handles keyboard input for the runner setup step.
+func (v *MainView) handleRunnerSetupKey(msg tea.KeyMsg) (tui.Component, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		v.runnerSetup = false
+		v.showRunnerModal = false
+		return v, nil
 
-		if v.environment != nil {
-			opts = append(opts, runner.WithEnvironment(v.environment))
+	case tea.KeyEnter:
+		iterations := 0
+		if input := strings.TrimSpace(v.runnerIterInput); input != "" {
+			n, err := strconv.Atoi(input)
+			if err != nil || n < 1 {
+				return v, v.modalError("Iterations must be a positive number")
+			}
+			iterations = n
 		}
 
-		// Create HTTP client with proxy/TLS settings
-		clientOpts := []httpclient.Option{
-			httpclient.WithTimeout(30 * time.Second),
-		}
-		if v.cookieJar != nil {
-			clientOpts = append(clientOpts, httpclient.WithCookieJar(v.cookieJar))
-		}
-		if v.proxyURL != "" {
-			clientOpts = append(clientOpts, httpclient.WithProxy(v.proxyURL))
+		var rows []map[string]string
+		if path := strings.TrimSpace(v.runnerDataInput); path != "" {
+			var err error
+			rows, err = runner.LoadIterationData(path)
+			if err != nil {
+				return v, v.modalError(err.Error())
+			}
+			if len(rows) == 0 {
+				return v, v.modalError("Data file has no rows")
+			}
 		}
-		if v.tlsCertFile != "" && v.tlsKeyFile != "" {
-			clientOpts = append(clientOpts, httpclient.WithClientCert(v.tlsCertFile, v.tlsKeyFile))
+
+		v.runnerData = rows
+		v.runnerIterations = iterations
+		v.runnerSetup = false
+		return v.startCollectionRunner()
+
+	case tea.KeyTab, tea.KeyDown, tea.KeyShiftTab, tea.KeyUp:
+		v.runnerSetupField = 1 - v.runnerSetupField
+
+	case tea.KeyBackspace:
+		if v.runnerSetupField == 0 {
+			if len(v.runnerDataInput) > 0 {
+				v.runnerDataInput = v.runnerDataInput[:len(v.runnerDataInput)-1]
+			}
+		} else if len(v.runnerIterInput) > 0 {
+			v.runnerIterInput = v.runnerIterInput[:len(v.runnerIterInput)-1]
 		}
-		if v.tlsCAFile != "" {
-			clientOpts = append(clientOpts, httpclient.WithCACert(v.tlsCAFile))
+
+	case tea.KeyCtrlU:
+		if v.runnerSetupField == 0 {
+			v.runnerDataInput = ""
+		} else {
+			v.runnerIterInput = ""
 		}
-		if v.tlsInsecureSkip {
-			clientOpts = append(clientOpts, httpclient.WithInsecureSkipVerify())
+
+	case tea.KeySpace:
+		if v.runnerSetupField == 0 {
+			v.runnerDataInput += " "
 		}
-		httpClient := httpclient.NewClient(clientOpts...)
-		opts = append(opts, runner.WithHTTPClient(httpClient))
+
+	case tea.KeyRunes:
+		if v.runnerSetupField == 0 {
+			v.runnerDataInput += string(msg.Runes)
+		} else {
+			for _, r := range msg.Runes {
+				if r >= '0' && r <= '9' {
+					v.runnerIterInput += string(r)
+				}
+			}
+		}
+	}
+
+	return v, nil
+}
+
+// modalError shows an error notification while keeping a setup modal open.
+func (v *MainView) modalError(message string) tea.Cmd {
+	v.notification = message
+	v.notifyUntil = time.Now().Add(3 * time.Second)
+	return tea.Tick(3*time.Second, func(time.Time) tea.Msg {
+		return clearNotificationMsg{}
+	})
+}
+
+// contractCache is a collection's parsed contract and the file it was
+// parsed from.
+type contractCache struct {
+	path     string
+	modTime  time.Time
+	contract *importer.Contract
+}
+
+// collectionDir returns the directory the relative paths of coll, such as
+// its contract and script modules, resolve against, as `currier run`
+// resolves them against the collection file's directory. It is "" without a
+// collection store.
+func (v *MainView) collectionDir(coll *core.Collection) string {
+	if v.collectionStore == nil || coll == nil {
+		return ""
+	}
+	return v.collectionStore.CollectionDir(coll.ID())
+}
+
+// contractRef returns how coll refers to the contract at path: relative to
+// the collection, so the reference works in every checkout of a shared
+// collection.
+func (v *MainView) contractRef(coll *core.Collection, path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+	if dir := v.collectionDir(coll); dir != "" {
+		if rel, err := filepath.Rel(dir, abs); err == nil {
+			return filepath.ToSlash(rel)
+		}
+	}
+	return abs
+}
+
+// collectionContract returns the parsed contract attached to coll, or nil
+// if there is none. Contracts are parsed once and reloaded when their file
+// changes.
+func (v *MainView) collectionContract(coll *core.Collection) (*importer.Contract, error) {
+	if coll == nil || coll.Contract() == "" {
+		return nil, nil
+	}
+	path := filepath.FromSlash(coll.Contract())
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(v.collectionDir(coll), path)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read contract: %w", err)
+	}
+	if cached, ok := v.contracts[coll.ID()]; ok && cached.path == path && cached.modTime.Equal(info.ModTime()) {
+		return cached.contract, nil
+	}
+
+	contract, err := importer.LoadContract(path)
+	if err != nil {
+		return nil, err
+	}
+	if v.contracts == nil {
+		v.contracts = make(map[string]contractCache)
+	}
+	v.contracts[coll.ID()] = contractCache{path: path, modTime: info.ModTime(), contract: contract}
+	return contract, nil
+}
+
+// startCollectionRunner starts running the current collection.
+func (v *MainView) startCollectionRunner() (tui.Component, tea.Cmd) {
+	// Get the current collection
+	coll := v.tree.GetSelectedCollection()
+	if coll == nil {
+		v.notification = "No collection selected"
+		v.notifyUntil = time.Now().Add(2 * time.Second)
+		return v, tea.Tick(2*time.Second, func(time.Time) tea.Msg {
+			return clearNotificationMsg{}
+		})
+	}
+
+	// Initialize runner state
+	v.showRunnerModal = true
+	v.runnerSetup = false
+	v.runnerRunning = true
+	v.runnerProgress = 0
+	v.runnerTotal = 0
+	v.runnerSummary = nil
+	v.runnerCurrentReq = ""
+
+	// Create context with cancel
+	ctx, cancel := context.WithCancel(context.Background())
+	v.runnerCancelFunc = cancel
+	rows := v.runnerData
+	iterations := v.runnerIterations
+	contract, _ := v.collectionContract(coll)
+	moduleDir := v.collectionDir(coll)
+
+	// Start runner in background
+	return v, func() tea.Msg {
+		// Build runner options
+		opts := []runner.Option{
+			runner.WithIterations(iterations),
+			runner.WithIterationData(rows),
+			runner.WithModuleDir(moduleDir),
+		}
+
+		if v.environment != nil {
+			opts = append(opts, runner.WithEnvironment(v.environment))
+		}
+		if contract != nil {
+			opts = append(opts, runner.WithContract(contract))
+		}
+
+		// Create HTTP client with proxy/TLS settings
+		clientOpts := []httpclient.Option{
+			httpclient.WithTimeout(30 * time.Second),
+		}
+		if v.cookieJar != nil {
+			clientOpts = append(clientOpts, httpclient.WithCookieJar(v.cookieJar))
+		}
+		if v.proxyURL != "" {
+			clientOpts = append(clientOpts, httpclient.WithProxy(v.proxyURL))
+		}
+		if v.tlsCertFile != "" && v.tlsKeyFile != "" {
+			clientOpts = append(clientOpts, httpclient.WithClientCert(v.tlsCertFile, v.tlsKeyFile))
+		}
+		if v.tlsCAFile != "" {
+			clientOpts = append(clientOpts, httpclient.WithCACert(v.tlsCAFile))
+		}
+		if v.tlsInsecureSkip {
+			clientOpts = append(clientOpts, httpclient.WithInsecureSkipVerify())
+		}
+		httpClient := httpclient.NewClient(clientOpts...)
+		opts = append(opts, runner.WithHTTPClient(httpClient))
 
 		// Create runner
 		r := runner.NewRunner(coll, opts...)
@@ -3070,8 +3672,35 @@ func (v *MainView) startCollectionRunner() (tui.Component, tea.Cmd) {
 	}
 }
 
+// formatIterationData renders an iteration data row as sorted key=value pairs.
+func formatIterationData(data map[string]string) string {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, k+"="+data[k])
+	}
+	return strings.Join(parts, " ")
+}
+
+// iterationPrefix labels a result with its iteration in multi-iteration runs.
+func iterationPrefix(s *runner.RunSummary, r runner.RunResult) string {
+	if len(s.Iterations) > 1 {
+		return fmt.Sprintf("#%d ", r.Iteration+1)
+	}
+	return ""
+}
+
 // handleRunnerModalKey handles keyboard input for the runner modal.
 func (v *MainView) handleRunnerModalKey(msg tea.KeyMsg) (tui.Component, tea.Cmd) {
+	if v.runnerSetup {
+		return v.handleRunnerSetupKey(msg)
+	}
+
 	switch msg.Type {
 	case tea.KeyEsc:
 		if v.runnerRunning && v.runnerCancelFunc != nil {
@@ -3127,7 +3756,50 @@ func (v *MainView) renderRunnerModal() string {
 
 	var lines []string
 
-	if v.runnerRunning {
+	if v.runnerSetup {
+		selectedStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("229")).
+			Bold(true)
+
+		inputStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("252")).
+			Background(lipgloss.Color("236")).
+			Width(boxWidth - 8).
+			Padding(0, 1)
+
+		selectedInputStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("229")).
+			Background(lipgloss.Color("62")).
+			Width(boxWidth - 8).
+			Padding(0, 1)
+
+		lines = append(lines, headerStyle.Render("Run Collection"))
+		lines = append(lines, "")
+		if coll := v.tree.GetSelectedCollection(); coll != nil {
+			lines = append(lines, labelStyle.Render(fmt.Sprintf("Collection: %s", coll.Name())))
+			lines = append(lines, "")
+		}
+
+		fields := []struct {
+			label string
+			value string
+		}{
+			{"Data file (CSV or JSON, optional):", v.runnerDataInput},
+			{"Iterations (empty = one per data row):", v.runnerIterInput},
+		}
+		for i, field := range fields {
+			if v.runnerSetupField == i {
+				lines = append(lines, selectedStyle.Render("→ "+field.label))
+				lines = append(lines, selectedInputStyle.Render(field.value+"█"))
+			} else {
+				lines = append(lines, labelStyle.Render("  "+field.label))
+				lines = append(lines, inputStyle.Render(field.value))
+			}
+		}
+
+		lines = append(lines, "")
+		lines = append(lines, hintStyle.Render("Tab: next field  Ctrl+U: clear  Enter: run  Esc: cancel"))
+	} else if v.runnerRunning {
 		lines = append(lines, headerStyle.Render("Running Collection"))
 		lines = append(lines, "")
 
@@ -3168,6 +3840,12 @@ func (v *MainView) renderRunnerModal() string {
 		} else {
 			lines = append(lines, passedStyle.Render(requestLine))
 		}
+		if s.Skipped > 0 {
+			lines = append(lines, labelStyle.Render(fmt.Sprintf("Skipped: %d", s.Skipped)))
+		}
+		if s.Stopped {
+			lines = append(lines, hintStyle.Render("Run stopped by script"))
+		}
 
 		// Test stats
 		if s.TotalTests > 0 {
@@ -3179,13 +3857,43 @@ func (v *MainView) renderRunnerModal() string {
 			}
 		}
 
+		// Per-iteration stats for data-driven runs
+		if len(s.Iterations) > 1 {
+			lines = append(lines, "")
+			lines = append(lines, labelStyle.Render(fmt.Sprintf("Iterations: %d", len(s.Iterations))))
+			for _, it := range s.Iterations {
+				if len(lines) >= boxHeight-6 {
+					lines = append(lines, hintStyle.Render("  ..."))
+					break
+				}
+				iterLine := fmt.Sprintf("  #%d %d/%d requests", it.Index+1, it.Passed, it.Executed)
+				if it.TotalTests > 0 {
+					iterLine += fmt.Sprintf(", %d/%d tests", it.TestsPassed, it.TotalTests)
+				}
+				if it.Skipped > 0 {
+					iterLine += fmt.Sprintf(", %d skipped", it.Skipped)
+				}
+				if data := formatIterationData(it.Data); data != "" {
+					iterLine += "  " + data
+				}
+				if len(iterLine) > boxWidth-6 {
+					iterLine = iterLine[:boxWidth-9] + "..."
+				}
+				if it.IsSuccess() {
+					lines = append(lines, passedStyle.Render("✓"+iterLine))
+				} else {
+					lines = append(lines, failedStyle.Render("✗"+iterLine))
+				}
+			}
+		}
+
 		// Show failed requests
 		if s.Failed > 0 {
 			lines = append(lines, "")
 			lines = append(lines, failedStyle.Render("Failed Requests:"))
 			for _, r := range s.Results {
 				if r.Error != nil {
-					errLine := fmt.Sprintf("  ✗ %s %s", r.Method, r.RequestName)
+					errLine := fmt.Sprintf("  ✗ %s%s %s", iterationPrefix(s, r), r.Method, r.RequestName)
 					lines = append(lines, failedStyle.Render(errLine))
 					errDetail := fmt.Sprintf("    %s", r.Error.Error())
 					if len(errDetail) > boxWidth-6 {
@@ -3211,7 +3919,7 @@ func (v *MainView) renderRunnerModal() string {
 			for _, r := range s.Results {
 				for _, t := range r.TestResults {
 					if !t.Passed && len(lines) < boxHeight-2 {
-						testLine := fmt.Sprintf("  ✗ [%s] %s", r.RequestName, t.Name)
+						testLine := fmt.Sprintf("  ✗ %s[%s] %s", iterationPrefix(s, r), r.RequestName, t.Name)
 						if len(testLine) > boxWidth-4 {
 							testLine = testLine[:boxWidth-7] + "..."
 						}
@@ -3243,35 +3951,457 @@ func (v *MainView) renderRunnerModal() string {
 	return containerStyle.Render(box)
 }
 
+// openBenchSetup opens the load test modal for the current request, starting
+// on the options step.
+func (v *MainView) openBenchSetup() (tui.Component, tea.Cmd) {
+	reqDef := v.request.Request()
+	if reqDef == nil || reqDef.FullURL() == "" {
+		v.notification = "No request to load test"
+		v.notifyUntil = time.Now().Add(2 * time.Second)
+		return v, tea.Tick(2*time.Second, func(time.Time) tea.Msg {
+			return clearNotificationMsg{}
+		})
+	}
+	if v.benchRunner != nil {
+		v.showBenchModal = true
+		return v, nil
+	}
+
+	if v.benchConcInput == "" {
+		v.benchConcInput = "10"
+	}
+	if v.benchDurInput == "" {
+		v.benchDurInput = "10s"
+	}
+	v.showBenchModal = true
+	v.benchSetup = true
+	v.benchField = 0
+	v.benchRequest = reqDef
+	v.benchReport = nil
+	return v, nil
+}
+
+// benchInput returns the input buffer of a load test option field.
+func (v *MainView) benchInput(field int) *string {
+	switch field {
+	case 1:
+		return &v.benchRateInput
+	case 2:
+		return &v.benchDurInput
+	default:
+		return &v.benchConcInput
+	}
+}
+
+// handleBenchKey handles keyboard input for the load test modal.
+func (v *MainView) handleBenchKey(msg tea.KeyMsg) (tui.Component, tea.Cmd) {
+	if v.benchSetup {
+		return v.handleBenchSetupKey(msg)
+	}
+
+	switch msg.Type {
+	case tea.KeyEsc:
+		if v.benchRunner != nil && v.benchCancel != nil {
+			// Stop early; the report for the requests so far still arrives
+			v.benchCancel()
+			return v, nil
+		}
+		v.showBenchModal = false
+
+	case tea.KeyEnter:
+		if v.benchRunner == nil {
+			v.showBenchModal = false
+		}
+
+	case tea.KeyRunes:
+		if string(msg.Runes) == "e" && v.benchReport != nil {
+			return v.exportBenchReport()
+		}
+	}
+
+	return v, nil
+}
+
+// handleBenchSetupKey handles keyboard input for the load test options step.
+func (v *MainView) handleBenchSetupKey(msg tea.KeyMsg) (tui.Component, tea.Cmd) {
+	input := v.benchInput(v.benchField)
+
+	switch msg.Type {
+	case tea.KeyEsc:
+		v.benchSetup = false
+		v.showBenchModal = false
+
+	case tea.KeyEnter:
+		return v.startBench()
+
+	case tea.KeyTab, tea.KeyDown:
+		v.benchField = (v.benchField + 1) % 3
+
+	case tea.KeyShiftTab, tea.KeyUp:
+		v.benchField = (v.benchField + 2) % 3
+
+	case tea.KeyBackspace:
+		if len(*input) > 0 {
+			*input = (*input)[:len(*input)-1]
+		}
+
+	case tea.KeyCtrlU:
+		*input = ""
+
+	case tea.KeyRunes:
+		allowed := "0123456789"
+		switch v.benchField {
+		case 1:
+			allowed += "."
+		case 2:
+			allowed += ".smh"
+		}
+		for _, r := range msg.Runes {
+			if strings.ContainsRune(allowed, r) {
+				*input += string(r)
+			}
+		}
+	}
+
+	return v, nil
+}
+
+// startBench validates the load test options and starts the benchmark.
+func (v *MainView) startBench() (tui.Component, tea.Cmd) {
+	concurrency, err := strconv.Atoi(strings.TrimSpace(v.benchConcInput))
+	if err != nil || concurrency < 1 {
+		return v, v.modalError("Concurrency must be a positive number")
+	}
+
+	var rate float64
+	if input := strings.TrimSpace(v.benchRateInput); input != "" {
+		rate, err = strconv.ParseFloat(input, 64)
+		if err != nil || rate < 0 {
+			return v, v.modalError("Rate must be a number of requests per second")
+		}
+	}
+
+	durInput := strings.TrimSpace(v.benchDurInput)
+	duration, err := time.ParseDuration(durInput)
+	if seconds, numErr := strconv.ParseFloat(durInput, 64); numErr == nil {
+		// Plain numbers are seconds
+		duration, err = time.Duration(seconds*float64(time.Second)), nil
+	}
+	if err != nil || duration <= 0 {
+		return v, v.modalError("Duration must be like 30s or 2m")
+	}
+
+	opts := []bench.Option{
+		bench.WithEngine(v.requestEngine(v.benchRequest)),
+		bench.WithConcurrency(concurrency),
+		bench.WithRate(rate),
+		bench.WithDuration(duration),
+	}
+	// Load tests use their own connection pool and no cookie jar, so they
+	// don't disturb the session; proxy and TLS settings still apply.
+	if v.proxyURL != "" || v.tlsCertFile != "" || v.tlsCAFile != "" || v.tlsInsecureSkip {
+		opts = append(opts, bench.WithHTTPClient(newHTTPClient(HTTPClientConfig{
+			ProxyURL:     v.proxyURL,
+			CertFile:     v.tlsCertFile,
+			KeyFile:      v.tlsKeyFile,
+			CAFile:       v.tlsCAFile,
+			InsecureSkip: v.tlsInsecureSkip,
+		})))
+	}
+
+	b := bench.New([]*core.RequestDefinition{v.benchRequest}, opts...)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	v.benchSetup = false
+	v.benchRunner = b
+	v.benchCancel = cancel
+	v.benchReport = nil
+
+	return v, tea.Batch(
+		func() tea.Msg {
+			report, err := b.Run(ctx)
+			return benchCompleteMsg{Report: report, Error: err}
+		},
+		tea.Tick(250*time.Millisecond, func(time.Time) tea.Msg {
+			return benchTickMsg{}
+		}),
+	)
+}
+
+// exportBenchReport writes the last load test report as JSON to the
+// download directory.
+func (v *MainView) exportBenchReport() (tui.Component, tea.Cmd) {
+	dir := v.downloadDir
+	if dir == "" {
+		dir = defaultDownloadDir()
+	}
+	path := filepath.Join(dir, "bench-"+v.benchReport.StartTime.Format("20060102-150405")+".json")
+
+	err := func() error {
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		if err := v.benchReport.WriteJSON(f); err != nil {
+			f.Close()
+			return err
+		}
+		return f.Close()
+	}()
+	if err != nil {
+		v.notification = "Export failed: " + err.Error()
+	} else {
+		v.notification = "Saved load test report to " + path
+	}
+	v.notifyUntil = time.Now().Add(3 * time.Second)
+	return v, tea.Tick(3*time.Second, func(time.Time) tea.Msg {
+		return clearNotificationMsg{}
+	})
+}
+
+// renderBenchModal renders the load test modal.
+func (v *MainView) renderBenchModal() string {
+	boxWidth := 70
+	if boxWidth > v.width-4 {
+		boxWidth = v.width - 4
+	}
+
+	boxHeight := 30
+	if boxHeight > v.height-4 {
+		boxHeight = v.height - 4
+	}
+
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("212")).
+		Width(boxWidth - 4).
+		Align(lipgloss.Center)
+
+	labelStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("252"))
+
+	passedStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("42"))
+
+	failedStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("196"))
+
+	hintStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("238"))
+
+	title := "Load Test"
+	switch {
+	case v.benchSetup:
+	case v.benchRunner != nil:
+		title = "Load Testing"
+	case v.benchReport != nil:
+		title = "Load Test Complete"
+	}
+
+	lines := []string{headerStyle.Render(title)}
+	if v.benchRequest != nil {
+		target := fmt.Sprintf("%s %s", v.benchRequest.Method(), v.benchRequest.FullURL())
+		if len(target) > boxWidth-8 {
+			target = target[:boxWidth-11] + "..."
+		}
+		lines = append(lines, "", labelStyle.Render(target))
+	}
+
+	switch {
+	case v.benchSetup:
+		selectedStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("229")).
+			Bold(true)
+
+		inputStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("252")).
+			Background(lipgloss.Color("236")).
+			Width(boxWidth - 8).
+			Padding(0, 1)
+
+		selectedInputStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("229")).
+			Background(lipgloss.Color("62")).
+			Width(boxWidth - 8).
+			Padding(0, 1)
+
+		lines = append(lines, "")
+
+		labels := []string{
+			"Concurrency (requests in flight):",
+			"Rate (requests/second, empty = unlimited):",
+			"Duration (e.g. 30s, 2m):",
+		}
+		for i, label := range labels {
+			value := *v.benchInput(i)
+			if v.benchField == i {
+				lines = append(lines, selectedStyle.Render("→ "+label))
+				lines = append(lines, selectedInputStyle.Render(value+"█"))
+			} else {
+				lines = append(lines, labelStyle.Render("  "+label))
+				lines = append(lines, inputStyle.Render(value))
+			}
+		}
+
+		lines = append(lines, "")
+		lines = append(lines, hintStyle.Render("Tab: next field  Ctrl+U: clear  Enter: start  Esc: cancel"))
+
+	case v.benchRunner != nil:
+		s := v.benchRunner.Snapshot()
+		lines = append(lines, "")
+
+		if s.Duration > 0 {
+			lines = append(lines, labelStyle.Render(fmt.Sprintf("Elapsed: %s / %s",
+				s.Elapsed.Round(100*time.Millisecond), s.Duration)))
+			barWidth := boxWidth - 10
+			filled := int(float64(barWidth) * s.Elapsed.Seconds() / s.Duration.Seconds())
+			if filled > barWidth {
+				filled = barWidth
+			}
+			bar := strings.Repeat("█", filled) + strings.Repeat("░", barWidth-filled)
+			lines = append(lines, labelStyle.Render("  "+bar))
+		}
+		lines = append(lines, "")
+		lines = append(lines, labelStyle.Render(fmt.Sprintf("Requests: %d (%.1f req/s)", s.Requests, s.Throughput())))
+		errLine := fmt.Sprintf("Errors: %d", s.Failed)
+		if s.Failed > 0 {
+			lines = append(lines, failedStyle.Render(errLine))
+		} else {
+			lines = append(lines, passedStyle.Render(errLine))
+		}
+
+		lines = append(lines, "")
+		lines = append(lines, hintStyle.Render("Press Esc to stop"))
+
+	case v.benchReport != nil:
+		r := v.benchReport
+		lines = append(lines, "")
+
+		lines = append(lines, labelStyle.Render(fmt.Sprintf("Duration: %s  Workers: %d",
+			r.Duration.Round(time.Millisecond), r.Concurrency)))
+		lines = append(lines, labelStyle.Render(fmt.Sprintf("Requests: %d (%.1f req/s)", r.Requests, r.Throughput)))
+		failLine := fmt.Sprintf("Failed: %d (%.1f%%)", r.Failed, 100*r.ErrorRate())
+		if r.Failed > 0 {
+			lines = append(lines, failedStyle.Render(failLine))
+		} else {
+			lines = append(lines, passedStyle.Render(failLine))
+		}
+
+		l := r.Latency
+		lines = append(lines, "")
+		lines = append(lines, labelStyle.Render(fmt.Sprintf("Latency  p50 %s  p90 %s  p99 %s",
+			formatBenchLatency(l.P50), formatBenchLatency(l.P90), formatBenchLatency(l.P99))))
+		lines = append(lines, hintStyle.Render(fmt.Sprintf("         min %s  mean %s  max %s",
+			formatBenchLatency(l.Min), formatBenchLatency(l.Mean), formatBenchLatency(l.Max))))
+
+		// Status and error breakdown
+		if len(r.StatusCodes) > 0 || len(r.Errors) > 0 {
+			var parts []string
+			codes := make([]int, 0, len(r.StatusCodes))
+			for code := range r.StatusCodes {
+				codes = append(codes, code)
+			}
+			sort.Ints(codes)
+			for _, code := range codes {
+				parts = append(parts, fmt.Sprintf("%d×%d", code, r.StatusCodes[code]))
+			}
+			classes := make([]string, 0, len(r.Errors))
+			for class := range r.Errors {
+				classes = append(classes, class)
+			}
+			sort.Strings(classes)
+			for _, class := range classes {
+				parts = append(parts, fmt.Sprintf("%s×%d", class, r.Errors[class]))
+			}
+			lines = append(lines, "")
+			lines = append(lines, labelStyle.Render("Responses: "+strings.Join(parts, "  ")))
+		}
+
+		// Throughput histogram
+		if len(r.Timeline) > 0 {
+			lines = append(lines, "")
+			lines = append(lines, labelStyle.Render("Throughput (req/s):"))
+			for _, line := range r.Histogram(boxWidth - 30) {
+				if len(lines) >= boxHeight-4 {
+					lines = append(lines, hintStyle.Render("  ..."))
+					break
+				}
+				lines = append(lines, hintStyle.Render("  "+line))
+			}
+		}
+
+		lines = append(lines, "")
+		lines = append(lines, hintStyle.Render("e: export JSON  Enter/Esc: close"))
+	}
+
+	// Build the box
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("240")).
+		Padding(1, 2).
+		Width(boxWidth)
+
+	box := boxStyle.Render(strings.Join(lines, "\n"))
+
+	// Center the box
+	containerStyle := lipgloss.NewStyle().
+		Width(v.width).
+		Height(v.height).
+		Align(lipgloss.Center, lipgloss.Center)
+
+	return containerStyle.Render(box)
+}
+
+// formatBenchLatency formats a latency with sub-millisecond precision.
+func formatBenchLatency(d time.Duration) string {
+	if d < time.Millisecond {
+		return fmt.Sprintf("%dµs", d.Microseconds())
+	}
+	if d < time.Second {
+		return fmt.Sprintf("%.1fms", float64(d)/float64(time.Millisecond))
+	}
+	return fmt.Sprintf("%.2fs", d.Seconds())
+}
+
 // saveToHistory saves a request/response pair to history.
 func (v *MainView) saveToHistory(req *core.RequestDefinition, resp *core.Response, err error) {
 	if v.historyStore == nil || req == nil {
 		return
 	}
 
+	// Secret values never reach the history database
+	redactor := v.secretRedactor()
 	entry := history.Entry{
 		RequestMethod:  req.Method(),
-		RequestURL:     req.FullURL(),
+		RequestURL:     redactor.Redact(req.FullURL()),
 		RequestName:    req.Name(),
-		RequestBody:    req.Body(),
-		RequestHeaders: req.Headers(),
+		RequestBody:    redactor.Redact(req.Body()),
+		RequestHeaders: redactor.RedactMap(req.Headers()),
 		Timestamp:      time.Now(),
 	}
 
 	if resp != nil {
 		entry.ResponseStatus = resp.Status().Code()
 		entry.ResponseStatusText = resp.Status().Text()
-		entry.ResponseBody = resp.Body().String()
+		// Only downloads are cut to a preview; their full body is on disk
+		maxBody := 0
+		if resp.DownloadPath() != "" {
+			maxBody = history.DefaultMaxStoredBody
+		}
+		entry.SetResponseBody(redactor.Redact(resp.Body().String()), maxBody, resp.DownloadPath())
 		entry.ResponseTime = resp.Timing().Total.Milliseconds()
 		entry.ResponseSize = resp.Body().Size()
+		if resp.DownloadPath() != "" {
+			entry.ResponseSize = resp.DownloadSize()
+		}
 		entry.ResponseHeaders = make(map[string]string)
 		for _, key := range resp.Headers().Keys() {
-			entry.ResponseHeaders[key] = resp.Headers().Get(key)
+			entry.ResponseHeaders[key] = redactor.Redact(resp.Headers().Get(key))
 		}
 	}
 
 	if err != nil {
-		entry.ResponseStatusText = "Error: " + err.Error()
+		entry.ResponseStatusText = "Error: " + redactor.Redact(err.Error())
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -3283,6 +4413,19 @@ func (v *MainView) saveToHistory(req *core.RequestDefinition, resp *core.Respons
 	}
 }
 
+// secretRedactor masks the secret values of the current and global
+// environments and those resolved through {{$secret "..."}}.
+func (v *MainView) secretRedactor() *secrets.Redactor {
+	values := secrets.ResolvedValues()
+	for _, env := range v.globals {
+		values = append(values, env.SecretValues()...)
+	}
+	if v.environment != nil {
+		values = append(values, v.environment.SecretValues()...)
+	}
+	return secrets.NewRedactor(values...)
+}
+
 // Environment returns the current environment.
 func (v *MainView) Environment() *core.Environment {
 	return v.environment
@@ -3293,6 +4436,23 @@ func (v *MainView) Interpolator() *interpolate.Engine {
 	return v.interpolator
 }
 
+// requestEngine returns the engine a request's variables resolve through:
+// the environment's engine with the request's collection and folder
+// variables layered in. Values set while sending still reach the
+// environment's engine.
+func (v *MainView) requestEngine(reqDef *core.RequestDefinition) *interpolate.Engine {
+	if v.interpolator == nil || reqDef == nil {
+		return v.interpolator
+	}
+	coll := v.tree.CollectionForRequest(reqDef)
+	if coll == nil {
+		return v.interpolator
+	}
+	return v.interpolator.
+		With(interpolate.LevelCollection, coll.Variables()).
+		With(interpolate.LevelFolder, coll.FolderVariables(reqDef.ID()))
+}
+
 // ShowingHelp returns true if help is showing.
 func (v *MainView) ShowingHelp() bool {
 	return v.showHelp
@@ -3325,8 +4485,32 @@ type HTTPClientConfig struct {
 	InsecureSkip    bool
 }
 
+// newHTTPClient creates an HTTP client with the configured cookie jar, proxy and TLS options.
+func newHTTPClient(config HTTPClientConfig) *httpclient.Client {
+	clientOpts := []httpclient.Option{
+		httpclient.WithTimeout(30 * time.Second),
+	}
+	if config.CookieJar != nil {
+		clientOpts = append(clientOpts, httpclient.WithCookieJar(config.CookieJar))
+	}
+	if config.ProxyURL != "" {
+		clientOpts = append(clientOpts, httpclient.WithProxy(config.ProxyURL))
+	}
+	if config.CertFile != "" && config.KeyFile != "" {
+		clientOpts = append(clientOpts, httpclient.WithClientCert(config.CertFile, config.KeyFile))
+	}
+	if config.CAFile != "" {
+		clientOpts = append(clientOpts, httpclient.WithCACert(config.CAFile))
+	}
+	if config.InsecureSkip {
+		clientOpts = append(clientOpts, httpclient.WithInsecureSkipVerify())
+	}
+	return httpclient.NewClient(clientOpts...)
+}
+
 // sendRequest creates a tea.Cmd that sends an HTTP request asynchronously.
-func sendRequest(reqDef *core.RequestDefinition, engine *interpolate.Engine, config HTTPClientConfig) tea.Cmd {
+// Responses are checked against contract when it is not nil.
+func sendRequest(reqDef *core.RequestDefinition, engine *interpolate.Engine, config HTTPClientConfig, contract *importer.Contract, modules *script.ModuleLoader) tea.Cmd {
 	return func() tea.Msg {
 		// Early validation of URL
 		url := reqDef.FullURL()
@@ -3339,8 +4523,23 @@ func sendRequest(reqDef *core.RequestDefinition, engine *interpolate.Engine, con
 			return components.RequestErrorMsg{Error: fmt.Errorf("URL must start with http:// or https://")}
 		}
 
+		// Create HTTP client with timeout and configured options
+		client := newHTTPClient(config)
+
+		// The send context also bounds requests the scripts send, so they
+		// stop with the request
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
 		// Create script scope for pre-request and test scripts
 		scope := script.NewScopeWithAssertions()
+		if modules != nil {
+			scope.SetModuleLoader(modules)
+		}
+		scope.SetRequestSender(runner.ScriptRequestSender(ctx, client))
+		if config.CookieJar != nil {
+			scope.SetCookieJar(config.CookieJar)
+		}
 		var consoleMessages []components.ConsoleMessage
 
 		// Set up console handler to capture console output
@@ -3360,9 +4559,9 @@ func sendRequest(reqDef *core.RequestDefinition, engine *interpolate.Engine, con
 		// Execute pre-request script (if any)
 		preScript := reqDef.PreScript()
 		if preScript != "" {
-			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-			_, err := scope.Execute(ctx, preScript)
-			cancel()
+			scriptCtx, scriptCancel := context.WithTimeout(ctx, 5*time.Second)
+			_, err := scope.Execute(scriptCtx, preScript)
+			scriptCancel()
 			if err != nil {
 				return components.RequestErrorMsg{Error: fmt.Errorf("pre-request script error: %w", err)}
 			}
@@ -3382,31 +4581,7 @@ func sendRequest(reqDef *core.RequestDefinition, engine *interpolate.Engine, con
 			return components.RequestErrorMsg{Error: err}
 		}
 
-		// Create HTTP client with timeout and configured options
-		clientOpts := []httpclient.Option{
-			httpclient.WithTimeout(30 * time.Second),
-		}
-		if config.CookieJar != nil {
-			clientOpts = append(clientOpts, httpclient.WithCookieJar(config.CookieJar))
-		}
-		if config.ProxyURL != "" {
-			clientOpts = append(clientOpts, httpclient.WithProxy(config.ProxyURL))
-		}
-		if config.CertFile != "" && config.KeyFile != "" {
-			clientOpts = append(clientOpts, httpclient.WithClientCert(config.CertFile, config.KeyFile))
-		}
-		if config.CAFile != "" {
-			clientOpts = append(clientOpts, httpclient.WithCACert(config.CAFile))
-		}
-		if config.InsecureSkip {
-			clientOpts = append(clientOpts, httpclient.WithInsecureSkipVerify())
-		}
-		client := httpclient.NewClient(clientOpts...)
-
 		// Send the request
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-		defer cancel()
-
 		resp, err := client.Send(ctx, req)
 		if err != nil {
 			return components.RequestErrorMsg{Error: err}
@@ -3425,12 +4600,13 @@ func sendRequest(reqDef *core.RequestDefinition, engine *interpolate.Engine, con
 			// Set up response context for test scripts
 			scope.SetResponseStatus(resp.Status().Code())
 			scope.SetResponseHeaders(headersMap)
+			scope.SetResponseCookies(script.ParseSetCookies(resp.Headers().GetAll("Set-Cookie")))
 			scope.SetResponseBody(resp.Body().String())
 			scope.SetResponseTime(resp.Timing().Total.Milliseconds())
 
-			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-			_, err := scope.Execute(ctx, testScript)
-			cancel()
+			scriptCtx, scriptCancel := context.WithTimeout(ctx, 5*time.Second)
+			_, err := scope.Execute(scriptCtx, testScript)
+			scriptCancel()
 			if err != nil {
 				// Add script error to console but don't fail the request
 				consoleMessages = append(consoleMessages, components.ConsoleMessage{
@@ -3443,6 +4619,19 @@ func sendRequest(reqDef *core.RequestDefinition, engine *interpolate.Engine, con
 			testResults = scope.GetTestResults()
 		}
 
+		vars, extracted := assertion.Extract(reqDef.Extractions(), resp)
+		if engine != nil {
+			engine.SetVariables(vars)
+		}
+		testResults = append(testResults, assertion.Evaluate(reqDef.Assertions(), resp, engine)...)
+		testResults = append(testResults, extracted...)
+
+		if contract != nil {
+			checks := contract.Check(req.Method(), req.Endpoint(), resp.Status().Code(),
+				resp.Headers().Get("Content-Type"), resp.Body().Bytes())
+			testResults = append(testResults, runner.ContractTestResults(checks)...)
+		}
+
 		return components.ResponseReceivedMsg{
 			Response:    resp,
 			TestResults: testResults,
@@ -3451,6 +4640,196 @@ func sendRequest(reqDef *core.RequestDefinition, engine *interpolate.Engine, con
 	}
 }
 
+// downloadTracker shares progress between a download goroutine and the UI.
+type downloadTracker struct {
+	mu       sync.Mutex
+	progress httpclient.DownloadProgress
+	path     string
+}
+
+// Update records the latest progress report.
+func (t *downloadTracker) Update(p httpclient.DownloadProgress) {
+	t.mu.Lock()
+	t.progress = p
+	t.mu.Unlock()
+}
+
+// Progress returns the latest progress report.
+func (t *downloadTracker) Progress() httpclient.DownloadProgress {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.progress
+}
+
+// startDownload streams the current request's response to a file in the download directory.
+func (v *MainView) startDownload() (tui.Component, tea.Cmd) {
+	reqDef := v.request.Request()
+	if reqDef == nil || reqDef.FullURL() == "" {
+		v.notification = "No request to download"
+		v.notifyUntil = time.Now().Add(2 * time.Second)
+		return v, tea.Tick(2*time.Second, func(time.Time) tea.Msg {
+			return clearNotificationMsg{}
+		})
+	}
+	if v.download != nil {
+		v.notification = "Download already in progress"
+		v.notifyUntil = time.Now().Add(2 * time.Second)
+		return v, tea.Tick(2*time.Second, func(time.Time) tea.Msg {
+			return clearNotificationMsg{}
+		})
+	}
+
+	dir := v.downloadDir
+	if dir == "" {
+		dir = defaultDownloadDir()
+	}
+	engine := v.requestEngine(reqDef)
+	tracker := &downloadTracker{path: uniqueDownloadPath(dir, reqDef, engine)}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	v.download = tracker
+	v.downloadCancel = cancel
+	v.lastRequest = reqDef
+	v.response.SetLoading(true)
+	v.response.SetLoadingText("Downloading to " + tracker.path)
+	v.focusPane(PaneResponse)
+
+	httpConfig := HTTPClientConfig{
+		CookieJar:    v.cookieJar,
+		ProxyURL:     v.proxyURL,
+		CertFile:     v.tlsCertFile,
+		KeyFile:      v.tlsKeyFile,
+		CAFile:       v.tlsCAFile,
+		InsecureSkip: v.tlsInsecureSkip,
+	}
+	opts := httpclient.DownloadOptions{
+		Path:         tracker.path,
+		PreviewLimit: v.previewLimit,
+		OnProgress:   tracker.Update,
+	}
+
+	return v, tea.Batch(
+		downloadRequest(ctx, reqDef, engine, httpConfig, opts),
+		tea.Tick(200*time.Millisecond, func(time.Time) tea.Msg {
+			return downloadTickMsg{}
+		}),
+	)
+}
+
+// handleDownloadComplete shows the downloaded preview and records it in history.
+func (v *MainView) handleDownloadComplete(msg downloadCompleteMsg) (tui.Component, tea.Cmd) {
+	if v.downloadCancel != nil {
+		v.downloadCancel()
+	}
+	v.download = nil
+	v.downloadCancel = nil
+	v.response.SetLoading(false)
+
+	if msg.Response != nil {
+		v.response.SetResponse(msg.Response)
+	}
+	if msg.Error != nil {
+		if msg.Response == nil {
+			v.response.SetError(msg.Error)
+		}
+		v.notification = "Download failed: " + msg.Error.Error()
+	} else {
+		v.notification = fmt.Sprintf("Saved %s to %s",
+			formatBytes(msg.Response.DownloadSize()), msg.Response.DownloadPath())
+	}
+	v.notifyUntil = time.Now().Add(3 * time.Second)
+
+	if v.historyStore != nil && v.lastRequest != nil {
+		go v.saveToHistory(v.lastRequest, msg.Response, msg.Error)
+	}
+
+	return v, tea.Tick(3*time.Second, func(time.Time) tea.Msg {
+		return clearNotificationMsg{}
+	})
+}
+
+// downloadRequest creates a tea.Cmd that streams a response body to disk.
+func downloadRequest(ctx context.Context, reqDef *core.RequestDefinition, engine *interpolate.Engine, config HTTPClientConfig, opts httpclient.DownloadOptions) tea.Cmd {
+	return func() tea.Msg {
+		var req *core.Request
+		var err error
+		if engine != nil {
+			req, err = reqDef.ToRequestWithEnv(engine)
+		} else {
+			req, err = reqDef.ToRequest()
+		}
+		if err != nil {
+			return downloadCompleteMsg{Error: err}
+		}
+
+		resp, err := newHTTPClient(config).Download(ctx, req, opts)
+		return downloadCompleteMsg{Response: resp, Error: err}
+	}
+}
+
+// defaultDownloadDir returns ~/Downloads if it exists, otherwise the working directory.
+func defaultDownloadDir() string {
+	if home, err := os.UserHomeDir(); err == nil {
+		dir := filepath.Join(home, "Downloads")
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			return dir
+		}
+	}
+	return "."
+}
+
+// uniqueDownloadPath derives a file name from the request URL, interpolated
+// with engine, that does not collide with an existing file in dir.
+func uniqueDownloadPath(dir string, reqDef *core.RequestDefinition, engine *interpolate.Engine) string {
+	rawURL := reqDef.URL()
+	if engine != nil {
+		if interpolated, err := engine.Interpolate(rawURL); err == nil {
+			rawURL = interpolated
+		}
+	}
+	name := ""
+	if parsed, err := url.Parse(rawURL); err == nil {
+		name = path.Base(parsed.Path)
+	}
+	if name == "" || name == "/" || name == "." {
+		name = "response-" + time.Now().Format("20060102-150405")
+	}
+
+	ext := filepath.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+	candidate := filepath.Join(dir, name)
+	for i := 1; ; i++ {
+		if _, err := os.Stat(candidate); os.IsNotExist(err) {
+			return candidate
+		}
+		candidate = filepath.Join(dir, fmt.Sprintf("%s-%d%s", base, i, ext))
+	}
+}
+
+// formatDownloadProgress renders a one-line progress and throughput readout.
+func formatDownloadProgress(p httpclient.DownloadProgress) string {
+	line := "Downloading " + formatBytes(p.Written)
+	if p.Total > 0 {
+		line += fmt.Sprintf(" / %s (%.0f%%)", formatBytes(p.Total), p.Percent())
+	}
+	line += fmt.Sprintf("  %s/s  ·  Esc to cancel", formatBytes(int64(p.BytesPerSecond())))
+	return line
+}
+
+// formatBytes formats a byte count using binary units.
+func formatBytes(n int64) string {
+	switch {
+	case n < 1024:
+		return fmt.Sprintf("%dB", n)
+	case n < 1024*1024:
+		return fmt.Sprintf("%.1fKB", float64(n)/1024)
+	case n < 1024*1024*1024:
+		return fmt.Sprintf("%.1fMB", float64(n)/(1024*1024))
+	default:
+		return fmt.Sprintf("%.2fGB", float64(n)/(1024*1024*1024))
+	}
+}
+
 // connectWebSocket creates a tea.Cmd that connects to a WebSocket endpoint.
 func (v *MainView) connectWebSocket(def *core.WebSocketDefinition) tea.Cmd {
 	return func() tea.Msg {
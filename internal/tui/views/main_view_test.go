@@ -3,15 +3,21 @@ package views
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/artpar/currier/internal/core"
 	"github.com/artpar/currier/internal/history"
+	"github.com/artpar/currier/internal/importer"
 	"github.com/artpar/currier/internal/interfaces"
 	"github.com/artpar/currier/internal/interpolate"
+	httpclient "github.com/artpar/currier/internal/protocol/http"
 	"github.com/artpar/currier/internal/runner"
 	"github.com/artpar/currier/internal/script"
 	"github.com/artpar/currier/internal/storage/filesystem"
@@ -972,9 +978,12 @@ func TestMainView_Notification(t *testing.T) {
 }
 
 // mockHistoryStore is a simple mock for testing
-type mockHistoryStore struct{}
+type mockHistoryStore struct {
+	added []history.Entry
+}
 
 func (m *mockHistoryStore) Add(ctx context.Context, entry history.Entry) (string, error) {
+	m.added = append(m.added, entry)
 	return "mock-id", nil
 }
 func (m *mockHistoryStore) Get(ctx context.Context, id string) (history.Entry, error) {
@@ -1169,6 +1178,59 @@ func TestMainView_SaveToHistoryIntegration(t *testing.T) {
 	})
 }
 
+func TestMainView_SaveToHistoryRedactsSecrets(t *testing.T) {
+	view := NewMainView()
+	store := &mockHistoryStore{}
+	view.SetHistoryStore(store)
+	env := core.NewEnvironment("Production")
+	env.SetSecret("token", "s3cr3t-token")
+	view.SetEnvironment(env, interpolate.NewEngine())
+
+	req := core.NewRequestDefinition("Test", "POST", "https://example.com?key=s3cr3t-token")
+	req.SetHeader("Authorization", "Bearer s3cr3t-token")
+	req.SetBody(`{"token":"s3cr3t-token"}`)
+	view.saveToHistory(req, nil, nil)
+
+	require.Len(t, store.added, 1)
+	entry := store.added[0]
+	assert.Equal(t, "https://example.com?key=********", entry.RequestURL)
+	assert.Equal(t, "Bearer ********", entry.RequestHeaders["Authorization"])
+	assert.Equal(t, `{"token":"********"}`, entry.RequestBody)
+}
+
+func TestMainView_SaveToHistoryResponseBody(t *testing.T) {
+	body := strings.Repeat("a", history.DefaultMaxStoredBody+10)
+	req := core.NewRequestDefinition("Test", "GET", "https://example.com")
+
+	t.Run("keeps the whole body", func(t *testing.T) {
+		view := NewMainView()
+		store := &mockHistoryStore{}
+		view.SetHistoryStore(store)
+		resp := core.NewResponse("r1", "http", core.NewStatus(200, "OK")).
+			WithBody(core.NewRawBody([]byte(body), "text/plain"))
+		view.saveToHistory(req, resp, nil)
+
+		require.Len(t, store.added, 1)
+		assert.Equal(t, body, store.added[0].ResponseBody)
+		assert.False(t, store.added[0].IsResponseTruncated())
+	})
+
+	t.Run("keeps a preview of downloads", func(t *testing.T) {
+		view := NewMainView()
+		store := &mockHistoryStore{}
+		view.SetHistoryStore(store)
+		resp := core.NewResponse("r1", "http", core.NewStatus(200, "OK")).
+			WithBody(core.NewRawBody([]byte(body), "text/plain")).
+			WithMetadata(core.MetaDownloadPath, "/tmp/out.bin")
+		view.saveToHistory(req, resp, nil)
+
+		require.Len(t, store.added, 1)
+		assert.Len(t, store.added[0].ResponseBody, history.DefaultMaxStoredBody)
+		assert.True(t, store.added[0].IsResponseTruncated())
+		assert.Equal(t, "/tmp/out.bin", store.added[0].ResponseFile())
+	})
+}
+
 func TestMainView_UpdateMessageTypes(t *testing.T) {
 	t.Run("handles SelectionMsg", func(t *testing.T) {
 		view := NewMainView()
@@ -2222,6 +2284,9 @@ func TestMainView_CopyAsCurlMsg(t *testing.T) {
 }
 
 func TestMainView_ExportCollectionMsg(t *testing.T) {
+	// Exports are written to the working directory
+	t.Chdir(t.TempDir())
+
 	t.Run("handles export collection message", func(t *testing.T) {
 		view := NewMainView()
 		view.SetSize(120, 40)
@@ -2234,6 +2299,7 @@ func TestMainView_ExportCollectionMsg(t *testing.T) {
 		view = updated.(*MainView)
 
 		assert.NotNil(t, cmd)
+		assert.FileExists(t, "Export Me.postman_collection.json")
 	})
 }
 
@@ -2354,6 +2420,99 @@ func TestMainView_ImportCollectionMsg(t *testing.T) {
 	})
 }
 
+func TestMainView_AttachContractMsg(t *testing.T) {
+	spec := filepath.Join(t.TempDir(), "openapi.yaml")
+	require.NoError(t, os.WriteFile(spec, []byte(`
+openapi: 3.0.3
+info: {title: Pets, version: "1"}
+paths:
+  /pets:
+    get:
+      responses:
+        "200": {description: ok}
+`), 0644))
+
+	t.Run("attaches a valid contract", func(t *testing.T) {
+		view := NewMainView()
+		coll := core.NewCollection("Pets")
+
+		updated, cmd := view.Update(components.AttachContractMsg{Collection: coll, Path: spec})
+		view = updated.(*MainView)
+
+		assert.NotNil(t, cmd)
+		assert.Equal(t, spec, coll.Contract())
+		assert.Contains(t, view.notification, "Attached contract openapi.yaml")
+	})
+
+	t.Run("rejects an invalid contract", func(t *testing.T) {
+		view := NewMainView()
+		coll := core.NewCollection("Pets")
+		coll.SetContract(spec)
+
+		updated, _ := view.Update(components.AttachContractMsg{Collection: coll, Path: "/nonexistent/openapi.yaml"})
+		view = updated.(*MainView)
+
+		assert.Equal(t, spec, coll.Contract())
+		assert.Contains(t, view.notification, "Invalid contract")
+	})
+
+	t.Run("stores the path relative to the saved collection and caches the contract", func(t *testing.T) {
+		base := t.TempDir()
+		specPath := filepath.Join(base, "specs", "openapi.yaml")
+		content, err := os.ReadFile(spec)
+		require.NoError(t, err)
+		require.NoError(t, os.MkdirAll(filepath.Dir(specPath), 0755))
+		require.NoError(t, os.WriteFile(specPath, content, 0644))
+
+		store, err := filesystem.NewCollectionStore(filepath.Join(base, "collections"))
+		require.NoError(t, err)
+		view := NewMainView()
+		view.SetCollectionStore(store)
+		coll := core.NewCollection("Pets")
+		require.NoError(t, store.Save(context.Background(), coll))
+
+		view.Update(components.AttachContractMsg{Collection: coll, Path: specPath})
+		assert.Equal(t, "../specs/openapi.yaml", coll.Contract())
+
+		contract, err := view.collectionContract(coll)
+		require.NoError(t, err)
+		require.NotNil(t, contract)
+		again, err := view.collectionContract(coll)
+		require.NoError(t, err)
+		assert.Same(t, contract, again)
+	})
+
+	t.Run("empty path detaches", func(t *testing.T) {
+		view := NewMainView()
+		coll := core.NewCollection("Pets")
+		coll.SetContract(spec)
+
+		view.Update(components.AttachContractMsg{Collection: coll})
+
+		assert.Empty(t, coll.Contract())
+	})
+}
+
+func TestMainView_CollectionDir(t *testing.T) {
+	t.Run("resolves against the directory holding the collection", func(t *testing.T) {
+		userDir, workspaceDir := t.TempDir(), t.TempDir()
+		store, err := filesystem.NewCollectionStore(userDir, filesystem.WithCollectionWorkspace(workspaceDir))
+		require.NoError(t, err)
+		coll := core.NewCollection("Team API")
+		require.NoError(t, store.Save(context.Background(), coll))
+
+		view := NewMainView()
+		view.SetCollectionStore(store)
+
+		assert.Equal(t, workspaceDir, view.collectionDir(coll))
+		assert.Empty(t, view.collectionDir(nil))
+	})
+
+	t.Run("is empty without a store", func(t *testing.T) {
+		assert.Empty(t, NewMainView().collectionDir(core.NewCollection("API")))
+	})
+}
+
 func TestMainView_SetCollectionStore(t *testing.T) {
 	t.Run("sets collection store", func(t *testing.T) {
 		view := NewMainView()
@@ -2380,6 +2539,9 @@ func TestMainView_FocusBlurMethods(t *testing.T) {
 }
 
 func TestMainView_SanitizeFilename(t *testing.T) {
+	// Exports are written to the working directory
+	t.Chdir(t.TempDir())
+
 	t.Run("sanitizes filename with spaces", func(t *testing.T) {
 		view := NewMainView()
 		view.SetSize(120, 40)
@@ -2777,6 +2939,20 @@ func TestMainView_EnvironmentSwitcherUI(t *testing.T) {
 		output := view.View()
 		assert.Contains(t, output, "Select Environment")
 	})
+
+	t.Run("renders the inheritance chain", func(t *testing.T) {
+		view := NewMainView()
+		view.SetSize(120, 40)
+		view.showEnvSwitcher = true
+		view.envList = []filesystem.EnvironmentMeta{
+			{ID: "1", Name: "staging", Chain: []string{"base", "shared"}},
+			{ID: "2", Name: "base", Chain: []string{"shared"}},
+		}
+		view.envCursor = 0
+
+		output := view.View()
+		assert.Contains(t, output, "staging → base → shared")
+	})
 }
 
 func TestMainView_ProxyDialog(t *testing.T) {
@@ -6982,7 +7158,7 @@ func TestSendRequest_URLValidation(t *testing.T) {
 		reqDef := core.NewRequestDefinition("Test", "GET", "")
 		config := HTTPClientConfig{}
 
-		cmd := sendRequest(reqDef, nil, config)
+		cmd := sendRequest(reqDef, nil, config, nil, nil)
 		msg := cmd()
 
 		errMsg, ok := msg.(components.RequestErrorMsg)
@@ -6994,7 +7170,7 @@ func TestSendRequest_URLValidation(t *testing.T) {
 		reqDef := core.NewRequestDefinition("Test", "GET", "example.com/api")
 		config := HTTPClientConfig{}
 
-		cmd := sendRequest(reqDef, nil, config)
+		cmd := sendRequest(reqDef, nil, config, nil, nil)
 		msg := cmd()
 
 		errMsg, ok := msg.(components.RequestErrorMsg)
@@ -7006,7 +7182,7 @@ func TestSendRequest_URLValidation(t *testing.T) {
 		reqDef := core.NewRequestDefinition("Test", "GET", "ftp://example.com")
 		config := HTTPClientConfig{}
 
-		cmd := sendRequest(reqDef, nil, config)
+		cmd := sendRequest(reqDef, nil, config, nil, nil)
 		msg := cmd()
 
 		errMsg, ok := msg.(components.RequestErrorMsg)
@@ -7018,7 +7194,7 @@ func TestSendRequest_URLValidation(t *testing.T) {
 		reqDef := core.NewRequestDefinition("Test", "GET", "http://localhost:8080/api")
 		config := HTTPClientConfig{}
 
-		cmd := sendRequest(reqDef, nil, config)
+		cmd := sendRequest(reqDef, nil, config, nil, nil)
 		// This will actually make an HTTP request - we just verify it doesn't error on validation
 		msg := cmd()
 
@@ -7034,7 +7210,7 @@ func TestSendRequest_URLValidation(t *testing.T) {
 		reqDef := core.NewRequestDefinition("Test", "GET", "https://example.com/api")
 		config := HTTPClientConfig{}
 
-		cmd := sendRequest(reqDef, nil, config)
+		cmd := sendRequest(reqDef, nil, config, nil, nil)
 		msg := cmd()
 
 		// Should not be a URL validation error
@@ -7052,7 +7228,7 @@ func TestSendRequest_PreScript(t *testing.T) {
 		reqDef.SetPreScript("var x = 1;")
 		config := HTTPClientConfig{}
 
-		cmd := sendRequest(reqDef, nil, config)
+		cmd := sendRequest(reqDef, nil, config, nil, nil)
 		msg := cmd()
 
 		// Should not be a pre-request script error
@@ -7066,7 +7242,7 @@ func TestSendRequest_PreScript(t *testing.T) {
 		reqDef.SetPreScript("this is not valid javascript @#$%^&*(")
 		config := HTTPClientConfig{}
 
-		cmd := sendRequest(reqDef, nil, config)
+		cmd := sendRequest(reqDef, nil, config, nil, nil)
 		msg := cmd()
 
 		errMsg, ok := msg.(components.RequestErrorMsg)
@@ -7079,7 +7255,7 @@ func TestSendRequest_PreScript(t *testing.T) {
 		reqDef.SetPreScript(`console.log("hello from pre-script");`)
 		config := HTTPClientConfig{}
 
-		cmd := sendRequest(reqDef, nil, config)
+		cmd := sendRequest(reqDef, nil, config, nil, nil)
 		msg := cmd()
 
 		// Script should execute without error
@@ -7097,7 +7273,7 @@ func TestSendRequest_HTTPClientConfig(t *testing.T) {
 			ProxyURL: "http://proxy.example.com:8080",
 		}
 
-		cmd := sendRequest(reqDef, nil, config)
+		cmd := sendRequest(reqDef, nil, config, nil, nil)
 		msg := cmd()
 
 		// Request will fail since no server, but should not be a configuration error
@@ -7110,7 +7286,7 @@ func TestSendRequest_HTTPClientConfig(t *testing.T) {
 			InsecureSkip: true,
 		}
 
-		cmd := sendRequest(reqDef, nil, config)
+		cmd := sendRequest(reqDef, nil, config, nil, nil)
 		msg := cmd()
 
 		// Request will fail since no server, but should apply the config
@@ -7124,7 +7300,7 @@ func TestSendRequest_HTTPClientConfig(t *testing.T) {
 			KeyFile:  "/path/to/key.pem",
 		}
 
-		cmd := sendRequest(reqDef, nil, config)
+		cmd := sendRequest(reqDef, nil, config, nil, nil)
 		msg := cmd()
 
 		// Will fail due to invalid cert path, but that's expected
@@ -7137,7 +7313,7 @@ func TestSendRequest_HTTPClientConfig(t *testing.T) {
 			CAFile: "/path/to/ca.pem",
 		}
 
-		cmd := sendRequest(reqDef, nil, config)
+		cmd := sendRequest(reqDef, nil, config, nil, nil)
 		msg := cmd()
 
 		// Will fail due to invalid CA path, but that's expected
@@ -7154,7 +7330,7 @@ func TestSendRequest_HTTPClientConfig(t *testing.T) {
 			InsecureSkip: true,
 		}
 
-		cmd := sendRequest(reqDef, nil, config)
+		cmd := sendRequest(reqDef, nil, config, nil, nil)
 		msg := cmd()
 
 		assert.NotNil(t, msg)
@@ -7171,7 +7347,7 @@ func TestSendRequest_WithInterpolation(t *testing.T) {
 		engine := interpolate.NewEngine()
 		engine.SetVariable("host", "localhost:9999")
 
-		cmd := sendRequest(reqDef, engine, config)
+		cmd := sendRequest(reqDef, engine, config, nil, nil)
 		msg := cmd()
 
 		// Should attempt to connect to localhost:9999, not literally "{{host}}"
@@ -7182,7 +7358,7 @@ func TestSendRequest_WithInterpolation(t *testing.T) {
 		reqDef := core.NewRequestDefinition("Test", "GET", "http://localhost:9999/test")
 		config := HTTPClientConfig{}
 
-		cmd := sendRequest(reqDef, nil, config)
+		cmd := sendRequest(reqDef, nil, config, nil, nil)
 		msg := cmd()
 
 		assert.NotNil(t, msg)
@@ -7202,7 +7378,7 @@ func TestSendRequest_PostScript(t *testing.T) {
 		reqDef.SetPostScript(`console.log("Response received");`)
 		config := HTTPClientConfig{}
 
-		cmd := sendRequest(reqDef, nil, config)
+		cmd := sendRequest(reqDef, nil, config, nil, nil)
 		msg := cmd()
 
 		// Will fail due to no server, but script error shouldn't be the issue
@@ -7221,7 +7397,7 @@ func TestSendRequest_RequestMethod(t *testing.T) {
 			reqDef := core.NewRequestDefinition("Test", method, "http://localhost:9999/test")
 			config := HTTPClientConfig{}
 
-			cmd := sendRequest(reqDef, nil, config)
+			cmd := sendRequest(reqDef, nil, config, nil, nil)
 			msg := cmd()
 
 			// All should return some message (likely error since no server)
@@ -7238,7 +7414,7 @@ func TestSendRequest_RequestWithBody(t *testing.T) {
 		reqDef.SetHeader("Content-Type", "application/json")
 		config := HTTPClientConfig{}
 
-		cmd := sendRequest(reqDef, nil, config)
+		cmd := sendRequest(reqDef, nil, config, nil, nil)
 		msg := cmd()
 
 		// Request will fail since no server, but body should be set
@@ -7251,7 +7427,7 @@ func TestSendRequest_RequestWithBody(t *testing.T) {
 		reqDef.SetHeader("Content-Type", "application/x-www-form-urlencoded")
 		config := HTTPClientConfig{}
 
-		cmd := sendRequest(reqDef, nil, config)
+		cmd := sendRequest(reqDef, nil, config, nil, nil)
 		msg := cmd()
 
 		assert.NotNil(t, msg)
@@ -7274,7 +7450,7 @@ func TestSendRequest_ScriptContext(t *testing.T) {
 		`)
 		config := HTTPClientConfig{}
 
-		cmd := sendRequest(reqDef, nil, config)
+		cmd := sendRequest(reqDef, nil, config, nil, nil)
 		msg := cmd()
 
 		// Should not error on script execution
@@ -7284,6 +7460,63 @@ func TestSendRequest_ScriptContext(t *testing.T) {
 	})
 }
 
+func TestSendRequest_Contract(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": "1"}`))
+	}))
+	defer server.Close()
+
+	contract, err := importer.ParseContract([]byte(`
+openapi: 3.0.3
+info: {title: Pets, version: "1"}
+paths:
+  /pets/{id}:
+    get:
+      responses:
+        "200":
+          description: A pet
+          content:
+            application/json:
+              schema:
+                properties:
+                  id: {type: integer}
+`))
+	require.NoError(t, err)
+
+	reqDef := core.NewRequestDefinition("Pet", "GET", server.URL+"/pets/1")
+	msg := sendRequest(reqDef, nil, HTTPClientConfig{}, contract, nil)()
+
+	received, ok := msg.(components.ResponseReceivedMsg)
+	require.True(t, ok, "expected ResponseReceivedMsg, got %T", msg)
+	require.Len(t, received.TestResults, 3)
+	assert.Equal(t, "Contract: Body matches schema", received.TestResults[2].Name)
+	assert.False(t, received.TestResults[2].Passed)
+	assert.Equal(t, "/id: expected integer, got string", received.TestResults[2].Error)
+}
+
+func TestSendRequest_ScriptSendRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"path": "` + r.URL.Path + `"}`))
+	}))
+	defer server.Close()
+
+	reqDef := core.NewRequestDefinition("Items", "GET", server.URL+"/items")
+	reqDef.SetPostScript(`
+		currier.test("script request", async function() {
+			var res = await currier.sendRequest("` + server.URL + `/token");
+			currier.expect(res.json().path).toBe("/token");
+		});
+	`)
+	msg := sendRequest(reqDef, nil, HTTPClientConfig{}, nil, nil)()
+
+	received, ok := msg.(components.ResponseReceivedMsg)
+	require.True(t, ok, "expected ResponseReceivedMsg, got %T", msg)
+	require.Len(t, received.TestResults, 1)
+	assert.True(t, received.TestResults[0].Passed, received.TestResults[0].Error)
+}
+
 // TestMainView_EnvironmentSwitcherCoverage tests environment switcher functionality
 func TestMainView_EnvironmentSwitcherCoverage(t *testing.T) {
 	t.Run("openEnvSwitcher without store shows notification", func(t *testing.T) {
@@ -8401,6 +8634,7 @@ func TestMainView_MoreUpdateMessages(t *testing.T) {
 	})
 
 	t.Run("Update with ExportCollectionMsg", func(t *testing.T) {
+		t.Chdir(t.TempDir())
 		view := NewMainView()
 		view.SetSize(120, 40)
 
@@ -8883,3 +9117,378 @@ func TestMainView_EnvSwitcherRender(t *testing.T) {
 	})
 }
 
+
+func TestMainView_Download(t *testing.T) {
+	t.Run("Ctrl+O streams response to download dir", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(strings.Repeat("a", 4096)))
+		}))
+		defer server.Close()
+
+		dir := t.TempDir()
+		view := NewMainView()
+		view.SetSize(120, 40)
+		view.SetDownloadDir(dir)
+		view.SetPreviewLimit(100)
+		view.RequestPanel().SetRequest(core.NewRequestDefinition("Blob", "GET", server.URL+"/files/blob.bin"))
+
+		updated, cmd := view.Update(tea.KeyMsg{Type: tea.KeyCtrlO})
+		view = updated.(*MainView)
+		require.NotNil(t, cmd)
+		assert.True(t, view.IsDownloading())
+		assert.True(t, view.ResponsePanel().IsLoading())
+
+		// Run the download command directly (tea.Batch wraps it)
+		var complete downloadCompleteMsg
+		for _, c := range cmd().(tea.BatchMsg) {
+			if msg, ok := c().(downloadCompleteMsg); ok {
+				complete = msg
+				break
+			}
+		}
+		require.NoError(t, complete.Error)
+
+		updated, _ = view.Update(complete)
+		view = updated.(*MainView)
+		assert.False(t, view.IsDownloading())
+		assert.Contains(t, view.Notification(), filepath.Join(dir, "blob.bin"))
+
+		resp := view.ResponsePanel().Response()
+		require.NotNil(t, resp)
+		assert.True(t, resp.IsBodyTruncated())
+		assert.Equal(t, int64(4096), resp.DownloadSize())
+	})
+
+	t.Run("Ctrl+O without request shows notification", func(t *testing.T) {
+		view := NewMainView()
+		view.SetSize(120, 40)
+
+		updated, _ := view.Update(tea.KeyMsg{Type: tea.KeyCtrlO})
+		view = updated.(*MainView)
+
+		assert.False(t, view.IsDownloading())
+		assert.Contains(t, view.Notification(), "No request")
+	})
+
+	t.Run("uniqueDownloadPath avoids existing files", func(t *testing.T) {
+		dir := t.TempDir()
+		req := core.NewRequestDefinition("Report", "GET", "https://example.com/reports/data.csv")
+
+		first := uniqueDownloadPath(dir, req, nil)
+		assert.Equal(t, filepath.Join(dir, "data.csv"), first)
+
+		require.NoError(t, os.WriteFile(first, []byte("x"), 0644))
+		assert.Equal(t, filepath.Join(dir, "data-1.csv"), uniqueDownloadPath(dir, req, nil))
+	})
+
+	t.Run("uniqueDownloadPath interpolates the URL", func(t *testing.T) {
+		dir := t.TempDir()
+		engine := interpolate.NewEngine()
+		engine.SetVariable("baseUrl", "https://example.com/files")
+		engine.SetVariable("file", "report.pdf")
+		req := core.NewRequestDefinition("Report", "GET", "{{baseUrl}}/{{file}}")
+
+		assert.Equal(t, filepath.Join(dir, "report.pdf"), uniqueDownloadPath(dir, req, engine))
+	})
+
+	t.Run("formatDownloadProgress shows percent and throughput", func(t *testing.T) {
+		line := formatDownloadProgress(httpclient.DownloadProgress{
+			Written: 512 * 1024,
+			Total:   1024 * 1024,
+			Elapsed: time.Second,
+		})
+		assert.Contains(t, line, "512.0KB / 1.0MB (50%)")
+		assert.Contains(t, line, "512.0KB/s")
+	})
+}
+
+// TestMainView_RunnerSetup tests the data file / iterations step of the runner
+func TestMainView_RunnerSetup(t *testing.T) {
+	newView := func() *MainView {
+		view := NewMainView()
+		view.SetSize(120, 40)
+		col := core.NewCollection("Test API")
+		col.AddRequest(core.NewRequestDefinition("Req1", "GET", "https://example.com/{{user}}"))
+		view.SetCollections([]*core.Collection{col})
+		return view
+	}
+
+	t.Run("Ctrl+R opens setup step", func(t *testing.T) {
+		view := newView()
+
+		updated, cmd := view.Update(tea.KeyMsg{Type: tea.KeyCtrlR})
+		view = updated.(*MainView)
+
+		assert.Nil(t, cmd)
+		assert.True(t, view.showRunnerModal)
+		assert.True(t, view.runnerSetup)
+		assert.False(t, view.runnerRunning)
+		assert.Contains(t, view.View(), "Data file")
+	})
+
+	t.Run("typing fills fields and iterations accept digits only", func(t *testing.T) {
+		view := newView()
+		view.openRunnerSetup()
+
+		view.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("users.csv")})
+		view.Update(tea.KeyMsg{Type: tea.KeyTab})
+		view.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("1x2")})
+
+		assert.Equal(t, "users.csv", view.runnerDataInput)
+		assert.Equal(t, "12", view.runnerIterInput)
+
+		view.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+		assert.Equal(t, "1", view.runnerIterInput)
+	})
+
+	t.Run("Enter loads data file and starts runner", func(t *testing.T) {
+		view := newView()
+		path := filepath.Join(t.TempDir(), "users.csv")
+		require.NoError(t, os.WriteFile(path, []byte("user\nalice\nbob\n"), 0644))
+
+		view.openRunnerSetup()
+		view.runnerDataInput = path
+
+		updated, cmd := view.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		view = updated.(*MainView)
+
+		assert.NotNil(t, cmd)
+		assert.False(t, view.runnerSetup)
+		assert.True(t, view.runnerRunning)
+		assert.Len(t, view.runnerData, 2)
+		assert.Equal(t, 0, view.runnerIterations)
+	})
+
+	t.Run("Enter with missing data file keeps setup open", func(t *testing.T) {
+		view := newView()
+		view.openRunnerSetup()
+		view.runnerDataInput = filepath.Join(t.TempDir(), "missing.csv")
+
+		updated, _ := view.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		view = updated.(*MainView)
+
+		assert.True(t, view.runnerSetup)
+		assert.False(t, view.runnerRunning)
+		assert.Contains(t, view.notification, "data file")
+	})
+
+	t.Run("Esc closes setup", func(t *testing.T) {
+		view := newView()
+		view.openRunnerSetup()
+
+		updated, _ := view.Update(tea.KeyMsg{Type: tea.KeyEsc})
+		view = updated.(*MainView)
+
+		assert.False(t, view.showRunnerModal)
+		assert.False(t, view.runnerSetup)
+	})
+
+	t.Run("summary shows per-iteration results", func(t *testing.T) {
+		view := newView()
+		view.showRunnerModal = true
+		view.runnerSummary = &runner.RunSummary{
+			CollectionName: "Test API",
+			TotalRequests:  2,
+			Executed:       2,
+			Passed:         2,
+			Iterations: []runner.IterationSummary{
+				{Index: 0, Data: map[string]string{"user": "alice"}, Executed: 1, Passed: 1},
+				{Index: 1, Data: map[string]string{"user": "bob"}, Executed: 1, Passed: 1},
+			},
+		}
+
+		output := view.View()
+		assert.Contains(t, output, "Iterations: 2")
+		assert.Contains(t, output, "user=bob")
+	})
+}
+
+func TestMainView_RunnerSummarySkipped(t *testing.T) {
+	view := NewMainView()
+	view.SetSize(120, 40)
+	view.showRunnerModal = true
+	view.runnerSummary = &runner.RunSummary{
+		CollectionName: "Test API",
+		TotalRequests:  3,
+		Executed:       1,
+		Passed:         1,
+		Skipped:        2,
+		Stopped:        true,
+	}
+
+	output := view.View()
+	assert.Contains(t, output, "Skipped: 2")
+	assert.Contains(t, output, "Run stopped by script")
+}
+
+func TestMainView_Bench(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	newView := func() *MainView {
+		view := NewMainView()
+		view.SetSize(120, 40)
+		view.RequestPanel().SetRequest(core.NewRequestDefinition("Health", "GET", server.URL+"/health"))
+		return view
+	}
+
+	t.Run("Ctrl+B opens options with defaults", func(t *testing.T) {
+		view := newView()
+
+		updated, _ := view.Update(tea.KeyMsg{Type: tea.KeyCtrlB})
+		view = updated.(*MainView)
+
+		assert.True(t, view.showBenchModal)
+		assert.True(t, view.benchSetup)
+		assert.Equal(t, "10", view.benchConcInput)
+		assert.Equal(t, "10s", view.benchDurInput)
+		output := view.View()
+		assert.Contains(t, output, "Load Test")
+		assert.Contains(t, output, "Concurrency")
+	})
+
+	t.Run("Ctrl+B without a request shows a notification", func(t *testing.T) {
+		view := NewMainView()
+		view.SetSize(120, 40)
+
+		view.Update(tea.KeyMsg{Type: tea.KeyCtrlB})
+
+		assert.False(t, view.showBenchModal)
+		assert.Equal(t, "No request to load test", view.notification)
+	})
+
+	t.Run("fields accept only valid characters", func(t *testing.T) {
+		view := newView()
+		view.openBenchSetup()
+		view.benchConcInput = ""
+
+		view.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("4x")})
+		view.Update(tea.KeyMsg{Type: tea.KeyTab})
+		view.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("2.5/s")})
+		view.Update(tea.KeyMsg{Type: tea.KeyTab})
+		view.Update(tea.KeyMsg{Type: tea.KeyCtrlU})
+		view.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("1m")})
+
+		assert.Equal(t, "4", view.benchConcInput)
+		assert.Equal(t, "2.5", view.benchRateInput)
+		assert.Equal(t, "1m", view.benchDurInput)
+
+		view.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
+		view.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+		assert.Equal(t, "2.", view.benchRateInput)
+	})
+
+	t.Run("invalid options keep setup open", func(t *testing.T) {
+		view := newView()
+		view.openBenchSetup()
+		view.benchDurInput = "0"
+
+		view.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+		assert.True(t, view.benchSetup)
+		assert.Nil(t, view.benchRunner)
+		assert.Contains(t, view.notification, "Duration")
+	})
+
+	t.Run("runs and shows the report", func(t *testing.T) {
+		view := newView()
+		view.openBenchSetup()
+		view.benchConcInput = "2"
+		view.benchDurInput = "0.2"
+
+		updated, cmd := view.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		view = updated.(*MainView)
+		require.NotNil(t, cmd)
+		assert.False(t, view.benchSetup)
+		require.NotNil(t, view.benchRunner)
+		assert.Contains(t, view.View(), "Load Testing")
+
+		batch, ok := cmd().(tea.BatchMsg)
+		require.True(t, ok)
+		msg := batch[0]()
+		require.IsType(t, benchCompleteMsg{}, msg)
+		view.Update(msg)
+
+		assert.Nil(t, view.benchRunner)
+		require.NotNil(t, view.benchReport)
+		output := view.View()
+		assert.Contains(t, output, "Load Test Complete")
+		assert.Contains(t, output, "p99")
+		assert.Contains(t, output, "200×")
+		assert.Contains(t, output, "Throughput")
+
+		dir := t.TempDir()
+		view.SetDownloadDir(dir)
+		view.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("e")})
+		assert.Contains(t, view.notification, "Saved load test report to "+dir)
+		files, err := filepath.Glob(filepath.Join(dir, "bench-*.json"))
+		require.NoError(t, err)
+		assert.Len(t, files, 1)
+
+		view.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		assert.False(t, view.showBenchModal)
+	})
+
+	t.Run("Esc while running stops the benchmark", func(t *testing.T) {
+		view := newView()
+		view.openBenchSetup()
+		_, cmd := view.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		require.NotNil(t, view.benchRunner)
+
+		view.Update(tea.KeyMsg{Type: tea.KeyEsc})
+		assert.True(t, view.showBenchModal, "modal stays open for the partial report")
+
+		start := time.Now()
+		batch := cmd().(tea.BatchMsg)
+		view.Update(batch[0]())
+		assert.Less(t, time.Since(start), 2*time.Second)
+		assert.NotNil(t, view.benchReport)
+	})
+}
+
+func TestMainView_GlobalEnvironments(t *testing.T) {
+	t.Run("layers globals beneath the environment and toggles them with g", func(t *testing.T) {
+		store, err := filesystem.NewEnvironmentStore(t.TempDir())
+		require.NoError(t, err)
+		ctx := context.Background()
+		company := core.NewEnvironment("company")
+		company.SetVariable("tenant", "acme")
+		company.SetVariable("region", "us")
+		company.SetGlobal(true)
+		require.NoError(t, store.Save(ctx, company))
+		personal := core.NewEnvironment("personal")
+		personal.SetSecret("token", "personal-s3cr3t")
+		require.NoError(t, store.Save(ctx, personal))
+
+		view := NewMainView()
+		view.SetSize(120, 40)
+		view.SetEnvironmentStore(store)
+		staging := core.NewEnvironment("staging")
+		staging.SetVariable("region", "eu")
+		engine := interpolate.NewEngine()
+		engine.Scope().SetEnvironment(interpolate.NewVariableSetFrom(staging.ExportAll()))
+		view.SetEnvironment(staging, engine)
+
+		result, err := view.interpolator.Interpolate("{{tenant}}/{{region}}")
+		require.NoError(t, err)
+		assert.Equal(t, "acme/eu", result)
+
+		view.envList, err = store.List(ctx)
+		require.NoError(t, err)
+		view.showEnvSwitcher = true
+		for i, meta := range view.envList {
+			if meta.Name == "personal" {
+				view.envCursor = i
+			}
+		}
+		assert.Contains(t, view.View(), "company (global)")
+
+		updated, _ := view.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'g'}})
+		view = updated.(*MainView)
+		assert.Len(t, view.globals, 2)
+		assert.Equal(t, "'personal' is now global", view.notification)
+		assert.Equal(t, "Bearer ********", view.secretRedactor().Redact("Bearer personal-s3cr3t"))
+	})
+}
@@ -0,0 +1,138 @@
+// Package workspace finds project workspaces: .currier directories that
+// hold a project's collections, environments and settings, found by walking
+// up from the working directory the way git finds .git.
+package workspace
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DirName is the name of a workspace directory.
+const DirName = ".currier"
+
+const (
+	settingsFile = "settings.yaml"
+	// activeFile records the environment chosen in a checkout. It is
+	// personal, so Init ignores it in git.
+	activeFile = "environments/.active"
+)
+
+// Settings are a workspace's settings, from its settings.yaml.
+type Settings struct {
+	// Name labels the workspace's collections in the TUI. It defaults to
+	// the name of the project directory.
+	Name string `yaml:"name,omitempty"`
+	// Environment is activated when no environment has been chosen in the
+	// checkout yet.
+	Environment string `yaml:"environment,omitempty"`
+	// CollectionLayout is the layout new collections are saved in:
+	// directory (the default) or file.
+	CollectionLayout string `yaml:"collection_layout,omitempty"`
+}
+
+// Workspace is a project's .currier directory.
+type Workspace struct {
+	Dir      string // The .currier directory
+	Settings Settings
+}
+
+// Find returns the workspace of the directory start or its nearest parent,
+// or nil if there is none. The .currier directory in the home directory
+// holds Currier's own data, such as proxy certificates, and is not a
+// workspace.
+func Find(start string) (*Workspace, error) {
+	dir, err := filepath.Abs(start)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", start, err)
+	}
+	home, _ := os.UserHomeDir()
+
+	for {
+		path := filepath.Join(dir, DirName)
+		if info, err := os.Stat(path); err == nil && info.IsDir() && dir != home {
+			return Load(path)
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, nil
+		}
+		dir = parent
+	}
+}
+
+// Load loads the workspace in the .currier directory dir.
+func Load(dir string) (*Workspace, error) {
+	w := &Workspace{Dir: dir}
+	content, err := os.ReadFile(filepath.Join(dir, settingsFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return w, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read workspace settings: %w", err)
+	}
+	if err := yaml.Unmarshal(content, &w.Settings); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filepath.Join(dir, settingsFile), err)
+	}
+	return w, nil
+}
+
+// Init creates a workspace in the project directory dir, or loads the one
+// already there.
+func Init(dir string) (*Workspace, error) {
+	path := filepath.Join(dir, DirName)
+	for _, sub := range []string{"collections", "environments"} {
+		if err := os.MkdirAll(filepath.Join(path, sub), 0755); err != nil {
+			return nil, fmt.Errorf("failed to create workspace: %w", err)
+		}
+	}
+
+	files := map[string]string{
+		settingsFile: "# Currier workspace settings\n" +
+			"# name: My API                 # Label of the workspace's collections\n" +
+			"# environment: staging         # Environment to activate by default\n" +
+			"# collection_layout: directory # Layout of new collections: directory or file\n",
+		".gitignore": activeFile + "\n",
+	}
+	for name, content := range files {
+		file := filepath.Join(path, name)
+		if _, err := os.Stat(file); err == nil {
+			continue
+		}
+		if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+			return nil, fmt.Errorf("failed to create workspace: %w", err)
+		}
+	}
+	return Load(path)
+}
+
+// Name returns the workspace's name: its name setting, or the name of the
+// project directory.
+func (w *Workspace) Name() string {
+	if w.Settings.Name != "" {
+		return w.Settings.Name
+	}
+	return filepath.Base(filepath.Dir(w.Dir))
+}
+
+// CollectionsDir returns the directory of the workspace's collections.
+func (w *Workspace) CollectionsDir() string {
+	return filepath.Join(w.Dir, "collections")
+}
+
+// EnvironmentsDir returns the directory of the workspace's environments.
+func (w *Workspace) EnvironmentsDir() string {
+	return filepath.Join(w.Dir, "environments")
+}
+
+// CollectionLayout returns the layout new collections are saved in.
+func (w *Workspace) CollectionLayout() string {
+	if w.Settings.CollectionLayout != "" {
+		return w.Settings.CollectionLayout
+	}
+	return "directory"
+}
@@ -0,0 +1,108 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFind(t *testing.T) {
+	t.Run("finds the workspace of a parent directory", func(t *testing.T) {
+		project := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(project, DirName), 0755))
+		nested := filepath.Join(project, "services", "api")
+		require.NoError(t, os.MkdirAll(nested, 0755))
+
+		w, err := Find(nested)
+		require.NoError(t, err)
+		require.NotNil(t, w)
+		assert.Equal(t, filepath.Join(project, DirName), w.Dir)
+		assert.Equal(t, filepath.Base(project), w.Name())
+		assert.Equal(t, filepath.Join(project, DirName, "collections"), w.CollectionsDir())
+		assert.Equal(t, filepath.Join(project, DirName, "environments"), w.EnvironmentsDir())
+		assert.Equal(t, "directory", w.CollectionLayout())
+	})
+
+	t.Run("returns nil outside a workspace", func(t *testing.T) {
+		t.Setenv("HOME", t.TempDir())
+		w, err := Find(t.TempDir())
+		require.NoError(t, err)
+		assert.Nil(t, w)
+	})
+
+	t.Run("ignores the .currier directory in the home directory", func(t *testing.T) {
+		home := t.TempDir()
+		t.Setenv("HOME", home)
+		require.NoError(t, os.MkdirAll(filepath.Join(home, DirName, "proxy"), 0755))
+		project := filepath.Join(home, "project")
+		require.NoError(t, os.MkdirAll(project, 0755))
+
+		w, err := Find(project)
+		require.NoError(t, err)
+		assert.Nil(t, w)
+	})
+
+	t.Run("ignores a .currier file", func(t *testing.T) {
+		project := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(project, DirName), []byte("x"), 0644))
+		t.Setenv("HOME", t.TempDir())
+
+		w, err := Find(project)
+		require.NoError(t, err)
+		assert.Nil(t, w)
+	})
+}
+
+func TestLoad(t *testing.T) {
+	t.Run("reads settings", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), DirName)
+		require.NoError(t, os.MkdirAll(dir, 0755))
+		settings := "name: Payments\nenvironment: staging\ncollection_layout: file\n"
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "settings.yaml"), []byte(settings), 0644))
+
+		w, err := Load(dir)
+		require.NoError(t, err)
+		assert.Equal(t, "Payments", w.Name())
+		assert.Equal(t, "staging", w.Settings.Environment)
+		assert.Equal(t, "file", w.CollectionLayout())
+	})
+
+	t.Run("returns an error for invalid settings", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), DirName)
+		require.NoError(t, os.MkdirAll(dir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "settings.yaml"), []byte("name: [unclosed"), 0644))
+
+		_, err := Load(dir)
+		assert.Error(t, err)
+	})
+}
+
+func TestInit(t *testing.T) {
+	t.Run("creates the workspace layout", func(t *testing.T) {
+		project := t.TempDir()
+
+		w, err := Init(project)
+		require.NoError(t, err)
+		assert.DirExists(t, w.CollectionsDir())
+		assert.DirExists(t, w.EnvironmentsDir())
+		assert.FileExists(t, filepath.Join(w.Dir, "settings.yaml"))
+
+		ignore, err := os.ReadFile(filepath.Join(w.Dir, ".gitignore"))
+		require.NoError(t, err)
+		assert.Equal(t, "environments/.active\n", string(ignore))
+	})
+
+	t.Run("keeps existing settings", func(t *testing.T) {
+		project := t.TempDir()
+		dir := filepath.Join(project, DirName)
+		require.NoError(t, os.MkdirAll(dir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "settings.yaml"), []byte("name: Kept\n"), 0644))
+
+		w, err := Init(project)
+		require.NoError(t, err)
+		assert.Equal(t, "Kept", w.Name())
+	})
+}
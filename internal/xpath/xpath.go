@@ -0,0 +1,475 @@
+// Package xpath evaluates a practical subset of XPath 1.0 against XML
+// documents: absolute and relative location paths with / and //, element
+// names and *, @attr and @*, text(), . and .., and predicates that select
+// by position ([2], [last()]), by attribute ([@id], [@id='7']) or by child
+// or text value ([name='Ada'], [text()='Ada']).
+package xpath
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// NodeKind identifies the kind of a node.
+type NodeKind int
+
+const (
+	DocumentNode NodeKind = iota
+	ElementNode
+	AttributeNode
+	TextNode
+)
+
+// String returns the XPath name of the node kind.
+func (k NodeKind) String() string {
+	switch k {
+	case DocumentNode:
+		return "document"
+	case ElementNode:
+		return "element"
+	case AttributeNode:
+		return "attribute"
+	case TextNode:
+		return "text"
+	}
+	return "unknown"
+}
+
+// Node is a node of a parsed XML document.
+type Node struct {
+	Kind     NodeKind
+	Name     string // Local name of elements and attributes
+	Value    string // Value of attributes and text nodes
+	Attrs    []*Node
+	Children []*Node
+	Parent   *Node
+}
+
+// Text returns the string value of the node: the concatenated text of all
+// descendant text nodes for documents and elements, the value otherwise.
+func (n *Node) Text() string {
+	if n.Kind == AttributeNode || n.Kind == TextNode {
+		return n.Value
+	}
+	var b strings.Builder
+	var walk func(*Node)
+	walk = func(node *Node) {
+		for _, c := range node.Children {
+			if c.Kind == TextNode {
+				b.WriteString(c.Value)
+			} else {
+				walk(c)
+			}
+		}
+	}
+	walk(n)
+	return b.String()
+}
+
+// Parse parses an XML document. Whitespace-only text is dropped.
+func Parse(data []byte) (*Node, error) {
+	root := &Node{Kind: DocumentNode}
+	current := root
+	dec := xml.NewDecoder(bytes.NewReader(data))
+
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid XML: %w", err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			el := &Node{Kind: ElementNode, Name: t.Name.Local, Parent: current}
+			for _, a := range t.Attr {
+				if a.Name.Space == "xmlns" || a.Name.Local == "xmlns" {
+					continue
+				}
+				el.Attrs = append(el.Attrs, &Node{Kind: AttributeNode, Name: a.Name.Local, Value: a.Value, Parent: el})
+			}
+			current.Children = append(current.Children, el)
+			current = el
+		case xml.EndElement:
+			if current.Parent != nil {
+				current = current.Parent
+			}
+		case xml.CharData:
+			if text := string(t); strings.TrimSpace(text) != "" {
+				current.Children = append(current.Children, &Node{Kind: TextNode, Value: text, Parent: current})
+			}
+		}
+	}
+
+	if current != root {
+		return nil, fmt.Errorf("invalid XML: unclosed element <%s>", current.Name)
+	}
+	for _, c := range root.Children {
+		if c.Kind == ElementNode {
+			return root, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid XML: no root element")
+}
+
+// Path is a compiled XPath expression.
+type Path struct {
+	raw   string
+	steps []step
+}
+
+type step struct {
+	descendant bool // Preceded by //
+	kind       NodeKind
+	name       string // Name test; "*" matches any name
+	self       bool   // .
+	parent     bool   // ..
+	predicates []predicate
+}
+
+type predicate struct {
+	position int  // 1-based position, 0 when unused
+	last     bool // last()
+	attr     string
+	child    string // Child element name; "text()" compares text nodes
+	value    *string
+}
+
+// Compile parses an XPath expression.
+func Compile(expr string) (*Path, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return nil, fmt.Errorf("path is empty")
+	}
+
+	p := &Path{raw: expr}
+	rest := expr
+
+	for rest != "" {
+		descendant := false
+		switch {
+		case strings.HasPrefix(rest, "//"):
+			descendant = true
+			rest = rest[2:]
+		case strings.HasPrefix(rest, "/"):
+			rest = rest[1:]
+		}
+		if rest == "" {
+			if len(p.steps) == 0 && !descendant {
+				// "/" selects the document
+				return p, nil
+			}
+			return nil, fmt.Errorf("path ends with /")
+		}
+
+		end := stepEnd(rest)
+		s, err := parseStep(rest[:end])
+		if err != nil {
+			return nil, err
+		}
+		s.descendant = descendant
+		p.steps = append(p.steps, s)
+		rest = rest[end:]
+	}
+
+	return p, nil
+}
+
+// MustCompile is like Compile but panics on an invalid expression.
+func MustCompile(expr string) *Path {
+	p, err := Compile(expr)
+	if err != nil {
+		panic(fmt.Sprintf("xpath: %q: %v", expr, err))
+	}
+	return p
+}
+
+// String returns the expression the path was compiled from.
+func (p *Path) String() string {
+	return p.raw
+}
+
+// stepEnd returns the length of the step at the start of s, stopping at the
+// first / outside brackets and quotes.
+func stepEnd(s string) int {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+		case c == '/' && depth == 0:
+			return i
+		}
+	}
+	return len(s)
+}
+
+func parseStep(s string) (step, error) {
+	test := s
+	var preds []string
+	if i := strings.IndexByte(s, '['); i >= 0 {
+		test = s[:i]
+		rest := s[i:]
+		for rest != "" {
+			if rest[0] != '[' {
+				return step{}, fmt.Errorf("unexpected %q in step %q", rest, s)
+			}
+			end := bracketEnd(rest)
+			if end < 0 {
+				return step{}, fmt.Errorf("unclosed [ in step %q", s)
+			}
+			preds = append(preds, strings.TrimSpace(rest[1:end]))
+			rest = rest[end+1:]
+		}
+	}
+
+	st := step{kind: ElementNode}
+	test = strings.TrimSpace(test)
+	switch {
+	case test == ".":
+		st.self = true
+	case test == "..":
+		st.parent = true
+	case test == "text()":
+		st.kind = TextNode
+	case strings.HasPrefix(test, "@"):
+		st.kind = AttributeNode
+		st.name = localName(test[1:])
+	default:
+		st.name = localName(test)
+	}
+	if st.name == "" && (st.kind == AttributeNode || !st.self && !st.parent && st.kind == ElementNode) {
+		return step{}, fmt.Errorf("missing name in step %q", s)
+	}
+	if !validName(st.name) {
+		return step{}, fmt.Errorf("invalid name %q", st.name)
+	}
+
+	for _, raw := range preds {
+		pred, err := parsePredicate(raw)
+		if err != nil {
+			return step{}, err
+		}
+		st.predicates = append(st.predicates, pred)
+	}
+	return st, nil
+}
+
+func bracketEnd(s string) int {
+	var quote byte
+	for i := 1; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == ']':
+			return i
+		}
+	}
+	return -1
+}
+
+func parsePredicate(raw string) (predicate, error) {
+	if raw == "last()" {
+		return predicate{last: true}, nil
+	}
+	if n, err := strconv.Atoi(raw); err == nil {
+		if n < 1 {
+			return predicate{}, fmt.Errorf("position must be at least 1 in [%s]", raw)
+		}
+		return predicate{position: n}, nil
+	}
+
+	lhs, rhs, hasValue := strings.Cut(raw, "=")
+	lhs = strings.TrimSpace(lhs)
+	var pred predicate
+	if hasValue {
+		value, err := unquote(strings.TrimSpace(rhs))
+		if err != nil {
+			return predicate{}, fmt.Errorf("invalid predicate [%s]: %w", raw, err)
+		}
+		pred.value = &value
+	}
+
+	switch {
+	case strings.HasPrefix(lhs, "@"):
+		pred.attr = localName(lhs[1:])
+	case lhs == "text()" || lhs == ".":
+		pred.child = "text()"
+	default:
+		pred.child = localName(lhs)
+	}
+	if pred.attr == "" && pred.child == "" || !validName(pred.attr) || pred.child != "text()" && !validName(pred.child) {
+		return predicate{}, fmt.Errorf("unsupported predicate [%s]", raw)
+	}
+	return pred, nil
+}
+
+func unquote(s string) (string, error) {
+	if len(s) >= 2 && (s[0] == '\'' || s[0] == '"') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1], nil
+	}
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return s, nil
+	}
+	return "", fmt.Errorf("expected a quoted string or a number, got %q", s)
+}
+
+// localName drops a namespace prefix; names match on their local part.
+func localName(name string) string {
+	name = strings.TrimSpace(name)
+	if i := strings.LastIndexByte(name, ':'); i >= 0 {
+		return name[i+1:]
+	}
+	return name
+}
+
+func validName(name string) bool {
+	return !strings.ContainsAny(name, "[]()='\" /@")
+}
+
+// Select returns the nodes matched by the path, in document order. Relative
+// paths are evaluated against the document.
+func (p *Path) Select(doc *Node) []*Node {
+	nodes := []*Node{doc}
+	for _, st := range p.steps {
+		var next []*Node
+		seen := make(map[*Node]bool)
+		for _, ctx := range nodes {
+			for _, n := range st.apply(ctx) {
+				if !seen[n] {
+					seen[n] = true
+					next = append(next, n)
+				}
+			}
+		}
+		nodes = next
+	}
+	return nodes
+}
+
+// apply returns the nodes selected by the step from a context node.
+func (st step) apply(ctx *Node) []*Node {
+	contexts := []*Node{ctx}
+	if st.descendant {
+		contexts = descendantsOrSelf(ctx)
+	}
+
+	var out []*Node
+	for _, c := range contexts {
+		var candidates []*Node
+		switch {
+		case st.self:
+			candidates = []*Node{c}
+		case st.parent:
+			if c.Parent != nil {
+				candidates = []*Node{c.Parent}
+			}
+		case st.kind == AttributeNode:
+			for _, a := range c.Attrs {
+				if st.name == "*" || a.Name == st.name {
+					candidates = append(candidates, a)
+				}
+			}
+		default:
+			for _, child := range c.Children {
+				if child.Kind != st.kind {
+					continue
+				}
+				if st.kind == ElementNode && st.name != "*" && child.Name != st.name {
+					continue
+				}
+				candidates = append(candidates, child)
+			}
+		}
+		for _, pred := range st.predicates {
+			candidates = pred.filter(candidates)
+		}
+		out = append(out, candidates...)
+	}
+	return out
+}
+
+func (pred predicate) filter(nodes []*Node) []*Node {
+	switch {
+	case pred.last:
+		if len(nodes) == 0 {
+			return nil
+		}
+		return nodes[len(nodes)-1:]
+	case pred.position > 0:
+		if pred.position > len(nodes) {
+			return nil
+		}
+		return nodes[pred.position-1 : pred.position]
+	}
+
+	var out []*Node
+	for _, n := range nodes {
+		if pred.matches(n) {
+			out = append(out, n)
+		}
+	}
+	return out
+}
+
+func (pred predicate) matches(n *Node) bool {
+	var values []string
+	switch {
+	case pred.attr != "":
+		for _, a := range n.Attrs {
+			if pred.attr == "*" || a.Name == pred.attr {
+				values = append(values, a.Value)
+			}
+		}
+	case pred.child == "text()":
+		values = append(values, n.Text())
+	default:
+		for _, c := range n.Children {
+			if c.Kind == ElementNode && (pred.child == "*" || c.Name == pred.child) {
+				values = append(values, c.Text())
+			}
+		}
+	}
+
+	if pred.value == nil {
+		return len(values) > 0
+	}
+	for _, v := range values {
+		if strings.TrimSpace(v) == *pred.value {
+			return true
+		}
+	}
+	return false
+}
+
+func descendantsOrSelf(n *Node) []*Node {
+	out := []*Node{n}
+	for _, c := range n.Children {
+		if c.Kind == ElementNode {
+			out = append(out, descendantsOrSelf(c)...)
+		}
+	}
+	return out
+}
@@ -0,0 +1,85 @@
+package xpath
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const library = `<?xml version="1.0"?>
+<library xmlns:b="urn:books">
+  <book id="1" lang="en">
+    <title>Dune</title>
+    <author>Herbert</author>
+  </book>
+  <book id="2">
+    <title>Solaris</title>
+    <b:author>Lem</b:author>
+  </book>
+  <note>Open <em>daily</em></note>
+</library>`
+
+func TestPath_Select(t *testing.T) {
+	doc, err := Parse([]byte(library))
+	require.NoError(t, err)
+
+	tests := []struct {
+		expr string
+		want []string
+	}{
+		{"/library/book/title", []string{"Dune", "Solaris"}},
+		{"library/book[1]/title", []string{"Dune"}},
+		{"//book[last()]/@id", []string{"2"}},
+		{"//title", []string{"Dune", "Solaris"}},
+		{"//book[@lang]/title", []string{"Dune"}},
+		{"//book[@id='2']/author", []string{"Lem"}},
+		{"//book[title=\"Dune\"]/@*", []string{"1", "en"}},
+		{"//title[text()='Solaris']/../@id", []string{"2"}},
+		{"/library/*[2]/title", []string{"Solaris"}},
+		{"//note", []string{"Open daily"}},
+		{"//note/text()", []string{"Open "}},
+		{"//book/.", []string{"DuneHerbert", "SolarisLem"}},
+		{"//missing", nil},
+		{"/library/book[3]", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			path, err := Compile(tt.expr)
+			require.NoError(t, err)
+
+			var got []string
+			for _, n := range path.Select(doc) {
+				got = append(got, n.Text())
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPath_Kinds(t *testing.T) {
+	doc, err := Parse([]byte(library))
+	require.NoError(t, err)
+
+	assert.Equal(t, ElementNode, MustCompile("//book").Select(doc)[0].Kind)
+	assert.Equal(t, "attribute", MustCompile("//@id").Select(doc)[0].Kind.String())
+	assert.Equal(t, TextNode, MustCompile("//title/text()").Select(doc)[0].Kind)
+	assert.Equal(t, []*Node{doc}, MustCompile("/").Select(doc))
+}
+
+func TestCompile_Invalid(t *testing.T) {
+	for _, expr := range []string{"", "/library/", "//book[", "//book[@id=2x]", "//book[0]", "//book[count(a)]", "@"} {
+		_, err := Compile(expr)
+		assert.Error(t, err, expr)
+	}
+	assert.Panics(t, func() { MustCompile("//[") })
+}
+
+func TestParse_Invalid(t *testing.T) {
+	_, err := Parse([]byte(`{"json": true}`))
+	assert.Error(t, err)
+
+	_, err = Parse([]byte(`<a><b></a>`))
+	assert.Error(t, err)
+}
@@ -71,7 +71,7 @@ func TestInterpolation_WithCollection(t *testing.T) {
 		assert.Equal(t, "Collection: coll-123, Timeout: 30s", result)
 	})
 
-	t.Run("collection variables override environment", func(t *testing.T) {
+	t.Run("environment variables override collection defaults", func(t *testing.T) {
 		scope := interpolate.NewScope()
 
 		env := interpolate.NewVariableSet()
@@ -85,7 +85,7 @@ func TestInterpolation_WithCollection(t *testing.T) {
 		result, err := scope.Interpolate("Host: {{api_host}}")
 
 		require.NoError(t, err)
-		assert.Equal(t, "Host: collection.example.com", result)
+		assert.Equal(t, "Host: env.example.com", result)
 	})
 }
 
@@ -301,10 +301,10 @@ func TestInterpolation_Performance(t *testing.T) {
 		result, _ := scope.Interpolate("{{level}}")
 		assert.Equal(t, "request", result)
 
-		// Clear request, should fall back to collection
+		// Clear request, should fall back to environment
 		scope.ClearLevel(interpolate.LevelRequest)
 		result, _ = scope.Interpolate("{{level}}")
-		assert.Equal(t, "collection", result)
+		assert.Equal(t, "environment", result)
 	})
 }
 